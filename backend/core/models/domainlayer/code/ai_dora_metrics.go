@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package code
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+)
+
+// AiDoraMetrics is the monthly project-level rollup of AI review signals over the
+// changes and deployments counted by the DORA dashboards, so they can be overlaid
+// on them by project_name and month. Calculated by the aireview plugin.
+type AiDoraMetrics struct {
+	domainlayer.DomainEntity
+
+	ProjectName string    `gorm:"index;type:varchar(255)"`
+	PeriodStart time.Time `gorm:"index"`
+	PeriodEnd   time.Time
+	PeriodType  string `gorm:"type:varchar(20)"`
+
+	// Changes are the PRs of the project's repos merged in the period
+	MergedPrs            int
+	AiReviewedPrs        int
+	AiFlaggedPrs         int
+	AiFlaggedChangeRatio float64 // AiFlaggedPrs / MergedPrs, 0 without merged PRs
+
+	// Deployments are the successful production deployments of the project's cicd scopes
+	Deployments            int
+	AiRiskyDeployments     int     // deployments shipping at least one AI-flagged PR
+	AiRiskyDeploymentRatio float64 // AiRiskyDeployments / Deployments, 0 without deployments
+
+	CalculatedAt time.Time
+}
+
+func (AiDoraMetrics) TableName() string {
+	return "ai_dora_metrics"
+}
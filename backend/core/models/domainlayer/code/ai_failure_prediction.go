@@ -35,6 +35,8 @@ type AiFailurePrediction struct {
 	RepoId          string `gorm:"index;type:varchar(255)"`
 	RepoName        string `gorm:"type:varchar(255)"`
 	AiTool          string `gorm:"type:varchar(100)"`
+	ToolVersion     string `gorm:"type:varchar(100)"`
+	FindingCategory string `gorm:"type:varchar(100);index"`
 	CiFailureSource string `gorm:"type:varchar(20);index"`
 
 	// PR display metadata for drill-down dashboards
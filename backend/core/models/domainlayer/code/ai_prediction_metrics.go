@@ -33,6 +33,12 @@ type AiPredictionMetrics struct {
 	RepoId          string `gorm:"index;type:varchar(255)"`
 	AiTool          string `gorm:"type:varchar(100)"`
 	CiFailureSource string `gorm:"type:varchar(20);index"`
+	// ToolVersion is empty on the row aggregating all versions of AiTool
+	ToolVersion string `gorm:"type:varchar(100)"`
+	// Category is empty on the row aggregating all finding categories
+	Category string `gorm:"type:varchar(100)"`
+	// IsAggregate marks the row aggregating all tool versions and finding categories
+	IsAggregate bool `gorm:"index"`
 
 	PeriodStart time.Time `gorm:"index"`
 	PeriodEnd   time.Time
@@ -33,6 +33,7 @@ type AiReview struct {
 	PullRequestId string `gorm:"index;type:varchar(255)"`
 	RepoId        string `gorm:"index;type:varchar(255)"`
 	AiTool        string `gorm:"type:varchar(100)"`
+	ToolVersion   string `gorm:"type:varchar(100)"`
 
 	CreatedDate time.Time `gorm:"index"`
 
@@ -33,6 +33,7 @@ func GetDomainTablesInfo() []dal.Tabler {
 		&code.AiReview{},
 		&code.AiFailurePrediction{},
 		&code.AiPredictionMetrics{},
+		&code.AiDoraMetrics{},
 		&code.Commit{},
 		&code.CommitFile{},
 		&code.CommitFileComponent{},
@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addAiToolVersion)(nil)
+
+type aiReviewToolVersion20260427 struct {
+	ToolVersion string `gorm:"type:varchar(100)"`
+}
+
+func (aiReviewToolVersion20260427) TableName() string { return "ai_reviews" }
+
+type aiFailurePredictionToolVersion20260427 struct {
+	ToolVersion string `gorm:"type:varchar(100)"`
+}
+
+func (aiFailurePredictionToolVersion20260427) TableName() string { return "ai_failure_predictions" }
+
+type aiPredictionMetricsToolVersion20260427 struct {
+	ToolVersion string `gorm:"type:varchar(100)"`
+}
+
+func (aiPredictionMetricsToolVersion20260427) TableName() string { return "ai_prediction_metrics" }
+
+type addAiToolVersion struct{}
+
+func (*addAiToolVersion) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes,
+		new(aiReviewToolVersion20260427),
+		new(aiFailurePredictionToolVersion20260427),
+		new(aiPredictionMetricsToolVersion20260427),
+	)
+}
+
+func (*addAiToolVersion) Version() uint64 {
+	return 20260427000001
+}
+
+func (*addAiToolVersion) Name() string {
+	return "add tool_version to AI review domain tables"
+}
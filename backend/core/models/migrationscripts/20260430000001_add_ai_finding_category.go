@@ -0,0 +1,56 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addAiFindingCategory)(nil)
+
+type aiFailurePredictionFindingCategory20260430 struct {
+	FindingCategory string `gorm:"type:varchar(100);index"`
+}
+
+func (aiFailurePredictionFindingCategory20260430) TableName() string { return "ai_failure_predictions" }
+
+type aiPredictionMetricsCategory20260430 struct {
+	Category string `gorm:"type:varchar(100)"`
+}
+
+func (aiPredictionMetricsCategory20260430) TableName() string { return "ai_prediction_metrics" }
+
+type addAiFindingCategory struct{}
+
+func (*addAiFindingCategory) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes,
+		new(aiFailurePredictionFindingCategory20260430),
+		new(aiPredictionMetricsCategory20260430),
+	)
+}
+
+func (*addAiFindingCategory) Version() uint64 {
+	return 20260430000001
+}
+
+func (*addAiFindingCategory) Name() string {
+	return "add finding category to AI prediction domain tables"
+}
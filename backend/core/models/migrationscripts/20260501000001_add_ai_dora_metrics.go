@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addAiDoraMetrics)(nil)
+
+type archivedAiDoraMetrics20260501 struct {
+	archived.DomainEntity
+
+	ProjectName string    `gorm:"index;type:varchar(255)"`
+	PeriodStart time.Time `gorm:"index"`
+	PeriodEnd   time.Time
+	PeriodType  string `gorm:"type:varchar(20)"`
+
+	MergedPrs            int
+	AiReviewedPrs        int
+	AiFlaggedPrs         int
+	AiFlaggedChangeRatio float64
+
+	Deployments            int
+	AiRiskyDeployments     int
+	AiRiskyDeploymentRatio float64
+
+	CalculatedAt time.Time
+}
+
+func (archivedAiDoraMetrics20260501) TableName() string { return "ai_dora_metrics" }
+
+type addAiDoraMetrics struct{}
+
+func (*addAiDoraMetrics) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, new(archivedAiDoraMetrics20260501))
+}
+
+func (*addAiDoraMetrics) Version() uint64 {
+	return 20260501000001
+}
+
+func (*addAiDoraMetrics) Name() string {
+	return "add ai_dora_metrics domain table"
+}
@@ -0,0 +1,50 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addAiPredictionMetricsAggregate)(nil)
+
+type aiPredictionMetricsAggregate20260508 struct {
+	IsAggregate bool `gorm:"index"`
+}
+
+func (aiPredictionMetricsAggregate20260508) TableName() string { return "ai_prediction_metrics" }
+
+type addAiPredictionMetricsAggregate struct{}
+
+func (*addAiPredictionMetricsAggregate) Up(basicRes context.BasicRes) errors.Error {
+	if err := migrationhelper.AutoMigrateTables(basicRes, new(aiPredictionMetricsAggregate20260508)); err != nil {
+		return err
+	}
+	return basicRes.GetDal().Exec("UPDATE ai_prediction_metrics SET is_aggregate = ? WHERE tool_version = '' AND category = ''", true)
+}
+
+func (*addAiPredictionMetricsAggregate) Version() uint64 {
+	return 20260508000001
+}
+
+func (*addAiPredictionMetricsAggregate) Name() string {
+	return "add aggregate marker to AI prediction metrics"
+}
@@ -147,5 +147,9 @@ func All() []plugin.MigrationScript {
 		new(addAuthSessions),
 		new(addAiReviewDomainTables),
 		new(fixAiReviewDomainColumns),
+		new(addAiToolVersion),
+		new(addAiFindingCategory),
+		new(addAiDoraMetrics),
+		new(addAiPredictionMetricsAggregate),
 	}
 }
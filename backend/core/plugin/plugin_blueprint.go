@@ -75,6 +75,20 @@ type DataSourcePluginBlueprintV200 interface {
 	) (models.PipelinePlan, []Scope, errors.Error)
 }
 
+// DataSourcePluginProjectBlueprintV200 is implemented by data-source plugins
+// whose plan depends on the project of the blueprint being planned, the
+// framework then calls MakeProjectDataSourcePipelinePlanV200 instead of
+// MakeDataSourcePipelinePlanV200. projectName is empty for blueprints without
+// a project.
+type DataSourcePluginProjectBlueprintV200 interface {
+	DataSourcePluginBlueprintV200
+	MakeProjectDataSourcePipelinePlanV200(
+		projectName string,
+		connectionId uint64,
+		scopes []*models.BlueprintScope,
+	) (models.PipelinePlan, []Scope, errors.Error)
+}
+
 // BlueprintConnectionV200 contains the pluginName/connectionId  and related Scopes,
 
 // MetricPluginBlueprintV200 is similar to the DataSourcePluginBlueprintV200
@@ -87,6 +101,16 @@ type MetricPluginBlueprintV200 interface {
 	MakeMetricPluginPipelinePlanV200(projectName string, options json.RawMessage) (models.PipelinePlan, errors.Error)
 }
 
+// MetricPluginAutoIncludeV200 is implemented by metric plugins that join a
+// project blueprint on their own, without being enabled for the project, once
+// they are configured on the instance (they have a connection) and the blueprint
+// collects from one of the data-source plugins returned by AutoIncludeDataSources.
+// A project can still opt out by disabling the plugin in its metric settings.
+type MetricPluginAutoIncludeV200 interface {
+	MetricPluginBlueprintV200
+	AutoIncludeDataSources() []string
+}
+
 // ProjectMapper is implemented by the plugin org, which binding project and scopes
 type ProjectMapper interface {
 	MapProject(projectName string, scopes []Scope) (models.PipelinePlan, errors.Error)
@@ -33,7 +33,7 @@ require (
 	golang.org/x/crypto v0.26.0 // indirect
 	golang.org/x/exp v0.0.0-20221028150844-83b7d23a625f
 	golang.org/x/oauth2 v0.13.0
-	golang.org/x/sync v0.8.0
+	golang.org/x/sync v0.22.0
 	gorm.io/datatypes v1.0.1
 	gorm.io/driver/mysql v1.5.1
 	gorm.io/driver/postgres v1.5.2
@@ -48,9 +48,12 @@ require (
 	github.com/go-sql-driver/mysql v1.7.1
 	github.com/golang-jwt/jwt/v5 v5.0.0-rc.1
 	github.com/merico-ai/graphql v0.0.0-20260206020408-b7fd267bcfac
+	github.com/opencontainers/go-digest v1.0.0
+	github.com/opencontainers/image-spec v1.1.1
 	github.com/rogpeppe/go-internal v1.11.0
 	golang.org/x/mod v0.17.0
 	google.golang.org/api v0.149.0
+	oras.land/oras-go/v2 v2.6.2
 )
 
 require (
@@ -67,6 +67,19 @@ func New(ctx context.Context, bucketName string) (*GCSBucket, errors.Error) {
 	}, nil
 }
 
+// NewWithCredentials creates a GCSBucket client for the given bucket authenticated
+// with a service account key (the JSON key file content), for private buckets.
+func NewWithCredentials(ctx context.Context, bucketName string, credentialsJSON []byte) (*GCSBucket, errors.Error) {
+	client, err := storage.NewClient(ctx, option.WithCredentialsJSON(credentialsJSON))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to create GCS client with the service account credentials")
+	}
+	return &GCSBucket{
+		client: client,
+		bkt:    client.Bucket(bucketName),
+	}, nil
+}
+
 // Close releases resources held by the GCS client.
 func (b *GCSBucket) Close() error {
 	return b.client.Close()
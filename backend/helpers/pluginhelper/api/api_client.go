@@ -323,6 +323,19 @@ func (apiClient *ApiClient) Do(
 	query url.Values,
 	body interface{},
 	headers http.Header,
+) (*http.Response, errors.Error) {
+	return apiClient.DoWithContext(apiClient.ctx, method, path, query, body, headers)
+}
+
+// DoWithContext sends a request bound to ctx instead of the client's context, e.g. to abort
+// a single request after a timeout
+func (apiClient *ApiClient) DoWithContext(
+	ctx gocontext.Context,
+	method string,
+	path string,
+	query url.Values,
+	body interface{},
+	headers http.Header,
 ) (*http.Response, errors.Error) {
 	uri, err := GetURIStringPointer(apiClient.endpoint, path, query)
 	if err != nil {
@@ -338,8 +351,8 @@ func (apiClient *ApiClient) Do(
 		reqBody = bytes.NewBuffer(reqJson)
 	}
 	var req *http.Request
-	if apiClient.ctx != nil {
-		req, err = errors.Convert01(http.NewRequestWithContext(apiClient.ctx, method, *uri, reqBody))
+	if ctx != nil {
+		req, err = errors.Convert01(http.NewRequestWithContext(ctx, method, *uri, reqBody))
 	} else {
 		req, err = errors.Convert01(http.NewRequest(method, *uri, reqBody))
 	}
@@ -33,6 +33,10 @@ type AnalyzeRequest struct {
 	RepoId        string `json:"repoId"`
 	ScopeConfigId uint64 `json:"scopeConfigId"`
 	TimeAfter     string `json:"timeAfter"`
+
+	// SkipFindings and SkipPredictions leave out the finding and CI prediction subtasks
+	SkipFindings    bool `json:"skipFindings"`
+	SkipPredictions bool `json:"skipPredictions"`
 }
 
 // GenerateAnalysisPipeline generates a pipeline configuration for AI review analysis
@@ -73,6 +77,12 @@ func GenerateAnalysisPipeline(input *plugin.ApiResourceInput) (*plugin.ApiResour
 	if request.TimeAfter != "" {
 		opts["timeAfter"] = request.TimeAfter
 	}
+	if request.SkipFindings {
+		opts["skipFindings"] = true
+	}
+	if request.SkipPredictions {
+		opts["skipPredictions"] = true
+	}
 
 	// Create pipeline plan
 	plan := models.PipelinePlan{
@@ -80,12 +90,15 @@ func GenerateAnalysisPipeline(input *plugin.ApiResourceInput) (*plugin.ApiResour
 			{
 				Plugin:  "aireview",
 				Options: opts,
-				Subtasks: []string{
+				Subtasks: tasks.FilterSubtasks([]string{
 					tasks.ExtractAiReviewsMeta.Name,
 					tasks.ExtractAiReviewFindingsMeta.Name,
 					tasks.CalculateFailurePredictionsMeta.Name,
 					tasks.CalculatePredictionMetricsMeta.Name,
-				},
+				}, &tasks.AiReviewOptions{
+					SkipFindings:    request.SkipFindings,
+					SkipPredictions: request.SkipPredictions,
+				}),
 			},
 		},
 	}
@@ -0,0 +1,173 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
+)
+
+// MakeDataSourcePipelinePlanV200 checks the repo scopes of a blueprint connection and returns
+// their domain repos, so the org plugin maps them to the project. The plan itself is empty:
+// the reviews are read from the PRs collected by github and gitlab, so the repos are analyzed
+// by the project's metric plan, which runs after every data source (MakeMetricPipelinePlanV200).
+func MakeDataSourcePipelinePlanV200(
+	connectionId uint64,
+	bpScopes []*coreModels.BlueprintScope,
+) (coreModels.PipelinePlan, []plugin.Scope, errors.Error) {
+	_, err := dsHelper.ConnSrv.FindByPk(connectionId)
+	if err != nil {
+		return nil, nil, err
+	}
+	scopeDetails, err := dsHelper.ScopeSrv.MapScopeDetails(connectionId, bpScopes)
+	if err != nil {
+		return nil, nil, err
+	}
+	repos := make([]*models.AiReviewRepo, len(scopeDetails))
+	for i, scopeDetail := range scopeDetails {
+		repos[i] = &scopeDetail.Scope
+	}
+	return coreModels.PipelinePlan{}, domainRepos(repos), nil
+}
+
+// domainRepos returns the domain repos of repo scopes, for the project mapping
+func domainRepos(repos []*models.AiReviewRepo) []plugin.Scope {
+	scopes := make([]plugin.Scope, 0, len(repos))
+	for _, repo := range repos {
+		scopes = append(scopes, &code.Repo{
+			DomainEntity: domainlayer.DomainEntity{Id: repo.Id},
+			Name:         repo.ScopeName(),
+			Url:          repo.Url,
+		})
+	}
+	return scopes
+}
+
+// MakeMetricPipelinePlanV200 generates the aireview tasks of a project. Without repo scopes
+// in the project's blueprint, one task analyzes every repo of the project. Otherwise each repo
+// scope gets its own task, with the scope config of the scope (the options' scopeConfigId when
+// it has none), next to a project task analyzing the project's other repos, and a final project
+// task runs the project-only subtasks over all their results.
+func MakeMetricPipelinePlanV200(
+	subtaskMetas []plugin.SubTaskMeta,
+	projectName string,
+	op *tasks.AiReviewOptions,
+) (coreModels.PipelinePlan, errors.Error) {
+	repos, err := loadProjectRepoScopes(projectName)
+	if err != nil {
+		return nil, err
+	}
+	subtaskNames := make([]string, 0, len(subtaskMetas))
+	for _, meta := range subtaskMetas {
+		subtaskNames = append(subtaskNames, meta.Name)
+	}
+	return makeMetricPipelinePlanV200(subtaskNames, projectName, op, repos), nil
+}
+
+// loadProjectRepoScopes loads the aireview repo scopes added to the blueprint of a project
+func loadProjectRepoScopes(projectName string) ([]*models.AiReviewRepo, errors.Error) {
+	var bpScopes []coreModels.BlueprintScope
+	err := db.All(&bpScopes,
+		dal.Select("bs.*"),
+		dal.From(coreModels.BlueprintScope{}.TableName()+" bs"),
+		dal.Join("JOIN "+coreModels.Blueprint{}.TableName()+" b ON b.id = bs.blueprint_id"),
+		dal.Where("b.project_name = ? AND bs.plugin_name = ?", projectName, pluginName),
+		dal.Orderby("bs.connection_id, bs.scope_id"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load the aireview scopes of the project")
+	}
+	repos := make([]*models.AiReviewRepo, 0, len(bpScopes))
+	for _, bpScope := range bpScopes {
+		repo := &models.AiReviewRepo{}
+		err = db.First(repo, dal.Where("connection_id = ? AND id = ?", bpScope.ConnectionId, bpScope.ScopeId))
+		if db.IsErrorNotFound(err) {
+			// the scope was deleted after it was added to the blueprint
+			continue
+		}
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to load the aireview repo "+bpScope.ScopeId)
+		}
+		repos = append(repos, repo)
+	}
+	return repos, nil
+}
+
+// makeMetricPipelinePlanV200 is MakeMetricPipelinePlanV200 once the repo scopes are loaded
+func makeMetricPipelinePlanV200(
+	subtaskNames []string,
+	projectName string,
+	op *tasks.AiReviewOptions,
+	repos []*models.AiReviewRepo,
+) coreModels.PipelinePlan {
+	subtaskNames = tasks.FilterSubtasks(subtaskNames, op)
+	taskOptions := func(scopeConfigId uint64) map[string]interface{} {
+		opts := map[string]interface{}{}
+		if scopeConfigId != 0 {
+			opts["scopeConfigId"] = scopeConfigId
+		}
+		if op.SkipFindings {
+			opts["skipFindings"] = true
+		}
+		if op.SkipPredictions {
+			opts["skipPredictions"] = true
+		}
+		return opts
+	}
+
+	if len(repos) == 0 {
+		opts := taskOptions(op.ScopeConfigId)
+		opts["projectName"] = projectName
+		return coreModels.PipelinePlan{
+			{{Plugin: pluginName, Options: opts, Subtasks: subtaskNames}},
+		}
+	}
+
+	repoSubtasks, projectOnly := tasks.SplitProjectSubtasks(subtaskNames)
+	repoStage := make(coreModels.PipelineStage, 0, len(repos)+1)
+	repoIds := make([]string, 0, len(repos))
+	for _, repo := range repos {
+		scopeConfigId := repo.ScopeConfigId
+		if scopeConfigId == 0 {
+			scopeConfigId = op.ScopeConfigId
+		}
+		opts := taskOptions(scopeConfigId)
+		opts["repoId"] = repo.Id
+		repoStage = append(repoStage, &coreModels.PipelineTask{Plugin: pluginName, Options: opts, Subtasks: repoSubtasks})
+		repoIds = append(repoIds, repo.Id)
+	}
+	// the repos of the project without a scope keep the options of the metric plugin
+	opts := taskOptions(op.ScopeConfigId)
+	opts["projectName"] = projectName
+	opts["excludeRepoIds"] = repoIds
+	repoStage = append(repoStage, &coreModels.PipelineTask{Plugin: pluginName, Options: opts, Subtasks: repoSubtasks})
+	plan := coreModels.PipelinePlan{repoStage}
+	if len(projectOnly) > 0 {
+		opts := taskOptions(op.ScopeConfigId)
+		opts["projectName"] = projectName
+		plan = append(plan, coreModels.PipelineStage{
+			{Plugin: pluginName, Options: opts, Subtasks: projectOnly},
+		})
+	}
+	return plan
+}
@@ -0,0 +1,98 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+var planSubtasks = []string{
+	tasks.ExtractAiReviewsMeta.Name,
+	tasks.ExtractAiReviewFindingsMeta.Name,
+	tasks.ConvertAiReviewsMeta.Name,
+	tasks.CalculateFailurePredictionsMeta.Name,
+	tasks.CalculateDoraOverlaysMeta.Name,
+}
+
+func newRepoScope(id string, scopeConfigId uint64) *models.AiReviewRepo {
+	repo := &models.AiReviewRepo{Id: id, Name: "konflux-ci/" + id}
+	repo.ConnectionId = 1
+	repo.ScopeConfigId = scopeConfigId
+	return repo
+}
+
+func TestMakeMetricPipelinePlanV200_WithoutRepoScopes(t *testing.T) {
+	plan := makeMetricPipelinePlanV200(planSubtasks, "konflux", &tasks.AiReviewOptions{ScopeConfigId: 3, SkipFindings: true}, nil)
+
+	if assert.Len(t, plan, 1) && assert.Len(t, plan[0], 1) {
+		task := plan[0][0]
+		assert.Equal(t, "aireview", task.Plugin)
+		assert.Equal(t, map[string]interface{}{"projectName": "konflux", "scopeConfigId": uint64(3), "skipFindings": true}, task.Options)
+		assert.NotContains(t, task.Subtasks, tasks.ExtractAiReviewFindingsMeta.Name)
+		assert.Contains(t, task.Subtasks, tasks.CalculateDoraOverlaysMeta.Name)
+	}
+}
+
+func TestMakeMetricPipelinePlanV200_WithRepoScopes(t *testing.T) {
+	repos := []*models.AiReviewRepo{
+		newRepoScope("github:GithubRepo:1:100", 7),
+		newRepoScope("github:GithubRepo:1:200", 0),
+	}
+	plan := makeMetricPipelinePlanV200(planSubtasks, "konflux", &tasks.AiReviewOptions{ScopeConfigId: 3}, repos)
+
+	if !assert.Len(t, plan, 2) {
+		return
+	}
+	if assert.Len(t, plan[0], 3) {
+		assert.Equal(t, map[string]interface{}{"repoId": "github:GithubRepo:1:100", "scopeConfigId": uint64(7)}, plan[0][0].Options)
+		// a repo without scope config falls back to the one of the metric options
+		assert.Equal(t, map[string]interface{}{"repoId": "github:GithubRepo:1:200", "scopeConfigId": uint64(3)}, plan[0][1].Options)
+		assert.Equal(t, []string{
+			tasks.ExtractAiReviewsMeta.Name,
+			tasks.ExtractAiReviewFindingsMeta.Name,
+			tasks.CalculateFailurePredictionsMeta.Name,
+		}, plan[0][0].Subtasks)
+		// the project's other repos are still analyzed, with the metric options
+		assert.Equal(t, map[string]interface{}{
+			"projectName":    "konflux",
+			"scopeConfigId":  uint64(3),
+			"excludeRepoIds": []string{"github:GithubRepo:1:100", "github:GithubRepo:1:200"},
+		}, plan[0][2].Options)
+		assert.Equal(t, plan[0][0].Subtasks, plan[0][2].Subtasks)
+	}
+	if assert.Len(t, plan[1], 1) {
+		assert.Equal(t, "konflux", plan[1][0].Options["projectName"])
+		assert.Equal(t, []string{tasks.ConvertAiReviewsMeta.Name, tasks.CalculateDoraOverlaysMeta.Name}, plan[1][0].Subtasks)
+	}
+}
+
+func TestDomainRepos(t *testing.T) {
+	scopes := domainRepos([]*models.AiReviewRepo{{Id: "gitlab:GitlabProject:2:42", Url: "https://gitlab.com/konflux/ui"}})
+
+	if assert.Len(t, scopes, 1) {
+		repo := scopes[0].(*code.Repo)
+		assert.Equal(t, "repos", repo.TableName())
+		assert.Equal(t, "gitlab:GitlabProject:2:42", repo.ScopeId())
+		assert.Equal(t, "gitlab:GitlabProject:2:42", repo.Name) // no name: the id is used
+		assert.Equal(t, "https://gitlab.com/konflux/ui", repo.Url)
+	}
+}
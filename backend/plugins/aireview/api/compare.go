@@ -0,0 +1,372 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+const (
+	// minSampleSize is the smallest sample on each side for which a significance hint is given
+	minSampleSize = 30
+	// zCritical is the two-sided 95% critical value of the normal distribution
+	zCritical = 1.96
+
+	SignificanceSignificant    = "significant"
+	SignificanceNotSignificant = "not_significant"
+	SignificanceInsufficient   = "insufficient_data"
+	SignificanceNotTested      = "not_tested"
+)
+
+// comparePeriod is a date range, both ends inclusive
+type comparePeriod struct {
+	Start time.Time
+	End   time.Time // exclusive upper bound: the day after the last day of the period
+}
+
+// days returns the number of days in the period
+func (p comparePeriod) days() int {
+	return int(p.End.Sub(p.Start).Hours() / 24)
+}
+
+// PeriodStats holds the figures compared between two periods
+type PeriodStats struct {
+	Start              string             `json:"start"`
+	End                string             `json:"end"`
+	Days               int                `json:"days"`
+	Reviews            int64              `json:"reviews"`
+	ReviewsPerDay      float64            `json:"reviewsPerDay"`
+	Findings           int64              `json:"findings"`
+	FindingsBySeverity []SeverityShare    `json:"findingsBySeverity"`
+	Predictions        PredictionAccuracy `json:"predictions"`
+	PrCycleTimeMinutes PercentileSummary  `json:"prCycleTimeMinutes"`
+}
+
+// SeverityShare is the number and percentage of findings with one severity
+type SeverityShare struct {
+	Severity string  `gorm:"column:severity" json:"severity"`
+	Count    int64   `gorm:"column:count" json:"count"`
+	Share    float64 `gorm:"-" json:"share"`
+}
+
+// PredictionAccuracy is the confusion matrix of the failure predictions made in a period
+type PredictionAccuracy struct {
+	Observed  int64   `json:"observed"`
+	TP        int64   `json:"tp"`
+	FP        int64   `json:"fp"`
+	FN        int64   `json:"fn"`
+	TN        int64   `json:"tn"`
+	Precision float64 `json:"precision"`
+	Recall    float64 `json:"recall"`
+	Accuracy  float64 `json:"accuracy"`
+}
+
+// ComparisonDelta is the change of one metric from period A to period B
+type ComparisonDelta struct {
+	Metric       string   `json:"metric"`
+	A            float64  `json:"a"`
+	B            float64  `json:"b"`
+	Delta        float64  `json:"delta"`
+	ChangePct    *float64 `json:"changePct"` // nil when A is 0
+	Significance string   `json:"significance"`
+}
+
+// ComparePeriods compares AI review figures between two date ranges
+// @Summary Compare two periods
+// @Description Compare review volume, finding severity mix, prediction accuracy and PR cycle time between two
+// @Description date ranges (e.g. before/after enabling an AI tool). Periods are "YYYY-MM-DD..YYYY-MM-DD", both
+// @Description days included. Each delta carries a significance hint from a two-sided z-test at 95%.
+// @Tags plugins/aireview
+// @Param repoId query string false "Filter by repository ID"
+// @Param projectName query string false "Filter by project name"
+// @Param periodA query string true "First period, e.g. 2025-01-01..2025-03-31"
+// @Param periodB query string true "Second period, e.g. 2025-04-01..2025-06-30"
+// @Success 200 {object} map[string]any
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Router /plugins/aireview/compare [get]
+func ComparePeriods(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	periodA, err := parseComparePeriod(input.Query.Get("periodA"))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid periodA")
+	}
+	periodB, err := parseComparePeriod(input.Query.Get("periodB"))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid periodB")
+	}
+
+	statsA, err := collectPeriodStats(input.Query, periodA)
+	if err != nil {
+		return nil, err
+	}
+	statsB, err := collectPeriodStats(input.Query, periodB)
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body: map[string]any{
+			"periodA": statsA,
+			"periodB": statsB,
+			"deltas":  compareDeltas(statsA, statsB),
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// parseComparePeriod parses "YYYY-MM-DD..YYYY-MM-DD"
+func parseComparePeriod(value string) (comparePeriod, errors.Error) {
+	startStr, endStr, found := strings.Cut(value, "..")
+	if !found {
+		return comparePeriod{}, errors.BadInput.New(fmt.Sprintf("period %q must look like 2025-01-01..2025-03-31", value))
+	}
+	start, err := time.Parse("2006-01-02", strings.TrimSpace(startStr))
+	if err != nil {
+		return comparePeriod{}, errors.BadInput.Wrap(err, "invalid period start")
+	}
+	end, err := time.Parse("2006-01-02", strings.TrimSpace(endStr))
+	if err != nil {
+		return comparePeriod{}, errors.BadInput.Wrap(err, "invalid period end")
+	}
+	if end.Before(start) {
+		return comparePeriod{}, errors.BadInput.New(fmt.Sprintf("period %q ends before it starts", value))
+	}
+	return comparePeriod{Start: start, End: end.AddDate(0, 0, 1)}, nil
+}
+
+// repoScopeClauses filters on the repo column through project_mapping when projectName is set, else on repoId
+func repoScopeClauses(query url.Values, repoColumn string) []dal.Clause {
+	if projectName := query.Get("projectName"); projectName != "" {
+		return []dal.Clause{
+			dal.Join("JOIN project_mapping pm ON " + repoColumn + " = pm.row_id"),
+			dal.Where("pm.project_name = ? AND pm.`table` = ?", projectName, "repos"),
+		}
+	}
+	if repoId := query.Get("repoId"); repoId != "" {
+		return []dal.Clause{dal.Where(repoColumn+" = ?", repoId)}
+	}
+	return nil
+}
+
+// collectPeriodStats computes the compared figures of one period
+func collectPeriodStats(query url.Values, period comparePeriod) (*PeriodStats, errors.Error) {
+	stats := &PeriodStats{
+		Start:              period.Start.Format("2006-01-02"),
+		End:                period.End.AddDate(0, 0, -1).Format("2006-01-02"),
+		Days:               period.days(),
+		FindingsBySeverity: []SeverityShare{},
+	}
+
+	// Review volume
+	reviewClauses := append([]dal.Clause{
+		dal.From("_tool_aireview_reviews r"),
+		dal.Where("r.created_date >= ? AND r.created_date < ?", period.Start, period.End),
+	}, repoScopeClauses(query, "r.repo_id")...)
+	var err errors.Error
+	stats.Reviews, err = db.Count(reviewClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count reviews")
+	}
+	if stats.Days > 0 {
+		stats.ReviewsPerDay = math.Round(float64(stats.Reviews)*100/float64(stats.Days)) / 100
+	}
+
+	// Finding severity mix
+	findingClauses := append([]dal.Clause{
+		dal.Select("f.severity as severity, COUNT(*) as count"),
+		dal.From("_tool_aireview_findings f"),
+		dal.Where("f.created_date >= ? AND f.created_date < ?", period.Start, period.End),
+	}, repoScopeClauses(query, "f.repo_id")...)
+	findingClauses = append(findingClauses, dal.Groupby("f.severity"), dal.Orderby("count DESC"))
+	err = db.All(&stats.FindingsBySeverity, findingClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get findings by severity")
+	}
+	for _, severity := range stats.FindingsBySeverity {
+		stats.Findings += severity.Count
+	}
+	for i := range stats.FindingsBySeverity {
+		stats.FindingsBySeverity[i].Share = percentage(stats.FindingsBySeverity[i].Count, stats.Findings)
+	}
+
+	// Prediction accuracy, by when the prediction was made
+	var outcomes []struct {
+		Outcome string `gorm:"column:outcome"`
+		Count   int64  `gorm:"column:count"`
+	}
+	predictionClauses := append([]dal.Clause{
+		dal.Select("p.prediction_outcome as outcome, COUNT(*) as count"),
+		dal.From("_tool_aireview_failure_predictions p"),
+		dal.Where("p.flagged_at >= ? AND p.flagged_at < ?", period.Start, period.End),
+	}, repoScopeClauses(query, "p.repo_id")...)
+	predictionClauses = append(predictionClauses, dal.Groupby("p.prediction_outcome"))
+	err = db.All(&outcomes, predictionClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get prediction outcomes")
+	}
+	counts := make(map[string]int64, len(outcomes))
+	for _, outcome := range outcomes {
+		counts[outcome.Outcome] = outcome.Count
+	}
+	stats.Predictions = predictionAccuracy(counts)
+
+	// PR cycle time of the PRs merged in the period, reviewed by an AI tool or not
+	cycleExpr := minutesBetweenExpr(db.Dialect(), "pr.created_date", "pr.merged_date")
+	var cycleBuckets []valueCount
+	cycleClauses := append([]dal.Clause{
+		dal.Select(cycleExpr + " as value, COUNT(*) as count"),
+		dal.From("pull_requests pr"),
+		dal.Where("pr.merged_date >= ? AND pr.merged_date < ? AND pr.created_date IS NOT NULL", period.Start, period.End),
+	}, repoScopeClauses(query, "pr.base_repo_id")...)
+	cycleClauses = append(cycleClauses, dal.Groupby(cycleExpr), dal.Orderby("value"))
+	err = db.All(&cycleBuckets, cycleClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get PR cycle time distribution")
+	}
+	stats.PrCycleTimeMinutes = summarizeHistogram(cycleBuckets)
+
+	return stats, nil
+}
+
+// predictionAccuracy builds the confusion matrix figures from outcome counts, NO_CI predictions are left out
+func predictionAccuracy(counts map[string]int64) PredictionAccuracy {
+	accuracy := PredictionAccuracy{
+		TP: counts[models.PredictionTP],
+		FP: counts[models.PredictionFP],
+		FN: counts[models.PredictionFN],
+		TN: counts[models.PredictionTN],
+	}
+	accuracy.Observed = accuracy.TP + accuracy.FP + accuracy.FN + accuracy.TN
+	accuracy.Precision = percentage(accuracy.TP, accuracy.TP+accuracy.FP)
+	accuracy.Recall = percentage(accuracy.TP, accuracy.TP+accuracy.FN)
+	accuracy.Accuracy = percentage(accuracy.TP+accuracy.TN, accuracy.Observed)
+	return accuracy
+}
+
+// compareDeltas lists the change of every compared metric from a to b
+func compareDeltas(a, b *PeriodStats) []ComparisonDelta {
+	deltas := []ComparisonDelta{
+		newDelta("reviewsPerDay", a.ReviewsPerDay, b.ReviewsPerDay, rateSignificance(a.Reviews, a.Days, b.Reviews, b.Days)),
+	}
+
+	// Severity mix: share of each severity seen in either period
+	sharesA, sharesB := severityCounts(a), severityCounts(b)
+	severities := make([]string, 0, len(sharesA)+len(sharesB))
+	for severity := range sharesA {
+		severities = append(severities, severity)
+	}
+	for severity := range sharesB {
+		if _, ok := sharesA[severity]; !ok {
+			severities = append(severities, severity)
+		}
+	}
+	sort.Strings(severities)
+	for _, severity := range severities {
+		deltas = append(deltas, newDelta(
+			"severityShare:"+severity,
+			percentage(sharesA[severity], a.Findings),
+			percentage(sharesB[severity], b.Findings),
+			proportionSignificance(sharesA[severity], a.Findings, sharesB[severity], b.Findings),
+		))
+	}
+
+	pa, pb := a.Predictions, b.Predictions
+	deltas = append(deltas,
+		newDelta("predictionPrecision", pa.Precision, pb.Precision, proportionSignificance(pa.TP, pa.TP+pa.FP, pb.TP, pb.TP+pb.FP)),
+		newDelta("predictionRecall", pa.Recall, pb.Recall, proportionSignificance(pa.TP, pa.TP+pa.FN, pb.TP, pb.TP+pb.FN)),
+		newDelta("predictionAccuracy", pa.Accuracy, pb.Accuracy, proportionSignificance(pa.TP+pa.TN, pa.Observed, pb.TP+pb.TN, pb.Observed)),
+	)
+
+	// Only the histogram of cycle times is available, so medians are compared without a test
+	cycleSignificance := SignificanceNotTested
+	if a.PrCycleTimeMinutes.Count < minSampleSize || b.PrCycleTimeMinutes.Count < minSampleSize {
+		cycleSignificance = SignificanceInsufficient
+	}
+	deltas = append(deltas,
+		newDelta("prCycleTimeP50Minutes", float64(a.PrCycleTimeMinutes.P50), float64(b.PrCycleTimeMinutes.P50), cycleSignificance),
+		newDelta("prCycleTimeP90Minutes", float64(a.PrCycleTimeMinutes.P90), float64(b.PrCycleTimeMinutes.P90), cycleSignificance),
+	)
+	return deltas
+}
+
+// severityCounts indexes the finding counts of a period by severity
+func severityCounts(stats *PeriodStats) map[string]int64 {
+	counts := make(map[string]int64, len(stats.FindingsBySeverity))
+	for _, severity := range stats.FindingsBySeverity {
+		counts[severity.Severity] = severity.Count
+	}
+	return counts
+}
+
+// newDelta computes the absolute and relative change from a to b
+func newDelta(metric string, a, b float64, significance string) ComparisonDelta {
+	delta := ComparisonDelta{
+		Metric:       metric,
+		A:            a,
+		B:            b,
+		Delta:        math.Round((b-a)*100) / 100,
+		Significance: significance,
+	}
+	if a != 0 {
+		changePct := math.Round((b-a)*1000/a) / 10
+		delta.ChangePct = &changePct
+	}
+	return delta
+}
+
+// proportionSignificance runs a two-proportion z-test of successesA/totalA against successesB/totalB
+func proportionSignificance(successesA, totalA, successesB, totalB int64) string {
+	if totalA < minSampleSize || totalB < minSampleSize {
+		return SignificanceInsufficient
+	}
+	pA := float64(successesA) / float64(totalA)
+	pB := float64(successesB) / float64(totalB)
+	pooled := float64(successesA+successesB) / float64(totalA+totalB)
+	stdErr := math.Sqrt(pooled * (1 - pooled) * (1/float64(totalA) + 1/float64(totalB)))
+	if stdErr == 0 {
+		return SignificanceNotSignificant
+	}
+	if math.Abs(pB-pA)/stdErr >= zCritical {
+		return SignificanceSignificant
+	}
+	return SignificanceNotSignificant
+}
+
+// rateSignificance compares two Poisson event rates (events per day) with a z-test
+func rateSignificance(countA int64, daysA int, countB int64, daysB int) string {
+	if countA+countB < minSampleSize || daysA == 0 || daysB == 0 {
+		return SignificanceInsufficient
+	}
+	rateA := float64(countA) / float64(daysA)
+	rateB := float64(countB) / float64(daysB)
+	stdErr := math.Sqrt(float64(countA)/float64(daysA*daysA) + float64(countB)/float64(daysB*daysB))
+	if math.Abs(rateB-rateA)/stdErr >= zCritical {
+		return SignificanceSignificant
+	}
+	return SignificanceNotSignificant
+}
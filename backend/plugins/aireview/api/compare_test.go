@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseComparePeriod(t *testing.T) {
+	period, err := parseComparePeriod("2025-01-01..2025-01-31")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), period.Start)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), period.End, "the last day is included")
+	assert.Equal(t, 31, period.days())
+
+	period, err = parseComparePeriod("2025-03-01..2025-03-01")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, period.days())
+
+	for _, invalid := range []string{"", "2025-01-01", "2025-01-01..", "2025-13-01..2025-12-31", "2025-02-01..2025-01-01"} {
+		_, err = parseComparePeriod(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func TestRepoScopeClauses(t *testing.T) {
+	assert.Empty(t, repoScopeClauses(url.Values{}, "r.repo_id"))
+	assert.Len(t, repoScopeClauses(url.Values{"repoId": {"github:GithubRepo:1:1"}}, "r.repo_id"), 1)
+	assert.Len(t, repoScopeClauses(url.Values{"projectName": {"konflux"}, "repoId": {"ignored"}}, "r.repo_id"), 2)
+}
+
+func TestPredictionAccuracy(t *testing.T) {
+	accuracy := predictionAccuracy(map[string]int64{"TP": 8, "FP": 2, "FN": 4, "TN": 26, "NO_CI": 100})
+	assert.Equal(t, int64(40), accuracy.Observed)
+	assert.Equal(t, 80.0, accuracy.Precision)
+	assert.Equal(t, 66.7, accuracy.Recall)
+	assert.Equal(t, 85.0, accuracy.Accuracy)
+
+	assert.Equal(t, PredictionAccuracy{}, predictionAccuracy(nil))
+}
+
+func TestProportionSignificance(t *testing.T) {
+	assert.Equal(t, SignificanceInsufficient, proportionSignificance(5, 10, 50, 100))
+	// 20% vs 60% on 100 samples each
+	assert.Equal(t, SignificanceSignificant, proportionSignificance(20, 100, 60, 100))
+	// 50% vs 52%
+	assert.Equal(t, SignificanceNotSignificant, proportionSignificance(50, 100, 52, 100))
+	// all or nothing on both sides
+	assert.Equal(t, SignificanceNotSignificant, proportionSignificance(0, 40, 0, 40))
+}
+
+func TestRateSignificance(t *testing.T) {
+	assert.Equal(t, SignificanceInsufficient, rateSignificance(3, 30, 5, 30))
+	assert.Equal(t, SignificanceInsufficient, rateSignificance(100, 0, 100, 30))
+	// 1/day vs 3/day over a month
+	assert.Equal(t, SignificanceSignificant, rateSignificance(30, 30, 90, 30))
+	// 3/day vs 3.1/day
+	assert.Equal(t, SignificanceNotSignificant, rateSignificance(90, 30, 93, 30))
+}
+
+func TestCompareDeltas(t *testing.T) {
+	a := &PeriodStats{
+		Days: 30, Reviews: 30, ReviewsPerDay: 1,
+		Findings:           100,
+		FindingsBySeverity: []SeverityShare{{Severity: "critical", Count: 40}, {Severity: "info", Count: 60}},
+		Predictions:        predictionAccuracy(map[string]int64{"TP": 10, "FP": 10, "FN": 10, "TN": 10}),
+		PrCycleTimeMinutes: PercentileSummary{Count: 50, P50: 600, P90: 3000},
+	}
+	b := &PeriodStats{
+		Days: 30, Reviews: 90, ReviewsPerDay: 3,
+		Findings:           100,
+		FindingsBySeverity: []SeverityShare{{Severity: "critical", Count: 10}, {Severity: "warning", Count: 90}},
+		Predictions:        predictionAccuracy(map[string]int64{"TP": 20, "FP": 5, "FN": 5, "TN": 10}),
+		PrCycleTimeMinutes: PercentileSummary{Count: 10, P50: 300, P90: 1200},
+	}
+
+	deltas := map[string]ComparisonDelta{}
+	for _, delta := range compareDeltas(a, b) {
+		deltas[delta.Metric] = delta
+	}
+
+	volume := deltas["reviewsPerDay"]
+	assert.Equal(t, 2.0, volume.Delta)
+	assert.Equal(t, 200.0, *volume.ChangePct)
+	assert.Equal(t, SignificanceSignificant, volume.Significance)
+
+	assert.Equal(t, -30.0, deltas["severityShare:critical"].Delta)
+	assert.Equal(t, SignificanceSignificant, deltas["severityShare:critical"].Significance)
+	assert.Equal(t, 90.0, deltas["severityShare:warning"].B)
+	assert.Nil(t, deltas["severityShare:warning"].ChangePct, "no relative change from 0")
+	assert.Equal(t, -60.0, deltas["severityShare:info"].Delta)
+
+	assert.Equal(t, 80.0, deltas["predictionPrecision"].B)
+	assert.Equal(t, SignificanceInsufficient, deltas["predictionPrecision"].Significance)
+
+	assert.Equal(t, -300.0, deltas["prCycleTimeP50Minutes"].Delta)
+	assert.Equal(t, SignificanceInsufficient, deltas["prCycleTimeP50Minutes"].Significance)
+}
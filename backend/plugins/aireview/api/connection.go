@@ -0,0 +1,106 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/server/api/shared"
+)
+
+// PostConnections creates a connection
+// @Summary create an aireview connection
+// @Description Create a connection grouping the repositories analyzed by aireview. It only needs a name: the reviews are read from the PRs collected by the github and gitlab plugins
+// @Tags plugins/aireview
+// @Param body body models.AiReviewConnection true "json body"
+// @Success 200 {object} models.AiReviewConnection
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Failure 500 {string} errcode.Error "Internal Error"
+// @Router /plugins/aireview/connections [POST]
+func PostConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.Post(input)
+}
+
+// PatchConnection renames a connection
+// @Summary patch an aireview connection
+// @Tags plugins/aireview
+// @Param body body models.AiReviewConnection true "json body"
+// @Success 200 {object} models.AiReviewConnection
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Failure 500 {string} errcode.Error "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId} [PATCH]
+func PatchConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.Patch(input)
+}
+
+// DeleteConnection deletes a connection without repo scopes
+// @Summary delete an aireview connection
+// @Tags plugins/aireview
+// @Success 200 {object} models.AiReviewConnection
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Failure 409 {object} srvhelper.DsRefs "References exist to this connection"
+// @Failure 500 {string} errcode.Error "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId} [DELETE]
+func DeleteConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.Delete(input)
+}
+
+// ListConnections lists the connections
+// @Summary get all aireview connections
+// @Tags plugins/aireview
+// @Success 200 {object} []models.AiReviewConnection
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Failure 500 {string} errcode.Error "Internal Error"
+// @Router /plugins/aireview/connections [GET]
+func ListConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.GetAll(input)
+}
+
+// GetConnection gets a connection
+// @Summary get an aireview connection
+// @Tags plugins/aireview
+// @Success 200 {object} models.AiReviewConnection
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Failure 500 {string} errcode.Error "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId} [GET]
+func GetConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ConnApi.GetDetail(input)
+}
+
+// TestConnection always succeeds, the connection has no endpoint to reach
+// @Summary test an aireview connection
+// @Tags plugins/aireview
+// @Success 200 {object} shared.ApiBody
+// @Router /plugins/aireview/test [POST]
+func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return &plugin.ApiResourceOutput{Body: shared.ApiBody{Success: true, Message: "success"}, Status: http.StatusOK}, nil
+}
+
+// TestExistingConnection checks that a connection exists, it has no endpoint to reach
+// @Summary test an existing aireview connection
+// @Tags plugins/aireview
+// @Success 200 {object} shared.ApiBody
+// @Failure 404 {string} errcode.Error "Not Found"
+// @Router /plugins/aireview/connections/{connectionId}/test [POST]
+func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if _, err := dsHelper.ConnApi.FindByPk(input); err != nil {
+		return nil, err
+	}
+	return TestConnection(input)
+}
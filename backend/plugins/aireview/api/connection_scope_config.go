@@ -0,0 +1,139 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
+)
+
+// The scope configs of a connection back the project configuration UI. They are the same
+// rows as the scope-configs endpoints, with the connectionId of the connection.
+
+// PostConnectionScopeConfig creates a scope config of a connection
+// @Summary create an aireview scope config of a connection
+// @Description Create a scope config on top of the default one, so the supported tools are detected unless turned off. Invalid patterns are rejected
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeConfig body models.AiReviewScopeConfig true "scope config"
+// @Success 200 {object} models.AiReviewScopeConfig
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scope-configs [POST]
+func PostConnectionScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	body, err := withDefaultScopeConfig(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	input.Body = body
+	return dsHelper.ScopeConfigApi.Post(input)
+}
+
+// PatchConnectionScopeConfig updates a scope config of a connection
+// @Summary patch an aireview scope config of a connection
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeConfigId path int true "scope config ID"
+// @Param scopeConfig body models.AiReviewScopeConfig true "scope config"
+// @Success 200 {object} models.AiReviewScopeConfig
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scope-configs/{scopeConfigId} [PATCH]
+func PatchConnectionScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.Patch(input)
+}
+
+// GetConnectionScopeConfig gets a scope config of a connection
+// @Summary get an aireview scope config of a connection
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeConfigId path int true "scope config ID"
+// @Success 200 {object} models.AiReviewScopeConfig
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scope-configs/{scopeConfigId} [GET]
+func GetConnectionScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.GetDetail(input)
+}
+
+// GetConnectionScopeConfigList lists the scope configs of a connection
+// @Summary get the aireview scope configs of a connection
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Success 200 {object} []models.AiReviewScopeConfig
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scope-configs [GET]
+func GetConnectionScopeConfigList(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.GetAll(input)
+}
+
+// DeleteConnectionScopeConfig deletes a scope config of a connection
+// @Summary delete an aireview scope config of a connection
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeConfigId path int true "scope config ID"
+// @Success 200
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scope-configs/{scopeConfigId} [DELETE]
+func DeleteConnectionScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.Delete(input)
+}
+
+// GetProjectsByScopeConfig lists the projects whose repo scopes use a scope config
+// @Summary get the projects using an aireview scope config
+// @Tags plugins/aireview
+// @Param scopeConfigId path int true "scope config ID"
+// @Success 200 {object} models.ProjectScopeOutput
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/scope-config/{scopeConfigId}/projects [GET]
+func GetProjectsByScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeConfigApi.GetProjectsByScopeConfig(input)
+}
+
+// withDefaultScopeConfig returns the request body on top of the default scope config,
+// without the fields set by the database, and checks that every pattern compiles
+func withDefaultScopeConfig(body map[string]interface{}) (map[string]interface{}, errors.Error) {
+	config := models.GetDefaultScopeConfig()
+	if err := api.DecodeMapStruct(body, config, true); err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to decode scope config")
+	}
+	taskData := &tasks.AiReviewTaskData{Options: &tasks.AiReviewOptions{ScopeConfig: config}}
+	if err := tasks.CompilePatterns(taskData); err != nil {
+		return nil, errors.BadInput.Wrap(err, "scope config has invalid patterns")
+	}
+
+	raw, jsonErr := json.Marshal(config)
+	if jsonErr != nil {
+		return nil, errors.Default.Wrap(jsonErr, "failed to encode scope config")
+	}
+	merged := map[string]interface{}{}
+	if jsonErr = json.Unmarshal(raw, &merged); jsonErr != nil {
+		return nil, errors.Default.Wrap(jsonErr, "failed to decode scope config")
+	}
+	for _, field := range []string{"id", "createdAt", "updatedAt"} {
+		delete(merged, field)
+	}
+	return merged, nil
+}
@@ -0,0 +1,44 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDefaultScopeConfig(t *testing.T) {
+	defaults := models.GetDefaultScopeConfig()
+
+	body, err := withDefaultScopeConfig(map[string]interface{}{"name": "konflux", "qodoEnabled": false})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "konflux", body["name"])
+		assert.Equal(t, false, body["qodoEnabled"])
+		assert.Equal(t, defaults.CodeRabbitEnabled, body["codeRabbitEnabled"])
+		assert.Equal(t, defaults.CodeRabbitUsername, body["codeRabbitUsername"])
+		assert.NotContains(t, body, "id")
+		assert.NotContains(t, body, "createdAt")
+	}
+
+	_, err = withDefaultScopeConfig(map[string]interface{}{"name": "broken", "riskHighPattern": "(unclosed"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errors.BadInput, err.GetType())
+	}
+}
@@ -0,0 +1,251 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+const (
+	defaultDiscoverySample = 500
+	maxDiscoverySample     = 5000
+	// maxDiscoveryUsernames is how many usernames are listed per detected bot
+	maxDiscoveryUsernames = 5
+)
+
+// botLikeUsername matches account names that look like automation, e.g. "renovate[bot]" or "ci-bot"
+var botLikeUsername = regexp.MustCompile(`(?i)(\[bot\]$|[-_]?bot$|^bot[-_])`)
+
+// sampledComment is one PR comment considered by the discovery
+type sampledComment struct {
+	Username string `gorm:"column:username"`
+	Body     string `gorm:"column:body"`
+}
+
+// UsernameCount is how many sampled comments an account wrote
+type UsernameCount struct {
+	Username string `json:"username"`
+	Comments int    `json:"comments"`
+}
+
+// DetectedBot is a supported AI review tool found in the sampled comments
+type DetectedBot struct {
+	AiTool            string          `json:"aiTool"`
+	Comments          int             `json:"comments"`
+	MatchedByUsername int             `json:"matchedByUsername"`
+	MatchedByPattern  int             `json:"matchedByPattern"`
+	Usernames         []UsernameCount `json:"usernames"`
+	SuggestedUsername string          `json:"suggestedUsername"`
+}
+
+// ScopeConfigDiscovery is the result of inspecting the recent comments of a repo
+type ScopeConfigDiscovery struct {
+	SampledComments      int                         `json:"sampledComments"`
+	DetectedBots         []DetectedBot               `json:"detectedBots"`
+	UnrecognizedBots     []UsernameCount             `json:"unrecognizedBots"`
+	SuggestedScopeConfig *models.AiReviewScopeConfig `json:"suggestedScopeConfig"`
+}
+
+// botSignature is how a supported AI tool is recognized, using the default scope config patterns
+type botSignature struct {
+	aiTool        string
+	username      string
+	usernameRegex *regexp.Regexp
+	patternRegex  *regexp.Regexp
+}
+
+// defaultBotSignatures returns the signatures of the supported tools, in detectAiTool order
+func defaultBotSignatures(defaults *models.AiReviewScopeConfig) []botSignature {
+	signature := func(aiTool, username, pattern string) botSignature {
+		return botSignature{
+			aiTool:        aiTool,
+			username:      username,
+			usernameRegex: regexp.MustCompile("(?i)" + regexp.QuoteMeta(username)),
+			patternRegex:  regexp.MustCompile(pattern),
+		}
+	}
+	return []botSignature{
+		signature(models.AiToolCodeRabbit, defaults.CodeRabbitUsername, defaults.CodeRabbitPattern),
+		signature(models.AiToolCursorBugbot, defaults.CursorBugbotUsername, defaults.CursorBugbotPattern),
+		signature(models.AiToolQodo, defaults.QodoUsername, defaults.QodoPattern),
+		signature(models.AiToolGemini, defaults.GeminiUsername, defaults.GeminiPattern),
+		signature(models.AiToolCopilot, defaults.CopilotUsername, defaults.CopilotPattern),
+	}
+}
+
+// DiscoverScopeConfig suggests a scope config from the recent PR comments of a repo
+// @Summary Discover scope config
+// @Description Sample recent PR comments of a repo and suggest which AI review tools to enable and their usernames
+// @Tags plugins/aireview
+// @Param repoId query string false "Repository ID"
+// @Param projectName query string false "Project name (alternative to repoId)"
+// @Param sample query int false "Number of most recent comments to inspect" default(500)
+// @Success 200 {object} ScopeConfigDiscovery
+// @Router /plugins/aireview/scope-configs/discover [get]
+func DiscoverScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	scopeClauses := repoScopeClauses(input.Query, "pr.base_repo_id")
+	if len(scopeClauses) == 0 {
+		return nil, errors.BadInput.New("repoId or projectName is required")
+	}
+	sample := defaultDiscoverySample
+	if raw := input.Query.Get("sample"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			return nil, errors.BadInput.New("sample must be a positive integer")
+		}
+		sample = parsed
+	}
+	if sample > maxDiscoverySample {
+		sample = maxDiscoverySample
+	}
+
+	// Same author resolution as extractAiReviews: accounts.user_name, falling back to the domain account id
+	clauses := append([]dal.Clause{
+		dal.Select("COALESCE(NULLIF(a.user_name, ''), prc.account_id) AS username, prc.body"),
+		dal.From("pull_request_comments prc"),
+		dal.Join("JOIN pull_requests pr ON prc.pull_request_id = pr.id"),
+		dal.Join("LEFT JOIN accounts a ON prc.account_id = a.id"),
+	}, scopeClauses...)
+	clauses = append(clauses, dal.Orderby("prc.created_date DESC"), dal.Limit(sample))
+
+	var comments []sampledComment
+	if err := db.All(&comments, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to sample pull request comments")
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body:   discoverAiBots(comments),
+		Status: http.StatusOK,
+	}, nil
+}
+
+// discoverAiBots attributes the sampled comments to the supported AI tools and builds a suggested scope config.
+// A tool is enabled in the suggestion only if it wrote at least one sampled comment; patterns keep their defaults.
+func discoverAiBots(comments []sampledComment) *ScopeConfigDiscovery {
+	suggested := models.GetDefaultScopeConfig()
+	signatures := defaultBotSignatures(suggested)
+
+	bots := make(map[string]*DetectedBot)
+	botUsernames := make(map[string]map[string]int)
+	unrecognized := make(map[string]int)
+
+	for _, comment := range comments {
+		matched := false
+		for _, signature := range signatures {
+			byUsername := signature.usernameRegex.MatchString(comment.Username)
+			if !byUsername && !signature.patternRegex.MatchString(comment.Body) {
+				continue
+			}
+			bot := bots[signature.aiTool]
+			if bot == nil {
+				bot = &DetectedBot{AiTool: signature.aiTool}
+				bots[signature.aiTool] = bot
+				botUsernames[signature.aiTool] = make(map[string]int)
+			}
+			bot.Comments++
+			botUsernames[signature.aiTool][comment.Username]++
+			if byUsername {
+				bot.MatchedByUsername++
+			} else {
+				bot.MatchedByPattern++
+			}
+			matched = true
+			break
+		}
+		if !matched && comment.Username != "" && botLikeUsername.MatchString(comment.Username) {
+			unrecognized[comment.Username]++
+		}
+	}
+
+	discovery := &ScopeConfigDiscovery{
+		SampledComments:      len(comments),
+		DetectedBots:         []DetectedBot{},
+		UnrecognizedBots:     sortedUsernameCounts(unrecognized, 0),
+		SuggestedScopeConfig: suggested,
+	}
+	for _, signature := range signatures {
+		bot := bots[signature.aiTool]
+		if bot != nil {
+			bot.Usernames = sortedUsernameCounts(botUsernames[signature.aiTool], maxDiscoveryUsernames)
+			bot.SuggestedUsername = signature.username
+			// A pattern-only match means the tool posts under another account, e.g. a GitHub App
+			// installed under a custom name, which is then the better username to detect it by
+			if bot.MatchedByUsername == 0 && len(bot.Usernames) > 0 {
+				bot.SuggestedUsername = strings.TrimSuffix(bot.Usernames[0].Username, "[bot]")
+			}
+			discovery.DetectedBots = append(discovery.DetectedBots, *bot)
+		}
+		applyDiscoveredBot(suggested, signature.aiTool, bot)
+	}
+	return discovery
+}
+
+// applyDiscoveredBot enables or disables one tool in the suggested scope config
+func applyDiscoveredBot(config *models.AiReviewScopeConfig, aiTool string, bot *DetectedBot) {
+	enabled := bot != nil
+	username := func(current string) string {
+		if bot == nil || bot.SuggestedUsername == "" {
+			return current
+		}
+		return bot.SuggestedUsername
+	}
+	switch aiTool {
+	case models.AiToolCodeRabbit:
+		config.CodeRabbitEnabled = enabled
+		config.CodeRabbitUsername = username(config.CodeRabbitUsername)
+	case models.AiToolCursorBugbot:
+		config.CursorBugbotEnabled = enabled
+		config.CursorBugbotUsername = username(config.CursorBugbotUsername)
+	case models.AiToolQodo:
+		config.QodoEnabled = enabled
+		config.QodoUsername = username(config.QodoUsername)
+	case models.AiToolGemini:
+		config.GeminiEnabled = enabled
+		config.GeminiUsername = username(config.GeminiUsername)
+	case models.AiToolCopilot:
+		config.CopilotEnabled = enabled
+		config.CopilotUsername = username(config.CopilotUsername)
+	}
+}
+
+// sortedUsernameCounts orders usernames by comment count, most active first, keeping at most limit (0 = all)
+func sortedUsernameCounts(counts map[string]int, limit int) []UsernameCount {
+	result := make([]UsernameCount, 0, len(counts))
+	for username, comments := range counts {
+		result = append(result, UsernameCount{Username: username, Comments: comments})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Comments != result[j].Comments {
+			return result[i].Comments > result[j].Comments
+		}
+		return result[i].Username < result[j].Username
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
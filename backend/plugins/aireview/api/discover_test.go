@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverAiBots(t *testing.T) {
+	comments := []sampledComment{
+		{Username: "coderabbitai[bot]", Body: "## Walkthrough"},
+		{Username: "coderabbitai[bot]", Body: "Actionable comments posted: 2"},
+		{Username: "acme-review[bot]", Body: "## PR Reviewer Guide\nEstimated effort to review: 2"},
+		{Username: "renovate[bot]", Body: "Update dependency"},
+		{Username: "alice", Body: "LGTM"},
+	}
+
+	discovery := discoverAiBots(comments)
+
+	assert.Equal(t, 5, discovery.SampledComments)
+	assert.Len(t, discovery.DetectedBots, 2)
+
+	coderabbit := discovery.DetectedBots[0]
+	assert.Equal(t, models.AiToolCodeRabbit, coderabbit.AiTool)
+	assert.Equal(t, 2, coderabbit.Comments)
+	assert.Equal(t, 2, coderabbit.MatchedByUsername)
+	assert.Equal(t, "coderabbitai", coderabbit.SuggestedUsername)
+	assert.Equal(t, []UsernameCount{{Username: "coderabbitai[bot]", Comments: 2}}, coderabbit.Usernames)
+
+	qodo := discovery.DetectedBots[1]
+	assert.Equal(t, models.AiToolQodo, qodo.AiTool)
+	assert.Equal(t, 1, qodo.MatchedByPattern)
+	assert.Equal(t, "acme-review", qodo.SuggestedUsername, "pattern-only matches suggest the account actually posting")
+
+	assert.Equal(t, []UsernameCount{{Username: "renovate[bot]", Comments: 1}}, discovery.UnrecognizedBots)
+
+	config := discovery.SuggestedScopeConfig
+	assert.True(t, config.CodeRabbitEnabled)
+	assert.Equal(t, "coderabbitai", config.CodeRabbitUsername)
+	assert.True(t, config.QodoEnabled)
+	assert.Equal(t, "acme-review", config.QodoUsername)
+	assert.False(t, config.GeminiEnabled)
+	assert.Equal(t, "gemini-code-assist", config.GeminiUsername)
+	assert.False(t, config.CursorBugbotEnabled)
+	assert.Equal(t, models.GetDefaultScopeConfig().QodoPattern, config.QodoPattern)
+}
+
+func TestDiscoverAiBotsNoComments(t *testing.T) {
+	discovery := discoverAiBots(nil)
+	assert.Equal(t, 0, discovery.SampledComments)
+	assert.Empty(t, discovery.DetectedBots)
+	assert.Empty(t, discovery.UnrecognizedBots)
+	assert.False(t, discovery.SuggestedScopeConfig.CodeRabbitEnabled)
+}
+
+func TestSortedUsernameCounts(t *testing.T) {
+	counts := map[string]int{"b": 2, "a": 2, "c": 5}
+	assert.Equal(t, []UsernameCount{{"c", 5}, {"a", 2}, {"b", 2}}, sortedUsernameCounts(counts, 0))
+	assert.Equal(t, []UsernameCount{{"c", 5}}, sortedUsernameCounts(counts, 1))
+}
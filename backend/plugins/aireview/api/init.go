@@ -21,13 +21,29 @@ import (
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
 )
 
+// pluginName is the name of the plugin in pipeline tasks and blueprint scopes
+const pluginName = "aireview"
+
 var db dal.Dal
 var basicRes context.BasicRes
+var dsHelper *helper.DsHelper[models.AiReviewConnection, models.AiReviewRepo, models.AiReviewScopeConfig]
 
 // Init initializes the API with basic resources
-func Init(br context.BasicRes, _ plugin.PluginMeta) {
+func Init(br context.BasicRes, meta plugin.PluginMeta) {
 	basicRes = br
 	db = basicRes.GetDal()
+	dsHelper = helper.NewDataSourceHelper[
+		models.AiReviewConnection, models.AiReviewRepo, models.AiReviewScopeConfig,
+	](
+		basicRes,
+		meta.Name(),
+		[]string{"id", "name"},
+		func(c models.AiReviewConnection) models.AiReviewConnection { return c },
+		func(s models.AiReviewRepo) models.AiReviewRepo { return s },
+		nil,
+	)
 }
@@ -0,0 +1,342 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+const (
+	defaultLeaderboardDays = 90
+	// leaderboardCacheTTL is how long a computed leaderboard is served before it is recomputed
+	leaderboardCacheTTL = 10 * time.Minute
+	// leaderboardCacheMaxEntries bounds the cached project/window pairs, as any days value can be requested
+	leaderboardCacheMaxEntries = 100
+
+	LeaderboardSortAdoption   = "adoption"
+	LeaderboardSortPrecision  = "precision"
+	LeaderboardSortUnresolved = "unresolved"
+)
+
+// highSeverities are the finding severities counted as high severity
+var highSeverities = []string{models.FindingSeverityError, models.FindingSeverityCritical}
+
+// LeaderboardEntry holds the health figures of one repo
+type LeaderboardEntry struct {
+	RepoId                     string             `json:"repoId"`
+	RepoName                   string             `json:"repoName"`
+	PullRequests               int64              `json:"pullRequests"`
+	ReviewedPullRequests       int64              `json:"reviewedPullRequests"`
+	Adoption                   float64            `json:"adoption"` // percentage of PRs with an AI review
+	Predictions                PredictionAccuracy `json:"predictions"`
+	UnresolvedHighSeverity     int64              `json:"unresolvedHighSeverity"`
+	AdoptionRank               int                `json:"adoptionRank"`
+	PrecisionRank              int                `json:"precisionRank"`
+	UnresolvedHighSeverityRank int                `json:"unresolvedHighSeverityRank"`
+}
+
+// Leaderboard is a cached ranking of the repos of a project
+type Leaderboard struct {
+	ProjectName string             `json:"projectName"`
+	Days        int                `json:"days"`
+	SortBy      string             `json:"sortBy"`
+	GeneratedAt time.Time          `json:"generatedAt"`
+	Repos       []LeaderboardEntry `json:"repos"`
+}
+
+// repoCount is a per-repo count computed in SQL
+type repoCount struct {
+	RepoId string `gorm:"column:repo_id"`
+	Count  int64  `gorm:"column:count"`
+}
+
+type leaderboardCacheEntry struct {
+	generatedAt time.Time
+	repos       []LeaderboardEntry
+}
+
+// leaderboardCache holds computed leaderboards by project and window
+var leaderboardCache = struct {
+	sync.Mutex
+	entries map[string]leaderboardCacheEntry
+}{entries: make(map[string]leaderboardCacheEntry)}
+
+// GetLeaderboard ranks repos by AI review adoption, prediction precision and unresolved high-severity findings
+// @Summary Get repo leaderboard
+// @Description Rank the repos of a project by the share of PRs with an AI review, failure prediction precision
+// @Description and unresolved error/critical findings over the last N days. Results are cached for 10 minutes.
+// @Tags plugins/aireview
+// @Param projectName query string false "Project name (all repos with AI reviews if omitted)"
+// @Param days query int false "Look-back window in days" default(90)
+// @Param sortBy query string false "adoption, precision or unresolved" default(adoption)
+// @Param refresh query bool false "Recompute instead of serving the cached leaderboard"
+// @Success 200 {object} Leaderboard
+// @Router /plugins/aireview/leaderboard [get]
+func GetLeaderboard(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	projectName := input.Query.Get("projectName")
+	days := defaultLeaderboardDays
+	if s := input.Query.Get("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d <= 0 {
+			return nil, errors.BadInput.New(fmt.Sprintf("days must be a positive integer, got %q", s))
+		}
+		days = d
+	}
+	sortBy := input.Query.Get("sortBy")
+	if sortBy == "" {
+		sortBy = LeaderboardSortAdoption
+	}
+	if sortBy != LeaderboardSortAdoption && sortBy != LeaderboardSortPrecision && sortBy != LeaderboardSortUnresolved {
+		return nil, errors.BadInput.New(fmt.Sprintf("sortBy must be adoption, precision or unresolved, got %q", sortBy))
+	}
+
+	cacheKey := fmt.Sprintf("%s\x00%d", projectName, days)
+	leaderboardCache.Lock()
+	cached, ok := leaderboardCache.entries[cacheKey]
+	leaderboardCache.Unlock()
+	if !ok || input.Query.Get("refresh") == "true" || time.Since(cached.generatedAt) > leaderboardCacheTTL {
+		repos, err := computeLeaderboard(projectName, time.Now().AddDate(0, 0, -days))
+		if err != nil {
+			return nil, err
+		}
+		cached = leaderboardCacheEntry{generatedAt: time.Now(), repos: repos}
+		cacheLeaderboard(cacheKey, cached)
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body: Leaderboard{
+			ProjectName: projectName,
+			Days:        days,
+			SortBy:      sortBy,
+			GeneratedAt: cached.generatedAt,
+			Repos:       sortLeaderboard(cached.repos, sortBy),
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// cacheLeaderboard stores a computed leaderboard, dropping the expired entries and,
+// when the cache is still full, the oldest one
+func cacheLeaderboard(cacheKey string, entry leaderboardCacheEntry) {
+	leaderboardCache.Lock()
+	defer leaderboardCache.Unlock()
+	oldestKey := ""
+	for key, cached := range leaderboardCache.entries {
+		if time.Since(cached.generatedAt) > leaderboardCacheTTL {
+			delete(leaderboardCache.entries, key)
+		} else if oldestKey == "" || cached.generatedAt.Before(leaderboardCache.entries[oldestKey].generatedAt) {
+			oldestKey = key
+		}
+	}
+	if _, ok := leaderboardCache.entries[cacheKey]; !ok && len(leaderboardCache.entries) >= leaderboardCacheMaxEntries {
+		delete(leaderboardCache.entries, oldestKey)
+	}
+	leaderboardCache.entries[cacheKey] = entry
+}
+
+// computeLeaderboard gathers the figures of every repo of the project with PRs or AI reviews since the given time
+func computeLeaderboard(projectName string, since time.Time) ([]LeaderboardEntry, errors.Error) {
+	projectClauses := func(repoColumn string) []dal.Clause {
+		if projectName == "" {
+			return nil
+		}
+		return []dal.Clause{
+			dal.Join("JOIN project_mapping pm ON " + repoColumn + " = pm.row_id"),
+			dal.Where("pm.project_name = ? AND pm.`table` = ?", projectName, "repos"),
+		}
+	}
+	countByRepo := func(what string, clauses ...dal.Clause) (map[string]int64, errors.Error) {
+		var counts []repoCount
+		if err := db.All(&counts, clauses...); err != nil {
+			return nil, errors.Default.Wrap(err, "failed to count "+what)
+		}
+		result := make(map[string]int64, len(counts))
+		for _, c := range counts {
+			result[c.RepoId] = c.Count
+		}
+		return result, nil
+	}
+
+	pullRequests, err := countByRepo("pull requests", append([]dal.Clause{
+		dal.Select("pr.base_repo_id AS repo_id, COUNT(*) AS count"),
+		dal.From("pull_requests pr"),
+		dal.Where("pr.created_date >= ?", since),
+		dal.Groupby("pr.base_repo_id"),
+	}, projectClauses("pr.base_repo_id")...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	reviewed, err := countByRepo("reviewed pull requests", append([]dal.Clause{
+		dal.Select("pr.base_repo_id AS repo_id, COUNT(DISTINCT r.pull_request_id) AS count"),
+		dal.From("_tool_aireview_reviews r"),
+		dal.Join("JOIN pull_requests pr ON pr.id = r.pull_request_id"),
+		dal.Where("pr.created_date >= ?", since),
+		dal.Groupby("pr.base_repo_id"),
+	}, projectClauses("pr.base_repo_id")...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	unresolved, err := countByRepo("unresolved findings", append([]dal.Clause{
+		dal.Select("f.repo_id AS repo_id, COUNT(*) AS count"),
+		dal.From("_tool_aireview_findings f"),
+		dal.Where("f.created_date >= ? AND f.is_resolved = ? AND f.severity IN (?)", since, false, highSeverities),
+		dal.Groupby("f.repo_id"),
+	}, projectClauses("f.repo_id")...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	var outcomes []struct {
+		RepoId  string `gorm:"column:repo_id"`
+		Outcome string `gorm:"column:outcome"`
+		Count   int64  `gorm:"column:count"`
+	}
+	dbErr := db.All(&outcomes, append([]dal.Clause{
+		dal.Select("p.repo_id AS repo_id, p.prediction_outcome AS outcome, COUNT(*) AS count"),
+		dal.From("_tool_aireview_failure_predictions p"),
+		dal.Where("p.flagged_at >= ?", since),
+		dal.Groupby("p.repo_id, p.prediction_outcome"),
+	}, projectClauses("p.repo_id")...)...)
+	if dbErr != nil {
+		return nil, errors.Default.Wrap(dbErr, "failed to get prediction outcomes")
+	}
+	predictionCounts := make(map[string]map[string]int64)
+	for _, o := range outcomes {
+		if predictionCounts[o.RepoId] == nil {
+			predictionCounts[o.RepoId] = make(map[string]int64)
+		}
+		predictionCounts[o.RepoId][o.Outcome] = o.Count
+	}
+
+	// Without a project only repos that use an AI review tool are ranked
+	repoIds := make(map[string]bool)
+	for repoId := range reviewed {
+		repoIds[repoId] = true
+	}
+	if projectName != "" {
+		for repoId := range pullRequests {
+			repoIds[repoId] = true
+		}
+	}
+	for repoId := range unresolved {
+		repoIds[repoId] = true
+	}
+	for repoId := range predictionCounts {
+		repoIds[repoId] = true
+	}
+	if len(repoIds) == 0 {
+		return []LeaderboardEntry{}, nil
+	}
+
+	ids := make([]string, 0, len(repoIds))
+	for repoId := range repoIds {
+		ids = append(ids, repoId)
+	}
+	var repoNames []struct {
+		Id   string `gorm:"column:id"`
+		Name string `gorm:"column:name"`
+	}
+	dbErr = db.All(&repoNames, dal.Select("id, name"), dal.From("repos"), dal.Where("id IN (?)", ids))
+	if dbErr != nil {
+		return nil, errors.Default.Wrap(dbErr, "failed to get repo names")
+	}
+	names := make(map[string]string, len(repoNames))
+	for _, repo := range repoNames {
+		names[repo.Id] = repo.Name
+	}
+
+	entries := make([]LeaderboardEntry, 0, len(ids))
+	for _, repoId := range ids {
+		entries = append(entries, LeaderboardEntry{
+			RepoId:                 repoId,
+			RepoName:               names[repoId],
+			PullRequests:           pullRequests[repoId],
+			ReviewedPullRequests:   reviewed[repoId],
+			Adoption:               percentage(reviewed[repoId], pullRequests[repoId]),
+			Predictions:            predictionAccuracy(predictionCounts[repoId]),
+			UnresolvedHighSeverity: unresolved[repoId],
+		})
+	}
+	rankLeaderboard(entries)
+	return entries, nil
+}
+
+// rankLeaderboard sets the rank of every entry for each metric: highest adoption and
+// precision first, fewest unresolved high-severity findings first. Repos without
+// observed predictions are ranked after all repos with a precision. Ties share a rank.
+func rankLeaderboard(entries []LeaderboardEntry) {
+	assignRanks(entries, func(e *LeaderboardEntry) float64 { return e.Adoption }, func(e *LeaderboardEntry, rank int) { e.AdoptionRank = rank })
+	assignRanks(entries, func(e *LeaderboardEntry) float64 {
+		if e.Predictions.Observed == 0 {
+			return -1
+		}
+		return e.Predictions.Precision
+	}, func(e *LeaderboardEntry, rank int) { e.PrecisionRank = rank })
+	assignRanks(entries, func(e *LeaderboardEntry) float64 { return -float64(e.UnresolvedHighSeverity) }, func(e *LeaderboardEntry, rank int) { e.UnresolvedHighSeverityRank = rank })
+}
+
+// assignRanks ranks the entries by descending score using standard competition ranking (1, 2, 2, 4)
+func assignRanks(entries []LeaderboardEntry, score func(*LeaderboardEntry) float64, setRank func(*LeaderboardEntry, int)) {
+	order := make([]int, len(entries))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return score(&entries[order[a]]) > score(&entries[order[b]]) })
+	rank := 0
+	for position, index := range order {
+		if position == 0 || score(&entries[index]) != score(&entries[order[position-1]]) {
+			rank = position + 1
+		}
+		setRank(&entries[index], rank)
+	}
+}
+
+// sortLeaderboard returns a copy of the entries ordered by the rank of the sortBy metric, then by repo name
+func sortLeaderboard(entries []LeaderboardEntry, sortBy string) []LeaderboardEntry {
+	rank := func(e LeaderboardEntry) int {
+		switch sortBy {
+		case LeaderboardSortPrecision:
+			return e.PrecisionRank
+		case LeaderboardSortUnresolved:
+			return e.UnresolvedHighSeverityRank
+		default:
+			return e.AdoptionRank
+		}
+	}
+	sorted := make([]LeaderboardEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if rank(sorted[i]) != rank(sorted[j]) {
+			return rank(sorted[i]) < rank(sorted[j])
+		}
+		return sorted[i].RepoName < sorted[j].RepoName
+	})
+	return sorted
+}
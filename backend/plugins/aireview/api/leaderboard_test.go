@@ -0,0 +1,80 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankLeaderboard(t *testing.T) {
+	entries := []LeaderboardEntry{
+		{RepoName: "org/a", Adoption: 50, Predictions: PredictionAccuracy{Observed: 10, Precision: 80}, UnresolvedHighSeverity: 3},
+		{RepoName: "org/b", Adoption: 90, Predictions: PredictionAccuracy{Observed: 0}, UnresolvedHighSeverity: 0},
+		{RepoName: "org/c", Adoption: 50, Predictions: PredictionAccuracy{Observed: 4, Precision: 0}, UnresolvedHighSeverity: 7},
+	}
+
+	rankLeaderboard(entries)
+
+	assert.Equal(t, []int{2, 1, 2}, []int{entries[0].AdoptionRank, entries[1].AdoptionRank, entries[2].AdoptionRank}, "ties share a rank")
+	assert.Equal(t, []int{1, 3, 2}, []int{entries[0].PrecisionRank, entries[1].PrecisionRank, entries[2].PrecisionRank}, "repos without predictions come last")
+	assert.Equal(t, []int{2, 1, 3}, []int{entries[0].UnresolvedHighSeverityRank, entries[1].UnresolvedHighSeverityRank, entries[2].UnresolvedHighSeverityRank})
+}
+
+func TestSortLeaderboard(t *testing.T) {
+	entries := []LeaderboardEntry{
+		{RepoName: "org/c", AdoptionRank: 2, PrecisionRank: 1, UnresolvedHighSeverityRank: 3},
+		{RepoName: "org/a", AdoptionRank: 2, PrecisionRank: 3, UnresolvedHighSeverityRank: 1},
+		{RepoName: "org/b", AdoptionRank: 1, PrecisionRank: 2, UnresolvedHighSeverityRank: 2},
+	}
+	names := func(sorted []LeaderboardEntry) []string {
+		result := make([]string, len(sorted))
+		for i, e := range sorted {
+			result[i] = e.RepoName
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"org/b", "org/a", "org/c"}, names(sortLeaderboard(entries, LeaderboardSortAdoption)))
+	assert.Equal(t, []string{"org/c", "org/b", "org/a"}, names(sortLeaderboard(entries, LeaderboardSortPrecision)))
+	assert.Equal(t, []string{"org/a", "org/b", "org/c"}, names(sortLeaderboard(entries, LeaderboardSortUnresolved)))
+	assert.Equal(t, "org/c", entries[0].RepoName, "the cached entries are not reordered")
+}
+
+func TestCacheLeaderboard(t *testing.T) {
+	leaderboardCache.entries = make(map[string]leaderboardCacheEntry)
+	defer func() { leaderboardCache.entries = make(map[string]leaderboardCacheEntry) }()
+
+	now := time.Now()
+	cacheLeaderboard("expired", leaderboardCacheEntry{generatedAt: now.Add(-leaderboardCacheTTL - time.Minute)})
+	cacheLeaderboard("fresh", leaderboardCacheEntry{generatedAt: now})
+	assert.NotContains(t, leaderboardCache.entries, "expired", "expired entries are dropped")
+	assert.Contains(t, leaderboardCache.entries, "fresh")
+
+	cacheLeaderboard("oldest", leaderboardCacheEntry{generatedAt: now.Add(-time.Minute)})
+	for i := len(leaderboardCache.entries); i < leaderboardCacheMaxEntries; i++ {
+		cacheLeaderboard(fmt.Sprintf("key%d", i), leaderboardCacheEntry{generatedAt: now})
+	}
+	cacheLeaderboard("new", leaderboardCacheEntry{generatedAt: now})
+	assert.Len(t, leaderboardCache.entries, leaderboardCacheMaxEntries)
+	assert.NotContains(t, leaderboardCache.entries, "oldest", "the oldest entry makes room when the cache is full")
+	assert.Contains(t, leaderboardCache.entries, "new")
+}
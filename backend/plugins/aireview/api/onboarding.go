@@ -0,0 +1,274 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
+)
+
+// Onboarding inputs, in the order they are reported
+const (
+	inputRepo                = "repo"
+	inputPullRequests        = "pullRequests"
+	inputPullRequestComments = "pullRequestComments"
+	inputAiReviews           = "aiReviews"
+	inputAiFindings          = "aiFindings"
+	inputPullRequestCommits  = "pullRequestCommits"
+	inputCommitFiles         = "commitFiles"
+	inputCiJobs              = "ciJobs"
+	inputBugIssueLinks       = "bugIssueLinks"
+	inputProjectMapping      = "projectMapping"
+	inputDeployments         = "productionDeployments"
+)
+
+// onboardingInputSpec describes one input the aireview metrics are computed from
+type onboardingInputSpec struct {
+	name        string
+	description string
+	hint        string
+}
+
+var onboardingInputSpecs = []onboardingInputSpec{
+	{inputRepo, "Repository collected by the github or gitlab plugin", "Add the repository to a github or gitlab connection and collect it"},
+	{inputPullRequests, "Pull requests of the repository", "Collect the repository with the github or gitlab plugin"},
+	{inputPullRequestComments, "Pull request comments, where AI reviews are read from", "Enable comment collection in the github or gitlab scope config"},
+	{inputAiReviews, "AI reviews detected by the aireview task", "Run the aireview task; if comments exist but no reviews are found, check the tool usernames with GET /plugins/aireview/scope-configs/discover"},
+	{inputAiFindings, "Findings extracted from the AI reviews", "Findings are skipped with skipFindings, or the reviews hold no findings in a known format"},
+	{inputPullRequestCommits, "Commits of the pull requests", "Collect pull request commits with the github or gitlab plugin"},
+	{inputCommitFiles, "Files changed by the pull request commits", "Enable commit collection (with files) for the repository"},
+	{inputCiJobs, "Pull request CI jobs of the repository in ci_test_jobs", "Collect CI results with the testregistry plugin"},
+	{inputBugIssueLinks, "Bug issues linked to commits or pull requests of the repository", "Collect issues (e.g. with the jira plugin) and link them to pull requests or commits"},
+	{inputProjectMapping, "Projects the repository belongs to", "Add the repository to a DevLake project"},
+	{inputDeployments, "Successful production deployments of the cicd scopes of the repository's projects", "Add a CI/CD scope producing PRODUCTION deployments to the project and enable the dora metric"},
+}
+
+// onboardingMetricSpec describes one metric and the inputs it needs
+type onboardingMetricSpec struct {
+	name        string
+	description string
+	requires    []string
+}
+
+var onboardingMetricSpecs = []onboardingMetricSpec{
+	{"reviews", "AI review volume, stats and leaderboard", []string{inputPullRequestComments, inputAiReviews}},
+	{"findings", "Findings by category and severity, finding trends", []string{inputAiFindings}},
+	{"suggestionAcceptance", "Suggestions applied, matched against the commit diffs", []string{inputAiFindings, inputPullRequestCommits, inputCommitFiles}},
+	{"reviewSlo", "AI review response time SLO", []string{inputAiReviews, inputPullRequestCommits}},
+	{"approvalGating", "Merge time cost of required AI approvals, when aiApprovalRequired is set", []string{inputAiReviews, inputPullRequestCommits}},
+	{"failurePredictions", "Failure prediction precision and recall", []string{inputAiReviews, inputCiJobs}},
+	{"bugCorrelation", "Findings whose file was later changed by a bug fix", []string{inputAiFindings, inputBugIssueLinks, inputCommitFiles}},
+	{"projectDashboards", "Project-scoped domain tables used by the dashboards", []string{inputAiReviews, inputProjectMapping}},
+	{"doraOverlays", "AI flagged change and AI-predicted-risky deployment ratios in ai_dora_metrics", []string{inputAiReviews, inputProjectMapping, inputDeployments}},
+}
+
+// OnboardingInput reports whether one input is present for the repository
+type OnboardingInput struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Present     bool   `json:"present"`
+	Count       int64  `json:"count"`
+	Hint        string `json:"hint,omitempty"`
+}
+
+// OnboardingMetric reports whether one metric can be computed and which inputs it is missing
+type OnboardingMetric struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Computable  bool     `json:"computable"`
+	Requires    []string `json:"requires"`
+	Missing     []string `json:"missing"`
+}
+
+// OnboardingChecklist lists the inputs present for a repository and the metrics they allow
+type OnboardingChecklist struct {
+	RepoId   string             `json:"repoId"`
+	RepoName string             `json:"repoName"`
+	Projects []string           `json:"projects"`
+	Inputs   []OnboardingInput  `json:"inputs"`
+	Metrics  []OnboardingMetric `json:"metrics"`
+}
+
+// GetOnboardingChecklist reports which inputs of the aireview metrics a repository has
+// @Summary Repository onboarding checklist
+// @Description Report which inputs are present for a repository (PR comments, project mapping, CI jobs, bug links, ...) and which metrics can therefore be computed
+// @Tags plugins/aireview
+// @Param repoId query string true "Repository ID"
+// @Success 200 {object} OnboardingChecklist
+// @Router /plugins/aireview/onboarding [get]
+func GetOnboardingChecklist(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	repoId := input.Query.Get("repoId")
+	if repoId == "" {
+		return nil, errors.BadInput.New("repoId is required")
+	}
+
+	var repos []struct {
+		Name string `gorm:"column:name"`
+	}
+	if err := db.All(&repos, dal.Select("name"), dal.From("repos"), dal.Where("id = ?", repoId)); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load repo")
+	}
+	repoName := ""
+	if len(repos) > 0 {
+		repoName = repos[0].Name
+	}
+
+	var projects []string
+	err := db.Pluck("project_name", &projects,
+		dal.From("project_mapping"),
+		dal.Where("`table` = ? AND row_id = ?", "repos", repoId),
+		dal.Orderby("project_name"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load project mapping")
+	}
+
+	counts, err := countOnboardingInputs(repoId, repoName)
+	if err != nil {
+		return nil, err
+	}
+	counts[inputRepo] = int64(len(repos))
+	counts[inputProjectMapping] = int64(len(projects))
+
+	return &plugin.ApiResourceOutput{
+		Body:   buildOnboardingChecklist(repoId, repoName, projects, counts),
+		Status: http.StatusOK,
+	}, nil
+}
+
+// countOnboardingInputs counts the rows of each input read from the database
+func countOnboardingInputs(repoId, repoName string) (map[string]int64, errors.Error) {
+	queries := map[string][]dal.Clause{
+		inputPullRequests: {
+			dal.From("pull_requests"),
+			dal.Where("base_repo_id = ?", repoId),
+		},
+		inputPullRequestComments: {
+			dal.From("pull_request_comments prc"),
+			dal.Join("JOIN pull_requests pr ON prc.pull_request_id = pr.id"),
+			dal.Where("pr.base_repo_id = ?", repoId),
+		},
+		inputAiReviews: {
+			dal.From("_tool_aireview_reviews"),
+			dal.Where("repo_id = ?", repoId),
+		},
+		inputAiFindings: {
+			dal.From("_tool_aireview_findings"),
+			dal.Where("repo_id = ?", repoId),
+		},
+		inputPullRequestCommits: {
+			dal.From("pull_request_commits prc"),
+			dal.Join("JOIN pull_requests pr ON prc.pull_request_id = pr.id"),
+			dal.Where("pr.base_repo_id = ?", repoId),
+		},
+		inputCommitFiles: {
+			dal.From("commit_files cf"),
+			dal.Join("JOIN pull_request_commits prc ON cf.commit_sha = prc.commit_sha"),
+			dal.Join("JOIN pull_requests pr ON prc.pull_request_id = pr.id"),
+			dal.Where("pr.base_repo_id = ?", repoId),
+		},
+		// Deployments as counted by calculateDoraOverlays, in any project of the repo
+		inputDeployments: {
+			dal.From("cicd_deployment_commits cdc"),
+			dal.Join("JOIN project_mapping pm ON cdc.cicd_scope_id = pm.row_id AND pm.`table` = 'cicd_scopes'"),
+			dal.Where("cdc.result = 'SUCCESS' AND cdc.environment = 'PRODUCTION' AND pm.project_name IN "+
+				"(SELECT project_name FROM project_mapping WHERE row_id = ? AND `table` = 'repos')", repoId),
+		},
+		// Bug links as used by correlateFindingsWithBugs: through issue_commits or pull_request_issues
+		inputBugIssueLinks: {
+			dal.From("issues i"),
+			dal.Where("i.type = ? AND (i.id IN (SELECT ic.issue_id FROM issue_commits ic JOIN repo_commits rc ON rc.commit_sha = ic.commit_sha WHERE rc.repo_id = ?)"+
+				" OR i.id IN (SELECT pri.issue_id FROM pull_request_issues pri JOIN pull_requests pr ON pr.id = pri.pull_request_id WHERE pr.base_repo_id = ?))",
+				ticket.BUG, repoId, repoId),
+		},
+	}
+
+	counts := make(map[string]int64, len(onboardingInputSpecs))
+	for name, clauses := range queries {
+		count, err := db.Count(clauses...)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to count "+name)
+		}
+		counts[name] = count
+	}
+
+	// calculateFailurePredictions matches CI jobs on the repo short name and PR number
+	if repoName != "" {
+		count, err := db.Count(
+			dal.From("ci_test_jobs"),
+			dal.Where("trigger_type = 'pull_request' AND pull_request_number > 0 AND repository = ?", tasks.RepoShortNameFrom(repoName)),
+		)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to count "+inputCiJobs)
+		}
+		counts[inputCiJobs] = count
+	}
+	return counts, nil
+}
+
+// buildOnboardingChecklist reports each input as present when it has rows, and each
+// metric as computable when all the inputs it requires are present
+func buildOnboardingChecklist(repoId, repoName string, projects []string, counts map[string]int64) *OnboardingChecklist {
+	checklist := &OnboardingChecklist{
+		RepoId:   repoId,
+		RepoName: repoName,
+		Projects: projects,
+		Inputs:   make([]OnboardingInput, 0, len(onboardingInputSpecs)),
+		Metrics:  make([]OnboardingMetric, 0, len(onboardingMetricSpecs)),
+	}
+	if checklist.Projects == nil {
+		checklist.Projects = []string{}
+	}
+
+	present := make(map[string]bool, len(onboardingInputSpecs))
+	for _, spec := range onboardingInputSpecs {
+		in := OnboardingInput{
+			Name:        spec.name,
+			Description: spec.description,
+			Count:       counts[spec.name],
+			Present:     counts[spec.name] > 0,
+		}
+		if !in.Present {
+			in.Hint = spec.hint
+		}
+		present[spec.name] = in.Present
+		checklist.Inputs = append(checklist.Inputs, in)
+	}
+
+	for _, spec := range onboardingMetricSpecs {
+		metric := OnboardingMetric{
+			Name:        spec.name,
+			Description: spec.description,
+			Requires:    spec.requires,
+			Missing:     []string{},
+		}
+		for _, name := range spec.requires {
+			if !present[name] {
+				metric.Missing = append(metric.Missing, name)
+			}
+		}
+		metric.Computable = len(metric.Missing) == 0
+		checklist.Metrics = append(checklist.Metrics, metric)
+	}
+	return checklist
+}
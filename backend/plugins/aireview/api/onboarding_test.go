@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildOnboardingChecklist(t *testing.T) {
+	counts := map[string]int64{
+		inputRepo:                1,
+		inputPullRequests:        40,
+		inputPullRequestComments: 300,
+		inputAiReviews:           25,
+		inputAiFindings:          80,
+		inputPullRequestCommits:  120,
+	}
+	checklist := buildOnboardingChecklist("github:GithubRepo:1:100", "konflux-ci/build-service", nil, counts)
+
+	assert.Equal(t, "konflux-ci/build-service", checklist.RepoName)
+	assert.NotNil(t, checklist.Projects)
+	assert.Len(t, checklist.Inputs, len(onboardingInputSpecs))
+
+	inputs := make(map[string]OnboardingInput)
+	for _, in := range checklist.Inputs {
+		inputs[in.Name] = in
+	}
+	assert.True(t, inputs[inputAiReviews].Present)
+	assert.Equal(t, int64(25), inputs[inputAiReviews].Count)
+	assert.Empty(t, inputs[inputAiReviews].Hint)
+	assert.False(t, inputs[inputCiJobs].Present)
+	assert.NotEmpty(t, inputs[inputCiJobs].Hint)
+
+	metrics := make(map[string]OnboardingMetric)
+	for _, m := range checklist.Metrics {
+		metrics[m.Name] = m
+	}
+	assert.True(t, metrics["reviews"].Computable)
+	assert.True(t, metrics["reviewSlo"].Computable)
+	assert.Empty(t, metrics["reviewSlo"].Missing)
+	assert.False(t, metrics["failurePredictions"].Computable)
+	assert.Equal(t, []string{inputCiJobs}, metrics["failurePredictions"].Missing)
+	assert.Equal(t, []string{inputBugIssueLinks, inputCommitFiles}, metrics["bugCorrelation"].Missing)
+	assert.Equal(t, []string{inputProjectMapping}, metrics["projectDashboards"].Missing)
+	assert.Equal(t, []string{inputProjectMapping, inputDeployments}, metrics["doraOverlays"].Missing)
+}
+
+func TestOnboardingMetricSpecsUseKnownInputs(t *testing.T) {
+	known := make(map[string]bool)
+	for _, spec := range onboardingInputSpecs {
+		known[spec.name] = true
+	}
+	for _, spec := range onboardingMetricSpecs {
+		for _, name := range spec.requires {
+			assert.True(t, known[name], "metric %s requires unknown input %s", spec.name, name)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// PatternCatalogResponse is the bundled catalog of default detection patterns
+type PatternCatalogResponse struct {
+	Version  int                          `json:"version"`
+	Patterns []models.PatternCatalogEntry `json:"patterns"`
+}
+
+// AppliedPatternCatalog is the outcome of applying the catalog to one scope config
+type AppliedPatternCatalog struct {
+	ScopeConfigId   uint64 `json:"scopeConfigId"`
+	ScopeConfigName string `json:"scopeConfigName"`
+	*models.PatternCatalogResult
+}
+
+// ApplyPatternCatalogResult lists the scope configs the catalog was applied to
+type ApplyPatternCatalogResult struct {
+	Version      int                     `json:"version"`
+	DryRun       bool                    `json:"dryRun"`
+	ScopeConfigs []AppliedPatternCatalog `json:"scopeConfigs"`
+}
+
+// GetPatternCatalog returns the default detection patterns shipped with the plugin
+// @Summary Get the default pattern catalog
+// @Description Get every default detection pattern shipped per AI tool, with the catalog version that introduced it
+// @Tags plugins/aireview
+// @Success 200 {object} PatternCatalogResponse
+// @Router /plugins/aireview/scope-configs/pattern-catalog [get]
+func GetPatternCatalog(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return &plugin.ApiResourceOutput{
+		Body: PatternCatalogResponse{
+			Version:  models.PatternCatalogVersion,
+			Patterns: models.PatternCatalog,
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// ApplyPatternCatalog re-applies the latest default detection patterns to existing scope configs
+// @Summary Apply the default pattern catalog
+// @Description Upgrade the detection patterns of scope configs still holding a previous default to the latest default, e.g. after a vendor changed its comment format.
+// @Description Patterns set by users are kept and listed as preserved. With dryRun=true the changes are only reported.
+// @Tags plugins/aireview
+// @Param id query int false "Only apply to this scope config (all scope configs by default)"
+// @Param dryRun query bool false "Report the changes without saving them"
+// @Success 200 {object} ApplyPatternCatalogResult
+// @Router /plugins/aireview/scope-configs/pattern-catalog/apply [post]
+func ApplyPatternCatalog(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	dryRun := false
+	if value := input.Query.Get("dryRun"); value != "" {
+		parsed, parseErr := strconv.ParseBool(value)
+		if parseErr != nil {
+			return nil, errors.BadInput.Wrap(parseErr, "dryRun must be a boolean")
+		}
+		dryRun = parsed
+	}
+	clauses := []dal.Clause{dal.From(&models.AiReviewScopeConfig{}), dal.Orderby("id")}
+	if value := input.Query.Get("id"); value != "" {
+		configId, parseErr := strconv.ParseUint(value, 10, 64)
+		if parseErr != nil {
+			return nil, errors.BadInput.Wrap(parseErr, "invalid scope config id")
+		}
+		clauses = append(clauses, dal.Where("id = ?", configId))
+	}
+
+	var configs []models.AiReviewScopeConfig
+	if err := db.All(&configs, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query scope configs")
+	}
+	if len(configs) == 0 && input.Query.Get("id") != "" {
+		return nil, errors.NotFound.New("scope config not found")
+	}
+
+	result := &ApplyPatternCatalogResult{
+		Version:      models.PatternCatalogVersion,
+		DryRun:       dryRun,
+		ScopeConfigs: make([]AppliedPatternCatalog, 0, len(configs)),
+	}
+	for i := range configs {
+		config := &configs[i]
+		applied := applyPatternCatalog(config)
+		result.ScopeConfigs = append(result.ScopeConfigs, applied)
+		if dryRun || (len(applied.Updated) == 0 && applied.FromVersion == applied.ToVersion) {
+			continue
+		}
+		if err := db.Update(config); err != nil {
+			return nil, errors.Default.Wrap(err, "failed to update scope config")
+		}
+	}
+	return &plugin.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+}
+
+// applyPatternCatalog applies the bundled catalog to the config
+func applyPatternCatalog(config *models.AiReviewScopeConfig) AppliedPatternCatalog {
+	return AppliedPatternCatalog{
+		ScopeConfigId:        config.ID,
+		ScopeConfigName:      config.Name,
+		PatternCatalogResult: models.ApplyPatternCatalog(config, models.PatternCatalog),
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultScopeConfigMatchesPatternCatalog(t *testing.T) {
+	config := models.GetDefaultScopeConfig()
+	assert.Equal(t, models.PatternCatalogVersion, config.PatternCatalogVersion)
+
+	latest := make(map[string]models.PatternCatalogEntry)
+	for _, entry := range models.PatternCatalog {
+		assert.NotNil(t, config.PatternField(entry.Field), "unknown catalog field %s", entry.Field)
+		assert.LessOrEqual(t, entry.Version, models.PatternCatalogVersion, entry.Field)
+		if entry.Version >= latest[entry.Field].Version {
+			latest[entry.Field] = entry
+		}
+	}
+	for field, entry := range latest {
+		assert.Equal(t, entry.Value, *config.PatternField(field), field)
+	}
+
+	applied := applyPatternCatalog(config)
+	assert.Empty(t, applied.Updated)
+	assert.Empty(t, applied.Preserved)
+}
+
+func TestApplyPatternCatalog(t *testing.T) {
+	catalog := []models.PatternCatalogEntry{
+		{Version: 1, Tool: models.AiToolCodeRabbit, Field: "codeRabbitPattern", Value: "(?i)coderabbit"},
+		{Version: 1, Tool: models.AiToolQodo, Field: "qodoPattern", Value: "(?i)qodo"},
+		{Version: 2, Tool: models.AiToolCodeRabbit, Field: "codeRabbitPattern", Value: "(?i)(coderabbit|actionable comments posted)"},
+		{Version: 2, Tool: models.AiToolQodo, Field: "qodoPattern", Value: "(?i)(qodo|pr code suggestions)"},
+		{Version: 2, Field: "botUsernamePattern", Value: "-robot$"},
+	}
+
+	t.Run("previous defaults are upgraded, overrides are kept", func(t *testing.T) {
+		config := &models.AiReviewScopeConfig{
+			CodeRabbitPattern:     "(?i)coderabbit",
+			QodoPattern:           "(?i)(qodo|our-bot)",
+			PatternCatalogVersion: 1,
+		}
+		result := models.ApplyPatternCatalog(config, catalog)
+
+		assert.Equal(t, 1, result.FromVersion)
+		assert.Equal(t, 2, result.ToVersion)
+		assert.Equal(t, 2, config.PatternCatalogVersion)
+		assert.Equal(t, "(?i)(coderabbit|actionable comments posted)", config.CodeRabbitPattern)
+		assert.Equal(t, "(?i)(qodo|our-bot)", config.QodoPattern)
+		// introduced after the config's version, so the empty field was never set
+		assert.Equal(t, "-robot$", config.BotUsernamePattern)
+
+		if assert.Len(t, result.Updated, 2) {
+			assert.Equal(t, models.PatternCatalogChange{
+				Tool:     models.AiToolCodeRabbit,
+				Field:    "codeRabbitPattern",
+				OldValue: "(?i)coderabbit",
+				NewValue: "(?i)(coderabbit|actionable comments posted)",
+			}, result.Updated[0])
+			assert.Equal(t, "botUsernamePattern", result.Updated[1].Field)
+		}
+		if assert.Len(t, result.Preserved, 1) {
+			assert.Equal(t, "qodoPattern", result.Preserved[0].Field)
+			assert.Equal(t, models.AiToolQodo, result.Preserved[0].Tool)
+		}
+	})
+
+	t.Run("cleared fields are kept", func(t *testing.T) {
+		config := &models.AiReviewScopeConfig{
+			CodeRabbitPattern:     "(?i)(coderabbit|actionable comments posted)",
+			QodoPattern:           "",
+			BotUsernamePattern:    "",
+			PatternCatalogVersion: 2,
+		}
+		result := models.ApplyPatternCatalog(config, catalog)
+
+		assert.Empty(t, result.Updated)
+		assert.Len(t, result.Preserved, 2)
+		assert.Empty(t, config.QodoPattern)
+		assert.Empty(t, config.BotUsernamePattern)
+	})
+
+	t.Run("configs created before the catalog get every default", func(t *testing.T) {
+		config := &models.AiReviewScopeConfig{}
+		result := models.ApplyPatternCatalog(config, catalog)
+
+		assert.Equal(t, 0, result.FromVersion)
+		assert.Len(t, result.Updated, 3)
+		assert.Empty(t, result.Preserved)
+		assert.Equal(t, "(?i)(qodo|pr code suggestions)", config.QodoPattern)
+	})
+}
@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	domainCode "github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// repoDataTables lists the tables holding aireview rows of a repository, all keyed by repo_id
+var repoDataTables = []dal.Tabler{
+	&models.AiReview{},
+	&models.AiReviewFinding{},
+	&models.AiReviewIssueRef{},
+	&models.AiFailurePrediction{},
+	&models.AiPredictionMetrics{},
+	&models.AiReviewSloMetric{},
+	&models.AiApprovalGatingMetric{},
+	&models.AiFindingTrendAlert{},
+	&models.AiPrDescription{},
+	&domainCode.AiReview{},
+	&domainCode.AiFailurePrediction{},
+	&domainCode.AiPredictionMetrics{},
+}
+
+// PurgedTable reports the rows of one table removed for the repository
+type PurgedTable struct {
+	Table string `json:"table"`
+	Rows  int64  `json:"rows"`
+}
+
+// PurgeRepoDataResult reports the aireview rows removed for a repository, or that would be in a dry run
+type PurgeRepoDataResult struct {
+	RepoId    string        `json:"repoId"`
+	DryRun    bool          `json:"dryRun"`
+	TotalRows int64         `json:"totalRows"`
+	Tables    []PurgedTable `json:"tables"`
+}
+
+// PurgeRepoData removes the aireview data of a repository
+// @Summary Purge the aireview data of a repository
+// @Description Delete the reviews, findings, issue references, PR descriptions, failure predictions and metrics of a repository, in the tool layer and the project domain tables, e.g. when the repo is offboarded or its data must be rebuilt after a detection config fix. The next aireview run rebuilds whatever still applies. With dryRun=true only the row counts are returned.
+// @Tags plugins/aireview
+// @Param repoId path string true "Repository ID"
+// @Param dryRun query bool false "Count the rows without deleting them"
+// @Success 200 {object} PurgeRepoDataResult
+// @Router /plugins/aireview/repos/{repoId}/data [delete]
+func PurgeRepoData(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	repoId := input.Params["repoId"]
+	if repoId == "" {
+		return nil, errors.BadInput.New("repoId is required")
+	}
+	dryRun := false
+	if value := input.Query.Get("dryRun"); value != "" {
+		parsed, parseErr := strconv.ParseBool(value)
+		if parseErr != nil {
+			return nil, errors.BadInput.Wrap(parseErr, "dryRun must be a boolean")
+		}
+		dryRun = parsed
+	}
+
+	var err errors.Error
+	txHelper := dbhelper.NewTxHelper(basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+
+	var result *PurgeRepoDataResult
+	result, err = purgeRepoData(tx, repoId, dryRun)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+}
+
+// purgeRepoData counts the rows of each aireview table for the repository and deletes them unless dryRun
+func purgeRepoData(tx dal.Dal, repoId string, dryRun bool) (*PurgeRepoDataResult, errors.Error) {
+	result := &PurgeRepoDataResult{RepoId: repoId, DryRun: dryRun, Tables: make([]PurgedTable, 0, len(repoDataTables))}
+	for _, table := range repoDataTables {
+		rows, err := tx.Count(dal.From(table), dal.Where("repo_id = ?", repoId))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to count the rows of "+table.TableName())
+		}
+		if rows > 0 && !dryRun {
+			if err := tx.Delete(table, dal.Where("repo_id = ?", repoId)); err != nil {
+				return nil, errors.Default.Wrap(err, "failed to delete the rows of "+table.TableName())
+			}
+		}
+		result.Tables = append(result.Tables, PurgedTable{Table: table.TableName(), Rows: rows})
+		result.TotalRows += rows
+	}
+	return result, nil
+}
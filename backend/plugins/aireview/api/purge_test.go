@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPurgeDal() *mockdal.Dal {
+	db := new(mockdal.Dal)
+	db.On("Count", mock.Anything).Return(int64(3), nil).Once()
+	db.On("Count", mock.Anything).Return(int64(5), nil).Once()
+	db.On("Count", mock.Anything).Return(int64(0), nil)
+	db.On("Delete", mock.Anything, mock.Anything).Return(nil)
+	return db
+}
+
+func TestPurgeRepoData(t *testing.T) {
+	db := newPurgeDal()
+	result, err := purgeRepoData(db, "github:GithubRepo:1:42", false)
+	assert.Nil(t, err)
+	assert.False(t, result.DryRun)
+	assert.Equal(t, int64(8), result.TotalRows)
+	assert.Len(t, result.Tables, len(repoDataTables))
+	assert.Equal(t, PurgedTable{Table: models.AiReview{}.TableName(), Rows: 3}, result.Tables[0])
+	assert.Equal(t, PurgedTable{Table: models.AiReviewFinding{}.TableName(), Rows: 5}, result.Tables[1])
+	// tables without rows are not deleted from
+	db.AssertNumberOfCalls(t, "Delete", 2)
+	db.AssertCalled(t, "Delete", &models.AiReview{}, []dal.Clause{dal.Where("repo_id = ?", "github:GithubRepo:1:42")})
+}
+
+func TestPurgeRepoData_DryRun(t *testing.T) {
+	db := newPurgeDal()
+	result, err := purgeRepoData(db, "github:GithubRepo:1:42", true)
+	assert.Nil(t, err)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, int64(8), result.TotalRows)
+	db.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,148 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	dsmodels "github.com/apache/incubator-devlake/helpers/pluginhelper/api/models"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// remoteScopesPageSize is the number of repos listed per page of remote scopes
+const remoteScopesPageSize = 100
+
+// RemoteScopes lists the repos that can be added as scopes: the domain repos collected by
+// the github and gitlab plugins, sorted by name. pageToken is the page number.
+// @Summary list the repos aireview can analyze
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param pageToken query string false "page token, from the nextPageToken of the previous page"
+// @Success 200 {object} dsmodels.DsRemoteApiScopeList[models.AiReviewRepo]
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/remote-scopes [GET]
+func RemoteScopes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if _, err := dsHelper.ConnApi.FindByPk(input); err != nil {
+		return nil, err
+	}
+	page := 1
+	if pageToken := input.Query.Get("pageToken"); pageToken != "" {
+		parsed, err := strconv.Atoi(pageToken)
+		if err != nil || parsed < 1 {
+			return nil, errors.BadInput.New("invalid pageToken")
+		}
+		page = parsed
+	}
+	repos, err := listDomainRepos("", page, remoteScopesPageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	nextPageToken := ""
+	if len(repos) > remoteScopesPageSize {
+		repos = repos[:remoteScopesPageSize]
+		nextPageToken = strconv.Itoa(page + 1)
+	}
+	return &plugin.ApiResourceOutput{Body: map[string]interface{}{
+		"children":      remoteScopeEntries(repos),
+		"nextPageToken": nextPageToken,
+	}, Status: http.StatusOK}, nil
+}
+
+// SearchRemoteScopes finds the domain repos whose name contains a search term
+// @Summary search the repos aireview can analyze
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param search query string true "case-insensitive part of the repo name"
+// @Param page query int false "page number, default 1"
+// @Param pageSize query int false "page size, default 50"
+// @Success 200 {object} dsmodels.DsRemoteApiScopeList[models.AiReviewRepo]
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/search-remote-scopes [GET]
+func SearchRemoteScopes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if _, err := dsHelper.ConnApi.FindByPk(input); err != nil {
+		return nil, err
+	}
+	search := strings.TrimSpace(input.Query.Get("search"))
+	if search == "" {
+		return nil, errors.BadInput.New("search is required")
+	}
+	page, _ := strconv.Atoi(input.Query.Get("page"))
+	if page <= 0 {
+		page = 1
+	}
+	pageSize, _ := strconv.Atoi(input.Query.Get("pageSize"))
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 50
+	}
+	repos, err := listDomainRepos(search, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: map[string]interface{}{
+		"children": remoteScopeEntries(repos),
+		"page":     page,
+		"pageSize": pageSize,
+	}, Status: http.StatusOK}, nil
+}
+
+// listDomainRepos loads a page of the domain repos sorted by name, only those whose name
+// contains search when it isn't empty
+func listDomainRepos(search string, page, pageSize int) ([]code.Repo, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(&code.Repo{}),
+		dal.Where("deleted = ?", false),
+	}
+	if search != "" {
+		escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(strings.ToLower(search))
+		clauses = append(clauses, dal.Where("LOWER(name) LIKE ?", "%"+escaped+"%"))
+	}
+	var repos []code.Repo
+	err := db.All(&repos, append(clauses,
+		dal.Orderby("name, id"),
+		dal.Limit(pageSize),
+		dal.Offset((page-1)*pageSize),
+	)...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to list the repos")
+	}
+	return repos, nil
+}
+
+// remoteScopeEntries converts domain repos into remote scope entries holding a repo scope
+func remoteScopeEntries(repos []code.Repo) []dsmodels.DsRemoteApiScopeListEntry[models.AiReviewRepo] {
+	entries := make([]dsmodels.DsRemoteApiScopeListEntry[models.AiReviewRepo], 0, len(repos))
+	for _, repo := range repos {
+		scope := &models.AiReviewRepo{Id: repo.Id, Name: repo.Name, Url: repo.Url}
+		entries = append(entries, dsmodels.DsRemoteApiScopeListEntry[models.AiReviewRepo]{
+			Type:     helper.RAS_ENTRY_TYPE_SCOPE,
+			Id:       scope.ScopeId(),
+			Name:     scope.ScopeName(),
+			FullName: scope.ScopeFullName(),
+			Data:     scope,
+		})
+	}
+	return entries
+}
@@ -0,0 +1,42 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteScopeEntries(t *testing.T) {
+	entries := remoteScopeEntries([]code.Repo{{
+		DomainEntity: domainlayer.DomainEntity{Id: "github:GithubRepo:1:100"},
+		Name:         "konflux-ci/build-service",
+		Url:          "https://github.com/konflux-ci/build-service",
+	}})
+
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "scope", entries[0].Type)
+		assert.Nil(t, entries[0].ParentId)
+		assert.Equal(t, "github:GithubRepo:1:100", entries[0].Id)
+		assert.Equal(t, "konflux-ci/build-service", entries[0].FullName)
+		assert.Equal(t, "https://github.com/konflux-ci/build-service", entries[0].Data.Url)
+	}
+	assert.Empty(t, remoteScopeEntries(nil))
+}
@@ -0,0 +1,111 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
+)
+
+// GetReviewDebug replays the extraction of a stored review
+// @Summary Debug the extraction of an AI review
+// @Description Recompute the intermediate parse artifacts of a review from its stored body: the detection
+// @Description pattern that identified the tool, the converted body split into sections, the summary, the
+// @Description risk pattern hit, the hits of every metric regex and the findings. Fields whose stored value
+// @Description differs from the recomputed one are listed in mismatches. bodyTruncated is set when the body
+// @Description was truncated by the retention cleanup, in which case the recomputed values are not reliable.
+// @Tags plugins/aireview
+// @Param id path string true "Review ID"
+// @Param scopeConfigId query int false "Scope config to replay the extraction with, otherwise the one of the review's repo scope, else the default scope config"
+// @Success 200 {object} tasks.ReviewExtractionDebug
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Failure 404 {string} errcode.Error "Not Found"
+// @Router /plugins/aireview/reviews/{id}/debug [get]
+func GetReviewDebug(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	reviewId := input.Params["id"]
+	if reviewId == "" {
+		return nil, errors.BadInput.New("review id is required")
+	}
+
+	var review models.AiReview
+	if err := db.First(&review, dal.Where("id = ?", reviewId)); err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.Wrap(err, "review not found")
+		}
+		return nil, errors.Default.Wrap(err, "failed to get review")
+	}
+
+	var configId uint64
+	if s := input.Query.Get("scopeConfigId"); s != "" {
+		var convErr error
+		configId, convErr = strconv.ParseUint(s, 10, 64)
+		if convErr != nil {
+			return nil, errors.BadInput.Wrap(convErr, "invalid scope config id")
+		}
+	} else {
+		var err errors.Error
+		configId, err = reviewScopeConfigId(&review)
+		if err != nil {
+			return nil, err
+		}
+	}
+	config := models.GetDefaultScopeConfig()
+	if configId != 0 {
+		config = &models.AiReviewScopeConfig{}
+		if err := db.First(config, dal.Where("id = ?", configId)); err != nil {
+			if db.IsErrorNotFound(err) {
+				return nil, errors.NotFound.Wrap(err, "scope config not found")
+			}
+			return nil, errors.Default.Wrap(err, "failed to get scope config")
+		}
+	}
+
+	taskData := &tasks.AiReviewTaskData{Options: &tasks.AiReviewOptions{ScopeConfig: config}}
+	if err := tasks.CompilePatterns(taskData); err != nil {
+		return nil, err
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body:   tasks.DebugReviewExtraction(taskData, &review),
+		Status: http.StatusOK,
+	}, nil
+}
+
+// reviewScopeConfigId returns the scope config of the aireview repo scope of a review, 0 when
+// its repo has no scope or the scope has no scope config
+func reviewScopeConfigId(review *models.AiReview) (uint64, errors.Error) {
+	var repos []models.AiReviewRepo
+	err := db.All(&repos,
+		dal.Where("id = ? AND scope_config_id != 0", review.RepoId),
+		dal.Orderby("connection_id"),
+		dal.Limit(1),
+	)
+	if err != nil {
+		return 0, errors.Default.Wrap(err, "failed to get the repo scope of the review")
+	}
+	if len(repos) == 0 {
+		return 0, nil
+	}
+	return repos[0].ScopeConfigId, nil
+}
@@ -18,8 +18,12 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
+	"math"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
@@ -37,6 +41,8 @@ import (
 // @Param projectName query string false "Filter by project name"
 // @Param riskLevel query string false "Filter by risk level (high, medium, low)"
 // @Param aiTool query string false "Filter by AI tool (coderabbit, cursor-bugbot)"
+// @Param excludeDrafts query bool false "Exclude reviews on draft PRs"
+// @Param prStatus query string false "Comma-separated PR statuses to keep (OPEN, MERGED, CLOSED)"
 // @Success 200 {object} map[string]any
 // @Router /plugins/aireview/reviews [get]
 func GetReviews(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
@@ -78,6 +84,7 @@ func GetReviews(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, erro
 	if aiTool := input.Query.Get("aiTool"); aiTool != "" {
 		clauses = append(clauses, dal.Where("ai_tool = ?", aiTool))
 	}
+	clauses = append(clauses, prStateFilters(input.Query)...)
 
 	// Get total count
 	countClauses := make([]dal.Clause, len(clauses))
@@ -141,30 +148,34 @@ func GetReview(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, error
 
 // GetReviewStats returns aggregated statistics for AI reviews
 // @Summary Get AI review statistics
-// @Description Get aggregated statistics for AI-generated code reviews
+// @Description Get aggregated statistics for AI-generated code reviews, including p50/p90 effort minutes,
+// @Description effort rating distribution, review latency (minutes from PR creation to the AI review)
+// @Description, suggestion adoption (applied suggestions, apply rate and p50/p90 minutes to apply)
+// @Description and the adoption of AI-generated PR descriptions
 // @Tags plugins/aireview
 // @Param repoId query string false "Filter by repository ID"
 // @Param projectName query string false "Filter by project name"
+// @Param excludeDrafts query bool false "Exclude reviews on draft PRs"
+// @Param prStatus query string false "Comma-separated PR statuses to keep (OPEN, MERGED, CLOSED)"
 // @Success 200 {object} map[string]any
 // @Router /plugins/aireview/stats [get]
 func GetReviewStats(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
 	// Build base clauses for filtering
 	var baseClauses []dal.Clause
 
+	// Always alias the reviews table so the latency query can join pull_requests
+	baseClauses = []dal.Clause{
+		dal.From("_tool_aireview_reviews r"),
+	}
 	if projectName := input.Query.Get("projectName"); projectName != "" {
-		baseClauses = []dal.Clause{
-			dal.From("_tool_aireview_reviews r"),
+		baseClauses = append(baseClauses,
 			dal.Join("JOIN project_mapping pm ON r.repo_id = pm.row_id"),
 			dal.Where("pm.project_name = ? AND pm.`table` = ?", projectName, "repos"),
-		}
-	} else {
-		baseClauses = []dal.Clause{
-			dal.From(&models.AiReview{}),
-		}
-		if repoId := input.Query.Get("repoId"); repoId != "" {
-			baseClauses = append(baseClauses, dal.Where("repo_id = ?", repoId))
-		}
+		)
+	} else if repoId := input.Query.Get("repoId"); repoId != "" {
+		baseClauses = append(baseClauses, dal.Where("r.repo_id = ?", repoId))
 	}
+	baseClauses = append(baseClauses, prStateFilters(input.Query)...)
 
 	// Get total count
 	total, err := db.Count(baseClauses...)
@@ -202,16 +213,264 @@ func GetReviewStats(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 		return nil, errors.Default.Wrap(err, "failed to get tool counts")
 	}
 
+	// Effort rating distribution (1-5, unrated reviews are left out)
+	type RatingCount struct {
+		EffortRating int   `gorm:"column:effort_rating" json:"effortRating"`
+		Count        int64 `gorm:"column:count" json:"count"`
+	}
+	var ratingCounts []RatingCount
+	ratingClauses := append(baseClauses,
+		dal.Select("effort_rating, COUNT(*) as count"),
+		dal.Where("effort_rating > 0"),
+		dal.Groupby("effort_rating"),
+		dal.Orderby("effort_rating"),
+	)
+	err = db.All(&ratingCounts, ratingClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get effort rating counts")
+	}
+
+	// Effort minutes histogram, percentiles are read from the cumulative counts
+	var effortBuckets []valueCount
+	effortClauses := append(baseClauses,
+		dal.Select("effort_minutes as value, COUNT(*) as count"),
+		dal.Where("effort_minutes > 0"),
+		dal.Groupby("effort_minutes"),
+		dal.Orderby("value"),
+	)
+	err = db.All(&effortBuckets, effortClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get effort minutes distribution")
+	}
+
+	// Review latency histogram: minutes from PR creation to the AI review
+	latencyExpr := latencyMinutesExpr(db.Dialect())
+	var latencyBuckets []valueCount
+	latencyClauses := append(baseClauses,
+		dal.Select(latencyExpr+" as value, COUNT(*) as count"),
+		dal.Join("JOIN pull_requests pr ON pr.id = r.pull_request_id"),
+		dal.Where("pr.created_date IS NOT NULL AND r.created_date >= pr.created_date"),
+		dal.Groupby(latencyExpr),
+		dal.Orderby("value"),
+	)
+	err = db.All(&latencyBuckets, latencyClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get review latency distribution")
+	}
+
+	adoption, err := suggestionAdoptionStats(baseClauses)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptions, err := prDescriptionAdoptionStats(input.Query)
+	if err != nil {
+		return nil, err
+	}
+
 	return &plugin.ApiResourceOutput{
 		Body: map[string]any{
-			"total":       total,
-			"byRiskLevel": riskCounts,
-			"byAiTool":    toolCounts,
+			"total":                total,
+			"byRiskLevel":          riskCounts,
+			"byAiTool":             toolCounts,
+			"byEffortRating":       ratingCounts,
+			"effortMinutes":        summarizeHistogram(effortBuckets),
+			"reviewLatencyMinutes": summarizeHistogram(latencyBuckets),
+			"suggestionAdoption":   adoption,
+			"prDescriptions":       descriptions,
 		},
 		Status: http.StatusOK,
 	}, nil
 }
 
+// SuggestionAdoption summarizes how many suggestion findings were applied by the PR authors
+type SuggestionAdoption struct {
+	Suggestions        int64              `json:"suggestions"`
+	Applied            int64              `json:"applied"`
+	ApplyRate          float64            `json:"applyRate"` // percentage of suggestions applied
+	ByMatchMethod      []MatchMethodCount `json:"byMatchMethod"`
+	TimeToApplyMinutes PercentileSummary  `json:"timeToApplyMinutes"`
+}
+
+// MatchMethodCount is the number of applied suggestions detected by one match method
+type MatchMethodCount struct {
+	Method string `gorm:"column:method" json:"method"`
+	Count  int64  `gorm:"column:count" json:"count"`
+}
+
+// suggestionAdoptionStats computes the adoption of suggestion findings of the
+// reviews selected by baseClauses. A suggestion counts as applied when it was
+// detected through the marker or the diff matching of matchSuggestionDiffs;
+// time to apply is measured from the finding to the matching commit, so only
+// diff-matched suggestions contribute to it.
+func suggestionAdoptionStats(baseClauses []dal.Clause) (*SuggestionAdoption, errors.Error) {
+	suggestionClauses := append(append([]dal.Clause{}, baseClauses...),
+		dal.Join("JOIN _tool_aireview_findings f ON f.ai_review_id = r.id"),
+		dal.Where("f.type = ?", models.FindingTypeSuggestion),
+	)
+	appliedClauses := append(append([]dal.Clause{}, suggestionClauses...),
+		dal.Where("(f.suggestion_applied = ? OR f.suggestion_diff_matched = ?)", true, true),
+	)
+
+	adoption := &SuggestionAdoption{ByMatchMethod: []MatchMethodCount{}}
+	var err errors.Error
+	adoption.Suggestions, err = db.Count(suggestionClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count suggestion findings")
+	}
+	adoption.Applied, err = db.Count(appliedClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count applied suggestions")
+	}
+	adoption.ApplyRate = percentage(adoption.Applied, adoption.Suggestions)
+
+	methodClauses := append(append([]dal.Clause{}, appliedClauses...),
+		dal.Select("f.suggestion_match_method as method, COUNT(*) as count"),
+		dal.Groupby("f.suggestion_match_method"),
+		dal.Orderby("count DESC"),
+	)
+	err = db.All(&adoption.ByMatchMethod, methodClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get applied suggestions by match method")
+	}
+
+	applyExpr := minutesBetweenExpr(db.Dialect(), "f.created_date", "c.authored_date")
+	var applyBuckets []valueCount
+	applyClauses := append(append([]dal.Clause{}, appliedClauses...),
+		dal.Select(applyExpr+" as value, COUNT(*) as count"),
+		dal.Join("JOIN commits c ON c.sha = f.matched_commit_sha"),
+		dal.Where("c.authored_date >= f.created_date"),
+		dal.Groupby(applyExpr),
+		dal.Orderby("value"),
+	)
+	err = db.All(&applyBuckets, applyClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get suggestion time to apply distribution")
+	}
+	adoption.TimeToApplyMinutes = summarizeHistogram(applyBuckets)
+
+	return adoption, nil
+}
+
+// PrDescriptionAdoption summarizes how many pull requests have a description generated by an AI tool
+type PrDescriptionAdoption struct {
+	PullRequests int64           `json:"pullRequests"`
+	AiGenerated  int64           `json:"aiGenerated"`
+	AdoptionRate float64         `json:"adoptionRate"` // percentage of pull requests with an AI description
+	ByAiTool     []AiToolPrCount `json:"byAiTool"`
+}
+
+// AiToolPrCount is the number of pull requests described by one AI tool
+type AiToolPrCount struct {
+	AiTool string `gorm:"column:ai_tool" json:"aiTool"`
+	Count  int64  `gorm:"column:count" json:"count"`
+}
+
+// prDescriptionAdoptionStats computes the adoption of AI-generated PR descriptions
+// from the rows of extractAiPrDescriptions, with the repo/project and PR state
+// filters of the stats endpoint
+func prDescriptionAdoptionStats(query url.Values) (*PrDescriptionAdoption, errors.Error) {
+	clauses := []dal.Clause{dal.From("_tool_aireview_pr_descriptions d")}
+	if projectName := query.Get("projectName"); projectName != "" {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON d.repo_id = pm.row_id"),
+			dal.Where("pm.project_name = ? AND pm.`table` = ?", projectName, "repos"),
+		)
+	} else if repoId := query.Get("repoId"); repoId != "" {
+		clauses = append(clauses, dal.Where("d.repo_id = ?", repoId))
+	}
+	clauses = append(clauses, prStateFilters(query)...)
+	aiClauses := append(append([]dal.Clause{}, clauses...), dal.Where("d.is_ai_generated = ?", true))
+
+	descriptions := &PrDescriptionAdoption{ByAiTool: []AiToolPrCount{}}
+	var err errors.Error
+	descriptions.PullRequests, err = db.Count(clauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count PR descriptions")
+	}
+	descriptions.AiGenerated, err = db.Count(aiClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count AI-generated PR descriptions")
+	}
+	descriptions.AdoptionRate = percentage(descriptions.AiGenerated, descriptions.PullRequests)
+
+	toolClauses := append(aiClauses,
+		dal.Select("d.ai_tool, COUNT(*) as count"),
+		dal.Groupby("d.ai_tool"),
+		dal.Orderby("count DESC"),
+	)
+	err = db.All(&descriptions.ByAiTool, toolClauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to get AI-generated PR descriptions by tool")
+	}
+	return descriptions, nil
+}
+
+// percentage returns part/total as a percentage rounded to one decimal, 0 when total is 0
+func percentage(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// valueCount is one bucket of a histogram grouped in SQL
+type valueCount struct {
+	Value int64 `gorm:"column:value"`
+	Count int64 `gorm:"column:count"`
+}
+
+// PercentileSummary holds nearest-rank percentiles of a histogram
+type PercentileSummary struct {
+	Count int64 `json:"count"`
+	P50   int64 `json:"p50"`
+	P90   int64 `json:"p90"`
+}
+
+// summarizeHistogram computes p50/p90 from buckets sorted by value
+func summarizeHistogram(buckets []valueCount) PercentileSummary {
+	summary := PercentileSummary{}
+	for _, b := range buckets {
+		summary.Count += b.Count
+	}
+	summary.P50 = histogramPercentile(buckets, summary.Count, 50)
+	summary.P90 = histogramPercentile(buckets, summary.Count, 90)
+	return summary
+}
+
+// histogramPercentile returns the nearest-rank percentile p (0-100) of sorted buckets
+func histogramPercentile(buckets []valueCount, total int64, p int64) int64 {
+	if total == 0 {
+		return 0
+	}
+	// rank = ceil(p/100 * total)
+	rank := (p*total + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	var cumulative int64
+	for _, b := range buckets {
+		cumulative += b.Count
+		if cumulative >= rank {
+			return b.Value
+		}
+	}
+	return buckets[len(buckets)-1].Value
+}
+
+// latencyMinutesExpr returns the SQL expression for minutes between PR creation and the review
+func latencyMinutesExpr(dialect string) string {
+	return minutesBetweenExpr(dialect, "pr.created_date", "r.created_date")
+}
+
+// minutesBetweenExpr returns the SQL expression for whole minutes from the from column to the to column
+func minutesBetweenExpr(dialect, from, to string) string {
+	if dialect == "postgres" {
+		return fmt.Sprintf("CAST(EXTRACT(EPOCH FROM (%s - %s)) / 60 AS BIGINT)", to, from)
+	}
+	return fmt.Sprintf("TIMESTAMPDIFF(MINUTE, %s, %s)", from, to)
+}
+
 // GetFindings returns a list of AI review findings
 // @Summary Get AI review findings
 // @Description Get a list of individual findings from AI reviews
@@ -280,3 +539,25 @@ func GetFindings(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, err
 		Status: http.StatusOK,
 	}, nil
 }
+
+// prStateFilters builds the PR state filters shared by the reviews and stats
+// endpoints, mirroring the excludeDraftPrs / excludeClosedUnmergedPrs scope
+// config options applied at extraction time.
+func prStateFilters(query url.Values) []dal.Clause {
+	var clauses []dal.Clause
+	if excludeDrafts, _ := strconv.ParseBool(query.Get("excludeDrafts")); excludeDrafts {
+		clauses = append(clauses, dal.Where("pr_is_draft = ?", false))
+	}
+	if prStatus := query.Get("prStatus"); prStatus != "" {
+		var statuses []string
+		for _, status := range strings.Split(prStatus, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				statuses = append(statuses, strings.ToUpper(status))
+			}
+		}
+		if len(statuses) > 0 {
+			clauses = append(clauses, dal.Where("pr_status IN ?", statuses))
+		}
+	}
+	return clauses
+}
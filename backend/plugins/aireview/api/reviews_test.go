@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeHistogram(t *testing.T) {
+	assert.Equal(t, PercentileSummary{}, summarizeHistogram(nil))
+
+	// 10 reviews: 5x5min, 3x10min, 1x30min, 1x120min
+	buckets := []valueCount{
+		{Value: 5, Count: 5},
+		{Value: 10, Count: 3},
+		{Value: 30, Count: 1},
+		{Value: 120, Count: 1},
+	}
+	assert.Equal(t, PercentileSummary{Count: 10, P50: 5, P90: 30}, summarizeHistogram(buckets))
+
+	// single bucket
+	assert.Equal(t, PercentileSummary{Count: 1, P50: 42, P90: 42}, summarizeHistogram([]valueCount{{Value: 42, Count: 1}}))
+}
+
+func TestLatencyMinutesExpr(t *testing.T) {
+	assert.Contains(t, latencyMinutesExpr("mysql"), "TIMESTAMPDIFF")
+	assert.Contains(t, latencyMinutesExpr("postgres"), "EXTRACT(EPOCH")
+}
+
+func TestMinutesBetweenExpr(t *testing.T) {
+	assert.Equal(t, "TIMESTAMPDIFF(MINUTE, f.created_date, c.authored_date)", minutesBetweenExpr("mysql", "f.created_date", "c.authored_date"))
+	assert.Equal(t, "CAST(EXTRACT(EPOCH FROM (c.authored_date - f.created_date)) / 60 AS BIGINT)", minutesBetweenExpr("postgres", "f.created_date", "c.authored_date"))
+}
+
+func TestPercentage(t *testing.T) {
+	assert.Equal(t, 0.0, percentage(0, 0))
+	assert.Equal(t, 0.0, percentage(0, 7))
+	assert.Equal(t, 33.3, percentage(1, 3))
+	assert.Equal(t, 66.7, percentage(2, 3))
+	assert.Equal(t, 100.0, percentage(4, 4))
+}
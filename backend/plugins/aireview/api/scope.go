@@ -0,0 +1,107 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package api
+
+import (
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+// PutScopes adds or updates repo scopes of a connection
+// @Summary add or update aireview repo scopes
+// @Description Add the repositories to analyze, by domain layer repo id, e.g. github:GithubRepo:1:384111310. The scopeConfigId of a repo picks its detection patterns
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scope body []models.AiReviewRepo true "json"
+// @Success 200 {object} []models.AiReviewRepo
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scopes [PUT]
+func PutScopes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeApi.PutMultiple(input)
+}
+
+// PatchScope updates a repo scope, e.g. its scope config
+// @Summary patch an aireview repo scope
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeId path string true "repo ID"
+// @Param scope body models.AiReviewRepo true "json"
+// @Success 200 {object} models.AiReviewRepo
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scopes/{scopeId} [PATCH]
+func PatchScope(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeApi.Patch(input)
+}
+
+// GetScopeList lists the repo scopes of a connection
+// @Summary get the aireview repo scopes of a connection
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param pageSize query int false "page size, default 50"
+// @Param page query int false "page number, default 1"
+// @Param blueprints query bool false "also return the blueprints using the scopes"
+// @Success 200 {object} []models.AiReviewRepo
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scopes [GET]
+func GetScopeList(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeApi.GetPage(input)
+}
+
+// GetScope gets a repo scope with its scope config
+// @Summary get an aireview repo scope
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeId path string true "repo ID"
+// @Success 200 {object} models.AiReviewRepo
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scopes/{scopeId} [GET]
+func GetScope(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeApi.GetScopeDetail(input)
+}
+
+// GetScopeLatestSyncState gets the latest sync state of a repo scope
+// @Summary get the latest sync state of an aireview repo scope
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeId path string true "repo ID"
+// @Success 200 {object} []models.LatestSyncState
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scopes/{scopeId}/latest-sync-state [GET]
+func GetScopeLatestSyncState(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeApi.GetScopeLatestSyncState(input)
+}
+
+// DeleteScope removes a repo scope
+// @Summary delete an aireview repo scope
+// @Description Remove a repository from the analyzed ones. Its reviews and metrics are kept, purge them with DELETE /plugins/aireview/repos/{repoId}/data
+// @Tags plugins/aireview
+// @Param connectionId path int true "connection ID"
+// @Param scopeId path string true "repo ID"
+// @Param delete_data_only query bool false "only delete the scope data, not the scope itself"
+// @Success 200
+// @Failure 400 {object} shared.ApiBody "Bad Request"
+// @Failure 409 {object} srvhelper.DsRefs "References exist to this scope"
+// @Failure 500 {object} shared.ApiBody "Internal Error"
+// @Router /plugins/aireview/connections/{connectionId}/scopes/{scopeId} [DELETE]
+func DeleteScope(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return dsHelper.ScopeApi.Delete(input)
+}
@@ -18,14 +18,18 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
 )
 
 // GetScopeConfigs returns a list of scope configurations
@@ -248,3 +252,182 @@ func GetDefaultScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceO
 		Status: http.StatusOK,
 	}, nil
 }
+
+// scopeConfigExportVersion is bumped when the export document format changes incompatibly
+const scopeConfigExportVersion = 1
+
+// ScopeConfigExport is the portable form of a scope config. Instance-specific
+// fields (id, timestamps, connectionId) are left out so the document can be
+// imported into another DevLake instance or applied to another scope.
+type ScopeConfigExport struct {
+	Plugin      string                      `json:"plugin"`
+	Version     int                         `json:"version"`
+	ExportedAt  time.Time                   `json:"exportedAt"`
+	ScopeConfig *models.AiReviewScopeConfig `json:"scopeConfig"`
+}
+
+// ExportScopeConfig exports a scope configuration as a portable JSON document
+// @Summary Export scope configuration
+// @Description Export an AI Review scope configuration (all patterns and toggles) for import elsewhere
+// @Tags plugins/aireview
+// @Param id path int true "Scope Config ID"
+// @Success 200 {object} ScopeConfigExport
+// @Router /plugins/aireview/scope-configs/{id}/export [get]
+func ExportScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	configId, err := strconv.ParseUint(input.Params["id"], 10, 64)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid scope config id")
+	}
+
+	var config models.AiReviewScopeConfig
+	dbErr := db.First(&config, dal.Where("id = ?", configId))
+	if dbErr != nil {
+		if db.IsErrorNotFound(dbErr) {
+			return nil, errors.NotFound.Wrap(dbErr, "scope config not found")
+		}
+		return nil, errors.Default.Wrap(dbErr, "failed to get scope config")
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body:   newScopeConfigExport(&config),
+		Status: http.StatusOK,
+	}, nil
+}
+
+// ImportScopeConfig imports a scope configuration exported by ExportScopeConfig
+// @Summary Import scope configuration
+// @Description Import an exported AI Review scope configuration. With targetId the document overwrites
+// @Description that scope config (keeping its name); otherwise it is upserted by name like a create.
+// @Tags plugins/aireview
+// @Accept json
+// @Param targetId query int false "Existing scope config ID to overwrite"
+// @Param name query string false "Name for the imported scope config (defaults to the exported name)"
+// @Param body body ScopeConfigExport true "Exported scope configuration"
+// @Success 200 {object} models.AiReviewScopeConfig
+// @Success 201 {object} models.AiReviewScopeConfig
+// @Router /plugins/aireview/scope-configs/import [post]
+func ImportScopeConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	config, err := scopeConfigFromImport(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	if name := input.Query.Get("name"); name != "" {
+		config.Name = name
+	}
+
+	// Overwrite an existing scope config, keeping its identity
+	if targetId := input.Query.Get("targetId"); targetId != "" {
+		configId, parseErr := strconv.ParseUint(targetId, 10, 64)
+		if parseErr != nil {
+			return nil, errors.BadInput.Wrap(parseErr, "invalid targetId")
+		}
+		var existing models.AiReviewScopeConfig
+		dbErr := db.First(&existing, dal.Where("id = ?", configId))
+		if dbErr != nil {
+			if db.IsErrorNotFound(dbErr) {
+				return nil, errors.NotFound.Wrap(dbErr, "target scope config not found")
+			}
+			return nil, errors.Default.Wrap(dbErr, "failed to get target scope config")
+		}
+		config.Model = existing.Model
+		config.ConnectionId = existing.ConnectionId
+		if input.Query.Get("name") == "" {
+			config.Name = existing.Name
+		}
+		dbErr = db.Update(config)
+		if dbErr != nil {
+			return nil, errors.Default.Wrap(dbErr, "failed to update scope config")
+		}
+		return &plugin.ApiResourceOutput{
+			Body:   config,
+			Status: http.StatusOK,
+		}, nil
+	}
+
+	// Same upsert-by-name semantics as CreateScopeConfig
+	var existing models.AiReviewScopeConfig
+	dbErr := db.First(&existing, dal.Where("name = ?", config.Name))
+	if dbErr == nil {
+		config.Model = existing.Model
+		config.ConnectionId = existing.ConnectionId
+		dbErr = db.Update(config)
+		if dbErr != nil {
+			return nil, errors.Default.Wrap(dbErr, "failed to update scope config")
+		}
+		return &plugin.ApiResourceOutput{
+			Body:   config,
+			Status: http.StatusOK,
+		}, nil
+	}
+	if !db.IsErrorNotFound(dbErr) {
+		return nil, errors.Default.Wrap(dbErr, "failed to look up scope config by name")
+	}
+
+	dbErr = db.Create(config)
+	if dbErr != nil {
+		return nil, errors.Default.Wrap(dbErr, "failed to create scope config")
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body:   config,
+		Status: http.StatusCreated,
+	}, nil
+}
+
+// newScopeConfigExport wraps a copy of the config with its instance-specific fields cleared
+func newScopeConfigExport(config *models.AiReviewScopeConfig) *ScopeConfigExport {
+	exported := *config
+	exported.Model = common.Model{}
+	exported.ConnectionId = 0
+	return &ScopeConfigExport{
+		Plugin:      "aireview",
+		Version:     scopeConfigExportVersion,
+		ExportedAt:  time.Now().UTC(),
+		ScopeConfig: &exported,
+	}
+}
+
+// scopeConfigFromImport decodes an export document (or a bare scope config) on top of the
+// defaults and checks that every pattern compiles, so a bad import fails here instead of
+// in the next pipeline run
+func scopeConfigFromImport(body map[string]interface{}) (*models.AiReviewScopeConfig, errors.Error) {
+	if body == nil {
+		return nil, errors.BadInput.New("missing scope config document")
+	}
+
+	raw := body
+	if nested, ok := body["scopeConfig"]; ok {
+		if p, ok := body["plugin"].(string); ok && p != "aireview" {
+			return nil, errors.BadInput.New(fmt.Sprintf("scope config was exported by plugin %q, not aireview", p))
+		}
+		if v, ok := body["version"].(float64); ok && int(v) > scopeConfigExportVersion {
+			return nil, errors.BadInput.New(fmt.Sprintf("unsupported export version %d", int(v)))
+		}
+		raw, ok = nested.(map[string]interface{})
+		if !ok {
+			return nil, errors.BadInput.New("scopeConfig must be an object")
+		}
+	}
+
+	// Instance-specific fields never travel with the document
+	fields := make(map[string]interface{}, len(raw))
+	for k, v := range raw {
+		switch k {
+		case "id", "createdAt", "updatedAt", "connectionId":
+			continue
+		}
+		fields[k] = v
+	}
+
+	config := models.GetDefaultScopeConfig()
+	if err := api.DecodeMapStruct(fields, config, true); err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to decode scope config")
+	}
+
+	taskData := &tasks.AiReviewTaskData{Options: &tasks.AiReviewOptions{ScopeConfig: config}}
+	if err := tasks.CompilePatterns(taskData); err != nil {
+		return nil, errors.BadInput.Wrap(err, "scope config has invalid patterns")
+	}
+
+	return config, nil
+}
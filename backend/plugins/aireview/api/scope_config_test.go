@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func toMap(t *testing.T, v any) map[string]interface{} {
+	t.Helper()
+	b, err := json.Marshal(v)
+	assert.NoError(t, err)
+	var m map[string]interface{}
+	assert.NoError(t, json.Unmarshal(b, &m))
+	return m
+}
+
+func TestScopeConfigExportImportRoundTrip(t *testing.T) {
+	config := models.GetDefaultScopeConfig()
+	config.ID = 12
+	config.ConnectionId = 3
+	config.Name = "tuned"
+	config.RiskHighPattern = `(?i)(critical|cve)`
+	config.ExcludeDraftPrs = true
+
+	export := newScopeConfigExport(config)
+	assert.Equal(t, "aireview", export.Plugin)
+	assert.Zero(t, export.ScopeConfig.ID)
+	assert.Zero(t, export.ScopeConfig.ConnectionId)
+	// the source config is left untouched
+	assert.Equal(t, uint64(12), config.ID)
+
+	imported, err := scopeConfigFromImport(toMap(t, export))
+	assert.Nil(t, err)
+	assert.Zero(t, imported.ID)
+	assert.Equal(t, "tuned", imported.Name)
+	assert.Equal(t, `(?i)(critical|cve)`, imported.RiskHighPattern)
+	assert.True(t, imported.ExcludeDraftPrs)
+	assert.Equal(t, config.CodeRabbitPattern, imported.CodeRabbitPattern)
+}
+
+func TestScopeConfigFromImport(t *testing.T) {
+	t.Run("bare scope config on top of defaults", func(t *testing.T) {
+		imported, err := scopeConfigFromImport(map[string]interface{}{"name": "bare", "id": 99, "observationWindowDays": 30})
+		assert.Nil(t, err)
+		assert.Zero(t, imported.ID)
+		assert.Equal(t, 30, imported.ObservationWindowDays)
+		assert.Equal(t, models.GetDefaultScopeConfig().RiskLowPattern, imported.RiskLowPattern)
+	})
+
+	t.Run("other plugin rejected", func(t *testing.T) {
+		_, err := scopeConfigFromImport(map[string]interface{}{"plugin": "github", "scopeConfig": map[string]interface{}{}})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("newer version rejected", func(t *testing.T) {
+		_, err := scopeConfigFromImport(map[string]interface{}{"plugin": "aireview", "version": float64(2), "scopeConfig": map[string]interface{}{}})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("invalid pattern rejected", func(t *testing.T) {
+		_, err := scopeConfigFromImport(map[string]interface{}{"riskHighPattern": "(unclosed"})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("missing body", func(t *testing.T) {
+		_, err := scopeConfigFromImport(nil)
+		assert.NotNil(t, err)
+	})
+}
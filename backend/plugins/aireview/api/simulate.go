@@ -0,0 +1,198 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+const (
+	defaultSimulationDays = 90
+	// defaultReviewMinutes is the review time charged to a blocked PR whose AI review gave no effort estimate
+	defaultReviewMinutes = 30
+)
+
+// defaultSimulationThresholds are the risk scores simulated when no threshold is requested
+var defaultSimulationThresholds = []int{20, 50, 70, 80, 90}
+
+// simulatedPr is one merged PR with its highest predicted risk and its observed outcome
+type simulatedPr struct {
+	PullRequestId string `gorm:"column:pull_request_id"`
+	RiskScore     int    `gorm:"column:risk_score"`
+	Observed      bool   `gorm:"column:observed"`
+	HadFailure    bool   `gorm:"column:had_failure"`
+	EffortMinutes int    `gorm:"column:effort_minutes"`
+}
+
+// PolicySimulation holds what a "block merges when risk >= Threshold" policy would have done
+type PolicySimulation struct {
+	Threshold                  int     `json:"threshold"`
+	Merges                     int     `json:"merges"`
+	BlockedMerges              int     `json:"blockedMerges"`
+	BlockedPct                 float64 `json:"blockedPct"`
+	Failures                   int     `json:"failures"` // merged PRs with CI outcome data that failed afterwards
+	FailuresPrevented          int     `json:"failuresPrevented"`
+	PreventedPct               float64 `json:"preventedPct"`
+	UnnecessaryBlocks          int     `json:"unnecessaryBlocks"` // blocked PRs with CI outcome data that did not fail
+	BlockPrecision             float64 `json:"blockPrecision"`    // percentage of observed blocked PRs that failed
+	AddedReviewMinutes         int     `json:"addedReviewMinutes"`
+	AvgAddedReviewMinutes      float64 `json:"avgAddedReviewMinutes"`
+	AddedReviewMinutesPerMerge float64 `json:"addedReviewMinutesPerMerge"`
+}
+
+// SimulateGatingPolicy replays historical predictions against merge-blocking thresholds
+// @Summary Simulate a merge-blocking policy
+// @Description Replay the failure predictions of merged PRs over the last N days against "block merges when
+// @Description risk >= X" policies. For each threshold, reports how many merges would have been blocked, how many
+// @Description observed post-merge failures would have been prevented and the review time the blocks would have
+// @Description added (the AI effort estimate of each blocked PR, 30 minutes when there is none).
+// @Tags plugins/aireview
+// @Param repoId query string false "Filter by repository ID"
+// @Param projectName query string false "Filter by project name"
+// @Param aiTool query string false "Only use the predictions of this AI tool"
+// @Param thresholds query string false "Comma-separated risk scores (0-100) to simulate" default(20,50,70,80,90)
+// @Param days query int false "Look-back window in days" default(90)
+// @Success 200 {object} map[string]any
+// @Failure 400 {string} errcode.Error "Bad Request"
+// @Router /plugins/aireview/simulate [get]
+func SimulateGatingPolicy(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	thresholds, err := parseThresholds(input.Query.Get("thresholds"))
+	if err != nil {
+		return nil, err
+	}
+	days := defaultSimulationDays
+	if s := input.Query.Get("days"); s != "" {
+		d, convErr := strconv.Atoi(s)
+		if convErr != nil || d <= 0 {
+			return nil, errors.BadInput.New(fmt.Sprintf("days must be a positive integer, got %q", s))
+		}
+		days = d
+	}
+
+	prs, err := loadSimulatedPrs(input.Query, time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	return &plugin.ApiResourceOutput{
+		Body: map[string]any{
+			"days":     days,
+			"policies": simulateGatingPolicy(prs, thresholds),
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// parseThresholds parses a comma-separated list of risk scores, sorted and deduplicated
+func parseThresholds(s string) ([]int, errors.Error) {
+	if strings.TrimSpace(s) == "" {
+		return defaultSimulationThresholds, nil
+	}
+	seen := make(map[int]bool)
+	var thresholds []int
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		t, convErr := strconv.Atoi(part)
+		if convErr != nil || t < 0 || t > 100 {
+			return nil, errors.BadInput.New(fmt.Sprintf("thresholds must be risk scores between 0 and 100, got %q", part))
+		}
+		if !seen[t] {
+			seen[t] = true
+			thresholds = append(thresholds, t)
+		}
+	}
+	sort.Ints(thresholds)
+	return thresholds, nil
+}
+
+// loadSimulatedPrs loads the merged PRs with a failure prediction flagged since the given time
+func loadSimulatedPrs(query url.Values, since time.Time) ([]simulatedPr, errors.Error) {
+	clauses := []dal.Clause{
+		dal.Select(fmt.Sprintf("p.pull_request_id, MAX(p.risk_score) AS risk_score, "+
+			"MAX(CASE WHEN p.prediction_outcome <> '%s' THEN 1 ELSE 0 END) AS observed, "+
+			"MAX(CASE WHEN p.had_ci_failure OR p.had_bug_reported THEN 1 ELSE 0 END) AS had_failure, "+
+			"COALESCE(MAX(e.effort_minutes), 0) AS effort_minutes", models.PredictionNoCi)),
+		dal.From("_tool_aireview_failure_predictions p"),
+		dal.Join("JOIN pull_requests pr ON pr.id = p.pull_request_id"),
+		dal.Join("LEFT JOIN (SELECT pull_request_id, MAX(effort_minutes) AS effort_minutes " +
+			"FROM _tool_aireview_reviews GROUP BY pull_request_id) e ON e.pull_request_id = p.pull_request_id"),
+		dal.Where("pr.merged_date IS NOT NULL AND p.flagged_at >= ?", since),
+	}
+	clauses = append(clauses, repoScopeClauses(query, "p.repo_id")...)
+	if aiTool := query.Get("aiTool"); aiTool != "" {
+		clauses = append(clauses, dal.Where("p.ai_tool = ?", aiTool))
+	}
+	clauses = append(clauses, dal.Groupby("p.pull_request_id"))
+
+	var prs []simulatedPr
+	if err := db.All(&prs, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load predictions of merged PRs")
+	}
+	return prs, nil
+}
+
+// simulateGatingPolicy computes, for each threshold, what blocking the PRs at or above it would have done
+func simulateGatingPolicy(prs []simulatedPr, thresholds []int) []PolicySimulation {
+	simulations := make([]PolicySimulation, 0, len(thresholds))
+	for _, threshold := range thresholds {
+		s := PolicySimulation{Threshold: threshold, Merges: len(prs)}
+		for _, pr := range prs {
+			failed := pr.Observed && pr.HadFailure
+			if failed {
+				s.Failures++
+			}
+			if pr.RiskScore < threshold {
+				continue
+			}
+			s.BlockedMerges++
+			if failed {
+				s.FailuresPrevented++
+			} else if pr.Observed {
+				s.UnnecessaryBlocks++
+			}
+			minutes := pr.EffortMinutes
+			if minutes <= 0 {
+				minutes = defaultReviewMinutes
+			}
+			s.AddedReviewMinutes += minutes
+		}
+		s.BlockedPct = percentage(int64(s.BlockedMerges), int64(s.Merges))
+		s.PreventedPct = percentage(int64(s.FailuresPrevented), int64(s.Failures))
+		s.BlockPrecision = percentage(int64(s.FailuresPrevented), int64(s.FailuresPrevented+s.UnnecessaryBlocks))
+		if s.BlockedMerges > 0 {
+			s.AvgAddedReviewMinutes = math.Round(float64(s.AddedReviewMinutes)*10/float64(s.BlockedMerges)) / 10
+		}
+		if s.Merges > 0 {
+			s.AddedReviewMinutesPerMerge = math.Round(float64(s.AddedReviewMinutes)*10/float64(s.Merges)) / 10
+		}
+		simulations = append(simulations, s)
+	}
+	return simulations
+}
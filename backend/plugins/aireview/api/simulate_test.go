@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseThresholds(t *testing.T) {
+	thresholds, err := parseThresholds("")
+	assert.Nil(t, err)
+	assert.Equal(t, defaultSimulationThresholds, thresholds)
+
+	thresholds, err = parseThresholds("90, 50,70,50")
+	assert.Nil(t, err)
+	assert.Equal(t, []int{50, 70, 90}, thresholds)
+
+	for _, s := range []string{"abc", "50,", "-1", "101"} {
+		_, err = parseThresholds(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestSimulateGatingPolicy(t *testing.T) {
+	prs := []simulatedPr{
+		{PullRequestId: "pr1", RiskScore: 95, Observed: true, HadFailure: true, EffortMinutes: 60},
+		{PullRequestId: "pr2", RiskScore: 75, Observed: true, HadFailure: false, EffortMinutes: 20},
+		{PullRequestId: "pr3", RiskScore: 40, Observed: true, HadFailure: true},
+		{PullRequestId: "pr4", RiskScore: 80, Observed: false},
+		{PullRequestId: "pr5", RiskScore: 10, Observed: true, HadFailure: false},
+	}
+
+	simulations := simulateGatingPolicy(prs, []int{0, 70, 90, 100})
+	assert.Len(t, simulations, 4)
+
+	all := simulations[0]
+	assert.Equal(t, 5, all.Merges)
+	assert.Equal(t, 5, all.BlockedMerges)
+	assert.Equal(t, 2, all.Failures)
+	assert.Equal(t, 2, all.FailuresPrevented)
+	assert.Equal(t, 100.0, all.PreventedPct)
+	assert.Equal(t, 2, all.UnnecessaryBlocks)
+	assert.Equal(t, 50.0, all.BlockPrecision)
+	assert.Equal(t, 60+20+30+30+30, all.AddedReviewMinutes)
+
+	seventy := simulations[1]
+	assert.Equal(t, 3, seventy.BlockedMerges)
+	assert.Equal(t, 60.0, seventy.BlockedPct)
+	assert.Equal(t, 1, seventy.FailuresPrevented)
+	assert.Equal(t, 50.0, seventy.PreventedPct)
+	assert.Equal(t, 1, seventy.UnnecessaryBlocks)
+	assert.Equal(t, 50.0, seventy.BlockPrecision)
+	assert.Equal(t, 110, seventy.AddedReviewMinutes)
+	assert.Equal(t, 36.7, seventy.AvgAddedReviewMinutes)
+	assert.Equal(t, 22.0, seventy.AddedReviewMinutesPerMerge)
+
+	ninety := simulations[2]
+	assert.Equal(t, 1, ninety.BlockedMerges)
+	assert.Equal(t, 100.0, ninety.BlockPrecision)
+
+	none := simulations[3]
+	assert.Equal(t, 0, none.BlockedMerges)
+	assert.Equal(t, 0, none.AddedReviewMinutes)
+	assert.Equal(t, 0.0, none.AvgAddedReviewMinutes)
+}
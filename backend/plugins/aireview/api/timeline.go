@@ -0,0 +1,315 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// Timeline event types, in the order events at the same time are listed
+const (
+	TimelineEventPrCreated        = "pr_created"
+	TimelineEventAiReview         = "ai_review"
+	TimelineEventFinding          = "finding"
+	TimelineEventHumanComment     = "human_comment"
+	TimelineEventFindingResolved  = "finding_resolved"
+	TimelineEventPrMerged         = "pr_merged"
+	TimelineEventPrClosed         = "pr_closed"
+	TimelineEventCiFailure        = "ci_failure"
+	TimelineEventBugReported      = "bug_reported"
+	TimelineEventRollback         = "rollback"
+	TimelineEventObservationEnded = "observation_ended"
+)
+
+var timelineEventOrder = map[string]int{
+	TimelineEventPrCreated:        0,
+	TimelineEventAiReview:         1,
+	TimelineEventFinding:          2,
+	TimelineEventHumanComment:     3,
+	TimelineEventFindingResolved:  4,
+	TimelineEventPrMerged:         5,
+	TimelineEventPrClosed:         6,
+	TimelineEventCiFailure:        7,
+	TimelineEventBugReported:      8,
+	TimelineEventRollback:         9,
+	TimelineEventObservationEnded: 10,
+}
+
+// TimelineEvent is one step in the story of a pull request
+type TimelineEvent struct {
+	Time    time.Time      `json:"time"`
+	Type    string         `json:"type"`
+	AiTool  string         `json:"aiTool,omitempty"`
+	Actor   string         `json:"actor,omitempty"`
+	RefId   string         `json:"refId,omitempty"` // id of the review, finding, comment or issue behind the event
+	Summary string         `json:"summary,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// PullRequestTimeline is the response of GetPullRequestTimeline
+type PullRequestTimeline struct {
+	PullRequestId string               `json:"pullRequestId"`
+	Title         string               `json:"title"`
+	Url           string               `json:"url"`
+	Status        string               `json:"status"`
+	Author        string               `json:"author"`
+	Predictions   []timelinePrediction `json:"predictions"`
+	Events        []TimelineEvent      `json:"events"`
+}
+
+// timelinePrediction is the outcome of the failure prediction of one AI tool for the PR
+type timelinePrediction struct {
+	AiTool            string `json:"aiTool"`
+	WasFlaggedRisky   bool   `json:"wasFlaggedRisky"`
+	RiskScore         int    `json:"riskScore"`
+	PredictionOutcome string `json:"predictionOutcome"`
+	CiFailureSource   string `json:"ciFailureSource"`
+}
+
+// timelineComment is a PR comment with the user name of its author
+type timelineComment struct {
+	Id          string    `gorm:"column:id"`
+	Body        string    `gorm:"column:body"`
+	CreatedDate time.Time `gorm:"column:created_date"`
+	UserName    string    `gorm:"column:user_name"`
+}
+
+// GetPullRequestTimeline returns the ordered events of one pull request
+// @Summary Get the AI review timeline of a pull request
+// @Description List in time order what happened to a pull request: creation, AI reviews, findings, human comments,
+// @Description resolved findings, merge or close, and the post-merge CI failures, bugs and rollbacks used by the
+// @Description failure predictions, together with the prediction outcome of each AI tool
+// @Tags plugins/aireview
+// @Param prId path string true "Pull request ID (domain pull_requests.id)"
+// @Success 200 {object} PullRequestTimeline
+// @Failure 404 {string} errcode.Error "Not Found"
+// @Router /plugins/aireview/pull-requests/{prId}/timeline [get]
+func GetPullRequestTimeline(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	prId := input.Params["prId"]
+	if prId == "" {
+		return nil, errors.BadInput.New("prId is required")
+	}
+
+	var pr code.PullRequest
+	err := db.First(&pr, dal.Where("id = ?", prId))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.Wrap(err, "pull request not found")
+		}
+		return nil, errors.Default.Wrap(err, "failed to get pull request")
+	}
+
+	var reviews []models.AiReview
+	err = db.All(&reviews, dal.Where("pull_request_id = ?", prId), dal.Orderby("created_date"))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query reviews")
+	}
+	var findings []models.AiReviewFinding
+	err = db.All(&findings, dal.Where("pull_request_id = ?", prId), dal.Orderby("created_date"))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query findings")
+	}
+	var predictions []models.AiFailurePrediction
+	err = db.All(&predictions, dal.Where("pull_request_id = ?", prId), dal.Orderby("ai_tool"))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query failure predictions")
+	}
+	var comments []timelineComment
+	err = db.All(&comments,
+		dal.Select("c.id, c.body, c.created_date, a.user_name"),
+		dal.From("pull_request_comments c"),
+		dal.Join("LEFT JOIN accounts a ON a.id = c.account_id"),
+		dal.Where("c.pull_request_id = ?", prId),
+		dal.Orderby("c.created_date"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query pull request comments")
+	}
+
+	timeline := &PullRequestTimeline{
+		PullRequestId: pr.Id,
+		Title:         pr.Title,
+		Url:           pr.Url,
+		Status:        pr.Status,
+		Author:        pr.AuthorName,
+		Predictions:   make([]timelinePrediction, 0, len(predictions)),
+		Events:        buildTimeline(&pr, reviews, findings, humanComments(reviews, comments), predictions),
+	}
+	for _, p := range predictions {
+		timeline.Predictions = append(timeline.Predictions, timelinePrediction{
+			AiTool:            p.AiTool,
+			WasFlaggedRisky:   p.WasFlaggedRisky,
+			RiskScore:         p.RiskScore,
+			PredictionOutcome: p.PredictionOutcome,
+			CiFailureSource:   p.CiFailureSource,
+		})
+	}
+
+	return &plugin.ApiResourceOutput{Body: timeline, Status: http.StatusOK}, nil
+}
+
+// humanComments drops the comments an AI review was extracted from and the comments of bots
+func humanComments(reviews []models.AiReview, comments []timelineComment) []timelineComment {
+	aiComments := make(map[string]bool, len(reviews))
+	var aiUsers []string
+	for _, r := range reviews {
+		aiComments[r.ReviewId] = true
+		if r.AiToolUser != "" {
+			aiUsers = append(aiUsers, r.AiToolUser)
+		}
+	}
+	isAiUser := func(userName string) bool {
+		for _, u := range aiUsers {
+			if strings.EqualFold(u, userName) {
+				return true
+			}
+		}
+		return false
+	}
+	var human []timelineComment
+	for _, c := range comments {
+		if aiComments[c.Id] || isAiUser(c.UserName) || strings.HasSuffix(c.UserName, "[bot]") {
+			continue
+		}
+		human = append(human, c)
+	}
+	return human
+}
+
+// buildTimeline turns the records of a PR into events sorted by time; events at the
+// same time keep the order of the PR lifecycle (see timelineEventOrder)
+func buildTimeline(pr *code.PullRequest, reviews []models.AiReview, findings []models.AiReviewFinding, comments []timelineComment, predictions []models.AiFailurePrediction) []TimelineEvent {
+	events := []TimelineEvent{}
+	add := func(at *time.Time, event TimelineEvent) {
+		if at == nil || at.IsZero() {
+			return
+		}
+		event.Time = *at
+		events = append(events, event)
+	}
+
+	add(&pr.CreatedDate, TimelineEvent{Type: TimelineEventPrCreated, Actor: pr.AuthorName, RefId: pr.Id, Summary: pr.Title})
+	for i := range reviews {
+		r := &reviews[i]
+		add(&r.CreatedDate, TimelineEvent{
+			Type:    TimelineEventAiReview,
+			AiTool:  r.AiTool,
+			Actor:   r.AiToolUser,
+			RefId:   r.Id,
+			Summary: fmt.Sprintf("%s risk (%d), %d issues, %d suggestions", r.RiskLevel, r.RiskScore, r.IssuesFound, r.SuggestionsCount),
+			Details: map[string]any{"riskLevel": r.RiskLevel, "riskScore": r.RiskScore, "reviewState": r.ReviewState, "sourceUrl": r.SourceUrl},
+		})
+	}
+	for i := range findings {
+		f := &findings[i]
+		add(&f.CreatedDate, TimelineEvent{
+			Type:    TimelineEventFinding,
+			AiTool:  f.AiTool,
+			RefId:   f.Id,
+			Summary: f.Title,
+			Details: map[string]any{"category": f.Category, "severity": f.Severity, "type": f.Type, "filePath": f.FilePath, "lineStart": f.LineStart},
+		})
+		add(f.ResolvedAt, TimelineEvent{
+			Type:    TimelineEventFindingResolved,
+			AiTool:  f.AiTool,
+			Actor:   f.ResolvedBy,
+			RefId:   f.Id,
+			Summary: f.Title,
+			Details: map[string]any{"resolution": f.Resolution, "suggestionApplied": f.SuggestionApplied || f.SuggestionDiffMatched},
+		})
+	}
+	for i := range comments {
+		c := &comments[i]
+		add(&c.CreatedDate, TimelineEvent{Type: TimelineEventHumanComment, Actor: c.UserName, RefId: c.Id, Summary: truncateSummary(c.Body)})
+	}
+	if pr.MergedDate != nil {
+		add(pr.MergedDate, TimelineEvent{Type: TimelineEventPrMerged, Actor: pr.MergedByName, RefId: pr.MergeCommitSha})
+	} else {
+		add(pr.ClosedDate, TimelineEvent{Type: TimelineEventPrClosed, RefId: pr.Id})
+	}
+	for i := range predictions {
+		p := &predictions[i]
+		if p.HadCiFailure {
+			add(p.CiFailureAt, TimelineEvent{Type: TimelineEventCiFailure, AiTool: p.AiTool, Details: map[string]any{"ciFailureSource": p.CiFailureSource}})
+		}
+		if p.HadBugReported {
+			add(p.BugReportedAt, TimelineEvent{Type: TimelineEventBugReported, AiTool: p.AiTool, RefId: p.BugIssueId})
+		}
+		if p.HadRollback {
+			add(p.RollbackAt, TimelineEvent{Type: TimelineEventRollback, AiTool: p.AiTool})
+		}
+		if p.PrMergedAt != nil {
+			add(&p.ObservationEndDate, TimelineEvent{
+				Type:    TimelineEventObservationEnded,
+				AiTool:  p.AiTool,
+				Summary: p.PredictionOutcome,
+				Details: map[string]any{"wasFlaggedRisky": p.WasFlaggedRisky, "riskScore": p.RiskScore, "observationWindowDays": p.ObservationWindowDays},
+			})
+		}
+	}
+	events = dedupePostMergeEvents(events)
+
+	sort.SliceStable(events, func(i, j int) bool {
+		if !events[i].Time.Equal(events[j].Time) {
+			return events[i].Time.Before(events[j].Time)
+		}
+		return timelineEventOrder[events[i].Type] < timelineEventOrder[events[j].Type]
+	})
+	return events
+}
+
+// dedupePostMergeEvents keeps one CI failure, bug or rollback event when the predictions
+// of several AI tools observed the same one; AiTool is cleared as it applies to all of them
+func dedupePostMergeEvents(events []TimelineEvent) []TimelineEvent {
+	seen := make(map[string]int)
+	result := events[:0]
+	for _, e := range events {
+		if e.Type == TimelineEventCiFailure || e.Type == TimelineEventBugReported || e.Type == TimelineEventRollback {
+			key := e.Type + "|" + e.Time.String() + "|" + e.RefId
+			if idx, ok := seen[key]; ok {
+				result[idx].AiTool = ""
+				continue
+			}
+			seen[key] = len(result)
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// truncateSummary shortens a comment body to its first line, at most 200 characters
+func truncateSummary(body string) string {
+	summary := strings.TrimSpace(body)
+	if idx := strings.IndexByte(summary, '\n'); idx >= 0 {
+		summary = strings.TrimSpace(summary[:idx])
+	}
+	if runes := []rune(summary); len(runes) > 200 {
+		summary = string(runes[:200]) + "…"
+	}
+	return summary
+}
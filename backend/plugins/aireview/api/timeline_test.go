@@ -0,0 +1,97 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildTimeline(t *testing.T) {
+	at := func(hour int) time.Time { return time.Date(2026, 4, 1, hour, 0, 0, 0, time.UTC) }
+	ptr := func(v time.Time) *time.Time { return &v }
+
+	pr := &code.PullRequest{
+		DomainEntity: domainlayer.DomainEntity{Id: "github:GithubPullRequest:1:42"},
+		Title:        "Add retries",
+		AuthorName:   "alice",
+		CreatedDate:  at(1),
+		MergedDate:   ptr(at(5)),
+		MergedByName: "bob",
+	}
+	reviews := []models.AiReview{
+		{Id: "r1", AiTool: models.AiToolCodeRabbit, AiToolUser: "coderabbitai[bot]", ReviewId: "c1", CreatedDate: at(2), RiskLevel: models.RiskLevelHigh, RiskScore: 80},
+	}
+	findings := []models.AiReviewFinding{
+		{Id: "f1", AiTool: models.AiToolCodeRabbit, Title: "Missing nil check", CreatedDate: at(2), ResolvedAt: ptr(at(4)), ResolvedBy: "alice", Resolution: models.ResolutionFixed},
+	}
+	comments := humanComments(reviews, []timelineComment{
+		{Id: "c1", Body: "review", CreatedDate: at(2), UserName: "coderabbitai[bot]"},
+		{Id: "c2", Body: "Good catch, fixed.\nThanks", CreatedDate: at(3), UserName: "alice"},
+		{Id: "c3", Body: "/retest", CreatedDate: at(3), UserName: "openshift-ci[bot]"},
+	})
+	predictions := []models.AiFailurePrediction{
+		{AiTool: models.AiToolCodeRabbit, WasFlaggedRisky: true, HadCiFailure: true, CiFailureAt: ptr(at(7)), PrMergedAt: ptr(at(5)), ObservationEndDate: at(9), PredictionOutcome: models.PredictionTP},
+		{AiTool: models.AiToolQodo, HadCiFailure: true, CiFailureAt: ptr(at(7)), PrMergedAt: ptr(at(5)), ObservationEndDate: at(9), PredictionOutcome: models.PredictionFN},
+	}
+
+	events := buildTimeline(pr, reviews, findings, comments, predictions)
+
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{
+		TimelineEventPrCreated,
+		TimelineEventAiReview,
+		TimelineEventFinding,
+		TimelineEventHumanComment,
+		TimelineEventFindingResolved,
+		TimelineEventPrMerged,
+		TimelineEventCiFailure,
+		TimelineEventObservationEnded,
+		TimelineEventObservationEnded,
+	}, types)
+	assert.Equal(t, "Good catch, fixed.", events[3].Summary)
+	assert.Equal(t, "bob", events[5].Actor)
+	assert.Empty(t, events[6].AiTool, "a CI failure seen by several tools is listed once")
+	assert.Equal(t, models.PredictionTP, events[7].Summary)
+}
+
+func TestBuildTimelineClosedWithoutMerge(t *testing.T) {
+	closed := time.Date(2026, 4, 2, 0, 0, 0, 0, time.UTC)
+	pr := &code.PullRequest{CreatedDate: closed.Add(-time.Hour), ClosedDate: &closed}
+
+	events := buildTimeline(pr, nil, nil, nil, nil)
+
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, TimelineEventPrClosed, events[1].Type)
+	}
+}
+
+func TestTruncateSummary(t *testing.T) {
+	assert.Equal(t, "first line", truncateSummary("  first line \nsecond"))
+	long := truncateSummary(strings.Repeat("é", 250))
+	assert.Equal(t, 201, len([]rune(long)))
+}
@@ -90,6 +90,7 @@ func TestCalculateFailurePredictions(t *testing.T) {
 	tester.FlushTabler(&code.PullRequest{})
 	tester.FlushTabler(&code.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&ciTestJob{})
 	tester.FlushTabler(&ciTestCase{})
@@ -158,6 +159,15 @@ func TestCalculateFailurePredictions(t *testing.T) {
 		assertPrediction(t, p, tc.prKey, tc.flagged, tc.ciFail, tc.outcome)
 	}
 
+	// Prediction ids are deterministic hashes, so the full rows are pinned as well
+	tester.VerifyTableWithOptions(&models.AiFailurePrediction{}, e2ehelper.TableOptions{
+		CSVRelPath: "./snapshot_tables/_tool_aireview_failure_predictions.csv",
+		TargetFields: []string{
+			"id", "pull_request_id", "pull_request_key", "repo_id", "repo_short_name", "repo_name", "ai_tool",
+			"ci_failure_source", "was_flagged_risky", "risk_score", "had_ci_failure", "prediction_outcome",
+		},
+	})
+
 	t.Logf("Predictions verified: %d total", len(predictions))
 }
 
@@ -185,6 +195,7 @@ func TestCalculatePredictionMetrics(t *testing.T) {
 	tester.FlushTabler(&code.PullRequest{})
 	tester.FlushTabler(&code.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&models.AiPredictionMetrics{})
 	tester.FlushTabler(&ciTestJob{})
@@ -263,6 +274,7 @@ func TestCalculateFailurePredictions_NoCiData(t *testing.T) {
 	tester.FlushTabler(&code.PullRequest{})
 	tester.FlushTabler(&code.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&ciTestJob{})
 	tester.FlushTabler(&ciTestCase{})
@@ -152,6 +152,7 @@ func TestConvertFailurePredictions(t *testing.T) {
 	tester.FlushTabler(&domainCode.PullRequest{})
 	tester.FlushTabler(&domainCode.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&domainCode.AiFailurePrediction{})
 	tester.FlushTabler(&ciTestJob{})
@@ -214,6 +215,7 @@ func TestConvertFailurePredictions_IsolatedByProject(t *testing.T) {
 	tester.FlushTabler(&domainCode.PullRequest{})
 	tester.FlushTabler(&domainCode.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&domainCode.AiFailurePrediction{})
 	tester.FlushTabler(&ciTestJob{})
@@ -281,6 +283,7 @@ func TestConvertPredictionMetrics(t *testing.T) {
 	tester.FlushTabler(&domainCode.PullRequest{})
 	tester.FlushTabler(&domainCode.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&models.AiPredictionMetrics{})
 	tester.FlushTabler(&domainCode.AiPredictionMetrics{})
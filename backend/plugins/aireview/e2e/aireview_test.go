@@ -21,12 +21,21 @@ import (
 	"testing"
 
 	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
 	"github.com/apache/incubator-devlake/helpers/e2ehelper"
 	"github.com/apache/incubator-devlake/plugins/aireview/impl"
 	"github.com/apache/incubator-devlake/plugins/aireview/models"
 	"github.com/apache/incubator-devlake/plugins/aireview/tasks"
 )
 
+// reviewGoldenFields are the _tool_aireview_reviews columns compared with the golden files
+var reviewGoldenFields = []string{
+	"id", "pull_request_id", "repo_id", "ai_tool", "ai_tool_user", "tool_version", "review_id", "summary", "created_date",
+	"risk_level", "risk_score", "risk_confidence", "issues_found", "suggestions_count", "files_reviewed",
+	"effort_complexity", "effort_rating", "effort_minutes", "suggestions_accepted", "review_state",
+	"pr_status", "pr_is_draft", "source_platform", "source_url",
+}
+
 func TestAiReviewDataFlow(t *testing.T) {
 	var plugin impl.AiReview
 	dataflowTester := e2ehelper.NewDataFlowTester(t, "aireview", plugin)
@@ -84,6 +93,12 @@ func TestAiReviewDataFlow(t *testing.T) {
 		}
 	}
 
+	// Review ids are deterministic hashes, so every extracted field can be pinned
+	dataflowTester.VerifyTableWithOptions(&models.AiReview{}, e2ehelper.TableOptions{
+		CSVRelPath:   "./snapshot_tables/_tool_aireview_reviews.csv",
+		TargetFields: reviewGoldenFields,
+	})
+
 	t.Logf("Successfully extracted %d AI reviews", len(reviews))
 }
 
@@ -131,3 +146,46 @@ func TestAiReviewFindingsDataFlow(t *testing.T) {
 		t.Logf("Extracted %d findings", len(findings))
 	}
 }
+
+// TestAiReviewMixedToolsDataFlow pins the reviews and findings extracted from
+// CodeRabbit and Gemini comments, including file blocks and suggestion blocks,
+// so detection or regex changes show up as golden file diffs.
+func TestAiReviewMixedToolsDataFlow(t *testing.T) {
+	var plugin impl.AiReview
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "aireview", plugin)
+
+	taskData := &tasks.AiReviewTaskData{
+		Options: &tasks.AiReviewOptions{
+			RepoId:      "github:GithubRepo:1:300",
+			ScopeConfig: models.GetDefaultScopeConfig(),
+		},
+	}
+	if err := tasks.CompilePatterns(taskData); err != nil {
+		t.Fatalf("Failed to compile patterns: %v", err)
+	}
+
+	dataflowTester.FlushTabler(&crossdomain.Account{})
+	dataflowTester.FlushTabler(&code.PullRequest{})
+	dataflowTester.FlushTabler(&code.PullRequestComment{})
+	dataflowTester.FlushTabler(&models.AiReview{})
+	dataflowTester.FlushTabler(&models.AiReviewFinding{})
+
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/mixed_pull_requests.csv", &code.PullRequest{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/mixed_pull_request_comments.csv", &code.PullRequestComment{})
+
+	dataflowTester.Subtask(tasks.ExtractAiReviewsMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractAiReviewFindingsMeta, taskData)
+
+	// The human reply on PR 3002 is not an AI review
+	dataflowTester.VerifyTableWithOptions(&models.AiReview{}, e2ehelper.TableOptions{
+		CSVRelPath:   "./snapshot_tables/_tool_aireview_reviews_mixed.csv",
+		TargetFields: reviewGoldenFields,
+	})
+	dataflowTester.VerifyTableWithOptions(&models.AiReviewFinding{}, e2ehelper.TableOptions{
+		CSVRelPath: "./snapshot_tables/_tool_aireview_findings_mixed.csv",
+		TargetFields: []string{
+			"id", "ai_review_id", "pull_request_id", "repo_id", "ai_tool", "category", "severity", "type",
+			"title", "description", "file_path", "suggested_code", "suggestion_applied", "created_date",
+		},
+	})
+}
@@ -116,6 +116,7 @@ func TestFetchMissingCiJobs(t *testing.T) {
 	tester.FlushTabler(&code.PullRequest{})
 	tester.FlushTabler(&code.PullRequestComment{})
 	tester.FlushTabler(&models.AiReview{})
+	tester.FlushTabler(&models.AiReviewFinding{})
 	tester.FlushTabler(&models.AiFailurePrediction{})
 	tester.FlushTabler(&ciTestJob{})
 	tester.FlushTabler(&repoRow{})
@@ -41,7 +41,10 @@ var _ interface {
 	plugin.PluginMetric
 	plugin.PluginMigration
 	plugin.PluginApi
+	plugin.PluginSource
+	plugin.DataSourcePluginBlueprintV200
 	plugin.MetricPluginBlueprintV200
+	plugin.MetricPluginAutoIncludeV200
 } = (*AiReview)(nil)
 
 // AiReview is the main plugin struct
@@ -61,6 +64,18 @@ func (p AiReview) Name() string {
 	return "aireview"
 }
 
+func (p AiReview) Connection() dal.Tabler {
+	return &models.AiReviewConnection{}
+}
+
+func (p AiReview) Scope() plugin.ToolLayerScope {
+	return &models.AiReviewRepo{}
+}
+
+func (p AiReview) ScopeConfig() dal.Tabler {
+	return &models.AiReviewScopeConfig{}
+}
+
 func (p AiReview) RequiredDataEntities() (data []map[string]interface{}, err errors.Error) {
 	return []map[string]interface{}{
 		{
@@ -85,9 +100,16 @@ func (p AiReview) GetTablesInfo() []dal.Tabler {
 	return []dal.Tabler{
 		&models.AiReview{},
 		&models.AiReviewFinding{},
+		&models.AiReviewIssueRef{},
 		&models.AiFailurePrediction{},
 		&models.AiPredictionMetrics{},
+		&models.AiReviewSloMetric{},
+		&models.AiApprovalGatingMetric{},
+		&models.AiFindingTrendAlert{},
+		&models.AiPrDescription{},
 		&models.AiReviewScopeConfig{},
+		&models.AiReviewConnection{},
+		&models.AiReviewRepo{},
 	}
 }
 
@@ -96,8 +118,9 @@ func (p AiReview) IsProjectMetric() bool {
 }
 
 func (p AiReview) RunAfter() ([]string, errors.Error) {
-	// Run after GitHub or GitLab plugins have collected PR data
-	return []string{"github", "gitlab"}, nil
+	// Run after GitHub or GitLab plugins have collected PR data, and after dora has
+	// computed the project_pr_metrics read by the DORA overlays
+	return []string{"github", "gitlab", "dora"}, nil
 }
 
 func (p AiReview) Settings() interface{} {
@@ -107,16 +130,26 @@ func (p AiReview) Settings() interface{} {
 func (p AiReview) SubTaskMetas() []plugin.SubTaskMeta {
 	return []plugin.SubTaskMeta{
 		tasks.ExtractAiReviewsMeta,
+		tasks.ExtractAiPrDescriptionsMeta,
 		tasks.EnrichGithubReviewReactionsMeta,
 		tasks.EnrichGitlabReviewReactionsMeta,
 		tasks.ExtractAiReviewFindingsMeta,
 		tasks.ConvertAiReviewsMeta,
+		tasks.ExtractIssueReferencesMeta,
 		tasks.MatchSuggestionDiffsMeta,
+		tasks.CorrelateFindingsWithBugsMeta,
+		tasks.SyncGithubThreadResolutionMeta,
+		tasks.AnonymizeAiReviewsMeta,
 		tasks.FetchMissingCiJobsMeta,
 		tasks.CalculateFailurePredictionsMeta,
 		tasks.ConvertFailurePredictionsMeta,
 		tasks.CalculatePredictionMetricsMeta,
 		tasks.ConvertPredictionMetricsMeta,
+		tasks.CalculateDoraOverlaysMeta,
+		tasks.CalculateReviewSloMeta,
+		tasks.CalculateApprovalGatingMeta,
+		tasks.DetectFindingTrendsMeta,
+		tasks.CleanupReviewBodiesMeta,
 	}
 }
 
@@ -175,12 +208,33 @@ func (p AiReview) ApiResources() map[string]map[string]plugin.ApiResourceHandler
 		"reviews/:id": {
 			"GET": api.GetReview,
 		},
+		"reviews/:id/debug": {
+			"GET": api.GetReviewDebug,
+		},
 		"stats": {
 			"GET": api.GetReviewStats,
 		},
 		"findings": {
 			"GET": api.GetFindings,
 		},
+		"pull-requests/:prId/timeline": {
+			"GET": api.GetPullRequestTimeline,
+		},
+		"leaderboard": {
+			"GET": api.GetLeaderboard,
+		},
+		"compare": {
+			"GET": api.ComparePeriods,
+		},
+		"simulate": {
+			"GET": api.SimulateGatingPolicy,
+		},
+		"onboarding": {
+			"GET": api.GetOnboardingChecklist,
+		},
+		"repos/:repoId/data": {
+			"DELETE": api.PurgeRepoData,
+		},
 		"scope-configs": {
 			"GET":  api.GetScopeConfigs,
 			"POST": api.CreateScopeConfig,
@@ -188,14 +242,74 @@ func (p AiReview) ApiResources() map[string]map[string]plugin.ApiResourceHandler
 		"scope-configs/default": {
 			"GET": api.GetDefaultScopeConfig,
 		},
+		"scope-configs/discover": {
+			"GET": api.DiscoverScopeConfig,
+		},
+		"scope-configs/import": {
+			"POST": api.ImportScopeConfig,
+		},
+		"scope-configs/pattern-catalog": {
+			"GET": api.GetPatternCatalog,
+		},
+		"scope-configs/pattern-catalog/apply": {
+			"POST": api.ApplyPatternCatalog,
+		},
 		"scope-configs/:id": {
 			"GET":    api.GetScopeConfig,
 			"PATCH":  api.UpdateScopeConfig,
 			"DELETE": api.DeleteScopeConfig,
 		},
+		"scope-configs/:id/export": {
+			"GET": api.ExportScopeConfig,
+		},
 		"analyze": {
 			"POST": api.GenerateAnalysisPipeline,
 		},
+		"test": {
+			"POST": api.TestConnection,
+		},
+		"connections": {
+			"POST": api.PostConnections,
+			"GET":  api.ListConnections,
+		},
+		"connections/:connectionId": {
+			"GET":    api.GetConnection,
+			"PATCH":  api.PatchConnection,
+			"DELETE": api.DeleteConnection,
+		},
+		"connections/:connectionId/test": {
+			"POST": api.TestExistingConnection,
+		},
+		"connections/:connectionId/scopes": {
+			"GET": api.GetScopeList,
+			"PUT": api.PutScopes,
+		},
+		"connections/:connectionId/scopes/:scopeId": {
+			"GET":    api.GetScope,
+			"PATCH":  api.PatchScope,
+			"DELETE": api.DeleteScope,
+		},
+		"connections/:connectionId/scopes/:scopeId/latest-sync-state": {
+			"GET": api.GetScopeLatestSyncState,
+		},
+		"connections/:connectionId/remote-scopes": {
+			"GET": api.RemoteScopes,
+		},
+		"connections/:connectionId/search-remote-scopes": {
+			"GET": api.SearchRemoteScopes,
+		},
+		"connections/:connectionId/scope-configs": {
+			"POST": api.PostConnectionScopeConfig,
+			"GET":  api.GetConnectionScopeConfigList,
+		},
+		"connections/:connectionId/scope-configs/:scopeConfigId": {
+			"GET":    api.GetConnectionScopeConfig,
+			"PATCH":  api.PatchConnectionScopeConfig,
+			"DELETE": api.DeleteConnectionScopeConfig,
+		},
+		"scope-config/:scopeConfigId/projects": {
+			"GET": api.GetProjectsByScopeConfig,
+		},
 	}
 }
 
@@ -216,6 +330,21 @@ func DecodeAndValidateTaskOptions(options map[string]interface{}) (*tasks.AiRevi
 	return op, nil
 }
 
+// AutoIncludeDataSources adds aireview, once it has a connection, to every project
+// blueprint collecting PRs from GitHub or GitLab, or holding aireview repo scopes,
+// so its project task runs after their collection without editing the pipeline JSON
+func (p AiReview) AutoIncludeDataSources() []string {
+	return []string{"github", "gitlab", "aireview"}
+}
+
+// MakeDataSourcePipelinePlanV200 maps the repo scopes to the project, they are analyzed by the metric plan
+func (p AiReview) MakeDataSourcePipelinePlanV200(
+	connectionId uint64,
+	scopes []*coreModels.BlueprintScope,
+) (coreModels.PipelinePlan, []plugin.Scope, errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(connectionId, scopes)
+}
+
 // MakeMetricPluginPipelinePlanV200 generates pipeline plan for project metrics
 func (p AiReview) MakeMetricPluginPipelinePlanV200(projectName string, options json.RawMessage) (coreModels.PipelinePlan, errors.Error) {
 	op := &tasks.AiReviewOptions{}
@@ -226,34 +355,5 @@ func (p AiReview) MakeMetricPluginPipelinePlanV200(projectName string, options j
 		}
 	}
 
-	// Build options map with projectName
-	opts := map[string]interface{}{
-		"projectName": projectName,
-	}
-	if op.ScopeConfigId != 0 {
-		opts["scopeConfigId"] = op.ScopeConfigId
-	}
-
-	plan := coreModels.PipelinePlan{
-		{
-			{
-				Plugin:  "aireview",
-				Options: opts,
-				Subtasks: []string{
-					tasks.ExtractAiReviewsMeta.Name,
-					tasks.EnrichGithubReviewReactionsMeta.Name,
-					tasks.EnrichGitlabReviewReactionsMeta.Name,
-					tasks.ExtractAiReviewFindingsMeta.Name,
-					tasks.ConvertAiReviewsMeta.Name,
-					tasks.MatchSuggestionDiffsMeta.Name,
-					tasks.FetchMissingCiJobsMeta.Name,
-					tasks.CalculateFailurePredictionsMeta.Name,
-					tasks.ConvertFailurePredictionsMeta.Name,
-					tasks.CalculatePredictionMetricsMeta.Name,
-					tasks.ConvertPredictionMetricsMeta.Name,
-				},
-			},
-		},
-	}
-	return plan, nil
+	return api.MakeMetricPipelinePlanV200(p.SubTaskMetas(), projectName, op)
 }
@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// AiApprovalGatingMetric stores the monthly cost of requiring an AI approval
+// to merge: the merge time of PRs whose AI approval was invalidated by a push
+// and had to be given again, against PRs merged on their first approval
+type AiApprovalGatingMetric struct {
+	common.NoPKModel
+
+	// Primary key
+	Id string `gorm:"primaryKey;type:varchar(255)"`
+
+	// Scope
+	RepoId string `gorm:"index;type:varchar(255)"`
+	AiTool string `gorm:"type:varchar(100)"`
+
+	// First day 00:00 UTC of the month the PRs were merged
+	MonthStart time.Time `gorm:"index"`
+
+	// Merged PRs approved by the AI tool, split into those pushed to after
+	// the first approval (needing a re-approval) and those that weren't
+	GatedPrs          int
+	ReapprovedPrs     int
+	SingleApprovalPrs int
+	ReapprovalPct     float64 // ReapprovedPrs / GatedPrs × 100
+
+	// Approvals given after the first one
+	Reapprovals int
+
+	// Time from PR creation to merge, and the difference between the two
+	// groups (reapproved minus single approval)
+	P50MergeHoursReapproved float64
+	P50MergeHoursSingle     float64
+	MergeDelayHours         float64
+
+	// Time from the push that invalidated the first approval to the next approval
+	P50ReapprovalWaitHours float64
+
+	CalculatedAt time.Time
+}
+
+func (AiApprovalGatingMetric) TableName() string {
+	return "_tool_aireview_approval_gating_metrics"
+}
@@ -49,6 +49,13 @@ type AiFailurePrediction struct {
 	// AI tool that made the prediction
 	AiTool string `gorm:"type:varchar(100)"`
 
+	// Tool version reported in the PR's reviews by AiTool (the greatest when they differ)
+	ToolVersion string `gorm:"type:varchar(100)"`
+
+	// Dominant category of AiTool's findings on the PR (most findings, ties
+	// broken by severity); empty when the tool left no categorised findings
+	FindingCategory string `gorm:"type:varchar(100);index"`
+
 	// Which CI data source was used: "test_cases", "job_result", or "none" (NO_CI records)
 	CiFailureSource string `gorm:"type:varchar(20);index"`
 
@@ -121,6 +128,18 @@ type AiPredictionMetrics struct {
 	AiTool          string `gorm:"type:varchar(100)"`
 	CiFailureSource string `gorm:"type:varchar(20);index"`
 
+	// Tool version the metrics are restricted to; empty for the row that
+	// aggregates all versions of AiTool
+	ToolVersion string `gorm:"type:varchar(100)"`
+
+	// Finding category the metrics are restricted to; empty for the row that
+	// aggregates all categories
+	Category string `gorm:"type:varchar(100)"`
+
+	// Set on the row aggregating all tool versions and finding categories, the
+	// per-version and per-category rows share the table and can have empty columns too
+	IsAggregate bool `gorm:"index"`
+
 	// Time period
 	PeriodStart time.Time `gorm:"index"`
 	PeriodEnd   time.Time
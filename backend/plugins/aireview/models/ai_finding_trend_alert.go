@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// AiFindingTrendAlert flags a finding category whose count rose notably in the
+// last 4 weeks compared with the 4 weeks before. Rows are keyed by the window,
+// so a digest or webhook can send each alert once by its id.
+type AiFindingTrendAlert struct {
+	common.NoPKModel
+
+	// Primary key
+	Id string `gorm:"primaryKey;type:varchar(255)"`
+
+	// Scope
+	RepoId   string `gorm:"index;type:varchar(255)"`
+	AiTool   string `gorm:"type:varchar(100)"`
+	Category string `gorm:"type:varchar(100)"`
+
+	// The current period is [WindowStart, WindowEnd); the prior period is the
+	// same length right before WindowStart
+	WindowStart time.Time
+	WindowEnd   time.Time `gorm:"index"`
+
+	CurrentCount int
+	PriorCount   int
+	ChangePct    float64 // (CurrentCount - PriorCount) / PriorCount × 100; 0 when PriorCount is 0
+	ZScore       float64 // (CurrentCount - PriorCount) / sqrt(CurrentCount + PriorCount)
+
+	CalculatedAt time.Time
+}
+
+func (AiFindingTrendAlert) TableName() string {
+	return "_tool_aireview_trend_alerts"
+}
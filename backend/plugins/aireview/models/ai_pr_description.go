@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// AiPrDescription records whether the description of a pull request was
+// generated by an AI tool (Qodo describe, Copilot, CodeRabbit summaries),
+// with the sections of AI-generated descriptions
+type AiPrDescription struct {
+	common.NoPKModel
+
+	// Primary key: one row per pull request
+	PullRequestId string `gorm:"primaryKey;type:varchar(255)"`
+
+	// Repository reference
+	RepoId string `gorm:"index;type:varchar(255)"`
+
+	IsAiGenerated bool   `gorm:"type:boolean"`
+	AiTool        string `gorm:"type:varchar(100)"` // coderabbit, qodo, copilot, other; empty when written by hand
+	Marker        string `gorm:"type:varchar(255)"` // the text that identified the tool
+
+	// Sections of an AI-generated description, split on its headings
+	Sections     []PrDescriptionSection `gorm:"type:json;serializer:json"`
+	SectionCount int
+
+	// PR state at extraction time, for the same filters as the reviews
+	PrStatus      string    `gorm:"type:varchar(100)"`
+	PrIsDraft     bool      `gorm:"type:boolean"`
+	PrCreatedDate time.Time `gorm:"index"`
+}
+
+// PrDescriptionSection is one headed section of a PR description, e.g. "Description" or "Changes walkthrough"
+type PrDescriptionSection struct {
+	Heading string `json:"heading"`
+	Content string `json:"content"`
+}
+
+func (AiPrDescription) TableName() string {
+	return "_tool_aireview_pr_descriptions"
+}
+
+// AiToolOther is the tool of descriptions matched by the scope config aiPrDescriptionPattern
+const AiToolOther = "other"
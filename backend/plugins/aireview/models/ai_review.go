@@ -40,6 +40,10 @@ type AiReview struct {
 	AiTool     string `gorm:"type:varchar(100)"` // coderabbit, cursor_bugbot, etc.
 	AiToolUser string `gorm:"type:varchar(255)"` // Bot username
 
+	// Tool version or model reported in the review body (e.g. "v2.3.1", "gemini-2.5-pro"),
+	// empty when the review does not mention one
+	ToolVersion string `gorm:"type:varchar(100);index"`
+
 	// Review metadata
 	ReviewId    string    `gorm:"type:varchar(255)"` // Original review/comment ID from source
 	Body        string    `gorm:"type:longtext"`     // Full review body
@@ -81,9 +85,16 @@ type AiReview struct {
 	// Review outcome
 	ReviewState string `gorm:"type:varchar(50)"` // approved, changes_requested, commented
 
+	// State of the reviewed PR at extraction time
+	PrStatus  string `gorm:"type:varchar(100)"` // OPEN, MERGED, CLOSED (domain pull_requests.status)
+	PrIsDraft bool
+
 	// Source information
-	SourcePlatform string `gorm:"type:varchar(50)"` // github, gitlab
+	SourcePlatform string `gorm:"type:varchar(50)"` // github, gitlab, bitbucket, azuredevops
 	SourceUrl      string `gorm:"type:varchar(500)"`
+
+	// Retention: set when the body was truncated by the cleanupReviewBodies subtask
+	BodyTruncatedAt *time.Time
 }
 
 func (AiReview) TableName() string {
@@ -46,6 +46,7 @@ type AiReviewFinding struct {
 	Category string `gorm:"type:varchar(100)"` // security, performance, best_practice, bug, style
 	Severity string `gorm:"type:varchar(50)"`  // info, warning, error, critical
 	Type     string `gorm:"type:varchar(100)"` // suggestion, issue, comment
+	ToolTerm string `gorm:"type:varchar(100)"` // risk term of the tool that set the category and severity, e.g. "Nitpick"
 
 	// Finding details
 	Title       string `gorm:"type:varchar(500)"`
@@ -69,6 +70,12 @@ type AiReviewFinding struct {
 	MatchedCommitSha        string  `gorm:"type:varchar(40)"`  // Commit SHA that applied the suggestion
 	MatchedFilePath         string  `gorm:"type:varchar(500)"` // File path resolved from raw data
 
+	// Bug correlation: a bug issue filed within the observation window after the
+	// PR was merged was fixed by a change touching the same file as this finding
+	BugMaterialized     bool
+	MaterializedIssueId string `gorm:"type:varchar(255)"`
+	MaterializedAt      *time.Time
+
 	// Resolution tracking
 	IsResolved   bool
 	ResolvedAt   *time.Time
@@ -119,4 +126,8 @@ const (
 	ResolutionFixed         = "fixed"
 	ResolutionWontFix       = "wont_fix"
 	ResolutionFalsePositive = "false_positive"
+
+	// ResolutionThreadResolved is set by syncGithubThreadResolution when the
+	// review thread was resolved on GitHub, with or without a code change
+	ResolutionThreadResolved = "thread_resolved"
 )
@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// AiReviewIssueRef is an issue referenced in the body of an AI review, such as a
+// Jira key ("relates to ABC-123") or a repository issue ("#42", "org/repo#42")
+type AiReviewIssueRef struct {
+	common.NoPKModel
+
+	// Primary key: one row per review and referenced issue
+	AiReviewId string `gorm:"primaryKey;type:varchar(255)"`
+	IssueKey   string `gorm:"primaryKey;type:varchar(255)"` // ABC-123, #42 or org/repo#42
+
+	// Foreign key to pull_requests domain table
+	PullRequestId string `gorm:"index;type:varchar(255)"`
+
+	// Repository reference
+	RepoId string `gorm:"index;type:varchar(255)"`
+
+	RefType string `gorm:"type:varchar(50)"` // jira, repo_issue
+
+	// Referenced issue in the issues domain table, empty when it was not collected
+	IssueId             string `gorm:"index;type:varchar(255)"`
+	IssueStatus         string `gorm:"type:varchar(100)"` // TODO, IN_PROGRESS, DONE (domain issues.status)
+	IssueResolutionDate *time.Time
+
+	// When the review was posted
+	CreatedDate time.Time `gorm:"index"`
+}
+
+func (AiReviewIssueRef) TableName() string {
+	return "_tool_aireview_issue_refs"
+}
+
+// Issue reference type constants
+const (
+	IssueRefTypeJira      = "jira"
+	IssueRefTypeRepoIssue = "repo_issue"
+)
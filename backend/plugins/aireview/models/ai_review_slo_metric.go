@@ -0,0 +1,62 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// AiReviewSloMetric stores the weekly attainment of the AI review response
+// time SLO: the share of PR opens and pushes that got an AI review within
+// SloMinutes
+type AiReviewSloMetric struct {
+	common.NoPKModel
+
+	// Primary key
+	Id string `gorm:"primaryKey;type:varchar(255)"`
+
+	// Scope
+	RepoId string `gorm:"index;type:varchar(255)"`
+	AiTool string `gorm:"type:varchar(100)"`
+
+	// Monday 00:00 UTC of the week the PR was opened or pushed to
+	WeekStart time.Time `gorm:"index"`
+
+	// The review_slo_minutes in effect when the row was computed
+	SloMinutes int
+
+	// Triggers (PR opens and pushes) answered by an AI review, those
+	// answered within SloMinutes, and those no review answered before the
+	// SLO window elapsed
+	Reviews       int
+	WithinSlo     int
+	Missed        int
+	AttainmentPct float64 // WithinSlo / (Reviews + Missed) × 100
+
+	// Response time from the trigger to the AI review, missed triggers left out
+	P50LatencyMinutes float64
+	P90LatencyMinutes float64
+
+	CalculatedAt time.Time
+}
+
+func (AiReviewSloMetric) TableName() string {
+	return "_tool_aireview_slo_metrics"
+}
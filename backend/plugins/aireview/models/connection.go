@@ -0,0 +1,32 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import (
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+// AiReviewConnection groups the repositories analyzed by aireview so they can be
+// added to projects like the scopes of any data source. The reviews are read from
+// the PRs collected by the github and gitlab plugins, so it holds no endpoint or token.
+type AiReviewConnection struct {
+	helper.BaseConnection `mapstructure:",squash"`
+}
+
+func (AiReviewConnection) TableName() string {
+	return "_tool_aireview_connections"
+}
@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addBodyRetention)(nil)
+
+type addBodyRetention struct{}
+
+// Up adds body_retention_days to scope configs and body_truncated_at to reviews.
+func (script *addBodyRetention) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	if err := db.AutoMigrate(&scopeConfigBodyRetention20260420{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for body retention")
+	}
+	if err := db.AutoMigrate(&reviewBodyRetention20260420{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_reviews for body retention")
+	}
+
+	return nil
+}
+
+func (script *addBodyRetention) Version() uint64 {
+	return 20260420000001
+}
+
+func (script *addBodyRetention) Name() string {
+	return "aireview add review body retention settings"
+}
+
+type scopeConfigBodyRetention20260420 struct {
+	BodyRetentionDays int `gorm:"default:0"`
+}
+
+func (scopeConfigBodyRetention20260420) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
+
+type reviewBodyRetention20260420 struct {
+	BodyTruncatedAt *time.Time
+}
+
+func (reviewBodyRetention20260420) TableName() string {
+	return "_tool_aireview_reviews"
+}
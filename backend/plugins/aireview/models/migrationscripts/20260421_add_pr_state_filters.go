@@ -0,0 +1,69 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addPrStateFilters)(nil)
+
+type addPrStateFilters struct{}
+
+// Up adds the draft / closed-unmerged PR toggles to scope configs and records
+// the PR state on each review so stats can filter on it.
+func (script *addPrStateFilters) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	if err := db.AutoMigrate(&scopeConfigPrState20260421{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for PR state filters")
+	}
+	if err := db.AutoMigrate(&reviewPrState20260421{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_reviews for PR state")
+	}
+
+	return nil
+}
+
+func (script *addPrStateFilters) Version() uint64 {
+	return 20260421000001
+}
+
+func (script *addPrStateFilters) Name() string {
+	return "aireview add draft and closed-unmerged PR filters"
+}
+
+type scopeConfigPrState20260421 struct {
+	ExcludeDraftPrs          bool `gorm:"type:boolean;default:false"`
+	ExcludeClosedUnmergedPrs bool `gorm:"type:boolean;default:false"`
+}
+
+func (scopeConfigPrState20260421) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
+
+type reviewPrState20260421 struct {
+	PrStatus  string `gorm:"type:varchar(100)"`
+	PrIsDraft bool
+}
+
+func (reviewPrState20260421) TableName() string {
+	return "_tool_aireview_reviews"
+}
@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addFindingBugCorrelation)(nil)
+
+type addFindingBugCorrelation struct{}
+
+// Up adds the materialized-bug flag to findings so each finding can be
+// validated against bugs filed after the PR was merged.
+func (script *addFindingBugCorrelation) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&findingBugCorrelation20260422{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_findings for bug correlation")
+	}
+	return nil
+}
+
+func (script *addFindingBugCorrelation) Version() uint64 {
+	return 20260422000001
+}
+
+func (script *addFindingBugCorrelation) Name() string {
+	return "aireview add finding bug correlation"
+}
+
+type findingBugCorrelation20260422 struct {
+	BugMaterialized     bool
+	MaterializedIssueId string `gorm:"type:varchar(255)"`
+	MaterializedAt      *time.Time
+}
+
+func (findingBugCorrelation20260422) TableName() string {
+	return "_tool_aireview_findings"
+}
@@ -0,0 +1,65 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addIssueRefs)(nil)
+
+type addIssueRefs struct{}
+
+// Up adds the table of issues referenced by AI reviews.
+func (script *addIssueRefs) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&issueRef20260424{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_issue_refs")
+	}
+	return nil
+}
+
+func (script *addIssueRefs) Version() uint64 {
+	return 20260424000001
+}
+
+func (script *addIssueRefs) Name() string {
+	return "aireview add issue references"
+}
+
+type issueRef20260424 struct {
+	common.NoPKModel
+	AiReviewId          string `gorm:"primaryKey;type:varchar(255)"`
+	IssueKey            string `gorm:"primaryKey;type:varchar(255)"`
+	PullRequestId       string `gorm:"index;type:varchar(255)"`
+	RepoId              string `gorm:"index;type:varchar(255)"`
+	RefType             string `gorm:"type:varchar(50)"`
+	IssueId             string `gorm:"index;type:varchar(255)"`
+	IssueStatus         string `gorm:"type:varchar(100)"`
+	IssueResolutionDate *time.Time
+	CreatedDate         time.Time `gorm:"index"`
+}
+
+func (issueRef20260424) TableName() string {
+	return "_tool_aireview_issue_refs"
+}
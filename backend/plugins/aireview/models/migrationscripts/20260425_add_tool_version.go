@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addToolVersion)(nil)
+
+type addToolVersion struct{}
+
+// Up adds tool_version to reviews, failure predictions and prediction metrics.
+func (script *addToolVersion) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	if err := db.AutoMigrate(&reviewToolVersion20260425{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_reviews for tool version")
+	}
+	if err := db.AutoMigrate(&failurePredictionToolVersion20260425{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_failure_predictions for tool version")
+	}
+	if err := db.AutoMigrate(&predictionMetricsToolVersion20260425{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_prediction_metrics for tool version")
+	}
+
+	return nil
+}
+
+func (script *addToolVersion) Version() uint64 {
+	return 20260425000001
+}
+
+func (script *addToolVersion) Name() string {
+	return "aireview add AI tool version"
+}
+
+type reviewToolVersion20260425 struct {
+	ToolVersion string `gorm:"type:varchar(100);index"`
+}
+
+func (reviewToolVersion20260425) TableName() string {
+	return "_tool_aireview_reviews"
+}
+
+type failurePredictionToolVersion20260425 struct {
+	ToolVersion string `gorm:"type:varchar(100)"`
+}
+
+func (failurePredictionToolVersion20260425) TableName() string {
+	return "_tool_aireview_failure_predictions"
+}
+
+type predictionMetricsToolVersion20260425 struct {
+	ToolVersion string `gorm:"type:varchar(100)"`
+}
+
+func (predictionMetricsToolVersion20260425) TableName() string {
+	return "_tool_aireview_prediction_metrics"
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addSourcePlatforms)(nil)
+
+type addSourcePlatforms struct{}
+
+// Up adds the source platform prefix rules to scope configs.
+func (script *addSourcePlatforms) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&scopeConfigSourcePlatforms20260426{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for source platforms")
+	}
+	return nil
+}
+
+func (script *addSourcePlatforms) Version() uint64 {
+	return 20260426000001
+}
+
+func (script *addSourcePlatforms) Name() string {
+	return "aireview add source platform prefix rules"
+}
+
+type scopeConfigSourcePlatforms20260426 struct {
+	SourcePlatforms string `gorm:"type:json"`
+}
+
+func (scopeConfigSourcePlatforms20260426) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addPreserveHtmlTools)(nil)
+
+type addPreserveHtmlTools struct{}
+
+// Up adds the list of tools whose review bodies skip the HTML to markdown conversion.
+func (script *addPreserveHtmlTools) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&scopeConfigPreserveHtml20260427{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for preserve html tools")
+	}
+	return nil
+}
+
+func (script *addPreserveHtmlTools) Version() uint64 {
+	return 20260427000001
+}
+
+func (script *addPreserveHtmlTools) Name() string {
+	return "aireview add preserve html tools to scope configs"
+}
+
+type scopeConfigPreserveHtml20260427 struct {
+	PreserveHtmlTools string `gorm:"type:varchar(255)"`
+}
+
+func (scopeConfigPreserveHtml20260427) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
@@ -0,0 +1,54 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addBotReplyFilter)(nil)
+
+type addBotReplyFilter struct{}
+
+// Up adds the toggle skipping AI replies to bots and the CI bot username pattern.
+func (script *addBotReplyFilter) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&scopeConfigBotReplies20260428{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for bot reply filter")
+	}
+	return nil
+}
+
+func (script *addBotReplyFilter) Version() uint64 {
+	return 20260428000001
+}
+
+func (script *addBotReplyFilter) Name() string {
+	return "aireview add bot reply filter to scope configs"
+}
+
+type scopeConfigBotReplies20260428 struct {
+	ExcludeBotReplies  bool   `gorm:"type:boolean;default:false"`
+	BotUsernamePattern string `gorm:"type:varchar(500)"`
+}
+
+func (scopeConfigBotReplies20260428) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addReviewSlo)(nil)
+
+type addReviewSlo struct{}
+
+// Up adds the review response time SLO to scope configs and the weekly SLO metrics table.
+func (script *addReviewSlo) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&scopeConfigReviewSlo20260429{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for review SLO")
+	}
+	if err := db.AutoMigrate(&sloMetric20260429{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_slo_metrics")
+	}
+	return nil
+}
+
+func (script *addReviewSlo) Version() uint64 {
+	return 20260429000001
+}
+
+func (script *addReviewSlo) Name() string {
+	return "aireview add review response time SLO"
+}
+
+type scopeConfigReviewSlo20260429 struct {
+	ReviewSloMinutes int `gorm:"default:0"`
+}
+
+func (scopeConfigReviewSlo20260429) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
+
+type sloMetric20260429 struct {
+	common.NoPKModel
+	Id                string    `gorm:"primaryKey;type:varchar(255)"`
+	RepoId            string    `gorm:"index;type:varchar(255)"`
+	AiTool            string    `gorm:"type:varchar(100)"`
+	WeekStart         time.Time `gorm:"index"`
+	SloMinutes        int
+	Reviews           int
+	WithinSlo         int
+	AttainmentPct     float64
+	P50LatencyMinutes float64
+	P90LatencyMinutes float64
+	CalculatedAt      time.Time
+}
+
+func (sloMetric20260429) TableName() string {
+	return "_tool_aireview_slo_metrics"
+}
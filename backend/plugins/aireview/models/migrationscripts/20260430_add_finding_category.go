@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addFindingCategory)(nil)
+
+type addFindingCategory struct{}
+
+// Up adds the dominant finding category to failure predictions and the category
+// split to prediction metrics.
+func (script *addFindingCategory) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	if err := db.AutoMigrate(&failurePredictionCategory20260430{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_failure_predictions for finding category")
+	}
+	if err := db.AutoMigrate(&predictionMetricsCategory20260430{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_prediction_metrics for finding category")
+	}
+
+	return nil
+}
+
+func (script *addFindingCategory) Version() uint64 {
+	return 20260430000001
+}
+
+func (script *addFindingCategory) Name() string {
+	return "aireview add per-category prediction metrics"
+}
+
+type failurePredictionCategory20260430 struct {
+	FindingCategory string `gorm:"type:varchar(100);index"`
+}
+
+func (failurePredictionCategory20260430) TableName() string {
+	return "_tool_aireview_failure_predictions"
+}
+
+type predictionMetricsCategory20260430 struct {
+	Category string `gorm:"type:varchar(100)"`
+}
+
+func (predictionMetricsCategory20260430) TableName() string {
+	return "_tool_aireview_prediction_metrics"
+}
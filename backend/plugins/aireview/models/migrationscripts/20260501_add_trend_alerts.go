@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addTrendAlerts)(nil)
+
+type addTrendAlerts struct{}
+
+// Up adds the finding category trend alerts table.
+func (script *addTrendAlerts) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&trendAlert20260501{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_trend_alerts")
+	}
+	return nil
+}
+
+func (script *addTrendAlerts) Version() uint64 {
+	return 20260501000001
+}
+
+func (script *addTrendAlerts) Name() string {
+	return "aireview add finding category trend alerts"
+}
+
+type trendAlert20260501 struct {
+	common.NoPKModel
+	Id           string `gorm:"primaryKey;type:varchar(255)"`
+	RepoId       string `gorm:"index;type:varchar(255)"`
+	AiTool       string `gorm:"type:varchar(100)"`
+	Category     string `gorm:"type:varchar(100)"`
+	WindowStart  time.Time
+	WindowEnd    time.Time `gorm:"index"`
+	CurrentCount int
+	PriorCount   int
+	ChangePct    float64
+	ZScore       float64
+	CalculatedAt time.Time
+}
+
+func (trendAlert20260501) TableName() string {
+	return "_tool_aireview_trend_alerts"
+}
@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addPrDescriptions)(nil)
+
+type addPrDescriptions struct{}
+
+// Up adds the AI-generated PR descriptions table and the custom description pattern.
+func (script *addPrDescriptions) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&prDescription20260502{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_pr_descriptions")
+	}
+	if err := db.AutoMigrate(&scopeConfigPrDescriptions20260502{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for PR descriptions")
+	}
+	return nil
+}
+
+func (script *addPrDescriptions) Version() uint64 {
+	return 20260502000001
+}
+
+func (script *addPrDescriptions) Name() string {
+	return "aireview add AI-generated PR descriptions"
+}
+
+type prDescriptionSection20260502 struct {
+	Heading string `json:"heading"`
+	Content string `json:"content"`
+}
+
+type prDescription20260502 struct {
+	common.NoPKModel
+	PullRequestId string                         `gorm:"primaryKey;type:varchar(255)"`
+	RepoId        string                         `gorm:"index;type:varchar(255)"`
+	IsAiGenerated bool                           `gorm:"type:boolean"`
+	AiTool        string                         `gorm:"type:varchar(100)"`
+	Marker        string                         `gorm:"type:varchar(255)"`
+	Sections      []prDescriptionSection20260502 `gorm:"type:json;serializer:json"`
+	SectionCount  int
+	PrStatus      string    `gorm:"type:varchar(100)"`
+	PrIsDraft     bool      `gorm:"type:boolean"`
+	PrCreatedDate time.Time `gorm:"index"`
+}
+
+func (prDescription20260502) TableName() string {
+	return "_tool_aireview_pr_descriptions"
+}
+
+type scopeConfigPrDescriptions20260502 struct {
+	AiPrDescriptionPattern string `gorm:"type:varchar(500)"`
+}
+
+func (scopeConfigPrDescriptions20260502) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
@@ -0,0 +1,81 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addApprovalGating)(nil)
+
+type addApprovalGating struct{}
+
+// Up adds the AI approval requirement to scope configs and the monthly approval gating metrics table.
+func (script *addApprovalGating) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&scopeConfigApprovalGating20260504{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for approval gating")
+	}
+	if err := db.AutoMigrate(&approvalGatingMetric20260504{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_approval_gating_metrics")
+	}
+	return nil
+}
+
+func (script *addApprovalGating) Version() uint64 {
+	return 20260504000001
+}
+
+func (script *addApprovalGating) Name() string {
+	return "aireview add AI approval gating metrics"
+}
+
+type scopeConfigApprovalGating20260504 struct {
+	AiApprovalRequired bool `gorm:"type:boolean;default:false"`
+}
+
+func (scopeConfigApprovalGating20260504) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
+
+type approvalGatingMetric20260504 struct {
+	common.NoPKModel
+	Id                      string    `gorm:"primaryKey;type:varchar(255)"`
+	RepoId                  string    `gorm:"index;type:varchar(255)"`
+	AiTool                  string    `gorm:"type:varchar(100)"`
+	MonthStart              time.Time `gorm:"index"`
+	GatedPrs                int
+	ReapprovedPrs           int
+	SingleApprovalPrs       int
+	ReapprovalPct           float64
+	Reapprovals             int
+	P50MergeHoursReapproved float64
+	P50MergeHoursSingle     float64
+	MergeDelayHours         float64
+	P50ReapprovalWaitHours  float64
+	CalculatedAt            time.Time
+}
+
+func (approvalGatingMetric20260504) TableName() string {
+	return "_tool_aireview_approval_gating_metrics"
+}
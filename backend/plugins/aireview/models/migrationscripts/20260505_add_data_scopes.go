@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addDataScopes)(nil)
+
+type addDataScopes struct{}
+
+// Up adds the connections and repo scopes used to add aireview to projects.
+func (script *addDataScopes) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&connection20260505{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_connections")
+	}
+	if err := db.AutoMigrate(&repo20260505{}); err != nil {
+		return errors.Default.Wrap(err, "failed to create _tool_aireview_repos")
+	}
+	return nil
+}
+
+func (script *addDataScopes) Version() uint64 {
+	return 20260505000001
+}
+
+func (script *addDataScopes) Name() string {
+	return "aireview add connections and repo scopes"
+}
+
+type connection20260505 struct {
+	common.Model
+	Name string `gorm:"type:varchar(100);uniqueIndex"`
+}
+
+func (connection20260505) TableName() string {
+	return "_tool_aireview_connections"
+}
+
+type repo20260505 struct {
+	common.NoPKModel
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	ScopeConfigId uint64
+	Id            string `gorm:"primaryKey;type:varchar(255)"`
+	Name          string `gorm:"type:varchar(255)"`
+	Url           string `gorm:"type:varchar(255)"`
+}
+
+func (repo20260505) TableName() string {
+	return "_tool_aireview_repos"
+}
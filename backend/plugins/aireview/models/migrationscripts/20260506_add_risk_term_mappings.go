@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addRiskTermMappings)(nil)
+
+type addRiskTermMappings struct{}
+
+// Up adds the risk term mappings to scope configs and the matched term to findings.
+func (script *addRiskTermMappings) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	if err := db.AutoMigrate(&scopeConfigRiskTermMappings20260506{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_scope_configs for risk term mappings")
+	}
+	if err := db.AutoMigrate(&findingToolTerm20260506{}); err != nil {
+		return errors.Default.Wrap(err, "failed to migrate _tool_aireview_findings for risk terms")
+	}
+
+	return nil
+}
+
+func (script *addRiskTermMappings) Version() uint64 {
+	return 20260506000001
+}
+
+func (script *addRiskTermMappings) Name() string {
+	return "aireview add risk term mappings"
+}
+
+type scopeConfigRiskTermMappings20260506 struct {
+	RiskTermMappings string `gorm:"type:json"`
+}
+
+func (scopeConfigRiskTermMappings20260506) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
+
+type findingToolTerm20260506 struct {
+	ToolTerm string `gorm:"type:varchar(100)"`
+}
+
+func (findingToolTerm20260506) TableName() string {
+	return "_tool_aireview_findings"
+}
@@ -0,0 +1,56 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addCopilotConfig)(nil)
+
+type addCopilotConfig struct{}
+
+// Up adds the GitHub Copilot detection columns to scope configs. The username and pattern
+// stay empty until the pattern catalog is applied to the scope config.
+func (script *addCopilotConfig) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&scopeConfigAddCopilot20260507{}); err != nil {
+		return errors.Default.Wrap(err, "failed to add Copilot columns to _tool_aireview_scope_configs")
+	}
+	return nil
+}
+
+func (script *addCopilotConfig) Version() uint64 {
+	return 20260507000001
+}
+
+func (script *addCopilotConfig) Name() string {
+	return "aireview add GitHub Copilot configuration"
+}
+
+type scopeConfigAddCopilot20260507 struct {
+	CopilotEnabled  bool   `gorm:"type:boolean;default:true"`
+	CopilotUsername string `gorm:"type:varchar(255)"`
+	CopilotPattern  string `gorm:"type:varchar(500)"`
+}
+
+func (scopeConfigAddCopilot20260507) TableName() string {
+	return "_tool_aireview_scope_configs"
+}
@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addPredictionMetricsAggregate)(nil)
+
+type addPredictionMetricsAggregate struct{}
+
+// Up adds is_aggregate to prediction metrics and sets it on the existing rows
+// aggregating all tool versions and finding categories.
+func (script *addPredictionMetricsAggregate) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&predictionMetricsAggregate20260508{}); err != nil {
+		return errors.Default.Wrap(err, "failed to add is_aggregate to _tool_aireview_prediction_metrics")
+	}
+	if err := db.Exec("UPDATE _tool_aireview_prediction_metrics SET is_aggregate = ? WHERE tool_version = '' AND category = ''", true); err != nil {
+		return errors.Default.Wrap(err, "failed to mark aggregate rows of _tool_aireview_prediction_metrics")
+	}
+	return nil
+}
+
+func (script *addPredictionMetricsAggregate) Version() uint64 {
+	return 20260508000001
+}
+
+func (script *addPredictionMetricsAggregate) Name() string {
+	return "aireview add prediction metrics aggregate marker"
+}
+
+type predictionMetricsAggregate20260508 struct {
+	IsAggregate bool `gorm:"index"`
+}
+
+func (predictionMetricsAggregate20260508) TableName() string {
+	return "_tool_aireview_prediction_metrics"
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addReviewSloMissed)(nil)
+
+type addReviewSloMissed struct{}
+
+// Up adds the count of triggers no AI review answered to the weekly SLO metrics.
+func (script *addReviewSloMissed) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+	if err := db.AutoMigrate(&sloMetricMissed20260509{}); err != nil {
+		return errors.Default.Wrap(err, "failed to add missed to _tool_aireview_slo_metrics")
+	}
+	return nil
+}
+
+func (script *addReviewSloMissed) Version() uint64 {
+	return 20260509000001
+}
+
+func (script *addReviewSloMissed) Name() string {
+	return "aireview add missed review SLO responses"
+}
+
+type sloMetricMissed20260509 struct {
+	Missed int
+}
+
+func (sloMetricMissed20260509) TableName() string {
+	return "_tool_aireview_slo_metrics"
+}
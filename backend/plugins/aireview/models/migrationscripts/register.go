@@ -32,5 +32,25 @@ func All() []plugin.MigrationScript {
 		&addFlakyInfraFilters{},
 		&addSuggestionsAccepted{},
 		&addDiffMatching{},
+		&addBodyRetention{},
+		&addPrStateFilters{},
+		&addFindingBugCorrelation{},
+		&addAnonymization{},
+		&addIssueRefs{},
+		&addToolVersion{},
+		&addSourcePlatforms{},
+		&addPreserveHtmlTools{},
+		&addBotReplyFilter{},
+		&addReviewSlo{},
+		&addFindingCategory{},
+		&addTrendAlerts{},
+		&addPrDescriptions{},
+		&addPatternCatalogVersion{},
+		&addApprovalGating{},
+		&addDataScopes{},
+		&addRiskTermMappings{},
+		&addCopilotConfig{},
+		&addPredictionMetricsAggregate{},
+		&addReviewSloMissed{},
 	}
 }
@@ -0,0 +1,178 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+// PatternCatalogVersion is the newest version in PatternCatalog. Bump it with every change to
+// the shipped defaults, so scope configs applied from an older version show up as outdated.
+const PatternCatalogVersion = 2
+
+// PatternCatalogEntry is a default value shipped for a detection pattern field
+type PatternCatalogEntry struct {
+	Version int    `json:"version"` // Catalog version that shipped the value
+	Tool    string `json:"tool"`    // AI tool the pattern detects, empty for patterns shared by all tools
+	Field   string `json:"field"`   // JSON name of the AiReviewScopeConfig field
+	Value   string `json:"value"`
+}
+
+// PatternCatalog lists every default ever shipped for the detection patterns, oldest first.
+// When a vendor changes its comment format, append an entry with the new value under a new
+// PatternCatalogVersion instead of editing the old one: a scope config still holding a
+// previous default is then known to be untouched by its users and can be upgraded.
+var PatternCatalog = []PatternCatalogEntry{
+	{Version: 1, Tool: AiToolCodeRabbit, Field: "codeRabbitUsername", Value: "coderabbitai"},
+	{Version: 1, Tool: AiToolCodeRabbit, Field: "codeRabbitPattern", Value: `(?i)(coderabbit|walkthrough|summary by coderabbit)`},
+	{Version: 1, Tool: AiToolCursorBugbot, Field: "cursorBugbotUsername", Value: "cursor-bugbot"},
+	{Version: 1, Tool: AiToolCursorBugbot, Field: "cursorBugbotPattern", Value: `(?i)(cursor|bugbot)`},
+	{Version: 1, Tool: AiToolQodo, Field: "qodoUsername", Value: "qodo-merge"},
+	{Version: 1, Tool: AiToolQodo, Field: "qodoPattern", Value: `(?i)(qodo|pr reviewer guide|estimated effort to review)`},
+	{Version: 1, Tool: AiToolGemini, Field: "geminiUsername", Value: "gemini-code-assist"},
+	{Version: 1, Tool: AiToolGemini, Field: "geminiPattern", Value: `(?i)(I'm Gemini Code Assist|codereviewagent|gstatic\.com/codereviewagent)`},
+	{Version: 1, Field: "aiCommitPatterns", Value: `(?i)(generated by|co-authored-by:.*ai|copilot|claude|gpt)`},
+	{Version: 1, Field: "aiPrLabelPattern", Value: `(?i)(ai-reviewed|coderabbit|automated-review)`},
+	{Version: 1, Field: "riskHighPattern", Value: `(?i)(critical|security|breaking|major)`},
+	{Version: 1, Field: "riskMediumPattern", Value: `(?i)(warning|medium|moderate)`},
+	{Version: 1, Field: "riskLowPattern", Value: `(?i)(minor|low|info|suggestion)`},
+	{Version: 1, Field: "bugLinkPattern", Value: `(?i)(fixes|closes|resolves)\s*#(\d+)`},
+	{Version: 1, Field: "botUsernamePattern", Value: `(?i)(-robot$|^openshift-ci$)`},
+	{Version: 2, Tool: AiToolCopilot, Field: "copilotUsername", Value: "copilot-pull-request-reviewer"},
+	{Version: 2, Tool: AiToolCopilot, Field: "copilotPattern", Value: `(?i)(copilot reviewed \d+ out of \d+ changed files|pull request overview)`},
+}
+
+// PatternField returns the scope config field holding the given catalog field, or nil if
+// the field is not a catalog pattern
+func (c *AiReviewScopeConfig) PatternField(field string) *string {
+	switch field {
+	case "codeRabbitUsername":
+		return &c.CodeRabbitUsername
+	case "codeRabbitPattern":
+		return &c.CodeRabbitPattern
+	case "cursorBugbotUsername":
+		return &c.CursorBugbotUsername
+	case "cursorBugbotPattern":
+		return &c.CursorBugbotPattern
+	case "qodoUsername":
+		return &c.QodoUsername
+	case "qodoPattern":
+		return &c.QodoPattern
+	case "geminiUsername":
+		return &c.GeminiUsername
+	case "geminiPattern":
+		return &c.GeminiPattern
+	case "copilotUsername":
+		return &c.CopilotUsername
+	case "copilotPattern":
+		return &c.CopilotPattern
+	case "aiCommitPatterns":
+		return &c.AiCommitPatterns
+	case "aiPrLabelPattern":
+		return &c.AiPrLabelPattern
+	case "aiPrDescriptionPattern":
+		return &c.AiPrDescriptionPattern
+	case "riskHighPattern":
+		return &c.RiskHighPattern
+	case "riskMediumPattern":
+		return &c.RiskMediumPattern
+	case "riskLowPattern":
+		return &c.RiskLowPattern
+	case "bugLinkPattern":
+		return &c.BugLinkPattern
+	case "botUsernamePattern":
+		return &c.BotUsernamePattern
+	}
+	return nil
+}
+
+// PatternCatalogChange is the outcome of applying the catalog to one scope config field
+type PatternCatalogChange struct {
+	Tool     string `json:"tool"`
+	Field    string `json:"field"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// PatternCatalogResult lists the fields upgraded to the latest default and the fields kept
+// because their value was set by the users of the scope config
+type PatternCatalogResult struct {
+	FromVersion int                    `json:"fromVersion"`
+	ToVersion   int                    `json:"toVersion"`
+	Updated     []PatternCatalogChange `json:"updated"`
+	Preserved   []PatternCatalogChange `json:"preserved"`
+}
+
+// ApplyPatternCatalog upgrades the pattern fields of the config still holding a default of
+// the catalog to the latest default. Any other value is a user override and is kept; an
+// empty field is only filled when the config predates the catalog version that introduced
+// it, otherwise it was cleared on purpose. The config is stamped with the newest version.
+func ApplyPatternCatalog(config *AiReviewScopeConfig, catalog []PatternCatalogEntry) *PatternCatalogResult {
+	result := &PatternCatalogResult{
+		FromVersion: config.PatternCatalogVersion,
+		Updated:     []PatternCatalogChange{},
+		Preserved:   []PatternCatalogChange{},
+	}
+
+	// The first and latest default of each field, and every value it ever shipped with
+	var fields []string
+	introduced := make(map[string]int)
+	latest := make(map[string]PatternCatalogEntry)
+	shipped := make(map[string]map[string]bool)
+	for _, entry := range catalog {
+		if entry.Version > result.ToVersion {
+			result.ToVersion = entry.Version
+		}
+		current, seen := latest[entry.Field]
+		if !seen {
+			fields = append(fields, entry.Field)
+			introduced[entry.Field] = entry.Version
+			shipped[entry.Field] = make(map[string]bool)
+		}
+		if entry.Version < introduced[entry.Field] {
+			introduced[entry.Field] = entry.Version
+		}
+		if !seen || entry.Version >= current.Version {
+			latest[entry.Field] = entry
+		}
+		shipped[entry.Field][entry.Value] = true
+	}
+
+	for _, field := range fields {
+		value := config.PatternField(field)
+		if value == nil {
+			continue
+		}
+		entry := latest[field]
+		if *value == entry.Value {
+			continue
+		}
+		change := PatternCatalogChange{Tool: entry.Tool, Field: field, OldValue: *value, NewValue: entry.Value}
+		userValue := !shipped[field][*value]
+		if *value == "" {
+			userValue = config.PatternCatalogVersion >= introduced[field]
+		}
+		if userValue {
+			result.Preserved = append(result.Preserved, change)
+			continue
+		}
+		*value = entry.Value
+		result.Updated = append(result.Updated, change)
+	}
+
+	if result.ToVersion > config.PatternCatalogVersion {
+		config.PatternCatalogVersion = result.ToVersion
+	}
+	return result
+}
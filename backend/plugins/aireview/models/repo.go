@@ -0,0 +1,67 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.ToolLayerScope = (*AiReviewRepo)(nil)
+
+// AiReviewRepo is a repository analyzed by aireview, picked from the repos collected
+// by the github or gitlab plugin. Its scope config holds the detection patterns.
+type AiReviewRepo struct {
+	common.Scope `mapstructure:",squash"`
+
+	// Domain layer repo id, e.g. "github:GithubRepo:1:384111310"
+	Id   string `json:"id" mapstructure:"id" gorm:"primaryKey;type:varchar(255)" validate:"required"`
+	Name string `json:"name" mapstructure:"name" gorm:"type:varchar(255)"`
+	Url  string `json:"url" mapstructure:"url" gorm:"type:varchar(255)"`
+}
+
+func (AiReviewRepo) TableName() string {
+	return "_tool_aireview_repos"
+}
+
+func (r AiReviewRepo) ScopeId() string {
+	return r.Id
+}
+
+func (r AiReviewRepo) ScopeName() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Id
+}
+
+func (r AiReviewRepo) ScopeFullName() string {
+	return r.ScopeName()
+}
+
+func (r AiReviewRepo) ScopeParams() interface{} {
+	return &AiReviewRepoParams{
+		ConnectionId: r.ConnectionId,
+		RepoId:       r.Id,
+	}
+}
+
+// AiReviewRepoParams identifies a repo scope in blueprints
+type AiReviewRepoParams struct {
+	ConnectionId uint64 `json:"connectionId"`
+	RepoId       string `json:"repoId"`
+}
@@ -21,6 +21,23 @@ import (
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
+// SourcePlatformRule maps the prefix of DevLake pull request and comment ids to a platform, for
+// instances whose github/gitlab plugin is registered under a custom name
+type SourcePlatformRule struct {
+	Prefix             string `mapstructure:"prefix" json:"prefix"`                         // e.g. "gitlab-internal:"
+	Platform           string `mapstructure:"platform" json:"platform"`                     // github, gitlab, bitbucket or azuredevops
+	CommentUrlTemplate string `mapstructure:"commentUrlTemplate" json:"commentUrlTemplate"` // e.g. "{prUrl}#note_{commentId}"; the platform's anchor if empty
+}
+
+// RiskTermMapping maps a severity or risk term an AI tool writes in its findings, e.g.
+// CodeRabbit's "Nitpick", to the normalized finding category and severity
+type RiskTermMapping struct {
+	AiTool   string `mapstructure:"aiTool" json:"aiTool"`     // e.g. "coderabbit"; any tool if empty
+	Term     string `mapstructure:"term" json:"term"`         // matched case-insensitively in the finding text
+	Category string `mapstructure:"category" json:"category"` // e.g. "style"; the detected category if empty
+	Severity string `mapstructure:"severity" json:"severity"` // e.g. "info"; the detected severity if empty
+}
+
 // AiReviewScopeConfig contains configuration for AI review extraction
 type AiReviewScopeConfig struct {
 	common.ScopeConfig `mapstructure:",squash" json:",inline" gorm:"embedded"`
@@ -45,10 +62,19 @@ type AiReviewScopeConfig struct {
 	GeminiUsername string `mapstructure:"geminiUsername" json:"geminiUsername" gorm:"type:varchar(255)"`
 	GeminiPattern  string `mapstructure:"geminiPattern" json:"geminiPattern" gorm:"type:varchar(500)"`
 
+	// GitHub Copilot code review detection patterns
+	CopilotEnabled  bool   `mapstructure:"copilotEnabled" json:"copilotEnabled" gorm:"type:boolean"`
+	CopilotUsername string `mapstructure:"copilotUsername" json:"copilotUsername" gorm:"type:varchar(255)"`
+	CopilotPattern  string `mapstructure:"copilotPattern" json:"copilotPattern" gorm:"type:varchar(500)"`
+
 	// Generic AI detection patterns (for commit messages, PR descriptions)
 	AiCommitPatterns string `mapstructure:"aiCommitPatterns" json:"aiCommitPatterns" gorm:"type:text"` // Comma-separated patterns
 	AiPrLabelPattern string `mapstructure:"aiPrLabelPattern" json:"aiPrLabelPattern" gorm:"type:varchar(500)"`
 
+	// AiPrDescriptionPattern marks PR descriptions generated by tools without a
+	// built-in marker; matching descriptions are attributed to the "other" tool
+	AiPrDescriptionPattern string `mapstructure:"aiPrDescriptionPattern" json:"aiPrDescriptionPattern" gorm:"type:varchar(500)"`
+
 	// Risk detection configuration
 	RiskHighPattern   string `mapstructure:"riskHighPattern" json:"riskHighPattern" gorm:"type:varchar(500)"`
 	RiskMediumPattern string `mapstructure:"riskMediumPattern" json:"riskMediumPattern" gorm:"type:varchar(500)"`
@@ -89,8 +115,72 @@ type AiReviewScopeConfig struct {
 	// 0 (the default) disables backfill. The task derives enabled/disabled from
 	// this value: CiBackfillDays > 0 means backfill is active.
 	CiBackfillDays int `mapstructure:"ciBackfillDays" json:"ciBackfillDays" gorm:"default:0"`
+
+	// ExcludeDraftPrs skips AI reviews on draft PRs during extraction, so
+	// reviews of work-in-progress changes don't skew stats. Off by default.
+	ExcludeDraftPrs bool `mapstructure:"excludeDraftPrs" json:"excludeDraftPrs" gorm:"type:boolean;default:false"`
+
+	// ExcludeClosedUnmergedPrs skips AI reviews on PRs that were closed
+	// without being merged. Open and merged PRs are always extracted. Off by default.
+	ExcludeClosedUnmergedPrs bool `mapstructure:"excludeClosedUnmergedPrs" json:"excludeClosedUnmergedPrs" gorm:"type:boolean;default:false"`
+
+	// BodyRetentionDays truncates the stored body of reviews older than this
+	// many days, keeping summary and metrics intact. 0 (the default) keeps
+	// bodies forever.
+	BodyRetentionDays int `mapstructure:"bodyRetentionDays" json:"bodyRetentionDays" gorm:"default:0"`
+
+	// AnonymizeEnabled hashes PR author / account names and strips code
+	// snippets from stored review bodies, summaries and findings, so
+	// installations with privacy constraints can still collect aggregate
+	// metrics. Off by default.
+	AnonymizeEnabled bool `mapstructure:"anonymizeEnabled" json:"anonymizeEnabled" gorm:"type:boolean;default:false"`
+
+	// SourcePlatforms are matched before the raw tables and repo URL of the PR to detect
+	// its platform and build links to its comments, for self-hosted deployments whose
+	// DevLake ids carry a custom plugin name
+	SourcePlatforms []SourcePlatformRule `mapstructure:"sourcePlatforms" json:"sourcePlatforms" gorm:"type:json;serializer:json"`
+
+	// RiskTermMappings are matched before the built-in terms of each tool to normalize
+	// the category and severity of findings, so findings of different tools compare
+	RiskTermMappings []RiskTermMapping `mapstructure:"riskTermMappings" json:"riskTermMappings" gorm:"type:json;serializer:json"`
+
+	// PreserveHtmlTools lists the AI tools (comma-separated, e.g. "qodo,gemini") whose
+	// summaries are extracted from the raw HTML body instead of its markdown
+	// conversion, for tools whose markup the conversion mangles
+	PreserveHtmlTools string `mapstructure:"preserveHtmlTools" json:"preserveHtmlTools" gorm:"type:varchar(255)"`
+
+	// ExcludeBotReplies skips AI comments replying to a bot (another AI tool
+	// or a CI bot), so tools talking to each other don't count as reviews.
+	// Off by default.
+	ExcludeBotReplies bool `mapstructure:"excludeBotReplies" json:"excludeBotReplies" gorm:"type:boolean;default:false"`
+
+	// BotUsernamePattern matches CI bot accounts that don't end in "[bot]",
+	// e.g. "openshift-ci-robot". Enabled AI tools always count as bots.
+	BotUsernamePattern string `mapstructure:"botUsernamePattern" json:"botUsernamePattern" gorm:"type:varchar(500)"`
+
+	// ReviewSloMinutes is the AI review response time SLO: a PR open or push
+	// should get an AI review within this many minutes. Default 10.
+	ReviewSloMinutes int `mapstructure:"reviewSloMinutes" json:"reviewSloMinutes" gorm:"default:0"`
+
+	// AiApprovalRequired is set for repos whose branch protection requires an
+	// approving AI review, so a push after the approval needs a new one. It
+	// enables the approval gating metrics. Off by default.
+	AiApprovalRequired bool `mapstructure:"aiApprovalRequired" json:"aiApprovalRequired" gorm:"type:boolean;default:false"`
+
+	// PatternCatalogVersion is the version of PatternCatalog whose defaults were last applied
+	// to the detection patterns. 0 for scope configs created before the catalog existed.
+	PatternCatalogVersion int `mapstructure:"patternCatalogVersion" json:"patternCatalogVersion" gorm:"default:0"`
 }
 
+// Source platform constants
+const (
+	SourcePlatformGithub      = "github"
+	SourcePlatformGitlab      = "gitlab"
+	SourcePlatformBitbucket   = "bitbucket"
+	SourcePlatformAzureDevops = "azuredevops"
+	SourcePlatformUnknown     = "unknown"
+)
+
 // CI failure source constants
 const (
 	CiSourceTestCases = "test_cases" // Use ci_test_cases with flaky-test quarantine
@@ -102,29 +192,20 @@ func (AiReviewScopeConfig) TableName() string {
 	return "_tool_aireview_scope_configs"
 }
 
-// GetDefaultScopeConfig returns a scope config with sensible defaults
+// GetDefaultScopeConfig returns a scope config with sensible defaults, its detection
+// patterns being the latest defaults of PatternCatalog
 func GetDefaultScopeConfig() *AiReviewScopeConfig {
-	return &AiReviewScopeConfig{
+	config := &AiReviewScopeConfig{
 		CodeRabbitEnabled:     true,
-		CodeRabbitUsername:    "coderabbitai",
-		CodeRabbitPattern:     `(?i)(coderabbit|walkthrough|summary by coderabbit)`,
 		CursorBugbotEnabled:   false,
-		CursorBugbotUsername:  "cursor-bugbot",
-		CursorBugbotPattern:   `(?i)(cursor|bugbot)`,
 		QodoEnabled:           true,
-		QodoUsername:          "qodo-merge",
-		QodoPattern:           `(?i)(qodo|pr reviewer guide|estimated effort to review)`,
 		GeminiEnabled:         true,
-		GeminiUsername:        "gemini-code-assist",
-		GeminiPattern:         `(?i)(I'm Gemini Code Assist|codereviewagent|gstatic\.com/codereviewagent)`,
-		AiCommitPatterns:      `(?i)(generated by|co-authored-by:.*ai|copilot|claude|gpt)`,
-		AiPrLabelPattern:      `(?i)(ai-reviewed|coderabbit|automated-review)`,
-		RiskHighPattern:       `(?i)(critical|security|breaking|major)`,
-		RiskMediumPattern:     `(?i)(warning|medium|moderate)`,
-		RiskLowPattern:        `(?i)(minor|low|info|suggestion)`,
+		CopilotEnabled:        true,
 		ObservationWindowDays: 14,
 		WarningThreshold:      50,
 		CiFailureSource:       CiSourceBoth,
-		BugLinkPattern:        `(?i)(fixes|closes|resolves)\s*#(\d+)`,
+		ReviewSloMinutes:      10,
 	}
+	ApplyPatternCatalog(config, PatternCatalog)
+	return config
 }
@@ -0,0 +1,194 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// anonymizedAccountPrefix marks hashed account names so dashboards can tell
+// them apart from real logins.
+const anonymizedAccountPrefix = "anon-"
+
+const removedCodeMarker = "_[code removed]_"
+
+// codeBlockRe matches fenced code blocks, including a fence left open at the
+// end of the text (bodies are sometimes cut off mid-block).
+var codeBlockRe = regexp.MustCompile("(?s)```.*?(?:```|$)")
+
+var AnonymizeAiReviewsMeta = plugin.SubTaskMeta{
+	Name:             "anonymizeAiReviews",
+	EntryPoint:       AnonymizeAiReviews,
+	EnabledByDefault: true,
+	Description:      "Strip code snippets from stored AI reviews and findings when anonymization is enabled",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&MatchSuggestionDiffsMeta},
+}
+
+// AnonymizeAiReviews removes code snippets from review bodies, summaries and
+// findings, and hashes the account that resolved a finding. Runs after
+// suggestion matching and bug correlation, which need the original code; both
+// start again from the full body re-extracted from pull_request_comments on
+// every run. PR authors are hashed where they are loaded, in
+// calculateFailurePredictions.
+func AnonymizeAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	config := data.Options.ScopeConfig
+	if config == nil || !config.AnonymizeEnabled {
+		logger.Info("anonymizeAiReviews: skipping — anonymizeEnabled is not set")
+		return nil
+	}
+
+	reviews, err := anonymizeReviews(db, data.Options)
+	if err != nil {
+		return err
+	}
+	findings, err := anonymizeFindings(db, data.Options)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("anonymizeAiReviews: anonymized %d reviews and %d findings", reviews, findings)
+	return nil
+}
+
+// anonymizeScopeClauses restricts a query on an aireview tool table to the
+// repo or project being processed.
+func anonymizeScopeClauses(table string, op *AiReviewOptions) []dal.Clause {
+	if op.ProjectName != "" {
+		return append([]dal.Clause{
+			dal.Join("JOIN project_mapping pm ON " + table + ".repo_id = pm.row_id AND pm.`table` = 'repos'"),
+			dal.Where("pm.project_name = ?", op.ProjectName),
+		}, excludedRepoClauses(table+".repo_id", op.ExcludeRepoIds)...)
+	}
+	return []dal.Clause{dal.Where(table+".repo_id = ?", op.RepoId)}
+}
+
+func anonymizeReviews(db dal.Dal, op *AiReviewOptions) (int, errors.Error) {
+	clauses := append([]dal.Clause{
+		dal.Select("_tool_aireview_reviews.id, _tool_aireview_reviews.body, _tool_aireview_reviews.summary"),
+		dal.From(&models.AiReview{}),
+	}, anonymizeScopeClauses("_tool_aireview_reviews", op)...)
+
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return 0, errors.Default.Wrap(err, "failed to query AI reviews for anonymization")
+	}
+	defer cursor.Close()
+
+	updated := 0
+	for cursor.Next() {
+		var review models.AiReview
+		if err := db.Fetch(cursor, &review); err != nil {
+			return updated, errors.Default.Wrap(err, "failed to fetch AI review")
+		}
+
+		body := stripCodeSnippets(review.Body)
+		summary := stripCodeSnippets(review.Summary)
+		if body == review.Body && summary == review.Summary {
+			continue
+		}
+		err := db.UpdateColumns(&models.AiReview{}, []dal.DalSet{
+			{ColumnName: "body", Value: body},
+			{ColumnName: "summary", Value: summary},
+		}, dal.Where("id = ?", review.Id))
+		if err != nil {
+			return updated, errors.Default.Wrap(err, "failed to anonymize AI review")
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+func anonymizeFindings(db dal.Dal, op *AiReviewOptions) (int, errors.Error) {
+	clauses := append([]dal.Clause{
+		dal.Select("_tool_aireview_findings.id, _tool_aireview_findings.description," +
+			" _tool_aireview_findings.code_snippet, _tool_aireview_findings.suggested_code, _tool_aireview_findings.resolved_by"),
+		dal.From(&models.AiReviewFinding{}),
+	}, anonymizeScopeClauses("_tool_aireview_findings", op)...)
+
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return 0, errors.Default.Wrap(err, "failed to query AI review findings for anonymization")
+	}
+	defer cursor.Close()
+
+	updated := 0
+	for cursor.Next() {
+		var finding models.AiReviewFinding
+		if err := db.Fetch(cursor, &finding); err != nil {
+			return updated, errors.Default.Wrap(err, "failed to fetch AI review finding")
+		}
+
+		description := stripCodeSnippets(finding.Description)
+		resolvedBy := anonymizeAccount(finding.ResolvedBy)
+		if description == finding.Description && resolvedBy == finding.ResolvedBy &&
+			finding.CodeSnippet == "" && finding.SuggestedCode == "" {
+			continue
+		}
+		err := db.UpdateColumns(&models.AiReviewFinding{}, []dal.DalSet{
+			{ColumnName: "description", Value: description},
+			{ColumnName: "code_snippet", Value: ""},
+			{ColumnName: "suggested_code", Value: ""},
+			{ColumnName: "resolved_by", Value: resolvedBy},
+		}, dal.Where("id = ?", finding.Id))
+		if err != nil {
+			return updated, errors.Default.Wrap(err, "failed to anonymize AI review finding")
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// anonymizeAccount replaces an account name with a stable pseudonym, so
+// per-author aggregates still work without storing who the author is.
+// Empty and already anonymized values are returned unchanged.
+func anonymizeAccount(account string) string {
+	if account == "" || isAnonymizedAccount(account) {
+		return account
+	}
+	hash := sha256.Sum256([]byte(account))
+	return anonymizedAccountPrefix + hex.EncodeToString(hash[:8])
+}
+
+func isAnonymizedAccount(account string) bool {
+	if len(account) != len(anonymizedAccountPrefix)+16 || account[:len(anonymizedAccountPrefix)] != anonymizedAccountPrefix {
+		return false
+	}
+	_, err := hex.DecodeString(account[len(anonymizedAccountPrefix):])
+	return err == nil
+}
+
+// stripCodeSnippets replaces every fenced code block (including ```suggestion
+// and ```diff blocks) with a marker, keeping the surrounding prose.
+func stripCodeSnippets(text string) string {
+	if text == "" {
+		return text
+	}
+	return codeBlockRe.ReplaceAllString(text, removedCodeMarker)
+}
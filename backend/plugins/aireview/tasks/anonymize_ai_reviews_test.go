@@ -0,0 +1,143 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnonymizeAccount(t *testing.T) {
+	t.Run("empty stays empty", func(t *testing.T) {
+		assert.Equal(t, "", anonymizeAccount(""))
+	})
+
+	t.Run("hash is stable and hides the login", func(t *testing.T) {
+		got := anonymizeAccount("octocat")
+		assert.Equal(t, got, anonymizeAccount("octocat"))
+		assert.NotEqual(t, got, anonymizeAccount("hubot"))
+		assert.True(t, strings.HasPrefix(got, anonymizedAccountPrefix))
+		assert.NotContains(t, got, "octocat")
+	})
+
+	t.Run("already anonymized values are kept", func(t *testing.T) {
+		once := anonymizeAccount("octocat")
+		assert.Equal(t, once, anonymizeAccount(once))
+	})
+}
+
+func TestStripCodeSnippets(t *testing.T) {
+	t.Run("prose unchanged", func(t *testing.T) {
+		assert.Equal(t, "Consider renaming `x`.", stripCodeSnippets("Consider renaming `x`."))
+	})
+
+	t.Run("fenced blocks replaced", func(t *testing.T) {
+		body := "Use a constant:\n```suggestion\nconst limit = 10\n```\nand\n```go\nfmt.Println(limit)\n```\ndone"
+		assert.Equal(t, "Use a constant:\n"+removedCodeMarker+"\nand\n"+removedCodeMarker+"\ndone", stripCodeSnippets(body))
+	})
+
+	t.Run("unterminated block removed to the end", func(t *testing.T) {
+		assert.Equal(t, "Try:\n"+removedCodeMarker, stripCodeSnippets("Try:\n```diff\n- a\n+ b"))
+	})
+
+	t.Run("idempotent", func(t *testing.T) {
+		once := stripCodeSnippets("a\n```\nb\n```")
+		assert.Equal(t, once, stripCodeSnippets(once))
+	})
+}
+
+func TestAnonymizeAiReviews_Disabled(t *testing.T) {
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDalI := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+
+	data := &AiReviewTaskData{
+		Options: &AiReviewOptions{RepoId: "repo-1", ScopeConfig: models.GetDefaultScopeConfig()},
+	}
+
+	mockCtx.On("GetDal").Return(mockDalI)
+	mockCtx.On("GetLogger").Return(mockLogger)
+	mockCtx.On("GetData").Return(data)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	err := AnonymizeAiReviews(mockCtx)
+	assert.Nil(t, err)
+	mockDalI.AssertNotCalled(t, "Cursor", mock.Anything)
+}
+
+func TestAnonymizeAiReviews_ScrubsReviewsAndFindings(t *testing.T) {
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDalI := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+	reviewRows := new(mockdal.Rows)
+	findingRows := new(mockdal.Rows)
+
+	config := models.GetDefaultScopeConfig()
+	config.AnonymizeEnabled = true
+	data := &AiReviewTaskData{
+		Options: &AiReviewOptions{RepoId: "repo-1", ScopeConfig: config},
+	}
+
+	mockCtx.On("GetDal").Return(mockDalI)
+	mockCtx.On("GetLogger").Return(mockLogger)
+	mockCtx.On("GetData").Return(data)
+	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	mockDalI.On("Cursor", mock.Anything).Return(reviewRows, nil).Once()
+	mockDalI.On("Cursor", mock.Anything).Return(findingRows, nil).Once()
+	for _, rows := range []*mockdal.Rows{reviewRows, findingRows} {
+		rows.On("Next").Return(true).Once()
+		rows.On("Next").Return(false)
+		rows.On("Close").Return(nil)
+	}
+	mockDalI.On("Fetch", reviewRows, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*models.AiReview) = models.AiReview{Id: "review-1", Body: "Fix:\n```go\nsecret()\n```", Summary: "short"}
+	}).Return(nil)
+	mockDalI.On("Fetch", findingRows, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*models.AiReviewFinding) = models.AiReviewFinding{Id: "finding-1", Description: "ok", SuggestedCode: "secret()", ResolvedBy: "octocat"}
+	}).Return(nil)
+
+	updates := map[string]map[string]interface{}{}
+	mockDalI.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		cols := map[string]interface{}{}
+		for _, set := range args.Get(1).([]dal.DalSet) {
+			cols[set.ColumnName] = set.Value
+		}
+		switch args.Get(0).(type) {
+		case *models.AiReview:
+			updates["review"] = cols
+		case *models.AiReviewFinding:
+			updates["finding"] = cols
+		}
+	}).Return(nil)
+
+	err := AnonymizeAiReviews(mockCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, "Fix:\n"+removedCodeMarker, updates["review"]["body"])
+	assert.Equal(t, "short", updates["review"]["summary"])
+	assert.Equal(t, "", updates["finding"]["suggested_code"])
+	assert.Equal(t, anonymizeAccount("octocat"), updates["finding"]["resolved_by"])
+}
@@ -0,0 +1,150 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+)
+
+// githubReviewCommentsRawTable holds GitHub review comments, whose raw JSON carries in_reply_to_id
+const githubReviewCommentsRawTable = "_raw_github_api_pull_request_review_comments"
+
+// leadingMentionRe matches the user a comment opens by addressing, e.g. "@coderabbitai ..."
+var leadingMentionRe = regexp.MustCompile(`^\s*@([A-Za-z0-9][A-Za-z0-9_.\-]*(?:\[bot\])?)`)
+
+// botReplyIds returns the ids of the AI comments replying to a comment written by a bot,
+// i.e. another AI tool or a CI bot. The replied-to author comes from the in_reply_to_id
+// of GitHub review comments, else from a leading @mention.
+func botReplyIds(db dal.Dal, data *AiReviewTaskData, comments []*code.PullRequestComment) (map[string]bool, errors.Error) {
+	parentIds, err := githubReplyParentIds(db, comments)
+	if err != nil {
+		return nil, err
+	}
+	parentUsernames, err := commentUsernames(db, parentIds)
+	if err != nil {
+		return nil, err
+	}
+	replies := make(map[string]bool)
+	for _, comment := range comments {
+		parent := parentUsernames[parentIds[comment.Id]]
+		if parent == "" {
+			if match := leadingMentionRe.FindStringSubmatch(comment.Body); match != nil {
+				parent = match[1]
+			}
+		}
+		if parent != "" && isBotUsername(data, parent) {
+			replies[comment.Id] = true
+		}
+	}
+	return replies, nil
+}
+
+// githubReplyParentIds returns the domain id of the GitHub review comment each comment
+// replies to, keyed by comment id. Comments that are no reply or weren't collected from
+// GitHub review comments are left out. The raw JSON is parsed here, the tool layer
+// doesn't keep in_reply_to_id.
+func githubReplyParentIds(db dal.Dal, comments []*code.PullRequestComment) (map[string]string, errors.Error) {
+	byRawId := make(map[uint64][]*code.PullRequestComment)
+	rawIds := make([]uint64, 0, len(comments))
+	for _, comment := range comments {
+		if comment.RawDataTable != githubReviewCommentsRawTable || comment.RawDataId == 0 || !strings.Contains(comment.Id, ":") {
+			continue
+		}
+		if _, ok := byRawId[comment.RawDataId]; !ok {
+			rawIds = append(rawIds, comment.RawDataId)
+		}
+		byRawId[comment.RawDataId] = append(byRawId[comment.RawDataId], comment)
+	}
+	parentIds := make(map[string]string)
+	if len(rawIds) == 0 {
+		return parentIds, nil
+	}
+	var rows []struct {
+		Id   uint64
+		Data []byte
+	}
+	err := db.All(&rows,
+		dal.Select("id, data"),
+		dal.From(githubReviewCommentsRawTable),
+		dal.Where("id IN ?", rawIds),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load the raw GitHub review comments")
+	}
+	for _, row := range rows {
+		var raw struct {
+			InReplyToId json.Number `json:"in_reply_to_id"`
+		}
+		if jsonErr := json.Unmarshal(row.Data, &raw); jsonErr != nil || raw.InReplyToId == "" {
+			continue
+		}
+		for _, comment := range byRawId[row.Id] {
+			// Parent and reply share the "github:GithubPrComment:<connectionId>:" prefix
+			parentIds[comment.Id] = comment.Id[:strings.LastIndex(comment.Id, ":")+1] + raw.InReplyToId.String()
+		}
+	}
+	return parentIds, nil
+}
+
+// commentUsernames returns the author of the given comments, keyed by comment id
+func commentUsernames(db dal.Dal, commentIds map[string]string) (map[string]string, errors.Error) {
+	usernames := make(map[string]string)
+	if len(commentIds) == 0 {
+		return usernames, nil
+	}
+	ids := make([]string, 0, len(commentIds))
+	for _, id := range commentIds {
+		ids = append(ids, id)
+	}
+	var rows []struct {
+		Id       string
+		Username string
+	}
+	err := db.All(&rows,
+		dal.Select("prc.id, COALESCE(NULLIF(a.user_name, ''), prc.account_id) AS username"),
+		dal.From("pull_request_comments prc"),
+		dal.Join("LEFT JOIN accounts a ON prc.account_id = a.id"),
+		dal.Where("prc.id IN ?", ids),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load the authors of the replied-to comments")
+	}
+	for _, row := range rows {
+		usernames[row.Id] = row.Username
+	}
+	return usernames, nil
+}
+
+// isBotUsername reports whether a username belongs to a bot: a "[bot]" app account,
+// an enabled AI tool or an account matching the scope config botUsernamePattern
+func isBotUsername(data *AiReviewTaskData, username string) bool {
+	if len(username) > len("[bot]") && strings.EqualFold(username[len(username)-len("[bot]"):], "[bot]") {
+		return true
+	}
+	if data.BotUsernamePatternRegex != nil && data.BotUsernamePatternRegex.MatchString(username) {
+		return true
+	}
+	_, isAiTool := detectAiTool(data, username, "")
+	return isAiTool
+}
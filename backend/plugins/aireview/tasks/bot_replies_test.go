@@ -0,0 +1,170 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newBotReplyTaskData(t *testing.T) *AiReviewTaskData {
+	t.Helper()
+	data := &AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: models.GetDefaultScopeConfig()}}
+	assert.Nil(t, CompilePatterns(data))
+	return data
+}
+
+// githubReviewReply is a GitHub review comment collected into the review comments raw table
+func githubReviewReply(body string) *code.PullRequestComment {
+	return &code.PullRequestComment{
+		DomainEntity: domainlayer.DomainEntity{
+			Id: "github:GithubPrComment:1:200",
+			NoPKModel: common.NoPKModel{RawDataOrigin: common.RawDataOrigin{
+				RawDataTable: githubReviewCommentsRawTable,
+				RawDataId:    42,
+			}},
+		},
+		Body: body,
+	}
+}
+
+func TestIsBotUsername(t *testing.T) {
+	data := newBotReplyTaskData(t)
+
+	assert.True(t, isBotUsername(data, "red-hat-konflux[bot]"))
+	assert.True(t, isBotUsername(data, "Renovate[BOT]"))
+	assert.True(t, isBotUsername(data, "openshift-ci-robot"))
+	assert.True(t, isBotUsername(data, "openshift-ci"))
+	assert.True(t, isBotUsername(data, "coderabbitai"))
+	assert.True(t, isBotUsername(data, "gemini-code-assist"))
+	assert.False(t, isBotUsername(data, "alice"))
+	assert.False(t, isBotUsername(data, "[bot]"))
+}
+
+func TestGithubReplyParentIds(t *testing.T) {
+	t.Run("parent id keeps the connection prefix", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			rows := args.Get(0).(*[]struct {
+				Id   uint64
+				Data []byte
+			})
+			*rows = append(*rows, struct {
+				Id   uint64
+				Data []byte
+			}{Id: 42, Data: []byte(`{"id":200,"in_reply_to_id":100}`)})
+		}).Return(nil).Once()
+
+		parentIds, err := githubReplyParentIds(mockDal, []*code.PullRequestComment{githubReviewReply("")})
+		assert.Nil(t, err)
+		assert.Equal(t, map[string]string{"github:GithubPrComment:1:200": "github:GithubPrComment:1:100"}, parentIds)
+	})
+
+	t.Run("top-level review comment has no parent", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			rows := args.Get(0).(*[]struct {
+				Id   uint64
+				Data []byte
+			})
+			*rows = append(*rows, struct {
+				Id   uint64
+				Data []byte
+			}{Id: 42, Data: []byte(`{"id":200,"in_reply_to_id":null}`)})
+		}).Return(nil).Once()
+
+		parentIds, err := githubReplyParentIds(mockDal, []*code.PullRequestComment{githubReviewReply("")})
+		assert.Nil(t, err)
+		assert.Empty(t, parentIds)
+	})
+
+	t.Run("raw lookup failure is returned", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("All", mock.Anything, mock.Anything).Return(errors.Default.New("no raw table"))
+
+		_, err := githubReplyParentIds(mockDal, []*code.PullRequestComment{githubReviewReply("")})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("issue comments are not looked up", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+
+		parentIds, err := githubReplyParentIds(mockDal, []*code.PullRequestComment{{Body: "@alice good catch, fixed."}})
+		assert.Nil(t, err)
+		assert.Empty(t, parentIds)
+		mockDal.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+	})
+}
+
+func TestBotReplyIds(t *testing.T) {
+	data := newBotReplyTaskData(t)
+	mockDal := new(mockdal.Dal)
+	// one raw lookup and one author lookup for the whole batch
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		rows := args.Get(0).(*[]struct {
+			Id   uint64
+			Data []byte
+		})
+		*rows = append(*rows, struct {
+			Id   uint64
+			Data []byte
+		}{Id: 42, Data: []byte(`{"id":200,"in_reply_to_id":100}`)})
+	}).Return(nil).Once()
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		rows := args.Get(0).(*[]struct {
+			Id       string
+			Username string
+		})
+		*rows = append(*rows, struct {
+			Id       string
+			Username string
+		}{Id: "github:GithubPrComment:1:100", Username: "gemini-code-assist[bot]"})
+	}).Return(nil).Once()
+
+	comments := []*code.PullRequestComment{
+		githubReviewReply("Thanks, agreed."),
+		{DomainEntity: domainlayer.DomainEntity{Id: "github:GithubPrComment:1:301"}, Body: "@coderabbitai I disagree with this suggestion."},
+		{DomainEntity: domainlayer.DomainEntity{Id: "github:GithubPrComment:1:302"}, Body: " @openshift-ci[bot] retest failed"},
+		{DomainEntity: domainlayer.DomainEntity{Id: "github:GithubPrComment:1:303"}, Body: "@alice thanks for the fix."},
+		{DomainEntity: domainlayer.DomainEntity{Id: "github:GithubPrComment:1:304"}, Body: "## Walkthrough\n\nAdds retries."},
+	}
+	replies, err := botReplyIds(mockDal, data, comments)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{
+		"github:GithubPrComment:1:200": true,
+		"github:GithubPrComment:1:301": true,
+		"github:GithubPrComment:1:302": true,
+	}, replies)
+	mockDal.AssertExpectations(t)
+}
+
+func TestCompilePatterns_InvalidBotUsernamePattern(t *testing.T) {
+	config := models.GetDefaultScopeConfig()
+	config.BotUsernamePattern = "[invalid"
+	err := CompilePatterns(&AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: config}})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "botUsernamePattern")
+}
@@ -0,0 +1,286 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var CalculateApprovalGatingMeta = plugin.SubTaskMeta{
+	Name:             "calculateApprovalGating",
+	EntryPoint:       CalculateApprovalGating,
+	EnabledByDefault: true,
+	Description:      "Compare the merge time of PRs that needed an AI re-approval with PRs merged on their first AI approval, when branch protection requires one",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractAiReviewsMeta},
+}
+
+// gatingApproval is one approving AI review of a merged PR
+type gatingApproval struct {
+	RepoId        string    `gorm:"column:repo_id"`
+	PullRequestId string    `gorm:"column:pull_request_id"`
+	AiTool        string    `gorm:"column:ai_tool"`
+	CreatedDate   time.Time `gorm:"column:created_date"`
+	PrCreatedDate time.Time `gorm:"column:pr_created_date"`
+	PrMergedDate  time.Time `gorm:"column:pr_merged_date"`
+}
+
+// gatedPullRequest is a merged PR approved by one AI tool
+type gatedPullRequest struct {
+	RepoId      string
+	AiTool      string
+	MergedAt    time.Time
+	MergeHours  float64
+	Reapprovals int
+	// Whether a push after the first approval invalidated it before the merge
+	NeededReapproval bool
+	// Hours from the invalidating push to the next approval, nil when no
+	// approval followed it
+	ReapprovalWaitHours *float64
+}
+
+// gatingMonthKey identifies one row of _tool_aireview_approval_gating_metrics
+type gatingMonthKey struct {
+	RepoId     string
+	AiTool     string
+	MonthStart time.Time
+}
+
+// CalculateApprovalGating measures what requiring an AI approval to merge
+// costs: per repo, tool and month of merge, it stores how many approved PRs
+// were pushed to after their first AI approval and the merge time of those
+// PRs against the ones merged on their first approval. It only runs when the
+// scope config sets aiApprovalRequired.
+func CalculateApprovalGating(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	if !data.Options.ScopeConfig.AiApprovalRequired {
+		logger.Info("aiApprovalRequired is not set, skipping approval gating calculation")
+		return nil
+	}
+
+	approvals, err := loadGatingApprovals(db, data.Options.RepoId, data.Options.ProjectName, data.Options.ExcludeRepoIds)
+	if err != nil {
+		return err
+	}
+	if len(approvals) == 0 {
+		logger.Info("No AI approvals of merged PRs found, skipping approval gating calculation")
+		return nil
+	}
+
+	prIds := make([]string, 0, len(approvals))
+	for _, a := range approvals {
+		prIds = append(prIds, a.PullRequestId)
+	}
+	pushes, err := loadPullRequestPushes(db, prIds)
+	if err != nil {
+		return err
+	}
+
+	prs := matchGatedPullRequests(approvals, pushes)
+	metrics := aggregateApprovalGating(prs, time.Now())
+	for _, m := range metrics {
+		if err := db.CreateOrUpdate(m); err != nil {
+			return errors.Default.Wrap(err, "failed to save approval gating metrics")
+		}
+	}
+
+	logger.Info("Calculated approval gating metrics for %d repo/tool months (%d approved PRs)", len(metrics), len(prs))
+	return nil
+}
+
+// loadGatingApprovals loads the approving AI reviews of merged PRs of the
+// repo, or of every repo of the project but excludeRepoIds when repoId is empty
+func loadGatingApprovals(db dal.Dal, repoId, projectName string, excludeRepoIds []string) ([]gatingApproval, errors.Error) {
+	clauses := []dal.Clause{
+		dal.Select("ar.repo_id, ar.pull_request_id, ar.ai_tool, ar.created_date, " +
+			"pr.created_date AS pr_created_date, pr.merged_date AS pr_merged_date"),
+		dal.From("_tool_aireview_reviews ar"),
+		dal.Join("JOIN pull_requests pr ON ar.pull_request_id = pr.id"),
+	}
+	if repoId != "" {
+		clauses = append(clauses, dal.Where("ar.repo_id = ? AND ar.review_state = ? AND pr.merged_date IS NOT NULL",
+			repoId, models.ReviewStateApproved))
+	} else {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON ar.repo_id = pm.row_id AND pm.`table` = 'repos'"),
+			dal.Where("pm.project_name = ? AND ar.review_state = ? AND pr.merged_date IS NOT NULL",
+				projectName, models.ReviewStateApproved),
+		)
+		clauses = append(clauses, excludedRepoClauses("ar.repo_id", excludeRepoIds)...)
+	}
+	var approvals []gatingApproval
+	if err := db.All(&approvals, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load AI approvals for approval gating")
+	}
+	return approvals, nil
+}
+
+// matchGatedPullRequests groups the approvals by PR and tool. A PR needed a
+// re-approval when a push came after the tool's first approval and no later
+// than the merge; the wait is measured from the first such push to the next
+// approval. Approvals after the merge are ignored. PRs are returned in the
+// order their first approval appears.
+func matchGatedPullRequests(approvals []gatingApproval, pushes map[string][]time.Time) []gatedPullRequest {
+	type prKey struct {
+		PullRequestId string
+		AiTool        string
+	}
+	byPr := make(map[prKey][]gatingApproval)
+	var order []prKey
+	for _, a := range approvals {
+		if a.CreatedDate.After(a.PrMergedDate) {
+			continue
+		}
+		key := prKey{PullRequestId: a.PullRequestId, AiTool: a.AiTool}
+		if _, ok := byPr[key]; !ok {
+			order = append(order, key)
+		}
+		byPr[key] = append(byPr[key], a)
+	}
+
+	prs := make([]gatedPullRequest, 0, len(order))
+	for _, key := range order {
+		prApprovals := byPr[key]
+		sort.Slice(prApprovals, func(i, j int) bool { return prApprovals[i].CreatedDate.Before(prApprovals[j].CreatedDate) })
+		first := prApprovals[0]
+		pr := gatedPullRequest{
+			RepoId:      first.RepoId,
+			AiTool:      first.AiTool,
+			MergedAt:    first.PrMergedDate,
+			MergeHours:  first.PrMergedDate.Sub(first.PrCreatedDate).Hours(),
+			Reapprovals: len(prApprovals) - 1,
+		}
+
+		var invalidatedAt time.Time
+		for _, p := range pushes[key.PullRequestId] {
+			if p.After(first.CreatedDate) && !p.After(first.PrMergedDate) &&
+				(invalidatedAt.IsZero() || p.Before(invalidatedAt)) {
+				invalidatedAt = p
+			}
+		}
+		if !invalidatedAt.IsZero() {
+			pr.NeededReapproval = true
+			for _, a := range prApprovals[1:] {
+				if !a.CreatedDate.Before(invalidatedAt) {
+					wait := a.CreatedDate.Sub(invalidatedAt).Hours()
+					pr.ReapprovalWaitHours = &wait
+					break
+				}
+			}
+		}
+		prs = append(prs, pr)
+	}
+	return prs
+}
+
+// aggregateApprovalGating groups approved PRs by repo, tool and the UTC month
+// of the merge, sorted by repo, tool and month
+func aggregateApprovalGating(prs []gatedPullRequest, calculatedAt time.Time) []*models.AiApprovalGatingMetric {
+	type monthValues struct {
+		reapproved, single, waits []float64
+		reapprovals               int
+	}
+	byMonth := make(map[gatingMonthKey]*monthValues)
+	for _, pr := range prs {
+		if pr.RepoId == "" || pr.AiTool == "" {
+			continue
+		}
+		merged := pr.MergedAt.UTC()
+		key := gatingMonthKey{
+			RepoId:     pr.RepoId,
+			AiTool:     pr.AiTool,
+			MonthStart: time.Date(merged.Year(), merged.Month(), 1, 0, 0, 0, 0, time.UTC),
+		}
+		values, ok := byMonth[key]
+		if !ok {
+			values = &monthValues{}
+			byMonth[key] = values
+		}
+		values.reapprovals += pr.Reapprovals
+		if pr.NeededReapproval {
+			values.reapproved = append(values.reapproved, pr.MergeHours)
+			if pr.ReapprovalWaitHours != nil {
+				values.waits = append(values.waits, *pr.ReapprovalWaitHours)
+			}
+		} else {
+			values.single = append(values.single, pr.MergeHours)
+		}
+	}
+
+	keys := make([]gatingMonthKey, 0, len(byMonth))
+	for key := range byMonth {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].RepoId != keys[j].RepoId {
+			return keys[i].RepoId < keys[j].RepoId
+		}
+		if keys[i].AiTool != keys[j].AiTool {
+			return keys[i].AiTool < keys[j].AiTool
+		}
+		return keys[i].MonthStart.Before(keys[j].MonthStart)
+	})
+
+	metrics := make([]*models.AiApprovalGatingMetric, 0, len(keys))
+	for _, key := range keys {
+		values := byMonth[key]
+		sort.Float64s(values.reapproved)
+		sort.Float64s(values.single)
+		sort.Float64s(values.waits)
+		gated := len(values.reapproved) + len(values.single)
+		metric := &models.AiApprovalGatingMetric{
+			Id:                      generateGatingMetricId(key),
+			RepoId:                  key.RepoId,
+			AiTool:                  key.AiTool,
+			MonthStart:              key.MonthStart,
+			GatedPrs:                gated,
+			ReapprovedPrs:           len(values.reapproved),
+			SingleApprovalPrs:       len(values.single),
+			ReapprovalPct:           float64(len(values.reapproved)) / float64(gated) * 100,
+			Reapprovals:             values.reapprovals,
+			P50MergeHoursReapproved: nearestRankPercentile(values.reapproved, 50),
+			P50MergeHoursSingle:     nearestRankPercentile(values.single, 50),
+			P50ReapprovalWaitHours:  nearestRankPercentile(values.waits, 50),
+			CalculatedAt:            calculatedAt,
+		}
+		// The delay is only meaningful when both groups have PRs
+		if len(values.reapproved) > 0 && len(values.single) > 0 {
+			metric.MergeDelayHours = metric.P50MergeHoursReapproved - metric.P50MergeHoursSingle
+		}
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+// generateGatingMetricId creates a deterministic ID for an approval gating metrics record
+func generateGatingMetricId(key gatingMonthKey) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s", key.RepoId, key.AiTool, key.MonthStart.Format("2006-01"))))
+	return "aigating:" + hex.EncodeToString(hash[:16])
+}
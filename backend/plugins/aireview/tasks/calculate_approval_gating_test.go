@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchGatedPullRequests(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return base.Add(time.Duration(hours) * time.Hour) }
+	approval := func(prId string, approvedAt, mergedAt time.Time) gatingApproval {
+		return gatingApproval{RepoId: "repo1", PullRequestId: prId, AiTool: "coderabbit",
+			CreatedDate: approvedAt, PrCreatedDate: base, PrMergedDate: mergedAt}
+	}
+
+	approvals := []gatingApproval{
+		// pr1: approved at 2, pushed to at 3 and 4, approved again at 6, merged at 8
+		approval("pr1", at(6), at(8)),
+		approval("pr1", at(2), at(8)),
+		// pr2: approved at 1 and merged at 3, the commit predates the approval
+		approval("pr2", at(1), at(3)),
+		// pr3: pushed to after the approval but never approved again
+		approval("pr3", at(1), at(5)),
+		// an approval after the merge is ignored
+		approval("pr4", at(9), at(8)),
+	}
+	pushes := map[string][]time.Time{
+		"pr1": {at(0), at(4), at(3)},
+		"pr2": {at(0)},
+		"pr3": {at(2)},
+	}
+
+	prs := matchGatedPullRequests(approvals, pushes)
+	wait := 3.0
+	assert.Equal(t, []gatedPullRequest{
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: at(8), MergeHours: 8, Reapprovals: 1,
+			NeededReapproval: true, ReapprovalWaitHours: &wait},
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: at(3), MergeHours: 3},
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: at(5), MergeHours: 5, NeededReapproval: true},
+	}, prs)
+}
+
+func TestAggregateApprovalGating(t *testing.T) {
+	april := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC)
+	hours := func(h float64) *float64 { return &h }
+	prs := []gatedPullRequest{
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: april.AddDate(0, 0, 2), MergeHours: 30, Reapprovals: 2,
+			NeededReapproval: true, ReapprovalWaitHours: hours(4)},
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: april.AddDate(0, 0, 9), MergeHours: 20, Reapprovals: 1,
+			NeededReapproval: true, ReapprovalWaitHours: hours(2)},
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: april.AddDate(0, 0, 10), MergeHours: 6},
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: april.AddDate(0, 0, 20), MergeHours: 10},
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: april.AddDate(0, 0, 29), MergeHours: 8},
+		// next month, single approvals only
+		{RepoId: "repo1", AiTool: "coderabbit", MergedAt: april.AddDate(0, 1, 1), MergeHours: 5},
+		// missing scope is skipped
+		{RepoId: "", AiTool: "coderabbit", MergedAt: april, MergeHours: 1},
+	}
+
+	metrics := aggregateApprovalGating(prs, now)
+	if assert.Len(t, metrics, 2) {
+		first := metrics[0]
+		assert.Equal(t, april, first.MonthStart)
+		assert.Equal(t, 5, first.GatedPrs)
+		assert.Equal(t, 2, first.ReapprovedPrs)
+		assert.Equal(t, 3, first.SingleApprovalPrs)
+		assert.InDelta(t, 40.0, first.ReapprovalPct, 0.001)
+		assert.Equal(t, 3, first.Reapprovals)
+		assert.Equal(t, 20.0, first.P50MergeHoursReapproved)
+		assert.Equal(t, 8.0, first.P50MergeHoursSingle)
+		assert.Equal(t, 12.0, first.MergeDelayHours)
+		assert.Equal(t, 2.0, first.P50ReapprovalWaitHours)
+		assert.Equal(t, now, first.CalculatedAt)
+
+		second := metrics[1]
+		assert.Equal(t, april.AddDate(0, 1, 0), second.MonthStart)
+		assert.Equal(t, 1, second.SingleApprovalPrs)
+		assert.Zero(t, second.ReapprovalPct)
+		assert.Zero(t, second.MergeDelayHours)
+		assert.NotEqual(t, first.Id, second.Id)
+	}
+}
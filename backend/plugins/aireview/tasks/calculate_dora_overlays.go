@@ -0,0 +1,219 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	domainCode "github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var CalculateDoraOverlaysMeta = plugin.SubTaskMeta{
+	Name:             "calculateDoraOverlays",
+	EntryPoint:       CalculateDoraOverlays,
+	EnabledByDefault: true,
+	Description:      "Roll up AI flagged changes and AI-predicted-risky deployments per project and month into domain table ai_dora_metrics",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&CalculateFailurePredictionsMeta},
+}
+
+// doraChange is a merged PR of one of the project's repos
+type doraChange struct {
+	PullRequestId string    `gorm:"column:pull_request_id"`
+	MergedDate    time.Time `gorm:"column:merged_date"`
+}
+
+// doraDeploymentCommit is a commit of a successful production deployment of one of
+// the project's cicd scopes, with a PR it shipped (empty when it shipped none)
+type doraDeploymentCommit struct {
+	DeploymentId  string    `gorm:"column:deployment_id"`
+	FinishedDate  time.Time `gorm:"column:finished_date"`
+	PullRequestId string    `gorm:"column:pull_request_id"`
+}
+
+// CalculateDoraOverlays walks project_mapping to gather the project's merged PRs,
+// the AI reviews and risk flags of its repos and the production deployments of its
+// cicd scopes, and rewrites the project's monthly rows of ai_dora_metrics.
+// Deployments are counted like the DORA dashboards: one per cicd_deployment_id,
+// dated by its last finished commit. Only runs in project mode.
+func CalculateDoraOverlays(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	projectName := data.Options.ProjectName
+	if projectName == "" {
+		logger.Info("calculateDoraOverlays: skipping — no projectName set (single-repo mode)")
+		return nil
+	}
+
+	var changes []doraChange
+	err := db.All(&changes,
+		dal.Select("pr.id AS pull_request_id, pr.merged_date"),
+		dal.From("pull_requests pr"),
+		dal.Join("JOIN project_mapping pm ON pr.base_repo_id = pm.row_id AND pm.`table` = 'repos'"),
+		dal.Where("pm.project_name = ? AND pr.merged_date IS NOT NULL", projectName),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load the merged PRs of the project")
+	}
+
+	reviewed, err := loadProjectPrIds(db, "_tool_aireview_reviews", projectName, "")
+	if err != nil {
+		return err
+	}
+	flagged, err := loadProjectPrIds(db, "_tool_aireview_failure_predictions", projectName, "t.was_flagged_risky = ?", true)
+	if err != nil {
+		return err
+	}
+
+	var deploymentCommits []doraDeploymentCommit
+	err = db.All(&deploymentCommits,
+		dal.Select("cdc.cicd_deployment_id AS deployment_id, cdc.finished_date, COALESCE(ppm.id, '') AS pull_request_id"),
+		dal.From("cicd_deployment_commits cdc"),
+		dal.Join("JOIN project_mapping pm ON cdc.cicd_scope_id = pm.row_id AND pm.`table` = 'cicd_scopes'"),
+		dal.Join("LEFT JOIN project_pr_metrics ppm ON ppm.deployment_commit_id = cdc.id AND ppm.project_name = pm.project_name"),
+		dal.Where("pm.project_name = ? AND cdc.result = 'SUCCESS' AND cdc.environment = 'PRODUCTION' AND cdc.finished_date IS NOT NULL", projectName),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load the production deployments of the project")
+	}
+
+	if err := db.Delete(&domainCode.AiDoraMetrics{}, dal.Where("project_name = ?", projectName)); err != nil {
+		return errors.Default.Wrap(err, "failed to delete existing ai_dora_metrics for project")
+	}
+	rows := aggregateDoraOverlays(projectName, changes, deploymentCommits, reviewed, flagged, time.Now())
+	for _, row := range rows {
+		if err := db.CreateOrUpdate(row); err != nil {
+			return errors.Default.Wrap(err, "failed to save ai_dora_metrics")
+		}
+	}
+
+	logger.Info("calculateDoraOverlays: %d months from %d merged PRs and %d deployment commits for project %s",
+		len(rows), len(changes), len(deploymentCommits), projectName)
+	return nil
+}
+
+// loadProjectPrIds returns the distinct pull_request_id of the rows of an aireview
+// table whose repo belongs to the project, optionally filtered by condition
+func loadProjectPrIds(db dal.Dal, table, projectName, condition string, args ...interface{}) (map[string]bool, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(table + " t"),
+		dal.Join("JOIN project_mapping pm ON t.repo_id = pm.row_id AND pm.`table` = 'repos'"),
+		dal.Where("pm.project_name = ?", projectName),
+	}
+	if condition != "" {
+		clauses = append(clauses, dal.Where(condition, args...))
+	}
+	var ids []string
+	if err := db.Pluck("DISTINCT t.pull_request_id", &ids, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load the PRs of "+table)
+	}
+	set := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set, nil
+}
+
+// aggregateDoraOverlays buckets the merged PRs by merge month and the deployments by
+// the month of their last finished commit. A deployment is AI-predicted-risky when
+// one of the PRs it shipped was flagged risky. Months are UTC calendar months.
+func aggregateDoraOverlays(projectName string, changes []doraChange, deploymentCommits []doraDeploymentCommit,
+	reviewed, flagged map[string]bool, now time.Time) []*domainCode.AiDoraMetrics {
+	rows := map[time.Time]*domainCode.AiDoraMetrics{}
+	month := func(t time.Time) *domainCode.AiDoraMetrics {
+		t = t.UTC()
+		start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		row, ok := rows[start]
+		if !ok {
+			row = &domainCode.AiDoraMetrics{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: generateAiDomainId("adm", projectName, start.Format("2006-01")),
+				},
+				ProjectName:  projectName,
+				PeriodStart:  start,
+				PeriodEnd:    start.AddDate(0, 1, 0),
+				PeriodType:   "monthly",
+				CalculatedAt: now,
+			}
+			rows[start] = row
+		}
+		return row
+	}
+
+	seenPrs := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		if seenPrs[change.PullRequestId] {
+			continue
+		}
+		seenPrs[change.PullRequestId] = true
+		row := month(change.MergedDate)
+		row.MergedPrs++
+		if reviewed[change.PullRequestId] {
+			row.AiReviewedPrs++
+		}
+		if flagged[change.PullRequestId] {
+			row.AiFlaggedPrs++
+		}
+	}
+
+	type deployment struct {
+		finished time.Time
+		risky    bool
+	}
+	deployments := map[string]*deployment{}
+	for _, commit := range deploymentCommits {
+		d, ok := deployments[commit.DeploymentId]
+		if !ok {
+			d = &deployment{}
+			deployments[commit.DeploymentId] = d
+		}
+		if commit.FinishedDate.After(d.finished) {
+			d.finished = commit.FinishedDate
+		}
+		if flagged[commit.PullRequestId] {
+			d.risky = true
+		}
+	}
+	for _, d := range deployments {
+		row := month(d.finished)
+		row.Deployments++
+		if d.risky {
+			row.AiRiskyDeployments++
+		}
+	}
+
+	result := make([]*domainCode.AiDoraMetrics, 0, len(rows))
+	for _, row := range rows {
+		if row.MergedPrs > 0 {
+			row.AiFlaggedChangeRatio = float64(row.AiFlaggedPrs) / float64(row.MergedPrs)
+		}
+		if row.Deployments > 0 {
+			row.AiRiskyDeploymentRatio = float64(row.AiRiskyDeployments) / float64(row.Deployments)
+		}
+		result = append(result, row)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].PeriodStart.Before(result[j].PeriodStart) })
+	return result
+}
@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateDoraOverlays(t *testing.T) {
+	now := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+	march := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 20, 12, 0, 0, 0, time.UTC)
+
+	changes := []doraChange{
+		{PullRequestId: "pr1", MergedDate: march},
+		{PullRequestId: "pr2", MergedDate: march},
+		{PullRequestId: "pr3", MergedDate: april},
+		{PullRequestId: "pr4", MergedDate: april},
+		{PullRequestId: "pr4", MergedDate: april}, // same PR mapped twice
+	}
+	deploymentCommits := []doraDeploymentCommit{
+		// one deployment of two commits, the risky PR on the first one; dated by its last commit in April
+		{DeploymentId: "d1", FinishedDate: time.Date(2026, 3, 31, 23, 0, 0, 0, time.UTC), PullRequestId: "pr1"},
+		{DeploymentId: "d1", FinishedDate: time.Date(2026, 4, 1, 1, 0, 0, 0, time.UTC), PullRequestId: ""},
+		{DeploymentId: "d2", FinishedDate: april, PullRequestId: "pr3"},
+		{DeploymentId: "d3", FinishedDate: march, PullRequestId: "pr2"},
+	}
+	reviewed := map[string]bool{"pr1": true, "pr2": true, "pr3": true}
+	flagged := map[string]bool{"pr1": true, "pr4": true}
+
+	rows := aggregateDoraOverlays("proj", changes, deploymentCommits, reviewed, flagged, now)
+
+	assert.Len(t, rows, 2)
+	m, a := rows[0], rows[1]
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), m.PeriodStart)
+	assert.Equal(t, time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC), m.PeriodEnd)
+	assert.Equal(t, "monthly", m.PeriodType)
+	assert.Equal(t, "proj", m.ProjectName)
+	assert.Equal(t, now, m.CalculatedAt)
+	assert.Equal(t, 2, m.MergedPrs)
+	assert.Equal(t, 2, m.AiReviewedPrs)
+	assert.Equal(t, 1, m.AiFlaggedPrs)
+	assert.InDelta(t, 0.5, m.AiFlaggedChangeRatio, 0.0001)
+	assert.Equal(t, 1, m.Deployments)
+	assert.Equal(t, 0, m.AiRiskyDeployments)
+	assert.Equal(t, 0.0, m.AiRiskyDeploymentRatio)
+
+	assert.Equal(t, 2, a.MergedPrs)
+	assert.Equal(t, 1, a.AiReviewedPrs)
+	assert.Equal(t, 1, a.AiFlaggedPrs)
+	assert.Equal(t, 2, a.Deployments)
+	assert.Equal(t, 1, a.AiRiskyDeployments)
+	assert.InDelta(t, 0.5, a.AiRiskyDeploymentRatio, 0.0001)
+
+	assert.NotEqual(t, m.Id, a.Id)
+	assert.Equal(t, generateAiDomainId("adm", "proj", "2026-03"), m.Id)
+}
+
+func TestAggregateDoraOverlays_Empty(t *testing.T) {
+	rows := aggregateDoraOverlays("proj", nil, nil, nil, nil, time.Now())
+	assert.Empty(t, rows)
+
+	// deployments without merged PRs keep a zero change ratio
+	rows = aggregateDoraOverlays("proj", nil, []doraDeploymentCommit{{DeploymentId: "d1", FinishedDate: time.Now()}}, nil, nil, time.Now())
+	if assert.Len(t, rows, 1) {
+		assert.Equal(t, 0, rows[0].MergedPrs)
+		assert.Equal(t, 0.0, rows[0].AiFlaggedChangeRatio)
+		assert.Equal(t, 1, rows[0].Deployments)
+	}
+}
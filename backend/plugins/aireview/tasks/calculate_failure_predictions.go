@@ -21,6 +21,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -48,14 +49,17 @@ type prAiSummary struct {
 	RepoShortName  string
 	RepoName       string
 	AiTool         string
-	MaxRiskScore   int
-	CreatedDate    time.Time
-	PrTitle        string
-	PrUrl          string
-	PrAuthor       string
-	PrCreatedAt    time.Time
-	Additions      int
-	Deletions      int
+	ToolVersion    string
+	// FindingCategory is the dominant category of AiTool's findings on the PR
+	FindingCategory string
+	MaxRiskScore    int
+	CreatedDate     time.Time
+	PrTitle         string
+	PrUrl           string
+	PrAuthor        string
+	PrCreatedAt     time.Time
+	Additions       int
+	Deletions       int
 }
 
 // prCiKey identifies a PR in the ci_test_jobs table.
@@ -102,7 +106,7 @@ func CalculateFailurePredictions(taskCtx plugin.SubTaskContext) errors.Error {
 	}
 
 	// Load AI-reviewed PR summaries (same for all sources).
-	prSummaries, err := loadAiReviewPrSummaries(db, data.Options.RepoId, data.Options.ProjectName)
+	prSummaries, err := loadAiReviewPrSummaries(db, data.Options.RepoId, data.Options.ProjectName, data.Options.ExcludeRepoIds)
 	if err != nil {
 		return err
 	}
@@ -112,6 +116,22 @@ func CalculateFailurePredictions(taskCtx plugin.SubTaskContext) errors.Error {
 	}
 	logger.Info("Loaded %d (PR, AI tool) pairs", len(prSummaries))
 
+	if data.Options.ScopeConfig.AnonymizeEnabled {
+		for i := range prSummaries {
+			prSummaries[i].PrAuthor = anonymizeAccount(prSummaries[i].PrAuthor)
+		}
+	}
+
+	// Attribute each (PR, AI tool) pair to the dominant category of its findings
+	// so prediction metrics can be broken down per category.
+	dominantCategories, err := loadDominantCategories(db, data.Options.RepoId, data.Options.ProjectName, data.Options.ExcludeRepoIds)
+	if err != nil {
+		return err
+	}
+	for i := range prSummaries {
+		prSummaries[i].FindingCategory = dominantCategories[prSummaries[i].PullRequestId+":"+prSummaries[i].AiTool]
+	}
+
 	repoShortNames := uniqueRepoShortNames(prSummaries)
 
 	// Pre-build flaky sets only when the exclude_flaky_tests flag is enabled.
@@ -185,6 +205,8 @@ func CalculateFailurePredictions(taskCtx plugin.SubTaskContext) errors.Error {
 				RepoShortName:         ps.RepoShortName,
 				RepoName:              ps.RepoName,
 				AiTool:                ps.AiTool,
+				ToolVersion:           ps.ToolVersion,
+				FindingCategory:       ps.FindingCategory,
 				CiFailureSource:       source,
 				WasFlaggedRisky:       wasFlaggedRisky,
 				RiskScore:             ps.MaxRiskScore,
@@ -234,6 +256,8 @@ func CalculateFailurePredictions(taskCtx plugin.SubTaskContext) errors.Error {
 			RepoShortName:         ps.RepoShortName,
 			RepoName:              ps.RepoName,
 			AiTool:                ps.AiTool,
+			ToolVersion:           ps.ToolVersion,
+			FindingCategory:       ps.FindingCategory,
 			CiFailureSource:       models.CiSourceNone,
 			WasFlaggedRisky:       ps.MaxRiskScore >= warningThreshold,
 			RiskScore:             ps.MaxRiskScore,
@@ -269,6 +293,10 @@ func CalculateFailurePredictions(taskCtx plugin.SubTaskContext) errors.Error {
 	return nil
 }
 
+// failedTestCaseStatuses are the ci_test_cases statuses counted as a CI failure: an
+// errored test case (setup or infrastructure error) fails the run like a failed one
+var failedTestCaseStatuses = []string{"failed", "errored"}
+
 // buildFlakyTestSet returns a set of (testName, repository) pairs that failed
 // on periodic or push runs in the last 30 days. Used by loadCiOutcomesByTestCases
 // to exclude environment-flaky test failures from PR outcome determination.
@@ -284,7 +312,7 @@ func buildFlakyTestSet(db dal.Dal) (map[prCiKey]bool, errors.Error) {
 		dal.Select("DISTINCT tc.name, j.repository"),
 		dal.From("ci_test_cases tc"),
 		dal.Join("JOIN ci_test_jobs j ON tc.connection_id = j.connection_id AND tc.job_id = j.job_id"),
-		dal.Where("tc.status = 'failed' AND j.trigger_type IN ('periodic', 'push') AND j.finished_at >= ?", thirtyDaysAgo),
+		dal.Where("tc.status IN ? AND j.trigger_type IN ('periodic', 'push') AND j.finished_at >= ?", failedTestCaseStatuses, thirtyDaysAgo),
 	)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "failed to build flaky test set")
@@ -326,14 +354,19 @@ func buildFlakyJobSet(db dal.Dal) (map[string]bool, errors.Error) {
 
 // loadAiReviewPrSummaries returns one row per (pull_request_id, ai_tool) with
 // the max risk_score and the PR key / repo short name needed to join CI data.
-// Supports both single-repo mode (repoId set) and project mode (projectName set).
-func loadAiReviewPrSummaries(db dal.Dal, repoId, projectName string) ([]prAiSummary, errors.Error) {
+// When the PR was reviewed by several versions of the tool the tool_version of
+// the latest review reporting one is kept, as versions don't sort as strings
+// ("1.10" < "1.9").
+// Supports both single-repo mode (repoId set) and project mode (projectName set,
+// without the repos of excludeRepoIds).
+func loadAiReviewPrSummaries(db dal.Dal, repoId, projectName string, excludeRepoIds []string) ([]prAiSummary, errors.Error) {
 	var rows []struct {
 		PullRequestId  string    `gorm:"column:pull_request_id"`
 		PullRequestKey string    `gorm:"column:pull_request_key"`
 		RepoId         string    `gorm:"column:repo_id"`
 		RepoName       string    `gorm:"column:repo_name"`
 		AiTool         string    `gorm:"column:ai_tool"`
+		ToolVersion    string    `gorm:"column:tool_version"`
 		MaxRiskScore   int       `gorm:"column:max_risk_score"`
 		CreatedDate    time.Time `gorm:"column:created_date"`
 		PrTitle        string    `gorm:"column:pr_title"`
@@ -345,6 +378,9 @@ func loadAiReviewPrSummaries(db dal.Dal, repoId, projectName string) ([]prAiSumm
 	}
 
 	const selectCols = "ar.pull_request_id, pr.pull_request_key, ar.repo_id, r.name AS repo_name, ar.ai_tool," +
+		" COALESCE((SELECT lv.tool_version FROM _tool_aireview_reviews lv" +
+		" WHERE lv.pull_request_id = ar.pull_request_id AND lv.ai_tool = ar.ai_tool AND lv.tool_version != ''" +
+		" AND lv.body NOT LIKE '%Review skipped%' ORDER BY lv.created_date DESC, lv.id DESC LIMIT 1), '') AS tool_version," +
 		" MAX(ar.risk_score) AS max_risk_score, MIN(ar.created_date) AS created_date," +
 		" MAX(pr.title) AS pr_title, MAX(pr.url) AS pr_url, MAX(pr.author_name) AS pr_author," +
 		" MAX(pr.created_date) AS pr_created_at, MAX(pr.additions) AS additions, MAX(pr.deletions) AS deletions"
@@ -367,8 +403,9 @@ func loadAiReviewPrSummaries(db dal.Dal, repoId, projectName string) ([]prAiSumm
 			dal.Join("JOIN repos r ON ar.repo_id = r.id"),
 			dal.Join("JOIN project_mapping pm ON ar.repo_id = pm.row_id AND pm.`table` = 'repos'"),
 			dal.Where("pm.project_name = ? AND ar.body NOT LIKE '%Review skipped%'", projectName),
-			dal.Groupby("ar.pull_request_id, pr.pull_request_key, ar.repo_id, r.name, ar.ai_tool"),
 		}
+		clauses = append(clauses, excludedRepoClauses("ar.repo_id", excludeRepoIds)...)
+		clauses = append(clauses, dal.Groupby("ar.pull_request_id, pr.pull_request_key, ar.repo_id, r.name, ar.ai_tool"))
 	}
 
 	err := db.All(&rows, clauses...)
@@ -382,9 +419,10 @@ func loadAiReviewPrSummaries(db dal.Dal, repoId, projectName string) ([]prAiSumm
 			PullRequestId:  r.PullRequestId,
 			PullRequestKey: r.PullRequestKey,
 			RepoId:         r.RepoId,
-			RepoShortName:  repoShortNameFrom(r.RepoName),
+			RepoShortName:  RepoShortNameFrom(r.RepoName),
 			RepoName:       r.RepoName,
 			AiTool:         r.AiTool,
+			ToolVersion:    r.ToolVersion,
 			MaxRiskScore:   r.MaxRiskScore,
 			CreatedDate:    r.CreatedDate,
 			PrTitle:        r.PrTitle,
@@ -398,6 +436,90 @@ func loadAiReviewPrSummaries(db dal.Dal, repoId, projectName string) ([]prAiSumm
 	return summaries, nil
 }
 
+// findingCategoryCount is the number of findings of one category and severity
+// left by an AI tool on a PR.
+type findingCategoryCount struct {
+	Category string `gorm:"column:category"`
+	Severity string `gorm:"column:severity"`
+	Count    int    `gorm:"column:cnt"`
+}
+
+// loadDominantCategories returns the dominant finding category per
+// (pull_request_id + ":" + ai_tool). Findings without a category are ignored.
+// Supports both single-repo mode (repoId set) and project mode (projectName set,
+// without the repos of excludeRepoIds).
+func loadDominantCategories(db dal.Dal, repoId, projectName string, excludeRepoIds []string) (map[string]string, errors.Error) {
+	var rows []struct {
+		PullRequestId string `gorm:"column:pull_request_id"`
+		AiTool        string `gorm:"column:ai_tool"`
+		findingCategoryCount
+	}
+
+	clauses := []dal.Clause{
+		dal.Select("f.pull_request_id, f.ai_tool, f.category, f.severity, COUNT(*) AS cnt"),
+		dal.From("_tool_aireview_findings f"),
+	}
+	if repoId != "" {
+		clauses = append(clauses, dal.Where("f.repo_id = ? AND f.category != ''", repoId))
+	} else {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON f.repo_id = pm.row_id AND pm.`table` = 'repos'"),
+			dal.Where("pm.project_name = ? AND f.category != ''", projectName),
+		)
+		clauses = append(clauses, excludedRepoClauses("f.repo_id", excludeRepoIds)...)
+	}
+	clauses = append(clauses, dal.Groupby("f.pull_request_id, f.ai_tool, f.category, f.severity"))
+
+	if err := db.All(&rows, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load AI review finding categories")
+	}
+
+	counts := make(map[string][]findingCategoryCount)
+	for _, r := range rows {
+		key := r.PullRequestId + ":" + r.AiTool
+		counts[key] = append(counts[key], r.findingCategoryCount)
+	}
+	categories := make(map[string]string, len(counts))
+	for key, c := range counts {
+		categories[key] = dominantCategory(c)
+	}
+	return categories, nil
+}
+
+// severityRank orders finding severities for tie-breaking; unknown severities rank lowest.
+var severityRank = map[string]int{
+	models.FindingSeverityInfo:     1,
+	models.FindingSeverityWarning:  2,
+	models.FindingSeverityError:    3,
+	models.FindingSeverityCritical: 4,
+}
+
+// dominantCategory picks the category with the most findings. Ties go to the
+// category with the most severe finding, then to the alphabetically first one
+// so the result does not depend on row order.
+func dominantCategory(counts []findingCategoryCount) string {
+	total := make(map[string]int)
+	maxSeverity := make(map[string]int)
+	for _, c := range counts {
+		total[c.Category] += c.Count
+		if rank := severityRank[c.Severity]; rank > maxSeverity[c.Category] {
+			maxSeverity[c.Category] = rank
+		}
+	}
+
+	best := ""
+	for category, n := range total {
+		switch {
+		case best == "",
+			n > total[best],
+			n == total[best] && maxSeverity[category] > maxSeverity[best],
+			n == total[best] && maxSeverity[category] == maxSeverity[best] && category < best:
+			best = category
+		}
+	}
+	return best
+}
+
 // ciOutcomeEntry records whether a PR had at least one non-flaky CI failure.
 type ciOutcomeEntry struct {
 	HadNonFlakyFailure bool
@@ -437,7 +559,7 @@ func loadCiOutcomesByTestCases(db dal.Dal, repoShortNames []string, flakyTests m
 		if _, exists := outcomes[key]; !exists {
 			outcomes[key] = ciOutcomeEntry{}
 		}
-		if r.Status != "failed" {
+		if !slices.Contains(failedTestCaseStatuses, r.Status) {
 			continue
 		}
 		// Check if this failed test is flaky.
@@ -533,7 +655,7 @@ func buildJobRunsWithTestFailures(db dal.Dal, repoShortNames []string) (map[stri
 		dal.Select("DISTINCT j.job_id"),
 		dal.From("ci_test_jobs j"),
 		dal.Join("JOIN ci_test_cases tc ON j.connection_id = tc.connection_id AND j.job_id = tc.job_id"),
-		dal.Where("j.trigger_type = 'pull_request' AND j.pull_request_number > 0 AND j.repository IN ? AND tc.status = 'failed' AND j.finished_at >= ?", repoShortNames, time.Now().AddDate(0, -3, 0)),
+		dal.Where("j.trigger_type = 'pull_request' AND j.pull_request_number > 0 AND j.repository IN ? AND tc.status IN ? AND j.finished_at >= ?", repoShortNames, failedTestCaseStatuses, time.Now().AddDate(0, -3, 0)),
 	)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "failed to build job runs with test failures set")
@@ -579,9 +701,10 @@ func generatePredictionId(prId, aiTool, ciFailureSource string) string {
 	return "aipred:" + hex.EncodeToString(hash[:16])
 }
 
-// repoShortNameFrom extracts the repository short name (the part after the last "/")
-// from a full "org/repo" name. This avoids MySQL-specific SUBSTRING_INDEX in SQL.
-func repoShortNameFrom(fullName string) string {
+// RepoShortNameFrom extracts the repository short name (the part after the last "/")
+// from a full "org/repo" name, as ci_test_jobs.repository stores it. This avoids
+// MySQL-specific SUBSTRING_INDEX in SQL.
+func RepoShortNameFrom(fullName string) string {
 	if i := strings.LastIndex(fullName, "/"); i >= 0 {
 		return fullName[i+1:]
 	}
@@ -121,9 +121,9 @@ func TestRepoShortNameFrom(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := repoShortNameFrom(tt.input)
+			got := RepoShortNameFrom(tt.input)
 			if got != tt.want {
-				t.Errorf("repoShortNameFrom(%q) = %q, want %q", tt.input, got, tt.want)
+				t.Errorf("RepoShortNameFrom(%q) = %q, want %q", tt.input, got, tt.want)
 			}
 		})
 	}
@@ -284,6 +284,7 @@ func TestLoadAiReviewPrSummaries(t *testing.T) {
 				RepoId         string    `gorm:"column:repo_id"`
 				RepoName       string    `gorm:"column:repo_name"`
 				AiTool         string    `gorm:"column:ai_tool"`
+				ToolVersion    string    `gorm:"column:tool_version"`
 				MaxRiskScore   int       `gorm:"column:max_risk_score"`
 				CreatedDate    time.Time `gorm:"column:created_date"`
 				PrTitle        string    `gorm:"column:pr_title"`
@@ -299,6 +300,7 @@ func TestLoadAiReviewPrSummaries(t *testing.T) {
 				RepoId         string    `gorm:"column:repo_id"`
 				RepoName       string    `gorm:"column:repo_name"`
 				AiTool         string    `gorm:"column:ai_tool"`
+				ToolVersion    string    `gorm:"column:tool_version"`
 				MaxRiskScore   int       `gorm:"column:max_risk_score"`
 				CreatedDate    time.Time `gorm:"column:created_date"`
 				PrTitle        string    `gorm:"column:pr_title"`
@@ -314,17 +316,19 @@ func TestLoadAiReviewPrSummaries(t *testing.T) {
 					RepoId:         "repo-1",
 					RepoName:       "org/my-repo",
 					AiTool:         "CodeRabbit",
+					ToolVersion:    "v2.3.1",
 					MaxRiskScore:   80,
 				},
 			}
 		}).Return(nil)
 
-		result, err := loadAiReviewPrSummaries(mockDal, "repo-1", "")
+		result, err := loadAiReviewPrSummaries(mockDal, "repo-1", "", nil)
 		assert.Nil(t, err)
 		assert.Len(t, result, 1)
 		assert.Equal(t, "pr-1", result[0].PullRequestId)
 		assert.Equal(t, "my-repo", result[0].RepoShortName)
 		assert.Equal(t, "CodeRabbit", result[0].AiTool)
+		assert.Equal(t, "v2.3.1", result[0].ToolVersion)
 	})
 
 	t.Run("error returns wrapped error", func(t *testing.T) {
@@ -332,7 +336,7 @@ func TestLoadAiReviewPrSummaries(t *testing.T) {
 		mockDal.On("All", mock.Anything, mock.Anything).
 			Return(errors.Default.New("db error"))
 
-		result, err := loadAiReviewPrSummaries(mockDal, "repo-1", "")
+		result, err := loadAiReviewPrSummaries(mockDal, "repo-1", "", nil)
 		assert.NotNil(t, err)
 		assert.Nil(t, result)
 	})
@@ -341,7 +345,7 @@ func TestLoadAiReviewPrSummaries(t *testing.T) {
 		mockDal := new(mockdal.Dal)
 		mockDal.On("All", mock.Anything, mock.Anything).Return(nil)
 
-		result, err := loadAiReviewPrSummaries(mockDal, "repo-1", "")
+		result, err := loadAiReviewPrSummaries(mockDal, "repo-1", "", nil)
 		assert.Nil(t, err)
 		assert.Empty(t, result)
 	})
@@ -387,6 +391,33 @@ func TestLoadCiOutcomesByTestCases(t *testing.T) {
 		assert.True(t, result[key].HadNonFlakyFailure)
 	})
 
+	t.Run("errored test case is a failure", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			dst := args.Get(0).(*[]struct {
+				PullRequestNumber int64  `gorm:"column:pull_request_number"`
+				Repository        string `gorm:"column:repository"`
+				TestName          string `gorm:"column:test_name"`
+				Status            string `gorm:"column:status"`
+			})
+			*dst = []struct {
+				PullRequestNumber int64  `gorm:"column:pull_request_number"`
+				Repository        string `gorm:"column:repository"`
+				TestName          string `gorm:"column:test_name"`
+				Status            string `gorm:"column:status"`
+			}{
+				{PullRequestNumber: 42, Repository: "repo-a", TestName: "TestPassing", Status: "passed"},
+				{PullRequestNumber: 42, Repository: "repo-a", TestName: "TestSetup", Status: "errored"},
+				{PullRequestNumber: 43, Repository: "repo-a", TestName: "TestSkipped", Status: "skipped"},
+			}
+		}).Return(nil)
+
+		result, err := loadCiOutcomesByTestCases(mockDal, []string{"repo-a"}, nil)
+		assert.Nil(t, err)
+		assert.True(t, result[prCiKey{PullRequestNumber: "42", Repository: "repo-a"}].HadNonFlakyFailure)
+		assert.False(t, result[prCiKey{PullRequestNumber: "43", Repository: "repo-a"}].HadNonFlakyFailure)
+	})
+
 	t.Run("error", func(t *testing.T) {
 		mockDal := new(mockdal.Dal)
 		mockDal.On("All", mock.Anything, mock.Anything).
@@ -499,3 +530,44 @@ func TestBuildJobRunsWithTestFailures(t *testing.T) {
 		assert.Nil(t, result)
 	})
 }
+
+func TestDominantCategory(t *testing.T) {
+	cases := []struct {
+		name   string
+		counts []findingCategoryCount
+		want   string
+	}{
+		{"no findings", nil, ""},
+		{
+			"most findings wins across severities",
+			[]findingCategoryCount{
+				{Category: "bug", Severity: "warning", Count: 2},
+				{Category: "bug", Severity: "info", Count: 2},
+				{Category: "security", Severity: "critical", Count: 3},
+			},
+			"bug",
+		},
+		{
+			"tie broken by highest severity",
+			[]findingCategoryCount{
+				{Category: "performance", Severity: "warning", Count: 2},
+				{Category: "security", Severity: "error", Count: 1},
+				{Category: "security", Severity: "info", Count: 1},
+			},
+			"security",
+		},
+		{
+			"full tie broken alphabetically",
+			[]findingCategoryCount{
+				{Category: "style", Severity: "info", Count: 1},
+				{Category: "bug", Severity: "info", Count: 1},
+			},
+			"bug",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, dominantCategory(tc.counts))
+		})
+	}
+}
@@ -43,6 +43,63 @@ var CalculatePredictionMetricsMeta = plugin.SubTaskMeta{
 // They match the sensitivity levels used in the Grafana dashboard.
 var aucThresholds = []int{0, 10, 20, 50, 80, 100}
 
+// metricsScope identifies one series of prediction metrics. An empty
+// ToolVersion aggregates every version of AiTool and an empty Category every
+// finding category.
+type metricsScope struct {
+	RepoId          string `gorm:"column:repo_id"`
+	AiTool          string `gorm:"column:ai_tool"`
+	CiFailureSource string `gorm:"column:ci_failure_source"`
+	ToolVersion     string `gorm:"column:tool_version"`
+	Category        string `gorm:"column:finding_category"`
+}
+
+func (s metricsScope) String() string {
+	str := fmt.Sprintf("%s/%s/%s", s.RepoId, s.AiTool, s.CiFailureSource)
+	if s.ToolVersion != "" {
+		str = fmt.Sprintf("%s/%s@%s/%s", s.RepoId, s.AiTool, s.ToolVersion, s.CiFailureSource)
+	}
+	if s.Category != "" {
+		str += "#" + s.Category
+	}
+	return str
+}
+
+// expandMetricsScopes turns the distinct (repo, tool, source, version, category)
+// rows into the scopes to compute: one all-versions scope per (repo, tool,
+// source), one scope per reported tool version and one per dominant finding
+// category. Accuracy changes can then be attributed to tool upgrades, and
+// precision compared across categories, without changing the all-versions
+// series. Versions and categories are not crossed to keep the series large
+// enough to be meaningful.
+func expandMetricsScopes(rows []metricsScope) []metricsScope {
+	scopes := make([]metricsScope, 0, len(rows))
+	seen := make(map[metricsScope]bool, len(rows))
+	add := func(s metricsScope) {
+		if !seen[s] {
+			seen[s] = true
+			scopes = append(scopes, s)
+		}
+	}
+	for _, r := range rows {
+		all := r
+		all.ToolVersion = ""
+		all.Category = ""
+		add(all)
+		if r.ToolVersion != "" {
+			version := all
+			version.ToolVersion = r.ToolVersion
+			add(version)
+		}
+		if r.Category != "" {
+			category := all
+			category.Category = r.Category
+			add(category)
+		}
+	}
+	return scopes
+}
+
 // predictionPoint is the minimal data needed for AUC computation.
 type predictionPoint struct {
 	RiskScore    int
@@ -62,21 +119,18 @@ func CalculatePredictionMetrics(taskCtx plugin.SubTaskContext) errors.Error {
 
 	logger.Info("Calculating prediction metrics for repo: %s", data.Options.RepoId)
 
-	// Get distinct (repo_id, ai_tool, ci_failure_source) triplets that have completed predictions.
+	// Get distinct (repo_id, ai_tool, ci_failure_source, tool_version, finding_category) tuples that have completed predictions.
 	// Supports both single-repo mode and project mode (repoId empty).
-	var toolRows []struct {
-		RepoId          string `gorm:"column:repo_id"`
-		AiTool          string `gorm:"column:ai_tool"`
-		CiFailureSource string `gorm:"column:ci_failure_source"`
-	}
+	var toolRows []metricsScope
 	toolQuery := []dal.Clause{
-		dal.Select("DISTINCT repo_id, ai_tool, ci_failure_source"),
+		dal.Select("DISTINCT repo_id, ai_tool, ci_failure_source, tool_version, finding_category"),
 		dal.From(&models.AiFailurePrediction{}),
 	}
 	if data.Options.RepoId != "" {
 		toolQuery = append(toolQuery, dal.Where("repo_id = ? AND prediction_outcome != ''", data.Options.RepoId))
 	} else {
 		toolQuery = append(toolQuery, dal.Where("prediction_outcome != ''"))
+		toolQuery = append(toolQuery, excludedRepoClauses("repo_id", data.Options.ExcludeRepoIds)...)
 	}
 	if err := db.All(&toolRows, toolQuery...); err != nil {
 		return errors.Default.Wrap(err, "failed to get repo/tool/source triplets")
@@ -94,22 +148,22 @@ func CalculatePredictionMetrics(taskCtx plugin.SubTaskContext) errors.Error {
 		{"rolling_60d", now.AddDate(0, 0, -60), now},
 	}
 
-	for _, tr := range toolRows {
+	for _, tr := range expandMetricsScopes(toolRows) {
 		if tr.AiTool == "" || tr.RepoId == "" {
 			continue
 		}
 
-		// Load all prediction points for this (repo, tool, source) triplet for AUC.
-		allPoints, err := loadPredictionPoints(db, tr.RepoId, tr.AiTool, tr.CiFailureSource, time.Time{}, now)
+		// Load all prediction points for this scope for AUC.
+		allPoints, err := loadPredictionPoints(db, tr, time.Time{}, now)
 		if err != nil {
-			logger.Warn(err, "Failed to load prediction points for %s/%s/%s", tr.RepoId, tr.AiTool, tr.CiFailureSource)
+			logger.Warn(err, "Failed to load prediction points for %s", tr)
 			continue
 		}
 
 		for _, period := range periods {
-			periodPoints, err := loadPredictionPoints(db, tr.RepoId, tr.AiTool, tr.CiFailureSource, period.start, period.end)
+			periodPoints, err := loadPredictionPoints(db, tr, period.start, period.end)
 			if err != nil {
-				logger.Warn(err, "Failed to load period prediction points for %s/%s/%s/%s", tr.RepoId, tr.AiTool, tr.CiFailureSource, period.name)
+				logger.Warn(err, "Failed to load period prediction points for %s/%s", tr, period.name)
 				continue
 			}
 			if len(periodPoints) == 0 {
@@ -121,7 +175,7 @@ func CalculatePredictionMetrics(taskCtx plugin.SubTaskContext) errors.Error {
 				aucPoints = allPoints
 			}
 
-			metrics := computeMetrics(tr.RepoId, tr.AiTool, tr.CiFailureSource, period.name, period.start, period.end, periodPoints, aucPoints, warningThreshold)
+			metrics := computeMetrics(tr, period.name, period.start, period.end, periodPoints, aucPoints, warningThreshold)
 
 			if err := db.CreateOrUpdate(metrics); err != nil {
 				return errors.Default.Wrap(err, "failed to save prediction metrics")
@@ -134,28 +188,33 @@ func CalculatePredictionMetrics(taskCtx plugin.SubTaskContext) errors.Error {
 }
 
 // loadPredictionPoints fetches risk_score + had_ci_failure for all completed
-// predictions for a given (repo, tool, ci_failure_source). When start is zero,
-// no time filter is applied (all-time).
-func loadPredictionPoints(db dal.Dal, repoId, aiTool, ciFailureSource string, start, end time.Time) ([]predictionPoint, errors.Error) {
+// predictions in the given scope. When start is zero, no time filter is
+// applied (all-time).
+func loadPredictionPoints(db dal.Dal, scope metricsScope, start, end time.Time) ([]predictionPoint, errors.Error) {
 	var rows []struct {
 		RiskScore    int  `gorm:"column:risk_score"`
 		HadCiFailure bool `gorm:"column:had_ci_failure"`
 	}
 
-	var err errors.Error
-	if start.IsZero() {
-		err = db.All(&rows,
-			dal.Select("risk_score, had_ci_failure"),
-			dal.From(&models.AiFailurePrediction{}),
-			dal.Where("repo_id = ? AND ai_tool = ? AND ci_failure_source = ? AND prediction_outcome != ''", repoId, aiTool, ciFailureSource),
-		)
-	} else {
-		err = db.All(&rows,
-			dal.Select("risk_score, had_ci_failure"),
-			dal.From(&models.AiFailurePrediction{}),
-			dal.Where("repo_id = ? AND ai_tool = ? AND ci_failure_source = ? AND prediction_outcome != '' AND created_at BETWEEN ? AND ?", repoId, aiTool, ciFailureSource, start, end),
-		)
+	where := "repo_id = ? AND ai_tool = ? AND ci_failure_source = ? AND prediction_outcome != ''"
+	args := []interface{}{scope.RepoId, scope.AiTool, scope.CiFailureSource}
+	if scope.ToolVersion != "" {
+		where += " AND tool_version = ?"
+		args = append(args, scope.ToolVersion)
 	}
+	if scope.Category != "" {
+		where += " AND finding_category = ?"
+		args = append(args, scope.Category)
+	}
+	if !start.IsZero() {
+		where += " AND created_at BETWEEN ? AND ?"
+		args = append(args, start, end)
+	}
+	err := db.All(&rows,
+		dal.Select("risk_score, had_ci_failure"),
+		dal.From(&models.AiFailurePrediction{}),
+		dal.Where(where, args...),
+	)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "failed to query prediction points")
 	}
@@ -168,7 +227,7 @@ func loadPredictionPoints(db dal.Dal, repoId, aiTool, ciFailureSource string, st
 }
 
 // computeMetrics builds an AiPredictionMetrics record from prediction points.
-func computeMetrics(repoId, aiTool, ciFailureSource, periodType string, periodStart, periodEnd time.Time,
+func computeMetrics(scope metricsScope, periodType string, periodStart, periodEnd time.Time,
 	periodPoints, aucPoints []predictionPoint, warningThreshold int) *models.AiPredictionMetrics {
 
 	// Confusion matrix at warning_threshold.
@@ -220,10 +279,13 @@ func computeMetrics(repoId, aiTool, ciFailureSource, periodType string, periodSt
 	failedPrs := tp + fn
 
 	return &models.AiPredictionMetrics{
-		Id:                       generateMetricsId(repoId, aiTool, ciFailureSource, periodType, periodStart),
-		RepoId:                   repoId,
-		AiTool:                   aiTool,
-		CiFailureSource:          ciFailureSource,
+		Id:                       generateMetricsId(scope, periodType, periodStart),
+		RepoId:                   scope.RepoId,
+		AiTool:                   scope.AiTool,
+		CiFailureSource:          scope.CiFailureSource,
+		ToolVersion:              scope.ToolVersion,
+		Category:                 scope.Category,
+		IsAggregate:              scope.ToolVersion == "" && scope.Category == "",
 		PeriodStart:              periodStart,
 		PeriodEnd:                periodEnd,
 		PeriodType:               periodType,
@@ -331,8 +393,17 @@ func determineAutonomyLevel(precision, recall float64) string {
 	return models.AutonomyAdvisoryOnly
 }
 
-// generateMetricsId creates a deterministic ID for a metrics record.
-func generateMetricsId(repoId, aiTool, ciFailureSource, periodType string, periodStart time.Time) string {
-	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s:%s:%s", repoId, aiTool, ciFailureSource, periodType, periodStart.Format("2006-01-02"))))
+// generateMetricsId creates a deterministic ID for a metrics record. The tool
+// version and category only take part in the hash when set, so all-versions
+// records keep the ids they had before metrics were split by version.
+func generateMetricsId(scope metricsScope, periodType string, periodStart time.Time) string {
+	key := fmt.Sprintf("%s:%s:%s:%s:%s", scope.RepoId, scope.AiTool, scope.CiFailureSource, periodType, periodStart.Format("2006-01-02"))
+	if scope.ToolVersion != "" {
+		key += ":" + scope.ToolVersion
+	}
+	if scope.Category != "" {
+		key += ":category:" + scope.Category
+	}
+	hash := sha256.Sum256([]byte(key))
 	return "aimetrics:" + hex.EncodeToString(hash[:16])
 }
@@ -57,17 +57,45 @@ func TestDetermineAutonomyLevel(t *testing.T) {
 func TestGenerateMetricsId(t *testing.T) {
 	ts := time.Date(2024, 6, 15, 0, 0, 0, 0, time.UTC)
 
-	id1 := generateMetricsId("repo1", "CodeRabbit", "test_cases", "weekly", ts)
-	id2 := generateMetricsId("repo1", "CodeRabbit", "test_cases", "weekly", ts)
-	id3 := generateMetricsId("repo1", "CodeRabbit", "test_cases", "monthly", ts)
-	id4 := generateMetricsId("repo2", "CodeRabbit", "test_cases", "weekly", ts)
-	id5 := generateMetricsId("repo1", "Qodo", "test_cases", "weekly", ts)
+	id1 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", ts)
+	id2 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", ts)
+	id3 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "monthly", ts)
+	id4 := generateMetricsId(metricsScope{RepoId: "repo2", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", ts)
+	id5 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "Qodo", CiFailureSource: "test_cases"}, "weekly", ts)
 
 	assert.Equal(t, id1, id2, "same inputs must produce same ID")
 	assert.NotEqual(t, id1, id3, "different period type must produce different ID")
 	assert.NotEqual(t, id1, id4, "different repo must produce different ID")
 	assert.NotEqual(t, id1, id5, "different tool must produce different ID")
 	assert.True(t, strings.HasPrefix(id1, "aimetrics:"))
+
+	id6 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: "v2.3.1"}, "weekly", ts)
+	id7 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: "v2.4.0"}, "weekly", ts)
+	assert.NotEqual(t, id1, id6, "versioned record must not overwrite the all-versions record")
+	assert.NotEqual(t, id6, id7, "different tool version must produce different ID")
+
+	id8 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", Category: "security"}, "weekly", ts)
+	id9 := generateMetricsId(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", Category: "bug"}, "weekly", ts)
+	assert.NotEqual(t, id1, id8, "category record must not overwrite the all-categories record")
+	assert.NotEqual(t, id8, id9, "different category must produce different ID")
+}
+
+func TestExpandMetricsScopes(t *testing.T) {
+	rows := []metricsScope{
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: "v2.3.1", Category: "security"},
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: "v2.4.0", Category: "security"},
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: ""},
+		{RepoId: "repo1", AiTool: "Qodo", CiFailureSource: "job_result", ToolVersion: "", Category: "bug"},
+	}
+
+	assert.Equal(t, []metricsScope{
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"},
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: "v2.3.1"},
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", Category: "security"},
+		{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases", ToolVersion: "v2.4.0"},
+		{RepoId: "repo1", AiTool: "Qodo", CiFailureSource: "job_result"},
+		{RepoId: "repo1", AiTool: "Qodo", CiFailureSource: "job_result", Category: "bug"},
+	}, expandMetricsScopes(rows))
 }
 
 func TestComputeAucs(t *testing.T) {
@@ -139,7 +167,7 @@ func TestComputeMetrics(t *testing.T) {
 			{RiskScore: 90, HadCiFailure: true},
 			{RiskScore: 70, HadCiFailure: true},
 		}
-		m := computeMetrics("repo1", "CodeRabbit", "test_cases", "weekly", weekAgo, now, points, points, 50)
+		m := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", weekAgo, now, points, points, 50)
 
 		assert.Equal(t, 3, m.TruePositives)
 		assert.Equal(t, 0, m.FalsePositives)
@@ -160,7 +188,7 @@ func TestComputeMetrics(t *testing.T) {
 			{RiskScore: 10, HadCiFailure: false},
 			{RiskScore: 20, HadCiFailure: false},
 		}
-		m := computeMetrics("repo1", "CodeRabbit", "test_cases", "daily", weekAgo, now, points, points, 50)
+		m := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "daily", weekAgo, now, points, points, 50)
 
 		assert.Equal(t, 0, m.TruePositives)
 		assert.Equal(t, 0, m.FalsePositives)
@@ -179,7 +207,7 @@ func TestComputeMetrics(t *testing.T) {
 			{RiskScore: 20, HadCiFailure: true},  // FN
 			{RiskScore: 10, HadCiFailure: false}, // TN
 		}
-		m := computeMetrics("repo1", "CodeRabbit", "job_result", "monthly", weekAgo, now, points, points, 50)
+		m := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "job_result"}, "monthly", weekAgo, now, points, points, 50)
 
 		assert.Equal(t, 1, m.TruePositives)
 		assert.Equal(t, 1, m.FalsePositives)
@@ -198,7 +226,7 @@ func TestComputeMetrics(t *testing.T) {
 
 	t.Run("zero division safety with empty points", func(t *testing.T) {
 		points := []predictionPoint{}
-		m := computeMetrics("repo1", "CodeRabbit", "test_cases", "daily", weekAgo, now, points, points, 50)
+		m := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "daily", weekAgo, now, points, points, 50)
 
 		assert.Equal(t, 0.0, m.Precision)
 		assert.Equal(t, 0.0, m.Recall)
@@ -217,23 +245,35 @@ func TestComputeMetrics(t *testing.T) {
 			{RiskScore: 10, HadCiFailure: false},
 			{RiskScore: 5, HadCiFailure: false},
 		}
-		m := computeMetrics("repo1", "CodeRabbit", "test_cases", "weekly", weekAgo, now, highPrecisionPoints, highPrecisionPoints, 50)
+		m := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", weekAgo, now, highPrecisionPoints, highPrecisionPoints, 50)
 		assert.Equal(t, models.AutonomyAutoBlock, m.RecommendedAutonomyLevel)
 	})
 
 	t.Run("metrics ID is deterministic", func(t *testing.T) {
 		points := []predictionPoint{{RiskScore: 50, HadCiFailure: true}}
-		m1 := computeMetrics("repo1", "CodeRabbit", "test_cases", "weekly", weekAgo, now, points, points, 50)
-		m2 := computeMetrics("repo1", "CodeRabbit", "test_cases", "weekly", weekAgo, now, points, points, 50)
+		m1 := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", weekAgo, now, points, points, 50)
+		m2 := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, "weekly", weekAgo, now, points, points, 50)
 		assert.Equal(t, m1.Id, m2.Id)
 		assert.True(t, strings.HasPrefix(m1.Id, "aimetrics:"))
 	})
 
 	t.Run("ci_failure_source preserved", func(t *testing.T) {
 		points := []predictionPoint{{RiskScore: 50, HadCiFailure: true}}
-		m := computeMetrics("repo1", "CodeRabbit", "job_result", "daily", weekAgo, now, points, points, 50)
+		m := computeMetrics(metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "job_result"}, "daily", weekAgo, now, points, points, 50)
 		assert.Equal(t, "job_result", m.CiFailureSource)
 	})
+
+	t.Run("only the all-versions all-categories row is the aggregate", func(t *testing.T) {
+		points := []predictionPoint{{RiskScore: 50, HadCiFailure: true}}
+		all := metricsScope{RepoId: "repo1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}
+		assert.True(t, computeMetrics(all, "weekly", weekAgo, now, points, points, 50).IsAggregate)
+		version := all
+		version.ToolVersion = "v2.3.1"
+		assert.False(t, computeMetrics(version, "weekly", weekAgo, now, points, points, 50).IsAggregate)
+		category := all
+		category.Category = "security"
+		assert.False(t, computeMetrics(category, "weekly", weekAgo, now, points, points, 50).IsAggregate)
+	})
 }
 
 func TestLoadPredictionPoints(t *testing.T) {
@@ -254,7 +294,7 @@ func TestLoadPredictionPoints(t *testing.T) {
 			}
 		}).Return(nil)
 
-		points, err := loadPredictionPoints(mockDal, "repo-1", "CodeRabbit", "test_cases", time.Time{}, time.Now())
+		points, err := loadPredictionPoints(mockDal, metricsScope{RepoId: "repo-1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, time.Time{}, time.Now())
 		assert.Nil(t, err)
 		assert.Len(t, points, 3)
 		assert.Equal(t, 80, points[0].RiskScore)
@@ -280,7 +320,7 @@ func TestLoadPredictionPoints(t *testing.T) {
 
 		now := time.Now()
 		start := now.AddDate(0, 0, -7)
-		points, err := loadPredictionPoints(mockDal, "repo-1", "CodeRabbit", "job_result", start, now)
+		points, err := loadPredictionPoints(mockDal, metricsScope{RepoId: "repo-1", AiTool: "CodeRabbit", CiFailureSource: "job_result"}, start, now)
 		assert.Nil(t, err)
 		assert.Len(t, points, 1)
 		assert.Equal(t, 50, points[0].RiskScore)
@@ -292,7 +332,7 @@ func TestLoadPredictionPoints(t *testing.T) {
 		mockDal.On("All", mock.Anything, mock.Anything).
 			Return(errors.Default.New("db error"))
 
-		points, err := loadPredictionPoints(mockDal, "repo-1", "CodeRabbit", "test_cases", time.Time{}, time.Now())
+		points, err := loadPredictionPoints(mockDal, metricsScope{RepoId: "repo-1", AiTool: "CodeRabbit", CiFailureSource: "test_cases"}, time.Time{}, time.Now())
 		assert.NotNil(t, err)
 		assert.Nil(t, points)
 		assert.Contains(t, err.Error(), "prediction points")
@@ -0,0 +1,337 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var CalculateReviewSloMeta = plugin.SubTaskMeta{
+	Name:             "calculateReviewSlo",
+	EntryPoint:       CalculateReviewSlo,
+	EnabledByDefault: true,
+	Description:      "Calculate the weekly attainment of the AI review response time SLO per repo and tool",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractAiReviewsMeta},
+}
+
+// defaultReviewSloMinutes is used when the scope config leaves reviewSloMinutes unset
+const defaultReviewSloMinutes = 10
+
+// sloReview is one AI review with the times of the PR events that can trigger it
+type sloReview struct {
+	RepoId        string    `gorm:"column:repo_id"`
+	PullRequestId string    `gorm:"column:pull_request_id"`
+	AiTool        string    `gorm:"column:ai_tool"`
+	CreatedDate   time.Time `gorm:"column:created_date"`
+	PrCreatedDate time.Time `gorm:"column:pr_created_date"`
+}
+
+// sloResponse is the first AI review of a tool after a PR open or push, or a
+// missed one when no review came before the SLO window elapsed
+type sloResponse struct {
+	RepoId         string
+	AiTool         string
+	TriggeredAt    time.Time
+	LatencyMinutes float64
+	Missed         bool
+}
+
+// sloWeekKey identifies one row of _tool_aireview_slo_metrics
+type sloWeekKey struct {
+	RepoId    string
+	AiTool    string
+	WeekStart time.Time
+}
+
+// CalculateReviewSlo measures how fast AI tools review PR opens and pushes and
+// stores the share answered within the scope config's reviewSloMinutes per
+// repo, tool and week.
+func CalculateReviewSlo(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	sloMinutes := data.Options.ScopeConfig.ReviewSloMinutes
+	if sloMinutes <= 0 {
+		sloMinutes = defaultReviewSloMinutes
+	}
+
+	reviews, err := loadSloReviews(db, data.Options.RepoId, data.Options.ProjectName, data.Options.ExcludeRepoIds)
+	if err != nil {
+		return err
+	}
+	if len(reviews) == 0 {
+		logger.Info("No AI reviews found, skipping review SLO calculation")
+		return nil
+	}
+
+	prIds := make([]string, 0, len(reviews))
+	for _, r := range reviews {
+		prIds = append(prIds, r.PullRequestId)
+	}
+	pushes, err := loadPullRequestPushes(db, prIds)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	responses := matchSloResponses(reviews, pushes, sloMinutes, now)
+	metrics := aggregateSloMetrics(responses, sloMinutes, now)
+	for _, m := range metrics {
+		if err := db.CreateOrUpdate(m); err != nil {
+			return errors.Default.Wrap(err, "failed to save review SLO metrics")
+		}
+	}
+
+	logger.Info("Calculated review SLO attainment for %d repo/tool weeks (%d responses, SLO %d minutes)",
+		len(metrics), len(responses), sloMinutes)
+	return nil
+}
+
+// loadSloReviews loads the AI reviews of the repo, or of every repo of the
+// project but excludeRepoIds when repoId is empty, with the creation time of their PR
+func loadSloReviews(db dal.Dal, repoId, projectName string, excludeRepoIds []string) ([]sloReview, errors.Error) {
+	clauses := []dal.Clause{
+		dal.Select("ar.repo_id, ar.pull_request_id, ar.ai_tool, ar.created_date, pr.created_date AS pr_created_date"),
+		dal.From("_tool_aireview_reviews ar"),
+		dal.Join("JOIN pull_requests pr ON ar.pull_request_id = pr.id"),
+	}
+	if repoId != "" {
+		clauses = append(clauses, dal.Where("ar.repo_id = ? AND ar.body NOT LIKE '%Review skipped%'", repoId))
+	} else {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON ar.repo_id = pm.row_id AND pm.`table` = 'repos'"),
+			dal.Where("pm.project_name = ? AND ar.body NOT LIKE '%Review skipped%'", projectName),
+		)
+		clauses = append(clauses, excludedRepoClauses("ar.repo_id", excludeRepoIds)...)
+	}
+	var reviews []sloReview
+	if err := db.All(&reviews, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load AI reviews for review SLO")
+	}
+	return reviews, nil
+}
+
+// loadPullRequestPushes returns the commit dates of the given PRs, which may repeat,
+// keyed by PR id. The authored date stands in for the push time, which isn't collected.
+func loadPullRequestPushes(db dal.Dal, pullRequestIds []string) (map[string][]time.Time, errors.Error) {
+	seen := make(map[string]bool)
+	var prIds []string
+	for _, id := range pullRequestIds {
+		if !seen[id] {
+			seen[id] = true
+			prIds = append(prIds, id)
+		}
+	}
+
+	pushes := make(map[string][]time.Time, len(prIds))
+	const batchSize = 500
+	for start := 0; start < len(prIds); start += batchSize {
+		end := start + batchSize
+		if end > len(prIds) {
+			end = len(prIds)
+		}
+		var rows []struct {
+			PullRequestId      string    `gorm:"column:pull_request_id"`
+			CommitAuthoredDate time.Time `gorm:"column:commit_authored_date"`
+		}
+		err := db.All(&rows,
+			dal.Select("pull_request_id, commit_authored_date"),
+			dal.From("pull_request_commits"),
+			dal.Where("pull_request_id IN ?", prIds[start:end]),
+		)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to load PR commits")
+		}
+		for _, row := range rows {
+			pushes[row.PullRequestId] = append(pushes[row.PullRequestId], row.CommitAuthoredDate)
+		}
+	}
+	return pushes, nil
+}
+
+// matchSloResponses pairs each PR open or push with the first review of each
+// AI tool that reviewed the PR, made before the next push. A trigger without
+// such a review is a missed response once its SLO window elapsed before now;
+// a trigger superseded by another push within the window is not counted.
+func matchSloResponses(reviews []sloReview, pushes map[string][]time.Time, sloMinutes int, now time.Time) []sloResponse {
+	type toolKey struct {
+		PullRequestId string
+		AiTool        string
+	}
+	byTool := make(map[toolKey][]sloReview)
+	var order []toolKey
+	for _, r := range reviews {
+		key := toolKey{PullRequestId: r.PullRequestId, AiTool: r.AiTool}
+		if _, ok := byTool[key]; !ok {
+			order = append(order, key)
+		}
+		byTool[key] = append(byTool[key], r)
+	}
+
+	window := time.Duration(sloMinutes) * time.Minute
+	var responses []sloResponse
+	for _, key := range order {
+		toolReviews := byTool[key]
+		triggers := sloTriggers(toolReviews[0].PrCreatedDate, pushes[key.PullRequestId])
+		for i, triggeredAt := range triggers {
+			var next *time.Time
+			if i+1 < len(triggers) {
+				next = &triggers[i+1]
+			}
+			var first *sloReview
+			for j := range toolReviews {
+				r := &toolReviews[j]
+				if r.CreatedDate.Before(triggeredAt) || (next != nil && !r.CreatedDate.Before(*next)) {
+					continue
+				}
+				if first == nil || r.CreatedDate.Before(first.CreatedDate) {
+					first = r
+				}
+			}
+			response := sloResponse{RepoId: toolReviews[0].RepoId, AiTool: key.AiTool, TriggeredAt: triggeredAt}
+			if first != nil {
+				response.LatencyMinutes = first.CreatedDate.Sub(triggeredAt).Minutes()
+			} else {
+				deadline := triggeredAt.Add(window)
+				if now.Before(deadline) || (next != nil && !next.After(deadline)) {
+					continue
+				}
+				response.Missed = true
+			}
+			responses = append(responses, response)
+		}
+	}
+	return responses
+}
+
+// sloTriggers returns the PR open followed by the pushes after it, sorted and without repeats
+func sloTriggers(prCreatedDate time.Time, pushes []time.Time) []time.Time {
+	triggers := []time.Time{prCreatedDate}
+	for _, p := range pushes {
+		if p.After(prCreatedDate) {
+			triggers = append(triggers, p)
+		}
+	}
+	sort.Slice(triggers, func(i, j int) bool { return triggers[i].Before(triggers[j]) })
+	unique := triggers[:1]
+	for _, t := range triggers[1:] {
+		if !t.Equal(unique[len(unique)-1]) {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// aggregateSloMetrics groups responses by repo, tool and the UTC week of the
+// trigger, sorted by repo, tool and week. Missed responses count against the
+// attainment but not in the latency percentiles.
+func aggregateSloMetrics(responses []sloResponse, sloMinutes int, calculatedAt time.Time) []*models.AiReviewSloMetric {
+	latencies := make(map[sloWeekKey][]float64)
+	missed := make(map[sloWeekKey]int)
+	var keys []sloWeekKey
+	for _, r := range responses {
+		if r.RepoId == "" || r.AiTool == "" {
+			continue
+		}
+		key := sloWeekKey{RepoId: r.RepoId, AiTool: r.AiTool, WeekStart: weekStart(r.TriggeredAt)}
+		if _, ok := latencies[key]; !ok {
+			keys = append(keys, key)
+			latencies[key] = nil
+		}
+		if r.Missed {
+			missed[key]++
+		} else {
+			latencies[key] = append(latencies[key], r.LatencyMinutes)
+		}
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].RepoId != keys[j].RepoId {
+			return keys[i].RepoId < keys[j].RepoId
+		}
+		if keys[i].AiTool != keys[j].AiTool {
+			return keys[i].AiTool < keys[j].AiTool
+		}
+		return keys[i].WeekStart.Before(keys[j].WeekStart)
+	})
+
+	metrics := make([]*models.AiReviewSloMetric, 0, len(keys))
+	for _, key := range keys {
+		values := latencies[key]
+		sort.Float64s(values)
+		within := 0
+		for _, v := range values {
+			if v <= float64(sloMinutes) {
+				within++
+			}
+		}
+		metrics = append(metrics, &models.AiReviewSloMetric{
+			Id:                generateSloMetricId(key),
+			RepoId:            key.RepoId,
+			AiTool:            key.AiTool,
+			WeekStart:         key.WeekStart,
+			SloMinutes:        sloMinutes,
+			Reviews:           len(values),
+			Missed:            missed[key],
+			WithinSlo:         within,
+			AttainmentPct:     float64(within) / float64(len(values)+missed[key]) * 100,
+			P50LatencyMinutes: nearestRankPercentile(values, 50),
+			P90LatencyMinutes: nearestRankPercentile(values, 90),
+			CalculatedAt:      calculatedAt,
+		})
+	}
+	return metrics
+}
+
+// weekStart returns Monday 00:00 UTC of the week containing t
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
+
+// nearestRankPercentile returns the nearest-rank percentile p (0-100) of sorted values
+func nearestRankPercentile(sorted []float64, p int) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	// rank = ceil(p/100 * n)
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// generateSloMetricId creates a deterministic ID for a review SLO metrics record
+func generateSloMetricId(key sloWeekKey) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s", key.RepoId, key.AiTool, key.WeekStart.Format("2006-01-02"))))
+	return "aislo:" + hex.EncodeToString(hash[:16])
+}
@@ -0,0 +1,158 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchSloResponses(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+
+	reviews := []sloReview{
+		// PR opened at 0: coderabbit answers at 5 and again at 8, only the first counts
+		{RepoId: "repo1", PullRequestId: "pr1", AiTool: "coderabbit", CreatedDate: at(8), PrCreatedDate: at(0)},
+		{RepoId: "repo1", PullRequestId: "pr1", AiTool: "coderabbit", CreatedDate: at(5), PrCreatedDate: at(0)},
+		// qodo answers the open separately
+		{RepoId: "repo1", PullRequestId: "pr1", AiTool: "qodo", CreatedDate: at(30), PrCreatedDate: at(0)},
+		// push at 60, answered at 75
+		{RepoId: "repo1", PullRequestId: "pr1", AiTool: "coderabbit", CreatedDate: at(75), PrCreatedDate: at(0)},
+	}
+	pushes := map[string][]time.Time{
+		// a commit authored before the PR was opened is not a trigger
+		"pr1": {at(-120), at(60), at(200)},
+	}
+
+	responses := matchSloResponses(reviews, pushes, 10, at(1000))
+	assert.Equal(t, []sloResponse{
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: at(0), LatencyMinutes: 5},
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: at(60), LatencyMinutes: 15},
+		// pushes no review answered are missed once the window elapsed
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: at(200), Missed: true},
+		{RepoId: "repo1", AiTool: "qodo", TriggeredAt: at(0), LatencyMinutes: 30},
+		{RepoId: "repo1", AiTool: "qodo", TriggeredAt: at(60), Missed: true},
+		{RepoId: "repo1", AiTool: "qodo", TriggeredAt: at(200), Missed: true},
+	}, responses)
+}
+
+func TestMatchSloResponses_UnansweredWithinWindow(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return base.Add(time.Duration(minutes) * time.Minute) }
+	reviews := []sloReview{
+		{RepoId: "repo1", PullRequestId: "pr1", AiTool: "coderabbit", CreatedDate: at(8), PrCreatedDate: at(0)},
+	}
+	// the open is superseded by the push at 3 before its window elapsed, the push at 40 is still within its window
+	pushes := map[string][]time.Time{"pr1": {at(3), at(40)}}
+
+	assert.Equal(t, []sloResponse{
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: at(3), LatencyMinutes: 5},
+	}, matchSloResponses(reviews, pushes, 10, at(45)))
+}
+
+func TestMatchSloResponses_ReviewBeforePrCreated(t *testing.T) {
+	base := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	reviews := []sloReview{
+		{RepoId: "repo1", PullRequestId: "pr1", AiTool: "coderabbit", CreatedDate: base.Add(-time.Minute), PrCreatedDate: base},
+	}
+	// the review doesn't answer the open, which is missed
+	assert.Equal(t, []sloResponse{
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: base, Missed: true},
+	}, matchSloResponses(reviews, nil, 10, base.Add(time.Hour)))
+}
+
+func TestAggregateSloMetrics(t *testing.T) {
+	monday := time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 4, 20, 0, 0, 0, 0, time.UTC)
+	responses := []sloResponse{
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.Add(2 * time.Hour), LatencyMinutes: 4},
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.AddDate(0, 0, 6), LatencyMinutes: 10},
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.AddDate(0, 0, 3), LatencyMinutes: 25},
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.AddDate(0, 0, 1), LatencyMinutes: 2},
+		// next week
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.AddDate(0, 0, 7), LatencyMinutes: 12},
+		// missing scope is skipped
+		{RepoId: "", AiTool: "coderabbit", TriggeredAt: monday, LatencyMinutes: 1},
+	}
+
+	metrics := aggregateSloMetrics(responses, 10, now)
+	if assert.Len(t, metrics, 2) {
+		first := metrics[0]
+		assert.Equal(t, monday, first.WeekStart)
+		assert.Equal(t, 10, first.SloMinutes)
+		assert.Equal(t, 4, first.Reviews)
+		assert.Equal(t, 3, first.WithinSlo)
+		assert.InDelta(t, 75.0, first.AttainmentPct, 0.001)
+		assert.Equal(t, 4.0, first.P50LatencyMinutes)
+		assert.Equal(t, 25.0, first.P90LatencyMinutes)
+		assert.Equal(t, now, first.CalculatedAt)
+		assert.Equal(t, generateSloMetricId(sloWeekKey{RepoId: "repo1", AiTool: "coderabbit", WeekStart: monday}), first.Id)
+
+		second := metrics[1]
+		assert.Equal(t, monday.AddDate(0, 0, 7), second.WeekStart)
+		assert.Equal(t, 1, second.Reviews)
+		assert.Equal(t, 0, second.WithinSlo)
+		assert.Equal(t, 0.0, second.AttainmentPct)
+	}
+}
+
+func TestAggregateSloMetrics_Missed(t *testing.T) {
+	monday := time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)
+	responses := []sloResponse{
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.Add(time.Hour), LatencyMinutes: 4},
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.Add(2 * time.Hour), LatencyMinutes: 20},
+		{RepoId: "repo1", AiTool: "coderabbit", TriggeredAt: monday.Add(3 * time.Hour), Missed: true},
+		{RepoId: "repo1", AiTool: "qodo", TriggeredAt: monday.Add(time.Hour), Missed: true},
+	}
+
+	metrics := aggregateSloMetrics(responses, 10, monday.AddDate(0, 0, 7))
+	if assert.Len(t, metrics, 2) {
+		assert.Equal(t, 2, metrics[0].Reviews)
+		assert.Equal(t, 1, metrics[0].WithinSlo)
+		assert.Equal(t, 1, metrics[0].Missed)
+		assert.InDelta(t, 33.333, metrics[0].AttainmentPct, 0.001)
+		// missed responses are left out of the latency percentiles
+		assert.Equal(t, 20.0, metrics[0].P90LatencyMinutes)
+
+		assert.Equal(t, "qodo", metrics[1].AiTool)
+		assert.Equal(t, 0, metrics[1].Reviews)
+		assert.Equal(t, 1, metrics[1].Missed)
+		assert.Equal(t, 0.0, metrics[1].AttainmentPct)
+	}
+}
+
+func TestWeekStart(t *testing.T) {
+	monday := time.Date(2026, 4, 6, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, weekStart(monday))
+	assert.Equal(t, monday, weekStart(time.Date(2026, 4, 12, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, monday.AddDate(0, 0, 7), weekStart(time.Date(2026, 4, 13, 0, 0, 0, 0, time.UTC)))
+	// converted to UTC first
+	est := time.FixedZone("EST", -5*3600)
+	assert.Equal(t, monday, weekStart(time.Date(2026, 4, 5, 20, 0, 0, 0, est)))
+}
+
+func TestNearestRankPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, nearestRankPercentile(nil, 50))
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, 5.0, nearestRankPercentile(values, 50))
+	assert.Equal(t, 9.0, nearestRankPercentile(values, 90))
+	assert.Equal(t, 1.0, nearestRankPercentile(values, 0))
+}
@@ -0,0 +1,127 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// retainedBodyRunes is how much of a review body survives truncation, enough
+// to keep the opening of the review readable in the UI.
+const retainedBodyRunes = 500
+
+const truncatedBodyMarker = "\n\n_[review body truncated by retention policy]_"
+
+// cleanupBatchSize is how many review bodies are loaded and truncated at a time
+const cleanupBatchSize = 100
+
+var CleanupReviewBodiesMeta = plugin.SubTaskMeta{
+	Name:             "cleanupReviewBodies",
+	EntryPoint:       CleanupReviewBodies,
+	EnabledByDefault: true,
+	Description:      "Truncate bodies of AI reviews older than the configured retention period",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractAiReviewFindingsMeta},
+}
+
+// CleanupReviewBodies truncates the body of reviews created before the
+// retention cutoff. Summary, metrics and extracted findings are left untouched.
+// Runs last so earlier subtasks always see the full body re-extracted from
+// pull_request_comments. Only bodies not truncated yet are selected, and their
+// ids are collected before any row is updated, so no cursor over the table is
+// open while it is written.
+func CleanupReviewBodies(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	config := data.Options.ScopeConfig
+	if config == nil || config.BodyRetentionDays <= 0 {
+		logger.Info("cleanupReviewBodies: skipping — bodyRetentionDays is not set")
+		return nil
+	}
+
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -config.BodyRetentionDays)
+
+	clauses := []dal.Clause{
+		dal.From(&models.AiReview{}),
+	}
+	if data.Options.ProjectName != "" {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON _tool_aireview_reviews.repo_id = pm.row_id AND pm.`table` = 'repos'"),
+			dal.Where("pm.project_name = ?", data.Options.ProjectName),
+		)
+		clauses = append(clauses, excludedRepoClauses("_tool_aireview_reviews.repo_id", data.Options.ExcludeRepoIds)...)
+	} else {
+		clauses = append(clauses, dal.Where("_tool_aireview_reviews.repo_id = ?", data.Options.RepoId))
+	}
+	clauses = append(clauses, dal.Where(
+		"_tool_aireview_reviews.created_date < ? AND _tool_aireview_reviews.body_truncated_at IS NULL", cutoff,
+	))
+
+	var ids []string
+	if err := db.Pluck("_tool_aireview_reviews.id", &ids, clauses...); err != nil {
+		return errors.Default.Wrap(err, "failed to query AI reviews for body retention")
+	}
+
+	truncated := 0
+	for start := 0; start < len(ids); start += cleanupBatchSize {
+		end := start + cleanupBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		var reviews []models.AiReview
+		err := db.All(&reviews,
+			dal.Select("id, body"),
+			dal.From(&models.AiReview{}),
+			dal.Where("id IN ?", ids[start:end]),
+		)
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to load AI review bodies")
+		}
+		for _, review := range reviews {
+			err := db.UpdateColumns(&models.AiReview{}, []dal.DalSet{
+				{ColumnName: "body", Value: truncateReviewBody(review.Body)},
+				{ColumnName: "body_truncated_at", Value: now},
+			}, dal.Where("id = ?", review.Id))
+			if err != nil {
+				return errors.Default.Wrap(err, "failed to truncate AI review body")
+			}
+			truncated++
+		}
+	}
+
+	logger.Info("cleanupReviewBodies: truncated %d review bodies older than %d days", truncated, config.BodyRetentionDays)
+	return nil
+}
+
+// truncateReviewBody keeps the first retainedBodyRunes runes of the body and
+// appends a marker so readers know the rest was dropped on purpose.
+func truncateReviewBody(body string) string {
+	runes := []rune(body)
+	if len(runes) <= retainedBodyRunes {
+		return body
+	}
+	return string(runes[:retainedBodyRunes]) + truncatedBodyMarker
+}
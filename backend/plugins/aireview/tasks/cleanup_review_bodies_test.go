@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTruncateReviewBody(t *testing.T) {
+	t.Run("short body unchanged", func(t *testing.T) {
+		assert.Equal(t, "LGTM", truncateReviewBody("LGTM"))
+	})
+
+	t.Run("body at limit unchanged", func(t *testing.T) {
+		body := strings.Repeat("a", retainedBodyRunes)
+		assert.Equal(t, body, truncateReviewBody(body))
+	})
+
+	t.Run("long body truncated with marker", func(t *testing.T) {
+		body := strings.Repeat("a", retainedBodyRunes+100)
+		got := truncateReviewBody(body)
+		assert.True(t, strings.HasSuffix(got, truncatedBodyMarker))
+		assert.Equal(t, retainedBodyRunes, len([]rune(strings.TrimSuffix(got, truncatedBodyMarker))))
+	})
+
+	t.Run("multi-byte runes are not split", func(t *testing.T) {
+		body := strings.Repeat("🐰", retainedBodyRunes+1)
+		got := strings.TrimSuffix(truncateReviewBody(body), truncatedBodyMarker)
+		assert.Equal(t, strings.Repeat("🐰", retainedBodyRunes), got)
+	})
+}
+
+func TestCleanupReviewBodies_Disabled(t *testing.T) {
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDalI := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+
+	data := &AiReviewTaskData{
+		Options: &AiReviewOptions{RepoId: "repo-1", ScopeConfig: models.GetDefaultScopeConfig()},
+	}
+
+	mockCtx.On("GetDal").Return(mockDalI)
+	mockCtx.On("GetLogger").Return(mockLogger)
+	mockCtx.On("GetData").Return(data)
+	mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
+
+	err := CleanupReviewBodies(mockCtx)
+	assert.Nil(t, err)
+	mockDalI.AssertNotCalled(t, "Pluck", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCleanupReviewBodies_TruncatesOldReviews(t *testing.T) {
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDalI := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+
+	config := models.GetDefaultScopeConfig()
+	config.BodyRetentionDays = 30
+	data := &AiReviewTaskData{
+		Options: &AiReviewOptions{RepoId: "repo-1", ScopeConfig: config},
+	}
+
+	mockCtx.On("GetDal").Return(mockDalI)
+	mockCtx.On("GetLogger").Return(mockLogger)
+	mockCtx.On("GetData").Return(data)
+	mockLogger.On("Info", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	// The ids are collected first, then the bodies are loaded one batch at a time
+	var calls []string
+	mockDalI.On("Pluck", "_tool_aireview_reviews.id", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		calls = append(calls, "Pluck")
+		*args.Get(1).(*[]string) = []string{"review-1"}
+	}).Return(nil)
+	mockDalI.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		calls = append(calls, "All")
+		*args.Get(0).(*[]models.AiReview) = []models.AiReview{{Id: "review-1", Body: strings.Repeat("x", retainedBodyRunes*2)}}
+	}).Return(nil)
+
+	var set []dal.DalSet
+	mockDalI.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		calls = append(calls, "UpdateColumns")
+		set = args.Get(1).([]dal.DalSet)
+	}).Return(nil)
+
+	err := CleanupReviewBodies(mockCtx)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Pluck", "All", "UpdateColumns"}, calls)
+	assert.Len(t, set, 2)
+	assert.Equal(t, "body", set[0].ColumnName)
+	assert.True(t, strings.HasSuffix(set[0].Value.(string), truncatedBodyMarker))
+	assert.Equal(t, "body_truncated_at", set[1].ColumnName)
+}
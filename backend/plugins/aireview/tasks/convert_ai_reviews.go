@@ -83,6 +83,7 @@ func ConvertAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
 			PullRequestId:        src.PullRequestId,
 			RepoId:               src.RepoId,
 			AiTool:               src.AiTool,
+			ToolVersion:          src.ToolVersion,
 			CreatedDate:          src.CreatedDate,
 			RiskLevel:            src.RiskLevel,
 			RiskScore:            src.RiskScore,
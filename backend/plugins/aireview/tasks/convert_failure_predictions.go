@@ -81,6 +81,8 @@ func ConvertFailurePredictions(taskCtx plugin.SubTaskContext) errors.Error {
 			RepoId:            src.RepoId,
 			RepoName:          src.RepoName,
 			AiTool:            src.AiTool,
+			ToolVersion:       src.ToolVersion,
+			FindingCategory:   src.FindingCategory,
 			CiFailureSource:   src.CiFailureSource,
 			PrTitle:           src.PrTitle,
 			PrUrl:             src.PrUrl,
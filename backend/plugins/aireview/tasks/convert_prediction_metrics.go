@@ -78,6 +78,9 @@ func ConvertPredictionMetrics(taskCtx plugin.SubTaskContext) errors.Error {
 			ProjectName:              projectName,
 			RepoId:                   src.RepoId,
 			AiTool:                   src.AiTool,
+			ToolVersion:              src.ToolVersion,
+			Category:                 src.Category,
+			IsAggregate:              src.IsAggregate,
 			CiFailureSource:          src.CiFailureSource,
 			PeriodStart:              src.PeriodStart,
 			PeriodEnd:                src.PeriodEnd,
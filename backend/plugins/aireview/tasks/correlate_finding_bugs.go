@@ -0,0 +1,227 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var CorrelateFindingsWithBugsMeta = plugin.SubTaskMeta{
+	Name:             "correlateFindingsWithBugs",
+	EntryPoint:       CorrelateFindingsWithBugs,
+	EnabledByDefault: true,
+	Description:      "Flag AI findings whose file was touched by a fix for a bug filed after the PR was merged",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&MatchSuggestionDiffsMeta},
+}
+
+// findingFileRef holds a finding that points at a file, with the time its PR landed
+type findingFileRef struct {
+	Id              string    `gorm:"column:id"`
+	PullRequestId   string    `gorm:"column:pull_request_id"`
+	FilePath        string    `gorm:"column:file_path"`
+	MatchedFilePath string    `gorm:"column:matched_file_path"`
+	PrDate          time.Time `gorm:"column:pr_date"`
+}
+
+// bugFixFile is a file changed by a commit linked to a bug issue
+type bugFixFile struct {
+	IssueId          string    `gorm:"column:issue_id"`
+	IssueCreatedDate time.Time `gorm:"column:issue_created_date"`
+	PullRequestId    string    `gorm:"column:pull_request_id"` // empty when linked through issue_commits
+	FilePath         string    `gorm:"column:file_path"`
+}
+
+// bugMatch is the earliest bug that materialized a finding
+type bugMatch struct {
+	IssueId   string
+	CreatedAt time.Time
+}
+
+// CorrelateFindingsWithBugs validates findings at file level: a finding is
+// "materialized" when a bug issue created within ObservationWindowDays after the
+// PR was merged is linked (via issue_commits or pull_request_issues) to a change
+// touching the file the finding was about. The flags are recomputed on every run.
+func CorrelateFindingsWithBugs(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	repoId := data.Options.RepoId
+	windowDays := data.Options.ScopeConfig.ObservationWindowDays
+	if windowDays <= 0 {
+		windowDays = 14
+	}
+
+	findings, err := loadFindingFileRefs(db, repoId)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load findings with file paths")
+	}
+
+	// Reset previous results so findings whose bug link disappeared are cleared
+	resetErr := db.UpdateColumns(
+		&models.AiReviewFinding{},
+		[]dal.DalSet{
+			{ColumnName: "bug_materialized", Value: false},
+			{ColumnName: "materialized_issue_id", Value: ""},
+			{ColumnName: "materialized_at", Value: nil},
+		},
+		dal.Where("repo_id = ? AND bug_materialized = ?", repoId, true),
+	)
+	if resetErr != nil {
+		return errors.Default.Wrap(resetErr, "failed to reset finding bug correlation")
+	}
+
+	if len(findings) == 0 {
+		logger.Info("No findings with file paths for repo %s, skipping bug correlation", repoId)
+		return nil
+	}
+
+	since := findings[0].PrDate
+	for _, f := range findings {
+		if f.PrDate.Before(since) {
+			since = f.PrDate
+		}
+	}
+	bugFiles, err := loadBugFixFiles(db, repoId, since)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load files changed by bug fixes")
+	}
+	logger.Info("Correlating %d findings with %d bug fix file changes (window=%d days)", len(findings), len(bugFiles), windowDays)
+
+	matches := correlateFindings(findings, bugFiles, time.Duration(windowDays)*24*time.Hour)
+	for findingId, match := range matches {
+		materializedAt := match.CreatedAt
+		updateErr := db.UpdateColumns(
+			&models.AiReviewFinding{},
+			[]dal.DalSet{
+				{ColumnName: "bug_materialized", Value: true},
+				{ColumnName: "materialized_issue_id", Value: match.IssueId},
+				{ColumnName: "materialized_at", Value: &materializedAt},
+			},
+			dal.Where("id = ?", findingId),
+		)
+		if updateErr != nil {
+			logger.Warn(updateErr, "failed to update bug correlation for finding %s", findingId)
+		}
+	}
+
+	logger.Info("Bug correlation complete: %d/%d findings materialized", len(matches), len(findings))
+	return nil
+}
+
+// correlateFindings returns, per finding id, the earliest bug created within
+// window after the finding's PR landed whose fix touched the finding's file.
+// Bugs fixed by the reviewed PR itself are ignored.
+func correlateFindings(findings []findingFileRef, bugFiles []bugFixFile, window time.Duration) map[string]bugMatch {
+	matches := make(map[string]bugMatch)
+	for _, f := range findings {
+		filePath := f.MatchedFilePath
+		if filePath == "" {
+			filePath = f.FilePath
+		}
+		if filePath == "" || f.PrDate.IsZero() {
+			continue
+		}
+		deadline := f.PrDate.Add(window)
+		for _, bf := range bugFiles {
+			if bf.PullRequestId != "" && bf.PullRequestId == f.PullRequestId {
+				continue
+			}
+			if bf.IssueCreatedDate.Before(f.PrDate) || bf.IssueCreatedDate.After(deadline) {
+				continue
+			}
+			if !filePathsMatch(bf.FilePath, filePath) {
+				continue
+			}
+			if existing, ok := matches[f.Id]; !ok || bf.IssueCreatedDate.Before(existing.CreatedAt) {
+				matches[f.Id] = bugMatch{IssueId: bf.IssueId, CreatedAt: bf.IssueCreatedDate}
+			}
+		}
+	}
+	return matches
+}
+
+// loadFindingFileRefs gets findings of the repo that mention a file, with the
+// merge date of their PR (or creation date for PRs that were not merged)
+func loadFindingFileRefs(db dal.Dal, repoId string) ([]findingFileRef, error) {
+	cursor, err := db.Cursor(
+		dal.Select("f.id, f.pull_request_id, f.file_path, f.matched_file_path, COALESCE(pr.merged_date, pr.created_date) as pr_date"),
+		dal.From("_tool_aireview_findings f"),
+		dal.Join("JOIN pull_requests pr ON pr.id = f.pull_request_id"),
+		dal.Where("f.repo_id = ? AND (f.file_path <> '' OR f.matched_file_path <> '')", repoId),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var findings []findingFileRef
+	for cursor.Next() {
+		var f findingFileRef
+		if scanErr := db.Fetch(cursor, &f); scanErr != nil {
+			return nil, scanErr
+		}
+		findings = append(findings, f)
+	}
+	return findings, nil
+}
+
+// loadBugFixFiles gets the files changed in the repo by commits linked to bug
+// issues created since the given time, either directly through issue_commits
+// or through the commits of a PR linked in pull_request_issues
+func loadBugFixFiles(db dal.Dal, repoId string, since time.Time) ([]bugFixFile, error) {
+	var files []bugFixFile
+
+	var viaCommits []bugFixFile
+	err := db.All(&viaCommits,
+		dal.Select("DISTINCT i.id as issue_id, i.created_date as issue_created_date, '' as pull_request_id, cf.file_path"),
+		dal.From("issues i"),
+		dal.Join("JOIN issue_commits ic ON ic.issue_id = i.id"),
+		dal.Join("JOIN repo_commits rc ON rc.commit_sha = ic.commit_sha"),
+		dal.Join("JOIN commit_files cf ON cf.commit_sha = ic.commit_sha"),
+		dal.Where("i.type = ? AND i.created_date >= ? AND rc.repo_id = ?", ticket.BUG, since, repoId),
+	)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, viaCommits...)
+
+	var viaPullRequests []bugFixFile
+	err = db.All(&viaPullRequests,
+		dal.Select("DISTINCT i.id as issue_id, i.created_date as issue_created_date, pri.pull_request_id, cf.file_path"),
+		dal.From("issues i"),
+		dal.Join("JOIN pull_request_issues pri ON pri.issue_id = i.id"),
+		dal.Join("JOIN pull_requests pr ON pr.id = pri.pull_request_id"),
+		dal.Join("JOIN pull_request_commits prc ON prc.pull_request_id = pr.id"),
+		dal.Join("JOIN commit_files cf ON cf.commit_sha = prc.commit_sha"),
+		dal.Where("i.type = ? AND i.created_date >= ? AND pr.base_repo_id = ?", ticket.BUG, since, repoId),
+	)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, viaPullRequests...)
+
+	return files, nil
+}
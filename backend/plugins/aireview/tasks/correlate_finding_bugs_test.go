@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelateFindings(t *testing.T) {
+	merged := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	window := 14 * 24 * time.Hour
+	findings := []findingFileRef{
+		{Id: "f1", PullRequestId: "pr1", FilePath: "pkg/handler.go", PrDate: merged},
+		{Id: "f2", PullRequestId: "pr1", FilePath: "pkg/other.go", PrDate: merged},
+		{Id: "f3", PullRequestId: "pr1", MatchedFilePath: "backend/pkg/late.go", PrDate: merged},
+		{Id: "f4", PullRequestId: "pr1", PrDate: merged},
+	}
+	bugFiles := []bugFixFile{
+		// fixed in a later PR, two bugs: the earliest one wins
+		{IssueId: "bug2", IssueCreatedDate: merged.Add(5 * 24 * time.Hour), PullRequestId: "pr2", FilePath: "pkg/handler.go"},
+		{IssueId: "bug1", IssueCreatedDate: merged.Add(2 * 24 * time.Hour), FilePath: "a/pkg/handler.go"},
+		// filed before the PR landed
+		{IssueId: "bug0", IssueCreatedDate: merged.Add(-time.Hour), FilePath: "pkg/other.go"},
+		// linked to the reviewed PR itself
+		{IssueId: "bug3", IssueCreatedDate: merged.Add(time.Hour), PullRequestId: "pr1", FilePath: "pkg/other.go"},
+		// outside the observation window
+		{IssueId: "bug4", IssueCreatedDate: merged.Add(20 * 24 * time.Hour), FilePath: "pkg/late.go"},
+	}
+
+	matches := correlateFindings(findings, bugFiles, window)
+
+	assert.Len(t, matches, 1)
+	assert.Equal(t, "bug1", matches["f1"].IssueId)
+	assert.Equal(t, merged.Add(2*24*time.Hour), matches["f1"].CreatedAt)
+
+	// a wider window picks up the late bug through the suffix path match
+	matches = correlateFindings(findings, bugFiles, 30*24*time.Hour)
+	assert.Equal(t, "bug4", matches["f3"].IssueId)
+	assert.NotContains(t, matches, "f2")
+	assert.NotContains(t, matches, "f4")
+}
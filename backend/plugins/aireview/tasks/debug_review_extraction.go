@@ -0,0 +1,252 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// maxDebugMatches caps the matches reported per pattern so a long body keeps the report readable
+const maxDebugMatches = 20
+
+// metricPatterns are the regexes parseReviewMetrics reads each metric from, in evaluation order
+var metricPatterns = []struct {
+	metric string
+	re     *regexp.Regexp
+}{
+	{"effortRating", effortRatingRe},
+	{"effortRating", qodoEffortRe},
+	{"effortComplexity", complexityRe},
+	{"effortMinutes", effortTimeRe},
+	{"preMergeChecksPassed", checksPassedRe},
+	{"preMergeChecksFailed", checksFailedRe},
+	{"preMergeChecksInconclusive", checksInconclusiveRe},
+	{"suggestionsAccepted", suggestionsSummaryRe},
+	{"suggestionsAccepted", appliedSuggestionRe},
+	{"suggestionsAccepted", checkedBoxRe},
+	{"suggestionsAccepted", uncheckedBoxRe},
+	{"suggestionsAccepted", committedSuggestionRe},
+	{"issuesFound", issuePatternRes[0]},
+	{"issuesFound", issuePatternRes[1]},
+	{"issuesFound", issuePatternRes[2]},
+	{"suggestionsCount", suggestionKeywordRe},
+	{"filesReviewed", fileReferenceRe},
+	{"linesReviewed", linesChangedRe},
+	{"filesReviewed", copilotReviewedRe},
+	{"issuesFound", copilotReviewedRe},
+	{"riskConfidence", copilotLowConfidenceRe},
+}
+
+// sectionHeadingRe matches the lines a converted body is split into sections on:
+// markdown headings and the bold titles htmlToMarkdown turns <details> summaries into
+var sectionHeadingRe = regexp.MustCompile(`(?m)^(?:#{1,6}\s+(.+)|\*\*([^*\n]+)\*\*)\s*$`)
+
+// ReviewExtractionDebug holds the intermediate artifacts of extracting one review,
+// recomputed from its stored body so they can be compared with the stored metrics
+type ReviewExtractionDebug struct {
+	ReviewId string `json:"reviewId"`
+	// BodyTruncated is set when the body was truncated by cleanupReviewBodies:
+	// the recomputed values then describe the truncated body, not the original one
+	BodyTruncated bool                      `json:"bodyTruncated"`
+	Tool          AiToolMatch               `json:"tool"`
+	ConvertedBody string                    `json:"convertedBody"`
+	Sections      []ReviewSection           `json:"sections"`
+	Summary       string                    `json:"summary"`
+	Risk          RiskMatch                 `json:"risk"`
+	MetricHits    []MetricPatternHit        `json:"metricHits"`
+	ToolVersion   string                    `json:"toolVersion"`
+	Findings      []*models.AiReviewFinding `json:"findings"`
+	Mismatches    []FieldMismatch           `json:"mismatches"`
+}
+
+// ReviewSection is a heading of the converted body with the text up to the next heading
+type ReviewSection struct {
+	Heading string `json:"heading"`
+	Text    string `json:"text"`
+}
+
+// RiskMatch tells which risk pattern of the scope config set the risk level
+type RiskMatch struct {
+	Level   string `json:"level"`
+	Score   int    `json:"score"`
+	Pattern string `json:"pattern"` // empty when no pattern matched and the default low risk applied
+	Match   string `json:"match"`
+}
+
+// MetricPatternHit lists what one metric regex matched in the body
+type MetricPatternHit struct {
+	Metric  string   `json:"metric"`
+	Pattern string   `json:"pattern"`
+	Count   int      `json:"count"`
+	Matches []string `json:"matches"` // the first maxDebugMatches matches
+}
+
+// FieldMismatch is a stored review field that differs from its recomputed value
+type FieldMismatch struct {
+	Field      string `json:"field"`
+	Stored     any    `json:"stored"`
+	Recomputed any    `json:"recomputed"`
+}
+
+// DebugReviewExtraction replays the extraction of a stored review with the
+// compiled patterns of data and reports every intermediate artifact. It reads
+// nothing but the review, so it can run from the API on demand.
+func DebugReviewExtraction(data *AiReviewTaskData, review *models.AiReview) *ReviewExtractionDebug {
+	body := review.Body
+	report := &ReviewExtractionDebug{
+		ReviewId:      review.Id,
+		BodyTruncated: review.BodyTruncatedAt != nil,
+		Tool:          matchAiTool(data, review.AiToolUser, body),
+		ToolVersion:   extractToolVersion(body),
+		MetricHits:    metricPatternHits(body),
+	}
+
+	// Sections and the summary depend on the tool, fall back to the stored one when detection changed
+	aiTool := report.Tool.Tool
+	if aiTool == "" {
+		aiTool = review.AiTool
+	}
+	report.ConvertedBody = convertReviewBody(data, aiTool, body)
+	report.Sections = splitReviewSections(report.ConvertedBody)
+	report.Summary = extractSummary(data, aiTool, body)
+	report.Risk = matchRisk(data, body)
+
+	replayed := *review
+	replayed.AiTool = aiTool
+	report.Findings = parseFindings(&replayed, data.RiskTermRules)
+
+	metrics := parseReviewMetrics(body)
+	report.Mismatches = compareStoredReview(review, []FieldMismatch{
+		{Field: "aiTool", Recomputed: report.Tool.Tool},
+		{Field: "toolVersion", Recomputed: report.ToolVersion},
+		{Field: "summary", Recomputed: report.Summary},
+		{Field: "riskLevel", Recomputed: report.Risk.Level},
+		{Field: "riskScore", Recomputed: report.Risk.Score},
+		{Field: "riskConfidence", Recomputed: metrics.Confidence},
+		{Field: "issuesFound", Recomputed: metrics.IssuesFound},
+		{Field: "suggestionsCount", Recomputed: metrics.SuggestionsCount},
+		{Field: "suggestionsAccepted", Recomputed: metrics.SuggestionsAccepted},
+		{Field: "filesReviewed", Recomputed: metrics.FilesReviewed},
+		{Field: "linesReviewed", Recomputed: metrics.LinesReviewed},
+		{Field: "effortComplexity", Recomputed: metrics.Complexity},
+		{Field: "effortRating", Recomputed: metrics.EffortRating},
+		{Field: "effortMinutes", Recomputed: metrics.EffortMinutes},
+		{Field: "preMergeChecksPassed", Recomputed: metrics.PreMergeChecksPassed},
+		{Field: "preMergeChecksFailed", Recomputed: metrics.PreMergeChecksFailed},
+		{Field: "preMergeChecksInconclusive", Recomputed: metrics.PreMergeChecksInconclusive},
+	})
+	return report
+}
+
+// storedReviewFields reads the stored value of each field compared by DebugReviewExtraction
+var storedReviewFields = map[string]func(*models.AiReview) any{
+	"aiTool":                     func(r *models.AiReview) any { return r.AiTool },
+	"toolVersion":                func(r *models.AiReview) any { return r.ToolVersion },
+	"summary":                    func(r *models.AiReview) any { return r.Summary },
+	"riskLevel":                  func(r *models.AiReview) any { return r.RiskLevel },
+	"riskScore":                  func(r *models.AiReview) any { return r.RiskScore },
+	"riskConfidence":             func(r *models.AiReview) any { return r.RiskConfidence },
+	"issuesFound":                func(r *models.AiReview) any { return r.IssuesFound },
+	"suggestionsCount":           func(r *models.AiReview) any { return r.SuggestionsCount },
+	"suggestionsAccepted":        func(r *models.AiReview) any { return r.SuggestionsAccepted },
+	"filesReviewed":              func(r *models.AiReview) any { return r.FilesReviewed },
+	"linesReviewed":              func(r *models.AiReview) any { return r.LinesReviewed },
+	"effortComplexity":           func(r *models.AiReview) any { return r.EffortComplexity },
+	"effortRating":               func(r *models.AiReview) any { return r.EffortRating },
+	"effortMinutes":              func(r *models.AiReview) any { return r.EffortMinutes },
+	"preMergeChecksPassed":       func(r *models.AiReview) any { return r.PreMergeChecksPassed },
+	"preMergeChecksFailed":       func(r *models.AiReview) any { return r.PreMergeChecksFailed },
+	"preMergeChecksInconclusive": func(r *models.AiReview) any { return r.PreMergeChecksInconclusive },
+}
+
+// compareStoredReview fills the stored value of each recomputed field and keeps those that differ
+func compareStoredReview(review *models.AiReview, recomputed []FieldMismatch) []FieldMismatch {
+	mismatches := make([]FieldMismatch, 0)
+	for _, field := range recomputed {
+		field.Stored = storedReviewFields[field.Field](review)
+		if !reflect.DeepEqual(field.Stored, field.Recomputed) {
+			mismatches = append(mismatches, field)
+		}
+	}
+	return mismatches
+}
+
+// matchRisk replays detectRiskLevel and reports the pattern and text that set the level
+func matchRisk(data *AiReviewTaskData, body string) RiskMatch {
+	level, score := detectRiskLevel(data, body)
+	risk := RiskMatch{Level: level, Score: score}
+	for _, re := range []*regexp.Regexp{data.RiskHighPatternRegex, data.RiskMediumPatternRegex, data.RiskLowPatternRegex} {
+		if re == nil {
+			continue
+		}
+		if match := re.FindString(body); match != "" {
+			risk.Pattern = re.String()
+			risk.Match = match
+			break
+		}
+	}
+	return risk
+}
+
+// metricPatternHits runs every metric regex on the body and keeps those that matched
+func metricPatternHits(body string) []MetricPatternHit {
+	hits := make([]MetricPatternHit, 0)
+	for _, p := range metricPatterns {
+		matches := p.re.FindAllString(body, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		hit := MetricPatternHit{Metric: p.metric, Pattern: p.re.String(), Count: len(matches), Matches: matches}
+		if len(hit.Matches) > maxDebugMatches {
+			hit.Matches = hit.Matches[:maxDebugMatches]
+		}
+		hits = append(hits, hit)
+	}
+	return hits
+}
+
+// splitReviewSections splits a converted body on its headings; text before the
+// first heading goes into a section with an empty heading
+func splitReviewSections(body string) []ReviewSection {
+	sections := make([]ReviewSection, 0)
+	locs := sectionHeadingRe.FindAllStringSubmatchIndex(body, -1)
+	appendSection := func(heading, text string) {
+		text = strings.TrimSpace(text)
+		if heading != "" || text != "" {
+			sections = append(sections, ReviewSection{Heading: heading, Text: text})
+		}
+	}
+
+	prevEnd := 0
+	prevHeading := ""
+	for _, loc := range locs {
+		appendSection(prevHeading, body[prevEnd:loc[0]])
+		if loc[2] >= 0 {
+			prevHeading = strings.TrimSpace(body[loc[2]:loc[3]])
+		} else {
+			prevHeading = strings.TrimSpace(body[loc[4]:loc[5]])
+		}
+		prevEnd = loc[1]
+	}
+	appendSection(prevHeading, body[prevEnd:])
+	return sections
+}
@@ -0,0 +1,128 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebugTaskData(t *testing.T) *AiReviewTaskData {
+	data := &AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: models.GetDefaultScopeConfig()}}
+	require.NoError(t, CompilePatterns(data))
+	return data
+}
+
+func TestDebugReviewExtraction(t *testing.T) {
+	data := newDebugTaskData(t)
+	body := "## Walkthrough\nAdds retries to the uploader in pkg/upload/client.go.\n\n" +
+		"## Review effort\n🎯 3 (Moderate) | ⏱️ ~20 minutes\n\n" +
+		"**Pre-merge checks**\n✅ 2 checks passed"
+
+	metrics := parseReviewMetrics(body)
+	riskLevel, riskScore := detectRiskLevel(data, body)
+	review := &models.AiReview{
+		Id:                   "aireview:1",
+		AiTool:               models.AiToolCodeRabbit,
+		AiToolUser:           "coderabbitai[bot]",
+		Body:                 body,
+		Summary:              extractSummary(data, models.AiToolCodeRabbit, body),
+		ToolVersion:          extractToolVersion(body),
+		RiskLevel:            riskLevel,
+		RiskScore:            riskScore,
+		RiskConfidence:       metrics.Confidence,
+		IssuesFound:          metrics.IssuesFound,
+		SuggestionsCount:     metrics.SuggestionsCount,
+		FilesReviewed:        metrics.FilesReviewed,
+		EffortComplexity:     metrics.Complexity,
+		EffortRating:         metrics.EffortRating,
+		EffortMinutes:        metrics.EffortMinutes,
+		PreMergeChecksPassed: 1, // stale value from an older parser
+	}
+
+	report := DebugReviewExtraction(data, review)
+
+	assert.False(t, report.BodyTruncated)
+	assert.Equal(t, models.AiToolCodeRabbit, report.Tool.Tool)
+	assert.Equal(t, "username", report.Tool.MatchedBy)
+	assert.Equal(t, []ReviewSection{
+		{Heading: "Walkthrough", Text: "Adds retries to the uploader in pkg/upload/client.go."},
+		{Heading: "Review effort", Text: "🎯 3 (Moderate) | ⏱️ ~20 minutes"},
+		{Heading: "Pre-merge checks", Text: "✅ 2 checks passed"},
+	}, report.Sections)
+
+	hits := make(map[string][]string)
+	for _, hit := range report.MetricHits {
+		hits[hit.Metric] = append(hits[hit.Metric], hit.Matches...)
+	}
+	assert.Equal(t, []string{"🎯 3 (Moderate)"}, hits["effortRating"])
+	assert.Equal(t, []string{"⏱️ ~20 minutes"}, hits["effortMinutes"])
+	assert.Equal(t, []string{"pkg/upload/client.go"}, hits["filesReviewed"])
+	assert.Equal(t, []string{"✅ 2 checks passed"}, hits["preMergeChecksPassed"])
+	assert.NotContains(t, hits, "linesReviewed")
+
+	assert.Equal(t, []FieldMismatch{
+		{Field: "preMergeChecksPassed", Stored: 1, Recomputed: 2},
+	}, report.Mismatches)
+}
+
+func TestDebugReviewExtraction_TruncatedBody(t *testing.T) {
+	data := newDebugTaskData(t)
+	truncatedAt := time.Now()
+	review := &models.AiReview{
+		Id:              "aireview:2",
+		AiTool:          models.AiToolQodo,
+		AiToolUser:      "someone",
+		Body:            "[truncated]",
+		IssuesFound:     4,
+		BodyTruncatedAt: &truncatedAt,
+	}
+
+	report := DebugReviewExtraction(data, review)
+
+	assert.True(t, report.BodyTruncated)
+	assert.Empty(t, report.Tool.Tool)
+	assert.Contains(t, report.Mismatches, FieldMismatch{Field: "aiTool", Stored: models.AiToolQodo, Recomputed: ""})
+	assert.Contains(t, report.Mismatches, FieldMismatch{Field: "issuesFound", Stored: 4, Recomputed: 0})
+}
+
+func TestMatchRisk(t *testing.T) {
+	data := newDebugTaskData(t)
+
+	risk := matchRisk(data, "This introduces a security vulnerability in the parser")
+	assert.Equal(t, models.RiskLevelHigh, risk.Level)
+	assert.Equal(t, data.RiskHighPatternRegex.String(), risk.Pattern)
+	assert.NotEmpty(t, risk.Match)
+
+	risk = matchRisk(data, "Nothing to see here")
+	assert.Equal(t, 10, risk.Score)
+	assert.Empty(t, risk.Pattern)
+}
+
+func TestSplitReviewSections(t *testing.T) {
+	assert.Equal(t, []ReviewSection{
+		{Text: "Intro line"},
+		{Heading: "Details", Text: "body"},
+		{Heading: "Empty"},
+	}, splitReviewSections("Intro line\n\n**Details**\nbody\n### Empty"))
+	assert.Empty(t, splitReviewSections(""))
+}
@@ -0,0 +1,213 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var DetectFindingTrendsMeta = plugin.SubTaskMeta{
+	Name:             "detectFindingTrends",
+	EntryPoint:       DetectFindingTrends,
+	EnabledByDefault: true,
+	Description:      "Flag finding categories whose count rose notably in the last 4 weeks compared with the prior 4 weeks",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractAiReviewFindingsMeta},
+}
+
+const (
+	// trendWindowDays is the length of both the current and the prior period
+	trendWindowDays = 28
+	// trendMinFindings is the fewest findings in the current period worth an alert
+	trendMinFindings = 5
+	// trendMinRatio is the smallest current/prior ratio worth an alert
+	trendMinRatio = 1.5
+	// trendMinZScore is the z-score above which an increase is unlikely to be
+	// noise (about 2.5% one-sided for equal Poisson rates)
+	trendMinZScore = 2.0
+)
+
+// categoryPeriodCount is the number of findings of one repo, tool and
+// category in the current and the prior period
+type categoryPeriodCount struct {
+	RepoId       string `gorm:"column:repo_id"`
+	AiTool       string `gorm:"column:ai_tool"`
+	Category     string `gorm:"column:category"`
+	CurrentCount int    `gorm:"column:current_count"`
+	PriorCount   int    `gorm:"column:prior_count"`
+}
+
+// DetectFindingTrends compares the finding counts per repo, tool and category
+// of the last 4 complete weeks with the 4 weeks before and writes an alert to
+// _tool_aireview_trend_alerts for each notable increase. The alerts of the
+// window are rewritten on every run.
+func DetectFindingTrends(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	now := time.Now()
+	windowEnd := weekStart(now)
+	windowStart := windowEnd.AddDate(0, 0, -trendWindowDays)
+	priorStart := windowStart.AddDate(0, 0, -trendWindowDays)
+
+	counts, err := loadCategoryPeriodCounts(db, data.Options.RepoId, data.Options.ProjectName, data.Options.ExcludeRepoIds, priorStart, windowStart, windowEnd)
+	if err != nil {
+		return err
+	}
+
+	if err := deleteTrendAlerts(db, data.Options.RepoId, data.Options.ProjectName, data.Options.ExcludeRepoIds, windowEnd); err != nil {
+		return err
+	}
+
+	alerts := detectTrendAlerts(counts, windowStart, windowEnd, now)
+	for _, a := range alerts {
+		if err := db.CreateOrUpdate(a); err != nil {
+			return errors.Default.Wrap(err, "failed to save finding trend alert")
+		}
+	}
+
+	logger.Info("Detected %d rising finding categories out of %d repo/tool/category counts (window %s to %s)",
+		len(alerts), len(counts), windowStart.Format("2006-01-02"), windowEnd.Format("2006-01-02"))
+	return nil
+}
+
+// loadCategoryPeriodCounts counts the findings of the repo, or of every repo
+// of the project but excludeRepoIds when repoId is empty, in [priorStart,
+// windowStart) and [windowStart, windowEnd)
+func loadCategoryPeriodCounts(db dal.Dal, repoId, projectName string, excludeRepoIds []string, priorStart, windowStart, windowEnd time.Time) ([]categoryPeriodCount, errors.Error) {
+	clauses := []dal.Clause{
+		dal.Select("f.repo_id, f.ai_tool, f.category, "+
+			"SUM(CASE WHEN f.created_date >= ? THEN 1 ELSE 0 END) AS current_count, "+
+			"SUM(CASE WHEN f.created_date < ? THEN 1 ELSE 0 END) AS prior_count", windowStart, windowStart),
+		dal.From("_tool_aireview_findings f"),
+	}
+	if repoId != "" {
+		clauses = append(clauses,
+			dal.Where("f.repo_id = ? AND f.category != '' AND f.created_date >= ? AND f.created_date < ?",
+				repoId, priorStart, windowEnd),
+		)
+	} else {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON f.repo_id = pm.row_id AND pm.`table` = 'repos'"),
+			dal.Where("pm.project_name = ? AND f.category != '' AND f.created_date >= ? AND f.created_date < ?",
+				projectName, priorStart, windowEnd),
+		)
+		clauses = append(clauses, excludedRepoClauses("f.repo_id", excludeRepoIds)...)
+	}
+	clauses = append(clauses, dal.Groupby("f.repo_id, f.ai_tool, f.category"))
+
+	var counts []categoryPeriodCount
+	if err := db.All(&counts, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count findings per category")
+	}
+	return counts, nil
+}
+
+// deleteTrendAlerts removes the alerts of the window for the repo, or for
+// every repo of the project but excludeRepoIds when repoId is empty
+func deleteTrendAlerts(db dal.Dal, repoId, projectName string, excludeRepoIds []string, windowEnd time.Time) errors.Error {
+	var err errors.Error
+	if repoId != "" {
+		err = db.Delete(&models.AiFindingTrendAlert{}, dal.Where("repo_id = ? AND window_end = ?", repoId, windowEnd))
+	} else {
+		clauses := append([]dal.Clause{
+			dal.Where("repo_id IN (SELECT row_id FROM project_mapping WHERE project_name = ? AND `table` = 'repos') AND window_end = ?",
+				projectName, windowEnd),
+		}, excludedRepoClauses("repo_id", excludeRepoIds)...)
+		err = db.Delete(&models.AiFindingTrendAlert{}, clauses...)
+	}
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete existing finding trend alerts")
+	}
+	return nil
+}
+
+// detectTrendAlerts returns an alert for each count that rose by at least
+// trendMinRatio to at least trendMinFindings with a z-score of at least
+// trendMinZScore, sorted by repo, tool and category. Given both periods have
+// the same length, the current count of n = current + prior findings is
+// binomial(n, 0.5) when the rate did not change, which gives
+// z = (current - prior) / sqrt(current + prior).
+func detectTrendAlerts(counts []categoryPeriodCount, windowStart, windowEnd, calculatedAt time.Time) []*models.AiFindingTrendAlert {
+	var alerts []*models.AiFindingTrendAlert
+	for _, c := range counts {
+		if c.RepoId == "" || c.Category == "" || c.CurrentCount < trendMinFindings {
+			continue
+		}
+		if float64(c.CurrentCount) < trendMinRatio*float64(c.PriorCount) {
+			continue
+		}
+		z := trendZScore(c.CurrentCount, c.PriorCount)
+		if z < trendMinZScore {
+			continue
+		}
+		changePct := 0.0
+		if c.PriorCount > 0 {
+			changePct = float64(c.CurrentCount-c.PriorCount) / float64(c.PriorCount) * 100
+		}
+		alerts = append(alerts, &models.AiFindingTrendAlert{
+			Id:           generateTrendAlertId(c.RepoId, c.AiTool, c.Category, windowEnd),
+			RepoId:       c.RepoId,
+			AiTool:       c.AiTool,
+			Category:     c.Category,
+			WindowStart:  windowStart,
+			WindowEnd:    windowEnd,
+			CurrentCount: c.CurrentCount,
+			PriorCount:   c.PriorCount,
+			ChangePct:    changePct,
+			ZScore:       z,
+			CalculatedAt: calculatedAt,
+		})
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].RepoId != alerts[j].RepoId {
+			return alerts[i].RepoId < alerts[j].RepoId
+		}
+		if alerts[i].AiTool != alerts[j].AiTool {
+			return alerts[i].AiTool < alerts[j].AiTool
+		}
+		return alerts[i].Category < alerts[j].Category
+	})
+	return alerts
+}
+
+// trendZScore is the normal approximation of the conditional binomial test
+// for two Poisson counts over periods of the same length
+func trendZScore(current, prior int) float64 {
+	if current+prior == 0 {
+		return 0
+	}
+	return float64(current-prior) / math.Sqrt(float64(current+prior))
+}
+
+// generateTrendAlertId creates a deterministic ID for a finding trend alert
+func generateTrendAlertId(repoId, aiTool, category string, windowEnd time.Time) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s:%s:%s:%s", repoId, aiTool, category, windowEnd.Format("2006-01-02"))))
+	return "aitrend:" + hex.EncodeToString(hash[:16])
+}
@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectTrendAlerts(t *testing.T) {
+	windowEnd := time.Date(2026, 4, 27, 0, 0, 0, 0, time.UTC)
+	windowStart := windowEnd.AddDate(0, 0, -trendWindowDays)
+	now := windowEnd.Add(36 * time.Hour)
+
+	counts := []categoryPeriodCount{
+		// security doubled: 24 vs 12, z = 12/6 = 2
+		{RepoId: "repo1", AiTool: "coderabbit", Category: "security", CurrentCount: 24, PriorCount: 12},
+		// steady
+		{RepoId: "repo1", AiTool: "coderabbit", Category: "style", CurrentCount: 40, PriorCount: 38},
+		// new category, enough findings
+		{RepoId: "repo1", AiTool: "qodo", Category: "bug", CurrentCount: 6, PriorCount: 0},
+		// too few findings to matter
+		{RepoId: "repo1", AiTool: "qodo", Category: "performance", CurrentCount: 4, PriorCount: 0},
+		// significant but a small relative rise
+		{RepoId: "repo1", AiTool: "qodo", Category: "best_practice", CurrentCount: 460, PriorCount: 400},
+		// doubled but could be noise: 10 vs 5, z = 5/sqrt(15) ≈ 1.29
+		{RepoId: "repo0", AiTool: "gemini", Category: "security", CurrentCount: 10, PriorCount: 5},
+		// decrease
+		{RepoId: "repo0", AiTool: "gemini", Category: "bug", CurrentCount: 5, PriorCount: 30},
+		// no category
+		{RepoId: "repo0", AiTool: "gemini", Category: "", CurrentCount: 50, PriorCount: 0},
+	}
+
+	alerts := detectTrendAlerts(counts, windowStart, windowEnd, now)
+	if assert.Len(t, alerts, 2) {
+		assert.Equal(t, "coderabbit", alerts[0].AiTool)
+		assert.Equal(t, "security", alerts[0].Category)
+		assert.Equal(t, 24, alerts[0].CurrentCount)
+		assert.Equal(t, 12, alerts[0].PriorCount)
+		assert.InDelta(t, 100.0, alerts[0].ChangePct, 0.001)
+		assert.InDelta(t, 2.0, alerts[0].ZScore, 0.001)
+		assert.Equal(t, windowStart, alerts[0].WindowStart)
+		assert.Equal(t, windowEnd, alerts[0].WindowEnd)
+		assert.Equal(t, now, alerts[0].CalculatedAt)
+
+		assert.Equal(t, "qodo", alerts[1].AiTool)
+		assert.Equal(t, "bug", alerts[1].Category)
+		assert.Equal(t, 0.0, alerts[1].ChangePct)
+	}
+}
+
+func TestTrendZScore(t *testing.T) {
+	assert.Equal(t, 0.0, trendZScore(0, 0))
+	assert.InDelta(t, 3.0, trendZScore(9, 0), 0.001)
+	assert.InDelta(t, -2.0, trendZScore(12, 24), 0.001)
+}
+
+func TestGenerateTrendAlertId(t *testing.T) {
+	windowEnd := time.Date(2026, 4, 27, 0, 0, 0, 0, time.UTC)
+	id := generateTrendAlertId("repo1", "coderabbit", "security", windowEnd)
+	assert.Equal(t, id, generateTrendAlertId("repo1", "coderabbit", "security", windowEnd))
+	assert.Contains(t, id, "aitrend:")
+	assert.NotEqual(t, id, generateTrendAlertId("repo1", "coderabbit", "security", windowEnd.AddDate(0, 0, 7)))
+	assert.NotEqual(t, id, generateTrendAlertId("repo1", "coderabbit", "bug", windowEnd))
+}
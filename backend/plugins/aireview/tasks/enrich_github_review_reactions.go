@@ -69,6 +69,7 @@ func EnrichGithubReviewReactions(taskCtx plugin.SubTaskContext) errors.Error {
 			dal.Join("JOIN project_mapping pm ON pr.base_repo_id = pm.row_id"),
 			dal.Where("pm.project_name = ? AND pm.`table` = ? AND prc._raw_data_table != ''", data.Options.ProjectName, "repos"),
 		}
+		clauses = append(clauses, excludedRepoClauses("pr.base_repo_id", data.Options.ExcludeRepoIds)...)
 	} else {
 		clauses = []dal.Clause{
 			dal.Select("ar.id, prc._raw_data_table, prc._raw_data_id"),
@@ -84,6 +84,7 @@ func EnrichGitlabReviewReactions(taskCtx plugin.SubTaskContext) errors.Error {
 			dal.Join("JOIN project_mapping pm ON pr.base_repo_id = pm.row_id"),
 			dal.Where("ar.source_platform = ? AND pm.project_name = ? AND pm.`table` = ?", "gitlab", data.Options.ProjectName, "repos"),
 		}
+		reviewClauses = append(reviewClauses, excludedRepoClauses("pr.base_repo_id", data.Options.ExcludeRepoIds)...)
 	} else {
 		reviewClauses = []dal.Clause{
 			dal.Select("ar.id as review_id, ar.review_id as domain_comment_id"),
@@ -116,7 +117,7 @@ func EnrichGitlabReviewReactions(taskCtx plugin.SubTaskContext) errors.Error {
 		if fetchErr := db.Fetch(cursor, &row); fetchErr != nil {
 			return errors.Default.Wrap(fetchErr, "failed to fetch GitLab review")
 		}
-		connId, gitlabId, parseErr := parseGitlabDomainId(row.DomainCommentId)
+		connId, gitlabId, parseErr := parseDomainCommentId(row.DomainCommentId)
 		if parseErr != nil {
 			logger.Warn(nil, "skipping review with unparseable domain ID: %s", row.DomainCommentId)
 			continue
@@ -218,9 +219,10 @@ func EnrichGitlabReviewReactions(taskCtx plugin.SubTaskContext) errors.Error {
 	return nil
 }
 
-// parseGitlabDomainId extracts connection_id and gitlab_id from a domain layer ID.
-// Format: "gitlab:GitlabMrComment:CONNECTION_ID:GITLAB_ID"
-func parseGitlabDomainId(domainId string) (uint64, int, error) {
+// parseDomainCommentId extracts the connection_id and the platform comment ID from a
+// domain layer comment ID.
+// Format: "gitlab:GitlabMrComment:CONNECTION_ID:GITLAB_ID" (or "github:GithubPrComment:...")
+func parseDomainCommentId(domainId string) (uint64, int, error) {
 	parts := strings.Split(domainId, ":")
 	if len(parts) < 4 {
 		return 0, 0, fmt.Errorf("expected at least 4 parts, got %d", len(parts))
@@ -66,7 +66,7 @@ func TestParseGitlabDomainId(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			connId, gitlabId, err := parseGitlabDomainId(tt.domainId)
+			connId, gitlabId, err := parseDomainCommentId(tt.domainId)
 			if tt.wantErr {
 				assert.Error(t, err)
 				return
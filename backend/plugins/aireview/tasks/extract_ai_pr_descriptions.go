@@ -0,0 +1,229 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var ExtractAiPrDescriptionsMeta = plugin.SubTaskMeta{
+	Name:             "extractAiPrDescriptions",
+	EntryPoint:       ExtractAiPrDescriptions,
+	EnabledByDefault: true,
+	Description:      "Detect pull request descriptions generated by AI tools and extract their sections",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+}
+
+// prDescriptionMarkers are the built-in markers of the tools generating PR descriptions, checked in order
+var prDescriptionMarkers = []struct {
+	aiTool string
+	re     *regexp.Regexp
+}{
+	// CodeRabbit appends its release notes to the description
+	{models.AiToolCodeRabbit, regexp.MustCompile(`(?i)(summary by coderabbit|auto-generated comment: release notes by coderabbit)`)},
+	// Qodo describe (PR-Agent) writes "PR Type", "Description" and "Changes walkthrough" sections
+	{models.AiToolQodo, regexp.MustCompile(`(?i)(###\s*\*\*PR Type\*\*|<!--\s*(qodo|pr-agent)|generated by qodo)`)},
+	{models.AiToolCopilot, regexp.MustCompile(`(?i)(<!--\s*copilot:\w+\s*-->|copilot summary|generated by (github )?copilot)`)},
+}
+
+var (
+	// descriptionHeadingRe matches markdown headings ("## Summary", "### **PR Type**")
+	descriptionHeadingRe = regexp.MustCompile(`^\s{0,3}#{1,6}\s+(.+?)\s*#*\s*$`)
+	// horizontalRuleRe matches the separators between sections ("___", "---")
+	horizontalRuleRe = regexp.MustCompile(`^\s*([-_*])(\s*[-_*]){2,}\s*$`)
+)
+
+// maxDescriptionMarkerLength is the size of the marker column
+const maxDescriptionMarkerLength = 255
+
+// prDescriptionRow is the subset of a pull request needed to analyze its description
+type prDescriptionRow struct {
+	Id          string    `gorm:"column:id"`
+	BaseRepoId  string    `gorm:"column:base_repo_id"`
+	Description string    `gorm:"column:description"`
+	Status      string    `gorm:"column:status"`
+	IsDraft     bool      `gorm:"column:is_draft"`
+	CreatedDate time.Time `gorm:"column:created_date"`
+}
+
+// ExtractAiPrDescriptions records for each pull request of the repo (or project)
+// whether its description was generated by an AI tool, so the adoption of AI
+// descriptions can be reported next to the AI reviews. The sections of AI
+// descriptions are stored; code snippets are stripped from them when the scope
+// config anonymizes the data. Descriptions written by hand only get the flag.
+func ExtractAiPrDescriptions(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	clauses := []dal.Clause{
+		dal.Select("pr.id, pr.base_repo_id, pr.description, pr.status, pr.is_draft, pr.created_date"),
+		dal.From("pull_requests pr"),
+	}
+	if data.Options.ProjectName != "" {
+		clauses = append(clauses,
+			dal.Join("JOIN project_mapping pm ON pr.base_repo_id = pm.row_id"),
+			dal.Where("pm.project_name = ? AND pm.`table` = ?", data.Options.ProjectName, "repos"),
+		)
+		clauses = append(clauses, excludedRepoClauses("pr.base_repo_id", data.Options.ExcludeRepoIds)...)
+	} else {
+		clauses = append(clauses, dal.Where("pr.base_repo_id = ?", data.Options.RepoId))
+	}
+	clauses = append(clauses, prStateClauses(data.Options.ScopeConfig)...)
+
+	// Descriptions analyzed before the PR state filters were turned on would still count in the adoption
+	if err := deleteExcludedPrRows(db, data, &models.AiPrDescription{}); err != nil {
+		return err
+	}
+
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to query pull requests")
+	}
+	defer cursor.Close()
+
+	total, aiGenerated := 0, 0
+	for cursor.Next() {
+		var pr prDescriptionRow
+		if err := db.Fetch(cursor, &pr); err != nil {
+			return errors.Default.Wrap(err, "failed to fetch pull request")
+		}
+		record := analyzePrDescription(data, &pr)
+		if err := db.CreateOrUpdate(record); err != nil {
+			return errors.Default.Wrap(err, "failed to save PR description")
+		}
+		total++
+		if record.IsAiGenerated {
+			aiGenerated++
+		}
+	}
+
+	logger.Info("Analyzed %d PR descriptions, %d generated by AI tools", total, aiGenerated)
+	return nil
+}
+
+// analyzePrDescription builds the description record of a pull request
+func analyzePrDescription(data *AiReviewTaskData, pr *prDescriptionRow) *models.AiPrDescription {
+	repoId := pr.BaseRepoId
+	if repoId == "" {
+		repoId = data.Options.RepoId
+	}
+	record := &models.AiPrDescription{
+		PullRequestId: pr.Id,
+		RepoId:        repoId,
+		PrStatus:      pr.Status,
+		PrIsDraft:     pr.IsDraft,
+		PrCreatedDate: pr.CreatedDate,
+	}
+	aiTool, marker, ok := detectAiDescription(data, pr.Description)
+	if !ok {
+		return record
+	}
+	record.IsAiGenerated = true
+	record.AiTool = aiTool
+	record.Marker = marker
+	record.Sections = parseDescriptionSections(convertReviewBody(data, aiTool, pr.Description))
+	if data.Options.ScopeConfig != nil && data.Options.ScopeConfig.AnonymizeEnabled {
+		for i := range record.Sections {
+			record.Sections[i].Content = stripCodeSnippets(record.Sections[i].Content)
+		}
+	}
+	record.SectionCount = len(record.Sections)
+	return record
+}
+
+// detectAiDescription returns the tool that generated a PR description and the
+// marker it was recognized by. The built-in markers are checked before the scope
+// config aiPrDescriptionPattern, whose matches belong to the "other" tool.
+func detectAiDescription(data *AiReviewTaskData, description string) (string, string, bool) {
+	if strings.TrimSpace(description) == "" {
+		return "", "", false
+	}
+	for _, marker := range prDescriptionMarkers {
+		if match := marker.re.FindString(description); match != "" {
+			return marker.aiTool, truncateMarker(match), true
+		}
+	}
+	if data != nil && data.AiPrDescriptionRegex != nil {
+		if match := data.AiPrDescriptionRegex.FindString(description); match != "" {
+			return models.AiToolOther, truncateMarker(match), true
+		}
+	}
+	return "", "", false
+}
+
+// truncateMarker trims a marker to the size of its column
+func truncateMarker(marker string) string {
+	marker = strings.TrimSpace(marker)
+	if len(marker) <= maxDescriptionMarkerLength {
+		return marker
+	}
+	// Drop the rune cut in half, if any
+	return strings.ToValidUTF8(marker[:maxDescriptionMarkerLength], "")
+}
+
+// parseDescriptionSections splits a markdown description on its headings. The text
+// before the first heading is a section without heading; headings inside fenced
+// code blocks and horizontal rules between sections are ignored.
+func parseDescriptionSections(body string) []models.PrDescriptionSection {
+	var sections []models.PrDescriptionSection
+	current := models.PrDescriptionSection{}
+	var lines []string
+	flush := func() {
+		current.Content = strings.TrimSpace(strings.Join(lines, "\n"))
+		if current.Heading != "" || current.Content != "" {
+			sections = append(sections, current)
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := descriptionHeadingRe.FindStringSubmatch(line); m != nil {
+				flush()
+				current = models.PrDescriptionSection{Heading: cleanSectionHeading(m[1])}
+				lines = nil
+				continue
+			}
+			if horizontalRuleRe.MatchString(line) {
+				continue
+			}
+		}
+		lines = append(lines, line)
+	}
+	flush()
+	return sections
+}
+
+// cleanSectionHeading drops the emphasis, emojis and punctuation around a heading ("**Changes walkthrough** 📝")
+func cleanSectionHeading(heading string) string {
+	return strings.TrimFunc(heading, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,113 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectAiDescription(t *testing.T) {
+	data := &AiReviewTaskData{AiPrDescriptionRegex: regexp.MustCompile(`(?i)written by our-bot`)}
+	tests := []struct {
+		name        string
+		description string
+		wantTool    string
+		wantMarker  string
+	}{
+		{"qodo describe", "### **User description**\nFix it\n\n___\n\n### **PR Type**\nBug fix", models.AiToolQodo, "### **PR Type**"},
+		{"coderabbit release notes", "Fix it\n\n## Summary by CodeRabbit\n\n- **Bug Fixes**", models.AiToolCodeRabbit, "Summary by CodeRabbit"},
+		{"copilot summary", "<!-- copilot:summary -->\n### Copilot Summary", models.AiToolCopilot, "<!-- copilot:summary -->"},
+		{"custom pattern", "Refactor the parser\n\nWritten by our-bot", models.AiToolOther, "Written by our-bot"},
+		{"written by hand", "Fixes the parser when Copilot is mentioned", "", ""},
+		{"empty", "  ", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aiTool, marker, ok := detectAiDescription(data, tt.description)
+			assert.Equal(t, tt.wantTool != "", ok)
+			assert.Equal(t, tt.wantTool, aiTool)
+			assert.Equal(t, tt.wantMarker, marker)
+		})
+	}
+}
+
+func TestDetectAiDescriptionWithoutCustomPattern(t *testing.T) {
+	_, _, ok := detectAiDescription(&AiReviewTaskData{}, "Written by our-bot")
+	assert.False(t, ok)
+}
+
+func TestTruncateMarker(t *testing.T) {
+	assert.Equal(t, "marker", truncateMarker(" marker\n"))
+	marker := truncateMarker(strings.Repeat("a", 254) + "é")
+	assert.Equal(t, strings.Repeat("a", 254), marker)
+}
+
+func TestParseDescriptionSections(t *testing.T) {
+	body := "Fixes the retry loop.\r\n\r\n" +
+		"___\n\n" +
+		"### **PR Type**\nBug fix\n\n" +
+		"___\n\n" +
+		"### **Description**\n- Retry on 503\n```go\n# not a heading\n```\n\n" +
+		"### **Changes walkthrough** 📝\n\n" +
+		"## Empty ##\n"
+
+	sections := parseDescriptionSections(body)
+	assert.Equal(t, []models.PrDescriptionSection{
+		{Heading: "", Content: "Fixes the retry loop."},
+		{Heading: "PR Type", Content: "Bug fix"},
+		{Heading: "Description", Content: "- Retry on 503\n```go\n# not a heading\n```"},
+		{Heading: "Changes walkthrough"},
+		{Heading: "Empty"},
+	}, sections)
+}
+
+func TestParseDescriptionSectionsWithoutHeadings(t *testing.T) {
+	assert.Nil(t, parseDescriptionSections("   \n"))
+	assert.Equal(t, []models.PrDescriptionSection{{Content: "Just a sentence."}}, parseDescriptionSections("Just a sentence."))
+}
+
+func TestAnalyzePrDescription(t *testing.T) {
+	data := &AiReviewTaskData{Options: &AiReviewOptions{
+		RepoId:      "github:GithubRepo:1:42",
+		ScopeConfig: &models.AiReviewScopeConfig{AnonymizeEnabled: true},
+	}}
+
+	record := analyzePrDescription(data, &prDescriptionRow{
+		Id:          "github:GithubPullRequest:1:7",
+		Description: "## Summary by CodeRabbit\n\n- **New Features**\n```yaml\nkey: secret\n```",
+		Status:      "MERGED",
+	})
+	assert.True(t, record.IsAiGenerated)
+	assert.Equal(t, models.AiToolCodeRabbit, record.AiTool)
+	assert.Equal(t, "github:GithubRepo:1:42", record.RepoId)
+	assert.Equal(t, "MERGED", record.PrStatus)
+	assert.Equal(t, 1, record.SectionCount)
+	assert.Equal(t, "Summary by CodeRabbit", record.Sections[0].Heading)
+	assert.NotContains(t, record.Sections[0].Content, "secret")
+
+	record = analyzePrDescription(data, &prDescriptionRow{Id: "github:GithubPullRequest:1:8", BaseRepoId: "github:GithubRepo:1:43", Description: "Manual description"})
+	assert.False(t, record.IsAiGenerated)
+	assert.Equal(t, "github:GithubRepo:1:43", record.RepoId)
+	assert.Empty(t, record.Sections)
+	assert.Zero(t, record.SectionCount)
+}
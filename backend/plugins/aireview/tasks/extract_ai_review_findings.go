@@ -68,7 +68,7 @@ func ExtractAiReviewFindings(taskCtx plugin.SubTaskContext) errors.Error {
 		}
 
 		// Parse findings from review body
-		findings := parseFindings(&review)
+		findings := parseFindings(&review, data.RiskTermRules)
 		totalFindings += len(findings)
 
 		for _, finding := range findings {
@@ -93,8 +93,9 @@ func ExtractAiReviewFindings(taskCtx plugin.SubTaskContext) errors.Error {
 	return nil
 }
 
-// parseFindings extracts individual findings from an AI review
-func parseFindings(review *models.AiReview) []*models.AiReviewFinding {
+// parseFindings extracts individual findings from an AI review and normalizes the
+// category and severity of the tool's risk terms
+func parseFindings(review *models.AiReview, riskTermRules []riskTermRule) []*models.AiReviewFinding {
 	var findings []*models.AiReviewFinding
 	body := normalizeBody(review.Body)
 
@@ -109,6 +110,10 @@ func parseFindings(review *models.AiReview) []*models.AiReviewFinding {
 	// Parse generic inline comment findings
 	findings = append(findings, parseGenericFindings(review, body)...)
 
+	heading := reviewHeading(body)
+	for _, finding := range findings {
+		normalizeFindingRisk(finding, heading, riskTermRules)
+	}
 	return findings
 }
 
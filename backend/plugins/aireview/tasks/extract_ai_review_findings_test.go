@@ -286,7 +286,7 @@ func TestParseFindings(t *testing.T) {
 			AiTool: models.AiToolCodeRabbit,
 			Body:   "📁 main.go\n- Security issue with input validation in the handler function\n",
 		}
-		findings := parseFindings(review)
+		findings := parseFindings(review, nil)
 		assert.NotEmpty(t, findings)
 	})
 
@@ -296,7 +296,7 @@ func TestParseFindings(t *testing.T) {
 			AiTool: models.AiToolQodo,
 			Body:   "- Consider refactoring this function for better maintainability\n",
 		}
-		findings := parseFindings(review)
+		findings := parseFindings(review, nil)
 		assert.NotEmpty(t, findings)
 	})
 
@@ -306,7 +306,7 @@ func TestParseFindings(t *testing.T) {
 			AiTool: models.AiToolGemini,
 			Body:   "```suggestion\nreturn nil, fmt.Errorf(\"invalid: %w\", err)\n```",
 		}
-		findings := parseFindings(review)
+		findings := parseFindings(review, nil)
 		hasSuggestion := false
 		for _, f := range findings {
 			if f.Type == models.FindingTypeSuggestion {
@@ -318,7 +318,7 @@ func TestParseFindings(t *testing.T) {
 
 	t.Run("empty body returns no findings", func(t *testing.T) {
 		review := &models.AiReview{Id: "r4", AiTool: models.AiToolCodeRabbit, Body: ""}
-		findings := parseFindings(review)
+		findings := parseFindings(review, nil)
 		assert.Empty(t, findings)
 	})
 }
@@ -54,25 +54,37 @@ func ExtractAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
 		logger.Info("Starting AI review extraction for project: %s", data.Options.ProjectName)
 		// Project mode: join with project_mappings to get all repos in project
 		clauses = []dal.Clause{
-			dal.Select("prc.*, pr.base_repo_id, pr.status as pr_status, pr.merged_date, pr.url as pr_url, a.user_name as account_username"),
+			dal.Select("prc.*, pr.base_repo_id, pr.status as pr_status, pr.is_draft as pr_is_draft, pr.merged_date, pr.url as pr_url, a.user_name as account_username, " +
+				"pr._raw_data_table as pr_raw_table, r._raw_data_table as repo_raw_table, r.url as repo_url"),
 			dal.From("pull_request_comments prc"),
 			dal.Join("LEFT JOIN pull_requests pr ON prc.pull_request_id = pr.id"),
 			dal.Join("LEFT JOIN accounts a ON prc.account_id = a.id"),
+			dal.Join("LEFT JOIN repos r ON pr.base_repo_id = r.id"),
 			dal.Join("LEFT JOIN project_mapping pm ON pr.base_repo_id = pm.row_id"),
 			dal.Where("pm.project_name = ? AND pm.`table` = ?", data.Options.ProjectName, "repos"),
 		}
+		clauses = append(clauses, excludedRepoClauses("pr.base_repo_id", data.Options.ExcludeRepoIds)...)
 	} else {
 		logger.Info("Starting AI review extraction for repo: %s", data.Options.RepoId)
 		// Single repo mode
 		clauses = []dal.Clause{
-			dal.Select("prc.*, pr.base_repo_id, pr.status as pr_status, pr.merged_date, pr.url as pr_url, a.user_name as account_username"),
+			dal.Select("prc.*, pr.base_repo_id, pr.status as pr_status, pr.is_draft as pr_is_draft, pr.merged_date, pr.url as pr_url, a.user_name as account_username, " +
+				"pr._raw_data_table as pr_raw_table, r._raw_data_table as repo_raw_table, r.url as repo_url"),
 			dal.From("pull_request_comments prc"),
 			dal.Join("LEFT JOIN pull_requests pr ON prc.pull_request_id = pr.id"),
 			dal.Join("LEFT JOIN accounts a ON prc.account_id = a.id"),
+			dal.Join("LEFT JOIN repos r ON pr.base_repo_id = r.id"),
 			dal.Where("pr.base_repo_id = ?", data.Options.RepoId),
 		}
 	}
 
+	clauses = append(clauses, prStateClauses(data.Options.ScopeConfig)...)
+
+	// Reviews and findings extracted before the PR state filters were turned on would still count in the metrics
+	if err := deleteExcludedPrRows(db, data, &models.AiReview{}, &models.AiReviewFinding{}); err != nil {
+		return err
+	}
+
 	cursor, err := db.Cursor(clauses...)
 	if err != nil {
 		return errors.Default.Wrap(err, "failed to query pull request comments")
@@ -81,17 +93,51 @@ func ExtractAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
 
 	// Track processed reviews to avoid duplicates
 	processedReviews := make(map[string]bool)
+	saved, botReplies := 0, 0
 	batchSize := 100
 	batch := make([]*models.AiReview, 0, batchSize)
+	batchComments := make([]*code.PullRequestComment, 0, batchSize)
+
+	// flush saves the batch, without the AI comments replying to a bot when the scope config excludes them
+	flush := func() errors.Error {
+		if data.Options.ScopeConfig.ExcludeBotReplies {
+			replies, err := botReplyIds(db, data, batchComments)
+			if err != nil {
+				return err
+			}
+			kept := batch[:0]
+			for _, review := range batch {
+				if replies[review.ReviewId] {
+					botReplies++
+					continue
+				}
+				kept = append(kept, review)
+			}
+			batch = kept
+		}
+		if len(batch) > 0 {
+			if err := saveBatch(db, batch); err != nil {
+				return err
+			}
+		}
+		saved += len(batch)
+		batch = make([]*models.AiReview, 0, batchSize)
+		batchComments = make([]*code.PullRequestComment, 0, batchSize)
+		return nil
+	}
 
 	for cursor.Next() {
 		var comment struct {
 			code.PullRequestComment
 			BaseRepoId      string     `gorm:"column:base_repo_id"`
 			PrStatus        string     `gorm:"column:pr_status"`
+			PrIsDraft       bool       `gorm:"column:pr_is_draft"`
 			MergedDate      *time.Time `gorm:"column:merged_date"`
 			PrUrl           string     `gorm:"column:pr_url"`
 			AccountUsername string     `gorm:"column:account_username"`
+			PrRawTable      string     `gorm:"column:pr_raw_table"`
+			RepoRawTable    string     `gorm:"column:repo_raw_table"`
+			RepoUrl         string     `gorm:"column:repo_url"`
 		}
 
 		if err := db.Fetch(cursor, &comment); err != nil {
@@ -124,6 +170,8 @@ func ExtractAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
 		// Detect risk level
 		riskLevel, riskScore := detectRiskLevel(data, comment.Body)
 
+		source := prSource{PrRawTable: comment.PrRawTable, RepoRawTable: comment.RepoRawTable, RepoUrl: comment.RepoUrl}
+
 		// Determine repo ID (from query result in project mode, from options in repo mode)
 		repoId := comment.BaseRepoId
 		if repoId == "" {
@@ -137,9 +185,10 @@ func ExtractAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
 			RepoId:                     repoId,
 			AiTool:                     aiTool,
 			AiToolUser:                 username,
+			ToolVersion:                extractToolVersion(comment.Body),
 			ReviewId:                   comment.Id,
 			Body:                       comment.Body,
-			Summary:                    extractSummary(comment.Body),
+			Summary:                    extractSummary(data, aiTool, comment.Body),
 			CreatedDate:                comment.CreatedDate,
 			RiskLevel:                  riskLevel,
 			RiskScore:                  riskScore,
@@ -156,74 +205,136 @@ func ExtractAiReviews(taskCtx plugin.SubTaskContext) errors.Error {
 			PreMergeChecksFailed:       reviewMetrics.PreMergeChecksFailed,
 			PreMergeChecksInconclusive: reviewMetrics.PreMergeChecksInconclusive,
 			ReviewState:                detectReviewState(comment.Body, comment.Status),
-			SourcePlatform:             detectSourcePlatform(comment.PullRequestId),
-			SourceUrl:                  buildCommentUrl(comment.PrUrl, comment.Id),
+			PrStatus:                   comment.PrStatus,
+			PrIsDraft:                  comment.PrIsDraft,
+			SourcePlatform:             detectSourcePlatform(comment.PullRequestId, source, data.SourcePlatformRules),
+			SourceUrl:                  buildCommentUrl(comment.PrUrl, comment.Id, source, data.SourcePlatformRules),
 		}
 
 		batch = append(batch, aiReview)
+		// AI tools replying to each other or to CI bots only add noise, flush looks up the replied-to authors per batch
+		batchComments = append(batchComments, &comment.PullRequestComment)
 
 		if len(batch) >= batchSize {
-			if err := saveBatch(db, batch); err != nil {
+			if err := flush(); err != nil {
 				return err
 			}
-			batch = make([]*models.AiReview, 0, batchSize)
 		}
 	}
 
 	// Save remaining batch
-	if len(batch) > 0 {
-		if err := saveBatch(db, batch); err != nil {
-			return err
-		}
+	if err := flush(); err != nil {
+		return err
 	}
 
-	logger.Info("Completed AI review extraction: %d reviews found", len(processedReviews))
+	logger.Info("Completed AI review extraction: %d reviews found, %d bot replies skipped", saved, botReplies)
 	return nil
 }
 
-// detectAiTool checks if the comment is from an AI review tool
-func detectAiTool(data *AiReviewTaskData, accountId, body string) (string, bool) {
-	// Check CodeRabbit
-	if data.Options.ScopeConfig.CodeRabbitEnabled {
-		if data.CodeRabbitUsernameRegex != nil && data.CodeRabbitUsernameRegex.MatchString(accountId) {
-			return models.AiToolCodeRabbit, true
-		}
-		if data.CodeRabbitPatternRegex != nil && data.CodeRabbitPatternRegex.MatchString(body) {
-			return models.AiToolCodeRabbit, true
-		}
+// prStateClauses returns the extra filters on the joined pull_requests (pr)
+// table for the draft / closed-unmerged toggles of the scope config. pr is
+// LEFT JOINed, so comments without a matching PR row (NULL columns) are kept.
+func prStateClauses(config *models.AiReviewScopeConfig) []dal.Clause {
+	var clauses []dal.Clause
+	if config == nil {
+		return clauses
+	}
+	if config.ExcludeDraftPrs {
+		clauses = append(clauses, dal.Where("(pr.is_draft IS NULL OR pr.is_draft = ?)", false))
+	}
+	if config.ExcludeClosedUnmergedPrs {
+		clauses = append(clauses, dal.Where("(pr.status IS NULL OR pr.status != ?)", code.CLOSED))
 	}
+	return clauses
+}
 
-	// Check Cursor Bugbot
-	if data.Options.ScopeConfig.CursorBugbotEnabled {
-		if data.CursorBugbotUsernameRegex != nil && data.CursorBugbotUsernameRegex.MatchString(accountId) {
-			return models.AiToolCursorBugbot, true
-		}
-		if data.CursorBugbotPatternRegex != nil && data.CursorBugbotPatternRegex.MatchString(body) {
-			return models.AiToolCursorBugbot, true
-		}
+// excludedPrCondition returns the condition on pull_requests matching the PRs that the PR state
+// filters of the scope config leave out, empty when no filter is on
+func excludedPrCondition(config *models.AiReviewScopeConfig) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	if config == nil {
+		return "", nil
+	}
+	if config.ExcludeDraftPrs {
+		conditions = append(conditions, "is_draft = ?")
+		args = append(args, true)
 	}
+	if config.ExcludeClosedUnmergedPrs {
+		conditions = append(conditions, "status = ?")
+		args = append(args, code.CLOSED)
+	}
+	return strings.Join(conditions, " OR "), args
+}
 
-	// Check Qodo (formerly Codium)
-	if data.Options.ScopeConfig.QodoEnabled {
-		if data.QodoUsernameRegex != nil && data.QodoUsernameRegex.MatchString(accountId) {
-			return models.AiToolQodo, true
-		}
-		if data.QodoPatternRegex != nil && data.QodoPatternRegex.MatchString(body) {
-			return models.AiToolQodo, true
+// deleteExcludedPrRows deletes the rows of the repo (or project) tables, which have pull_request_id
+// and repo_id columns, that belong to PRs the PR state filters of the scope config leave out, so
+// turning a filter on also drops what was extracted before
+func deleteExcludedPrRows(db dal.Dal, data *AiReviewTaskData, tables ...dal.Tabler) errors.Error {
+	condition, args := excludedPrCondition(data.Options.ScopeConfig)
+	if condition == "" {
+		return nil
+	}
+	clauses := []dal.Clause{dal.Where("pull_request_id IN (SELECT id FROM pull_requests WHERE "+condition+")", args...)}
+	if data.Options.ProjectName != "" {
+		clauses = append(clauses, dal.Where(
+			"repo_id IN (SELECT row_id FROM project_mapping WHERE project_name = ? AND `table` = ?)", data.Options.ProjectName, "repos",
+		))
+		clauses = append(clauses, excludedRepoClauses("repo_id", data.Options.ExcludeRepoIds)...)
+	} else {
+		clauses = append(clauses, dal.Where("repo_id = ?", data.Options.RepoId))
+	}
+	for _, table := range tables {
+		if err := db.Delete(table, clauses...); err != nil {
+			return errors.Default.Wrap(err, "failed to delete the rows of excluded PRs from "+table.TableName())
 		}
 	}
+	return nil
+}
+
+// detectAiTool checks if the comment is from an AI review tool
+func detectAiTool(data *AiReviewTaskData, accountId, body string) (string, bool) {
+	match := matchAiTool(data, accountId, body)
+	return match.Tool, match.Tool != ""
+}
+
+// AiToolMatch tells which detection regex of the scope config identified the tool of a review
+type AiToolMatch struct {
+	Tool      string `json:"tool"`
+	MatchedBy string `json:"matchedBy"` // username or pattern
+	Pattern   string `json:"pattern"`
+}
 
-	// Check Gemini Code Assist
-	if data.Options.ScopeConfig.GeminiEnabled {
-		if data.GeminiUsernameRegex != nil && data.GeminiUsernameRegex.MatchString(accountId) {
-			return models.AiToolGemini, true
+// matchAiTool returns the first enabled tool whose username regex matches the
+// account or whose pattern regex matches the body, in the order CodeRabbit,
+// Cursor Bugbot, Qodo, Gemini, Copilot. The match is empty when no tool matches.
+func matchAiTool(data *AiReviewTaskData, accountId, body string) AiToolMatch {
+	config := data.Options.ScopeConfig
+	detectors := []struct {
+		tool     string
+		enabled  bool
+		username *regexp.Regexp
+		pattern  *regexp.Regexp
+	}{
+		{models.AiToolCodeRabbit, config.CodeRabbitEnabled, data.CodeRabbitUsernameRegex, data.CodeRabbitPatternRegex},
+		{models.AiToolCursorBugbot, config.CursorBugbotEnabled, data.CursorBugbotUsernameRegex, data.CursorBugbotPatternRegex},
+		// Qodo (formerly Codium)
+		{models.AiToolQodo, config.QodoEnabled, data.QodoUsernameRegex, data.QodoPatternRegex},
+		{models.AiToolGemini, config.GeminiEnabled, data.GeminiUsernameRegex, data.GeminiPatternRegex},
+		{models.AiToolCopilot, config.CopilotEnabled, data.CopilotUsernameRegex, data.CopilotPatternRegex},
+	}
+	for _, d := range detectors {
+		if !d.enabled {
+			continue
 		}
-		if data.GeminiPatternRegex != nil && data.GeminiPatternRegex.MatchString(body) {
-			return models.AiToolGemini, true
+		if d.username != nil && d.username.MatchString(accountId) {
+			return AiToolMatch{Tool: d.tool, MatchedBy: "username", Pattern: d.username.String()}
+		}
+		if d.pattern != nil && d.pattern.MatchString(body) {
+			return AiToolMatch{Tool: d.tool, MatchedBy: "pattern", Pattern: d.pattern.String()}
 		}
 	}
-
-	return "", false
+	return AiToolMatch{}
 }
 
 // generateReviewId creates a deterministic ID for an AI review
@@ -248,6 +359,34 @@ type ReviewMetrics struct {
 	PreMergeChecksInconclusive int
 }
 
+// Patterns parseReviewMetrics reads the metrics from, also replayed by DebugReviewExtraction
+var (
+	effortRatingRe        = regexp.MustCompile(`🎯\s*(\d)(?:\s*\([^)]+\))?`)
+	qodoEffortRe          = regexp.MustCompile(`(?i)estimated effort[^:]*:\s*(\d)`)
+	complexityRe          = regexp.MustCompile(`(?i)(simple|moderate|complex|trivial)`)
+	effortTimeRe          = regexp.MustCompile(`(?:⏱️\s*)?~?(\d+)\s*minutes?`)
+	checksPassedRe        = regexp.MustCompile(`(?i)(?:✅\s*)?(\d+)\s*(?:checks?\s+)?passed`)
+	checksFailedRe        = regexp.MustCompile(`(?i)(?:❌\s*)?(\d+)\s*(?:checks?\s+)?failed`)
+	checksInconclusiveRe  = regexp.MustCompile(`(?i)(\d+)\s*(?:checks?\s+)?inconclusive`)
+	suggestionsSummaryRe  = regexp.MustCompile(`(?i)(\d+)(?:/\d+)?\s+suggestions?\s+(?:applied|accepted|implemented)`)
+	appliedSuggestionRe   = regexp.MustCompile(`(?i)(?:applied suggestion|suggestion applied|✅\s*Resolved)`)
+	checkedBoxRe          = regexp.MustCompile(`(?m)(?:^|\\n)[\s-]*\[x\]\s+`)
+	uncheckedBoxRe        = regexp.MustCompile(`(?m)(?:^|\\n)[\s-]*\[ \]\s+`)
+	committedSuggestionRe = regexp.MustCompile(`(?i)suggestion\s+(?:was\s+)?(?:applied|committed)\s+in\s+commit`)
+	issuePatternRes       = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)\b(bug|error|issue|problem|warning)\b`),
+		regexp.MustCompile(`(?i)❌`),
+		regexp.MustCompile(`(?i)⚠️`),
+	}
+	suggestionKeywordRe = regexp.MustCompile(`(?i)(suggest|recommend|consider|should|could)`)
+	fileReferenceRe     = regexp.MustCompile(`\b[\w/]+\.(go|ts|js|py|java|rs|cpp|c|h)\b`)
+	linesChangedRe      = regexp.MustCompile(`\+(\d+)\s*[−-](\d+)`)
+	// GitHub Copilot review overview, e.g. "Copilot reviewed 3 out of 4 changed files in this
+	// pull request and generated 2 comments." and "Comments suppressed due to low confidence (1)"
+	copilotReviewedRe      = regexp.MustCompile(`(?i)copilot reviewed (\d+) out of \d+ changed files[^.]*?generated (no|\d+) (?:new )?comments?`)
+	copilotLowConfidenceRe = regexp.MustCompile(`(?i)comments suppressed due to low confidence \((\d+)\)`)
+)
+
 // parseReviewMetrics extracts metrics from review body
 func parseReviewMetrics(body string) ReviewMetrics {
 	metrics := ReviewMetrics{
@@ -256,7 +395,6 @@ func parseReviewMetrics(body string) ReviewMetrics {
 
 	// Parse CodeRabbit numeric effort rating (e.g., "🎯 3 (Moderate)" or "🎯 3")
 	// This format appears in CodeRabbit reviews
-	effortRatingRe := regexp.MustCompile(`🎯\s*(\d)(?:\s*\([^)]+\))?`)
 	if match := effortRatingRe.FindStringSubmatch(body); len(match) > 1 {
 		if val, err := strconv.Atoi(match[1]); err == nil && val >= 1 && val <= 5 {
 			metrics.EffortRating = val
@@ -265,7 +403,6 @@ func parseReviewMetrics(body string) ReviewMetrics {
 
 	// Parse Qodo effort rating (e.g., "Estimated effort to review: 3" or with emoji dots)
 	if metrics.EffortRating == 0 {
-		qodoEffortRe := regexp.MustCompile(`(?i)estimated effort[^:]*:\s*(\d)`)
 		if match := qodoEffortRe.FindStringSubmatch(body); len(match) > 1 {
 			if val, err := strconv.Atoi(match[1]); err == nil && val >= 1 && val <= 5 {
 				metrics.EffortRating = val
@@ -274,7 +411,6 @@ func parseReviewMetrics(body string) ReviewMetrics {
 	}
 
 	// Parse effort/complexity (CodeRabbit format)
-	complexityRe := regexp.MustCompile(`(?i)(simple|moderate|complex|trivial)`)
 	if match := complexityRe.FindString(body); match != "" {
 		metrics.Complexity = strings.ToLower(match)
 		switch metrics.Complexity {
@@ -300,8 +436,7 @@ func parseReviewMetrics(body string) ReviewMetrics {
 	}
 
 	// Parse time estimate (e.g., "~12 minutes" or "⏱️ ~20 minutes")
-	timeRe := regexp.MustCompile(`(?:⏱️\s*)?~?(\d+)\s*minutes?`)
-	if match := timeRe.FindStringSubmatch(body); len(match) > 1 {
+	if match := effortTimeRe.FindStringSubmatch(body); len(match) > 1 {
 		if val, err := strconv.Atoi(match[1]); err == nil {
 			metrics.EffortMinutes = val
 		}
@@ -318,31 +453,22 @@ func parseReviewMetrics(body string) ReviewMetrics {
 	parseSuggestionAcceptance(body, &metrics)
 
 	// Count issue patterns
-	issuePatterns := []string{
-		`(?i)\b(bug|error|issue|problem|warning)\b`,
-		`(?i)❌`,
-		`(?i)⚠️`,
-	}
-	for _, pattern := range issuePatterns {
-		re := regexp.MustCompile(pattern)
+	for _, re := range issuePatternRes {
 		metrics.IssuesFound += len(re.FindAllString(body, -1))
 	}
 
 	// Count suggestions
-	suggestionRe := regexp.MustCompile(`(?i)(suggest|recommend|consider|should|could)`)
-	metrics.SuggestionsCount = len(suggestionRe.FindAllString(body, -1))
+	metrics.SuggestionsCount = len(suggestionKeywordRe.FindAllString(body, -1))
 
 	// Count file references
-	fileRe := regexp.MustCompile(`\b[\w/]+\.(go|ts|js|py|java|rs|cpp|c|h)\b`)
 	files := make(map[string]bool)
-	for _, match := range fileRe.FindAllString(body, -1) {
+	for _, match := range fileReferenceRe.FindAllString(body, -1) {
 		files[match] = true
 	}
 	metrics.FilesReviewed = len(files)
 
 	// Parse lines changed (e.g., "+50 −36")
-	linesRe := regexp.MustCompile(`\+(\d+)\s*[−-](\d+)`)
-	if match := linesRe.FindStringSubmatch(body); len(match) > 2 {
+	if match := linesChangedRe.FindStringSubmatch(body); len(match) > 2 {
 		added, err1 := strconv.Atoi(match[1])
 		removed, err2 := strconv.Atoi(match[2])
 		if err1 == nil && err2 == nil {
@@ -350,31 +476,55 @@ func parseReviewMetrics(body string) ReviewMetrics {
 		}
 	}
 
+	// GitHub Copilot states its counts, they replace the keyword counts above
+	parseCopilotOverview(body, &metrics)
+
 	return metrics
 }
 
+// parseCopilotOverview reads the files reviewed and the comments posted from the overview of a
+// GitHub Copilot review. Copilot holds back the comments it is not confident about, so the
+// confidence is the share of its comments it posted; without suppressed comments it is 100.
+func parseCopilotOverview(body string, metrics *ReviewMetrics) {
+	match := copilotReviewedRe.FindStringSubmatch(body)
+	if len(match) < 3 {
+		return
+	}
+	if files, err := strconv.Atoi(match[1]); err == nil {
+		metrics.FilesReviewed = files
+	}
+	posted, _ := strconv.Atoi(match[2]) // "generated no new comments"
+	metrics.IssuesFound = posted
+
+	suppressed := 0
+	if lowConfidence := copilotLowConfidenceRe.FindStringSubmatch(body); len(lowConfidence) > 1 {
+		suppressed, _ = strconv.Atoi(lowConfidence[1])
+	}
+	metrics.Confidence = 100
+	if posted+suppressed > 0 {
+		metrics.Confidence = posted * 100 / (posted + suppressed)
+	}
+}
+
 // parsePreMergeChecks extracts pre-merge check results from CodeRabbit format
 // Handles formats like: "2 passed, 1 inconclusive" or "✅ 2 checks passed"
 func parsePreMergeChecks(body string, metrics *ReviewMetrics) {
 	// CodeRabbit format: "N passed" or "✅ N checks passed" or "N checks passed"
-	passedRe := regexp.MustCompile(`(?i)(?:✅\s*)?(\d+)\s*(?:checks?\s+)?passed`)
-	if match := passedRe.FindStringSubmatch(body); len(match) > 1 {
+	if match := checksPassedRe.FindStringSubmatch(body); len(match) > 1 {
 		if val, err := strconv.Atoi(match[1]); err == nil {
 			metrics.PreMergeChecksPassed = val
 		}
 	}
 
 	// CodeRabbit format: "N failed" or "❌ N checks failed" or "N checks failed"
-	failedRe := regexp.MustCompile(`(?i)(?:❌\s*)?(\d+)\s*(?:checks?\s+)?failed`)
-	if match := failedRe.FindStringSubmatch(body); len(match) > 1 {
+	if match := checksFailedRe.FindStringSubmatch(body); len(match) > 1 {
 		if val, err := strconv.Atoi(match[1]); err == nil {
 			metrics.PreMergeChecksFailed = val
 		}
 	}
 
 	// CodeRabbit format: "N inconclusive"
-	inconclusiveRe := regexp.MustCompile(`(?i)(\d+)\s*(?:checks?\s+)?inconclusive`)
-	if match := inconclusiveRe.FindStringSubmatch(body); len(match) > 1 {
+	if match := checksInconclusiveRe.FindStringSubmatch(body); len(match) > 1 {
 		if val, err := strconv.Atoi(match[1]); err == nil {
 			metrics.PreMergeChecksInconclusive = val
 		}
@@ -399,8 +549,7 @@ func parseSuggestionAcceptance(body string, metrics *ReviewMetrics) {
 
 	// Pattern 1: Explicit "N suggestions applied/accepted/implemented" summary lines
 	// Matches: "3 suggestions applied", "2/5 suggestions accepted", "1 suggestion implemented"
-	summaryRe := regexp.MustCompile(`(?i)(\d+)(?:/\d+)?\s+suggestions?\s+(?:applied|accepted|implemented)`)
-	if match := summaryRe.FindStringSubmatch(body); len(match) > 1 {
+	if match := suggestionsSummaryRe.FindStringSubmatch(body); len(match) > 1 {
 		if val, err := strconv.Atoi(match[1]); err == nil {
 			accepted += val
 		}
@@ -408,16 +557,13 @@ func parseSuggestionAcceptance(body string, metrics *ReviewMetrics) {
 
 	// Pattern 2: "Applied suggestion" markers (CodeRabbit tracking tables)
 	// Matches individual "Applied suggestion" or "Suggestion applied" entries
-	appliedRe := regexp.MustCompile(`(?i)(?:applied suggestion|suggestion applied|✅\s*Resolved)`)
-	accepted += len(appliedRe.FindAllString(body, -1))
+	accepted += len(appliedSuggestionRe.FindAllString(body, -1))
 
 	// Pattern 3: Checked checkboxes in suggestion lists (Qodo "Apply" checkboxes)
 	// Matches: "- [x] **suggestion title**" but NOT "- [ ] **suggestion title**"
 	// These appear in Qodo's persistent suggestion table when a developer clicks Apply.
 	// Note: comment bodies from CSV/DB may store newlines as literal "\n" (escaped),
 	// so we match both real newlines and the "- " prefix mid-line.
-	checkedBoxRe := regexp.MustCompile(`(?m)(?:^|\\n)[\s-]*\[x\]\s+`)
-	uncheckedBoxRe := regexp.MustCompile(`(?m)(?:^|\\n)[\s-]*\[ \]\s+`)
 	checkedCount := len(checkedBoxRe.FindAllString(body, -1))
 	uncheckedCount := len(uncheckedBoxRe.FindAllString(body, -1))
 	// Only count checkboxes as accepted suggestions if there's a mix of checked/unchecked
@@ -429,16 +575,15 @@ func parseSuggestionAcceptance(body string, metrics *ReviewMetrics) {
 	// Pattern 4: CodeRabbit "committed suggestion" references
 	// When a user clicks "Commit suggestion" on GitHub, the thread may contain
 	// "Suggestion was applied in commit <sha>" or similar
-	committedRe := regexp.MustCompile(`(?i)suggestion\s+(?:was\s+)?(?:applied|committed)\s+in\s+commit`)
-	accepted += len(committedRe.FindAllString(body, -1))
+	accepted += len(committedSuggestionRe.FindAllString(body, -1))
 
 	metrics.SuggestionsAccepted = accepted
 }
 
 // extractSummary extracts a clean markdown summary from the review body
-func extractSummary(body string) string {
+func extractSummary(data *AiReviewTaskData, aiTool, body string) string {
 	// First, convert HTML to markdown
-	cleaned := htmlToMarkdown(body)
+	cleaned := convertReviewBody(data, aiTool, body)
 
 	// Try to extract specific sections based on AI tool format
 	var summaryParts []string
@@ -510,6 +655,14 @@ func extractSummary(body string) string {
 		}
 	}
 
+	// GitHub Copilot format: "## Pull request overview\n\n[summary]\n\n### Reviewed Changes\n..."
+	if len(summaryParts) == 0 {
+		overviewRe := regexp.MustCompile(`(?is)#+\s*Pull request overview\s*\n+(.+?)(?:\n\n|$)`)
+		if match := overviewRe.FindStringSubmatch(cleaned); len(match) > 1 {
+			summaryParts = append(summaryParts, strings.TrimSpace(match[1]))
+		}
+	}
+
 	// CodeRabbit format: Look for "Walkthrough" section
 	if len(summaryParts) == 0 && strings.Contains(cleaned, "Walkthrough") {
 		walkRe := regexp.MustCompile(`(?is)Walkthrough\s*\n+(.+?)(\n\n|$)`)
@@ -587,8 +740,39 @@ func extractSummary(body string) string {
 	return strings.TrimSpace(summary)
 }
 
-// htmlToMarkdown converts HTML elements to markdown equivalents while preserving structure
-func htmlToMarkdown(body string) string {
+// htmlConversionHooks convert tool-specific markup that the generic rules of
+// htmlToMarkdown mangle. A hook runs on the unescaped body, after fenced code
+// blocks were set aside and before the generic rules.
+var htmlConversionHooks = map[string]func(string) string{
+	// Qodo nests tables in the cells of its PR Reviewer Guide
+	models.AiToolQodo: flattenNestedTables,
+	// CodeRabbit nests collapsible sections (review details, additional comments)
+	models.AiToolCodeRabbit: unwrapNestedDetails,
+}
+
+var (
+	fencedCodeBlockRe  = regexp.MustCompile("(?s)```.*?```")
+	htmlTableOpenRe    = regexp.MustCompile(`(?i)<table[^>]*>`)
+	htmlTableCloseRe   = regexp.MustCompile(`(?i)</table\s*>`)
+	htmlTableCellRe    = regexp.MustCompile(`(?is)<t[dh][^>]*>(.*?)</t[dh]>`)
+	htmlTableTagsRe    = regexp.MustCompile(`(?i)</?(?:table|tr|thead|tbody|tfoot)[^>]*>`)
+	htmlDetailsOpenRe  = regexp.MustCompile(`(?i)<details[^>]*>`)
+	htmlDetailsCloseRe = regexp.MustCompile(`(?i)</details\s*>`)
+	htmlDetailsRe      = regexp.MustCompile(`(?is)<details>\s*<summary>(.*?)</summary>\s*(.*?)\s*</details>`)
+	htmlTagRe          = regexp.MustCompile(`<[^>]+>`)
+)
+
+// convertReviewBody returns the body summaries are extracted from: the raw
+// HTML when the scope config preserves it for the tool, markdown otherwise
+func convertReviewBody(data *AiReviewTaskData, aiTool, body string) string {
+	if data != nil && data.PreserveHtmlTools[aiTool] {
+		return strings.TrimSpace(unescapeReviewBody(body))
+	}
+	return htmlToMarkdown(body, aiTool)
+}
+
+// unescapeReviewBody undoes the JSON quoting bodies may be stored with
+func unescapeReviewBody(body string) string {
 	// Strip surrounding JSON quotes if present (bodies may be stored with JSON quoting)
 	if len(body) >= 2 && body[0] == '"' && body[len(body)-1] == '"' {
 		body = body[1 : len(body)-1]
@@ -598,6 +782,25 @@ func htmlToMarkdown(body string) string {
 	body = strings.ReplaceAll(body, "\\n", "\n")
 	body = strings.ReplaceAll(body, "\\r", "")
 	body = strings.ReplaceAll(body, "\\\"", "\"")
+	return body
+}
+
+// htmlToMarkdown converts HTML elements to markdown equivalents while preserving structure.
+// Fenced code blocks (suggestions, mermaid diagrams) are kept verbatim and the
+// htmlConversionHooks of aiTool run before the generic rules.
+func htmlToMarkdown(body, aiTool string) string {
+	body = unescapeReviewBody(body)
+
+	// Set fenced code blocks aside so tags and entities inside them survive
+	var codeBlocks []string
+	body = fencedCodeBlockRe.ReplaceAllStringFunc(body, func(block string) string {
+		codeBlocks = append(codeBlocks, block)
+		return fmt.Sprintf("\x00codeblock%d\x00", len(codeBlocks)-1)
+	})
+
+	if hook := htmlConversionHooks[aiTool]; hook != nil {
+		body = hook(body)
+	}
 
 	// Remove HTML comments first
 	commentRe := regexp.MustCompile(`<!--.*?-->`)
@@ -640,41 +843,14 @@ func htmlToMarkdown(body string) string {
 
 	// Extract content from <details><summary>...</summary>content</details>
 	// Handle summary with nested HTML
-	detailsRe := regexp.MustCompile(`(?is)<details>\s*<summary>(.*?)</summary>\s*(.*?)\s*</details>`)
-	body = detailsRe.ReplaceAllStringFunc(body, func(match string) string {
-		parts := detailsRe.FindStringSubmatch(match)
-		if len(parts) < 3 {
-			return match
-		}
-		title := parts[1]
-		content := parts[2]
-		// Clean up the title - remove HTML tags but keep markdown
-		title = regexp.MustCompile(`<[^>]+>`).ReplaceAllString(title, "")
-		title = strings.TrimSpace(title)
-		content = strings.TrimSpace(content)
-		if title == "" {
-			return content
-		}
-		return "\n**" + title + "**\n" + content + "\n"
-	})
+	body = htmlDetailsRe.ReplaceAllStringFunc(body, detailsToMarkdown)
 
 	// Remove remaining <summary> tags
 	summaryTagRe := regexp.MustCompile(`(?i)</?summary>`)
 	body = summaryTagRe.ReplaceAllString(body, "")
 
 	// Convert simple tables: extract cell content
-	tdRe := regexp.MustCompile(`(?is)<t[dh][^>]*>(.*?)</t[dh]>`)
-	body = tdRe.ReplaceAllStringFunc(body, func(match string) string {
-		content := tdRe.FindStringSubmatch(match)
-		if len(content) > 1 {
-			return strings.TrimSpace(content[1]) + "\n"
-		}
-		return ""
-	})
-
-	// Remove table structure tags
-	tableTagsRe := regexp.MustCompile(`(?i)</?(?:table|tr|thead|tbody|tfoot)[^>]*>`)
-	body = tableTagsRe.ReplaceAllString(body, "\n")
+	body = tableToMarkdown(body)
 
 	// Remove any remaining HTML tags
 	htmlRe := regexp.MustCompile(`<[^>]+>`)
@@ -708,7 +884,79 @@ func htmlToMarkdown(body string) string {
 		}
 	}
 
-	return strings.TrimSpace(strings.Join(cleanedLines, "\n"))
+	body = strings.TrimSpace(strings.Join(cleanedLines, "\n"))
+
+	for i, block := range codeBlocks {
+		body = strings.Replace(body, fmt.Sprintf("\x00codeblock%d\x00", i), block, 1)
+	}
+	return body
+}
+
+// detailsToMarkdown turns a <details> element into its bold summary followed by its content
+func detailsToMarkdown(match string) string {
+	parts := htmlDetailsRe.FindStringSubmatch(match)
+	if len(parts) < 3 {
+		return match
+	}
+	// Clean up the title - remove HTML tags but keep markdown
+	title := strings.TrimSpace(htmlTagRe.ReplaceAllString(parts[1], ""))
+	content := strings.TrimSpace(parts[2])
+	if title == "" {
+		return content
+	}
+	return "\n**" + title + "**\n" + content + "\n"
+}
+
+// tableToMarkdown puts the content of each table cell on its own line and drops the table structure tags
+func tableToMarkdown(body string) string {
+	body = htmlTableCellRe.ReplaceAllStringFunc(body, func(match string) string {
+		content := htmlTableCellRe.FindStringSubmatch(match)
+		if len(content) > 1 {
+			return strings.TrimSpace(content[1]) + "\n"
+		}
+		return ""
+	})
+	return htmlTableTagsRe.ReplaceAllString(body, "\n")
+}
+
+// replaceInnermost converts the elements delimited by openRe and closeRe from
+// the innermost outwards, so that an element nested in another one is converted
+// before a non-greedy match of its parent could end at its closing tag
+func replaceInnermost(body string, openRe, closeRe *regexp.Regexp, convert func(string) string) string {
+	for {
+		closeLoc := closeRe.FindStringIndex(body)
+		if closeLoc == nil {
+			return body
+		}
+		opens := openRe.FindAllStringIndex(body[:closeLoc[0]], -1)
+		if len(opens) == 0 {
+			// Unbalanced closing tag, leave the rest to the generic rules
+			return body
+		}
+		start := opens[len(opens)-1][0]
+		body = body[:start] + convert(body[start:closeLoc[1]]) + body[closeLoc[1]:]
+	}
+}
+
+// flattenNestedTables converts tables innermost first
+func flattenNestedTables(body string) string {
+	return replaceInnermost(body, htmlTableOpenRe, htmlTableCloseRe, func(table string) string {
+		return "\n" + tableToMarkdown(table) + "\n"
+	})
+}
+
+// unwrapNestedDetails converts <details> elements innermost first
+func unwrapNestedDetails(body string) string {
+	return replaceInnermost(body, htmlDetailsOpenRe, htmlDetailsCloseRe, func(details string) string {
+		// Drop attributes such as "open" so the generic rule matches
+		details = htmlDetailsOpenRe.ReplaceAllString(details, "<details>")
+		converted := htmlDetailsRe.ReplaceAllStringFunc(details, detailsToMarkdown)
+		if converted == details {
+			// No <summary>: keep the content only
+			return htmlDetailsCloseRe.ReplaceAllString(strings.TrimPrefix(details, "<details>"), "")
+		}
+		return converted
+	})
 }
 
 // detectRiskLevel analyzes the review body for risk indicators
@@ -750,42 +998,33 @@ func detectReviewState(body, status string) string {
 	return models.ReviewStateCommented
 }
 
-// detectSourcePlatform determines if the PR is from GitHub or GitLab
-func detectSourcePlatform(prId string) string {
-	if strings.HasPrefix(prId, "github:") {
-		return "github"
-	}
-	if strings.HasPrefix(prId, "gitlab:") {
-		return "gitlab"
-	}
-	return "unknown"
-}
+// toolVersionRe matches a version reported next to the tool name or behind a
+// version label, e.g. "CodeRabbit v2.3.1", "PR-Agent v0.29" or "Version: 1.4"
+var toolVersionRe = regexp.MustCompile(`(?i)(?:(?:coderabbit|qodo|pr-agent|bugbot|code assist|copilot)\s+v|\bversion\s*[:=]\s*v?)(\d+(?:\.(?:\d+|x)){0,3})\b`)
 
-// buildCommentUrl constructs a direct URL to the comment
-// commentId format: "github:GithubPrComment:1:123456789" or "gitlab:GitlabMrComment:1:123456"
-func buildCommentUrl(prUrl, commentId string) string {
-	if prUrl == "" {
-		return ""
-	}
-
-	// Extract the numeric comment ID from the DevLake ID
-	parts := strings.Split(commentId, ":")
-	if len(parts) < 4 {
-		return prUrl
-	}
-	numericId := parts[len(parts)-1]
+// toolModelRe matches the LLM a review was generated with, either from a
+// "Model: <name>" label or a well-known model family name in the text
+var toolModelRe = regexp.MustCompile("(?i)(?:\\bmodel\\s*[:=]\\s*`?([\\w./-]+)`?|\\b((?:gpt|claude|gemini)-[\\w.-]*\\d[\\w.-]*))")
 
-	// GitHub format: {pr_url}#issuecomment-{id}
-	if strings.Contains(commentId, "github") || strings.Contains(commentId, "Github") {
-		return prUrl + "#issuecomment-" + numericId
+// extractToolVersion returns the tool version ("v2.3.1") or, when no version is
+// reported, the model name ("gemini-2.5-pro") found in the review body so metrics
+// can be split by tool upgrade. It returns "" when the body names neither.
+func extractToolVersion(body string) string {
+	if match := toolVersionRe.FindStringSubmatch(body); len(match) > 1 {
+		return "v" + strings.ToLower(match[1])
 	}
-
-	// GitLab format: {mr_url}#note_{id}
-	if strings.Contains(commentId, "gitlab") || strings.Contains(commentId, "Gitlab") {
-		return prUrl + "#note_" + numericId
+	if match := toolModelRe.FindStringSubmatch(body); len(match) > 2 {
+		model := match[1]
+		if model == "" {
+			model = match[2]
+		}
+		model = strings.TrimRight(strings.ToLower(model), ".-")
+		if len(model) > 100 {
+			model = model[:100]
+		}
+		return model
 	}
-
-	return prUrl
+	return ""
 }
 
 // saveBatch saves a batch of AI reviews to the database
@@ -21,8 +21,12 @@ import (
 	"regexp"
 	"testing"
 
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
 	"github.com/apache/incubator-devlake/plugins/aireview/models"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestDetectAiTool_CodeRabbit(t *testing.T) {
@@ -330,7 +334,7 @@ func TestExtractSummary(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractSummary(tt.body)
+			got := extractSummary(nil, "", tt.body)
 			assert.Contains(t, got, tt.wantContain)
 			if tt.wantNotContain != "" {
 				assert.NotContains(t, got, tt.wantNotContain)
@@ -398,7 +402,7 @@ func TestHtmlToMarkdown(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := htmlToMarkdown(tt.input)
+			got := htmlToMarkdown(tt.input, "")
 			assert.Contains(t, got, tt.wantContain)
 			if tt.wantNotContain != "" {
 				assert.NotContains(t, got, tt.wantNotContain)
@@ -407,49 +411,89 @@ func TestHtmlToMarkdown(t *testing.T) {
 	}
 }
 
-func TestGenerateReviewId(t *testing.T) {
-	// Test deterministic ID generation
-	id1 := generateReviewId("pr-123", "comment-456", "coderabbit")
-	id2 := generateReviewId("pr-123", "comment-456", "coderabbit")
-	id3 := generateReviewId("pr-123", "comment-789", "coderabbit")
-
-	assert.Equal(t, id1, id2, "Same inputs should produce same ID")
-	assert.NotEqual(t, id1, id3, "Different inputs should produce different IDs")
-	assert.True(t, len(id1) > 0, "ID should not be empty")
-	assert.Contains(t, id1, "aireview:", "ID should have correct prefix")
-}
-
-func TestDetectSourcePlatform(t *testing.T) {
+// TestHtmlToMarkdown_ToolFormats covers markup from real tool output that the
+// generic conversion rules used to mangle
+func TestHtmlToMarkdown_ToolFormats(t *testing.T) {
 	tests := []struct {
-		name     string
-		prId     string
-		wantPlat string
+		name           string
+		aiTool         string
+		input          string
+		wantContain    []string
+		wantNotContain []string
 	}{
 		{
-			name:     "GitHub PR",
-			prId:     "github:GithubPullRequest:1:12345",
-			wantPlat: "github",
+			name:   "CodeRabbit mermaid sequence diagram is kept verbatim",
+			aiTool: models.AiToolCodeRabbit,
+			input: "## Walkthrough\n\nThe uploader retries failed chunks.\n\n## Sequence Diagram(s)\n\n" +
+				"```mermaid\nsequenceDiagram\n    participant C as Client\n    participant U as Uploader\n    C->>U: upload(file)\n    U-->>C: ok<br/>after retries\n```\n",
+			wantContain: []string{"U-->>C: ok<br/>after retries", "C->>U: upload(file)", "The uploader retries failed chunks."},
 		},
 		{
-			name:     "GitLab MR",
-			prId:     "gitlab:GitlabMergeRequest:1:67890",
-			wantPlat: "gitlab",
+			name:   "CodeRabbit nested review details keep both titles",
+			aiTool: models.AiToolCodeRabbit,
+			input: "<details>\n<summary>📜 Recent review details</summary>\n\n**Configuration used: CodeRabbit UI**\n\n" +
+				"<details open>\n<summary>📥 Commits</summary>\n\nReviewing files that changed from the base of the PR and between abc and def.\n\n</details>\n</details>",
+			wantContain:    []string{"**📜 Recent review details**", "**📥 Commits**", "Reviewing files that changed"},
+			wantNotContain: []string{"<details", "</details>", "<summary>"},
 		},
 		{
-			name:     "Unknown platform",
-			prId:     "bitbucket:PullRequest:1:11111",
-			wantPlat: "unknown",
+			name:   "Qodo nested compliance table keeps cells in order",
+			aiTool: models.AiToolQodo,
+			input: "## PR Compliance Guide 🔍\n\n<table><tr><td><strong>Ticket</strong></td><td>" +
+				"<table><tr><th>Requirement</th><th>Status</th></tr><tr><td>Retry uploads</td><td>🟢</td></tr></table>" +
+				"</td></tr><tr><td>⏱️&nbsp;<strong>Estimated effort to review</strong>: 3 🔵🔵🔵⚪⚪</td></tr></table>",
+			wantContain:    []string{"**Ticket**\nRequirement\nStatus\n\nRetry uploads\n🟢", "**Estimated effort to review**: 3"},
+			wantNotContain: []string{"<table", "<td", "</td>"},
+		},
+		{
+			name:        "Gemini suggestion keeps generic type parameters",
+			aiTool:      models.AiToolGemini,
+			input:       "![medium](https://www.gstatic.com/codereviewagent/medium-priority.svg)\n\nThe index should be typed.\n\n```suggestion\nMap<String, List<Integer>> index = new HashMap<>();\n```",
+			wantContain: []string{"Map<String, List<Integer>> index = new HashMap<>();"},
+		},
+		{
+			name:           "Unknown tool still converts HTML outside code blocks",
+			aiTool:         "",
+			input:          "<strong>Note</strong>\n\n```go\nvar x = a < b && c > d\n```",
+			wantContain:    []string{"**Note**", "var x = a < b && c > d"},
+			wantNotContain: []string{"<strong>"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := detectSourcePlatform(tt.prId)
-			assert.Equal(t, tt.wantPlat, got)
+			got := htmlToMarkdown(tt.input, tt.aiTool)
+			for _, want := range tt.wantContain {
+				assert.Contains(t, got, want)
+			}
+			for _, notWant := range tt.wantNotContain {
+				assert.NotContains(t, got, notWant)
+			}
 		})
 	}
 }
 
+func TestConvertReviewBody_PreserveHtml(t *testing.T) {
+	data := &AiReviewTaskData{PreserveHtmlTools: map[string]bool{models.AiToolQodo: true}}
+	body := "\"<table><tr><td><strong>Estimated effort to review</strong>: 2</td></tr></table>\\n\""
+
+	assert.Equal(t, "<table><tr><td><strong>Estimated effort to review</strong>: 2</td></tr></table>", convertReviewBody(data, models.AiToolQodo, body))
+	assert.Equal(t, "**Estimated effort to review**: 2", convertReviewBody(data, models.AiToolCodeRabbit, body))
+	assert.Equal(t, "**Estimated effort to review**: 2", convertReviewBody(nil, models.AiToolQodo, body))
+}
+
+func TestGenerateReviewId(t *testing.T) {
+	// Test deterministic ID generation
+	id1 := generateReviewId("pr-123", "comment-456", "coderabbit")
+	id2 := generateReviewId("pr-123", "comment-456", "coderabbit")
+	id3 := generateReviewId("pr-123", "comment-789", "coderabbit")
+
+	assert.Equal(t, id1, id2, "Same inputs should produce same ID")
+	assert.NotEqual(t, id1, id3, "Different inputs should produce different IDs")
+	assert.True(t, len(id1) > 0, "ID should not be empty")
+	assert.Contains(t, id1, "aireview:", "ID should have correct prefix")
+}
+
 func TestCompilePatterns(t *testing.T) {
 	t.Run("Default config compiles successfully", func(t *testing.T) {
 		taskData := &AiReviewTaskData{
@@ -486,6 +530,30 @@ func TestCompilePatterns(t *testing.T) {
 		err := CompilePatterns(taskData)
 		assert.NoError(t, err)
 		assert.NotNil(t, taskData.Options.ScopeConfig)
+		assert.Empty(t, taskData.SourcePlatformRules)
+	})
+
+	t.Run("Source platform rules are kept", func(t *testing.T) {
+		config := models.GetDefaultScopeConfig()
+		config.SourcePlatforms = []models.SourcePlatformRule{{Prefix: "gitlab-internal:", Platform: "gitlab"}}
+		taskData := &AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: config}}
+		err := CompilePatterns(taskData)
+		assert.NoError(t, err)
+		assert.Len(t, taskData.SourcePlatformRules, 1)
+		assert.Equal(t, "gitlab-internal:", taskData.SourcePlatformRules[0].Prefix)
+	})
+
+	t.Run("Invalid source platform rules return errors", func(t *testing.T) {
+		for _, rule := range []models.SourcePlatformRule{
+			{Platform: "gitlab"},
+			{Prefix: "gitlab-internal:"},
+			{Prefix: "gitlab-internal:", Platform: "gitlab", CommentUrlTemplate: "{prUrl}#note"},
+		} {
+			config := models.GetDefaultScopeConfig()
+			config.SourcePlatforms = []models.SourcePlatformRule{rule}
+			err := CompilePatterns(&AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: config}})
+			assert.Error(t, err)
+		}
 	})
 }
 
@@ -658,45 +726,67 @@ func TestDetectAiTool_Gemini(t *testing.T) {
 	}
 }
 
-func TestBuildCommentUrl(t *testing.T) {
+// copilotOverview is the body of a GitHub Copilot code review
+const copilotOverview = "## Pull request overview\n\n" +
+	"This PR adds retries to the artifact uploader and logs the failed attempts.\n\n" +
+	"### Reviewed Changes\n\n" +
+	"Copilot reviewed 3 out of 4 changed files in this pull request and generated 2 comments.\n\n" +
+	"| File | Description |\n| ---- | ----------- |\n| pkg/upload/client.go | Retry on 5xx |\n\n" +
+	"<details>\n<summary>Comments suppressed due to low confidence (2)</summary>\n</details>"
+
+func TestDetectAiTool_Copilot(t *testing.T) {
 	tests := []struct {
 		name      string
-		prUrl     string
-		commentId string
-		want      string
+		accountId string
+		body      string
+		wantTool  string
 	}{
-		{
-			name:      "GitHub comment",
-			prUrl:     "https://github.com/owner/repo/pull/123",
-			commentId: "github:GithubPrComment:1:456789",
-			want:      "https://github.com/owner/repo/pull/123#issuecomment-456789",
-		},
-		{
-			name:      "GitLab comment",
-			prUrl:     "https://gitlab.com/owner/repo/-/merge_requests/123",
-			commentId: "gitlab:GitlabMrComment:1:456789",
-			want:      "https://gitlab.com/owner/repo/-/merge_requests/123#note_456789",
-		},
-		{
-			name:      "Empty PR URL",
-			prUrl:     "",
-			commentId: "github:GithubPrComment:1:456789",
-			want:      "",
-		},
-		{
-			name:      "Malformed comment ID",
-			prUrl:     "https://github.com/owner/repo/pull/123",
-			commentId: "invalid",
-			want:      "https://github.com/owner/repo/pull/123",
-		},
+		{"Copilot by username", "copilot-pull-request-reviewer[bot]", "Consider checking the error", models.AiToolCopilot},
+		{"Copilot by review overview", "Copilot", copilotOverview, models.AiToolCopilot},
+		{"Copilot review without comments", "someone", "Copilot reviewed 2 out of 2 changed files in this pull request and generated no new comments.", models.AiToolCopilot},
+		{"Human mentioning Copilot", "developer", "Copilot suggested this change, LGTM", ""},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := buildCommentUrl(tt.prUrl, tt.commentId)
-			assert.Equal(t, tt.want, got)
+			taskData := &AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: models.GetDefaultScopeConfig()}}
+			assert.NoError(t, CompilePatterns(taskData))
+			gotTool, _ := detectAiTool(taskData, tt.accountId, tt.body)
+			assert.Equal(t, tt.wantTool, gotTool)
 		})
 	}
+
+	t.Run("disabled", func(t *testing.T) {
+		config := models.GetDefaultScopeConfig()
+		config.CopilotEnabled = false
+		taskData := &AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: config}}
+		assert.NoError(t, CompilePatterns(taskData))
+		_, isAi := detectAiTool(taskData, "copilot-pull-request-reviewer[bot]", copilotOverview)
+		assert.False(t, isAi)
+	})
+}
+
+func TestParseReviewMetrics_Copilot(t *testing.T) {
+	metrics := parseReviewMetrics(copilotOverview)
+	assert.Equal(t, 3, metrics.FilesReviewed)
+	assert.Equal(t, 2, metrics.IssuesFound)
+	assert.Equal(t, 50, metrics.Confidence)
+
+	metrics = parseReviewMetrics("Copilot reviewed 2 out of 2 changed files in this pull request and generated no new comments.")
+	assert.Equal(t, 2, metrics.FilesReviewed)
+	assert.Equal(t, 0, metrics.IssuesFound)
+	assert.Equal(t, 100, metrics.Confidence)
+
+	metrics = parseReviewMetrics("Copilot reviewed 1 out of 1 changed files in this pull request and generated 1 comment.")
+	assert.Equal(t, 1, metrics.IssuesFound)
+	assert.Equal(t, 100, metrics.Confidence)
+}
+
+func TestExtractSummary_Copilot(t *testing.T) {
+	data := &AiReviewTaskData{Options: &AiReviewOptions{ScopeConfig: models.GetDefaultScopeConfig()}}
+	assert.NoError(t, CompilePatterns(data))
+	assert.Equal(t, "This PR adds retries to the artifact uploader and logs the failed attempts.",
+		extractSummary(data, models.AiToolCopilot, copilotOverview))
 }
 
 func TestQodoPatternMatching(t *testing.T) {
@@ -944,3 +1034,78 @@ The changes introduce new handlers for authentication.
 	assert.Equal(t, 2, metrics.PreMergeChecksPassed)
 	assert.Equal(t, 1, metrics.PreMergeChecksInconclusive)
 }
+
+func TestPrStateClauses(t *testing.T) {
+	assert.Empty(t, prStateClauses(nil))
+	assert.Empty(t, prStateClauses(&models.AiReviewScopeConfig{}))
+	assert.Len(t, prStateClauses(&models.AiReviewScopeConfig{ExcludeDraftPrs: true}), 1)
+	assert.Len(t, prStateClauses(&models.AiReviewScopeConfig{ExcludeClosedUnmergedPrs: true}), 1)
+	clauses := prStateClauses(&models.AiReviewScopeConfig{
+		ExcludeDraftPrs:          true,
+		ExcludeClosedUnmergedPrs: true,
+	})
+	assert.Len(t, clauses, 2)
+	// pr is LEFT JOINed, rows without a matching PR must not be dropped
+	for _, clause := range clauses {
+		assert.Contains(t, clause.Data.(dal.DalClause).Expr, "IS NULL OR")
+	}
+}
+
+func TestExcludedPrCondition(t *testing.T) {
+	condition, args := excludedPrCondition(&models.AiReviewScopeConfig{})
+	assert.Empty(t, condition)
+	assert.Empty(t, args)
+
+	condition, args = excludedPrCondition(&models.AiReviewScopeConfig{ExcludeDraftPrs: true, ExcludeClosedUnmergedPrs: true})
+	assert.Equal(t, "is_draft = ? OR status = ?", condition)
+	assert.Equal(t, []interface{}{true, code.CLOSED}, args)
+}
+
+func TestDeleteExcludedPrRows(t *testing.T) {
+	t.Run("nothing deleted without PR state filters", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		data := &AiReviewTaskData{Options: &AiReviewOptions{RepoId: "repo-1", ScopeConfig: &models.AiReviewScopeConfig{}}}
+		assert.Nil(t, deleteExcludedPrRows(mockDal, data, &models.AiReview{}))
+		mockDal.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+
+	t.Run("rows of the excluded PRs of the repo are deleted from each table", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		var deleted []string
+		mockDal.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			deleted = append(deleted, args.Get(0).(dal.Tabler).TableName())
+			clauses := args.Get(1).([]dal.Clause)
+			assert.Len(t, clauses, 2)
+			assert.Contains(t, clauses[0].Data.(dal.DalClause).Expr, "is_draft = ?")
+			assert.Equal(t, "repo_id = ?", clauses[1].Data.(dal.DalClause).Expr)
+		}).Return(nil)
+		data := &AiReviewTaskData{Options: &AiReviewOptions{RepoId: "repo-1", ScopeConfig: &models.AiReviewScopeConfig{ExcludeDraftPrs: true}}}
+		assert.Nil(t, deleteExcludedPrRows(mockDal, data, &models.AiReview{}, &models.AiReviewFinding{}))
+		assert.Equal(t, []string{"_tool_aireview_reviews", "_tool_aireview_findings"}, deleted)
+	})
+}
+
+func TestExtractToolVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"CodeRabbit version", "Summary by CodeRabbit v2.3.1\n\nNew features", "v2.3.1"},
+		{"wildcard minor", "Reviewed with CodeRabbit v2.x", "v2.x"},
+		{"version label", "<sub>Version: 0.29</sub>", "v0.29"},
+		{"PR-Agent version", "Generated by PR-Agent v0.29.1", "v0.29.1"},
+		{"model label", "Model: `gpt-4o-mini`", "gpt-4o-mini"},
+		{"model family", "This review was generated by gemini-2.5-pro.", "gemini-2.5-pro"},
+		{"version wins over model", "CodeRabbit v3 using claude-sonnet-4", "v3"},
+		{"tool name without version", "CodeRabbit found 3 issues", ""},
+		{"project version bump is ignored", "Bump the chart version to 1.2.3", ""},
+		{"no metadata", "Looks good to me", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, extractToolVersion(tt.body))
+		})
+	}
+}
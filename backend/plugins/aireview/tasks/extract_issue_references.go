@@ -0,0 +1,271 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var ExtractIssueReferencesMeta = plugin.SubTaskMeta{
+	Name:             "extractIssueReferences",
+	EntryPoint:       ExtractIssueReferences,
+	EnabledByDefault: true,
+	Description:      "Extract Jira keys and repository issues referenced in AI reviews and look up their status in the issues domain",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW, plugin.DOMAIN_TYPE_TICKET},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractAiReviewsMeta},
+}
+
+var (
+	// jiraKeyRe matches Jira issue keys such as ABC-123
+	jiraKeyRe = regexp.MustCompile(`\b([A-Z][A-Z0-9]{1,9})-([1-9][0-9]{0,6})\b`)
+	// repoIssueUrlRe matches links to GitHub and GitLab issues
+	repoIssueUrlRe = regexp.MustCompile(`https?://[\w.-]+/([\w.-]+(?:/[\w.-]+)+?)(?:/-)?/issues/([0-9]+)`)
+	// repoIssueRe matches #42 and org/repo#42, but not anchors inside URLs or words
+	repoIssueRe = regexp.MustCompile(`(?:^|[\s(\[,;])((?:[\w.-]+/[\w.-]+)?)#([0-9]{1,7})\b`)
+)
+
+// notJiraProjects are key-like prefixes of standards and algorithms that AI reviews
+// mention all the time ("UTF-8", "SHA-256", "CVE-2024-1234")
+var notJiraProjects = map[string]bool{
+	"AES": true, "CVE": true, "CWE": true, "ECMA": true, "GHSA": true, "HTTP": true,
+	"IEEE": true, "ISO": true, "MD5": true, "PEP": true, "RFC": true, "RSA": true,
+	"SHA": true, "SHA1": true, "SHA2": true, "SHA3": true, "TLS": true, "SSL": true,
+	"UTF": true, "UUID": true, "X509": true, "GPT": true, "CHACHA20": true,
+}
+
+// issueRef is an issue referenced in a review body, before it is linked to the issues domain
+type issueRef struct {
+	Key     string // ABC-123, #42 or org/repo#42
+	RefType string
+	Repo    string // org/repo of a cross-repository reference, empty for the reviewed repo
+	Number  string // issue number of a repository issue, the full key of a Jira issue
+}
+
+// reviewBody is the subset of an AI review needed to extract references
+type reviewBody struct {
+	Id            string    `gorm:"column:id"`
+	PullRequestId string    `gorm:"column:pull_request_id"`
+	RepoId        string    `gorm:"column:repo_id"`
+	Body          string    `gorm:"column:body"`
+	CreatedDate   time.Time `gorm:"column:created_date"`
+}
+
+// referencedIssue is an issue of the issues domain matching a reference
+type referencedIssue struct {
+	Id             string     `gorm:"column:id"`
+	IssueKey       string     `gorm:"column:issue_key"`
+	RepoName       string     `gorm:"column:repo_name"`
+	Status         string     `gorm:"column:status"`
+	ResolutionDate *time.Time `gorm:"column:resolution_date"`
+}
+
+// ExtractIssueReferences stores the issues each AI review of the repo refers to,
+// and whether they are open or closed according to the issues domain. Jira issues
+// are matched by key; "#42" is matched against the issues of the reviewed repo's
+// board and "org/repo#42" against the board of that repo. References to issues
+// that were not collected are kept with an empty issue id. The references of the
+// repo are rebuilt on every run, so status changes of the issues are picked up.
+func ExtractIssueReferences(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+	repoId := data.Options.RepoId
+
+	var reviews []reviewBody
+	err := db.All(&reviews,
+		dal.Select("id, pull_request_id, repo_id, body, created_date"),
+		dal.From(&models.AiReview{}),
+		dal.Where("repo_id = ?", repoId),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load AI reviews")
+	}
+
+	if err := db.Delete(&models.AiReviewIssueRef{}, dal.Where("repo_id = ?", repoId)); err != nil {
+		return errors.Default.Wrap(err, "failed to delete previous issue references")
+	}
+
+	refsByReview := make(map[string][]issueRef, len(reviews))
+	var jiraKeys, localNumbers []string
+	crossRepo := make(map[string][]string)
+	for _, review := range reviews {
+		refs := parseIssueRefs(review.Body)
+		refsByReview[review.Id] = refs
+		for _, ref := range refs {
+			switch {
+			case ref.RefType == models.IssueRefTypeJira:
+				jiraKeys = append(jiraKeys, ref.Number)
+			case ref.Repo == "":
+				localNumbers = append(localNumbers, ref.Number)
+			default:
+				crossRepo[ref.Repo] = append(crossRepo[ref.Repo], ref.Number)
+			}
+		}
+	}
+
+	issues, err := lookupReferencedIssues(db, repoId, jiraKeys, localNumbers, crossRepo)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to look up referenced issues")
+	}
+
+	saved, linked := 0, 0
+	for _, review := range reviews {
+		for _, ref := range refsByReview[review.Id] {
+			record := &models.AiReviewIssueRef{
+				AiReviewId:    review.Id,
+				IssueKey:      ref.Key,
+				PullRequestId: review.PullRequestId,
+				RepoId:        review.RepoId,
+				RefType:       ref.RefType,
+				CreatedDate:   review.CreatedDate,
+			}
+			if issue, ok := issues[ref.Key]; ok {
+				record.IssueId = issue.Id
+				record.IssueStatus = issue.Status
+				record.IssueResolutionDate = issue.ResolutionDate
+				linked++
+			}
+			if err := db.CreateOrUpdate(record); err != nil {
+				return errors.Default.Wrap(err, "failed to save issue reference")
+			}
+			saved++
+		}
+	}
+
+	logger.Info("Extracted %d issue references from %d AI reviews, %d linked to collected issues", saved, len(reviews), linked)
+	return nil
+}
+
+// parseIssueRefs returns the distinct issues referenced in a review body, in order of appearance.
+// Code blocks are skipped, as keys in code are identifiers rather than references.
+func parseIssueRefs(body string) []issueRef {
+	text := codeBlockRe.ReplaceAllString(body, "")
+	seen := make(map[string]bool)
+	var refs []issueRef
+	add := func(ref issueRef) {
+		if !seen[ref.Key] {
+			seen[ref.Key] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	for _, m := range jiraKeyRe.FindAllStringSubmatch(text, -1) {
+		if notJiraProjects[m[1]] {
+			continue
+		}
+		add(issueRef{Key: m[0], RefType: models.IssueRefTypeJira, Number: m[0]})
+	}
+	for _, m := range repoIssueUrlRe.FindAllStringSubmatch(text, -1) {
+		add(issueRef{Key: m[1] + "#" + m[2], RefType: models.IssueRefTypeRepoIssue, Repo: m[1], Number: m[2]})
+	}
+	// URLs are matched already, and their fragments look like references ("#L42")
+	text = repoIssueUrlRe.ReplaceAllString(text, "")
+	for _, m := range repoIssueRe.FindAllStringSubmatch(text, -1) {
+		add(issueRef{Key: m[1] + "#" + m[2], RefType: models.IssueRefTypeRepoIssue, Repo: m[1], Number: m[2]})
+	}
+	return refs
+}
+
+// lookupReferencedIssues finds the collected issues matching the references, keyed by issueRef.Key
+func lookupReferencedIssues(db dal.Dal, repoId string, jiraKeys, localNumbers []string, crossRepo map[string][]string) (map[string]referencedIssue, errors.Error) {
+	found := make(map[string]referencedIssue)
+
+	if len(jiraKeys) > 0 {
+		var issues []referencedIssue
+		err := db.All(&issues,
+			dal.Select("i.id, i.issue_key, i.status, i.resolution_date"),
+			dal.From("issues i"),
+			dal.Where("i.issue_key IN (?)", distinctStrings(jiraKeys)),
+			dal.Orderby("i.id"),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if _, ok := found[issue.IssueKey]; !ok {
+				found[issue.IssueKey] = issue
+			}
+		}
+	}
+
+	if len(localNumbers) > 0 {
+		var issues []referencedIssue
+		err := db.All(&issues,
+			dal.Select("i.id, i.issue_key, i.status, i.resolution_date"),
+			dal.From("issues i"),
+			dal.Join("JOIN board_issues bi ON bi.issue_id = i.id"),
+			dal.Where("bi.board_id = ? AND i.issue_key IN (?)", repoId, distinctStrings(localNumbers)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			found["#"+issue.IssueKey] = issue
+		}
+	}
+
+	if len(crossRepo) > 0 {
+		repoNames := make([]string, 0, len(crossRepo))
+		var numbers []string
+		for name, repoNumbers := range crossRepo {
+			repoNames = append(repoNames, name)
+			numbers = append(numbers, repoNumbers...)
+		}
+		sort.Strings(repoNames)
+		var issues []referencedIssue
+		err := db.All(&issues,
+			dal.Select("i.id, i.issue_key, r.name AS repo_name, i.status, i.resolution_date"),
+			dal.From("issues i"),
+			dal.Join("JOIN board_issues bi ON bi.issue_id = i.id"),
+			dal.Join("JOIN repos r ON r.id = bi.board_id"),
+			dal.Where("r.name IN (?) AND i.issue_key IN (?)", repoNames, distinctStrings(numbers)),
+		)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			found[fmt.Sprintf("%s#%s", issue.RepoName, issue.IssueKey)] = issue
+		}
+	}
+
+	return found, nil
+}
+
+// distinctStrings returns the values without duplicates, sorted
+func distinctStrings(values []string) []string {
+	set := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && !set[value] {
+			set[value] = true
+			result = append(result, value)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
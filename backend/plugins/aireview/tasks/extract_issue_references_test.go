@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIssueRefs(t *testing.T) {
+	body := "This relates to ABC-123 and fixes #42 (see also konflux-ci/build-service#7).\n" +
+		"Details in https://github.com/konflux-ci/release-service/issues/15#issuecomment-1 and " +
+		"https://gitlab.com/group/sub/project/-/issues/9.\n" +
+		"Use UTF-8 and SHA-256, not CVE-2024-1234. ABC-123 again, #42 again.\n" +
+		"See handler.go#L10 and issue#5.\n" +
+		"```go\n// TODO XYZ-9 #77\n```\n"
+
+	refs := parseIssueRefs(body)
+
+	keys := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		keys = append(keys, ref.Key)
+	}
+	assert.Equal(t, []string{
+		"ABC-123",
+		"konflux-ci/release-service#15",
+		"group/sub/project#9",
+		"#42",
+		"konflux-ci/build-service#7",
+	}, keys)
+
+	assert.Equal(t, issueRef{Key: "ABC-123", RefType: models.IssueRefTypeJira, Number: "ABC-123"}, refs[0])
+	assert.Equal(t, issueRef{Key: "#42", RefType: models.IssueRefTypeRepoIssue, Number: "42"}, refs[3])
+	assert.Equal(t, issueRef{Key: "konflux-ci/build-service#7", RefType: models.IssueRefTypeRepoIssue, Repo: "konflux-ci/build-service", Number: "7"}, refs[4])
+}
+
+func TestParseIssueRefsNone(t *testing.T) {
+	assert.Empty(t, parseIssueRefs(""))
+	assert.Empty(t, parseIssueRefs("## Walkthrough\nLooks good, uses ISO-8601 dates."))
+}
+
+func TestDistinctStrings(t *testing.T) {
+	assert.Equal(t, []string{"1", "2"}, distinctStrings([]string{"2", "1", "2", " ", ""}))
+}
@@ -94,7 +94,9 @@ func FetchMissingCiJobs(taskCtx plugin.SubTaskContext) errors.Error {
 		var repoRows []struct {
 			RepoId string `gorm:"column:repo_id"`
 		}
-		if dbErr := db.All(&repoRows, dal.Select("DISTINCT repo_id"), dal.From("_tool_aireview_reviews")); dbErr != nil {
+		repoClauses := append([]dal.Clause{dal.Select("DISTINCT repo_id"), dal.From("_tool_aireview_reviews")},
+			excludedRepoClauses("repo_id", data.Options.ExcludeRepoIds)...)
+		if dbErr := db.All(&repoRows, repoClauses...); dbErr != nil {
 			return errors.Default.Wrap(dbErr, "querying distinct repo IDs from aireview reviews")
 		}
 		for _, r := range repoRows {
@@ -657,6 +657,7 @@ func enrichFindingFilePaths(db dal.Dal, data *AiReviewTaskData) (int, errors.Err
 			dal.Where("pm.project_name = ? AND pm.`table` = ? AND f.file_path = '' AND prc._raw_data_table != ''",
 				data.Options.ProjectName, "repos"),
 		}
+		clauses = append(clauses, excludedRepoClauses("pr.base_repo_id", data.Options.ExcludeRepoIds)...)
 	} else {
 		clauses = []dal.Clause{
 			dal.Select("f.id as finding_id, prc._raw_data_table, prc._raw_data_id"),
@@ -0,0 +1,137 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// defaultRiskTermMappings are the severity labels the AI tools put on their comments,
+// matched after the scope config's riskTermMappings
+var defaultRiskTermMappings = []models.RiskTermMapping{
+	// CodeRabbit labels each inline comment, e.g. "_⚠️ Potential issue_"
+	{AiTool: models.AiToolCodeRabbit, Term: "Potential issue", Category: models.FindingCategoryBug, Severity: models.FindingSeverityError},
+	{AiTool: models.AiToolCodeRabbit, Term: "Refactor suggestion", Category: models.FindingCategoryMaintainability, Severity: models.FindingSeverityWarning},
+	{AiTool: models.AiToolCodeRabbit, Term: "Nitpick", Category: models.FindingCategoryStyle, Severity: models.FindingSeverityInfo},
+	// Qodo names the category of its code suggestions
+	{AiTool: models.AiToolQodo, Term: "Possible issue", Category: models.FindingCategoryBug, Severity: models.FindingSeverityError},
+	{AiTool: models.AiToolQodo, Term: "Possible bug", Category: models.FindingCategoryBug, Severity: models.FindingSeverityError},
+	// Gemini Code Assist starts inline comments with a priority badge, e.g. "![high](...high-priority.svg)"
+	{AiTool: models.AiToolGemini, Term: "![critical]", Severity: models.FindingSeverityCritical},
+	{AiTool: models.AiToolGemini, Term: "![high]", Severity: models.FindingSeverityError},
+	{AiTool: models.AiToolGemini, Term: "![medium]", Severity: models.FindingSeverityWarning},
+	{AiTool: models.AiToolGemini, Term: "![low]", Severity: models.FindingSeverityInfo},
+}
+
+// defaultRiskTermRules are the compiled defaultRiskTermMappings
+var defaultRiskTermRules = mustCompileRiskTermRules(defaultRiskTermMappings)
+
+// findingCategories are the normalized finding categories a mapping can set
+var findingCategories = map[string]bool{
+	models.FindingCategorySecurity:        true,
+	models.FindingCategoryPerformance:     true,
+	models.FindingCategoryBestPractice:    true,
+	models.FindingCategoryBug:             true,
+	models.FindingCategoryStyle:           true,
+	models.FindingCategoryDocumentation:   true,
+	models.FindingCategoryMaintainability: true,
+}
+
+// riskTermRule is a risk term mapping with its case-insensitive term regex
+type riskTermRule struct {
+	models.RiskTermMapping
+	termRegex *regexp.Regexp
+}
+
+// compileRiskTermRules validates the risk term mappings of a scope config: a mapping needs
+// a term and sets a known category, a known severity or both
+func compileRiskTermRules(mappings []models.RiskTermMapping) ([]riskTermRule, errors.Error) {
+	var rules []riskTermRule
+	for i, mapping := range mappings {
+		if strings.TrimSpace(mapping.Term) == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("riskTermMappings[%d] needs a term", i))
+		}
+		if mapping.Category == "" && mapping.Severity == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("riskTermMappings[%d] needs a category or a severity", i))
+		}
+		if mapping.Category != "" && !findingCategories[mapping.Category] {
+			return nil, errors.BadInput.New(fmt.Sprintf("riskTermMappings[%d] has unknown category %q", i, mapping.Category))
+		}
+		if _, ok := severityRank[mapping.Severity]; mapping.Severity != "" && !ok {
+			return nil, errors.BadInput.New(fmt.Sprintf("riskTermMappings[%d] has unknown severity %q", i, mapping.Severity))
+		}
+		rules = append(rules, riskTermRule{
+			RiskTermMapping: mapping,
+			termRegex:       regexp.MustCompile("(?i)" + regexp.QuoteMeta(strings.TrimSpace(mapping.Term))),
+		})
+	}
+	return rules, nil
+}
+
+func mustCompileRiskTermRules(mappings []models.RiskTermMapping) []riskTermRule {
+	rules, err := compileRiskTermRules(mappings)
+	if err != nil {
+		panic(err)
+	}
+	return rules
+}
+
+// normalizeFindingRisk sets the category and severity of a finding from the first risk term
+// of its tool found in the finding text, else in the heading line of the review (where
+// tools put the label of an inline comment). The scope config rules are tried before the
+// defaults; a finding without a known term keeps the detected category and severity.
+func normalizeFindingRisk(finding *models.AiReviewFinding, heading string, rules []riskTermRule) {
+	for _, text := range []string{finding.Description, heading} {
+		if text == "" {
+			continue
+		}
+		for _, ruleSet := range [][]riskTermRule{rules, defaultRiskTermRules} {
+			for _, rule := range ruleSet {
+				if rule.AiTool != "" && rule.AiTool != finding.AiTool {
+					continue
+				}
+				if !rule.termRegex.MatchString(text) {
+					continue
+				}
+				if rule.Category != "" {
+					finding.Category = rule.Category
+				}
+				if rule.Severity != "" {
+					finding.Severity = rule.Severity
+				}
+				finding.ToolTerm = strings.TrimSpace(rule.Term)
+				return
+			}
+		}
+	}
+}
+
+// reviewHeading is the first non-empty line of a review body
+func reviewHeading(body string) string {
+	for _, line := range strings.Split(body, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,146 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompileRiskTermRules(t *testing.T) {
+	rules, err := compileRiskTermRules([]models.RiskTermMapping{
+		{AiTool: models.AiToolQodo, Term: " Minor ", Severity: models.FindingSeverityInfo},
+	})
+	require.Nil(t, err)
+	if assert.Len(t, rules, 1) {
+		assert.True(t, rules[0].termRegex.MatchString("a MINOR remark"))
+	}
+
+	for _, mapping := range []models.RiskTermMapping{
+		{Term: "", Severity: models.FindingSeverityInfo},
+		{Term: "Minor"},
+		{Term: "Minor", Category: "cosmetic"},
+		{Term: "Minor", Severity: "low"},
+	} {
+		_, err := compileRiskTermRules([]models.RiskTermMapping{mapping})
+		assert.NotNil(t, err, "%+v", mapping)
+	}
+}
+
+func TestNormalizeFindingRisk(t *testing.T) {
+	custom, err := compileRiskTermRules([]models.RiskTermMapping{
+		{AiTool: models.AiToolCodeRabbit, Term: "Nitpick", Category: models.FindingCategoryStyle, Severity: models.FindingSeverityWarning},
+		{Term: "blocker", Severity: models.FindingSeverityCritical},
+	})
+	require.Nil(t, err)
+
+	tests := []struct {
+		name         string
+		tool         string
+		description  string
+		heading      string
+		rules        []riskTermRule
+		wantCategory string
+		wantSeverity string
+		wantTerm     string
+	}{
+		{
+			name:         "CodeRabbit default term in the finding",
+			tool:         models.AiToolCodeRabbit,
+			description:  "🧹 Nitpick: rename the variable",
+			wantCategory: models.FindingCategoryStyle,
+			wantSeverity: models.FindingSeverityInfo,
+			wantTerm:     "Nitpick",
+		},
+		{
+			name:         "Term in the heading of an inline comment",
+			tool:         models.AiToolCodeRabbit,
+			description:  "AI-suggested code change",
+			heading:      "_⚠️ Potential issue_",
+			wantCategory: models.FindingCategoryBug,
+			wantSeverity: models.FindingSeverityError,
+			wantTerm:     "Potential issue",
+		},
+		{
+			name:         "Gemini badge keeps the detected category",
+			tool:         models.AiToolGemini,
+			description:  "Handle the error returned by Close",
+			heading:      "![high](https://www.gstatic.com/codereviewagent/high-priority.svg)",
+			wantCategory: models.FindingCategoryPerformance,
+			wantSeverity: models.FindingSeverityError,
+			wantTerm:     "![high]",
+		},
+		{
+			name:         "Terms of another tool are ignored",
+			tool:         models.AiToolQodo,
+			description:  "Nitpick: rename the variable",
+			wantCategory: models.FindingCategoryPerformance,
+			wantSeverity: models.FindingSeverityWarning,
+		},
+		{
+			name:         "Scope config mapping wins over the defaults",
+			tool:         models.AiToolCodeRabbit,
+			description:  "Nitpick: rename the variable",
+			rules:        custom,
+			wantCategory: models.FindingCategoryStyle,
+			wantSeverity: models.FindingSeverityWarning,
+			wantTerm:     "Nitpick",
+		},
+		{
+			name:         "Scope config mapping for any tool",
+			tool:         models.AiToolCursorBugbot,
+			description:  "BLOCKER: the lock is never released",
+			rules:        custom,
+			wantCategory: models.FindingCategoryPerformance,
+			wantSeverity: models.FindingSeverityCritical,
+			wantTerm:     "blocker",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			finding := &models.AiReviewFinding{
+				AiTool:      tt.tool,
+				Description: tt.description,
+				Category:    models.FindingCategoryPerformance,
+				Severity:    models.FindingSeverityWarning,
+			}
+			normalizeFindingRisk(finding, tt.heading, tt.rules)
+			assert.Equal(t, tt.wantCategory, finding.Category)
+			assert.Equal(t, tt.wantSeverity, finding.Severity)
+			assert.Equal(t, tt.wantTerm, finding.ToolTerm)
+		})
+	}
+}
+
+func TestParseFindings_RiskTerms(t *testing.T) {
+	review := &models.AiReview{
+		Id:     "r1",
+		AiTool: models.AiToolCodeRabbit,
+		Body:   "_🛠️ Refactor suggestion_\n\n```suggestion\nreturn nil\n```",
+	}
+	findings := parseFindings(review, nil)
+	if assert.Len(t, findings, 1) {
+		assert.Equal(t, models.FindingCategoryMaintainability, findings[0].Category)
+		assert.Equal(t, models.FindingSeverityWarning, findings[0].Severity)
+		assert.Equal(t, "Refactor suggestion", findings[0].ToolTerm)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+// prSource is what the domain tables tell about the data source plugin of a PR: the raw
+// tables its pull_requests and repos rows were converted from, and the URL of its repo
+type prSource struct {
+	PrRawTable   string
+	RepoRawTable string
+	RepoUrl      string
+}
+
+// defaultSourcePlatforms match the ids of the stock data source plugins, used when the
+// domain tables don't tell the platform
+var defaultSourcePlatforms = []models.SourcePlatformRule{
+	{Prefix: "github:", Platform: models.SourcePlatformGithub},
+	{Prefix: "gitlab:", Platform: models.SourcePlatformGitlab},
+	{Prefix: "bitbucket:", Platform: models.SourcePlatformBitbucket},
+	{Prefix: "azuredevops:", Platform: models.SourcePlatformAzureDevops},
+}
+
+// rawTablePlatforms map the raw table names of the data source plugins to their platform.
+// The names are constants of the plugins, so they hold for plugins registered under a
+// custom name too. Bitbucket Server comes first: its links differ from Bitbucket Cloud.
+var rawTablePlatforms = []models.SourcePlatformRule{
+	{Prefix: "_raw_bitbucket_server_", Platform: models.SourcePlatformUnknown},
+	{Prefix: "_raw_github_", Platform: models.SourcePlatformGithub},
+	{Prefix: "_raw_gitlab_", Platform: models.SourcePlatformGitlab},
+	{Prefix: "_raw_bitbucket_", Platform: models.SourcePlatformBitbucket},
+	{Prefix: "_raw_azuredevops", Platform: models.SourcePlatformAzureDevops},
+}
+
+// commentUrlTemplates link to a comment on the PR page of each platform. Azure DevOps has
+// none: the azuredevops plugins don't collect PR comments and its anchors take thread ids,
+// so its links point at the PR unless a sourcePlatforms rule gives a template.
+var commentUrlTemplates = map[string]string{
+	models.SourcePlatformGithub:    "{prUrl}#issuecomment-{commentId}",
+	models.SourcePlatformGitlab:    "{prUrl}#note_{commentId}",
+	models.SourcePlatformBitbucket: "{prUrl}#comment-{commentId}",
+}
+
+// matchSourcePlatform returns the first rule whose prefix the DevLake id starts with, or nil
+func matchSourcePlatform(id string, rules []models.SourcePlatformRule) *models.SourcePlatformRule {
+	for i := range rules {
+		if strings.HasPrefix(id, rules[i].Prefix) {
+			return &rules[i]
+		}
+	}
+	return nil
+}
+
+// resolveSourcePlatform determines the platform of a PR or comment id: a sourcePlatforms
+// rule of the scope config wins, then the raw tables of the PR and repo, the repo URL host
+// and last the prefixes of the stock plugins. The rule is nil when nothing matches.
+func resolveSourcePlatform(id string, source prSource, rules []models.SourcePlatformRule) *models.SourcePlatformRule {
+	if rule := matchSourcePlatform(id, rules); rule != nil {
+		return rule
+	}
+	for _, rawTable := range []string{source.PrRawTable, source.RepoRawTable} {
+		if rawTable == "" {
+			continue
+		}
+		if rule := matchSourcePlatform(rawTable, rawTablePlatforms); rule != nil {
+			if rule.Platform == models.SourcePlatformUnknown {
+				return nil
+			}
+			return &models.SourcePlatformRule{Platform: rule.Platform}
+		}
+	}
+	if platform := platformFromUrl(source.RepoUrl); platform != "" {
+		return &models.SourcePlatformRule{Platform: platform}
+	}
+	return matchSourcePlatform(id, defaultSourcePlatforms)
+}
+
+// platformFromUrl recognizes the hosts of the SaaS platforms and hosts named after them,
+// e.g. gitlab.example.com; empty for any other host
+func platformFromUrl(rawUrl string) string {
+	parsed, err := url.Parse(rawUrl)
+	if err != nil || parsed.Hostname() == "" {
+		return ""
+	}
+	host := parsed.Hostname()
+	switch {
+	case host == "dev.azure.com" || strings.HasSuffix(host, ".visualstudio.com"):
+		return models.SourcePlatformAzureDevops
+	case host == "bitbucket.org":
+		return models.SourcePlatformBitbucket
+	case host == "github.com" || strings.HasPrefix(host, "github."):
+		return models.SourcePlatformGithub
+	case host == "gitlab.com" || strings.HasPrefix(host, "gitlab."):
+		return models.SourcePlatformGitlab
+	}
+	return ""
+}
+
+// detectSourcePlatform determines the platform of a PR, see resolveSourcePlatform
+func detectSourcePlatform(prId string, source prSource, rules []models.SourcePlatformRule) string {
+	if rule := resolveSourcePlatform(prId, source, rules); rule != nil {
+		return rule.Platform
+	}
+	return models.SourcePlatformUnknown
+}
+
+// buildCommentUrl constructs a direct URL to the comment
+// commentId format: "github:GithubPrComment:1:123456789" or "gitlab:GitlabMrComment:1:123456"
+func buildCommentUrl(prUrl, commentId string, source prSource, rules []models.SourcePlatformRule) string {
+	if prUrl == "" {
+		return ""
+	}
+
+	// Extract the numeric comment ID from the DevLake ID
+	parts := strings.Split(commentId, ":")
+	if len(parts) < 4 {
+		return prUrl
+	}
+	numericId := parts[len(parts)-1]
+
+	template := ""
+	platform := ""
+	if rule := resolveSourcePlatform(commentId, source, rules); rule != nil {
+		platform = rule.Platform
+		template = rule.CommentUrlTemplate
+		if template == "" {
+			template = commentUrlTemplates[platform]
+		}
+	} else if strings.Contains(commentId, "github") || strings.Contains(commentId, "Github") {
+		template = commentUrlTemplates[models.SourcePlatformGithub]
+	} else if strings.Contains(commentId, "gitlab") || strings.Contains(commentId, "Gitlab") {
+		template = commentUrlTemplates[models.SourcePlatformGitlab]
+	}
+	if platform == models.SourcePlatformAzureDevops {
+		prUrl = azureDevopsPrWebUrl(prUrl)
+	}
+	if template == "" {
+		return prUrl
+	}
+	return strings.NewReplacer("{prUrl}", prUrl, "{commentId}", numericId).Replace(template)
+}
+
+// azureDevopsPrWebUrl turns the REST URL the azuredevops plugin stores for a PR,
+// .../{project}/_apis/git/repositories/{repoId}/pullRequests/{number}, into the URL of its
+// page, .../{project}/_git/{repoId}/pullrequest/{number}. Other URLs are kept.
+func azureDevopsPrWebUrl(prUrl string) string {
+	if !strings.Contains(prUrl, "/_apis/git/repositories/") {
+		return prUrl
+	}
+	return strings.NewReplacer("/_apis/git/repositories/", "/_git/", "/pullRequests/", "/pullrequest/").Replace(prUrl)
+}
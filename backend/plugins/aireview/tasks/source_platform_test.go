@@ -0,0 +1,236 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectSourcePlatform(t *testing.T) {
+	customRules := []models.SourcePlatformRule{
+		{Prefix: "gitlab-internal:", Platform: "gitlab"},
+	}
+
+	tests := []struct {
+		name     string
+		prId     string
+		source   prSource
+		rules    []models.SourcePlatformRule
+		wantPlat string
+	}{
+		{
+			name:     "GitHub PR",
+			prId:     "github:GithubPullRequest:1:12345",
+			wantPlat: "github",
+		},
+		{
+			name:     "GitLab MR",
+			prId:     "gitlab:GitlabMergeRequest:1:67890",
+			wantPlat: "gitlab",
+		},
+		{
+			name:     "Bitbucket PR",
+			prId:     "bitbucket:BitbucketPullRequest:1:ws/repo:11",
+			wantPlat: "bitbucket",
+		},
+		{
+			name:     "Unknown platform",
+			prId:     "gitee:GiteePullRequest:1:11111",
+			wantPlat: "unknown",
+		},
+		{
+			name:     "Custom plugin name without a rule",
+			prId:     "gitlab-internal:GitlabMergeRequest:1:67890",
+			wantPlat: "unknown",
+		},
+		{
+			name:     "Custom plugin name with a rule",
+			prId:     "gitlab-internal:GitlabMergeRequest:1:67890",
+			rules:    customRules,
+			wantPlat: "gitlab",
+		},
+		{
+			name:     "Built-in prefixes still match after custom rules",
+			prId:     "github:GithubPullRequest:1:12345",
+			rules:    customRules,
+			wantPlat: "github",
+		},
+		{
+			name:     "Custom plugin name resolved from the PR raw table",
+			prId:     "gitlab-internal:GitlabMergeRequest:1:67890",
+			source:   prSource{PrRawTable: "_raw_gitlab_api_merge_requests"},
+			wantPlat: "gitlab",
+		},
+		{
+			name:     "Raw table wins over a misleading prefix",
+			prId:     "github:GithubPullRequest:1:12345",
+			source:   prSource{PrRawTable: "_raw_gitlab_api_merge_requests"},
+			wantPlat: "gitlab",
+		},
+		{
+			name:     "Repo raw table when the PR has none",
+			prId:     "ado:AzuredevopsPullRequest:1:3",
+			source:   prSource{RepoRawTable: "_raw_azuredevops_go_api_repositories"},
+			wantPlat: "azuredevops",
+		},
+		{
+			name:     "Bitbucket Server is not Bitbucket Cloud",
+			prId:     "bitbucket_server:BitbucketServerPullRequest:1:ws/repo:11",
+			source:   prSource{PrRawTable: "_raw_bitbucket_server_api_pull_requests"},
+			wantPlat: "unknown",
+		},
+		{
+			name:     "Repo URL host",
+			prId:     "scm:PullRequest:1:3",
+			source:   prSource{RepoUrl: "https://bitbucket.org/ws/repo"},
+			wantPlat: "bitbucket",
+		},
+		{
+			name:     "Self-hosted repo URL host",
+			prId:     "scm:PullRequest:1:3",
+			source:   prSource{RepoUrl: "https://gitlab.example.com/group/repo"},
+			wantPlat: "gitlab",
+		},
+		{
+			name:     "Scope config rule wins over the domain tables",
+			prId:     "gitlab-internal:GitlabMergeRequest:1:67890",
+			source:   prSource{PrRawTable: "_raw_github_api_pull_requests"},
+			rules:    customRules,
+			wantPlat: "gitlab",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectSourcePlatform(tt.prId, tt.source, tt.rules)
+			assert.Equal(t, tt.wantPlat, got)
+		})
+	}
+}
+
+func TestBuildCommentUrl(t *testing.T) {
+	customRules := []models.SourcePlatformRule{
+		{Prefix: "gitlab-internal:", Platform: "gitlab"},
+		{Prefix: "ghe:", Platform: "github", CommentUrlTemplate: "{prUrl}#discussion_r{commentId}"},
+	}
+
+	tests := []struct {
+		name      string
+		prUrl     string
+		commentId string
+		source    prSource
+		rules     []models.SourcePlatformRule
+		want      string
+	}{
+		{
+			name:      "GitHub comment",
+			prUrl:     "https://github.com/owner/repo/pull/123",
+			commentId: "github:GithubPrComment:1:456789",
+			want:      "https://github.com/owner/repo/pull/123#issuecomment-456789",
+		},
+		{
+			name:      "GitLab comment",
+			prUrl:     "https://gitlab.com/owner/repo/-/merge_requests/123",
+			commentId: "gitlab:GitlabMrComment:1:456789",
+			want:      "https://gitlab.com/owner/repo/-/merge_requests/123#note_456789",
+		},
+		{
+			name:      "Empty PR URL",
+			prUrl:     "",
+			commentId: "github:GithubPrComment:1:456789",
+			want:      "",
+		},
+		{
+			name:      "Malformed comment ID",
+			prUrl:     "https://github.com/owner/repo/pull/123",
+			commentId: "invalid",
+			want:      "https://github.com/owner/repo/pull/123",
+		},
+		{
+			name:      "Custom plugin name matched by rule",
+			prUrl:     "https://git.example.com/owner/repo/-/merge_requests/7",
+			commentId: "gitlab-internal:GitlabMrComment:2:1001",
+			rules:     customRules,
+			want:      "https://git.example.com/owner/repo/-/merge_requests/7#note_1001",
+		},
+		{
+			name:      "Rule with URL template",
+			prUrl:     "https://ghe.example.com/owner/repo/pull/5",
+			commentId: "ghe:GithubPrComment:3:2002",
+			rules:     customRules,
+			want:      "https://ghe.example.com/owner/repo/pull/5#discussion_r2002",
+		},
+		{
+			name:      "Unmatched prefix falls back to the comment type",
+			prUrl:     "https://ghe.example.com/owner/repo/pull/5",
+			commentId: "ghe:GithubPrComment:3:2002",
+			want:      "https://ghe.example.com/owner/repo/pull/5#issuecomment-2002",
+		},
+		{
+			name:      "Unknown platform keeps the PR URL",
+			prUrl:     "https://example.com/pr/5",
+			commentId: "custom:PrComment:3:2002",
+			want:      "https://example.com/pr/5",
+		},
+		{
+			name:      "Custom GitLab plugin name resolved from the raw table",
+			prUrl:     "https://git.example.com/group/repo/-/merge_requests/7",
+			commentId: "gitlab-internal:GitlabMrNote:2:1001",
+			source:    prSource{PrRawTable: "_raw_gitlab_api_merge_requests"},
+			want:      "https://git.example.com/group/repo/-/merge_requests/7#note_1001",
+		},
+		{
+			name:      "Bitbucket comment",
+			prUrl:     "https://bitbucket.org/ws/repo/pull-requests/11",
+			commentId: "bitbucket:BitbucketPrComment:1:3003",
+			source:    prSource{PrRawTable: "_raw_bitbucket_api_pull_requests"},
+			want:      "https://bitbucket.org/ws/repo/pull-requests/11#comment-3003",
+		},
+		{
+			name:      "Azure DevOps comment links the page of the PR from its REST URL",
+			prUrl:     "https://dev.azure.com/org/proj/_apis/git/repositories/8d1c/pullRequests/42",
+			commentId: "azuredevops:AzuredevopsPrThread:1:7",
+			source:    prSource{RepoRawTable: "_raw_azuredevops_go_api_repositories"},
+			want:      "https://dev.azure.com/org/proj/_git/8d1c/pullrequest/42",
+		},
+		{
+			name:      "Azure DevOps comment links the PR page URL",
+			prUrl:     "https://dev.azure.com/org/proj/_git/repo/pullrequest/42",
+			commentId: "ado:PrThread:1:7",
+			source:    prSource{RepoUrl: "https://dev.azure.com/org/proj/_git/repo"},
+			want:      "https://dev.azure.com/org/proj/_git/repo/pullrequest/42",
+		},
+		{
+			name:      "Azure DevOps template of a rule applies to the PR page URL",
+			prUrl:     "https://dev.azure.com/org/proj/_apis/git/repositories/8d1c/pullRequests/42",
+			commentId: "ado:PrThread:1:7",
+			rules:     []models.SourcePlatformRule{{Prefix: "ado:", Platform: models.SourcePlatformAzureDevops, CommentUrlTemplate: "{prUrl}?discussionId={commentId}"}},
+			want:      "https://dev.azure.com/org/proj/_git/8d1c/pullrequest/42?discussionId=7",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := buildCommentUrl(tt.prUrl, tt.commentId, tt.source, tt.rules)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+// findingSubtasks are the subtasks left out when AiReviewOptions.SkipFindings is set
+var findingSubtasks = map[string]bool{
+	ExtractAiReviewFindingsMeta.Name:    true,
+	MatchSuggestionDiffsMeta.Name:       true,
+	CorrelateFindingsWithBugsMeta.Name:  true,
+	SyncGithubThreadResolutionMeta.Name: true,
+	DetectFindingTrendsMeta.Name:        true,
+}
+
+// predictionSubtasks are the subtasks left out when AiReviewOptions.SkipPredictions is set
+var predictionSubtasks = map[string]bool{
+	FetchMissingCiJobsMeta.Name:          true,
+	CalculateFailurePredictionsMeta.Name: true,
+	ConvertFailurePredictionsMeta.Name:   true,
+	CalculatePredictionMetricsMeta.Name:  true,
+	ConvertPredictionMetricsMeta.Name:    true,
+	CalculateDoraOverlaysMeta.Name:       true,
+}
+
+// projectSubtasks only do something in project mode, they skip themselves when the task has no projectName
+var projectSubtasks = map[string]bool{
+	ConvertAiReviewsMeta.Name:          true,
+	ConvertFailurePredictionsMeta.Name: true,
+	ConvertPredictionMetricsMeta.Name:  true,
+	CalculateDoraOverlaysMeta.Name:     true,
+}
+
+// SplitProjectSubtasks splits names into the subtasks of a single-repo task and the
+// project-only ones, keeping the order
+func SplitProjectSubtasks(names []string) (repoSubtasks []string, projectOnly []string) {
+	for _, name := range names {
+		if projectSubtasks[name] {
+			projectOnly = append(projectOnly, name)
+		} else {
+			repoSubtasks = append(repoSubtasks, name)
+		}
+	}
+	return repoSubtasks, projectOnly
+}
+
+// FilterSubtasks removes the subtasks disabled by the skip options, keeping the order
+func FilterSubtasks(names []string, op *AiReviewOptions) []string {
+	filtered := make([]string, 0, len(names))
+	for _, name := range names {
+		if op != nil && op.SkipFindings && findingSubtasks[name] {
+			continue
+		}
+		if op != nil && op.SkipPredictions && predictionSubtasks[name] {
+			continue
+		}
+		filtered = append(filtered, name)
+	}
+	return filtered
+}
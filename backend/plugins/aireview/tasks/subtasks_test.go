@@ -0,0 +1,81 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilterSubtasks(t *testing.T) {
+	all := []string{
+		ExtractAiReviewsMeta.Name,
+		ExtractAiReviewFindingsMeta.Name,
+		MatchSuggestionDiffsMeta.Name,
+		CorrelateFindingsWithBugsMeta.Name,
+		FetchMissingCiJobsMeta.Name,
+		CalculateFailurePredictionsMeta.Name,
+		CalculatePredictionMetricsMeta.Name,
+		CleanupReviewBodiesMeta.Name,
+	}
+
+	t.Run("no options keeps everything", func(t *testing.T) {
+		assert.Equal(t, all, FilterSubtasks(all, nil))
+		assert.Equal(t, all, FilterSubtasks(all, &AiReviewOptions{}))
+	})
+
+	t.Run("skip findings", func(t *testing.T) {
+		got := FilterSubtasks(all, &AiReviewOptions{SkipFindings: true})
+		assert.Equal(t, []string{
+			ExtractAiReviewsMeta.Name,
+			FetchMissingCiJobsMeta.Name,
+			CalculateFailurePredictionsMeta.Name,
+			CalculatePredictionMetricsMeta.Name,
+			CleanupReviewBodiesMeta.Name,
+		}, got)
+	})
+
+	t.Run("skip findings and predictions", func(t *testing.T) {
+		got := FilterSubtasks(all, &AiReviewOptions{SkipFindings: true, SkipPredictions: true})
+		assert.Equal(t, []string{ExtractAiReviewsMeta.Name, CleanupReviewBodiesMeta.Name}, got)
+	})
+}
+
+func TestDecodeTaskOptions_SkipFlags(t *testing.T) {
+	op, err := DecodeTaskOptions(map[string]any{
+		"repoId":          "github:GithubRepo:1:100",
+		"skipFindings":    true,
+		"skipPredictions": true,
+	})
+	assert.Nil(t, err)
+	assert.True(t, op.SkipFindings)
+	assert.True(t, op.SkipPredictions)
+}
+
+func TestSplitProjectSubtasks(t *testing.T) {
+	repoSubtasks, projectOnly := SplitProjectSubtasks([]string{
+		ExtractAiReviewsMeta.Name,
+		ConvertAiReviewsMeta.Name,
+		CalculateFailurePredictionsMeta.Name,
+		CalculateDoraOverlaysMeta.Name,
+		CleanupReviewBodiesMeta.Name,
+	})
+	assert.Equal(t, []string{ExtractAiReviewsMeta.Name, CalculateFailurePredictionsMeta.Name, CleanupReviewBodiesMeta.Name}, repoSubtasks)
+	assert.Equal(t, []string{ConvertAiReviewsMeta.Name, CalculateDoraOverlaysMeta.Name}, projectOnly)
+}
@@ -0,0 +1,343 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/aireview/models"
+)
+
+var SyncGithubThreadResolutionMeta = plugin.SubTaskMeta{
+	Name:             "syncGithubThreadResolution",
+	EntryPoint:       SyncGithubThreadResolution,
+	EnabledByDefault: true,
+	Description:      "Mark AI findings resolved when their GitHub review thread is resolved",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE_REVIEW},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractAiReviewFindingsMeta},
+}
+
+// githubConn holds the minimal fields needed to call the GitHub GraphQL API.
+// Token uses the encdec serializer so GORM decrypts it automatically.
+type githubConn struct {
+	ID       uint64 `gorm:"primaryKey;column:id"`
+	Endpoint string `gorm:"column:endpoint"`
+	Token    string `gorm:"column:token;serializer:encdec"`
+}
+
+func (githubConn) TableName() string { return "_tool_github_connections" }
+
+// githubReviewCommentInfo locates an AI review comment on GitHub
+type githubReviewCommentInfo struct {
+	CommentGithubId int    `gorm:"column:comment_github_id"`
+	PrNumber        int    `gorm:"column:pr_number"`
+	RepoFullName    string `gorm:"column:repo_full_name"`
+}
+
+// githubThreadState is the resolution state of the review thread holding a comment
+type githubThreadState struct {
+	Resolved   bool
+	ResolvedBy string
+}
+
+// reviewThreadsQuery lists the review threads of a PR with the database IDs of their comments
+const reviewThreadsQuery = `query($owner: String!, $name: String!, $number: Int!, $after: String) {
+  repository(owner: $owner, name: $name) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $after) {
+        nodes {
+          isResolved
+          resolvedBy { login }
+          comments(first: 50) { nodes { databaseId } }
+        }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// SyncGithubThreadResolution refreshes the resolution of findings from the state of the
+// GitHub review thread their AI comment belongs to. Developers often resolve a thread
+// without pushing a change, which the diff-based matching cannot see.
+//
+// The github plugin does not collect thread state, so it is read from the GraphQL API
+// with the token of the github connection that collected the comment. Findings already
+// resolved for another reason (fixed, wont_fix, ...) are left alone, and a thread that
+// was reopened clears the thread_resolved resolution again. GitHub does not expose when
+// a thread was resolved, so resolved_at is not set.
+func SyncGithubThreadResolution(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*AiReviewTaskData)
+
+	// Step 1: GitHub reviews that produced findings, with their domain comment IDs
+	hasFindings := "EXISTS (SELECT 1 FROM _tool_aireview_findings f WHERE f.ai_review_id = ar.id)"
+	var reviewClauses []dal.Clause
+	if data.Options.ProjectName != "" {
+		reviewClauses = []dal.Clause{
+			dal.Select("ar.id as review_id, ar.review_id as domain_comment_id"),
+			dal.From("_tool_aireview_reviews ar"),
+			dal.Join("JOIN project_mapping pm ON ar.repo_id = pm.row_id"),
+			dal.Where("ar.source_platform = ? AND pm.project_name = ? AND pm.`table` = ? AND "+hasFindings, "github", data.Options.ProjectName, "repos"),
+		}
+		reviewClauses = append(reviewClauses, excludedRepoClauses("ar.repo_id", data.Options.ExcludeRepoIds)...)
+	} else {
+		reviewClauses = []dal.Clause{
+			dal.Select("ar.id as review_id, ar.review_id as domain_comment_id"),
+			dal.From("_tool_aireview_reviews ar"),
+			dal.Where("ar.source_platform = ? AND ar.repo_id = ? AND "+hasFindings, "github", data.Options.RepoId),
+		}
+	}
+
+	var reviews []struct {
+		ReviewId        string `gorm:"column:review_id"`
+		DomainCommentId string `gorm:"column:domain_comment_id"`
+	}
+	if err := db.All(&reviews, reviewClauses...); err != nil {
+		return errors.Default.Wrap(err, "failed to query GitHub reviews with findings")
+	}
+
+	// Domain comment IDs look like "github:GithubPrComment:CONNECTION_ID:GITHUB_ID"; the plugin
+	// name prefix differs on deployments matched through the scope config's sourcePlatforms
+	commentToReviewId := make(map[uint64]map[int]string)
+	for _, review := range reviews {
+		if !strings.Contains(review.DomainCommentId, ":GithubPrComment:") {
+			continue
+		}
+		connId, githubId, parseErr := parseDomainCommentId(review.DomainCommentId)
+		if parseErr != nil {
+			logger.Warn(nil, "skipping review with unparseable domain ID: %s", review.DomainCommentId)
+			continue
+		}
+		if commentToReviewId[connId] == nil {
+			commentToReviewId[connId] = make(map[int]string)
+		}
+		commentToReviewId[connId][githubId] = review.ReviewId
+	}
+
+	if len(commentToReviewId) == 0 {
+		logger.Info("No GitHub AI review comments with findings, skipping thread resolution sync")
+		return nil
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+	resolved, reopened := 0, 0
+
+	// Step 2: per connection, find the PR of each inline review comment
+	for connId, comments := range commentToReviewId {
+		var conn githubConn
+		if err := db.First(&conn, dal.Where("id = ?", connId)); err != nil {
+			logger.Warn(err, "failed to load GitHub connection %d, skipping %d reviews", connId, len(comments))
+			continue
+		}
+		token := firstGithubToken(conn.Token)
+		graphqlUrl := githubGraphqlUrl(conn.Endpoint)
+
+		githubIds := make([]int, 0, len(comments))
+		for githubId := range comments {
+			githubIds = append(githubIds, githubId)
+		}
+
+		// Only inline (DIFF) review comments live in review threads
+		var infos []githubReviewCommentInfo
+		err := db.All(&infos,
+			dal.Select("gpc.github_id as comment_github_id, gpr.number as pr_number, gr.full_name as repo_full_name"),
+			dal.From("_tool_github_pull_request_comments gpc"),
+			dal.Join("JOIN _tool_github_pull_requests gpr ON gpr.connection_id = gpc.connection_id AND gpr.github_id = gpc.pull_request_id"),
+			dal.Join("JOIN _tool_github_repos gr ON gr.connection_id = gpr.connection_id AND gr.github_id = gpr.repo_id"),
+			dal.Where("gpc.connection_id = ? AND gpc.github_id IN (?) AND gpc.type = ?", connId, githubIds, "DIFF"),
+		)
+		if err != nil {
+			logger.Warn(err, "failed to query GitHub review comment details for connection %d, skipping", connId)
+			continue
+		}
+
+		prComments := make(map[string][]githubReviewCommentInfo)
+		for _, info := range infos {
+			key := fmt.Sprintf("%s#%d", info.RepoFullName, info.PrNumber)
+			prComments[key] = append(prComments[key], info)
+		}
+
+		// Step 3: one GraphQL lookup per PR, then update the findings of each comment
+		for _, infos := range prComments {
+			owner, name, ok := strings.Cut(infos[0].RepoFullName, "/")
+			if !ok {
+				continue
+			}
+			threads, fetchErr := fetchReviewThreadStates(taskCtx.GetContext(), httpClient, graphqlUrl, token, owner, name, infos[0].PrNumber)
+			if fetchErr != nil {
+				logger.Warn(nil, "failed to fetch review threads of %s#%d: %s", infos[0].RepoFullName, infos[0].PrNumber, fetchErr)
+				continue
+			}
+
+			for _, info := range infos {
+				state, found := threads[info.CommentGithubId]
+				if !found {
+					continue
+				}
+				reviewId := comments[info.CommentGithubId]
+				if state.Resolved {
+					err = db.Exec(
+						"UPDATE _tool_aireview_findings SET is_resolved = ?, resolved_by = ?, resolution = ?"+
+							" WHERE ai_review_id = ? AND (resolution IS NULL OR resolution = '' OR resolution = ?)",
+						true, state.ResolvedBy, models.ResolutionThreadResolved, reviewId, models.ResolutionThreadResolved,
+					)
+					resolved++
+				} else {
+					err = db.Exec(
+						"UPDATE _tool_aireview_findings SET is_resolved = ?, resolved_by = '', resolution = ''"+
+							" WHERE ai_review_id = ? AND resolution = ?",
+						false, reviewId, models.ResolutionThreadResolved,
+					)
+					reopened++
+				}
+				if err != nil {
+					return errors.Default.Wrap(err, "failed to update finding resolution")
+				}
+			}
+		}
+	}
+
+	logger.Info("GitHub thread resolution sync complete: %d resolved threads, %d open threads", resolved, reopened)
+	return nil
+}
+
+// firstGithubToken returns the first token of a github connection, which may hold a
+// comma-separated list of tokens
+func firstGithubToken(tokens string) string {
+	for _, token := range strings.Split(tokens, ",") {
+		if token = strings.TrimSpace(token); token != "" {
+			return token
+		}
+	}
+	return ""
+}
+
+// githubGraphqlUrl derives the GraphQL endpoint from the REST endpoint of a github connection:
+// https://api.github.com/ for github.com, https://host/api/v3/ for GitHub Enterprise Server.
+func githubGraphqlUrl(endpoint string) string {
+	endpoint = strings.TrimRight(endpoint, "/")
+	if endpoint == "" {
+		return "https://api.github.com/graphql"
+	}
+	if strings.HasSuffix(endpoint, "/api/v3") {
+		return strings.TrimSuffix(endpoint, "/v3") + "/graphql"
+	}
+	return endpoint + "/graphql"
+}
+
+// reviewThreadsResponse is the part of the GraphQL response used by fetchReviewThreadStates
+type reviewThreadsResponse struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						IsResolved bool `json:"isResolved"`
+						ResolvedBy *struct {
+							Login string `json:"login"`
+						} `json:"resolvedBy"`
+						Comments struct {
+							Nodes []struct {
+								DatabaseId int `json:"databaseId"`
+							} `json:"nodes"`
+						} `json:"comments"`
+					} `json:"nodes"`
+					PageInfo struct {
+						HasNextPage bool   `json:"hasNextPage"`
+						EndCursor   string `json:"endCursor"`
+					} `json:"pageInfo"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// fetchReviewThreadStates returns the thread state of every review comment of a PR,
+// keyed by the comment's database ID (the github_id of _tool_github_pull_request_comments).
+func fetchReviewThreadStates(ctx context.Context, client *http.Client, graphqlUrl, token, owner, name string, number int) (map[int]githubThreadState, error) {
+	states := make(map[int]githubThreadState)
+	var after *string
+	for {
+		payload, err := json.Marshal(map[string]interface{}{
+			"query": reviewThreadsQuery,
+			"variables": map[string]interface{}{
+				"owner": owner, "name": name, "number": number, "after": after,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, "POST", graphqlUrl, bytes.NewReader(payload))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+			resp.Body.Close()
+			return nil, fmt.Errorf("GitHub GraphQL API returned %d: %s", resp.StatusCode, string(body))
+		}
+
+		var result reviewThreadsResponse
+		err = json.NewDecoder(resp.Body).Decode(&result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode review threads response: %w", err)
+		}
+		if len(result.Errors) > 0 {
+			return nil, fmt.Errorf("GitHub GraphQL API error: %s", result.Errors[0].Message)
+		}
+
+		threads := result.Data.Repository.PullRequest.ReviewThreads
+		for _, thread := range threads.Nodes {
+			state := githubThreadState{Resolved: thread.IsResolved}
+			if thread.ResolvedBy != nil {
+				state.ResolvedBy = thread.ResolvedBy.Login
+			}
+			for _, comment := range thread.Comments.Nodes {
+				states[comment.DatabaseId] = state
+			}
+		}
+		if !threads.PageInfo.HasNextPage {
+			return states, nil
+		}
+		cursor := threads.PageInfo.EndCursor
+		after = &cursor
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGithubGraphqlUrl(t *testing.T) {
+	assert.Equal(t, "https://api.github.com/graphql", githubGraphqlUrl(""))
+	assert.Equal(t, "https://api.github.com/graphql", githubGraphqlUrl("https://api.github.com/"))
+	assert.Equal(t, "https://ghe.example.com/api/graphql", githubGraphqlUrl("https://ghe.example.com/api/v3/"))
+}
+
+func TestFirstGithubToken(t *testing.T) {
+	assert.Equal(t, "ghp_a", firstGithubToken("ghp_a"))
+	assert.Equal(t, "ghp_a", firstGithubToken(" ghp_a , ghp_b"))
+	assert.Equal(t, "ghp_b", firstGithubToken(",ghp_b"))
+	assert.Equal(t, "", firstGithubToken(""))
+}
+
+func TestFetchReviewThreadStates(t *testing.T) {
+	pages := []string{
+		`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"nodes":[
+				{"isResolved":true,"resolvedBy":{"login":"dev1"},"comments":{"nodes":[{"databaseId":11},{"databaseId":12}]}},
+				{"isResolved":false,"resolvedBy":null,"comments":{"nodes":[{"databaseId":21}]}}
+			],
+			"pageInfo":{"hasNextPage":true,"endCursor":"c1"}}}}}}`,
+		`{"data":{"repository":{"pullRequest":{"reviewThreads":{
+			"nodes":[{"isResolved":true,"resolvedBy":null,"comments":{"nodes":[{"databaseId":31}]}}],
+			"pageInfo":{"hasNextPage":false,"endCursor":"c2"}}}}}}`,
+	}
+	var afters []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer ghp_test", r.Header.Get("Authorization"))
+		var body struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "konflux-ci", body.Variables["owner"])
+		assert.Equal(t, float64(42), body.Variables["number"])
+		afters = append(afters, body.Variables["after"])
+		_, _ = w.Write([]byte(pages[len(afters)-1]))
+	}))
+	defer server.Close()
+
+	states, err := fetchReviewThreadStates(context.Background(), server.Client(), server.URL, "ghp_test", "konflux-ci", "build-service", 42)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{nil, "c1"}, afters)
+	assert.Equal(t, map[int]githubThreadState{
+		11: {Resolved: true, ResolvedBy: "dev1"},
+		12: {Resolved: true, ResolvedBy: "dev1"},
+		21: {Resolved: false},
+		31: {Resolved: true},
+	}, states)
+}
+
+func TestFetchReviewThreadStates_Errors(t *testing.T) {
+	t.Run("http error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+		}))
+		defer server.Close()
+		_, err := fetchReviewThreadStates(context.Background(), server.Client(), server.URL, "bad", "o", "r", 1)
+		assert.ErrorContains(t, err, "401")
+	})
+
+	t.Run("graphql error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"errors":[{"message":"Could not resolve to a Repository"}]}`))
+		}))
+		defer server.Close()
+		_, err := fetchReviewThreadStates(context.Background(), server.Client(), server.URL, "t", "o", "r", 1)
+		assert.ErrorContains(t, err, "Could not resolve to a Repository")
+	})
+}
@@ -18,8 +18,11 @@ limitations under the License.
 package tasks
 
 import (
+	"fmt"
 	"regexp"
+	"strings"
 
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/helpers/gcshelper"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
@@ -34,6 +37,9 @@ type AiReviewOptions struct {
 	// Repository to analyze (domain layer ID) - optional if projectName is provided
 	RepoId string `json:"repoId"`
 
+	// Repos left out in project mode, because they are analyzed by a task of their own
+	ExcludeRepoIds []string `json:"excludeRepoIds"`
+
 	// Scope config ID reference
 	ScopeConfigId uint64 `json:"scopeConfigId"`
 
@@ -45,6 +51,12 @@ type AiReviewOptions struct {
 
 	// Time filter
 	TimeAfter string `json:"timeAfter"`
+
+	// Subtask switches for pipelines that only need review counts.
+	// SkipFindings drops finding extraction and the subtasks built on findings,
+	// SkipPredictions drops CI backfill and failure prediction/metrics subtasks.
+	SkipFindings    bool `json:"skipFindings"`
+	SkipPredictions bool `json:"skipPredictions"`
 }
 
 // AiReviewTaskData contains shared data for subtasks
@@ -65,12 +77,25 @@ type AiReviewTaskData struct {
 	QodoPatternRegex          *regexp.Regexp
 	GeminiUsernameRegex       *regexp.Regexp
 	GeminiPatternRegex        *regexp.Regexp
+	CopilotUsernameRegex      *regexp.Regexp
+	CopilotPatternRegex       *regexp.Regexp
 	AiCommitPatternsRegex     []*regexp.Regexp
 	AiPrLabelPatternRegex     *regexp.Regexp
+	AiPrDescriptionRegex      *regexp.Regexp
 	RiskHighPatternRegex      *regexp.Regexp
 	RiskMediumPatternRegex    *regexp.Regexp
 	RiskLowPatternRegex       *regexp.Regexp
 	BugLinkPatternRegex       *regexp.Regexp
+	BotUsernamePatternRegex   *regexp.Regexp
+
+	// SourcePlatformRules are the scope config's sourcePlatforms, see resolveSourcePlatform
+	SourcePlatformRules []models.SourcePlatformRule
+
+	// RiskTermRules are the scope config's riskTermMappings, see normalizeFindingRisk
+	RiskTermRules []riskTermRule
+
+	// PreserveHtmlTools are the AI tools whose summaries are extracted from the raw HTML body
+	PreserveHtmlTools map[string]bool
 }
 
 // DecodeTaskOptions decodes and validates task options
@@ -90,6 +115,15 @@ func ValidateTaskOptions(op *AiReviewOptions) errors.Error {
 	return nil
 }
 
+// excludedRepoClauses leaves the options' ExcludeRepoIds out of a project mode query,
+// column being the repo id column of the query
+func excludedRepoClauses(column string, excludeRepoIds []string) []dal.Clause {
+	if len(excludeRepoIds) == 0 {
+		return nil
+	}
+	return []dal.Clause{dal.Where(column+" NOT IN ?", excludeRepoIds)}
+}
+
 // CompilePatterns compiles all regex patterns from scope config
 func CompilePatterns(taskData *AiReviewTaskData) errors.Error {
 	config := taskData.Options.ScopeConfig
@@ -156,6 +190,20 @@ func CompilePatterns(taskData *AiReviewTaskData) errors.Error {
 		}
 	}
 
+	// GitHub Copilot patterns
+	if config.CopilotEnabled && config.CopilotUsername != "" {
+		taskData.CopilotUsernameRegex, err = regexp.Compile("(?i)" + regexp.QuoteMeta(config.CopilotUsername))
+		if err != nil {
+			return errors.BadInput.Wrap(err, "invalid copilotUsername pattern")
+		}
+	}
+	if config.CopilotEnabled && config.CopilotPattern != "" {
+		taskData.CopilotPatternRegex, err = regexp.Compile(config.CopilotPattern)
+		if err != nil {
+			return errors.BadInput.Wrap(err, "invalid copilotPattern")
+		}
+	}
+
 	// Risk patterns
 	if config.RiskHighPattern != "" {
 		taskData.RiskHighPatternRegex, err = regexp.Compile(config.RiskHighPattern)
@@ -184,6 +232,14 @@ func CompilePatterns(taskData *AiReviewTaskData) errors.Error {
 		}
 	}
 
+	// Bot username pattern
+	if config.BotUsernamePattern != "" {
+		taskData.BotUsernamePatternRegex, err = regexp.Compile(config.BotUsernamePattern)
+		if err != nil {
+			return errors.BadInput.Wrap(err, "invalid botUsernamePattern")
+		}
+	}
+
 	// AI PR label pattern
 	if config.AiPrLabelPattern != "" {
 		taskData.AiPrLabelPatternRegex, err = regexp.Compile(config.AiPrLabelPattern)
@@ -192,5 +248,45 @@ func CompilePatterns(taskData *AiReviewTaskData) errors.Error {
 		}
 	}
 
+	// AI PR description pattern
+	if config.AiPrDescriptionPattern != "" {
+		taskData.AiPrDescriptionRegex, err = regexp.Compile(config.AiPrDescriptionPattern)
+		if err != nil {
+			return errors.BadInput.Wrap(err, "invalid aiPrDescriptionPattern")
+		}
+	}
+
+	// Source platform rules
+	taskData.SourcePlatformRules = nil
+	for i, rule := range config.SourcePlatforms {
+		if rule.Prefix == "" || rule.Platform == "" {
+			return errors.BadInput.New(fmt.Sprintf("sourcePlatforms[%d] needs a prefix and a platform", i))
+		}
+		if rule.CommentUrlTemplate != "" && !strings.Contains(rule.CommentUrlTemplate, "{commentId}") {
+			return errors.BadInput.New(fmt.Sprintf("sourcePlatforms[%d] commentUrlTemplate must contain {commentId}", i))
+		}
+		taskData.SourcePlatformRules = append(taskData.SourcePlatformRules, rule)
+	}
+
+	// Risk term mappings
+	riskTermRules, riskTermErr := compileRiskTermRules(config.RiskTermMappings)
+	if riskTermErr != nil {
+		return riskTermErr
+	}
+	taskData.RiskTermRules = riskTermRules
+
+	// Tools whose bodies skip the HTML to markdown conversion
+	taskData.PreserveHtmlTools = nil
+	for _, tool := range strings.Split(config.PreserveHtmlTools, ",") {
+		tool = strings.TrimSpace(tool)
+		if tool == "" {
+			continue
+		}
+		if taskData.PreserveHtmlTools == nil {
+			taskData.PreserveHtmlTools = make(map[string]bool)
+		}
+		taskData.PreserveHtmlTools[tool] = true
+	}
+
 	return nil
 }
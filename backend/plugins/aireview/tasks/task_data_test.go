@@ -216,6 +216,19 @@ func TestCompilePatterns_InvalidGeminiPattern(t *testing.T) {
 	assert.Contains(t, err.Error(), "geminiPattern")
 }
 
+func TestCompilePatterns_InvalidCopilotPattern(t *testing.T) {
+	config := models.GetDefaultScopeConfig()
+	config.CopilotPattern = "[invalid"
+	taskData := &AiReviewTaskData{
+		Options: &AiReviewOptions{
+			ScopeConfig: config,
+		},
+	}
+	err := CompilePatterns(taskData)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "copilotPattern")
+}
+
 func TestCompilePatterns_InvalidRiskMediumPattern(t *testing.T) {
 	config := models.GetDefaultScopeConfig()
 	config.RiskMediumPattern = "[invalid"
@@ -254,3 +267,16 @@ func TestCompilePatterns_InvalidAiPrLabelPattern(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Contains(t, err.Error(), "aiPrLabelPattern")
 }
+
+func TestCompilePatterns_PreserveHtmlTools(t *testing.T) {
+	config := models.GetDefaultScopeConfig()
+	config.PreserveHtmlTools = " qodo, gemini,,"
+	taskData := &AiReviewTaskData{
+		Options: &AiReviewOptions{
+			ScopeConfig: config,
+		},
+	}
+	err := CompilePatterns(taskData)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{models.AiToolQodo: true, models.AiToolGemini: true}, taskData.PreserveHtmlTools)
+}
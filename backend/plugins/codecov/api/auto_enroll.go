@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	gocontext "context"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/apache/incubator-devlake/plugins/codecov/tasks"
+)
+
+// autoEnrollScopes adds the organization's repos matching the connection's
+// autoEnrollRegex to the blueprint scopes, creating their scope records first.
+// Only the blueprint of the connection's autoEnrollProjectName is enrolled into,
+// other blueprints using the connection keep their scopes. Existing scope
+// records are left untouched, so a scope config picked by hand for an enrolled
+// repo survives later runs.
+func autoEnrollScopes(connection *models.CodecovConnection, projectName string, bpScopes []*coreModels.BlueprintScope) ([]*coreModels.BlueprintScope, errors.Error) {
+	if !connection.AutoEnrollsInto(projectName) {
+		return bpScopes, nil
+	}
+	pattern, err := connection.AutoEnrollPattern()
+	if err != nil || pattern == nil {
+		return bpScopes, err
+	}
+
+	apiClient, err := api.NewApiClientFromConnection(gocontext.TODO(), basicRes, connection)
+	if err != nil {
+		return bpScopes, err
+	}
+	tasks.NewTokenRotator(&connection.CodecovConn, basicRes.GetLogger()).Install(apiClient)
+
+	repos, err := listAllCodecovRepos(apiClient, connection.ApiService(), connection.Organization)
+	if err != nil {
+		return bpScopes, err
+	}
+
+	inBlueprint := make(map[string]bool, len(bpScopes))
+	for _, bpScope := range bpScopes {
+		inBlueprint[bpScope.ScopeId] = true
+	}
+	for _, repo := range matchAutoEnrollRepos(pattern, connection.Organization, repos) {
+		if inBlueprint[repo.CodecovId] {
+			continue
+		}
+		if _, findErr := dsHelper.ScopeSrv.FindByPk(connection.ID, repo.CodecovId); findErr != nil {
+			if findErr.GetType() != errors.NotFound {
+				return bpScopes, findErr
+			}
+			repo.ConnectionId = connection.ID
+			repo.ScopeConfigId = connection.AutoEnrollScopeConfigId
+			if createErr := dsHelper.ScopeSrv.Create(repo); createErr != nil {
+				return bpScopes, createErr
+			}
+			basicRes.GetLogger().Info("auto-enrolled Codecov repo %s as a scope of connection %d", repo.FullName, connection.ID)
+		}
+		bpScopes = append(bpScopes, &coreModels.BlueprintScope{ScopeId: repo.CodecovId})
+		inBlueprint[repo.CodecovId] = true
+	}
+	return bpScopes, nil
+}
+
+// listAllCodecovRepos lists every repository of the owner, following pagination
+func listAllCodecovRepos(apiClient plugin.ApiClient, service, owner string) ([]codecovRepo, errors.Error) {
+	var repos []codecovRepo
+	for page := 1; ; page++ {
+		query := url.Values{
+			"page":      []string{fmt.Sprintf("%v", page)},
+			"page_size": []string{"100"},
+		}
+		reposResponse, err := getCodecovRepos(apiClient, fmt.Sprintf("/api/v2/%s/%s/repos/", service, owner), query, owner)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, reposResponse.Results...)
+		if reposResponse.Next == nil || *reposResponse.Next == "" {
+			return repos, nil
+		}
+	}
+}
+
+// matchAutoEnrollRepos keeps the active repos (coverage uploads activated on Codecov)
+// whose name matches the pattern, converted to scope records
+func matchAutoEnrollRepos(pattern *regexp.Regexp, owner string, repos []codecovRepo) []*models.CodecovRepo {
+	var matched []*models.CodecovRepo
+	for _, repo := range repos {
+		entry := toCodecovRepoEntry(owner, nil, repo)
+		if entry == nil || !entry.Data.Active || !pattern.MatchString(entry.Name) {
+			continue
+		}
+		matched = append(matched, entry.Data)
+	}
+	return matched
+}
@@ -0,0 +1,124 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAutoEnrollPattern(t *testing.T) {
+	conn := newTestConnection()
+	pattern, err := conn.AutoEnrollPattern()
+	assert.Nil(t, err)
+	assert.Nil(t, pattern)
+
+	conn.AutoEnrollRegex = "-service$"
+	pattern, err = conn.AutoEnrollPattern()
+	assert.Nil(t, err)
+	assert.True(t, pattern.MatchString("build-service"))
+
+	conn.AutoEnrollRegex = "("
+	_, err = conn.AutoEnrollPattern()
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.BadInput, err.GetType())
+}
+
+func TestAutoEnrollsInto(t *testing.T) {
+	conn := newTestConnection()
+	assert.False(t, conn.AutoEnrollsInto(""))
+	assert.False(t, conn.AutoEnrollsInto("konflux"))
+
+	conn.AutoEnrollProjectName = "konflux"
+	assert.True(t, conn.AutoEnrollsInto("konflux"))
+	assert.False(t, conn.AutoEnrollsInto("other"))
+	assert.False(t, conn.AutoEnrollsInto(""))
+}
+
+func TestAutoEnrollScopesOtherProject(t *testing.T) {
+	conn := newTestConnection()
+	conn.AutoEnrollRegex = "-service$"
+	conn.AutoEnrollProjectName = "konflux"
+	bpScopes := []*coreModels.BlueprintScope{{ScopeId: "konflux-ci/docs"}}
+
+	// Codecov is not called for the blueprint of another project
+	scopes, err := autoEnrollScopes(conn, "other", bpScopes)
+	assert.Nil(t, err)
+	assert.Equal(t, bpScopes, scopes)
+}
+
+func TestMergeKeepsAutoEnrollFields(t *testing.T) {
+	existed := newTestConnection()
+	existed.AutoEnrollRegex = "^old"
+	modified := newTestConnection()
+	modified.AutoEnrollRegex = "-service$"
+	modified.AutoEnrollProjectName = "konflux"
+	modified.AutoEnrollScopeConfigId = 3
+	modified.Service = "github_enterprise"
+
+	assert.Nil(t, existed.Merge(existed, modified, nil))
+	assert.Equal(t, "-service$", existed.AutoEnrollRegex)
+	assert.Equal(t, "konflux", existed.AutoEnrollProjectName)
+	assert.Equal(t, uint64(3), existed.AutoEnrollScopeConfigId)
+	assert.Equal(t, "github_enterprise", existed.Service)
+}
+
+func TestListAllCodecovRepos(t *testing.T) {
+	apiClient := new(mockplugin.ApiClient)
+	apiClient.On("Get", "/api/v2/github/konflux-ci/repos/", mock.Anything, mock.Anything).Return(
+		func(_ string, query url.Values, _ http.Header) (*http.Response, errors.Error) {
+			if query.Get("page") == "1" {
+				return jsonResponse(http.StatusOK, `{"results":[{"name":"build-service","active":true}],"next":"page2"}`), nil
+			}
+			return jsonResponse(http.StatusOK, `{"results":[{"name":"docs","active":true}],"next":null}`), nil
+		})
+
+	repos, err := listAllCodecovRepos(apiClient, models.DefaultService, "konflux-ci")
+	assert.Nil(t, err)
+	if assert.Len(t, repos, 2) {
+		assert.Equal(t, "build-service", repos[0].Name)
+		assert.Equal(t, "docs", repos[1].Name)
+	}
+	apiClient.AssertNumberOfCalls(t, "Get", 2)
+}
+
+func TestMatchAutoEnrollRepos(t *testing.T) {
+	repos := []codecovRepo{
+		{Name: "build-service", Active: true, Branch: "develop"},
+		{Name: "release-service", Active: false},
+		{Name: "docs", Active: true},
+		{Name: "", Active: true},
+	}
+	repos = append(repos, codecovRepo{Active: true})
+	repos[len(repos)-1].Repository.Name = "integration-service"
+
+	matched := matchAutoEnrollRepos(regexp.MustCompile(`-service$`), "konflux-ci", repos)
+	assert.Equal(t, []*models.CodecovRepo{
+		{CodecovId: "konflux-ci/build-service", Name: "build-service", FullName: "konflux-ci/build-service", Active: true, Branch: "develop"},
+		{CodecovId: "konflux-ci/integration-service", Name: "integration-service", FullName: "konflux-ci/integration-service", Active: true, Branch: "main"},
+	}, matched)
+}
@@ -27,9 +27,11 @@ import (
 	"github.com/apache/incubator-devlake/plugins/codecov/tasks"
 )
 
-// MakeDataSourcePipelinePlanV200 creates a pipeline plan for Codecov
+// MakeDataSourcePipelinePlanV200 creates a pipeline plan for Codecov. projectName is the
+// project of the blueprint being planned, empty when unknown
 func MakeDataSourcePipelinePlanV200(
 	subtaskMetas []plugin.SubTaskMeta,
+	projectName string,
 	connectionId uint64,
 	bpScopes []*coreModels.BlueprintScope,
 ) (coreModels.PipelinePlan, []plugin.Scope, errors.Error) {
@@ -38,6 +40,13 @@ func MakeDataSourcePipelinePlanV200(
 	if err != nil {
 		return nil, nil, err
 	}
+	// enroll new repos matching autoEnrollRegex into the blueprint of autoEnrollProjectName; Codecov
+	// being unreachable must not block collecting the scopes already enrolled
+	if enrolledScopes, enrollErr := autoEnrollScopes(connection, projectName, bpScopes); enrollErr != nil {
+		basicRes.GetLogger().Warn(enrollErr, "failed to auto-enroll Codecov repos of connection %d", connectionId)
+	} else {
+		bpScopes = enrolledScopes
+	}
 	scopeDetails, err := dsHelper.ScopeSrv.MapScopeDetails(connectionId, bpScopes)
 	if err != nil {
 		// Provide a more helpful error message if scope is not found
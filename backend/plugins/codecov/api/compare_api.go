@@ -0,0 +1,171 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+// BranchCoverage is the coverage of the latest collected commit of a branch
+type BranchCoverage struct {
+	Branch string `json:"branch"`
+	CoverageSnapshot
+}
+
+// FlagCoverageComparison is the coverage of one flag on both branches; a side is nil
+// when the flag has no coverage at the latest commit of that branch
+type FlagCoverageComparison struct {
+	FlagName string   `json:"flagName"`
+	Base     *float64 `json:"base"`
+	Head     *float64 `json:"head"`
+	Delta    *float64 `json:"delta"` // head minus base, nil unless both sides have coverage
+}
+
+// BranchCoverageComparison compares the latest collected coverage of two branches of a repo
+type BranchCoverageComparison struct {
+	ConnectionId uint64                   `json:"connectionId"`
+	RepoId       string                   `json:"repoId"`
+	Base         BranchCoverage           `json:"base"`
+	Head         BranchCoverage           `json:"head"`
+	Delta        float64                  `json:"delta"` // overall head minus base coverage
+	Flags        []FlagCoverageComparison `json:"flags"`
+}
+
+// GetRepoCompare compare the coverage of two branches of a Codecov repo
+// @Summary compare the coverage of two branches of a Codecov repo
+// @Description Overall and per-flag coverage of the latest collected commit of the head branch against the base branch, e.g. before a release cut. Computed from the collected coverage, without calling the Codecov API
+// @Tags plugins/codecov
+// @Param scopeId path string true "scope ID, e.g. owner/repo"
+// @Param base query string true "base branch, e.g. release-1.4"
+// @Param head query string true "head branch, e.g. main"
+// @Param connectionId query int false "connection ID, required when the repo is added to several connections"
+// @Success 200  {object} BranchCoverageComparison
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/codecov/repos/{scopeId}/compare [GET]
+func GetRepoCompare(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	scopeId, ok := parseRepoScopeId(input.Params["scopeId"], comparePathSuffix)
+	if !ok {
+		return nil, errors.NotFound.New("unknown repo resource, expected repos/{scopeId}/compare")
+	}
+	base, head := input.Query.Get("base"), input.Query.Get("head")
+	if base == "" || head == "" {
+		return nil, errors.BadInput.New("base and head branches are required")
+	}
+
+	db := basicRes.GetDal()
+	repo, err := findRepo(db, scopeId, input.Query)
+	if err != nil {
+		return nil, err
+	}
+	comparison, err := buildBranchComparison(db, repo, base, head)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: comparison, Status: http.StatusOK}, nil
+}
+
+// buildBranchComparison loads the latest commit coverage of each branch and the flag
+// coverages of those commits. A branch without collected coverage is not found.
+func buildBranchComparison(db dal.Dal, repo *models.CodecovRepo, base, head string) (*BranchCoverageComparison, errors.Error) {
+	repoId := repo.FullName
+	if repoId == "" {
+		repoId = repo.CodecovId
+	}
+
+	var latest [2]*models.CodecovCommitCoverage
+	var flags [2][]models.CodecovCoverage
+	for i, branch := range []string{base, head} {
+		coverage, err := findCommitCoverage(db, repo.ConnectionId, repoId, branch, nil)
+		if err != nil {
+			return nil, err
+		}
+		if coverage == nil {
+			return nil, errors.NotFound.New("no coverage collected for branch " + branch + " of repo " + repoId)
+		}
+		latest[i] = coverage
+		err = db.All(&flags[i],
+			dal.Where("connection_id = ? AND repo_id = ? AND branch = ? AND commit_sha = ? AND flag_name <> ''",
+				repo.ConnectionId, repoId, branch, coverage.CommitSha),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &BranchCoverageComparison{
+		ConnectionId: repo.ConnectionId,
+		RepoId:       repoId,
+		Base:         branchCoverage(latest[0]),
+		Head:         branchCoverage(latest[1]),
+		Delta:        latest[1].OverallCoverage - latest[0].OverallCoverage,
+		Flags:        compareFlagCoverages(flags[0], flags[1]),
+	}, nil
+}
+
+// branchCoverage is the overall coverage snapshot of a commit coverage
+func branchCoverage(coverage *models.CodecovCommitCoverage) BranchCoverage {
+	return BranchCoverage{
+		Branch: coverage.Branch,
+		CoverageSnapshot: CoverageSnapshot{
+			CommitSha:       coverage.CommitSha,
+			CommitTimestamp: coverage.CommitTimestamp,
+			Coverage:        coverage.OverallCoverage,
+		},
+	}
+}
+
+// compareFlagCoverages pairs the flag coverages of the base and head commits by flag name,
+// sorted by flag name; a flag on one side only keeps a nil coverage and delta on the other
+func compareFlagCoverages(base, head []models.CodecovCoverage) []FlagCoverageComparison {
+	byFlag := make(map[string]*FlagCoverageComparison)
+	get := func(flagName string) *FlagCoverageComparison {
+		flag, ok := byFlag[flagName]
+		if !ok {
+			flag = &FlagCoverageComparison{FlagName: flagName}
+			byFlag[flagName] = flag
+		}
+		return flag
+	}
+	for _, row := range base {
+		coverage := row.CoveragePercentage
+		get(row.FlagName).Base = &coverage
+	}
+	for _, row := range head {
+		coverage := row.CoveragePercentage
+		get(row.FlagName).Head = &coverage
+	}
+
+	flags := make([]FlagCoverageComparison, 0, len(byFlag))
+	for _, flag := range byFlag {
+		if flag.Base != nil && flag.Head != nil {
+			delta := *flag.Head - *flag.Base
+			flag.Delta = &delta
+		}
+		flags = append(flags, *flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].FlagName < flags[j].FlagName })
+	return flags
+}
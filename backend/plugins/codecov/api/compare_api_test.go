@@ -0,0 +1,111 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCompareFlagCoverages(t *testing.T) {
+	flags := compareFlagCoverages(
+		[]models.CodecovCoverage{
+			{FlagName: "unit", CoveragePercentage: 70},
+			{FlagName: "e2e", CoveragePercentage: 40},
+		},
+		[]models.CodecovCoverage{
+			{FlagName: "unit", CoveragePercentage: 72.5},
+			{FlagName: "integration", CoveragePercentage: 55},
+		},
+	)
+
+	assert.Len(t, flags, 3)
+	assert.Equal(t, "e2e", flags[0].FlagName)
+	assert.Equal(t, 40.0, *flags[0].Base)
+	assert.Nil(t, flags[0].Head)
+	assert.Nil(t, flags[0].Delta)
+
+	assert.Equal(t, "integration", flags[1].FlagName)
+	assert.Nil(t, flags[1].Base)
+	assert.Equal(t, 55.0, *flags[1].Head)
+
+	assert.Equal(t, "unit", flags[2].FlagName)
+	if assert.NotNil(t, flags[2].Delta) {
+		assert.InDelta(t, 2.5, *flags[2].Delta, 0.0001)
+	}
+}
+
+func TestBuildBranchComparison(t *testing.T) {
+	baseTs := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	headTs := time.Date(2026, 5, 31, 0, 0, 0, 0, time.UTC)
+	fillCoverage := func(coverage models.CodecovCommitCoverage) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(0).(*models.CodecovCommitCoverage) = coverage
+		}
+	}
+	fillFlags := func(rows ...models.CodecovCoverage) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCoverage) = rows
+		}
+	}
+
+	db := new(mockdal.Dal)
+	db.On("First", mock.Anything, mock.Anything).Run(fillCoverage(models.CodecovCommitCoverage{
+		Branch: "release-1.4", CommitSha: "rel", CommitTimestamp: &baseTs, OverallCoverage: 78,
+	})).Return(nil).Once()
+	db.On("All", mock.Anything, mock.Anything).Run(fillFlags(
+		models.CodecovCoverage{FlagName: "unit", CoveragePercentage: 70},
+	)).Return(nil).Once()
+	db.On("First", mock.Anything, mock.Anything).Run(fillCoverage(models.CodecovCommitCoverage{
+		Branch: "main", CommitSha: "tip", CommitTimestamp: &headTs, OverallCoverage: 81,
+	})).Return(nil).Once()
+	db.On("All", mock.Anything, mock.Anything).Run(fillFlags(
+		models.CodecovCoverage{FlagName: "unit", CoveragePercentage: 74},
+	)).Return(nil).Once()
+
+	repo := &models.CodecovRepo{CodecovId: "konflux-ci/build-service", FullName: "konflux-ci/build-service"}
+	repo.ConnectionId = 1
+	comparison, err := buildBranchComparison(db, repo, "release-1.4", "main")
+	assert.Nil(t, err)
+	assert.Equal(t, "konflux-ci/build-service", comparison.RepoId)
+	assert.Equal(t, "rel", comparison.Base.CommitSha)
+	assert.Equal(t, "main", comparison.Head.Branch)
+	assert.InDelta(t, 3.0, comparison.Delta, 0.0001)
+	if assert.Len(t, comparison.Flags, 1) {
+		assert.InDelta(t, 4.0, *comparison.Flags[0].Delta, 0.0001)
+	}
+}
+
+func TestBuildBranchComparison_NoCoverage(t *testing.T) {
+	db := new(mockdal.Dal)
+	db.On("First", mock.Anything, mock.Anything).Return(errNotFound)
+	db.On("IsErrorNotFound", errNotFound).Return(true)
+
+	repo := &models.CodecovRepo{CodecovId: "konflux-ci/build-service"}
+	_, err := buildBranchComparison(db, repo, "release-1.4", "main")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errors.NotFound, err.GetType())
+	}
+	db.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+}
@@ -28,12 +28,14 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/apache/incubator-devlake/plugins/codecov/tasks"
 	"github.com/apache/incubator-devlake/server/api/shared"
 )
 
 type CodecovTestConnResponse struct {
 	shared.ApiBody
 	Organization string `json:"organization"`
+	ActiveToken  string `json:"activeToken"`
 }
 
 // TestConnection test codecov connection
@@ -68,6 +70,19 @@ func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 // @Failure 500  {string} errcode.Error "Internal Error"
 // @Router /plugins/codecov/connections [POST]
 func PostConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	var connection models.CodecovConnection
+	if err := api.DecodeMapStruct(input.Body, &connection, false); err != nil {
+		return nil, err
+	}
+	if _, err := connection.AutoEnrollPattern(); err != nil {
+		return nil, err
+	}
+	if err := connection.ValidateAccessSettings(); err != nil {
+		return nil, err
+	}
+	if err := validateConnectionAccess(context.TODO(), connection.CodecovConn); err != nil {
+		return nil, err
+	}
 	return dsHelper.ConnApi.Post(input)
 }
 
@@ -80,6 +95,27 @@ func PostConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 // @Failure 500  {string} errcode.Error "Internal Error"
 // @Router /plugins/codecov/connections/{connectionId} [PATCH]
 func PatchConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	existing, err := dsHelper.ConnApi.FindByPk(input)
+	if err != nil {
+		return nil, err
+	}
+	patched, patchErr := dsHelper.ConnApi.PatchModel(input, true)
+	if patchErr != nil {
+		return nil, errors.Convert(patchErr)
+	}
+	if _, err := patched.AutoEnrollPattern(); err != nil {
+		return nil, err
+	}
+	if err := patched.ValidateAccessSettings(); err != nil {
+		return nil, err
+	}
+	// Only re-check access when something that affects it changed, so renaming a
+	// connection still works while Codecov is unreachable
+	if connectionAccessChanged(existing.CodecovConn, patched.CodecovConn) {
+		if err := validateConnectionAccess(context.TODO(), patched.CodecovConn); err != nil {
+			return nil, err
+		}
+	}
 	return dsHelper.ConnApi.Patch(input)
 }
 
@@ -136,34 +172,53 @@ func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResource
 	return &plugin.ApiResourceOutput{Body: testConnectionResult, Status: http.StatusOK}, nil
 }
 
+// connectionAccessChanged reports whether a patch touches the organization, endpoint,
+// proxy, service or tokens, i.e. anything that decides whether the connection can reach Codecov
+func connectionAccessChanged(before, after models.CodecovConn) bool {
+	return before.Organization != after.Organization ||
+		before.Endpoint != after.Endpoint ||
+		before.Proxy != after.Proxy ||
+		before.Service != after.Service ||
+		before.Token != after.Token ||
+		before.FallbackToken != after.FallbackToken
+}
+
+// validateConnectionAccess makes sure a connection can read its organization before it is
+// saved, so a wrong token or organization fails at configuration time instead of in a pipeline
+func validateConnectionAccess(ctx context.Context, conn models.CodecovConn) errors.Error {
+	if _, err := testConnection(ctx, conn); err != nil {
+		return errors.BadInput.Wrap(err, "connection was not saved")
+	}
+	return nil
+}
+
 func testConnection(ctx context.Context, conn models.CodecovConn) (*CodecovTestConnResponse, errors.Error) {
 	if vld != nil {
 		if err := vld.Struct(conn); err != nil {
 			return nil, errors.Convert(err)
 		}
 	}
+	if err := conn.ValidateAccessSettings(); err != nil {
+		return nil, err
+	}
 
 	apiClient, err := api.NewApiClientFromConnection(ctx, basicRes, &conn)
 	if err != nil {
 		return nil, err
 	}
+	tokenRotator := tasks.NewTokenRotator(&conn, basicRes.GetLogger())
+	tokenRotator.Install(apiClient)
 
 	// Test connection by fetching organization info
-	// Codecov API endpoint: GET /api/v2/github/{owner}/users
+	// Codecov API endpoint: GET /api/v2/{service}/{owner}/users
 	// According to Codecov API docs: https://docs.codecov.com/reference/overview
-	testUrl := fmt.Sprintf("/api/v2/github/%s/users", conn.Organization)
+	testUrl := fmt.Sprintf("/api/v2/%s/%s/users", conn.ApiService(), conn.Organization)
 	res, err := apiClient.Get(testUrl, nil, nil)
 	if err != nil {
 		return nil, errors.BadInput.Wrap(err, "verify token failed")
 	}
-	if res.StatusCode == http.StatusUnauthorized {
-		return nil, errors.HttpStatus(http.StatusBadRequest).New("StatusUnauthorized error when testing connection")
-	}
-	if res.StatusCode == http.StatusNotFound {
-		return nil, errors.HttpStatus(http.StatusBadRequest).New(fmt.Sprintf("Organization '%s' not found or token does not have access", conn.Organization))
-	}
-	if res.StatusCode != http.StatusOK {
-		return nil, errors.HttpStatus(res.StatusCode).New("unexpected status code while testing connection")
+	if err := accessStatusError(res.StatusCode, conn.Organization); err != nil {
+		return nil, err
 	}
 
 	return &CodecovTestConnResponse{
@@ -172,5 +227,23 @@ func testConnection(ctx context.Context, conn models.CodecovConn) (*CodecovTestC
 			Message: "success",
 		},
 		Organization: conn.Organization,
+		ActiveToken:  tokenRotator.ActiveToken(),
 	}, nil
 }
+
+// accessStatusError turns the status of the organization check into an actionable error,
+// or nil if the token can read the organization
+func accessStatusError(statusCode int, organization string) errors.Error {
+	switch statusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return errors.HttpStatus(http.StatusBadRequest).New("Codecov rejected the token (401). Generate a new API access token in Codecov under Settings > Access and update the connection")
+	case http.StatusForbidden:
+		return errors.HttpStatus(http.StatusBadRequest).New(fmt.Sprintf("the token is valid but cannot read organization '%s' (403). Use a token of a user who is a member of that organization", organization))
+	case http.StatusNotFound:
+		return errors.HttpStatus(http.StatusBadRequest).New(fmt.Sprintf("Organization '%s' not found or token does not have access. Check the spelling of the GitHub organization and that the token owner is a member of it", organization))
+	default:
+		return errors.HttpStatus(statusCode).New("unexpected status code while testing connection")
+	}
+}
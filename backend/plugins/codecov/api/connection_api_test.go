@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionAccessChanged(t *testing.T) {
+	before := models.CodecovConn{Organization: "konflux-ci"}
+	before.Endpoint = "https://api.codecov.io/"
+	before.Token = "token-1"
+
+	assert.False(t, connectionAccessChanged(before, before))
+
+	renamedOrg := before
+	renamedOrg.Organization = "redhat-appstudio"
+	assert.True(t, connectionAccessChanged(before, renamedOrg))
+
+	newToken := before
+	newToken.Token = "token-2"
+	assert.True(t, connectionAccessChanged(before, newToken))
+
+	newFallback := before
+	newFallback.FallbackToken = "token-3"
+	assert.True(t, connectionAccessChanged(before, newFallback))
+
+	// The GitHub token is not used to reach Codecov
+	newGithubToken := before
+	newGithubToken.GithubToken = "ghp_token"
+	assert.False(t, connectionAccessChanged(before, newGithubToken))
+
+	enterprise := before
+	enterprise.Service = "github_enterprise"
+	assert.True(t, connectionAccessChanged(before, enterprise))
+
+	rateLimited := before
+	rateLimited.RateLimitPerHour = 1000
+	assert.False(t, connectionAccessChanged(before, rateLimited))
+}
+
+func TestValidateAccessSettings(t *testing.T) {
+	conn := models.CodecovConn{Organization: "konflux-ci"}
+	conn.Endpoint = "https://codecov.example.com/codecov/"
+	assert.Nil(t, conn.ValidateAccessSettings())
+	assert.Equal(t, "github", conn.ApiService())
+
+	conn.Proxy = "http://proxy.example.com:3128"
+	conn.Service = "github_enterprise"
+	assert.Nil(t, conn.ValidateAccessSettings())
+	assert.Equal(t, "github_enterprise", conn.ApiService())
+
+	invalid := []func(c *models.CodecovConn){
+		func(c *models.CodecovConn) { c.Endpoint = "codecov.example.com" },
+		func(c *models.CodecovConn) { c.Endpoint = "ftp://codecov.example.com" },
+		func(c *models.CodecovConn) { c.Proxy = "proxy.example.com:3128" },
+		func(c *models.CodecovConn) { c.Service = "gitea" },
+	}
+	for _, change := range invalid {
+		c := conn
+		change(&c)
+		err := c.ValidateAccessSettings()
+		if assert.NotNil(t, err) {
+			assert.Equal(t, http.StatusBadRequest, err.GetType().GetHttpCode())
+		}
+	}
+}
+
+func TestPostConnectionsRejectsInvalidAccessSettings(t *testing.T) {
+	// Rejected before Codecov or the database is reached
+	_, err := PostConnections(&plugin.ApiResourceInput{Body: map[string]interface{}{
+		"name":         "codecov",
+		"organization": "konflux-ci",
+		"endpoint":     "https://api.codecov.io/",
+		"proxy":        "proxy.example.com:3128",
+	}})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, http.StatusBadRequest, err.GetType().GetHttpCode())
+		assert.Contains(t, err.Error(), "proxy")
+	}
+}
+
+func TestAccessStatusError(t *testing.T) {
+	assert.Nil(t, accessStatusError(http.StatusOK, "konflux-ci"))
+
+	err := accessStatusError(http.StatusUnauthorized, "konflux-ci")
+	assert.Equal(t, http.StatusBadRequest, err.GetType().GetHttpCode())
+	assert.Contains(t, err.Error(), "rejected the token")
+
+	err = accessStatusError(http.StatusForbidden, "konflux-ci")
+	assert.Equal(t, http.StatusBadRequest, err.GetType().GetHttpCode())
+	assert.Contains(t, err.Error(), "cannot read organization 'konflux-ci'")
+
+	err = accessStatusError(http.StatusNotFound, "konflux-ci")
+	assert.Contains(t, err.Error(), "Organization 'konflux-ci' not found")
+
+	err = accessStatusError(http.StatusBadGateway, "konflux-ci")
+	assert.Equal(t, http.StatusBadGateway, err.GetType().GetHttpCode())
+}
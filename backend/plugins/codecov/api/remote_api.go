@@ -72,91 +72,108 @@ func listCodecovRemoteScopes(
 		page.PerPage = 100
 	}
 
-	// Codecov API endpoint: GET /api/v2/github/{owner}/repos/
+	// Codecov API endpoint: GET /api/v2/{service}/{owner}/repos/
 	// According to Codecov API docs: https://docs.codecov.com/reference/overview
-	// Service is "github" for GitHub repositories
+	// Service is "github" for GitHub repositories unless the connection sets another one
 	// If groupId is empty, we're listing repos for the organization
 	owner := connection.Organization
+	var parentId *string
 	if groupId != "" {
 		owner = groupId
+		parentId = &groupId
 	}
+	reposUrl := fmt.Sprintf("/api/v2/%s/%s/repos/", connection.ApiService(), owner)
 
 	query := url.Values{
 		"page":      []string{fmt.Sprintf("%v", page.Page)},
 		"page_size": []string{fmt.Sprintf("%v", page.PerPage)},
 	}
 
-	// Codecov API format: /api/v2/github/{owner}/repos/
-	reposUrl := fmt.Sprintf("/api/v2/github/%s/repos/", owner)
-	reposBody, err := apiClient.Get(reposUrl, query, nil)
+	reposResponse, err := getCodecovRepos(apiClient, reposUrl, query, owner)
 	if err != nil {
 		return nil, nil, err
 	}
 
+	for _, repo := range reposResponse.Results {
+		if entry := toCodecovRepoEntry(owner, parentId, repo); entry != nil {
+			children = append(children, *entry)
+		}
+	}
+
+	// Check if there's a next page
+	if reposResponse.Next != nil && *reposResponse.Next != "" {
+		nextPage = &CodecovRemotePagination{
+			Page:    page.Page + 1,
+			PerPage: page.PerPage,
+		}
+	}
+
+	return children, nextPage, nil
+}
+
+// getCodecovRepos fetches one page of repositories and maps the usual error statuses
+func getCodecovRepos(apiClient plugin.ApiClient, reposUrl string, query url.Values, owner string) (*codecovReposResponse, errors.Error) {
+	reposBody, err := apiClient.Get(reposUrl, query, nil)
+	if err != nil {
+		return nil, err
+	}
+
 	if reposBody.StatusCode == http.StatusNotFound {
 		_ = reposBody.Body.Close()
-		return nil, nil, errors.HttpStatus(http.StatusNotFound).New(fmt.Sprintf("Organization or owner '%s' not found", owner))
+		return nil, errors.HttpStatus(http.StatusNotFound).New(fmt.Sprintf("Organization or owner '%s' not found", owner))
 	}
 
 	if reposBody.StatusCode != http.StatusOK {
 		_ = reposBody.Body.Close()
-		return nil, nil, errors.HttpStatus(reposBody.StatusCode).New("unexpected status code while fetching repositories")
+		return nil, errors.HttpStatus(reposBody.StatusCode).New("unexpected status code while fetching repositories")
 	}
 
 	var reposResponse codecovReposResponse
 	err = api.UnmarshalResponse(reposBody, &reposResponse)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
+	return &reposResponse, nil
+}
 
-	for _, repo := range reposResponse.Results {
-		// Get repo name - it might be in repo.Name or repo.Repository.Name
-		repoName := repo.Name
-		if repoName == "" && repo.Repository.Name != "" {
-			repoName = repo.Repository.Name
-		}
-		if repoName == "" {
-			continue
-		}
-
-		fullName := fmt.Sprintf("%s/%s", owner, repoName)
-		codecovId := fmt.Sprintf("%s/%s", owner, repoName)
+// toCodecovRepoEntry converts a Codecov repo into a RAS scope entry, nil when the repo has no name
+func toCodecovRepoEntry(owner string, parentId *string, repo codecovRepo) *dsmodels.DsRemoteApiScopeListEntry[models.CodecovRepo] {
+	// Get repo name - it might be in repo.Name or repo.Repository.Name
+	repoName := repo.Name
+	if repoName == "" && repo.Repository.Name != "" {
+		repoName = repo.Repository.Name
+	}
+	if repoName == "" {
+		return nil
+	}
 
-		branch := repo.Branch
-		if branch == "" {
-			branch = "main"
-		}
+	fullName := fmt.Sprintf("%s/%s", owner, repoName)
+	codecovId := fullName
 
-		children = append(children, dsmodels.DsRemoteApiScopeListEntry[models.CodecovRepo]{
-			Type:     api.RAS_ENTRY_TYPE_SCOPE,
-			ParentId: nil,
-			Id:       codecovId,
-			Name:     repoName,
-			FullName: fullName,
-			Data: &models.CodecovRepo{
-				CodecovId:   codecovId,
-				Name:        repoName,
-				FullName:    fullName,
-				Service:     repo.Service,
-				Language:    repo.Language,
-				Active:      repo.Active,
-				ActivatedAt: repo.ActivatedAt,
-				Updatestamp: repo.Updatestamp,
-				Private:     repo.Private,
-				Branch:      branch,
-			},
-		})
+	branch := repo.Branch
+	if branch == "" {
+		branch = "main"
 	}
 
-	// Check if there's a next page
-	if reposResponse.Next != nil && *reposResponse.Next != "" {
-		nextPage = &CodecovRemotePagination{
-			Page:    page.Page + 1,
-			PerPage: page.PerPage,
-		}
+	return &dsmodels.DsRemoteApiScopeListEntry[models.CodecovRepo]{
+		Type:     api.RAS_ENTRY_TYPE_SCOPE,
+		ParentId: parentId,
+		Id:       codecovId,
+		Name:     repoName,
+		FullName: fullName,
+		Data: &models.CodecovRepo{
+			CodecovId:   codecovId,
+			Name:        repoName,
+			FullName:    fullName,
+			Service:     repo.Service,
+			Language:    repo.Language,
+			Active:      repo.Active,
+			ActivatedAt: repo.ActivatedAt,
+			Updatestamp: repo.Updatestamp,
+			Private:     repo.Private,
+			Branch:      branch,
+		},
 	}
-
-	return children, nextPage, nil
 }
 
 // RemoteScopes list all available scopes on the remote server
@@ -229,64 +246,17 @@ func SearchRemoteScopes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutp
 		"page_size": []string{fmt.Sprintf("%v", pageSize)},
 	}
 
-	reposUrl := fmt.Sprintf("/api/v2/github/%s/repos/", owner)
-	res, err := apiClient.Get(reposUrl, query, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	if res.StatusCode == http.StatusNotFound {
-		_ = res.Body.Close()
-		return nil, errors.HttpStatus(http.StatusNotFound).New(fmt.Sprintf("organization '%s' not found", owner))
-	}
-	if res.StatusCode != http.StatusOK {
-		_ = res.Body.Close()
-		return nil, errors.HttpStatus(res.StatusCode).New("unexpected status code while searching repositories")
-	}
-
-	var reposResponse codecovReposResponse
-	err = api.UnmarshalResponse(res, &reposResponse)
+	reposUrl := fmt.Sprintf("/api/v2/%s/%s/repos/", connection.ApiService(), owner)
+	reposResponse, err := getCodecovRepos(apiClient, reposUrl, query, owner)
 	if err != nil {
 		return nil, err
 	}
 
 	children := make([]dsmodels.DsRemoteApiScopeListEntry[models.CodecovRepo], 0, len(reposResponse.Results))
 	for _, repo := range reposResponse.Results {
-		repoName := repo.Name
-		if repoName == "" && repo.Repository.Name != "" {
-			repoName = repo.Repository.Name
-		}
-		if repoName == "" {
-			continue
-		}
-
-		fullName := fmt.Sprintf("%s/%s", owner, repoName)
-		codecovId := fullName
-
-		branch := repo.Branch
-		if branch == "" {
-			branch = "main"
+		if entry := toCodecovRepoEntry(owner, nil, repo); entry != nil {
+			children = append(children, *entry)
 		}
-
-		children = append(children, dsmodels.DsRemoteApiScopeListEntry[models.CodecovRepo]{
-			Type:     api.RAS_ENTRY_TYPE_SCOPE,
-			ParentId: nil,
-			Id:       codecovId,
-			Name:     repoName,
-			FullName: fullName,
-			Data: &models.CodecovRepo{
-				CodecovId:   codecovId,
-				Name:        repoName,
-				FullName:    fullName,
-				Service:     repo.Service,
-				Language:    repo.Language,
-				Active:      repo.Active,
-				ActivatedAt: repo.ActivatedAt,
-				Updatestamp: repo.Updatestamp,
-				Private:     repo.Private,
-				Branch:      branch,
-			},
-		})
 	}
 
 	return &plugin.ApiResourceOutput{
@@ -297,3 +267,16 @@ func SearchRemoteScopes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutp
 		},
 	}, nil
 }
+
+// Proxy forwards GET requests to the Codecov API with the connection's token, so the
+// config UI can list flags, components and repos without the token reaching the browser.
+// The path is relative to the endpoint, e.g. api/v2/github/{owner}/repos/{repo}/flags
+// @Summary Remote server API proxy
+// @Description Forward API requests to the specified remote server
+// @Param connectionId path int true "connection ID"
+// @Param path path string true "path to a API endpoint"
+// @Tags plugins/codecov
+// @Router /plugins/codecov/connections/{connectionId}/proxy/rest/{path} [GET]
+func Proxy(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return raProxy.Proxy(input)
+}
@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func newTestConnection() *models.CodecovConnection {
+	conn := &models.CodecovConnection{}
+	conn.Organization = "konflux-ci"
+	return conn
+}
+
+func TestListCodecovRemoteScopes_PagesRepos(t *testing.T) {
+	apiClient := new(mockplugin.ApiClient)
+	apiClient.On("Get", "/api/v2/github/konflux-ci/repos/", mock.MatchedBy(func(query url.Values) bool {
+		return query.Get("page") == "1" && query.Get("page_size") == "100"
+	}), mock.Anything).Return(
+		func(string, url.Values, http.Header) (*http.Response, errors.Error) {
+			return jsonResponse(http.StatusOK, `{"results":[{"name":"build-service"},{"name":""}],"next":"page2"}`), nil
+		})
+	apiClient.On("Get", "/api/v2/github/konflux-ci/repos/", mock.MatchedBy(func(query url.Values) bool {
+		return query.Get("page") == "2"
+	}), mock.Anything).Return(
+		func(string, url.Values, http.Header) (*http.Response, errors.Error) {
+			return jsonResponse(http.StatusOK, `{"results":[{"name":"release-service","branch":"develop"}]}`), nil
+		})
+
+	children, nextPage, err := listCodecovRemoteScopes(newTestConnection(), apiClient, "", CodecovRemotePagination{})
+	assert.Nil(t, err)
+	assert.Len(t, children, 1)
+	assert.Equal(t, "konflux-ci/build-service", children[0].Id)
+	assert.Equal(t, "scope", children[0].Type)
+	assert.Nil(t, children[0].ParentId)
+	assert.Equal(t, "main", children[0].Data.Branch)
+	assert.Equal(t, &CodecovRemotePagination{Page: 2, PerPage: 100}, nextPage)
+
+	children, nextPage, err = listCodecovRemoteScopes(newTestConnection(), apiClient, "", *nextPage)
+	assert.Nil(t, err)
+	assert.Nil(t, nextPage)
+	assert.Len(t, children, 1)
+	assert.Equal(t, "develop", children[0].Data.Branch)
+	apiClient.AssertNumberOfCalls(t, "Get", 2)
+}
+
+func TestListCodecovRemoteScopes_OwnerGroup(t *testing.T) {
+	apiClient := new(mockplugin.ApiClient)
+	apiClient.On("Get", "/api/v2/github/other-org/repos/", mock.Anything, mock.Anything).Return(
+		jsonResponse(http.StatusOK, `{"results":[{"name":"build-service"}]}`), nil)
+
+	children, _, err := listCodecovRemoteScopes(newTestConnection(), apiClient, "other-org", CodecovRemotePagination{})
+	assert.Nil(t, err)
+	assert.Len(t, children, 1)
+	assert.Equal(t, "other-org/build-service", children[0].Data.CodecovId)
+	assert.Equal(t, "other-org", *children[0].ParentId)
+}
+
+func TestListCodecovRemoteScopes_ErrorStatus(t *testing.T) {
+	apiClient := new(mockplugin.ApiClient)
+	apiClient.On("Get", "/api/v2/github/konflux-ci/repos/", mock.Anything, mock.Anything).Return(
+		jsonResponse(http.StatusInternalServerError, `{}`), nil)
+
+	_, _, err := listCodecovRemoteScopes(newTestConnection(), apiClient, "", CodecovRemotePagination{})
+	assert.NotNil(t, err)
+}
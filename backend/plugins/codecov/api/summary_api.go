@@ -0,0 +1,280 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+const (
+	summaryPathSuffix = "/summary"
+	comparePathSuffix = "/compare"
+)
+
+// CoverageSnapshot is the coverage of a repo or flag at one commit
+type CoverageSnapshot struct {
+	CommitSha       string     `json:"commitSha"`
+	CommitTimestamp *time.Time `json:"commitTimestamp"`
+	Coverage        float64    `json:"coverage"`
+}
+
+// FlagCoverageSummary is the latest coverage of one flag and its status against the scope
+// config target; Target, TargetStatus and TargetGap are empty when the flag has no target
+type FlagCoverageSummary struct {
+	FlagName string `json:"flagName"`
+	CoverageSnapshot
+	Target       *float64 `json:"target"`
+	TargetStatus string   `json:"targetStatus"`
+	TargetGap    *float64 `json:"targetGap"`
+}
+
+// RepoCoverageSummary is the coverage summary of a repo, meant for badges and portals
+type RepoCoverageSummary struct {
+	ConnectionId  uint64                `json:"connectionId"`
+	RepoId        string                `json:"repoId"`
+	Branch        string                `json:"branch"`
+	Latest        *CoverageSnapshot     `json:"latest"`
+	Delta7d       *float64              `json:"delta7d"`  // nil when there is no commit older than 7 days
+	Delta30d      *float64              `json:"delta30d"` // nil when there is no commit older than 30 days
+	Flags         []FlagCoverageSummary `json:"flags"`
+	TargetsMet    int                   `json:"targetsMet"`    // flags whose latest coverage meets their target
+	TargetsMissed int                   `json:"targetsMissed"` // flags whose latest coverage misses their target
+}
+
+// GetRepoSummary get the coverage summary of a Codecov repo
+// @Summary get the coverage summary of a Codecov repo
+// @Description Latest overall coverage, its delta over 7 and 30 days and the latest coverage of every flag with its target status
+// @Tags plugins/codecov
+// @Param scopeId path string true "scope ID, e.g. owner/repo"
+// @Param connectionId query int false "connection ID, required when the repo is added to several connections"
+// @Param branch query string false "branch, defaults to the repo default branch"
+// @Success 200  {object} RepoCoverageSummary
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /plugins/codecov/repos/{scopeId}/summary [GET]
+func GetRepoSummary(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	scopeId, ok := parseRepoScopeId(input.Params["scopeId"], summaryPathSuffix)
+	if !ok {
+		return nil, errors.NotFound.New("unknown repo resource, expected repos/{scopeId}/summary")
+	}
+
+	db := basicRes.GetDal()
+	repo, err := findRepo(db, scopeId, input.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	branch := input.Query.Get("branch")
+	if branch == "" {
+		branch = repo.Branch
+	}
+	summary, err := buildRepoSummary(db, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: summary, Status: http.StatusOK}, nil
+}
+
+// GetRepoDispatcher serves the resources of the "repos/*scopeId" wildcard route, whose
+// scope ID contains a slash: repos/{scopeId}/summary and repos/{scopeId}/compare
+func GetRepoDispatcher(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if _, ok := parseRepoScopeId(input.Params["scopeId"], comparePathSuffix); ok {
+		return GetRepoCompare(input)
+	}
+	return GetRepoSummary(input)
+}
+
+// parseRepoScopeId extracts the scope ID from a wildcard path "/owner/repo" followed by suffix
+func parseRepoScopeId(rawPath, suffix string) (string, bool) {
+	path := strings.TrimLeft(rawPath, "/")
+	if decoded, err := url.QueryUnescape(path); err == nil {
+		path = decoded
+	}
+	if !strings.HasSuffix(path, suffix) {
+		return "", false
+	}
+	scopeId := strings.TrimSuffix(path, suffix)
+	return scopeId, scopeId != ""
+}
+
+// findRepo loads the repo of a scope ID, restricted to the optional connectionId query parameter
+func findRepo(db dal.Dal, scopeId string, query url.Values) (*models.CodecovRepo, errors.Error) {
+	repoClauses := []dal.Clause{dal.Where("codecov_id = ?", scopeId)}
+	if rawConnectionId := query.Get("connectionId"); rawConnectionId != "" {
+		connectionId, err := strconv.ParseUint(rawConnectionId, 10, 64)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "invalid connectionId")
+		}
+		repoClauses = append(repoClauses, dal.Where("connection_id = ?", connectionId))
+	}
+	repo := &models.CodecovRepo{}
+	if err := db.First(repo, repoClauses...); err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New("repo " + scopeId + " not found")
+		}
+		return nil, err
+	}
+	return repo, nil
+}
+
+// buildRepoSummary loads the latest overall coverage, the 7 and 30 day deltas and the
+// latest coverage of each flag. Coverage tables key repos by full name.
+func buildRepoSummary(db dal.Dal, repo *models.CodecovRepo, branch string) (*RepoCoverageSummary, errors.Error) {
+	repoId := repo.FullName
+	if repoId == "" {
+		repoId = repo.CodecovId
+	}
+	summary := &RepoCoverageSummary{
+		ConnectionId: repo.ConnectionId,
+		RepoId:       repoId,
+		Branch:       branch,
+		Flags:        []FlagCoverageSummary{},
+	}
+
+	latest, err := findCommitCoverage(db, repo.ConnectionId, repoId, branch, nil)
+	if err != nil || latest == nil {
+		return summary, err
+	}
+	summary.Branch = latest.Branch
+	summary.Latest = &CoverageSnapshot{
+		CommitSha:       latest.CommitSha,
+		CommitTimestamp: latest.CommitTimestamp,
+		Coverage:        latest.OverallCoverage,
+	}
+
+	summary.Delta7d, err = coverageDeltaOver(db, latest, 7)
+	if err != nil {
+		return nil, err
+	}
+	summary.Delta30d, err = coverageDeltaOver(db, latest, 30)
+	if err != nil {
+		return nil, err
+	}
+
+	var flagRows []models.CodecovCoverage
+	err = db.All(&flagRows,
+		dal.From("_tool_codecov_coverages c"),
+		dal.Join(`JOIN (
+			SELECT flag_name, MAX(commit_timestamp) AS max_ts FROM _tool_codecov_coverages
+			WHERE connection_id = ? AND repo_id = ? AND branch = ? AND flag_name <> ''
+			GROUP BY flag_name
+		) m ON m.flag_name = c.flag_name AND m.max_ts = c.commit_timestamp`, repo.ConnectionId, repoId, summary.Branch),
+		dal.Where("c.connection_id = ? AND c.repo_id = ? AND c.branch = ?", repo.ConnectionId, repoId, summary.Branch),
+		dal.Orderby("c.flag_name ASC"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	summary.Flags = latestFlagCoverages(flagRows)
+	summary.TargetsMet, summary.TargetsMissed = countFlagTargets(summary.Flags)
+	return summary, nil
+}
+
+// findCommitCoverage returns the most recent overall coverage of the branch, optionally
+// no later than before. An empty branch matches any branch. It returns nil if there is none.
+func findCommitCoverage(db dal.Dal, connectionId uint64, repoId, branch string, before *time.Time) (*models.CodecovCommitCoverage, errors.Error) {
+	clauses := []dal.Clause{
+		dal.Where("connection_id = ? AND repo_id = ? AND commit_timestamp IS NOT NULL", connectionId, repoId),
+	}
+	if branch != "" {
+		clauses = append(clauses, dal.Where("branch = ?", branch))
+	}
+	if before != nil {
+		clauses = append(clauses, dal.Where("commit_timestamp <= ?", *before))
+	}
+	clauses = append(clauses, dal.Orderby("commit_timestamp DESC"))
+
+	coverage := &models.CodecovCommitCoverage{}
+	if err := db.First(coverage, clauses...); err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return coverage, nil
+}
+
+// coverageDeltaOver compares the latest coverage with the last commit at least days older
+func coverageDeltaOver(db dal.Dal, latest *models.CodecovCommitCoverage, days int) (*float64, errors.Error) {
+	if latest.CommitTimestamp == nil {
+		return nil, nil
+	}
+	before := latest.CommitTimestamp.AddDate(0, 0, -days)
+	baseline, err := findCommitCoverage(db, latest.ConnectionId, latest.RepoId, latest.Branch, &before)
+	if err != nil {
+		return nil, err
+	}
+	return coverageDelta(latest, baseline), nil
+}
+
+// coverageDelta returns latest minus baseline coverage, or nil without a baseline
+func coverageDelta(latest, baseline *models.CodecovCommitCoverage) *float64 {
+	if latest == nil || baseline == nil {
+		return nil
+	}
+	delta := latest.OverallCoverage - baseline.OverallCoverage
+	return &delta
+}
+
+// latestFlagCoverages keeps one row per flag; rows sharing the latest timestamp are
+// resolved in favour of the first one
+func latestFlagCoverages(rows []models.CodecovCoverage) []FlagCoverageSummary {
+	flags := make([]FlagCoverageSummary, 0, len(rows))
+	seen := make(map[string]bool, len(rows))
+	for _, row := range rows {
+		if seen[row.FlagName] {
+			continue
+		}
+		seen[row.FlagName] = true
+		flags = append(flags, FlagCoverageSummary{
+			FlagName: row.FlagName,
+			CoverageSnapshot: CoverageSnapshot{
+				CommitSha:       row.CommitSha,
+				CommitTimestamp: row.CommitTimestamp,
+				Coverage:        row.CoveragePercentage,
+			},
+			Target:       row.CoverageTarget,
+			TargetStatus: row.TargetStatus,
+			TargetGap:    row.TargetGap,
+		})
+	}
+	return flags
+}
+
+// countFlagTargets counts the flags meeting and missing their coverage target
+func countFlagTargets(flags []FlagCoverageSummary) (met, missed int) {
+	for _, flag := range flags {
+		switch flag.TargetStatus {
+		case models.TargetStatusMet:
+			met++
+		case models.TargetStatusMissed:
+			missed++
+		}
+	}
+	return met, missed
+}
@@ -0,0 +1,156 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var errNotFound = errors.NotFound.New("record not found")
+
+func TestParseRepoScopeId(t *testing.T) {
+	scopeId, ok := parseRepoScopeId("/konflux-ci/build-service/summary", summaryPathSuffix)
+	assert.True(t, ok)
+	assert.Equal(t, "konflux-ci/build-service", scopeId)
+
+	scopeId, ok = parseRepoScopeId("/konflux-ci%2Fbuild-service/summary", summaryPathSuffix)
+	assert.True(t, ok)
+	assert.Equal(t, "konflux-ci/build-service", scopeId)
+
+	scopeId, ok = parseRepoScopeId("/konflux-ci/build-service/compare", comparePathSuffix)
+	assert.True(t, ok)
+	assert.Equal(t, "konflux-ci/build-service", scopeId)
+
+	_, ok = parseRepoScopeId("/konflux-ci/build-service", summaryPathSuffix)
+	assert.False(t, ok)
+	_, ok = parseRepoScopeId("/konflux-ci/build-service/compare", summaryPathSuffix)
+	assert.False(t, ok)
+	_, ok = parseRepoScopeId("/summary", summaryPathSuffix)
+	assert.False(t, ok)
+}
+
+func TestCoverageDelta(t *testing.T) {
+	latest := &models.CodecovCommitCoverage{OverallCoverage: 81.5}
+	assert.Nil(t, coverageDelta(latest, nil))
+
+	delta := coverageDelta(latest, &models.CodecovCommitCoverage{OverallCoverage: 80})
+	if assert.NotNil(t, delta) {
+		assert.InDelta(t, 1.5, *delta, 0.0001)
+	}
+}
+
+func TestLatestFlagCoverages(t *testing.T) {
+	ts := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	flags := latestFlagCoverages([]models.CodecovCoverage{
+		{FlagName: "e2e", CommitSha: "aaa", CommitTimestamp: &ts, CoveragePercentage: 40},
+		{FlagName: "unit", CommitSha: "bbb", CommitTimestamp: &ts, CoveragePercentage: 70},
+		{FlagName: "unit", CommitSha: "ccc", CommitTimestamp: &ts, CoveragePercentage: 71},
+	})
+	assert.Len(t, flags, 2)
+	assert.Equal(t, "e2e", flags[0].FlagName)
+	assert.Equal(t, "bbb", flags[1].CommitSha)
+	assert.Equal(t, 70.0, flags[1].Coverage)
+}
+
+func TestLatestFlagCoverages_Targets(t *testing.T) {
+	target, gap := 80.0, -5.0
+	flags := latestFlagCoverages([]models.CodecovCoverage{
+		{FlagName: "e2e", CoveragePercentage: 40},
+		{FlagName: "unit", CoveragePercentage: 75, CoverageTarget: &target, TargetStatus: models.TargetStatusMissed, TargetGap: &gap},
+	})
+	assert.Nil(t, flags[0].Target)
+	assert.Empty(t, flags[0].TargetStatus)
+	assert.Equal(t, &target, flags[1].Target)
+	assert.Equal(t, models.TargetStatusMissed, flags[1].TargetStatus)
+	assert.Equal(t, &gap, flags[1].TargetGap)
+}
+
+func TestCountFlagTargets(t *testing.T) {
+	met, missed := countFlagTargets([]FlagCoverageSummary{
+		{FlagName: "unit", TargetStatus: models.TargetStatusMet},
+		{FlagName: "e2e", TargetStatus: models.TargetStatusMissed},
+		{FlagName: "integration", TargetStatus: models.TargetStatusMet},
+		{FlagName: "lint"},
+	})
+	assert.Equal(t, 2, met)
+	assert.Equal(t, 1, missed)
+}
+
+func TestBuildRepoSummary_NoCoverage(t *testing.T) {
+	db := new(mockdal.Dal)
+	db.On("First", mock.Anything, mock.Anything).Return(errNotFound)
+	db.On("IsErrorNotFound", errNotFound).Return(true)
+
+	repo := &models.CodecovRepo{CodecovId: "konflux-ci/build-service", Branch: "main"}
+	repo.ConnectionId = 1
+	summary, err := buildRepoSummary(db, repo, "main")
+	assert.Nil(t, err)
+	assert.Equal(t, "konflux-ci/build-service", summary.RepoId)
+	assert.Equal(t, "main", summary.Branch)
+	assert.Nil(t, summary.Latest)
+	assert.Nil(t, summary.Delta7d)
+	assert.Empty(t, summary.Flags)
+	db.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+}
+
+func TestBuildRepoSummary(t *testing.T) {
+	latestTs := time.Date(2026, 5, 31, 12, 0, 0, 0, time.UTC)
+	weekAgoTs := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	fill := func(coverage models.CodecovCommitCoverage) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(0).(*models.CodecovCommitCoverage) = coverage
+		}
+	}
+
+	db := new(mockdal.Dal)
+	db.On("First", mock.Anything, mock.Anything).Run(fill(models.CodecovCommitCoverage{
+		ConnectionId: 1, RepoId: "konflux-ci/build-service", Branch: "main",
+		CommitSha: "new", CommitTimestamp: &latestTs, OverallCoverage: 82,
+	})).Return(nil).Once()
+	db.On("First", mock.Anything, mock.Anything).Run(fill(models.CodecovCommitCoverage{
+		ConnectionId: 1, RepoId: "konflux-ci/build-service", Branch: "main",
+		CommitSha: "old", CommitTimestamp: &weekAgoTs, OverallCoverage: 80,
+	})).Return(nil).Once()
+	db.On("First", mock.Anything, mock.Anything).Return(errNotFound).Once()
+	db.On("IsErrorNotFound", errNotFound).Return(true)
+	db.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]models.CodecovCoverage) = []models.CodecovCoverage{
+			{FlagName: "unit", CommitSha: "new", CommitTimestamp: &latestTs, CoveragePercentage: 75},
+		}
+	}).Return(nil)
+
+	repo := &models.CodecovRepo{CodecovId: "konflux-ci/build-service", FullName: "konflux-ci/build-service"}
+	repo.ConnectionId = 1
+	summary, err := buildRepoSummary(db, repo, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "main", summary.Branch)
+	assert.Equal(t, "new", summary.Latest.CommitSha)
+	assert.Equal(t, 82.0, summary.Latest.Coverage)
+	if assert.NotNil(t, summary.Delta7d) {
+		assert.InDelta(t, 2.0, *summary.Delta7d, 0.0001)
+	}
+	assert.Nil(t, summary.Delta30d)
+	assert.Equal(t, []FlagCoverageSummary{{FlagName: "unit", CoverageSnapshot: CoverageSnapshot{CommitSha: "new", CommitTimestamp: &latestTs, Coverage: 75}}}, summary.Flags)
+}
@@ -0,0 +1,156 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/codecov/impl"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/apache/incubator-devlake/plugins/codecov/tasks"
+)
+
+// TestCoverageDataFlow runs commits -> comparisons -> per-flag coverages -> commit coverages.
+// The fixtures cover the subtle branches of the converters:
+//   - a commit timestamp with an offset is stored in UTC, an unparsable one is stored as NULL
+//   - patch coverage is kept only when the patch touched files or lines, and is NULL when Codecov returns null
+//   - a flag present in the "flags" map uses its own totals, a missing one falls back to the commit totals
+//   - "commitid" in the response overrides a stale commit_sha input for per-flag coverages, while commit
+//     totals only fall back to it when the input is empty
+//   - rows for unknown commits, empty flags or empty commit ids are skipped
+//   - commit coverages take methods from the overall comparison when there is one, even if it is all zeroes
+//   - flag coverages get the status of the first matching scope config target: met at or above it, missed below
+func TestCoverageDataFlow(t *testing.T) {
+	var codecov impl.Codecov
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "codecov", codecov)
+
+	taskData := &tasks.CodecovTaskData{
+		Options: &tasks.CodecovOptions{
+			ConnectionId: 1,
+			FullName:     "konflux-ci/build-service",
+			ScopeConfig: &models.CodecovScopeConfig{
+				FlagCoverageTargets: []models.FlagCoverageTarget{
+					{FlagPattern: "^unit$", Target: 87},
+					{FlagPattern: "^e2e", Target: 62},
+				},
+			},
+		},
+	}
+
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_codecov_api_commits.csv", "_raw_"+tasks.RAW_COMMITS_TABLE)
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_codecov_api_comparisons.csv", "_raw_"+tasks.RAW_COMPARISONS_TABLE)
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_codecov_api_commit_coverages.csv", "_raw_"+tasks.RAW_COMMIT_COVERAGES_TABLE)
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_codecov_api_commit_totals.csv", "_raw_"+tasks.RAW_COMMIT_TOTALS_TABLE)
+
+	// verify commit extraction
+	dataflowTester.FlushTabler(&models.CodecovCommit{})
+	dataflowTester.Subtask(tasks.ExtractCommitsMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		models.CodecovCommit{},
+		"./snapshot_tables/_tool_codecov_commits.csv",
+		[]string{
+			"connection_id",
+			"repo_id",
+			"commit_sha",
+			"branch",
+			"commit_timestamp",
+			"message",
+			"author",
+			"parent_sha",
+		},
+	)
+
+	// verify comparison conversion
+	dataflowTester.FlushTabler(&tasks.ComparisonData{})
+	dataflowTester.Subtask(tasks.ConvertComparisonMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		tasks.ComparisonData{},
+		"./snapshot_tables/_tool_codecov_comparisons.csv",
+		[]string{
+			"connection_id",
+			"repo_id",
+			"commit_sha",
+			"flag_name",
+			"parent_sha",
+			"modified_coverage",
+			"files_changed",
+			"methods_covered",
+			"methods_total",
+			"lines_covered",
+			"lines_total",
+			"lines_missed",
+			"patch",
+		},
+	)
+
+	// verify per-flag coverage conversion
+	dataflowTester.FlushTabler(&models.CodecovCoverage{})
+	dataflowTester.Subtask(tasks.ConvertCoverageMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		models.CodecovCoverage{},
+		"./snapshot_tables/_tool_codecov_coverages.csv",
+		[]string{
+			"connection_id",
+			"repo_id",
+			"flag_name",
+			"branch",
+			"commit_sha",
+			"commit_timestamp",
+			"coverage_percentage",
+			"modified_coverage",
+			"lines_covered",
+			"lines_total",
+			"lines_missed",
+			"hits",
+			"partials",
+			"misses",
+			"methods_covered",
+			"methods_total",
+			"coverage_target",
+			"target_status",
+			"target_gap",
+		},
+	)
+
+	// verify commit coverage conversion
+	dataflowTester.FlushTabler(&models.CodecovCommitCoverage{})
+	dataflowTester.Subtask(tasks.ConvertCommitCoverageMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		models.CodecovCommitCoverage{},
+		"./snapshot_tables/_tool_codecov_commit_coverages.csv",
+		[]string{
+			"connection_id",
+			"repo_id",
+			"commit_sha",
+			"branch",
+			"commit_timestamp",
+			"overall_coverage",
+			"modified_coverage",
+			"files_changed",
+			"lines_covered",
+			"lines_total",
+			"lines_missed",
+			"hits",
+			"partials",
+			"misses",
+			"methods_covered",
+			"methods_total",
+		},
+	)
+}
@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/codecov/impl"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/apache/incubator-devlake/plugins/codecov/tasks"
+)
+
+func TestCoverageTrendDataFlow(t *testing.T) {
+	var codecov impl.Codecov
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "codecov", codecov)
+
+	taskData := &tasks.CodecovTaskData{
+		Options: &tasks.CodecovOptions{
+			ConnectionId: 1,
+			FullName:     "konflux-ci/build-service",
+		},
+	}
+
+	// one point uses a "YYYY-MM-DD hh:mm:ss" timestamp and is parsed by its date prefix,
+	// another has an invalid date and must be skipped
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_codecov_api_flag_coverage_trends.csv", "_raw_"+tasks.RAW_FLAG_COVERAGE_TRENDS_TABLE)
+	dataflowTester.FlushTabler(&models.CodecovCoverageTrend{})
+	dataflowTester.Subtask(tasks.ConvertCoverageTrendMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		models.CodecovCoverageTrend{},
+		"./snapshot_tables/_tool_codecov_coverage_trends.csv",
+		[]string{
+			"connection_id",
+			"repo_id",
+			"flag_name",
+			"branch",
+			"date",
+			"coverage_percentage",
+			"lines_covered",
+			"lines_total",
+			"methods_covered",
+			"methods_total",
+		},
+	)
+}
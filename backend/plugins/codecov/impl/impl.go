@@ -38,7 +38,7 @@ var _ interface {
 	plugin.PluginApi
 	plugin.PluginModel
 	plugin.PluginSource
-	plugin.DataSourcePluginBlueprintV200
+	plugin.DataSourcePluginProjectBlueprintV200
 } = (*Codecov)(nil)
 
 type Codecov struct{}
@@ -70,6 +70,10 @@ func (p Codecov) GetTablesInfo() []dal.Tabler {
 		&models.CodecovCoverage{},
 		&models.CodecovCoverageTrend{},
 		&models.CodecovCommitCoverage{},
+		&models.CodecovMissingUpload{},
+		&models.CodecovFlagScenario{},
+		&models.CodecovCommitLink{},
+		&models.CodecovCoverageDrop{},
 	}
 }
 
@@ -134,6 +138,13 @@ func (p Codecov) ApiResources() map[string]map[string]plugin.ApiResourceHandler
 		"connections/:connectionId/search-remote-scopes": {
 			"GET": api.SearchRemoteScopes,
 		},
+		"connections/:connectionId/proxy/rest/*path": {
+			"GET": api.Proxy,
+		},
+		"repos/*scopeId": {
+			// "repos/:scopeId/summary" and "repos/:scopeId/compare"; scopeId contains a slash ("owner/repo")
+			"GET": api.GetRepoDispatcher,
+		},
 	}
 }
 
@@ -142,6 +153,7 @@ func (p Codecov) SubTaskMetas() []plugin.SubTaskMeta {
 		// Step 1: Collect and convert flags first (needed for flag-based coverage collection)
 		tasks.CollectFlagsMeta,
 		tasks.ConvertFlagsMeta,
+		tasks.MapFlagScenariosMeta,
 		// Step 2: Collect commits
 		tasks.CollectCommitsMeta,
 		tasks.ExtractCommitsMeta,
@@ -155,6 +167,11 @@ func (p Codecov) SubTaskMetas() []plugin.SubTaskMeta {
 		tasks.ConvertCoverageMeta,
 		tasks.ConvertCommitCoverageMeta,
 		tasks.ConvertCoverageTrendMeta,
+		tasks.ConvertCommitLinksMeta,
+		// Step 5: Alert on default-branch commits without coverage upload
+		tasks.DetectMissingUploadsMeta,
+		// Step 6: Report default-branch coverage drops to the webhook and GitHub
+		tasks.ExportCoverageStatusMeta,
 	}
 }
 
@@ -176,6 +193,9 @@ func (p Codecov) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]
 	if err != nil {
 		return nil, err
 	}
+	// Rotate to the fallback token (if any) when the primary one gets 401/429
+	tokenRotator := tasks.NewTokenRotator(&connection.CodecovConn, taskCtx.GetLogger())
+	tokenRotator.Install(apiClient)
 
 	// Create async API client with rate limiter optimized for Codecov
 	// Note: Go's http.Client already has connection pooling via http.Transport
@@ -192,7 +212,7 @@ func (p Codecov) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]
 		return nil, err
 	}
 
-	taskCtx.GetLogger().Info("[Codecov] API client initialized with rate limiter (5000 req/hour)")
+	taskCtx.GetLogger().Info("[Codecov] API client initialized with rate limiter (5000 req/hour), using %s token", tokenRotator.ActiveToken())
 
 	// Load the CodecovRepo scope to get branch and other metadata
 	repo := &models.CodecovRepo{}
@@ -207,7 +227,7 @@ func (p Codecov) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]
 	if parseErr != nil {
 		taskCtx.GetLogger().Warn(parseErr, "[Codecov] Failed to parse fullName '%s', branch detection skipped", op.FullName)
 	} else {
-		repoUrl := fmt.Sprintf("/api/v2/github/%s/repos/%s/", owner, repoName)
+		repoUrl := fmt.Sprintf("/api/v2/%s/%s/repos/%s/", connection.ApiService(), owner, repoName)
 		res, apiErr := apiClient.Get(repoUrl, nil, nil)
 		if apiErr != nil {
 			taskCtx.GetLogger().Warn(apiErr, "[Codecov] Failed to fetch repo detail for %s, using stored branch", op.FullName)
@@ -247,9 +267,12 @@ func (p Codecov) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]
 	}
 
 	return &tasks.CodecovTaskData{
-		Options:   op,
-		ApiClient: asyncApiClient,
-		Repo:      repo,
+		Options:      op,
+		ApiClient:    asyncApiClient,
+		Repo:         repo,
+		TokenRotator: tokenRotator,
+		Service:      connection.ApiService(),
+		GithubToken:  connection.GithubToken,
 	}, nil
 }
 
@@ -257,5 +280,13 @@ func (p Codecov) MakeDataSourcePipelinePlanV200(
 	connectionId uint64,
 	scopes []*coreModels.BlueprintScope,
 ) (coreModels.PipelinePlan, []plugin.Scope, errors.Error) {
-	return api.MakeDataSourcePipelinePlanV200(p.SubTaskMetas(), connectionId, scopes)
+	return api.MakeDataSourcePipelinePlanV200(p.SubTaskMetas(), "", connectionId, scopes)
+}
+
+func (p Codecov) MakeProjectDataSourcePipelinePlanV200(
+	projectName string,
+	connectionId uint64,
+	scopes []*coreModels.BlueprintScope,
+) (coreModels.PipelinePlan, []plugin.Scope, errors.Error) {
+	return api.MakeDataSourcePipelinePlanV200(p.SubTaskMetas(), projectName, connectionId, scopes)
 }
@@ -0,0 +1,38 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// CodecovCommitLink links a Codecov commit to the domain repo whose repo_commits contain
+// the same SHA, so coverage can be joined with the author and dates of the domain
+// commits table collected by the github/gitlab plugins or gitextractor.
+// Rows of a repo are rebuilt on every run.
+type CodecovCommitLink struct {
+	common.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey;type:bigint" json:"connectionId"`
+	RepoId       string `gorm:"primaryKey;type:varchar(200)" json:"repoId"`
+	CommitSha    string `gorm:"primaryKey;type:varchar(64)" json:"commitSha"` // Also commits.sha
+	DomainRepoId string `gorm:"type:varchar(255);index" json:"domainRepoId"`  // repos.id
+}
+
+func (CodecovCommitLink) TableName() string {
+	return "_tool_codecov_commit_links"
+}
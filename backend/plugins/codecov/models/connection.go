@@ -20,6 +20,8 @@ package models
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
@@ -27,8 +29,12 @@ import (
 )
 
 // CodecovAccessToken supports token-based authentication
+// FallbackToken is optional, it takes over when the primary token is rejected (401) or throttled (429)
+// GithubToken is optional, the coverage status export uses it to post commit statuses to GitHub
 type CodecovAccessToken struct {
 	helper.AccessToken `mapstructure:",squash"`
+	FallbackToken      string `mapstructure:"fallbackToken" json:"fallbackToken" gorm:"serializer:encdec"`
+	GithubToken        string `mapstructure:"githubToken" json:"githubToken" gorm:"serializer:encdec"`
 }
 
 // SetupAuthentication sets up the HTTP Request Authentication
@@ -39,11 +45,69 @@ func (conn *CodecovAccessToken) SetupAuthentication(req *http.Request) errors.Er
 	return nil
 }
 
+// DefaultService is the git provider of Codecov cloud organizations
+const DefaultService = "github"
+
+// CodecovServices are the git providers Codecov API paths accept, self-hosted
+// installations backed by GitHub Enterprise use "github_enterprise"
+var CodecovServices = []string{"github", "github_enterprise", "gitlab", "gitlab_enterprise", "bitbucket", "bitbucket_server"}
+
 // CodecovConn holds the essential information to connect to the Codecov API
+// Endpoint is https://api.codecov.io for Codecov cloud or the base URL of a self-hosted
+// installation; Proxy, when set, is used for every request of the connection
 type CodecovConn struct {
 	helper.RestConnection `mapstructure:",squash"`
 	CodecovAccessToken    `mapstructure:",squash"`
 	Organization          string `mapstructure:"organization" json:"organization" gorm:"type:varchar(255)" validate:"required"`
+	// Service is the git provider segment of the API paths (empty = github)
+	Service string `mapstructure:"service" json:"service" gorm:"type:varchar(50)"`
+}
+
+// ApiService returns the git provider segment of the API paths, e.g. "github" in /api/v2/github/{owner}/repos/
+func (conn *CodecovConn) ApiService() string {
+	if conn.Service == "" {
+		return DefaultService
+	}
+	return conn.Service
+}
+
+// ValidateAccessSettings checks the endpoint, proxy and service before any request is made,
+// so a typo in a self-hosted URL fails with a readable error instead of a transport error
+func (conn *CodecovConn) ValidateAccessSettings() errors.Error {
+	if err := validateHttpUrl("endpoint", conn.Endpoint, "http", "https"); err != nil {
+		return err
+	}
+	if conn.Proxy != "" {
+		if err := validateHttpUrl("proxy", conn.Proxy, "http", "https", "socks5"); err != nil {
+			return err
+		}
+	}
+	if conn.Service != "" {
+		for _, service := range CodecovServices {
+			if conn.Service == service {
+				return nil
+			}
+		}
+		return errors.BadInput.New(fmt.Sprintf("invalid service %q, expected one of %v", conn.Service, CodecovServices))
+	}
+	return nil
+}
+
+// validateHttpUrl makes sure a connection URL is absolute and uses one of the schemes
+func validateHttpUrl(field, rawUrl string, schemes ...string) errors.Error {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid %s %q", field, rawUrl))
+	}
+	if u.Host == "" {
+		return errors.BadInput.New(fmt.Sprintf("invalid %s %q, expected an absolute URL such as https://codecov.example.com", field, rawUrl))
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return errors.BadInput.New(fmt.Sprintf("invalid %s %q, the scheme must be one of %v", field, rawUrl, schemes))
 }
 
 // PrepareApiClient configures the HTTP client headers for optimal performance
@@ -62,9 +126,15 @@ func (conn *CodecovConn) PrepareApiClient(apiClient plugin.ApiClient) errors.Err
 }
 
 // CodecovConnection holds CodecovConn plus ID/Name for database storage
+// AutoEnrollRegex, when set, makes each run of the blueprint of project AutoEnrollProjectName
+// enroll the organization's repos whose name matches as scopes, with AutoEnrollScopeConfigId
+// as their scope config (0 = none)
 type CodecovConnection struct {
-	helper.BaseConnection `mapstructure:",squash"`
-	CodecovConn           `mapstructure:",squash"`
+	helper.BaseConnection   `mapstructure:",squash"`
+	CodecovConn             `mapstructure:",squash"`
+	AutoEnrollRegex         string `mapstructure:"autoEnrollRegex" json:"autoEnrollRegex" gorm:"type:varchar(500)"`
+	AutoEnrollProjectName   string `mapstructure:"autoEnrollProjectName" json:"autoEnrollProjectName" gorm:"type:varchar(255)"`
+	AutoEnrollScopeConfigId uint64 `mapstructure:"autoEnrollScopeConfigId" json:"autoEnrollScopeConfigId"`
 }
 
 func (connection CodecovConnection) TableName() string {
@@ -81,37 +151,53 @@ func (connection *CodecovConnection) MergeFromRequest(target *CodecovConnection,
 
 func (connection *CodecovConnection) Merge(existed, modified *CodecovConnection, body map[string]interface{}) error {
 	existedTokenStr := existed.Token
+	existedFallbackStr := existed.FallbackToken
+	existedGithubStr := existed.GithubToken
+	sanitized := existed.Sanitize()
 	existed.Name = modified.Name
 	existed.Organization = modified.Organization
 	existed.Proxy = modified.Proxy
 	existed.Endpoint = modified.Endpoint
+	existed.Service = modified.Service
 	existed.RateLimitPerHour = modified.RateLimitPerHour
+	existed.AutoEnrollRegex = modified.AutoEnrollRegex
+	existed.AutoEnrollProjectName = modified.AutoEnrollProjectName
+	existed.AutoEnrollScopeConfigId = modified.AutoEnrollScopeConfigId
 
-	// handle token
-	if existedTokenStr == "" {
-		if modified.Token != "" {
-			existed.Token = modified.Token
-		}
-	} else {
-		if modified.Token == "" {
-			// delete token
-			existed.Token = modified.Token
-		} else {
-			// update token
-			sanitizeToken := existed.Sanitize().Token
-			if sanitizeToken == modified.Token {
-				// change nothing, restore it
-				existed.Token = existedTokenStr
-			} else {
-				// has changed, replace it with the new token
-				existed.Token = modified.Token
-			}
-		}
-	}
+	// handle tokens
+	existed.Token = mergeToken(existedTokenStr, modified.Token, sanitized.Token)
+	existed.FallbackToken = mergeToken(existedFallbackStr, modified.FallbackToken, sanitized.FallbackToken)
+	existed.GithubToken = mergeToken(existedGithubStr, modified.GithubToken, sanitized.GithubToken)
 
 	return nil
 }
 
+// AutoEnrollsInto tells whether auto-enrollment applies to the blueprint of the project being planned
+func (connection *CodecovConnection) AutoEnrollsInto(projectName string) bool {
+	return connection.AutoEnrollProjectName != "" && connection.AutoEnrollProjectName == projectName
+}
+
+// AutoEnrollPattern compiles AutoEnrollRegex, nil when auto-enrollment is off
+func (connection *CodecovConnection) AutoEnrollPattern() (*regexp.Regexp, errors.Error) {
+	if connection.AutoEnrollRegex == "" {
+		return nil, nil
+	}
+	pattern, err := regexp.Compile(connection.AutoEnrollRegex)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid autoEnrollRegex %q", connection.AutoEnrollRegex))
+	}
+	return pattern, nil
+}
+
+// mergeToken decides the token to keep: empty means delete, the sanitized value means unchanged
+func mergeToken(existed, modified, sanitized string) string {
+	if existed != "" && modified != "" && modified == sanitized {
+		// change nothing, restore it
+		return existed
+	}
+	return modified
+}
+
 func (connection CodecovConnection) Sanitize() CodecovConnection {
 	connection.CodecovConn = connection.CodecovConn.Sanitize()
 	return connection
@@ -123,20 +209,25 @@ func (conn *CodecovConn) Sanitize() CodecovConn {
 }
 
 func (conn *CodecovConn) SanitizeToken() CodecovConn {
-	if conn.Token == "" {
-		return *conn
+	conn.Token = sanitizeToken(conn.Token)
+	conn.FallbackToken = sanitizeToken(conn.FallbackToken)
+	conn.GithubToken = sanitizeToken(conn.GithubToken)
+	return *conn
+}
+
+func sanitizeToken(token string) string {
+	if token == "" {
+		return token
 	}
 	// Codecov tokens are typically UUIDs or similar, mask most of it
-	if len(conn.Token) > 8 {
+	if len(token) > 8 {
 		showPrefixLen := 4
-		hiddenLen := len(conn.Token) - showPrefixLen - 4
+		hiddenLen := len(token) - showPrefixLen - 4
 		secret := ""
 		for i := 0; i < hiddenLen; i++ {
 			secret += "*"
 		}
-		conn.Token = conn.Token[:showPrefixLen] + secret + conn.Token[len(conn.Token)-4:]
-	} else {
-		conn.Token = "****"
+		return token[:showPrefixLen] + secret + token[len(token)-4:]
 	}
-	return *conn
+	return "****"
 }
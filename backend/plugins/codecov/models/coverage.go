@@ -41,6 +41,9 @@ type CodecovCoverage struct {
 	Misses             int        `json:"misses"`
 	MethodsCovered     int        `json:"methodsCovered"`
 	MethodsTotal       int        `json:"methodsTotal"`
+	CoverageTarget     *float64   `json:"coverageTarget"`                       // nil when no scope config target matches the flag
+	TargetStatus       string     `gorm:"type:varchar(20)" json:"targetStatus"` // met or missed, empty without a target
+	TargetGap          *float64   `json:"targetGap"`                            // coverage minus target, negative when missed
 }
 
 func (CodecovCoverage) TableName() string {
@@ -0,0 +1,47 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// CodecovCoverageDrop is a default-branch commit whose overall coverage fell more than the
+// CoverageDropThreshold of the scope config below the previous commit. NotifiedAt is set once
+// the webhook accepted it, StatusPostedAt once GitHub accepted the commit status.
+type CodecovCoverageDrop struct {
+	common.NoPKModel
+	ConnectionId    uint64     `gorm:"primaryKey;type:bigint" json:"connectionId"`
+	RepoId          string     `gorm:"primaryKey;type:varchar(200)" json:"repoId"`
+	CommitSha       string     `gorm:"primaryKey;type:varchar(64)" json:"commitSha"`
+	Branch          string     `gorm:"type:varchar(100)" json:"branch"`
+	CommitTimestamp *time.Time `gorm:"index" json:"commitTimestamp"`
+	Coverage        float64    `json:"coverage"`
+	BaseCommitSha   string     `gorm:"type:varchar(64)" json:"baseCommitSha"`
+	BaseCoverage    float64    `json:"baseCoverage"`
+	Decrease        float64    `json:"decrease"` // BaseCoverage - Coverage, in percentage points
+	DetectedAt      time.Time  `json:"detectedAt"`
+	NotifiedAt      *time.Time `json:"notifiedAt"`
+	StatusPostedAt  *time.Time `json:"statusPostedAt"`
+}
+
+func (CodecovCoverageDrop) TableName() string {
+	return "_tool_codecov_coverage_drops"
+}
@@ -0,0 +1,39 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// CodecovFlagScenario links a Codecov flag to the testregistry scenario producing its
+// coverage, so dashboards can show the scenario pass rate next to the flag coverage.
+// Rows of a repo are rebuilt from the scope config's FlagScenarioMappings on every run.
+type CodecovFlagScenario struct {
+	common.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey;type:bigint" json:"connectionId"`
+	RepoId       string `gorm:"primaryKey;type:varchar(200)" json:"repoId"`
+	FlagName     string `gorm:"primaryKey;type:varchar(100)" json:"flagName"`
+	Scenario     string `gorm:"type:varchar(500);index" json:"scenario"`
+	ScenarioKind string `gorm:"type:varchar(20)" json:"scenarioKind"` // job or suite
+	FlagPattern  string `gorm:"type:varchar(500)" json:"flagPattern"` // Mapping that matched the flag
+}
+
+func (CodecovFlagScenario) TableName() string {
+	return "_tool_codecov_flag_scenarios"
+}
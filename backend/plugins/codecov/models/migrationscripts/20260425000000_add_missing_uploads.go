@@ -0,0 +1,69 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addMissingUploads)(nil)
+
+type addMissingUploads struct{}
+
+type scopeConfig20260425 struct {
+	MissingUploadGraceHours int
+	MissingUploadWebhookUrl string `gorm:"type:varchar(500)"`
+}
+
+func (scopeConfig20260425) TableName() string {
+	return "_tool_codecov_scope_configs"
+}
+
+type missingUpload20260425 struct {
+	archived.NoPKModel
+	ConnectionId    uint64     `gorm:"primaryKey;type:bigint"`
+	RepoId          string     `gorm:"primaryKey;type:varchar(200)"`
+	CommitSha       string     `gorm:"primaryKey;type:varchar(64)"`
+	Branch          string     `gorm:"type:varchar(100)"`
+	CommitTimestamp *time.Time `gorm:"index"`
+	Author          string     `gorm:"type:varchar(255)"`
+	DetectedAt      time.Time
+	NotifiedAt      *time.Time
+}
+
+func (missingUpload20260425) TableName() string {
+	return "_tool_codecov_missing_uploads"
+}
+
+func (script *addMissingUploads) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &scopeConfig20260425{}, &missingUpload20260425{})
+}
+
+func (*addMissingUploads) Version() uint64 {
+	return 20260425000000
+}
+
+func (*addMissingUploads) Name() string {
+	return "Codecov add missing upload settings to scope configs and the missing uploads table"
+}
@@ -0,0 +1,64 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addFlagScenarios)(nil)
+
+type addFlagScenarios struct{}
+
+type scopeConfig20260426 struct {
+	FlagScenarioMappings string `gorm:"type:json"`
+}
+
+func (scopeConfig20260426) TableName() string {
+	return "_tool_codecov_scope_configs"
+}
+
+type flagScenario20260426 struct {
+	archived.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey;type:bigint"`
+	RepoId       string `gorm:"primaryKey;type:varchar(200)"`
+	FlagName     string `gorm:"primaryKey;type:varchar(100)"`
+	Scenario     string `gorm:"type:varchar(500);index"`
+	ScenarioKind string `gorm:"type:varchar(20)"`
+	FlagPattern  string `gorm:"type:varchar(500)"`
+}
+
+func (flagScenario20260426) TableName() string {
+	return "_tool_codecov_flag_scenarios"
+}
+
+func (script *addFlagScenarios) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &scopeConfig20260426{}, &flagScenario20260426{})
+}
+
+func (*addFlagScenarios) Version() uint64 {
+	return 20260426000000
+}
+
+func (*addFlagScenarios) Name() string {
+	return "Codecov add flag to testregistry scenario mappings to scope configs and the flag scenarios table"
+}
@@ -0,0 +1,54 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addCommitLinks)(nil)
+
+type addCommitLinks struct{}
+
+type commitLink20260428 struct {
+	archived.NoPKModel
+	ConnectionId uint64 `gorm:"primaryKey;type:bigint"`
+	RepoId       string `gorm:"primaryKey;type:varchar(200)"`
+	CommitSha    string `gorm:"primaryKey;type:varchar(64)"`
+	DomainRepoId string `gorm:"type:varchar(255);index"`
+}
+
+func (commitLink20260428) TableName() string {
+	return "_tool_codecov_commit_links"
+}
+
+func (script *addCommitLinks) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &commitLink20260428{})
+}
+
+func (*addCommitLinks) Version() uint64 {
+	return 20260428000000
+}
+
+func (*addCommitLinks) Name() string {
+	return "Codecov add commit links to domain commits table"
+}
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addFlagCoverageTargets)(nil)
+
+type addFlagCoverageTargets struct{}
+
+type scopeConfig20260429 struct {
+	FlagCoverageTargets string `gorm:"type:json"`
+}
+
+func (scopeConfig20260429) TableName() string {
+	return "_tool_codecov_scope_configs"
+}
+
+type coverage20260429 struct {
+	CoverageTarget *float64
+	TargetStatus   string `gorm:"type:varchar(20)"`
+	TargetGap      *float64
+}
+
+func (coverage20260429) TableName() string {
+	return "_tool_codecov_coverages"
+}
+
+func (script *addFlagCoverageTargets) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &scopeConfig20260429{}, &coverage20260429{})
+}
+
+func (*addFlagCoverageTargets) Version() uint64 {
+	return 20260429000000
+}
+
+func (*addFlagCoverageTargets) Name() string {
+	return "Codecov add flag coverage targets to scope configs and their status to coverages"
+}
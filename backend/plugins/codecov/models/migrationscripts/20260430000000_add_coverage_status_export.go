@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addCoverageStatusExport)(nil)
+
+type addCoverageStatusExport struct{}
+
+type scopeConfig20260430 struct {
+	CoverageDropThreshold  float64
+	CoverageDropWebhookUrl string `gorm:"type:varchar(500)"`
+	PostCommitStatus       bool
+}
+
+func (scopeConfig20260430) TableName() string {
+	return "_tool_codecov_scope_configs"
+}
+
+type connection20260430 struct {
+	GithubToken string `gorm:"type:text;serializer:encdec"`
+}
+
+func (connection20260430) TableName() string {
+	return "_tool_codecov_connections"
+}
+
+type coverageDrop20260430 struct {
+	archived.NoPKModel
+	ConnectionId    uint64     `gorm:"primaryKey;type:bigint"`
+	RepoId          string     `gorm:"primaryKey;type:varchar(200)"`
+	CommitSha       string     `gorm:"primaryKey;type:varchar(64)"`
+	Branch          string     `gorm:"type:varchar(100)"`
+	CommitTimestamp *time.Time `gorm:"index"`
+	Coverage        float64
+	BaseCommitSha   string `gorm:"type:varchar(64)"`
+	BaseCoverage    float64
+	Decrease        float64
+	DetectedAt      time.Time
+	NotifiedAt      *time.Time
+	StatusPostedAt  *time.Time
+}
+
+func (coverageDrop20260430) TableName() string {
+	return "_tool_codecov_coverage_drops"
+}
+
+func (script *addCoverageStatusExport) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(basicRes, &scopeConfig20260430{}, &connection20260430{}, &coverageDrop20260430{})
+}
+
+func (*addCoverageStatusExport) Version() uint64 {
+	return 20260430000000
+}
+
+func (*addCoverageStatusExport) Name() string {
+	return "Codecov add coverage status export settings, the connection GitHub token and the coverage drops table"
+}
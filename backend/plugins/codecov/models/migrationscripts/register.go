@@ -37,5 +37,14 @@ func All() []plugin.MigrationScript {
 		new(addPatchToComparisons),
 		new(addCoverageToFlags),
 		new(addLineCountsToCommitCoverages),
+		new(addFallbackTokenToConnections),
+		new(addAutoEnrollToConnections),
+		new(addMissingUploads),
+		new(addFlagScenarios),
+		new(addServiceToConnections),
+		new(addCommitLinks),
+		new(addFlagCoverageTargets),
+		new(addCoverageStatusExport),
+		new(addAutoEnrollProjectToConnections),
 	}
 }
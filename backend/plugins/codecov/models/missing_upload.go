@@ -0,0 +1,43 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// CodecovMissingUpload is a default-branch commit that had no Codecov report once
+// the grace period of the scope config was over. The record is removed when a
+// report shows up later; NotifiedAt is set once the webhook accepted it.
+type CodecovMissingUpload struct {
+	common.NoPKModel
+	ConnectionId    uint64     `gorm:"primaryKey;type:bigint" json:"connectionId"`
+	RepoId          string     `gorm:"primaryKey;type:varchar(200)" json:"repoId"`
+	CommitSha       string     `gorm:"primaryKey;type:varchar(64)" json:"commitSha"`
+	Branch          string     `gorm:"type:varchar(100)" json:"branch"`
+	CommitTimestamp *time.Time `gorm:"index" json:"commitTimestamp"`
+	Author          string     `gorm:"type:varchar(255)" json:"author"`
+	DetectedAt      time.Time  `json:"detectedAt"`
+	NotifiedAt      *time.Time `json:"notifiedAt"`
+}
+
+func (CodecovMissingUpload) TableName() string {
+	return "_tool_codecov_missing_uploads"
+}
@@ -24,8 +24,54 @@ import (
 
 var _ plugin.ToolLayerScopeConfig = (*CodecovScopeConfig)(nil)
 
+// DefaultMissingUploadGraceHours is the grace period used when MissingUploadGraceHours is 0
+const DefaultMissingUploadGraceHours = 6
+
+// Kinds of testregistry scenario a flag is mapped to
+const (
+	ScenarioKindJob   = "job"   // ci_test_jobs.job_name: Prow job name or Tekton scenario
+	ScenarioKindSuite = "suite" // ci_test_suites.name
+)
+
+// FlagScenarioMapping maps the Codecov flags matching FlagPattern to a testregistry scenario.
+// Scenario may reference capture groups of FlagPattern, e.g. "e2e-$1"; Kind defaults to "job".
+type FlagScenarioMapping struct {
+	FlagPattern string `mapstructure:"flagPattern" json:"flagPattern"`
+	Scenario    string `mapstructure:"scenario" json:"scenario"`
+	Kind        string `mapstructure:"kind" json:"kind"`
+}
+
+// Status of a flag coverage against its FlagCoverageTarget
+const (
+	TargetStatusMet    = "met"
+	TargetStatusMissed = "missed"
+)
+
+// FlagCoverageTarget is the minimum coverage percentage of the Codecov flags matching FlagPattern
+type FlagCoverageTarget struct {
+	FlagPattern string  `mapstructure:"flagPattern" json:"flagPattern"`
+	Target      float64 `mapstructure:"target" json:"target"`
+}
+
+// CodecovScopeConfig configures the missing upload alert: a default-branch commit without a
+// Codecov report MissingUploadGraceHours after it was made is recorded as a missing upload,
+// and MissingUploadWebhookUrl, when set, receives the new ones.
+// FlagScenarioMappings link flags to the testregistry scenarios producing their coverage;
+// the first mapping matching a flag wins. FlagCoverageTargets set the coverage each flag
+// should reach; likewise the first target matching a flag wins.
+// CoverageDropThreshold, when above 0, turns on the coverage status export: a default-branch
+// commit whose overall coverage is more than this many percentage points below the previous
+// commit is recorded as a coverage drop, POSTed to CoverageDropWebhookUrl when set and, with
+// PostCommitStatus, reported as a failed commit status on GitHub with the connection's githubToken.
 type CodecovScopeConfig struct {
-	common.ScopeConfig `mapstructure:",squash" json:",inline" gorm:"embedded"`
+	common.ScopeConfig      `mapstructure:",squash" json:",inline" gorm:"embedded"`
+	MissingUploadGraceHours int                   `mapstructure:"missingUploadGraceHours" json:"missingUploadGraceHours"`
+	MissingUploadWebhookUrl string                `mapstructure:"missingUploadWebhookUrl" json:"missingUploadWebhookUrl" gorm:"type:varchar(500)"`
+	FlagScenarioMappings    []FlagScenarioMapping `mapstructure:"flagScenarioMappings" json:"flagScenarioMappings" gorm:"type:json;serializer:json"`
+	FlagCoverageTargets     []FlagCoverageTarget  `mapstructure:"flagCoverageTargets" json:"flagCoverageTargets" gorm:"type:json;serializer:json"`
+	CoverageDropThreshold   float64               `mapstructure:"coverageDropThreshold" json:"coverageDropThreshold"`
+	CoverageDropWebhookUrl  string                `mapstructure:"coverageDropWebhookUrl" json:"coverageDropWebhookUrl" gorm:"type:varchar(500)"`
+	PostCommitStatus        bool                  `mapstructure:"postCommitStatus" json:"postCommitStatus"`
 }
 
 // GetConnectionId implements plugin.ToolLayerScopeConfig.
@@ -36,4 +82,3 @@ func (sc CodecovScopeConfig) GetConnectionId() uint64 {
 func (CodecovScopeConfig) TableName() string {
 	return "_tool_codecov_scope_configs"
 }
-
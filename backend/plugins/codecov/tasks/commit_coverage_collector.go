@@ -36,6 +36,10 @@ type CommitFlagInput struct {
 	FlagName  string `json:"flag_name"`
 }
 
+// commitCoverageChunkSize is the number of commit × flag totals requested before the collector
+// waits for their responses and logs its progress
+const commitCoverageChunkSize = 500
+
 var CollectCommitCoverageMeta = plugin.SubTaskMeta{
 	Name:             "CollectCommitCoverage",
 	EntryPoint:       CollectCommitCoverage,
@@ -96,8 +100,8 @@ func CollectCommitCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 		collectedSet[key] = true
 	}
 
-	// Build iterator with only NEW commit × flag combinations
-	iterator := helper.NewQueueIterator()
+	// Queue only NEW commit × flag combinations
+	var inputs []*CommitFlagInput
 	skippedCount := 0
 	addedCount := 0
 	for _, commit := range commits {
@@ -108,7 +112,7 @@ func CollectCommitCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 			}
 			key := fmt.Sprintf("%s|%s", commit.CommitSha, flag.FlagName)
 			if !collectedSet[key] {
-				iterator.Push(&CommitFlagInput{
+				inputs = append(inputs, &CommitFlagInput{
 					CommitSha: commit.CommitSha,
 					FlagName:  flag.FlagName,
 				})
@@ -127,6 +131,9 @@ func CollectCommitCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 		return nil
 	}
 
+	iterator := newCommitFlagChunks(inputs, commitCoverageChunkSize, func(done, total int) {
+		logger.Info("[Codecov] CommitCoverage: Collected %d of %d commit flag totals", done, total)
+	})
 	collector, err := helper.NewApiCollector(helper.ApiCollectorArgs{
 		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
 			Ctx: taskCtx,
@@ -139,7 +146,7 @@ func CollectCommitCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 		Incremental: true, // ALWAYS preserve historical data
 		ApiClient:   data.ApiClient,
 		Input:       iterator,
-		UrlTemplate: fmt.Sprintf("api/v2/github/%s/repos/%s/totals/", owner, repo),
+		UrlTemplate: fmt.Sprintf("api/v2/%s/%s/repos/%s/totals/", data.Service, owner, repo),
 		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
 			input := reqData.Input.(*CommitFlagInput)
 			query := url.Values{}
@@ -180,3 +187,70 @@ func CollectCommitCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 
 	return collector.Execute()
 }
+
+// commitFlagChunks hands the commit × flag inputs to the collector one chunk at a time.
+// At the end of each chunk HasNext is false once: the collector then waits for the pending
+// responses and asks again, which reports the progress and opens the next chunk. Large repos
+// with hundreds of flags thus never queue more than a chunk of requests and log how far they are.
+type commitFlagChunks struct {
+	inputs    []*CommitFlagInput
+	chunkSize int
+	next      int  // index of the next input to fetch
+	chunkEnd  int  // index where the current chunk ends
+	draining  bool // the current chunk was handed out, the collector waits for its responses
+	done      bool // the last chunk was reported
+	onChunk   func(done, total int)
+}
+
+func newCommitFlagChunks(inputs []*CommitFlagInput, chunkSize int, onChunk func(done, total int)) *commitFlagChunks {
+	if chunkSize <= 0 {
+		chunkSize = len(inputs)
+	}
+	chunks := &commitFlagChunks{inputs: inputs, chunkSize: chunkSize, onChunk: onChunk}
+	chunks.chunkEnd = chunks.end(0)
+	return chunks
+}
+
+func (c *commitFlagChunks) end(start int) int {
+	if start+c.chunkSize > len(c.inputs) {
+		return len(c.inputs)
+	}
+	return start + c.chunkSize
+}
+
+func (c *commitFlagChunks) HasNext() bool {
+	if c.next < c.chunkEnd {
+		return true
+	}
+	if c.done {
+		return false
+	}
+	if !c.draining {
+		c.draining = true
+		return false
+	}
+	// the collector waited for the responses of the chunk
+	c.draining = false
+	if c.onChunk != nil {
+		c.onChunk(c.next, len(c.inputs))
+	}
+	if c.next >= len(c.inputs) {
+		c.done = true
+		return false
+	}
+	c.chunkEnd = c.end(c.next)
+	return true
+}
+
+func (c *commitFlagChunks) Fetch() (interface{}, errors.Error) {
+	if c.next >= c.chunkEnd {
+		return nil, errors.Default.New("no commit flag input left in the chunk")
+	}
+	input := c.inputs[c.next]
+	c.next++
+	return input, nil
+}
+
+func (c *commitFlagChunks) Close() errors.Error {
+	return nil
+}
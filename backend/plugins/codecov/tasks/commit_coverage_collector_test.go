@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// drainCommitFlagChunks consumes an iterator the way the API collector does, waiting for the
+// pending responses whenever HasNext is false and asking again
+func drainCommitFlagChunks(t *testing.T, chunks *commitFlagChunks) (fetched []string, waits int) {
+	for {
+		if !chunks.HasNext() {
+			waits++
+			if !chunks.HasNext() {
+				return fetched, waits
+			}
+		}
+		input, err := chunks.Fetch()
+		require.Nil(t, err)
+		fetched = append(fetched, input.(*CommitFlagInput).FlagName)
+	}
+}
+
+func TestCommitFlagChunks(t *testing.T) {
+	var inputs []*CommitFlagInput
+	for i := 0; i < 7; i++ {
+		inputs = append(inputs, &CommitFlagInput{CommitSha: "abc", FlagName: fmt.Sprintf("flag-%d", i)})
+	}
+	var progress []string
+	chunks := newCommitFlagChunks(inputs, 3, func(done, total int) {
+		progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+	})
+
+	fetched, waits := drainCommitFlagChunks(t, chunks)
+
+	assert.Len(t, fetched, 7)
+	assert.Equal(t, "flag-6", fetched[6])
+	assert.Equal(t, 3, waits, "the collector waits at the end of each chunk")
+	assert.Equal(t, []string{"3/7", "6/7", "7/7"}, progress)
+	assert.False(t, chunks.HasNext())
+	_, err := chunks.Fetch()
+	assert.NotNil(t, err)
+	assert.Equal(t, []string{"3/7", "6/7", "7/7"}, progress, "the last chunk is reported once")
+}
+
+func TestCommitFlagChunks_SingleChunk(t *testing.T) {
+	inputs := []*CommitFlagInput{{CommitSha: "abc", FlagName: "unit"}, {CommitSha: "def", FlagName: "unit"}}
+	var progress []string
+	chunks := newCommitFlagChunks(inputs, 500, func(done, total int) {
+		progress = append(progress, fmt.Sprintf("%d/%d", done, total))
+	})
+
+	fetched, waits := drainCommitFlagChunks(t, chunks)
+
+	assert.Equal(t, []string{"unit", "unit"}, fetched)
+	assert.Equal(t, 1, waits)
+	assert.Equal(t, []string{"2/2"}, progress)
+}
@@ -0,0 +1,112 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+var ConvertCommitLinksMeta = plugin.SubTaskMeta{
+	Name:             "ConvertCommitLinks",
+	EntryPoint:       ConvertCommitLinks,
+	EnabledByDefault: true,
+	Description:      "Link Codecov commits to the domain commits of the same repo by SHA",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
+	Dependencies:     []*plugin.SubTaskMeta{&ExtractCommitsMeta},
+}
+
+// domainCommitMatch is one row of the Codecov commit to repo_commits join
+type domainCommitMatch struct {
+	CommitSha    string
+	DomainRepoId string
+}
+
+// ConvertCommitLinks rebuilds _tool_codecov_commit_links for the repo. The domain repos
+// of the scope are the ones named "owner/repo" or whose URL ends in "/owner/repo"; a
+// Codecov commit is linked to the first of them whose repo_commits contain its SHA.
+// Commits of repos that were not collected by another plugin stay unlinked.
+func ConvertCommitLinks(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*CodecovTaskData)
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	connectionId, repoId := data.Options.ConnectionId, data.Options.FullName
+
+	err := db.Delete(&models.CodecovCommitLink{}, dal.Where("connection_id = ? AND repo_id = ?", connectionId, repoId))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete previous commit links")
+	}
+
+	var domainRepoIds []string
+	err = db.Pluck("id", &domainRepoIds,
+		dal.From(&code.Repo{}),
+		dal.Where("name = ? OR url LIKE ?", repoId, "%/"+repoId),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to find domain repos")
+	}
+	if len(domainRepoIds) == 0 {
+		logger.Info("[Codecov] No domain repo matches %s, skipping commit links", repoId)
+		return nil
+	}
+
+	var matches []domainCommitMatch
+	err = db.All(&matches,
+		dal.Select("c.commit_sha AS commit_sha, rc.repo_id AS domain_repo_id"),
+		dal.From("_tool_codecov_commits c"),
+		dal.Join("INNER JOIN repo_commits rc ON rc.commit_sha = c.commit_sha"),
+		dal.Where("c.connection_id = ? AND c.repo_id = ? AND rc.repo_id IN ?", connectionId, repoId, domainRepoIds),
+		dal.Orderby("c.commit_sha, rc.repo_id"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to match commits with repo_commits")
+	}
+
+	links := buildCommitLinks(connectionId, repoId, matches)
+	for _, link := range links {
+		if err := db.CreateOrUpdate(link); err != nil {
+			return errors.Default.Wrap(err, "failed to save commit link")
+		}
+	}
+	logger.Info("[Codecov] Linked %d commits of %s to domain repos %v", len(links), repoId, domainRepoIds)
+	return nil
+}
+
+// buildCommitLinks keeps the first domain repo of each SHA; matches are sorted by SHA
+// and domain repo id, so a SHA found in several domain repos (forks) links to the same
+// one on every run
+func buildCommitLinks(connectionId uint64, repoId string, matches []domainCommitMatch) []*models.CodecovCommitLink {
+	links := make([]*models.CodecovCommitLink, 0, len(matches))
+	linked := make(map[string]bool, len(matches))
+	for _, match := range matches {
+		if match.CommitSha == "" || linked[match.CommitSha] {
+			continue
+		}
+		linked[match.CommitSha] = true
+		links = append(links, &models.CodecovCommitLink{
+			ConnectionId: connectionId,
+			RepoId:       repoId,
+			CommitSha:    match.CommitSha,
+			DomainRepoId: match.DomainRepoId,
+		})
+	}
+	return links
+}
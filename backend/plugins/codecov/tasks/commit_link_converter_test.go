@@ -0,0 +1,84 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBuildCommitLinks(t *testing.T) {
+	links := buildCommitLinks(1, "owner/repo", []domainCommitMatch{
+		{CommitSha: "aaa", DomainRepoId: "github:GithubRepo:1:100"},
+		// The same SHA in a fork keeps the first repo
+		{CommitSha: "aaa", DomainRepoId: "github:GithubRepo:1:200"},
+		{CommitSha: "bbb", DomainRepoId: "github:GithubRepo:1:200"},
+		{CommitSha: "", DomainRepoId: "github:GithubRepo:1:100"},
+	})
+
+	assert.Equal(t, []*models.CodecovCommitLink{
+		{ConnectionId: 1, RepoId: "owner/repo", CommitSha: "aaa", DomainRepoId: "github:GithubRepo:1:100"},
+		{ConnectionId: 1, RepoId: "owner/repo", CommitSha: "bbb", DomainRepoId: "github:GithubRepo:1:200"},
+	}, links)
+}
+
+func TestConvertCommitLinks(t *testing.T) {
+	t.Run("links commits found in repo_commits", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+
+		mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+		mockDal.On("Pluck", "id", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]string) = []string{"github:GithubRepo:1:100"}
+		}).Return(nil).Once()
+		mockDal.On("All", mock.AnythingOfType("*[]tasks.domainCommitMatch"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]domainCommitMatch) = []domainCommitMatch{
+				{CommitSha: "aaa", DomainRepoId: "github:GithubRepo:1:100"},
+				{CommitSha: "bbb", DomainRepoId: "github:GithubRepo:1:100"},
+			}
+		}).Return(nil).Once()
+		var saved []*models.CodecovCommitLink
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = append(saved, args.Get(0).(*models.CodecovCommitLink))
+		}).Return(nil).Twice()
+
+		assert.Nil(t, ConvertCommitLinks(mockCtx))
+
+		mockDal.AssertExpectations(t)
+		if assert.Len(t, saved, 2) {
+			assert.Equal(t, "aaa", saved[0].CommitSha)
+			assert.Equal(t, "owner/repo", saved[0].RepoId)
+			assert.Equal(t, "github:GithubRepo:1:100", saved[1].DomainRepoId)
+		}
+	})
+
+	t.Run("no matching domain repo only clears the previous links", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+
+		mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+		mockDal.On("Pluck", "id", mock.Anything, mock.Anything).Return(nil).Once()
+
+		assert.Nil(t, ConvertCommitLinks(mockCtx))
+
+		mockDal.AssertExpectations(t)
+		mockDal.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+		mockDal.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+}
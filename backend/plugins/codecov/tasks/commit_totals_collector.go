@@ -121,7 +121,7 @@ func CollectCommitTotals(taskCtx plugin.SubTaskContext) errors.Error {
 		Incremental: true, // ALWAYS preserve historical data
 		ApiClient:   data.ApiClient,
 		Input:       iterator,
-		UrlTemplate: fmt.Sprintf("api/v2/github/%s/repos/%s/totals/", owner, repo),
+		UrlTemplate: fmt.Sprintf("api/v2/%s/%s/repos/%s/totals/", data.Service, owner, repo),
 		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
 			input := reqData.Input.(*CommitInput)
 			query := url.Values{}
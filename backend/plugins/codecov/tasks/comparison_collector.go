@@ -146,7 +146,7 @@ func CollectComparison(taskCtx plugin.SubTaskContext) errors.Error {
 		Incremental: true, // ALWAYS preserve historical data
 		ApiClient:   data.ApiClient,
 		Input:       iterator,
-		UrlTemplate: fmt.Sprintf("api/v2/github/%s/repos/%s/compare", owner, repo),
+		UrlTemplate: fmt.Sprintf("api/v2/%s/%s/repos/%s/compare", data.Service, owner, repo),
 		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
 			input := reqData.Input.(*ComparisonInput)
 			query := url.Values{}
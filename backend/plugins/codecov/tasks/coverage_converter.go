@@ -41,6 +41,15 @@ var ConvertCoverageMeta = plugin.SubTaskMeta{
 func ConvertCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*CodecovTaskData)
 
+	var targets []models.FlagCoverageTarget
+	if data.Options.ScopeConfig != nil {
+		targets = data.Options.ScopeConfig.FlagCoverageTargets
+	}
+	compiledTargets, err := compileFlagCoverageTargets(targets)
+	if err != nil {
+		return err
+	}
+
 	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
 		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
 			Ctx: taskCtx,
@@ -172,7 +181,7 @@ func ConvertCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 			}
 
 			// Create one coverage record for this flag/commit combination
-			results = append(results, &models.CodecovCoverage{
+			coverage := &models.CodecovCoverage{
 				NoPKModel:          common.NoPKModel{},
 				ConnectionId:       data.Options.ConnectionId,
 				RepoId:             data.Options.FullName,
@@ -190,7 +199,9 @@ func ConvertCoverage(taskCtx plugin.SubTaskContext) errors.Error {
 				Misses:             misses,
 				MethodsCovered:     methodsCovered,
 				MethodsTotal:       methodsTotal,
-			})
+			}
+			applyFlagCoverageTarget(coverage, compiledTargets)
+			results = append(results, coverage)
 
 			return results, nil
 		},
@@ -0,0 +1,255 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+// coverageDropLookbackDays limits the exports to the drops detected in the last days,
+// older drops that could not be exported are not sent again
+const coverageDropLookbackDays = 7
+
+// coverageStatusContext is the context of the GitHub commit statuses posted by the export
+const coverageStatusContext = "devlake/codecov-coverage"
+
+// githubApiUrl is the base URL of the GitHub API the commit statuses are posted to
+var githubApiUrl = "https://api.github.com"
+
+var ExportCoverageStatusMeta = plugin.SubTaskMeta{
+	Name:             "ExportCoverageStatus",
+	EntryPoint:       ExportCoverageStatus,
+	EnabledByDefault: true,
+	Description:      "Record default-branch coverage drops beyond the scope config threshold and report them to the webhook and as GitHub commit statuses",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
+	Dependencies:     []*plugin.SubTaskMeta{&ConvertCommitCoverageMeta},
+}
+
+// coverageDropNotification is the JSON body POSTed to the coverage drop webhook
+type coverageDropNotification struct {
+	Repo      string              `json:"repo"`
+	Branch    string              `json:"branch"`
+	Threshold float64             `json:"threshold"`
+	Drops     []coverageDropEvent `json:"drops"`
+}
+
+type coverageDropEvent struct {
+	Sha          string     `json:"sha"`
+	Coverage     float64    `json:"coverage"`
+	BaseSha      string     `json:"baseSha"`
+	BaseCoverage float64    `json:"baseCoverage"`
+	Decrease     float64    `json:"decrease"`
+	CommittedAt  *time.Time `json:"committedAt"`
+	DetectedAt   time.Time  `json:"detectedAt"`
+}
+
+// githubCommitStatus is the body of the GitHub create commit status API
+type githubCommitStatus struct {
+	State       string `json:"state"`
+	Context     string `json:"context"`
+	Description string `json:"description"`
+}
+
+// ExportCoverageStatus turns the collected coverage into a guardrail. When a default-branch
+// commit with coverage of the last coverageDropLookbackDays is more than coverageDropThreshold
+// percentage points below the last commit with coverage before it, the drop is stored in
+// _tool_codecov_coverage_drops. Drops not
+// exported yet are POSTed to coverageDropWebhookUrl and, with postCommitStatus, reported as a
+// failed GitHub commit status using the connection's githubToken. A failing export is logged
+// and retried on the next run.
+func ExportCoverageStatus(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*CodecovTaskData)
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+
+	scopeConfig := data.Options.ScopeConfig
+	if scopeConfig == nil || scopeConfig.CoverageDropThreshold <= 0 {
+		return nil
+	}
+	if data.Repo == nil || data.Repo.Branch == "" {
+		logger.Info("[Codecov] Default branch of %s unknown, skipping coverage status export", data.Options.FullName)
+		return nil
+	}
+	connectionId, repoId, branch := data.Options.ConnectionId, data.Options.FullName, data.Repo.Branch
+
+	// Every default-branch commit with coverage of the lookback window is compared with the commit
+	// with coverage before it, so a drop followed by another commit before the next run is not missed
+	since := time.Now().AddDate(0, 0, -coverageDropLookbackDays)
+	hasCoverage := dal.Where("connection_id = ? AND repo_id = ? AND branch = ? AND lines_total > 0 AND commit_timestamp IS NOT NULL", connectionId, repoId, branch)
+	var coverages []models.CodecovCommitCoverage
+	err := db.All(&coverages, hasCoverage, dal.Where("commit_timestamp >= ?", since), dal.Orderby("commit_timestamp DESC"))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load the latest commit coverages")
+	}
+	if len(coverages) > 0 {
+		// The oldest commit of the window is compared with the last commit with coverage before it
+		base := &models.CodecovCommitCoverage{}
+		err := db.First(base, hasCoverage, dal.Where("commit_timestamp < ?", since), dal.Orderby("commit_timestamp DESC"))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return errors.Default.Wrap(err, "failed to load the commit coverage before the lookback window")
+		}
+		if err == nil {
+			coverages = append(coverages, *base)
+		}
+	}
+	for _, drop := range detectCoverageDrops(coverages, scopeConfig.CoverageDropThreshold) {
+		existing := &models.CodecovCoverageDrop{}
+		err := db.First(existing, dal.Where("connection_id = ? AND repo_id = ? AND commit_sha = ?", connectionId, repoId, drop.CommitSha))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return errors.Default.Wrap(err, "failed to load coverage drop")
+		}
+		if err != nil {
+			if err := db.CreateOrUpdate(drop); err != nil {
+				return errors.Default.Wrap(err, "failed to save coverage drop")
+			}
+			logger.Warn(nil, "[Codecov] Coverage of %s@%s dropped %.2f points to %.2f%% at commit %s", repoId, branch, drop.Decrease, drop.Coverage, drop.CommitSha)
+		}
+	}
+
+	var drops []models.CodecovCoverageDrop
+	err = db.All(&drops,
+		dal.Where("connection_id = ? AND repo_id = ? AND detected_at >= ? AND (notified_at IS NULL OR status_posted_at IS NULL)",
+			connectionId, repoId, time.Now().AddDate(0, 0, -coverageDropLookbackDays)),
+		dal.Orderby("commit_timestamp"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load coverage drops")
+	}
+
+	now := time.Now()
+	var notify []*models.CodecovCoverageDrop
+	if scopeConfig.CoverageDropWebhookUrl != "" {
+		for i := range drops {
+			if drops[i].NotifiedAt == nil {
+				notify = append(notify, &drops[i])
+			}
+		}
+	}
+	if len(notify) > 0 {
+		notification := &coverageDropNotification{Repo: repoId, Branch: branch, Threshold: scopeConfig.CoverageDropThreshold}
+		for _, drop := range notify {
+			notification.Drops = append(notification.Drops, coverageDropEvent{
+				Sha:          drop.CommitSha,
+				Coverage:     drop.Coverage,
+				BaseSha:      drop.BaseCommitSha,
+				BaseCoverage: drop.BaseCoverage,
+				Decrease:     drop.Decrease,
+				CommittedAt:  drop.CommitTimestamp,
+				DetectedAt:   drop.DetectedAt,
+			})
+		}
+		if err := postJSON(taskCtx.GetContext(), scopeConfig.CoverageDropWebhookUrl, nil, notification, "coverage drop webhook"); err != nil {
+			logger.Warn(err, "[Codecov] Failed to notify %d coverage drops of %s, retrying on the next run", len(notify), repoId)
+		} else {
+			for _, drop := range notify {
+				drop.NotifiedAt = &now
+			}
+		}
+	}
+
+	if scopeConfig.PostCommitStatus {
+		if data.Service != models.DefaultService || data.GithubToken == "" {
+			logger.Warn(nil, "[Codecov] postCommitStatus needs a github connection with a githubToken, no commit status posted for %s", repoId)
+		} else {
+			githubRepo := repoId
+			if data.Repo.FullName != "" {
+				githubRepo = data.Repo.FullName
+			}
+			for i := range drops {
+				drop := &drops[i]
+				if drop.StatusPostedAt != nil {
+					continue
+				}
+				if err := postCoverageStatus(taskCtx.GetContext(), data.GithubToken, githubRepo, drop); err != nil {
+					logger.Warn(err, "[Codecov] Failed to post the coverage status of %s@%s, retrying on the next run", repoId, drop.CommitSha)
+					continue
+				}
+				drop.StatusPostedAt = &now
+			}
+		}
+	}
+
+	for i := range drops {
+		if drops[i].NotifiedAt == nil && drops[i].StatusPostedAt == nil {
+			continue
+		}
+		if err := db.Update(&drops[i]); err != nil {
+			return errors.Default.Wrap(err, "failed to mark coverage drop as exported")
+		}
+	}
+	return nil
+}
+
+// detectCoverageDrops compares each commit coverage, newest first, with the next one, the
+// previous commit with coverage, and returns the drops beyond threshold percentage points
+func detectCoverageDrops(coverages []models.CodecovCommitCoverage, threshold float64) []*models.CodecovCoverageDrop {
+	var drops []*models.CodecovCoverageDrop
+	for i := 0; i+1 < len(coverages); i++ {
+		if drop := detectCoverageDrop(&coverages[i], &coverages[i+1], threshold); drop != nil {
+			drops = append(drops, drop)
+		}
+	}
+	return drops
+}
+
+// detectCoverageDrop returns the drop of latest against base when it exceeds threshold
+// percentage points, nil otherwise
+func detectCoverageDrop(latest, base *models.CodecovCommitCoverage, threshold float64) *models.CodecovCoverageDrop {
+	decrease := base.OverallCoverage - latest.OverallCoverage
+	if decrease <= threshold {
+		return nil
+	}
+	return &models.CodecovCoverageDrop{
+		ConnectionId:    latest.ConnectionId,
+		RepoId:          latest.RepoId,
+		CommitSha:       latest.CommitSha,
+		Branch:          latest.Branch,
+		CommitTimestamp: latest.CommitTimestamp,
+		Coverage:        latest.OverallCoverage,
+		BaseCommitSha:   base.CommitSha,
+		BaseCoverage:    base.OverallCoverage,
+		Decrease:        decrease,
+		DetectedAt:      time.Now(),
+	}
+}
+
+// postCoverageStatus reports the drop as a failed commit status of the GitHub repo "owner/name"
+func postCoverageStatus(ctx context.Context, token, githubRepo string, drop *models.CodecovCoverageDrop) errors.Error {
+	baseSha := drop.BaseCommitSha
+	if len(baseSha) > 7 {
+		baseSha = baseSha[:7]
+	}
+	status := &githubCommitStatus{
+		State:       "failure",
+		Context:     coverageStatusContext,
+		Description: fmt.Sprintf("Coverage dropped %.2f%% to %.2f%% (compared to %s)", drop.Decrease, drop.Coverage, baseSha),
+	}
+	url := fmt.Sprintf("%s/repos/%s/statuses/%s", githubApiUrl, githubRepo, drop.CommitSha)
+	headers := map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/vnd.github+json",
+	}
+	return postJSON(ctx, url, headers, status, "GitHub commit status API")
+}
@@ -0,0 +1,176 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDetectCoverageDrop(t *testing.T) {
+	committedAt := time.Date(2026, 4, 30, 10, 0, 0, 0, time.UTC)
+	base := &models.CodecovCommitCoverage{CommitSha: "base", OverallCoverage: 80}
+	latest := &models.CodecovCommitCoverage{
+		ConnectionId: 1, RepoId: "owner/repo", CommitSha: "latest", Branch: "main",
+		CommitTimestamp: &committedAt, OverallCoverage: 77.5,
+	}
+
+	drop := detectCoverageDrop(latest, base, 2)
+	if assert.NotNil(t, drop) {
+		assert.Equal(t, "latest", drop.CommitSha)
+		assert.Equal(t, "owner/repo", drop.RepoId)
+		assert.Equal(t, "base", drop.BaseCommitSha)
+		assert.Equal(t, 77.5, drop.Coverage)
+		assert.Equal(t, 80.0, drop.BaseCoverage)
+		assert.Equal(t, 2.5, drop.Decrease)
+	}
+	assert.Nil(t, detectCoverageDrop(latest, base, 2.5), "a drop equal to the threshold is tolerated")
+	assert.Nil(t, detectCoverageDrop(base, latest, 2), "a coverage increase is not a drop")
+}
+
+func TestDetectCoverageDrops(t *testing.T) {
+	// Newest first: the drop at "dropped" is followed by a commit that keeps the coverage level
+	coverages := []models.CodecovCommitCoverage{
+		{CommitSha: "latest", OverallCoverage: 75.5},
+		{CommitSha: "dropped", OverallCoverage: 76},
+		{CommitSha: "base", OverallCoverage: 80},
+	}
+	drops := detectCoverageDrops(coverages, 2)
+	if assert.Len(t, drops, 1) {
+		assert.Equal(t, "dropped", drops[0].CommitSha)
+		assert.Equal(t, "base", drops[0].BaseCommitSha)
+		assert.Equal(t, 4.0, drops[0].Decrease)
+	}
+	assert.Empty(t, detectCoverageDrops(coverages[:1], 2))
+	assert.Empty(t, detectCoverageDrops(nil, 2))
+}
+
+func TestExportCoverageStatus(t *testing.T) {
+	committedAt := time.Now().Add(-time.Hour)
+	coverages := []models.CodecovCommitCoverage{
+		{ConnectionId: 1, RepoId: "owner/repo", CommitSha: "latest1234", Branch: "main", CommitTimestamp: &committedAt, OverallCoverage: 70},
+		{ConnectionId: 1, RepoId: "owner/repo", CommitSha: "base567890", Branch: "main", OverallCoverage: 75},
+	}
+	errNotFound := errors.NotFound.New("not found")
+
+	t.Run("records the drop, notifies the webhook and posts the commit status", func(t *testing.T) {
+		var notified coverageDropNotification
+		var status githubCommitStatus
+		var statusPath, authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/webhook" {
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&notified))
+				return
+			}
+			statusPath, authorization = r.URL.Path, r.Header.Get("Authorization")
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&status))
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+		defer func(url string) { githubApiUrl = url }(githubApiUrl)
+		githubApiUrl = server.URL
+
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Repo = &models.CodecovRepo{Branch: "main", FullName: "owner/repo"}
+		data.Service = models.DefaultService
+		data.GithubToken = "ghp_token"
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{
+			CoverageDropThreshold:  2,
+			CoverageDropWebhookUrl: server.URL + "/webhook",
+			PostCommitStatus:       true,
+		}
+
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovCommitCoverage"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCommitCoverage) = coverages
+		}).Return(nil)
+		mockDal.On("First", mock.Anything, mock.Anything).Return(errNotFound)
+		mockDal.On("IsErrorNotFound", errNotFound).Return(true)
+		var saved *models.CodecovCoverageDrop
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*models.CodecovCoverageDrop)
+		}).Return(nil).Once()
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovCoverageDrop"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCoverageDrop) = []models.CodecovCoverageDrop{*saved}
+		}).Return(nil)
+		var updated *models.CodecovCoverageDrop
+		mockDal.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			updated = args.Get(0).(*models.CodecovCoverageDrop)
+		}).Return(nil).Once()
+
+		assert.Nil(t, ExportCoverageStatus(mockCtx))
+
+		mockDal.AssertExpectations(t)
+		assert.Equal(t, "latest1234", saved.CommitSha)
+		assert.Equal(t, 5.0, saved.Decrease)
+		assert.Equal(t, 2.0, notified.Threshold)
+		if assert.Len(t, notified.Drops, 1) {
+			assert.Equal(t, "base567890", notified.Drops[0].BaseSha)
+		}
+		assert.Equal(t, "/repos/owner/repo/statuses/latest1234", statusPath)
+		assert.Equal(t, "Bearer ghp_token", authorization)
+		assert.Equal(t, "failure", status.State)
+		assert.Equal(t, coverageStatusContext, status.Context)
+		assert.Equal(t, "Coverage dropped 5.00% to 70.00% (compared to base567)", status.Description)
+		assert.NotNil(t, updated.NotifiedAt)
+		assert.NotNil(t, updated.StatusPostedAt)
+	})
+
+	t.Run("export failures leave the drop pending", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Repo = &models.CodecovRepo{Branch: "main"}
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{CoverageDropThreshold: 2, CoverageDropWebhookUrl: server.URL}
+
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovCommitCoverage"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCommitCoverage) = coverages
+		}).Return(nil)
+		mockDal.On("First", mock.Anything, mock.Anything).Return(nil)
+		mockDal.On("IsErrorNotFound", mock.Anything).Return(false)
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovCoverageDrop"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCoverageDrop) = []models.CodecovCoverageDrop{{CommitSha: "latest1234", DetectedAt: time.Now()}}
+		}).Return(nil)
+
+		assert.Nil(t, ExportCoverageStatus(mockCtx))
+		mockDal.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+		mockDal.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("no threshold disables the export", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Repo = &models.CodecovRepo{Branch: "main"}
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{CoverageDropWebhookUrl: "http://localhost/webhook"}
+
+		assert.Nil(t, ExportCoverageStatus(mockCtx))
+		mockDal.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+// compiledFlagCoverageTarget is a FlagCoverageTarget with its compiled pattern
+type compiledFlagCoverageTarget struct {
+	models.FlagCoverageTarget
+	re *regexp.Regexp
+}
+
+// compileFlagCoverageTargets validates the targets and compiles their flag patterns
+func compileFlagCoverageTargets(targets []models.FlagCoverageTarget) ([]compiledFlagCoverageTarget, errors.Error) {
+	compiled := make([]compiledFlagCoverageTarget, 0, len(targets))
+	for i, target := range targets {
+		if target.FlagPattern == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("flagCoverageTargets[%d] needs a flagPattern", i))
+		}
+		if target.Target < 0 || target.Target > 100 {
+			return nil, errors.BadInput.New(fmt.Sprintf("flagCoverageTargets[%d] target must be between 0 and 100, got %v", i, target.Target))
+		}
+		re, err := regexp.Compile(target.FlagPattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid flagCoverageTargets[%d] flagPattern", i))
+		}
+		compiled = append(compiled, compiledFlagCoverageTarget{FlagCoverageTarget: target, re: re})
+	}
+	return compiled, nil
+}
+
+// applyFlagCoverageTarget sets the target, status and gap of a flag coverage from the first
+// target matching its flag, and leaves them empty when none matches
+func applyFlagCoverageTarget(coverage *models.CodecovCoverage, targets []compiledFlagCoverageTarget) {
+	for _, target := range targets {
+		if !target.re.MatchString(coverage.FlagName) {
+			continue
+		}
+		value := target.Target
+		gap := coverage.CoveragePercentage - value
+		coverage.CoverageTarget = &value
+		coverage.TargetGap = &gap
+		coverage.TargetStatus = models.TargetStatusMissed
+		if gap >= 0 {
+			coverage.TargetStatus = models.TargetStatusMet
+		}
+		return
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileFlagCoverageTargets(t *testing.T) {
+	compiled, err := compileFlagCoverageTargets([]models.FlagCoverageTarget{
+		{FlagPattern: "^unit$", Target: 80},
+		{FlagPattern: "^e2e-", Target: 0},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, compiled, 2)
+
+	_, err = compileFlagCoverageTargets([]models.FlagCoverageTarget{{Target: 80}})
+	assert.NotNil(t, err)
+	_, err = compileFlagCoverageTargets([]models.FlagCoverageTarget{{FlagPattern: "^unit$", Target: 101}})
+	assert.NotNil(t, err)
+	_, err = compileFlagCoverageTargets([]models.FlagCoverageTarget{{FlagPattern: "(", Target: 80}})
+	assert.NotNil(t, err)
+}
+
+func TestApplyFlagCoverageTarget(t *testing.T) {
+	compiled, err := compileFlagCoverageTargets([]models.FlagCoverageTarget{
+		{FlagPattern: "^unit$", Target: 80},
+		{FlagPattern: "^e2e-", Target: 60},
+		{FlagPattern: "^e2e-ocp$", Target: 99},
+	})
+	assert.Nil(t, err)
+
+	missed := &models.CodecovCoverage{FlagName: "unit", CoveragePercentage: 78.5}
+	applyFlagCoverageTarget(missed, compiled)
+	assert.Equal(t, models.TargetStatusMissed, missed.TargetStatus)
+	if assert.NotNil(t, missed.CoverageTarget) && assert.NotNil(t, missed.TargetGap) {
+		assert.Equal(t, 80.0, *missed.CoverageTarget)
+		assert.InDelta(t, -1.5, *missed.TargetGap, 0.0001)
+	}
+
+	// the first matching target wins and reaching the target exactly meets it
+	met := &models.CodecovCoverage{FlagName: "e2e-ocp", CoveragePercentage: 60}
+	applyFlagCoverageTarget(met, compiled)
+	assert.Equal(t, models.TargetStatusMet, met.TargetStatus)
+	if assert.NotNil(t, met.TargetGap) {
+		assert.Equal(t, 0.0, *met.TargetGap)
+	}
+
+	untargeted := &models.CodecovCoverage{FlagName: "integration", CoveragePercentage: 50}
+	applyFlagCoverageTarget(untargeted, compiled)
+	assert.Empty(t, untargeted.TargetStatus)
+	assert.Nil(t, untargeted.CoverageTarget)
+	assert.Nil(t, untargeted.TargetGap)
+}
@@ -0,0 +1,132 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+var MapFlagScenariosMeta = plugin.SubTaskMeta{
+	Name:             "MapFlagScenarios",
+	EntryPoint:       MapFlagScenarios,
+	EnabledByDefault: true,
+	Description:      "Link flags to the testregistry scenarios producing their coverage using the scope config flag scenario mappings",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
+	Dependencies:     []*plugin.SubTaskMeta{&ConvertFlagsMeta},
+}
+
+// compiledFlagScenarioMapping is a FlagScenarioMapping with its compiled pattern
+type compiledFlagScenarioMapping struct {
+	models.FlagScenarioMapping
+	re *regexp.Regexp
+}
+
+// MapFlagScenarios rebuilds _tool_codecov_flag_scenarios for the repo: every flag that
+// is not deleted is matched against the scope config's flag scenario mappings and the
+// first match links it to a testregistry scenario. The testregistry tables are only
+// joined by name at query time, the plugin doesn't need to be installed.
+func MapFlagScenarios(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*CodecovTaskData)
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	connectionId, repoId := data.Options.ConnectionId, data.Options.FullName
+
+	var mappings []models.FlagScenarioMapping
+	if data.Options.ScopeConfig != nil {
+		mappings = data.Options.ScopeConfig.FlagScenarioMappings
+	}
+	compiled, err := compileFlagScenarioMappings(mappings)
+	if err != nil {
+		return err
+	}
+
+	var flags []models.CodecovFlag
+	err = db.All(&flags, dal.Where("connection_id = ? AND repo_id = ? AND deleted = ?", connectionId, repoId, false), dal.Orderby("flag_name"))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load flags")
+	}
+
+	err = db.Delete(&models.CodecovFlagScenario{}, dal.Where("connection_id = ? AND repo_id = ?", connectionId, repoId))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete previous flag scenarios")
+	}
+	mapped := 0
+	for _, flag := range flags {
+		flagScenario := mapFlagScenario(flag, compiled)
+		if flagScenario == nil {
+			continue
+		}
+		if err := db.CreateOrUpdate(flagScenario); err != nil {
+			return errors.Default.Wrap(err, "failed to save flag scenario")
+		}
+		mapped++
+	}
+	logger.Info("[Codecov] Mapped %d of %d flags of %s to testregistry scenarios", mapped, len(flags), repoId)
+	return nil
+}
+
+// compileFlagScenarioMappings validates the mappings and compiles their flag patterns
+func compileFlagScenarioMappings(mappings []models.FlagScenarioMapping) ([]compiledFlagScenarioMapping, errors.Error) {
+	compiled := make([]compiledFlagScenarioMapping, 0, len(mappings))
+	for i, mapping := range mappings {
+		if mapping.FlagPattern == "" || mapping.Scenario == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("flagScenarioMappings[%d] needs a flagPattern and a scenario", i))
+		}
+		if mapping.Kind == "" {
+			mapping.Kind = models.ScenarioKindJob
+		}
+		if mapping.Kind != models.ScenarioKindJob && mapping.Kind != models.ScenarioKindSuite {
+			return nil, errors.BadInput.New(fmt.Sprintf("flagScenarioMappings[%d] kind must be job or suite, got %q", i, mapping.Kind))
+		}
+		re, err := regexp.Compile(mapping.FlagPattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid flagScenarioMappings[%d] flagPattern", i))
+		}
+		compiled = append(compiled, compiledFlagScenarioMapping{FlagScenarioMapping: mapping, re: re})
+	}
+	return compiled, nil
+}
+
+// mapFlagScenario links a flag to the scenario of the first matching mapping, nil when none matches
+func mapFlagScenario(flag models.CodecovFlag, mappings []compiledFlagScenarioMapping) *models.CodecovFlagScenario {
+	for _, mapping := range mappings {
+		match := mapping.re.FindStringSubmatchIndex(flag.FlagName)
+		if match == nil {
+			continue
+		}
+		scenario := string(mapping.re.ExpandString(nil, mapping.Scenario, flag.FlagName, match))
+		if scenario == "" {
+			continue
+		}
+		return &models.CodecovFlagScenario{
+			ConnectionId: flag.ConnectionId,
+			RepoId:       flag.RepoId,
+			FlagName:     flag.FlagName,
+			Scenario:     scenario,
+			ScenarioKind: mapping.Kind,
+			FlagPattern:  mapping.FlagPattern,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCompileFlagScenarioMappings(t *testing.T) {
+	compiled, err := compileFlagScenarioMappings([]models.FlagScenarioMapping{
+		{FlagPattern: "^e2e-(.+)$", Scenario: "pull-ci-$1"},
+		{FlagPattern: "^unit$", Scenario: "unit-tests", Kind: models.ScenarioKindSuite},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, compiled, 2)
+	assert.Equal(t, models.ScenarioKindJob, compiled[0].Kind)
+	assert.Equal(t, models.ScenarioKindSuite, compiled[1].Kind)
+
+	_, err = compileFlagScenarioMappings([]models.FlagScenarioMapping{{FlagPattern: "^unit$"}})
+	assert.NotNil(t, err)
+	_, err = compileFlagScenarioMappings([]models.FlagScenarioMapping{{FlagPattern: "^unit$", Scenario: "unit", Kind: "case"}})
+	assert.NotNil(t, err)
+	_, err = compileFlagScenarioMappings([]models.FlagScenarioMapping{{FlagPattern: "(", Scenario: "unit"}})
+	assert.NotNil(t, err)
+}
+
+func TestMapFlagScenario(t *testing.T) {
+	compiled, err := compileFlagScenarioMappings([]models.FlagScenarioMapping{
+		{FlagPattern: "^e2e-(.+)$", Scenario: "pull-ci-$1"},
+		{FlagPattern: "^e2e", Scenario: "never-reached"},
+		{FlagPattern: "^unit$", Scenario: "unit-tests", Kind: models.ScenarioKindSuite},
+		{FlagPattern: "^(?P<name>integration)?-only$", Scenario: "${name}"},
+	})
+	assert.Nil(t, err)
+
+	flagScenario := mapFlagScenario(models.CodecovFlag{ConnectionId: 1, RepoId: "owner/repo", FlagName: "e2e-ocp"}, compiled)
+	if assert.NotNil(t, flagScenario) {
+		assert.Equal(t, "pull-ci-ocp", flagScenario.Scenario)
+		assert.Equal(t, models.ScenarioKindJob, flagScenario.ScenarioKind)
+		assert.Equal(t, "^e2e-(.+)$", flagScenario.FlagPattern)
+		assert.Equal(t, "owner/repo", flagScenario.RepoId)
+	}
+
+	flagScenario = mapFlagScenario(models.CodecovFlag{FlagName: "unit"}, compiled)
+	if assert.NotNil(t, flagScenario) {
+		assert.Equal(t, "unit-tests", flagScenario.Scenario)
+		assert.Equal(t, models.ScenarioKindSuite, flagScenario.ScenarioKind)
+	}
+
+	// An empty expansion doesn't link the flag
+	assert.Nil(t, mapFlagScenario(models.CodecovFlag{FlagName: "-only"}, compiled))
+	assert.Nil(t, mapFlagScenario(models.CodecovFlag{FlagName: "lint"}, compiled))
+}
+
+func TestMapFlagScenarios(t *testing.T) {
+	t.Run("saves the mapped flags", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{
+			FlagScenarioMappings: []models.FlagScenarioMapping{{FlagPattern: "^e2e-(.+)$", Scenario: "pull-ci-$1"}},
+		}
+
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovFlag"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovFlag) = []models.CodecovFlag{
+				{ConnectionId: 1, RepoId: "owner/repo", FlagName: "e2e-ocp"},
+				{ConnectionId: 1, RepoId: "owner/repo", FlagName: "unit"},
+			}
+		}).Return(nil)
+		mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+		var saved *models.CodecovFlagScenario
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*models.CodecovFlagScenario)
+		}).Return(nil).Once()
+
+		assert.Nil(t, MapFlagScenarios(mockCtx))
+
+		mockDal.AssertExpectations(t)
+		assert.Equal(t, "e2e-ocp", saved.FlagName)
+		assert.Equal(t, "pull-ci-ocp", saved.Scenario)
+	})
+
+	t.Run("no mappings clears the previous links", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovFlag"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovFlag) = []models.CodecovFlag{{FlagName: "unit"}}
+		}).Return(nil)
+		mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Once()
+
+		assert.Nil(t, MapFlagScenarios(mockCtx))
+
+		mockDal.AssertExpectations(t)
+		mockDal.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("invalid mapping fails before touching the table", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{
+			FlagScenarioMappings: []models.FlagScenarioMapping{{FlagPattern: "(", Scenario: "unit"}},
+		}
+
+		assert.NotNil(t, MapFlagScenarios(mockCtx))
+		mockDal.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
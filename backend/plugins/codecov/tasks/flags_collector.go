@@ -21,22 +21,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 )
 
+// flagsPageSize is the largest page size the Codecov API accepts
+const flagsPageSize = 100
+
 var CollectFlagsMeta = plugin.SubTaskMeta{
 	Name:             "CollectFlags",
 	EntryPoint:       CollectFlags,
 	EnabledByDefault: true,
-	Description:      "Collect flags data from Codecov API",
+	Description:      "Collect flags data from Codecov API, following all the pages of the flags list",
 	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
 }
 
 func CollectFlags(taskCtx plugin.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*CodecovTaskData)
+	logger := taskCtx.GetLogger()
 
 	// Extract owner and repo from FullName (format: "owner/repo")
 	owner, repo, err := ParseFullName(data.Options.FullName)
@@ -55,16 +60,44 @@ func CollectFlags(taskCtx plugin.SubTaskContext) errors.Error {
 		},
 		Incremental: true, // ALWAYS preserve historical data
 		ApiClient:   data.ApiClient,
-		UrlTemplate: fmt.Sprintf("api/v2/github/%s/repos/%s/flags", owner, repo),
+		UrlTemplate: fmt.Sprintf("api/v2/%s/%s/repos/%s/flags", data.Service, owner, repo),
+		PageSize:    flagsPageSize,
+		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
+			query := url.Values{}
+			query.Set("page", fmt.Sprintf("%d", reqData.Pager.Page))
+			query.Set("page_size", fmt.Sprintf("%d", flagsPageSize))
+			return query, nil
+		},
+		GetTotalPages: func(res *http.Response, args *helper.ApiCollectorArgs) (int, errors.Error) {
+			page, err := parseFlagsPage(res)
+			if err != nil {
+				return 0, err
+			}
+			totalPages, err := flagsTotalPages(page)
+			if err == nil {
+				logger.Info("[Codecov] %s has %d flags on %d pages", data.Options.FullName, page.Count, totalPages)
+			}
+			return totalPages, err
+		},
 		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
-			var response struct {
-				Results []json.RawMessage `json:"results"`
+			// A page past the end, when flags were deleted while paging
+			if res.StatusCode == http.StatusNotFound {
+				return []json.RawMessage{}, nil
 			}
-			err := helper.UnmarshalResponse(res, &response)
+			page, err := parseFlagsPage(res)
 			if err != nil {
 				return nil, err
 			}
-			return response.Results, nil
+			return page.Results, nil
+		},
+		AfterResponse: func(res *http.Response) errors.Error {
+			if res.StatusCode == http.StatusUnauthorized {
+				return errors.Unauthorized.New("authentication failed, please check your AccessToken")
+			}
+			if res.StatusCode == http.StatusNotFound {
+				return helper.ErrIgnoreAndContinue
+			}
+			return nil
 		},
 	})
 
@@ -75,3 +108,41 @@ func CollectFlags(taskCtx plugin.SubTaskContext) errors.Error {
 	return collector.Execute()
 }
 
+// flagsPage is a page of the Codecov flags list
+type flagsPage struct {
+	Count      int               `json:"count"`
+	Next       *string           `json:"next"`
+	TotalPages int               `json:"total_pages"`
+	Results    []json.RawMessage `json:"results"`
+}
+
+func parseFlagsPage(res *http.Response) (*flagsPage, errors.Error) {
+	page := &flagsPage{}
+	err := helper.UnmarshalResponse(res, page)
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// flagsTotalPages returns the number of pages of the flags list from its first page.
+// The page count is derived from the flag count and the size of the first page when the
+// API leaves out total_pages or reports fewer pages than that (the server may cap page_size).
+// A first page with a next link but no paging information is undetermined, so the
+// collector keeps fetching pages until one comes back short.
+func flagsTotalPages(first *flagsPage) (int, errors.Error) {
+	totalPages := first.TotalPages
+	if first.Count > 0 && len(first.Results) > 0 {
+		derived := (first.Count + len(first.Results) - 1) / len(first.Results)
+		if derived > totalPages {
+			totalPages = derived
+		}
+	}
+	if first.Next != nil && totalPages < 2 {
+		return 0, helper.ErrUndetermined
+	}
+	if totalPages < 1 {
+		return 1, nil
+	}
+	return totalPages, nil
+}
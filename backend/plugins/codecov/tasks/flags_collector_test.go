@@ -0,0 +1,112 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flagsPageFixture is a page of the flags list of a repo with 230 flags, 100 per page
+func flagsPageFixture(page, totalPages, count int, names ...string) string {
+	next := "null"
+	if page < totalPages {
+		next = fmt.Sprintf(`"https://api.codecov.io/api/v2/github/konflux-ci/repos/build-service/flags?page=%d&page_size=100"`, page+1)
+	}
+	results := make([]string, 0, len(names))
+	for _, name := range names {
+		results = append(results, fmt.Sprintf(`{"flag_name": %q, "coverage": 80.5, "carryforward": false, "deleted": false}`, name))
+	}
+	return fmt.Sprintf(`{"count": %d, "next": %s, "previous": null, "results": [%s], "total_pages": %d}`,
+		count, next, strings.Join(results, ","), totalPages)
+}
+
+func flagNames(from, to int) []string {
+	names := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		names = append(names, fmt.Sprintf("e2e-%03d", i))
+	}
+	return names
+}
+
+func jsonResponse(body string) *http.Response {
+	req := httptest.NewRequest(http.MethodGet, "https://api.codecov.io/api/v2/github/konflux-ci/repos/build-service/flags", nil)
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Request: req}
+}
+
+func TestParseFlagsPage(t *testing.T) {
+	pages := []string{
+		flagsPageFixture(1, 3, 230, flagNames(0, 100)...),
+		flagsPageFixture(2, 3, 230, flagNames(100, 200)...),
+		flagsPageFixture(3, 3, 230, flagNames(200, 230)...),
+	}
+	collected := map[string]bool{}
+	for i, body := range pages {
+		page, err := parseFlagsPage(jsonResponse(body))
+		require.Nil(t, err)
+		assert.Equal(t, 230, page.Count)
+		assert.Equal(t, i < 2, page.Next != nil)
+		for _, result := range page.Results {
+			collected[string(result)] = true
+		}
+	}
+	assert.Len(t, collected, 230)
+
+	_, err := parseFlagsPage(jsonResponse("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestFlagsTotalPages(t *testing.T) {
+	next := "https://api.codecov.io/api/v2/github/konflux-ci/repos/build-service/flags?page=2"
+	first, err := parseFlagsPage(jsonResponse(flagsPageFixture(1, 3, 230, flagNames(0, 100)...)))
+	require.Nil(t, err)
+
+	tests := []struct {
+		name     string
+		page     *flagsPage
+		expected int
+		err      error
+	}{
+		{name: "total pages reported", page: first, expected: 3},
+		{name: "single page", page: &flagsPage{Count: 12, Results: first.Results[:12], TotalPages: 1}, expected: 1},
+		{name: "no flags", page: &flagsPage{}, expected: 1},
+		{name: "total pages missing", page: &flagsPage{Count: 230, Next: &next, Results: first.Results}, expected: 3},
+		{name: "page size capped by the server", page: &flagsPage{Count: 230, Next: &next, Results: first.Results[:20], TotalPages: 3}, expected: 12},
+		{name: "last page full", page: &flagsPage{Count: 200, Next: &next, Results: first.Results, TotalPages: 2}, expected: 2},
+		{name: "no paging information", page: &flagsPage{Next: &next, Results: first.Results}, err: helper.ErrUndetermined},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totalPages, err := flagsTotalPages(tt.page)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, totalPages)
+		})
+	}
+}
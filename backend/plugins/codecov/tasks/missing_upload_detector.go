@@ -0,0 +1,205 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+// missingUploadLookbackDays limits the check to commits made in the last days before
+// the grace period, older commits without a report are not reported again
+const missingUploadLookbackDays = 7
+
+var DetectMissingUploadsMeta = plugin.SubTaskMeta{
+	Name:             "DetectMissingUploads",
+	EntryPoint:       DetectMissingUploads,
+	EnabledByDefault: true,
+	Description:      "Record default-branch commits without a Codecov report after the grace period and notify the webhook",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
+	Dependencies:     []*plugin.SubTaskMeta{&ConvertCommitCoverageMeta},
+}
+
+// missingUploadNotification is the JSON body POSTed to the missing upload webhook
+type missingUploadNotification struct {
+	Repo       string                `json:"repo"`
+	Branch     string                `json:"branch"`
+	GraceHours int                   `json:"graceHours"`
+	Commits    []missingUploadCommit `json:"commits"`
+}
+
+type missingUploadCommit struct {
+	Sha         string     `json:"sha"`
+	Author      string     `json:"author"`
+	CommittedAt *time.Time `json:"committedAt"`
+	DetectedAt  time.Time  `json:"detectedAt"`
+}
+
+// DetectMissingUploads compares the recent default-branch commits with the commit
+// coverages: a commit without coverage (no totals or zero lines) once the grace
+// period is over is stored in _tool_codecov_missing_uploads. Records whose report
+// arrived since are removed. Events not notified yet are sent to the webhook of
+// the scope config, a failing webhook is retried on the next run.
+func DetectMissingUploads(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*CodecovTaskData)
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+
+	if data.Repo == nil || data.Repo.Branch == "" {
+		logger.Info("[Codecov] Default branch of %s unknown, skipping missing upload detection", data.Options.FullName)
+		return nil
+	}
+	connectionId, repoId, branch := data.Options.ConnectionId, data.Options.FullName, data.Repo.Branch
+	graceHours := missingUploadGraceHours(data.Options.ScopeConfig)
+
+	// Late uploads resolve earlier events
+	err := db.Delete(&models.CodecovMissingUpload{}, dal.Where(
+		"connection_id = ? AND repo_id = ? AND commit_sha IN (SELECT commit_sha FROM _tool_codecov_commit_coverages WHERE connection_id = ? AND repo_id = ? AND lines_total > 0)",
+		connectionId, repoId, connectionId, repoId,
+	))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete resolved missing uploads")
+	}
+
+	until := time.Now().Add(-time.Duration(graceHours) * time.Hour)
+	since := until.AddDate(0, 0, -missingUploadLookbackDays)
+	var commits []models.CodecovCommit
+	err = db.All(&commits,
+		dal.Select("c.*"),
+		dal.From("_tool_codecov_commits c"),
+		dal.Join("LEFT JOIN _tool_codecov_commit_coverages cc ON cc.connection_id = c.connection_id AND cc.repo_id = c.repo_id AND cc.commit_sha = c.commit_sha"),
+		dal.Where("c.connection_id = ? AND c.repo_id = ? AND c.branch = ? AND c.commit_timestamp BETWEEN ? AND ? AND (cc.commit_sha IS NULL OR cc.lines_total = 0)",
+			connectionId, repoId, branch, since, until),
+		dal.Orderby("c.commit_timestamp"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to find commits without coverage")
+	}
+
+	var existing []models.CodecovMissingUpload
+	err = db.All(&existing, dal.Where("connection_id = ? AND repo_id = ?", connectionId, repoId))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load missing uploads")
+	}
+	events := make(map[string]*models.CodecovMissingUpload, len(existing))
+	for i := range existing {
+		events[existing[i].CommitSha] = &existing[i]
+	}
+
+	now := time.Now()
+	var pending []*models.CodecovMissingUpload
+	for _, commit := range commits {
+		event, ok := events[commit.CommitSha]
+		if !ok {
+			event = &models.CodecovMissingUpload{
+				ConnectionId:    connectionId,
+				RepoId:          repoId,
+				CommitSha:       commit.CommitSha,
+				Branch:          commit.Branch,
+				CommitTimestamp: commit.CommitTimestamp,
+				Author:          commit.Author,
+				DetectedAt:      now,
+			}
+			if err := db.CreateOrUpdate(event); err != nil {
+				return errors.Default.Wrap(err, "failed to save missing upload")
+			}
+			logger.Warn(nil, "[Codecov] No coverage uploaded for %s@%s %dh after commit %s", repoId, branch, graceHours, commit.CommitSha)
+		}
+		if event.NotifiedAt == nil {
+			pending = append(pending, event)
+		}
+	}
+
+	webhookUrl := ""
+	if data.Options.ScopeConfig != nil {
+		webhookUrl = data.Options.ScopeConfig.MissingUploadWebhookUrl
+	}
+	if webhookUrl == "" || len(pending) == 0 {
+		return nil
+	}
+	notification := &missingUploadNotification{Repo: repoId, Branch: branch, GraceHours: graceHours}
+	for _, event := range pending {
+		notification.Commits = append(notification.Commits, missingUploadCommit{
+			Sha:         event.CommitSha,
+			Author:      event.Author,
+			CommittedAt: event.CommitTimestamp,
+			DetectedAt:  event.DetectedAt,
+		})
+	}
+	if err := notifyMissingUploads(taskCtx.GetContext(), webhookUrl, notification); err != nil {
+		logger.Warn(err, "[Codecov] Failed to notify %d missing uploads of %s, retrying on the next run", len(pending), repoId)
+		return nil
+	}
+	for _, event := range pending {
+		event.NotifiedAt = &now
+		if err := db.Update(event); err != nil {
+			return errors.Default.Wrap(err, "failed to mark missing upload as notified")
+		}
+	}
+	return nil
+}
+
+// missingUploadGraceHours returns the grace period of the scope config in hours
+func missingUploadGraceHours(scopeConfig *models.CodecovScopeConfig) int {
+	if scopeConfig == nil || scopeConfig.MissingUploadGraceHours <= 0 {
+		return models.DefaultMissingUploadGraceHours
+	}
+	return scopeConfig.MissingUploadGraceHours
+}
+
+// notifyMissingUploads POSTs the notification to the webhook, any non-2xx answer is an error
+func notifyMissingUploads(ctx context.Context, webhookUrl string, notification *missingUploadNotification) errors.Error {
+	return postJSON(ctx, webhookUrl, nil, notification, "missing upload webhook")
+}
+
+// postJSON POSTs the payload as JSON with the extra headers, any non-2xx answer is an error.
+// target names the receiver in the errors.
+func postJSON(ctx context.Context, url string, headers map[string]string, payload interface{}, target string) errors.Error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Default.Wrap(err, fmt.Sprintf("failed to encode the %s payload", target))
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid %s URL", target))
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Default.Wrap(err, fmt.Sprintf("failed to call %s", target))
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("%s returned status %d", target, res.StatusCode))
+	}
+	return nil
+}
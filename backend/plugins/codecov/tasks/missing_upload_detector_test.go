@@ -0,0 +1,146 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMissingUploadGraceHours(t *testing.T) {
+	assert.Equal(t, models.DefaultMissingUploadGraceHours, missingUploadGraceHours(nil))
+	assert.Equal(t, models.DefaultMissingUploadGraceHours, missingUploadGraceHours(&models.CodecovScopeConfig{}))
+	assert.Equal(t, 2, missingUploadGraceHours(&models.CodecovScopeConfig{MissingUploadGraceHours: 2}))
+}
+
+func TestNotifyMissingUploads(t *testing.T) {
+	committedAt := time.Date(2026, 4, 20, 10, 0, 0, 0, time.UTC)
+	notification := &missingUploadNotification{
+		Repo:       "owner/repo",
+		Branch:     "main",
+		GraceHours: 6,
+		Commits:    []missingUploadCommit{{Sha: "abc123", Author: "dev", CommittedAt: &committedAt}},
+	}
+
+	t.Run("posts the notification", func(t *testing.T) {
+		var received missingUploadNotification
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		assert.Nil(t, notifyMissingUploads(context.Background(), server.URL, notification))
+		assert.Equal(t, "owner/repo", received.Repo)
+		assert.Equal(t, "abc123", received.Commits[0].Sha)
+	})
+
+	t.Run("non-2xx status is an error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		assert.NotNil(t, notifyMissingUploads(context.Background(), server.URL, notification))
+	})
+}
+
+func TestDetectMissingUploads(t *testing.T) {
+	committedAt := time.Now().Add(-24 * time.Hour)
+
+	t.Run("records new events and notifies the webhook", func(t *testing.T) {
+		var notified missingUploadNotification
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&notified))
+		}))
+		defer server.Close()
+
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Repo = &models.CodecovRepo{Branch: "main"}
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{MissingUploadWebhookUrl: server.URL}
+
+		mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovCommit"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCommit) = []models.CodecovCommit{
+				{CommitSha: "new", Branch: "main", CommitTimestamp: &committedAt, Author: "dev"},
+				{CommitSha: "notified", Branch: "main", CommitTimestamp: &committedAt},
+			}
+		}).Return(nil)
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovMissingUpload"), mock.Anything).Run(func(args mock.Arguments) {
+			notifiedAt := time.Now()
+			*args.Get(0).(*[]models.CodecovMissingUpload) = []models.CodecovMissingUpload{
+				{CommitSha: "notified", NotifiedAt: &notifiedAt},
+			}
+		}).Return(nil)
+		var saved *models.CodecovMissingUpload
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*models.CodecovMissingUpload)
+		}).Return(nil).Once()
+		mockDal.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+		assert.Nil(t, DetectMissingUploads(mockCtx))
+
+		mockDal.AssertExpectations(t)
+		assert.Equal(t, "new", saved.CommitSha)
+		assert.Equal(t, "owner/repo", saved.RepoId)
+		assert.NotNil(t, saved.NotifiedAt)
+		assert.Equal(t, "main", notified.Branch)
+		assert.Len(t, notified.Commits, 1)
+		assert.Equal(t, "new", notified.Commits[0].Sha)
+	})
+
+	t.Run("webhook failure leaves events pending", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+		data := mockCtx.GetData().(*CodecovTaskData)
+		data.Repo = &models.CodecovRepo{Branch: "main"}
+		data.Options.ScopeConfig = &models.CodecovScopeConfig{MissingUploadWebhookUrl: server.URL}
+
+		mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil)
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovCommit"), mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(0).(*[]models.CodecovCommit) = []models.CodecovCommit{{CommitSha: "new", Branch: "main", CommitTimestamp: &committedAt}}
+		}).Return(nil)
+		mockDal.On("All", mock.AnythingOfType("*[]models.CodecovMissingUpload"), mock.Anything).Return(nil)
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Once()
+
+		assert.Nil(t, DetectMissingUploads(mockCtx))
+		mockDal.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown default branch is skipped", func(t *testing.T) {
+		mockCtx, mockDal, _ := setupCodecovMocks(t)
+
+		assert.Nil(t, DetectMissingUploads(mockCtx))
+		mockDal.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
@@ -31,9 +31,14 @@ type CodecovOptions struct {
 }
 
 type CodecovTaskData struct {
-	Options   *CodecovOptions
-	ApiClient *helper.ApiAsyncClient
-	Repo      *models.CodecovRepo
+	Options      *CodecovOptions
+	ApiClient    *helper.ApiAsyncClient
+	Repo         *models.CodecovRepo
+	TokenRotator *TokenRotator
+	// Service is the git provider segment of the API paths, see models.CodecovConn.ApiService
+	Service string
+	// GithubToken posts the commit statuses of the coverage status export, see models.CodecovAccessToken
+	GithubToken string
 }
 
 // CodecovApiParams matches the models.CodecovApiParams
@@ -0,0 +1,159 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+)
+
+const (
+	TokenPrimary  = "primary"
+	TokenFallback = "fallback"
+)
+
+type codecovToken struct {
+	label string
+	value string
+}
+
+// TokenRotator hands out the active Codecov token and switches to the next one
+// when the API answers 401 (rejected) or 429 (quota exhausted).
+type TokenRotator struct {
+	mu     sync.Mutex
+	tokens []codecovToken
+	active int
+	logger log.Logger
+	next   http.RoundTripper
+}
+
+// NewTokenRotator creates a rotator for the tokens configured on the connection
+func NewTokenRotator(conn *models.CodecovConn, logger log.Logger) *TokenRotator {
+	rotator := &TokenRotator{logger: logger}
+	if conn.Token != "" {
+		rotator.tokens = append(rotator.tokens, codecovToken{label: TokenPrimary, value: conn.Token})
+	}
+	if conn.FallbackToken != "" && conn.FallbackToken != conn.Token {
+		rotator.tokens = append(rotator.tokens, codecovToken{label: TokenFallback, value: conn.FallbackToken})
+	}
+	return rotator
+}
+
+// Install makes the api client authenticate with the active token and retry once
+// with the next token whenever the current one is rejected or throttled
+func (r *TokenRotator) Install(apiClient *helper.ApiClient) {
+	apiClient.SetAuthFunction(r.SetupAuthentication)
+	client := apiClient.GetClient()
+	r.next = client.Transport
+	if r.next == nil {
+		r.next = http.DefaultTransport
+	}
+	client.Transport = r
+}
+
+// ActiveToken returns the label of the token currently in use
+func (r *TokenRotator) ActiveToken() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.tokens) == 0 {
+		return ""
+	}
+	return r.tokens[r.active].label
+}
+
+// SetupAuthentication sets the Authorization header with the active token
+func (r *TokenRotator) SetupAuthentication(req *http.Request) errors.Error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.tokens) > 0 {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %v", r.tokens[r.active].value))
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *TokenRotator) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := r.next.RoundTrip(req)
+	if err != nil || !shouldRotateToken(res.StatusCode) {
+		return res, err
+	}
+	used := req.Header.Get("Authorization")
+	// every token gets at most one attempt per request
+	for attempt := 1; attempt < len(r.tokens); attempt++ {
+		token, rotated := r.rotate(used, res.StatusCode)
+		if !rotated {
+			return res, nil
+		}
+		retryReq, ok := cloneRequestWithToken(req, token)
+		if !ok {
+			return res, nil
+		}
+		_ = res.Body.Close()
+		res, err = r.next.RoundTrip(retryReq)
+		if err != nil || !shouldRotateToken(res.StatusCode) {
+			return res, err
+		}
+		used = retryReq.Header.Get("Authorization")
+	}
+	return res, nil
+}
+
+// rotate moves to the next token unless another request already rotated away from
+// the token that failed, in which case the current active token is returned as is
+func (r *TokenRotator) rotate(usedHeader string, statusCode int) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.tokens) < 2 {
+		return "", false
+	}
+	current := r.tokens[r.active]
+	if usedHeader != fmt.Sprintf("Bearer %v", current.value) {
+		return current.value, true
+	}
+	r.active = (r.active + 1) % len(r.tokens)
+	if r.logger != nil {
+		r.logger.Warn(nil, "[Codecov] %s token got status %d, rotating to %s token", current.label, statusCode, r.tokens[r.active].label)
+	}
+	return r.tokens[r.active].value, true
+}
+
+func shouldRotateToken(statusCode int) bool {
+	return statusCode == http.StatusUnauthorized || statusCode == http.StatusTooManyRequests
+}
+
+func cloneRequestWithToken(req *http.Request, token string) (*http.Request, bool) {
+	retryReq := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, false
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, false
+		}
+		retryReq.Body = body
+	}
+	retryReq.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+	return retryReq, true
+}
@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/codecov/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundTripFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// newTestRotator returns a rotator whose transport answers with the status mapped to each bearer token
+func newTestRotator(primary, fallback string, statusByToken map[string]int) (*TokenRotator, *[]string) {
+	conn := &models.CodecovConn{}
+	conn.Token = primary
+	conn.FallbackToken = fallback
+	rotator := NewTokenRotator(conn, nil)
+	var seen []string
+	rotator.next = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		auth := req.Header.Get("Authorization")
+		seen = append(seen, auth)
+		status, ok := statusByToken[strings.TrimPrefix(auth, "Bearer ")]
+		if !ok {
+			status = http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	return rotator, &seen
+}
+
+func doRotatorRequest(t *testing.T, rotator *TokenRotator) *http.Response {
+	req, err := http.NewRequest(http.MethodGet, "https://api.codecov.io/api/v2/github/org/users", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, rotator.SetupAuthentication(req))
+	res, err := rotator.RoundTrip(req)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestTokenRotator(t *testing.T) {
+	t.Run("primary token serves the request", func(t *testing.T) {
+		rotator, seen := newTestRotator("p", "f", nil)
+		res := doRotatorRequest(t, rotator)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []string{"Bearer p"}, *seen)
+		assert.Equal(t, TokenPrimary, rotator.ActiveToken())
+	})
+
+	t.Run("rotates to fallback on 429 and stays there", func(t *testing.T) {
+		rotator, seen := newTestRotator("p", "f", map[string]int{"p": http.StatusTooManyRequests})
+		res := doRotatorRequest(t, rotator)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, []string{"Bearer p", "Bearer f"}, *seen)
+		assert.Equal(t, TokenFallback, rotator.ActiveToken())
+
+		doRotatorRequest(t, rotator)
+		assert.Equal(t, "Bearer f", (*seen)[2])
+	})
+
+	t.Run("rotates to fallback on 401", func(t *testing.T) {
+		rotator, _ := newTestRotator("p", "f", map[string]int{"p": http.StatusUnauthorized})
+		res := doRotatorRequest(t, rotator)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, TokenFallback, rotator.ActiveToken())
+	})
+
+	t.Run("returns last response when all tokens fail", func(t *testing.T) {
+		rotator, seen := newTestRotator("p", "f", map[string]int{
+			"p": http.StatusTooManyRequests,
+			"f": http.StatusTooManyRequests,
+		})
+		res := doRotatorRequest(t, rotator)
+		assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+		assert.Len(t, *seen, 2)
+	})
+
+	t.Run("no fallback configured", func(t *testing.T) {
+		rotator, seen := newTestRotator("p", "", map[string]int{"p": http.StatusUnauthorized})
+		res := doRotatorRequest(t, rotator)
+		assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+		assert.Len(t, *seen, 1)
+		assert.Equal(t, TokenPrimary, rotator.ActiveToken())
+	})
+
+	t.Run("other errors do not rotate", func(t *testing.T) {
+		rotator, seen := newTestRotator("p", "f", map[string]int{"p": http.StatusNotFound})
+		res := doRotatorRequest(t, rotator)
+		assert.Equal(t, http.StatusNotFound, res.StatusCode)
+		assert.Len(t, *seen, 1)
+		assert.Equal(t, TokenPrimary, rotator.ActiveToken())
+	})
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package tasks
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -26,6 +27,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/apache/incubator-devlake/core/config"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
@@ -43,6 +45,13 @@ var CollectParentIssuesMeta = plugin.SubTaskMeta{
 	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
 }
 
+const (
+	// defaultParentIssueRequestTimeout bounds a single parent issue request
+	defaultParentIssueRequestTimeout = 30 * time.Second
+	// defaultParentIssueCollectionBudget bounds the whole subtask so a slow Jira instance can't stall the pipeline
+	defaultParentIssueCollectionBudget = 15 * time.Minute
+)
+
 // CollectParentIssues collects parent issues that are referenced in epic_key field
 // but were not collected due to JQL filter restrictions (e.g., Features in a different project)
 func CollectParentIssues(taskCtx plugin.SubTaskContext) errors.Error {
@@ -54,6 +63,11 @@ func CollectParentIssues(taskCtx plugin.SubTaskContext) errors.Error {
 
 	logger.Info("collecting parent issues for connection_id=%d, board_id=%d", connectionId, data.Options.BoardId)
 
+	// The budget is derived from the task context, so a cancelled task stops the collection too
+	requestTimeout, budget := parentIssueTimeouts(taskCtx.GetConfigReader())
+	ctx, cancel := context.WithTimeout(taskCtx.GetContext(), budget)
+	defer cancel()
+
 	// Collect parent issues iteratively (they may have their own parents)
 	maxIterations := 10 // Prevent infinite loops
 	totalCollected := 0
@@ -116,8 +130,12 @@ func CollectParentIssues(taskCtx plugin.SubTaskContext) errors.Error {
 		logger.Info("iteration %d: collecting %d missing parent issues: %v", iteration+1, len(keysToCollect), keysToCollect)
 
 		// Collect each parent issue directly
-		for _, issueKey := range keysToCollect {
-			err = collectAndExtractSingleIssue(taskCtx, data, db, issueKey)
+		for i, issueKey := range keysToCollect {
+			// Check for cancellation between issues
+			if ctx.Err() != nil {
+				return stopParentIssueCollection(taskCtx, budget, totalCollected, len(keysToCollect)-i)
+			}
+			err = collectAndExtractSingleIssue(ctx, taskCtx, data, db, issueKey, requestTimeout)
 			if err != nil {
 				// Log but don't fail - the issue might not exist or we might not have permission
 				logger.Warn(err, "failed to collect parent issue %s, skipping", issueKey)
@@ -131,8 +149,62 @@ func CollectParentIssues(taskCtx plugin.SubTaskContext) errors.Error {
 	return nil
 }
 
+// parentIssueTimeouts reads the request timeout and the overall budget,
+// JIRA_PARENT_ISSUE_REQUEST_TIMEOUT and JIRA_PARENT_ISSUE_COLLECTION_TIMEOUT (e.g. "30s", "15m") override the defaults
+func parentIssueTimeouts(cfg config.ConfigReader) (requestTimeout, budget time.Duration) {
+	requestTimeout = defaultParentIssueRequestTimeout
+	budget = defaultParentIssueCollectionBudget
+	if cfg == nil {
+		return
+	}
+	if v := cfg.GetDuration("JIRA_PARENT_ISSUE_REQUEST_TIMEOUT"); v > 0 {
+		requestTimeout = v
+	}
+	if v := cfg.GetDuration("JIRA_PARENT_ISSUE_COLLECTION_TIMEOUT"); v > 0 {
+		budget = v
+	}
+	return
+}
+
+// stopParentIssueCollection ends the collection early: a cancelled task is an error,
+// an exhausted budget only skips the remaining parents so the pipeline can move on
+func stopParentIssueCollection(taskCtx plugin.SubTaskContext, budget time.Duration, collected, remaining int) errors.Error {
+	if err := taskCtx.GetContext().Err(); err != nil {
+		return errors.Convert(err)
+	}
+	taskCtx.GetLogger().Warn(nil, "parent issue collection budget of %s exhausted, collected %d parent issues, skipped %d", budget, collected, remaining)
+	return nil
+}
+
+// getWithTimeout runs a request bound to a context that times out after timeout or ends
+// with ctx, so a slow request is aborted. The context lives until the body is closed.
+func getWithTimeout(ctx context.Context, timeout time.Duration, get func(context.Context) (*http.Response, errors.Error)) (*http.Response, errors.Error) {
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	resp, err := get(reqCtx)
+	if err != nil {
+		cancel()
+		if reqCtx.Err() != nil {
+			return nil, errors.Default.Wrap(err, "request timed out")
+		}
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnClose is a response body releasing the context of its request when closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelOnClose) Close() error {
+	defer body.cancel()
+	return body.ReadCloser.Close()
+}
+
 // collectAndExtractSingleIssue collects a single issue by key and extracts it
-func collectAndExtractSingleIssue(taskCtx plugin.SubTaskContext, data *JiraTaskData, db dal.Dal, issueKey string) errors.Error {
+func collectAndExtractSingleIssue(ctx context.Context, taskCtx plugin.SubTaskContext, data *JiraTaskData, db dal.Dal, issueKey string, requestTimeout time.Duration) errors.Error {
 	logger := taskCtx.GetLogger()
 
 	// Fetch the issue from Jira API
@@ -140,7 +212,9 @@ func collectAndExtractSingleIssue(taskCtx plugin.SubTaskContext, data *JiraTaskD
 	query := url.Values{}
 	query.Set("expand", "changelog")
 
-	resp, err := data.ApiClient.Get(path, query, nil)
+	resp, err := getWithTimeout(ctx, requestTimeout, func(reqCtx context.Context) (*http.Response, errors.Error) {
+		return data.ApiClient.DoWithContext(reqCtx, http.MethodGet, path, query, nil, nil)
+	})
 	if err != nil {
 		return errors.Default.Wrap(err, fmt.Sprintf("failed to fetch issue %s", issueKey))
 	}
@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetWithTimeout(t *testing.T) {
+	okResponse := func(context.Context) (*http.Response, errors.Error) {
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+	// slowResponse waits for the request context like an http.Client does
+	slowResponse := func(ctx context.Context) (*http.Response, errors.Error) {
+		<-ctx.Done()
+		return nil, errors.Convert(ctx.Err())
+	}
+
+	t.Run("returns response within timeout", func(t *testing.T) {
+		resp, err := getWithTimeout(context.Background(), time.Second, okResponse)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		body, readErr := io.ReadAll(resp.Body)
+		assert.Nil(t, readErr)
+		assert.Equal(t, "{}", string(body))
+		assert.Nil(t, resp.Body.Close())
+	})
+
+	t.Run("keeps the request context until the body is closed", func(t *testing.T) {
+		var reqCtx context.Context
+		resp, err := getWithTimeout(context.Background(), time.Second, func(ctx context.Context) (*http.Response, errors.Error) {
+			reqCtx = ctx
+			return okResponse(ctx)
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, reqCtx.Err())
+		_ = resp.Body.Close()
+		assert.NotNil(t, reqCtx.Err())
+	})
+
+	t.Run("passes request errors through", func(t *testing.T) {
+		_, err := getWithTimeout(context.Background(), time.Second, func(context.Context) (*http.Response, errors.Error) {
+			return nil, errors.Default.New("connection refused")
+		})
+		assert.NotNil(t, err)
+	})
+
+	t.Run("aborts slow requests", func(t *testing.T) {
+		start := time.Now()
+		_, err := getWithTimeout(context.Background(), 20*time.Millisecond, slowResponse)
+		assert.NotNil(t, err)
+		assert.Contains(t, err.Error(), "request timed out")
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("stops when the parent context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := getWithTimeout(ctx, time.Minute, slowResponse)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestParentIssueTimeoutsDefaults(t *testing.T) {
+	requestTimeout, budget := parentIssueTimeouts(nil)
+	assert.Equal(t, defaultParentIssueRequestTimeout, requestTimeout)
+	assert.Equal(t, defaultParentIssueCollectionBudget, budget)
+}
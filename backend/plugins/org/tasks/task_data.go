@@ -40,11 +40,18 @@ type Scope struct {
 // NewProjectMapping is the construct function of ProjectMapping
 func NewProjectMapping(projectName string, pluginScopes []plugin.Scope) ProjectMapping {
 	var scopes []Scope
+	seen := make(map[Scope]bool, len(pluginScopes))
 	for _, ps := range pluginScopes {
-		scopes = append(scopes, Scope{
+		scope := Scope{
 			Table: ps.TableName(),
 			RowID: ps.ScopeId(),
-		})
+		}
+		// a repo may be produced by several plugins, e.g. github and aireview
+		if seen[scope] {
+			continue
+		}
+		seen[scope] = true
+		scopes = append(scopes, scope)
 	}
 	return ProjectMapping{
 		ProjectName: projectName,
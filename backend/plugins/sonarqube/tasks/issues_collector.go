@@ -51,33 +51,27 @@ func CollectIssues(taskCtx plugin.SubTaskContext) (err errors.Error) {
 	logger := taskCtx.GetLogger()
 	logger.Info("collect issues")
 
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_ISSUES_TABLE)
+	collectorWithState, err := helper.NewStatefulApiCollector(*rawDataSubTaskArgs)
+	if err != nil {
+		return err
+	}
+	// issues/search has no updatedAfter filter: the incremental mode collects the issues created
+	// since the last successful collection, then the older issues updated since, sorted by update date
+	var createdAfter *time.Time
+	if collectorWithState.IsIncremental() {
+		createdAfter = collectorWithState.GetSince()
+		logger.Info("collect issues created or updated after %s", GetFormatTime(createdAfter))
+	}
 	iterator := helper.NewQueueIterator()
-	severities := []string{"BLOCKER", "CRITICAL", "MAJOR", "MINOR", "INFO"}
-	statuses := []string{"OPEN", "CONFIRMED", "REOPENED", "RESOLVED", "CLOSED"}
-	types := []string{"BUG", "VULNERABILITY", "CODE_SMELL"}
-	for _, severity := range severities {
-		for _, status := range statuses {
-			for _, typ := range types {
-				iterator.Push(
-					&SonarqubeIssueIteratorNode{
-						Severity:      severity,
-						Status:        status,
-						Type:          typ,
-						CreatedAfter:  nil,
-						CreatedBefore: nil,
-						FilePath:      "",
-					},
-				)
-			}
-		}
+	for _, node := range newIssueIteratorNodes(createdAfter) {
+		iterator.Push(node)
 	}
-	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_ISSUES_TABLE)
-	collector, err := helper.NewApiCollector(helper.ApiCollectorArgs{
-		RawDataSubTaskArgs: *rawDataSubTaskArgs,
-		ApiClient:          data.ApiClient,
-		PageSize:           100,
-		UrlTemplate:        "issues/search",
-		Input:              iterator,
+	err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+		ApiClient:   data.ApiClient,
+		PageSize:    100,
+		UrlTemplate: "issues/search",
+		Input:       iterator,
 		Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
 			query := url.Values{}
 			input, ok := reqData.Input.(*SonarqubeIssueIteratorNode)
@@ -234,37 +228,100 @@ func CollectIssues(taskCtx plugin.SubTaskContext) (err errors.Error) {
 			}
 		},
 		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
-			var resData struct {
-				Data []json.RawMessage `json:"issues"`
-			}
-			err = helper.UnmarshalResponse(res, &resData)
-			if err != nil {
-				return nil, err
-			}
-
-			// check if sonar report updated during collecting
-			var issue struct {
-				UpdateDate *common.Iso8601Time `json:"updateDate"`
-			}
-			for _, v := range resData.Data {
-				err = errors.Convert(json.Unmarshal(v, &issue))
-				if err != nil {
-					return nil, err
-				}
-				if issue.UpdateDate.ToTime().After(data.TaskStartTime) {
-					return nil, errors.Default.New(fmt.Sprintf(`Your data is affected by the latest analysis\n
-						Please recollect this project: %s`, data.Options.ProjectKey))
-				}
-			}
-
-			return resData.Data, nil
+			return parseIssues(res, data.Options.ProjectKey, data.TaskStartTime, nil)
 		},
 	})
 	if err != nil {
 		return err
 	}
-	return collector.Execute()
+	if createdAfter != nil {
+		err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+			ApiClient:   data.ApiClient,
+			PageSize:    100,
+			UrlTemplate: "issues/search",
+			Query: func(reqData *helper.RequestData) (url.Values, errors.Error) {
+				query := url.Values{}
+				query.Set("componentKeys", fmt.Sprintf("%v", data.Options.ProjectKey))
+				query.Set("s", "UPDATE_DATE")
+				query.Set("asc", "false")
+				query.Set("p", fmt.Sprintf("%v", reqData.Pager.Page))
+				query.Set("ps", fmt.Sprintf("%v", reqData.Pager.Size))
+				return query, nil
+			},
+			GetNextPageCustomData: func(prevReqData *helper.RequestData, prevPageResponse *http.Response) (interface{}, errors.Error) {
+				// issues/search returns at most 10000 issues per query
+				if prevReqData.Pager.Page >= MAXPAGES {
+					logger.Warn(nil, "more than %d issues were updated after %s, run a full sync to refresh the others", MAXISSUECOUNT, GetFormatTime(createdAfter))
+					return nil, helper.ErrFinishCollect
+				}
+				return nil, nil
+			},
+			ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
+				return parseIssues(res, data.Options.ProjectKey, data.TaskStartTime, createdAfter)
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return collectorWithState.Execute()
+}
+
+// parseIssues returns the issues of an issues/search page. It fails when an issue was updated by an
+// analysis during the collection. With updatedAfter, for pages sorted by update date descending, it
+// returns the issues updated after it and ErrFinishCollect once an issue was updated before.
+func parseIssues(res *http.Response, projectKey string, taskStartTime time.Time, updatedAfter *time.Time) ([]json.RawMessage, errors.Error) {
+	var resData struct {
+		Data []json.RawMessage `json:"issues"`
+	}
+	err := helper.UnmarshalResponse(res, &resData)
+	if err != nil {
+		return nil, err
+	}
+
+	// check if sonar report updated during collecting
+	var issue struct {
+		UpdateDate *common.Iso8601Time `json:"updateDate"`
+	}
+	for i, v := range resData.Data {
+		err = errors.Convert(json.Unmarshal(v, &issue))
+		if err != nil {
+			return nil, err
+		}
+		if issue.UpdateDate.ToTime().After(taskStartTime) {
+			return nil, errors.Default.New(fmt.Sprintf(`Your data is affected by the latest analysis\n
+						Please recollect this project: %s`, projectKey))
+		}
+		if updatedAfter != nil && issue.UpdateDate.ToTime().Before(*updatedAfter) {
+			return resData.Data[:i], helper.ErrFinishCollect
+		}
+	}
 
+	return resData.Data, nil
+}
+
+// newIssueIteratorNodes splits the issue search into one node per severity, status and type,
+// optionally bounded by createdAfter for incremental collection.
+func newIssueIteratorNodes(createdAfter *time.Time) []*SonarqubeIssueIteratorNode {
+	severities := []string{"BLOCKER", "CRITICAL", "MAJOR", "MINOR", "INFO"}
+	statuses := []string{"OPEN", "CONFIRMED", "REOPENED", "RESOLVED", "CLOSED"}
+	types := []string{"BUG", "VULNERABILITY", "CODE_SMELL"}
+	nodes := make([]*SonarqubeIssueIteratorNode, 0, len(severities)*len(statuses)*len(types))
+	for _, severity := range severities {
+		for _, status := range statuses {
+			for _, typ := range types {
+				nodes = append(nodes, &SonarqubeIssueIteratorNode{
+					Severity:      severity,
+					Status:        status,
+					Type:          typ,
+					CreatedAfter:  createdAfter,
+					CreatedBefore: nil,
+					FilePath:      "",
+				})
+			}
+		}
+	}
+	return nodes
 }
 
 var CollectIssuesMeta = plugin.SubTaskMeta{
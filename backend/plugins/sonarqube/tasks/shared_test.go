@@ -19,12 +19,14 @@ package tasks
 
 import (
 	"crypto/sha256"
+	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/sonarqube/models"
 	"io/ioutil"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestConvertTimeToMinutes(t *testing.T) {
@@ -149,3 +151,52 @@ func TestSetMetrics(t *testing.T) {
 		t.Errorf("CodeSmells was not set properly")
 	}
 }
+
+func TestNewIssueIteratorNodes(t *testing.T) {
+	nodes := newIssueIteratorNodes(nil)
+	if len(nodes) != 75 {
+		t.Fatalf("newIssueIteratorNodes(nil) returned %d nodes; expected 75", len(nodes))
+	}
+	for _, node := range nodes {
+		if node.CreatedAfter != nil {
+			t.Errorf("full sync node %+v should not be bounded by createdAfter", node)
+		}
+	}
+
+	since := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, node := range newIssueIteratorNodes(&since) {
+		if node.CreatedAfter == nil || !node.CreatedAfter.Equal(since) {
+			t.Errorf("incremental node %+v should start at %v", node, since)
+		}
+		if node.CreatedBefore != nil || node.FilePath != "" {
+			t.Errorf("incremental node %+v should not be split yet", node)
+		}
+	}
+}
+
+func TestParseIssues(t *testing.T) {
+	newResponse := func() *http.Response {
+		return &http.Response{Body: ioutil.NopCloser(strings.NewReader(`{"issues": [
+			{"key": "a", "updateDate": "2024-03-03T10:00:00+0000"},
+			{"key": "b", "updateDate": "2024-03-02T10:00:00+0000"},
+			{"key": "c", "updateDate": "2024-02-20T10:00:00+0000"}
+		]}`))}
+	}
+	taskStartTime := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	issues, err := parseIssues(newResponse(), "project", taskStartTime, nil)
+	if err != nil || len(issues) != 3 {
+		t.Fatalf("parseIssues without updatedAfter returned %d issues and %v; expected 3 issues", len(issues), err)
+	}
+
+	updatedAfter := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	issues, err = parseIssues(newResponse(), "project", taskStartTime, &updatedAfter)
+	if !errors.Is(err, api.ErrFinishCollect) || len(issues) != 2 {
+		t.Fatalf("parseIssues with updatedAfter returned %d issues and %v; expected 2 issues and ErrFinishCollect", len(issues), err)
+	}
+
+	_, err = parseIssues(newResponse(), "project", time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), nil)
+	if err == nil {
+		t.Errorf("parseIssues should fail for issues updated after the task start")
+	}
+}
@@ -20,7 +20,10 @@ package api
 import (
 	"github.com/apache/incubator-devlake/core/errors"
 	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/utils"
 	helperapi "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/helpers/srvhelper"
 	"github.com/apache/incubator-devlake/plugins/testregistry/models"
@@ -54,6 +57,10 @@ func makePipelinePlanV200(
 	scopeDetails []*srvhelper.ScopeDetail[models.TestRegistryScope, models.TestRegistryScopeConfig],
 	connection *models.TestRegistryConnection,
 ) (coreModels.PipelinePlan, errors.Error) {
+	// Only the collector matching the connection's collection mode is planned,
+	// so the pipeline does not list collectors that would skip themselves
+	subtaskMetas = filterCollectorSubtasks(subtaskMetas, connection.CollectionMode())
+
 	plan := make(coreModels.PipelinePlan, len(scopeDetails))
 	for i, scopeDetail := range scopeDetails {
 		stage := plan[i]
@@ -63,32 +70,12 @@ func makePipelinePlanV200(
 
 		scope, scopeConfig := scopeDetail.Scope, scopeDetail.ScopeConfig
 
-		// Determine which entities to collect based on CI tool type
-		// Default to CICD domain type for testregistry plugin
-		entities := []string{plugin.DOMAIN_TYPE_CICD}
-
-		if connection.CITool == models.CIToolOpenshiftCI {
-			// For Openshift CI, collect Prow jobs (CICD domain)
-			if scopeConfig != nil && len(scopeConfig.Entities) > 0 {
-				entities = scopeConfig.Entities
-			}
-		} else if connection.CITool == models.CIToolTektonCI {
-			// For Tekton CI, collect OCI artifacts (to be implemented)
-			if scopeConfig != nil && len(scopeConfig.Entities) > 0 {
-				entities = scopeConfig.Entities
-			}
-		}
-
 		// construct task options for testregistry
 		task, err := helperapi.MakePipelinePlanTask(
 			"testregistry",
 			subtaskMetas,
-			entities,
-			tasks.TestRegistryOptions{
-				ConnectionId: connection.ID,
-				FullName:     scope.FullName,
-				ScopeConfig:  scopeConfig,
-			},
+			scopeEntities(scopeConfig),
+			makeTaskOptionsV200(scope, scopeConfig, connection),
 		)
 		if err != nil {
 			return nil, err
@@ -100,16 +87,76 @@ func makePipelinePlanV200(
 	return plan, nil
 }
 
+// makeTaskOptionsV200 builds the task options of a scope, copying the scope config
+// settings the tasks read (JUnit regex, passed cases mode, backfill slices...) so a
+// pipeline runs with the config it was planned with
+func makeTaskOptionsV200(
+	scope models.TestRegistryScope,
+	scopeConfig *models.TestRegistryScopeConfig,
+	connection *models.TestRegistryConnection,
+) tasks.TestRegistryOptions {
+	op := tasks.TestRegistryOptions{
+		ConnectionId:   connection.ID,
+		FullName:       scope.FullName,
+		ScopeConfigId:  scope.ScopeConfigId,
+		ScopeConfig:    scopeConfig,
+		CollectionMode: connection.CollectionMode(),
+	}
+	if scopeConfig != nil {
+		op.JUnitRegex = scopeConfig.JUnitRegex
+	}
+	return op
+}
+
+// collectorModes maps each collector subtask, and the subtasks reading one source, to the collection mode it serves
+var collectorModes = map[string]string{
+	tasks.CollectProwJobsMeta.Name:               models.CollectionModeProw,
+	tasks.ReprocessJUnitMeta.Name:                models.CollectionModeProw,
+	tasks.SummarizeProwFailuresMeta.Name:         models.CollectionModeProw,
+	tasks.CollectTektonJobsMeta.Name:             models.CollectionModeQuay,
+	tasks.CollectKubernetesPipelineRunsMeta.Name: models.CollectionModeKubernetes,
+}
+
+// filterCollectorSubtasks drops the collectors of other collection modes.
+// Non-collector subtasks are kept, and everything is kept when the mode is unknown.
+func filterCollectorSubtasks(subtaskMetas []plugin.SubTaskMeta, mode string) []plugin.SubTaskMeta {
+	if mode == "" {
+		return subtaskMetas
+	}
+	filtered := make([]plugin.SubTaskMeta, 0, len(subtaskMetas))
+	for _, meta := range subtaskMetas {
+		if collectorMode, ok := collectorModes[meta.Name]; ok && collectorMode != mode {
+			continue
+		}
+		filtered = append(filtered, meta)
+	}
+	return filtered
+}
+
+// scopeEntities returns the domain types collected for a scope, the CICD domain type by default
+func scopeEntities(scopeConfig *models.TestRegistryScopeConfig) []string {
+	if scopeConfig != nil && len(scopeConfig.Entities) > 0 {
+		return scopeConfig.Entities
+	}
+	return []string{plugin.DOMAIN_TYPE_CICD}
+}
+
 func makeScopesV200(
 	scopeDetails []*srvhelper.ScopeDetail[models.TestRegistryScope, models.TestRegistryScopeConfig],
 	connection *models.TestRegistryConnection,
 ) ([]plugin.Scope, errors.Error) {
 	scopes := make([]plugin.Scope, 0, len(scopeDetails))
 
+	idgen := didgen.NewDomainIdGenerator(&models.TestRegistryScope{})
 	for _, scopeDetail := range scopeDetails {
-		scope := scopeDetail.Scope
+		scope, scopeConfig := scopeDetail.Scope, scopeDetail.ScopeConfig
 		// Return the TestRegistryScope itself as it implements plugin.Scope
 		scopes = append(scopes, scope)
+
+		// The cicd scope links the converted pipelines to the project
+		if utils.StringsContains(scopeEntities(scopeConfig), plugin.DOMAIN_TYPE_CICD) {
+			scopes = append(scopes, devops.NewCicdScope(idgen.Generate(connection.ID, scope.FullName), scope.Name))
+		}
 	}
 
 	return scopes, nil
@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/srvhelper"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+func subtaskNames(metas []plugin.SubTaskMeta) []string {
+	names := make([]string, 0, len(metas))
+	for _, meta := range metas {
+		names = append(names, meta.Name)
+	}
+	return names
+}
+
+func TestFilterCollectorSubtasks(t *testing.T) {
+	metas := []plugin.SubTaskMeta{
+		tasks.CollectProwJobsMeta,
+		tasks.CollectTektonJobsMeta,
+		tasks.CollectKubernetesPipelineRunsMeta,
+		tasks.ReprocessJUnitMeta,
+		tasks.SummarizeProwFailuresMeta,
+		tasks.GenerateCiIncidentsMeta,
+		tasks.DiffJobOutcomesMeta,
+	}
+
+	assert.Equal(t,
+		[]string{"collectProwJobs", "reprocessJUnit", "summarizeProwFailures", "generateCiIncidents", "diffJobOutcomes"},
+		subtaskNames(filterCollectorSubtasks(metas, models.CollectionModeProw)))
+	assert.Equal(t,
+		[]string{"collectTektonJobs", "generateCiIncidents", "diffJobOutcomes"},
+		subtaskNames(filterCollectorSubtasks(metas, models.CollectionModeQuay)))
+	assert.Equal(t,
+		[]string{"collectKubernetesPipelineRuns", "generateCiIncidents", "diffJobOutcomes"},
+		subtaskNames(filterCollectorSubtasks(metas, models.CollectionModeKubernetes)))
+	assert.Len(t, filterCollectorSubtasks(metas, ""), len(metas))
+}
+
+func TestMakeTaskOptionsV200(t *testing.T) {
+	connection := &models.TestRegistryConnection{CITool: models.CIToolTektonCI, TektonSource: models.TektonSourceKubernetes}
+	connection.ID = 3
+	scope := models.TestRegistryScope{FullName: "rhtap-releng-tenant"}
+	scope.ScopeConfigId = 7
+	scopeConfig := &models.TestRegistryScopeConfig{
+		ScopeConfig:     common.ScopeConfig{Entities: []string{plugin.DOMAIN_TYPE_CICD}},
+		PassedCasesMode: models.PassedCasesModeAggregate,
+		JUnitRegex:      `report-.*\.xml`,
+	}
+	scopeConfig.ID = 7
+
+	op := makeTaskOptionsV200(scope, scopeConfig, connection)
+	assert.Equal(t, uint64(3), op.ConnectionId)
+	assert.Equal(t, "rhtap-releng-tenant", op.FullName)
+	assert.Equal(t, uint64(7), op.ScopeConfigId)
+	assert.Same(t, scopeConfig, op.ScopeConfig)
+	assert.Equal(t, `report-.*\.xml`, op.JUnitRegex)
+	assert.Equal(t, models.CollectionModeKubernetes, op.CollectionMode)
+
+	// a scope without a scope config keeps the connection's JUnit regex
+	op = makeTaskOptionsV200(models.TestRegistryScope{FullName: "konflux-ci/build-service"}, nil, &models.TestRegistryConnection{CITool: models.CIToolOpenshiftCI})
+	assert.Nil(t, op.ScopeConfig)
+	assert.Empty(t, op.JUnitRegex)
+	assert.Equal(t, models.CollectionModeProw, op.CollectionMode)
+}
+
+func mockTestRegistryPlugin(t *testing.T) {
+	mockMeta := mockplugin.NewPluginMeta(t)
+	mockMeta.On("RootPkgPath").Return("github.com/apache/incubator-devlake/plugins/testregistry")
+	mockMeta.On("Name").Return("dummy").Maybe()
+	err := plugin.RegisterPlugin("testregistry", mockMeta)
+	assert.Nil(t, err)
+}
+
+func TestMakeScopesV200(t *testing.T) {
+	mockTestRegistryPlugin(t)
+
+	connection := &models.TestRegistryConnection{}
+	connection.ID = 3
+	scope := models.TestRegistryScope{Name: "release-service", FullName: "konflux-ci/release-service"}
+	ticketsOnly := &models.TestRegistryScopeConfig{ScopeConfig: common.ScopeConfig{Entities: []string{plugin.DOMAIN_TYPE_TICKET}}}
+
+	scopes, err := makeScopesV200([]*srvhelper.ScopeDetail[models.TestRegistryScope, models.TestRegistryScopeConfig]{
+		{Scope: scope},
+		{Scope: scope, ScopeConfig: ticketsOnly},
+	}, connection)
+	assert.Nil(t, err)
+	assert.Len(t, scopes, 3)
+	assert.Equal(t, scope, scopes[0])
+	cicdScope, ok := scopes[1].(*devops.CicdScope)
+	assert.True(t, ok)
+	assert.Equal(t, "testregistry:TestRegistryScope:3:konflux-ci/release-service", cicdScope.Id)
+	assert.Equal(t, "release-service", cicdScope.Name)
+	assert.Equal(t, scope, scopes[2])
+}
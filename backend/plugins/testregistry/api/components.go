@@ -0,0 +1,166 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const defaultComponentDays = 30
+
+// componentStatusCount is one row of the per-component test case count query
+type componentStatusCount struct {
+	Component string
+	Status    string
+	Count     int64
+}
+
+// componentSuiteTotals is one row of the per-component suite query
+type componentSuiteTotals struct {
+	Component     string
+	Jobs          int64
+	Suites        int64
+	PassedOmitted int64
+}
+
+// ComponentPassRate is the pass rate of one Konflux component
+type ComponentPassRate struct {
+	Component string  `json:"component"`
+	Jobs      int64   `json:"jobs"`
+	Suites    int64   `json:"suites"`
+	Total     int64   `json:"total"`
+	Passed    int64   `json:"passed"`
+	Failed    int64   `json:"failed"`
+	Errored   int64   `json:"errored"`
+	Skipped   int64   `json:"skipped"`
+	PassRate  float64 `json:"passRate"` // passed / (passed + failed + errored) * 100, skipped cases excluded
+}
+
+// GetComponentPassRates lists the pass rate of every Konflux component of a connection.
+// Components are assigned to suites by the componentMappings of the scope config.
+//
+// Query parameters:
+//   - days: Only include jobs started in the last N days (default 30)
+//   - scopeId: Only include jobs of this scope (optional)
+func GetComponentPassRates(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+
+	days := defaultComponentDays
+	if s := input.Query.Get("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d <= 0 {
+			return nil, errors.BadInput.New(fmt.Sprintf("days must be a positive integer, got %q", s))
+		}
+		days = d
+	}
+	since := time.Now().AddDate(0, 0, -days)
+	scopeId := input.Query.Get("scopeId")
+
+	jobFilter := "s.connection_id = ? AND s.component != '' AND j.started_at >= ?"
+	args := []interface{}{connectionId, since}
+	if scopeId != "" {
+		jobFilter += " AND j.scope_id = ?"
+		args = append(args, scopeId)
+	}
+
+	db := basicRes.GetDal()
+	var suiteTotals []componentSuiteTotals
+	err := db.All(&suiteTotals,
+		dal.Select("s.component AS component, COUNT(DISTINCT s.job_id) AS jobs, COUNT(*) AS suites, COALESCE(SUM(s.num_passed_omitted), 0) AS passed_omitted"),
+		dal.From(fmt.Sprintf("%s s", models.TestSuite{}.TableName())),
+		dal.Join(fmt.Sprintf("JOIN %s j ON j.connection_id = s.connection_id AND j.job_id = s.job_id", models.TestRegistryCIJob{}.TableName())),
+		dal.Where(jobFilter, args...),
+		dal.Groupby("s.component"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count component suites")
+	}
+
+	var statusCounts []componentStatusCount
+	err = db.All(&statusCounts,
+		dal.Select("s.component AS component, c.status AS status, COUNT(*) AS count"),
+		dal.From(fmt.Sprintf("%s c", models.TestCase{}.TableName())),
+		dal.Join(fmt.Sprintf("JOIN %s s ON s.connection_id = c.connection_id AND s.job_id = c.job_id AND s.suite_id = c.suite_id", models.TestSuite{}.TableName())),
+		dal.Join(fmt.Sprintf("JOIN %s j ON j.connection_id = s.connection_id AND j.job_id = s.job_id", models.TestRegistryCIJob{}.TableName())),
+		dal.Where(jobFilter, args...),
+		dal.Groupby("s.component, c.status"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count component test cases")
+	}
+
+	return &plugin.ApiResourceOutput{Body: mergeComponentPassRates(suiteTotals, statusCounts), Status: http.StatusOK}, nil
+}
+
+// mergeComponentPassRates combines the suite totals and test case counts of each component.
+// Passing test cases that were not stored (see passedCases in the scope config) still count
+// as passed through num_passed_omitted. The result is sorted by component name.
+func mergeComponentPassRates(suiteTotals []componentSuiteTotals, statusCounts []componentStatusCount) []ComponentPassRate {
+	rates := make(map[string]*ComponentPassRate)
+	get := func(component string) *ComponentPassRate {
+		rate, ok := rates[component]
+		if !ok {
+			rate = &ComponentPassRate{Component: component}
+			rates[component] = rate
+		}
+		return rate
+	}
+
+	for _, totals := range suiteTotals {
+		rate := get(totals.Component)
+		rate.Jobs = totals.Jobs
+		rate.Suites = totals.Suites
+		rate.Passed += totals.PassedOmitted
+	}
+	for _, count := range statusCounts {
+		rate := get(count.Component)
+		switch count.Status {
+		case "passed":
+			rate.Passed += count.Count
+		case "failed":
+			rate.Failed += count.Count
+		case "errored":
+			rate.Errored += count.Count
+		case "skipped":
+			rate.Skipped += count.Count
+		}
+	}
+
+	result := make([]ComponentPassRate, 0, len(rates))
+	for _, rate := range rates {
+		rate.Total = rate.Passed + rate.Failed + rate.Errored + rate.Skipped
+		if executed := rate.Passed + rate.Failed + rate.Errored; executed > 0 {
+			rate.PassRate = float64(rate.Passed) / float64(executed) * 100
+		}
+		result = append(result, *rate)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Component < result[j].Component })
+	return result
+}
@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeComponentPassRates(t *testing.T) {
+	rates := mergeComponentPassRates(
+		[]componentSuiteTotals{
+			{Component: "release-service", Jobs: 2, Suites: 4, PassedOmitted: 6},
+			{Component: "build-service", Jobs: 3, Suites: 3},
+		},
+		[]componentStatusCount{
+			{Component: "build-service", Status: "passed", Count: 9},
+			{Component: "build-service", Status: "failed", Count: 1},
+			{Component: "build-service", Status: "skipped", Count: 5},
+			{Component: "release-service", Status: "failed", Count: 2},
+			{Component: "release-service", Status: "errored", Count: 2},
+		},
+	)
+
+	assert.Equal(t, []ComponentPassRate{
+		{Component: "build-service", Jobs: 3, Suites: 3, Total: 15, Passed: 9, Failed: 1, Skipped: 5, PassRate: 90},
+		{Component: "release-service", Jobs: 2, Suites: 4, Total: 10, Passed: 6, Failed: 2, Errored: 2, PassRate: 60},
+	}, rates)
+}
+
+func TestMergeComponentPassRatesOnlySkipped(t *testing.T) {
+	rates := mergeComponentPassRates(
+		[]componentSuiteTotals{{Component: "mintmaker", Jobs: 1, Suites: 1}},
+		[]componentStatusCount{{Component: "mintmaker", Status: "skipped", Count: 3}},
+	)
+	assert.Len(t, rates, 1)
+	assert.Equal(t, float64(0), rates[0].PassRate)
+	assert.Equal(t, int64(3), rates[0].Total)
+}
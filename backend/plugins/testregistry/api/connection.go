@@ -22,11 +22,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
 )
 
 // PostConnections
@@ -39,6 +42,15 @@ import (
 // @Failure 500  {string} errcode.Error "Internal Error"
 // @Router /plugins/testregistry/connections [POST]
 func PostConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if err := validateTektonStatusMapping(input.Body); err != nil {
+		return nil, err
+	}
+	if err := validateRepoRenames(input.Body); err != nil {
+		return nil, err
+	}
+	if err := validateGCSSettings(input.Body); err != nil {
+		return nil, err
+	}
 	return dsHelper.ConnApi.Post(input)
 }
 
@@ -52,9 +64,72 @@ func PostConnections(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 // @Failure 500  {string} errcode.Error "Internal Error"
 // @Router /plugins/testregistry/connections/{connectionId} [PATCH]
 func PatchConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	if err := validateTektonStatusMapping(input.Body); err != nil {
+		return nil, err
+	}
+	if err := validateRepoRenames(input.Body); err != nil {
+		return nil, err
+	}
+	if err := validateGCSSettings(input.Body); err != nil {
+		return nil, err
+	}
 	return dsHelper.ConnApi.Patch(input)
 }
 
+// validateTektonStatusMapping rejects a tektonStatusMapping whose results are not CI job results
+// a dashboard knows, so a typo does not silently move runs out of the SUCCESS/FAILURE buckets
+func validateTektonStatusMapping(body map[string]interface{}) errors.Error {
+	raw, ok := body["tektonStatusMapping"]
+	if !ok || raw == nil {
+		return nil
+	}
+	mapping, ok := raw.(map[string]interface{})
+	if !ok {
+		return errors.BadInput.New("tektonStatusMapping must be an object mapping Tekton statuses to results")
+	}
+	for status, result := range mapping {
+		if value, _ := result.(string); !slices.Contains(models.TektonStatusResults, value) {
+			return errors.BadInput.New(fmt.Sprintf("invalid result %v for Tekton status %s. Must be one of %s", result, status, strings.Join(models.TektonStatusResults, ", ")))
+		}
+	}
+	return nil
+}
+
+// validateGCSSettings rejects a gcsPrefixTemplate with unknown placeholders and gcsCredentials that are not
+// a service account key. The sanitized credentials sent back by the UI are left to MergeFromRequest.
+func validateGCSSettings(body map[string]interface{}) errors.Error {
+	if template, ok := body["gcsPrefixTemplate"].(string); ok {
+		if err := tasks.ValidateGCSPrefixTemplate(template); err != nil {
+			return err
+		}
+	}
+	credentials, _ := body["gcsCredentials"].(string)
+	if strings.TrimSpace(credentials) == "" || isSanitized(credentials) {
+		return nil
+	}
+	return tasks.ValidateGCSCredentials(credentials)
+}
+
+// secretConnectionFields are the connection fields holding credentials, never logged
+var secretConnectionFields = []string{"githubToken", "gcsCredentials", "quayRobotToken", "quayOAuthToken", "kubernetesToken", "webhookSecret"}
+
+// redactSecrets returns a copy of a request body with the values of the secretConnectionFields masked
+func redactSecrets(body map[string]interface{}) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(body))
+	for field, value := range body {
+		if s, ok := value.(string); ok && s != "" && slices.Contains(secretConnectionFields, field) {
+			value = "********"
+		}
+		redacted[field] = value
+	}
+	return redacted
+}
+
+// isSanitized reports whether a secret is the masked value returned by Sanitize, e.g. "{\"****...***}\n"
+func isSanitized(secret string) bool {
+	return len(secret) > 4 && strings.Trim(secret[2:len(secret)-2], "*") == ""
+}
+
 // DeleteConnection
 // @Summary delete a testregistry connection
 // @Description Delete a testregistry connection
@@ -101,6 +176,10 @@ func GetConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, e
 // @Failure 500  {string} errcode.Error "Internal Error"
 // @Router /plugins/testregistry/test [POST]
 func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	ctx := gocontext.Background()
+	if input.Request != nil {
+		ctx = input.Request.Context()
+	}
 	// input.Body is already decoded as map[string]interface{} by the framework
 	// However, frontend pick() might exclude custom fields, so we check both input.Body and try struct decode
 	bodyMap := input.Body
@@ -137,7 +216,7 @@ func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 	// Log what we received for debugging
 	if basicRes != nil {
 		logger := basicRes.GetLogger()
-		logger.Info("TestConnection received body fields: %v", bodyMap)
+		logger.Info("TestConnection received body fields: %v", redactSecrets(bodyMap))
 	}
 
 	// Extract fields with type assertions
@@ -162,10 +241,19 @@ func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 	// Test based on CI tool type
 	switch ciTool {
 	case models.CIToolTektonCI:
+		if tektonSource, _ := bodyMap["tektonSource"].(string); tektonSource == models.TektonSourceKubernetes {
+			conn.CITool, conn.TektonSource = ciTool, tektonSource
+			testErr = testKubernetesConnection(ctx, &conn)
+			if testErr == nil {
+				successMsg = "Successfully read Tekton PipelineRuns through the Kubernetes API"
+			}
+			break
+		}
 		if quayOrg == "" {
 			return nil, errors.BadInput.New("quayOrganization is required for Tekton CI")
 		}
-		testErr = testQuayConnection(gocontext.TODO(), quayOrg)
+		conn.QuayOrganization = quayOrg
+		testErr = testQuayConnection(ctx, &conn)
 		if testErr == nil {
 			successMsg = fmt.Sprintf("Successfully connected to Quay.io organization: %s", quayOrg)
 		}
@@ -176,7 +264,10 @@ func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 		if githubToken == "" {
 			return nil, errors.BadInput.New("githubToken is required for Openshift CI")
 		}
-		testErr = testGitHubConnection(gocontext.TODO(), githubOrg, githubToken)
+		testErr = testGitHubConnection(ctx, githubOrg, githubToken)
+		if testErr == nil {
+			testErr = testGCSBucket(ctx, &conn)
+		}
 		if testErr == nil {
 			successMsg = fmt.Sprintf("Successfully connected to GitHub organization: %s", githubOrg)
 		}
@@ -207,6 +298,10 @@ func TestConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput,
 // @Failure 500  {string} errcode.Error "Internal Error"
 // @Router /plugins/testregistry/connections/{connectionId}/test [POST]
 func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	ctx := gocontext.Background()
+	if input.Request != nil {
+		ctx = input.Request.Context()
+	}
 	connection, err := dsHelper.ConnApi.GetMergedConnection(input)
 	if err != nil {
 		return nil, errors.Convert(err)
@@ -218,10 +313,17 @@ func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResource
 	// Test based on CI tool type
 	switch connection.CITool {
 	case models.CIToolTektonCI:
+		if connection.UsesKubernetes() {
+			testErr = testKubernetesConnection(ctx, connection)
+			if testErr == nil {
+				successMsg = "Successfully read Tekton PipelineRuns through the Kubernetes API"
+			}
+			break
+		}
 		if connection.QuayOrganization == "" {
 			return nil, errors.BadInput.New("quayOrganization is required for Tekton CI")
 		}
-		testErr = testQuayConnection(gocontext.TODO(), connection.QuayOrganization)
+		testErr = testQuayConnection(ctx, connection)
 		if testErr == nil {
 			successMsg = fmt.Sprintf("Successfully connected to Quay.io organization: %s", connection.QuayOrganization)
 		}
@@ -232,7 +334,10 @@ func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResource
 		if connection.GitHubToken == "" {
 			return nil, errors.BadInput.New("githubToken is required for Openshift CI")
 		}
-		testErr = testGitHubConnection(gocontext.TODO(), connection.GitHubOrganization, connection.GitHubToken)
+		testErr = testGitHubConnection(ctx, connection.GitHubOrganization, connection.GitHubToken)
+		if testErr == nil {
+			testErr = testGCSBucket(ctx, connection)
+		}
 		if testErr == nil {
 			successMsg = fmt.Sprintf("Successfully connected to GitHub organization: %s", connection.GitHubOrganization)
 		}
@@ -253,12 +358,12 @@ func TestExistingConnection(input *plugin.ApiResourceInput) (*plugin.ApiResource
 	}, nil
 }
 
-// testQuayConnection pings Quay.io API to verify the organization is accessible
-func testQuayConnection(ctx gocontext.Context, quayOrganization string) errors.Error {
-	// Create API client for Quay.io
-	apiClient, err := api.NewApiClient(ctx, "https://quay.io", nil, 0, "", basicRes)
+// testQuayConnection pings Quay.io API to verify the organization is accessible with the connection's credentials
+func testQuayConnection(ctx gocontext.Context, connection *models.TestRegistryConnection) errors.Error {
+	quayOrganization := connection.QuayOrganization
+	apiClient, err := newQuayApiClient(ctx, connection)
 	if err != nil {
-		return errors.Default.Wrap(err, "failed to create API client")
+		return err
 	}
 
 	// Ping Quay.io by trying to list repositories for the organization
@@ -278,13 +383,60 @@ func testQuayConnection(ctx gocontext.Context, quayOrganization string) errors.E
 		return errors.BadInput.New(fmt.Sprintf("Quay.io organization '%s' not found or not accessible", quayOrganization))
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return errors.Unauthorized.New(fmt.Sprintf("Quay.io rejected the credentials for organization '%s' (status %d)", quayOrganization, resp.StatusCode))
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		return errors.Default.New(fmt.Sprintf("Quay.io API returned status %d for organization '%s'", resp.StatusCode, quayOrganization))
 	}
 
+	// The robot account pulls the artifacts, check that it can log in to the registry
+	if connection.QuayRobotUsername != "" {
+		return tasks.CheckRegistryLogin(ctx, tasks.QuayRegistryURL, connection.QuayRobotUsername, connection.QuayRobotToken)
+	}
+
 	return nil
 }
 
+// newQuayApiClient creates a Quay.io API client sending the connection's credentials, anonymous without them
+func newQuayApiClient(ctx gocontext.Context, connection *models.TestRegistryConnection) (plugin.ApiClient, errors.Error) {
+	apiClient, err := api.NewApiClient(ctx, "https://quay.io", nil, 0, "", basicRes)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to create Quay.io API client")
+	}
+	if authorization := connection.QuayApiAuthorization(); authorization != "" {
+		apiClient.SetHeaders(map[string]string{"Authorization": authorization})
+	}
+	return apiClient, nil
+}
+
+// testGCSBucket checks that the JUnit results bucket of the connection can be listed, when the connection
+// sets its own bucket or credentials instead of reading the public Openshift CI bucket
+func testGCSBucket(ctx gocontext.Context, connection *models.TestRegistryConnection) errors.Error {
+	if strings.TrimSpace(connection.GCSBucket) == "" && strings.TrimSpace(connection.GCSCredentials) == "" {
+		return nil
+	}
+	bucket, err := tasks.NewGCSBucketClient(ctx, connection)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = bucket.Close() }()
+	if err := bucket.CheckAccess(ctx); err != nil {
+		return errors.BadInput.Wrap(err, "failed to list the JUnit results GCS bucket with the connection's credentials")
+	}
+	return nil
+}
+
+// testKubernetesConnection checks that the connection's service account can read Tekton PipelineRuns
+func testKubernetesConnection(ctx gocontext.Context, connection *models.TestRegistryConnection) errors.Error {
+	client, err := tasks.NewKubernetesClient(connection, basicRes.GetLogger())
+	if err != nil {
+		return err
+	}
+	return client.Ping(ctx)
+}
+
 // testGitHubConnection pings GitHub API to verify the organization and token are valid
 func testGitHubConnection(ctx gocontext.Context, githubOrganization, githubToken string) errors.Error {
 	// Create API client for GitHub
@@ -0,0 +1,62 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTektonStatusMapping(t *testing.T) {
+	assert.Nil(t, validateTektonStatusMapping(map[string]interface{}{"name": "tekton"}))
+	assert.Nil(t, validateTektonStatusMapping(map[string]interface{}{"tektonStatusMapping": nil}))
+	assert.Nil(t, validateTektonStatusMapping(map[string]interface{}{
+		"tektonStatusMapping": map[string]interface{}{"CouldntGetTask": "FAILURE", "PipelineRunTimeout": "ABORTED"},
+	}))
+
+	err := validateTektonStatusMapping(map[string]interface{}{
+		"tektonStatusMapping": map[string]interface{}{"CouldntGetTask": "failed"},
+	})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "CouldntGetTask")
+
+	assert.NotNil(t, validateTektonStatusMapping(map[string]interface{}{"tektonStatusMapping": []interface{}{"FAILURE"}}))
+}
+
+func TestValidateGCSSettings(t *testing.T) {
+	key := `{"type": "service_account", "client_email": "junit-reader@project.iam.gserviceaccount.com", "private_key": "key"}`
+	assert.Nil(t, validateGCSSettings(map[string]interface{}{"gcsBucket": "team-results"}))
+	assert.Nil(t, validateGCSSettings(map[string]interface{}{"gcsPrefixTemplate": "ci/{job}/{build}", "gcsCredentials": key}))
+	// The UI sends the masked key back when it wasn't changed
+	assert.Nil(t, validateGCSSettings(map[string]interface{}{"gcsCredentials": utils.SanitizeString(key)}))
+
+	assert.NotNil(t, validateGCSSettings(map[string]interface{}{"gcsPrefixTemplate": "ci/{job}/{id}"}))
+	assert.NotNil(t, validateGCSSettings(map[string]interface{}{"gcsCredentials": `{"type": "authorized_user"}`}))
+}
+
+func TestRedactSecrets(t *testing.T) {
+	body := map[string]interface{}{"ciTool": "Openshift CI", "githubToken": "ghp_secret", "gcsCredentials": `{"private_key": "key"}`, "quayOAuthToken": ""}
+	redacted := redactSecrets(body)
+	assert.Equal(t, "Openshift CI", redacted["ciTool"])
+	assert.Equal(t, "********", redacted["githubToken"])
+	assert.Equal(t, "********", redacted["gcsCredentials"])
+	assert.Equal(t, "", redacted["quayOAuthToken"])
+	assert.Equal(t, "ghp_secret", body["githubToken"])
+}
@@ -0,0 +1,96 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	defaultFailureClusterLimit = 50
+	maxFailureClusterLimit     = 500
+)
+
+// GetFailureClusters lists the failure clusters of the last 7 days stored by the clusterFailureMessages
+// subtask, the ones hitting the most jobs first, e.g. to answer "which failure hit 37 jobs this week".
+//
+// Query parameters:
+//   - scopeId: Only include clusters of this scope (optional)
+//   - minJobs: Only include clusters that hit at least N job runs (default 1)
+//   - signature: Only include the cluster with this signature (optional)
+//   - limit: Maximum number of clusters to return (default 50, max 500)
+func GetFailureClusters(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+
+	minJobs, err := positiveIntQuery(input, "minJobs", 1)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := positiveIntQuery(input, "limit", defaultFailureClusterLimit)
+	if err != nil {
+		return nil, err
+	}
+	if limit > maxFailureClusterLimit {
+		limit = maxFailureClusterLimit
+	}
+
+	filter := "connection_id = ? AND job_count >= ?"
+	args := []interface{}{connectionId, minJobs}
+	if scopeId := input.Query.Get("scopeId"); scopeId != "" {
+		filter += " AND scope_id = ?"
+		args = append(args, scopeId)
+	}
+	if signature := input.Query.Get("signature"); signature != "" {
+		filter += " AND signature = ?"
+		args = append(args, signature)
+	}
+
+	clusters := []models.FailureCluster{}
+	err = basicRes.GetDal().All(&clusters,
+		dal.Where(filter, args...),
+		dal.Orderby("job_count DESC, occurrences DESC, signature"),
+		dal.Limit(limit),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query failure clusters")
+	}
+	return &plugin.ApiResourceOutput{Body: clusters, Status: http.StatusOK}, nil
+}
+
+// positiveIntQuery reads an optional positive integer query parameter
+func positiveIntQuery(input *plugin.ApiResourceInput, name string, defaultValue int) (int, errors.Error) {
+	s := input.Query.Get(name)
+	if s == "" {
+		return defaultValue, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v <= 0 {
+		return 0, errors.BadInput.New(fmt.Sprintf("%s must be a positive integer, got %q", name, s))
+	}
+	return v, nil
+}
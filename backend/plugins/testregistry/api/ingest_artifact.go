@@ -0,0 +1,103 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	contextimpl "github.com/apache/incubator-devlake/impls/context"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
+)
+
+// ingestArtifactRequest names the artifact to ingest: the scope repository, with or
+// without the Quay.io organization of the connection, and a tag or digest
+type ingestArtifactRequest struct {
+	Repo string `json:"repo" mapstructure:"repo"`
+	Ref  string `json:"ref" mapstructure:"ref"`
+}
+
+// ingestScopeFullName returns the scope full name of a repository of the Quay.io organization
+func ingestScopeFullName(quayOrg, repo string) string {
+	repo = strings.Trim(strings.TrimSpace(repo), "/")
+	if strings.HasPrefix(repo, quayOrg+"/") {
+		return repo
+	}
+	return quayOrg + "/" + repo
+}
+
+// PostIngestArtifact pulls one Tekton OCI artifact synchronously, saves its PipelineRuns and
+// JUnit results with the scope config of the repository's scope and returns a summary.
+// Useful to check a new store-pipeline-status task version before it rolls out broadly.
+// Body: {"repo": "konflux-team/release-service", "ref": "<tag or sha256:digest>"}
+func PostIngestArtifact(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.TestRegistryConnection{}
+	if err := connectionHelper.First(connection, input.Params); err != nil {
+		return nil, err
+	}
+	if connection.CITool != models.CIToolTektonCI || connection.UsesKubernetes() {
+		return nil, errors.BadInput.New("artifact ingestion needs a Tekton CI connection reading Quay.io artifacts")
+	}
+
+	var body ingestArtifactRequest
+	if err := api.Decode(input.Body, &body, nil); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid request body")
+	}
+	body.Ref = strings.TrimSpace(body.Ref)
+	if strings.TrimSpace(body.Repo) == "" || body.Ref == "" {
+		return nil, errors.BadInput.New("required fields: repo, ref (tag or digest)")
+	}
+
+	quayOrg := strings.TrimSpace(connection.QuayOrganization)
+	fullName := ingestScopeFullName(quayOrg, body.Repo)
+	scopeDetail, err := dsHelper.ScopeSrv.GetScopeDetail(false, connection.ID, fullName)
+	if err != nil {
+		return nil, errors.NotFound.Wrap(err, fmt.Sprintf("scope %s not found, add it to the connection first", fullName))
+	}
+
+	op := &tasks.TestRegistryOptions{
+		ConnectionId:   connection.ID,
+		FullName:       fullName,
+		ScopeConfig:    scopeDetail.ScopeConfig,
+		CollectionMode: connection.CollectionMode(),
+	}
+	if op.ScopeConfig == nil {
+		op.ScopeConfig = &models.TestRegistryScopeConfig{}
+	}
+	data, err := tasks.NewTestRegistryTaskData(op, connection, basicRes.GetLogger())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if input.Request != nil {
+		ctx = input.Request.Context()
+	}
+	taskCtx := contextimpl.NewStandaloneSubTaskContext(ctx, basicRes, "ingestArtifact", data, pluginName, nil)
+	summary, err := tasks.IngestTektonArtifact(taskCtx, body.Ref)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: summary, Status: http.StatusOK}, nil
+}
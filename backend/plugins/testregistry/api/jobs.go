@@ -0,0 +1,328 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 100
+)
+
+// JobDetail is a CI job with the JUnit files found for it and the suites each of them produced
+type JobDetail struct {
+	models.TestRegistryCIJob
+	JUnitFiles []JUnitFileDetail `json:"junit_files"`
+	// Suites not linked to a file: pushed results and jobs processed before files were recorded
+	UnlinkedSuites []models.TestSuite `json:"unlinked_suites"`
+}
+
+// JUnitFileDetail is a JUnit file of a job with its parse status and the suites read from it
+type JUnitFileDetail struct {
+	models.JUnitFile
+	Suites []models.TestSuite `json:"suites"`
+}
+
+// GetJob returns a CI job with its JUnit files, so a job whose results come from several
+// files shows which file produced which suites and which files failed to parse.
+func GetJob(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	jobId := input.Params["jobId"]
+	if jobId == "" {
+		return nil, errors.BadInput.New("missing jobId")
+	}
+
+	db := basicRes.GetDal()
+	job := &models.TestRegistryCIJob{}
+	if err := db.First(job, dal.Where("connection_id = ? AND job_id = ?", connectionId, jobId)); err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New("job not found")
+		}
+		return nil, errors.Default.Wrap(err, "failed to query job")
+	}
+
+	files := []models.JUnitFile{}
+	if err := db.All(&files, dal.Where("connection_id = ? AND job_id = ?", connectionId, jobId), dal.Orderby("path")); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query JUnit files")
+	}
+	suites := []models.TestSuite{}
+	if err := db.All(&suites, dal.Where("connection_id = ? AND job_id = ?", connectionId, jobId), dal.Orderby("name")); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query test suites")
+	}
+
+	return &plugin.ApiResourceOutput{Body: buildJobDetail(job, files, suites), Status: http.StatusOK}, nil
+}
+
+// buildJobDetail groups the suites of a job under the file they were read from, keeping
+// the order of files and suites
+func buildJobDetail(job *models.TestRegistryCIJob, files []models.JUnitFile, suites []models.TestSuite) *JobDetail {
+	detail := &JobDetail{
+		TestRegistryCIJob: *job,
+		JUnitFiles:        make([]JUnitFileDetail, 0, len(files)),
+		UnlinkedSuites:    []models.TestSuite{},
+	}
+	index := make(map[string]int, len(files))
+	for i, file := range files {
+		index[file.FileId] = i
+		detail.JUnitFiles = append(detail.JUnitFiles, JUnitFileDetail{JUnitFile: file, Suites: []models.TestSuite{}})
+	}
+	for _, suite := range suites {
+		if i, ok := index[suite.FileId]; ok && suite.FileId != "" {
+			detail.JUnitFiles[i].Suites = append(detail.JUnitFiles[i].Suites, suite)
+		} else {
+			detail.UnlinkedSuites = append(detail.UnlinkedSuites, suite)
+		}
+	}
+	return detail
+}
+
+// ListJobs lists the CI jobs of a connection, newest first, e.g. to browse the collected runs
+// without SQL. The body holds the page of "jobs" and the "total" matching the filters.
+//
+// Query parameters:
+//   - scopeId: Only include jobs of this scope (optional)
+//   - jobName: Only include runs of this job (optional)
+//   - jobType: Only include prow or tekton jobs (optional)
+//   - result: Only include jobs with this result, e.g. FAILURE (optional)
+//   - triggerType: Only include jobs with this trigger type, e.g. pull_request (optional)
+//   - since, until: Only include jobs started in this time range, RFC 3339 or YYYY-MM-DD (optional)
+//   - page, pageSize: Pagination (default 1 and 50, pageSize at most 100)
+func ListJobs(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	page, pageSize, err := pageQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	filter, args, err := jobListFilter(connectionId, input.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	db := basicRes.GetDal()
+	total, err := db.Count(dal.From(&models.TestRegistryCIJob{}), dal.Where(filter, args...))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count jobs")
+	}
+	jobs := []models.TestRegistryCIJob{}
+	err = db.All(&jobs,
+		dal.Where(filter, args...),
+		dal.Orderby("started_at DESC, job_id"),
+		dal.Limit(pageSize),
+		dal.Offset((page-1)*pageSize),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query jobs")
+	}
+	return &plugin.ApiResourceOutput{Body: map[string]any{
+		"jobs":     jobs,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	}, Status: http.StatusOK}, nil
+}
+
+// ListJobSuites lists the test suites of a CI job by name. The body holds the page of
+// "suites" and the "total" of the job.
+//
+// Query parameters:
+//   - failedOnly: Only include suites with failed or errored tests (optional)
+//   - page, pageSize: Pagination (default 1 and 50, pageSize at most 100)
+func ListJobSuites(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	jobId := input.Params["jobId"]
+	if jobId == "" {
+		return nil, errors.BadInput.New("missing jobId")
+	}
+	page, pageSize, err := pageQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := "connection_id = ? AND job_id = ?"
+	args := []interface{}{connectionId, jobId}
+	if s := input.Query.Get("failedOnly"); s != "" {
+		failedOnly, boolErr := strconv.ParseBool(s)
+		if boolErr != nil {
+			return nil, errors.BadInput.New(fmt.Sprintf("failedOnly must be a boolean, got %q", s))
+		}
+		if failedOnly {
+			filter += " AND (num_failed > 0 OR num_errors > 0)"
+		}
+	}
+
+	db := basicRes.GetDal()
+	total, err := db.Count(dal.From(&models.TestSuite{}), dal.Where(filter, args...))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count test suites")
+	}
+	suites := []models.TestSuite{}
+	err = db.All(&suites,
+		dal.Where(filter, args...),
+		dal.Orderby("name, suite_id"),
+		dal.Limit(pageSize),
+		dal.Offset((page-1)*pageSize),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query test suites")
+	}
+	return &plugin.ApiResourceOutput{Body: map[string]any{
+		"suites":   suites,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    total,
+	}, Status: http.StatusOK}, nil
+}
+
+// ListSuiteTestCases lists the test cases of a suite of a CI job by name. The body holds the
+// page of "testCases" and the "total" of the suite.
+//
+// Query parameters:
+//   - status: Only include test cases with this status: passed, failed, errored or skipped (optional)
+//   - page, pageSize: Pagination (default 1 and 50, pageSize at most 100)
+func ListSuiteTestCases(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	jobId, suiteId := input.Params["jobId"], input.Params["suiteId"]
+	if jobId == "" || suiteId == "" {
+		return nil, errors.BadInput.New("missing jobId or suiteId")
+	}
+	page, pageSize, err := pageQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := "connection_id = ? AND job_id = ? AND suite_id = ?"
+	args := []interface{}{connectionId, jobId, suiteId}
+	if status := input.Query.Get("status"); status != "" {
+		if !validTestCaseStatuses[status] {
+			return nil, errors.BadInput.New(fmt.Sprintf("unknown status %q", status))
+		}
+		filter += " AND status = ?"
+		args = append(args, status)
+	}
+
+	db := basicRes.GetDal()
+	total, err := db.Count(dal.From(&models.TestCase{}), dal.Where(filter, args...))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count test cases")
+	}
+	testCases := []models.TestCase{}
+	err = db.All(&testCases,
+		dal.Where(filter, args...),
+		dal.Orderby("name, test_case_id"),
+		dal.Limit(pageSize),
+		dal.Offset((page-1)*pageSize),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query test cases")
+	}
+	return &plugin.ApiResourceOutput{Body: map[string]any{
+		"testCases": testCases,
+		"page":      page,
+		"pageSize":  pageSize,
+		"total":     total,
+	}, Status: http.StatusOK}, nil
+}
+
+// validTestCaseStatuses are the statuses of ci_test_cases
+var validTestCaseStatuses = map[string]bool{"passed": true, "failed": true, "errored": true, "skipped": true}
+
+// jobListFilter builds the ci_test_jobs filter of the ListJobs query parameters
+func jobListFilter(connectionId uint64, query url.Values) (string, []interface{}, errors.Error) {
+	filter := "connection_id = ?"
+	args := []interface{}{connectionId}
+	for _, column := range []struct{ param, column string }{
+		{"scopeId", "scope_id"},
+		{"jobName", "job_name"},
+		{"jobType", "job_type"},
+		{"result", "result"},
+		{"triggerType", "trigger_type"},
+	} {
+		if value := query.Get(column.param); value != "" {
+			filter += fmt.Sprintf(" AND %s = ?", column.column)
+			args = append(args, value)
+		}
+	}
+	for _, bound := range []struct{ param, operator string }{{"since", ">="}, {"until", "<"}} {
+		value := query.Get(bound.param)
+		if value == "" {
+			continue
+		}
+		t, err := parseTimeQuery(value, bound.param == "until")
+		if err != nil {
+			return "", nil, errors.BadInput.New(fmt.Sprintf("%s must be an RFC 3339 time or a YYYY-MM-DD date, got %q", bound.param, value))
+		}
+		filter += fmt.Sprintf(" AND started_at %s ?", bound.operator)
+		args = append(args, t)
+	}
+	return filter, args, nil
+}
+
+// parseTimeQuery parses an RFC 3339 time or a YYYY-MM-DD date; with endOfDay a date
+// stands for the start of the next day, so "until" includes the whole day
+func parseTimeQuery(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// pageQuery reads the optional page and pageSize query parameters, pageSize is capped at maxPageSize
+func pageQuery(input *plugin.ApiResourceInput) (int, int, errors.Error) {
+	page, err := positiveIntQuery(input, "page", 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	pageSize, err := positiveIntQuery(input, "pageSize", defaultPageSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	return page, pageSize, nil
+}
@@ -0,0 +1,103 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJobDetail(t *testing.T) {
+	job := &models.TestRegistryCIJob{ConnectionId: 1, JobId: "job-1", JobName: "e2e"}
+	files := []models.JUnitFile{
+		{JobId: "job-1", FileId: "f1", Path: "artifacts/junit_e2e.xml", Status: models.JUnitFileParsed, SuitesCount: 2},
+		{JobId: "job-1", FileId: "f2", Path: "artifacts/junit_upgrade.xml", Status: models.JUnitFileFailed, Error: "XML syntax error on line 5: unexpected EOF"},
+	}
+	suites := []models.TestSuite{
+		{JobId: "job-1", SuiteId: "s1", Name: "e2e", FileId: "f1"},
+		{JobId: "job-1", SuiteId: "s2", Name: "e2e nested", FileId: "f1"},
+		{JobId: "job-1", SuiteId: "s3", Name: "pushed"},
+		{JobId: "job-1", SuiteId: "s4", Name: "unknown file", FileId: "f9"},
+	}
+
+	detail := buildJobDetail(job, files, suites)
+	assert.Equal(t, "e2e", detail.JobName)
+	if assert.Len(t, detail.JUnitFiles, 2) {
+		assert.Equal(t, "artifacts/junit_e2e.xml", detail.JUnitFiles[0].Path)
+		assert.Equal(t, []string{"s1", "s2"}, suiteIds(detail.JUnitFiles[0].Suites))
+		assert.Equal(t, models.JUnitFileFailed, detail.JUnitFiles[1].Status)
+		assert.Empty(t, detail.JUnitFiles[1].Suites)
+		assert.NotNil(t, detail.JUnitFiles[1].Suites)
+	}
+	assert.Equal(t, []string{"s3", "s4"}, suiteIds(detail.UnlinkedSuites))
+}
+
+func TestBuildJobDetailWithoutFiles(t *testing.T) {
+	detail := buildJobDetail(&models.TestRegistryCIJob{JobId: "job-1"}, nil, nil)
+	assert.NotNil(t, detail.JUnitFiles)
+	assert.Empty(t, detail.JUnitFiles)
+	assert.NotNil(t, detail.UnlinkedSuites)
+	assert.Empty(t, detail.UnlinkedSuites)
+}
+
+func suiteIds(suites []models.TestSuite) []string {
+	ids := make([]string, 0, len(suites))
+	for _, s := range suites {
+		ids = append(ids, s.SuiteId)
+	}
+	return ids
+}
+
+func TestJobListFilter(t *testing.T) {
+	query := url.Values{
+		"scopeId":     {"org/repo"},
+		"result":      {"FAILURE"},
+		"triggerType": {"pull_request"},
+		"since":       {"2025-02-01"},
+		"until":       {"2025-02-07"},
+	}
+	filter, args, err := jobListFilter(1, query)
+	assert.Nil(t, err)
+	assert.Equal(t, "connection_id = ? AND scope_id = ? AND result = ? AND trigger_type = ? AND started_at >= ? AND started_at < ?", filter)
+	assert.Equal(t, []interface{}{
+		uint64(1), "org/repo", "FAILURE", "pull_request",
+		time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
+		// A date "until" includes the whole day
+		time.Date(2025, 2, 8, 0, 0, 0, 0, time.UTC),
+	}, args)
+}
+
+func TestJobListFilterWithoutFilters(t *testing.T) {
+	filter, args, err := jobListFilter(1, url.Values{})
+	assert.Nil(t, err)
+	assert.Equal(t, "connection_id = ?", filter)
+	assert.Equal(t, []interface{}{uint64(1)}, args)
+}
+
+func TestJobListFilterTimes(t *testing.T) {
+	_, args, err := jobListFilter(1, url.Values{"until": {"2025-02-07T12:30:00Z"}})
+	assert.Nil(t, err)
+	assert.Equal(t, time.Date(2025, 2, 7, 12, 30, 0, 0, time.UTC), args[1])
+
+	_, _, err = jobListFilter(1, url.Values{"since": {"last week"}})
+	assert.NotNil(t, err)
+}
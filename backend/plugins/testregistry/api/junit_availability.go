@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// JobJUnitAvailability tells whether the runs of a job upload JUnit results
+type JobJUnitAvailability struct {
+	ScopeId          string     `json:"scopeId"`
+	JobName          string     `json:"jobName"`
+	LastRunAt        time.Time  `json:"lastRunAt"`
+	JunitFound       int        `json:"junitFound"`
+	JunitNotFound    int        `json:"junitNotFound"`
+	JunitRate        float64    `json:"junitRate"` // found / (found + not found) * 100 in the last collection
+	LastJunitFoundAt *time.Time `json:"lastJunitFoundAt"`
+	NeverFound       bool       `json:"neverFound"` // no collection ever found JUnit results for the job
+}
+
+// GetJUnitAvailability lists, per job name, whether the runs saved by the last collection that saw
+// the job uploaded JUnit results, so teams can find and fix the pipelines that never upload them.
+// Jobs that never uploaded results come first, then the lowest JUnit rates.
+//
+// Query parameters:
+//   - scopeId: Only include jobs of this scope (optional)
+//   - missingOnly: Only include jobs whose last collected runs had no JUnit results (optional)
+func GetJUnitAvailability(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	missingOnly := false
+	if s := input.Query.Get("missingOnly"); s != "" {
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, errors.BadInput.New("missingOnly must be a boolean")
+		}
+		missingOnly = b
+	}
+
+	filter := "connection_id = ?"
+	args := []interface{}{connectionId}
+	if scopeId := input.Query.Get("scopeId"); scopeId != "" {
+		filter += " AND scope_id = ?"
+		args = append(args, scopeId)
+	}
+	if missingOnly {
+		filter += " AND junit_found = 0"
+	}
+	var rows []models.JUnitAvailability
+	if err := basicRes.GetDal().All(&rows, dal.Where(filter, args...)); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load JUnit availability")
+	}
+	return &plugin.ApiResourceOutput{Body: buildJUnitAvailability(rows), Status: http.StatusOK}, nil
+}
+
+// buildJUnitAvailability computes the JUnit rate of each job and sorts the jobs never uploading
+// results first, then by rate, scope and job name
+func buildJUnitAvailability(rows []models.JUnitAvailability) []JobJUnitAvailability {
+	result := make([]JobJUnitAvailability, 0, len(rows))
+	for _, row := range rows {
+		availability := JobJUnitAvailability{
+			ScopeId:          row.ScopeId,
+			JobName:          row.JobName,
+			LastRunAt:        row.LastRunAt,
+			JunitFound:       row.JunitFound,
+			JunitNotFound:    row.JunitNotFound,
+			LastJunitFoundAt: row.LastJunitFoundAt,
+			NeverFound:       row.LastJunitFoundAt == nil,
+		}
+		if runs := row.JunitFound + row.JunitNotFound; runs > 0 {
+			availability.JunitRate = float64(row.JunitFound) / float64(runs) * 100
+		}
+		result = append(result, availability)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.NeverFound != b.NeverFound {
+			return a.NeverFound
+		}
+		if a.JunitRate != b.JunitRate {
+			return a.JunitRate < b.JunitRate
+		}
+		if a.ScopeId != b.ScopeId {
+			return a.ScopeId < b.ScopeId
+		}
+		return a.JobName < b.JobName
+	})
+	return result
+}
@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildJUnitAvailability(t *testing.T) {
+	lastRunAt := time.Date(2025, 2, 8, 10, 0, 0, 0, time.UTC)
+	foundAt := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	rows := []models.JUnitAvailability{
+		{ScopeId: "konflux-ci/e2e", JobName: "unit", LastRunAt: lastRunAt, JunitFound: 4, LastJunitFoundAt: &lastRunAt},
+		{ScopeId: "konflux-ci/e2e", JobName: "e2e-flaky-upload", LastRunAt: lastRunAt, JunitFound: 1, JunitNotFound: 3, LastJunitFoundAt: &lastRunAt},
+		{ScopeId: "konflux-ci/e2e", JobName: "e2e-regressed", LastRunAt: lastRunAt, JunitNotFound: 2, LastJunitFoundAt: &foundAt},
+		{ScopeId: "konflux-ci/e2e", JobName: "lint", LastRunAt: lastRunAt, JunitNotFound: 5},
+	}
+
+	result := buildJUnitAvailability(rows)
+
+	if assert.Len(t, result, 4) {
+		assert.Equal(t, "lint", result[0].JobName)
+		assert.True(t, result[0].NeverFound)
+		assert.Equal(t, 0.0, result[0].JunitRate)
+
+		assert.Equal(t, "e2e-regressed", result[1].JobName)
+		assert.False(t, result[1].NeverFound)
+		assert.Equal(t, &foundAt, result[1].LastJunitFoundAt)
+
+		assert.Equal(t, "e2e-flaky-upload", result[2].JobName)
+		assert.Equal(t, 25.0, result[2].JunitRate)
+
+		assert.Equal(t, "unit", result[3].JobName)
+		assert.Equal(t, 100.0, result[3].JunitRate)
+	}
+}
@@ -0,0 +1,270 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const defaultMetricsDays = 30
+
+// metricsDimensions are the groupBy values of GetTestTrendMetrics
+var metricsDimensions = map[string]bool{
+	"scope":       true,
+	"scenario":    true,
+	"triggerType": true,
+}
+
+// TestTrendPoint is the outcome of the jobs started in one period (UTC day, or week starting on Monday)
+// for one combination of the grouped dimensions; dimensions that are not grouped by are empty
+type TestTrendPoint struct {
+	Period         string  `json:"period"` // First day of the period, e.g. 2025-02-10
+	ScopeId        string  `json:"scopeId"`
+	Scenario       string  `json:"scenario"` // Prow job name or Tekton scenario
+	TriggerType    string  `json:"triggerType"`
+	Jobs           int     `json:"jobs"`
+	Succeeded      int     `json:"succeeded"`
+	Failed         int     `json:"failed"`
+	Other          int     `json:"other"`    // Aborted, pending and unknown results
+	PassRate       float64 `json:"passRate"` // succeeded / (succeeded + failed) * 100, other results excluded
+	AvgDurationSec float64 `json:"avgDurationSec"`
+	Tests          int64   `json:"tests"`        // Tests of the jobs' top-level suites
+	FailedTests    int64   `json:"failedTests"`  // Failures and errors of the jobs' top-level suites
+	SkippedTests   int64   `json:"skippedTests"` // Skipped tests of the jobs' top-level suites
+	TestPassRate   float64 `json:"testPassRate"` // passed / (tests - skipped) * 100
+}
+
+// metricsJobRow is one job of the metrics query
+type metricsJobRow struct {
+	ConnectionId uint64
+	JobId        string
+	ScopeId      string
+	JobName      string
+	TriggerType  string
+	Result       string
+	StartedAt    time.Time
+	DurationSec  *float64
+}
+
+// metricsSuiteTotals is the sum of the top-level suites of one job
+type metricsSuiteTotals struct {
+	ConnectionId uint64
+	JobId        string
+	Tests        int64
+	FailedTests  int64
+	SkippedTests int64
+}
+
+// metricsOptions are the parsed query parameters of GetTestTrendMetrics
+type metricsOptions struct {
+	weekly  bool
+	groupBy map[string]bool
+}
+
+// GetTestTrendMetrics aggregates the CI jobs and their test suites into daily or weekly pass rates,
+// average durations and failure counts, so Grafana panels can query one endpoint instead of
+// hand-written SQL. Jobs are bucketed by their start time.
+//
+// Query parameters:
+//   - connectionId: Only include jobs of this connection (optional, all connections by default)
+//   - days: Only include jobs started in the last N days (default 30)
+//   - interval: day or week (default day)
+//   - groupBy: Comma-separated dimensions among scope, scenario and triggerType (default scope)
+//   - scopeId, scenario, triggerType: Only include jobs of this scope, scenario or trigger type (optional)
+func GetTestTrendMetrics(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	days, err := positiveIntQuery(input, "days", defaultMetricsDays)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := parseMetricsOptions(input.Query.Get("interval"), input.Query.Get("groupBy"))
+	if err != nil {
+		return nil, err
+	}
+
+	filter := "j.started_at >= ?"
+	args := []interface{}{time.Now().AddDate(0, 0, -days)}
+	if s := input.Query.Get("connectionId"); s != "" {
+		connectionId, parseErr := strconv.ParseUint(s, 10, 64)
+		if parseErr != nil || connectionId == 0 {
+			return nil, errors.BadInput.New("invalid connectionId")
+		}
+		filter += " AND j.connection_id = ?"
+		args = append(args, connectionId)
+	}
+	for _, column := range []struct{ param, column string }{
+		{"scopeId", "scope_id"},
+		{"scenario", "job_name"},
+		{"triggerType", "trigger_type"},
+	} {
+		if value := input.Query.Get(column.param); value != "" {
+			filter += fmt.Sprintf(" AND j.%s = ?", column.column)
+			args = append(args, value)
+		}
+	}
+
+	db := basicRes.GetDal()
+	var jobs []metricsJobRow
+	err = db.All(&jobs,
+		dal.Select("j.connection_id, j.job_id, j.scope_id, j.job_name, j.trigger_type, j.result, j.started_at, j.duration_sec"),
+		dal.From(fmt.Sprintf("%s j", models.TestRegistryCIJob{}.TableName())),
+		dal.Where(filter, args...),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query CI jobs")
+	}
+
+	var suiteTotals []metricsSuiteTotals
+	err = db.All(&suiteTotals,
+		dal.Select("s.connection_id, s.job_id, COALESCE(SUM(s.num_tests), 0) AS tests, "+
+			"COALESCE(SUM(s.num_failed + s.num_errors), 0) AS failed_tests, COALESCE(SUM(s.num_skipped), 0) AS skipped_tests"),
+		dal.From(fmt.Sprintf("%s s", models.TestSuite{}.TableName())),
+		dal.Join(fmt.Sprintf("JOIN %s j ON j.connection_id = s.connection_id AND j.job_id = s.job_id", models.TestRegistryCIJob{}.TableName())),
+		dal.Where(filter+" AND s.parent_suite_id IS NULL", args...),
+		dal.Groupby("s.connection_id, s.job_id"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to sum test suites")
+	}
+
+	return &plugin.ApiResourceOutput{Body: buildTestTrendPoints(jobs, suiteTotals, opts), Status: http.StatusOK}, nil
+}
+
+// parseMetricsOptions checks the interval and groupBy query parameters
+func parseMetricsOptions(interval, groupBy string) (*metricsOptions, errors.Error) {
+	opts := &metricsOptions{groupBy: make(map[string]bool)}
+	switch interval {
+	case "", "day":
+	case "week":
+		opts.weekly = true
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("interval must be day or week, got %q", interval))
+	}
+	if groupBy == "" {
+		groupBy = "scope"
+	}
+	for _, dimension := range strings.Split(groupBy, ",") {
+		dimension = strings.TrimSpace(dimension)
+		if !metricsDimensions[dimension] {
+			return nil, errors.BadInput.New(fmt.Sprintf("unknown groupBy dimension %q, expected scope, scenario or triggerType", dimension))
+		}
+		opts.groupBy[dimension] = true
+	}
+	return opts, nil
+}
+
+// metricsPeriod returns the first day of the UTC day or week (starting on Monday) of t
+func metricsPeriod(t time.Time, weekly bool) string {
+	t = t.UTC()
+	if weekly {
+		t = t.AddDate(0, 0, -((int(t.Weekday()) + 6) % 7))
+	}
+	return t.Format("2006-01-02")
+}
+
+// buildTestTrendPoints buckets the jobs by period and grouped dimensions, adding the suite totals of each
+// job, sorted by scope, scenario, trigger type and period
+func buildTestTrendPoints(jobs []metricsJobRow, suiteTotals []metricsSuiteTotals, opts *metricsOptions) []TestTrendPoint {
+	type jobKey struct {
+		connectionId uint64
+		jobId        string
+	}
+	totals := make(map[jobKey]metricsSuiteTotals, len(suiteTotals))
+	for _, t := range suiteTotals {
+		totals[jobKey{t.ConnectionId, t.JobId}] = t
+	}
+
+	type pointKey struct {
+		period, scopeId, scenario, triggerType string
+	}
+	points := make(map[pointKey]*TestTrendPoint)
+	durations := make(map[pointKey][2]float64) // sum and count of the known durations
+	for _, job := range jobs {
+		key := pointKey{period: metricsPeriod(job.StartedAt, opts.weekly)}
+		if opts.groupBy["scope"] {
+			key.scopeId = job.ScopeId
+		}
+		if opts.groupBy["scenario"] {
+			key.scenario = job.JobName
+		}
+		if opts.groupBy["triggerType"] {
+			key.triggerType = job.TriggerType
+		}
+		point, ok := points[key]
+		if !ok {
+			point = &TestTrendPoint{Period: key.period, ScopeId: key.scopeId, Scenario: key.scenario, TriggerType: key.triggerType}
+			points[key] = point
+		}
+
+		point.Jobs++
+		switch job.Result {
+		case "SUCCESS":
+			point.Succeeded++
+		case "FAILURE":
+			point.Failed++
+		default:
+			point.Other++
+		}
+		if job.DurationSec != nil {
+			d := durations[key]
+			durations[key] = [2]float64{d[0] + *job.DurationSec, d[1] + 1}
+		}
+		if t, ok := totals[jobKey{job.ConnectionId, job.JobId}]; ok {
+			point.Tests += t.Tests
+			point.FailedTests += t.FailedTests
+			point.SkippedTests += t.SkippedTests
+		}
+	}
+
+	result := make([]TestTrendPoint, 0, len(points))
+	for key, point := range points {
+		if decided := point.Succeeded + point.Failed; decided > 0 {
+			point.PassRate = float64(point.Succeeded) / float64(decided) * 100
+		}
+		if d := durations[key]; d[1] > 0 {
+			point.AvgDurationSec = d[0] / d[1]
+		}
+		if executed := point.Tests - point.SkippedTests; executed > 0 {
+			point.TestPassRate = float64(executed-point.FailedTests) / float64(executed) * 100
+		}
+		result = append(result, *point)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.ScopeId != b.ScopeId {
+			return a.ScopeId < b.ScopeId
+		}
+		if a.Scenario != b.Scenario {
+			return a.Scenario < b.Scenario
+		}
+		if a.TriggerType != b.TriggerType {
+			return a.TriggerType < b.TriggerType
+		}
+		return a.Period < b.Period
+	})
+	return result
+}
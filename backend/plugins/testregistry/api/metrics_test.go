@@ -0,0 +1,94 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMetricsOptions(t *testing.T) {
+	opts, err := parseMetricsOptions("", "")
+	require.Nil(t, err)
+	assert.False(t, opts.weekly)
+	assert.Equal(t, map[string]bool{"scope": true}, opts.groupBy)
+
+	opts, err = parseMetricsOptions("week", "scenario, triggerType")
+	require.Nil(t, err)
+	assert.True(t, opts.weekly)
+	assert.Equal(t, map[string]bool{"scenario": true, "triggerType": true}, opts.groupBy)
+
+	_, err = parseMetricsOptions("month", "")
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errors.BadInput, err.GetType())
+	}
+	_, err = parseMetricsOptions("day", "scope,repo")
+	assert.NotNil(t, err)
+}
+
+func TestMetricsPeriod(t *testing.T) {
+	wednesday := time.Date(2025, 2, 12, 23, 30, 0, 0, time.UTC)
+	assert.Equal(t, "2025-02-12", metricsPeriod(wednesday, false))
+	assert.Equal(t, "2025-02-10", metricsPeriod(wednesday, true))
+	sunday := time.Date(2025, 2, 16, 0, 30, 0, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, "2025-02-15", metricsPeriod(sunday, false), "periods are UTC days")
+	assert.Equal(t, "2025-02-10", metricsPeriod(sunday, true))
+}
+
+func TestBuildTestTrendPoints(t *testing.T) {
+	monday := time.Date(2025, 2, 10, 8, 0, 0, 0, time.UTC)
+	duration := func(sec float64) *float64 { return &sec }
+	jobs := []metricsJobRow{
+		{ConnectionId: 1, JobId: "1", ScopeId: "konflux-ci/e2e", JobName: "e2e-aws", TriggerType: "pull_request", Result: "SUCCESS", StartedAt: monday, DurationSec: duration(600)},
+		{ConnectionId: 1, JobId: "2", ScopeId: "konflux-ci/e2e", JobName: "e2e-aws", TriggerType: "periodic", Result: "FAILURE", StartedAt: monday.Add(time.Hour), DurationSec: duration(1200)},
+		{ConnectionId: 1, JobId: "3", ScopeId: "konflux-ci/e2e", JobName: "e2e-gcp", TriggerType: "pull_request", Result: "ABORTED", StartedAt: monday.Add(2 * time.Hour)},
+		{ConnectionId: 1, JobId: "4", ScopeId: "konflux-ci/e2e", JobName: "e2e-aws", TriggerType: "pull_request", Result: "SUCCESS", StartedAt: monday.AddDate(0, 0, 2), DurationSec: duration(300)},
+		{ConnectionId: 2, JobId: "1", ScopeId: "konflux-ci/build", JobName: "unit", TriggerType: "push", Result: "SUCCESS", StartedAt: monday},
+	}
+	suiteTotals := []metricsSuiteTotals{
+		{ConnectionId: 1, JobId: "1", Tests: 10, SkippedTests: 2},
+		{ConnectionId: 1, JobId: "2", Tests: 10, FailedTests: 3, SkippedTests: 2},
+		{ConnectionId: 2, JobId: "1", Tests: 5},
+	}
+
+	daily := buildTestTrendPoints(jobs, suiteTotals, &metricsOptions{groupBy: map[string]bool{"scope": true}})
+	if assert.Len(t, daily, 3) {
+		assert.Equal(t, TestTrendPoint{Period: "2025-02-10", ScopeId: "konflux-ci/build", Jobs: 1, Succeeded: 1, PassRate: 100, Tests: 5, TestPassRate: 100}, daily[0])
+		assert.Equal(t, TestTrendPoint{
+			Period: "2025-02-10", ScopeId: "konflux-ci/e2e", Jobs: 3, Succeeded: 1, Failed: 1, Other: 1, PassRate: 50, AvgDurationSec: 900,
+			Tests: 20, FailedTests: 3, SkippedTests: 4, TestPassRate: 81.25,
+		}, daily[1])
+		assert.Equal(t, "2025-02-12", daily[2].Period)
+		assert.Equal(t, 300.0, daily[2].AvgDurationSec)
+		assert.Equal(t, 0.0, daily[2].TestPassRate, "no suites")
+	}
+
+	weekly := buildTestTrendPoints(jobs[:4], suiteTotals, &metricsOptions{weekly: true, groupBy: map[string]bool{"scenario": true, "triggerType": true}})
+	if assert.Len(t, weekly, 3) {
+		assert.Equal(t, TestTrendPoint{Period: "2025-02-10", Scenario: "e2e-aws", TriggerType: "periodic", Jobs: 1, Failed: 1, AvgDurationSec: 1200, Tests: 10, FailedTests: 3, SkippedTests: 2, TestPassRate: 62.5}, weekly[0])
+		assert.Equal(t, "pull_request", weekly[1].TriggerType)
+		assert.Equal(t, 2, weekly[1].Jobs)
+		assert.Equal(t, 450.0, weekly[1].AvgDurationSec)
+		assert.Equal(t, "e2e-gcp", weekly[2].Scenario)
+		assert.Equal(t, 0.0, weekly[2].PassRate, "aborted jobs don't count")
+	}
+}
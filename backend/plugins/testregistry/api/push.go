@@ -59,6 +59,62 @@ func PostTestResultsByName(input *plugin.ApiResourceInput) (*plugin.ApiResourceO
 	return postTestResultsImpl(input, connection.ID)
 }
 
+// pushRepoRenamer returns the repo renamer of the connection, nil when the connection has no
+// valid repoRenames
+func pushRepoRenamer(connectionId uint64) *tasks.RepoRenamer {
+	connection := &models.TestRegistryConnection{}
+	if err := connectionHelper.FirstById(connection, connectionId); err != nil {
+		return nil
+	}
+	renamer, err := tasks.NewRepoRenamer(connection.RepoRenames)
+	if err != nil {
+		basicRes.GetLogger().Warn(err, "ignoring the repo renames of connection %d", connectionId)
+		return nil
+	}
+	return renamer
+}
+
+// pushCommitShaResolver returns the commit SHA resolver of the connection, resolving short SHAs on GitHub
+// when the connection has a GitHub token
+func pushCommitShaResolver(connectionId uint64) *tasks.CommitShaResolver {
+	connection := &models.TestRegistryConnection{}
+	if err := connectionHelper.FirstById(connection, connectionId); err != nil {
+		return tasks.NewCommitShaResolver(nil)
+	}
+	return tasks.NewCommitShaResolver(connection)
+}
+
+// pushScopeConfig returns the scope config of the pushed job's scope, nil when the scope is unknown or has none
+func pushScopeConfig(connectionId uint64, scopeId string) *models.TestRegistryScopeConfig {
+	scopeDetail, err := dsHelper.ScopeSrv.GetScopeDetail(false, connectionId, scopeId)
+	if err != nil {
+		return nil
+	}
+	return scopeDetail.ScopeConfig
+}
+
+// pushJobNameNormalizer returns the job name normalizer of the scope config of the pushed job's scope,
+// nil when the scope config has no job name rules
+func pushJobNameNormalizer(scopeConfig *models.TestRegistryScopeConfig, scopeId string) *tasks.JobNameNormalizer {
+	normalizer, err := tasks.NewJobNameNormalizer(scopeConfig)
+	if err != nil {
+		basicRes.GetLogger().Warn(err, "ignoring the job name rules of scope %s", scopeId)
+		return nil
+	}
+	return normalizer
+}
+
+// pushTestIdentityNormalizer returns the test identity normalizer of the scope config of the pushed job's scope,
+// nil when the scope config has no suite prefixes or aliases
+func pushTestIdentityNormalizer(scopeConfig *models.TestRegistryScopeConfig, scopeId string) *tasks.TestIdentityNormalizer {
+	normalizer, err := tasks.NewTestIdentityNormalizer(scopeConfig)
+	if err != nil {
+		basicRes.GetLogger().Warn(err, "ignoring the test suite prefixes of scope %s", scopeId)
+		return nil
+	}
+	return normalizer
+}
+
 func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*plugin.ApiResourceOutput, errors.Error) {
 	if input.Request == nil {
 		return nil, errors.BadInput.New("request must be multipart/form-data with job metadata as form fields and JUnit XML as file uploads (field name: junit)")
@@ -78,6 +134,7 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 	if jobId == "" || jobName == "" || organization == "" || repository == "" || result == "" {
 		return nil, errors.BadInput.New("required form fields: jobId, jobName, organization, repository, result")
 	}
+	organization, repository = pushRepoRenamer(connectionId).Rename(organization, repository)
 
 	// Validate field lengths to prevent DB column overflow (job_id is varchar(255), job_name is varchar(500))
 	domainJobId := fmt.Sprintf("testregistry:%d:%s", connectionId, jobId)
@@ -88,6 +145,10 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 	// Read optional form fields
 	jobType := input.Request.FormValue("jobType")
 	commitSha := input.Request.FormValue("commitSha")
+	if commitSha != "" && tasks.NormalizeCommitSha(commitSha) == "" {
+		return nil, errors.BadInput.New(fmt.Sprintf("commitSha must be a hex commit SHA of 4 to 40 characters, got %q", commitSha))
+	}
+	commitSha, commitShaShort := pushCommitShaResolver(connectionId).Resolve(basicRes.GetDal(), basicRes.GetLogger(), organization, repository, commitSha)
 	pullRequestAuthor := input.Request.FormValue("pullRequestAuthor")
 	triggerType := input.Request.FormValue("triggerType")
 	viewUrl := input.Request.FormValue("viewUrl")
@@ -133,6 +194,9 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 	if scopeId == "" {
 		scopeId = repository
 	}
+	scopeConfig := pushScopeConfig(connectionId, scopeId)
+	baseJobName, jobVariant := pushJobNameNormalizer(scopeConfig, scopeId).Normalize(jobName)
+	testIdentityNormalizer := pushTestIdentityNormalizer(scopeConfig, scopeId)
 
 	// Enforce file count limit
 	junitFiles := input.Request.MultipartForm.File["junit"]
@@ -168,10 +232,13 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 		ConnectionId:      connectionId,
 		JobId:             domainJobId,
 		JobName:           jobName,
+		BaseJobName:       baseJobName,
+		JobVariant:        jobVariant,
 		JobType:           jobType,
 		Organization:      organization,
 		Repository:        repository,
 		CommitSHA:         commitSha,
+		CommitShaShort:    commitShaShort,
 		PullRequestNumber: pullRequestNumber,
 		PullRequestAuthor: pullRequestAuthor,
 		TriggerType:       triggerType,
@@ -183,11 +250,6 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 		ScopeId:           scopeId,
 	}
 
-	if dbErr := db.CreateOrUpdate(ciJob); dbErr != nil {
-		err = errors.Default.Wrap(dbErr, "failed to save CI job")
-		return nil, err
-	}
-
 	savedSuites := 0
 	savedCases := 0
 
@@ -241,14 +303,17 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 				return nil, err
 			}
 			testSuite := &models.TestSuite{
-				ConnectionId: connectionId,
-				JobId:        domainJobId,
-				SuiteId:      suiteId,
-				Name:         suite.Name,
-				NumTests:     suite.NumTests,
-				NumFailed:    suite.NumFailed,
-				NumSkipped:   suite.NumSkipped,
-				Duration:     suite.Duration,
+				ConnectionId:  connectionId,
+				JobId:         domainJobId,
+				SuiteId:       suiteId,
+				Name:          suite.Name,
+				NumTests:      suite.NumTests,
+				NumFailed:     suite.NumFailed,
+				NumSkipped:    suite.NumSkipped,
+				NumErrors:     suite.Errors(),
+				NumAssertions: suite.NumAssertions,
+				Timestamp:     tasks.ParseJUnitTimestamp(suite.Timestamp),
+				Duration:      suite.Duration,
 			}
 
 			if dbErr := db.CreateOrUpdate(testSuite); dbErr != nil {
@@ -256,6 +321,10 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 				return nil, err
 			}
 			savedSuites++
+			ciJob.TotalTests += suite.NumTests
+			ciJob.FailedTests += suite.NumFailed + testSuite.NumErrors
+			ciJob.SkippedTests += suite.NumSkipped
+			ciJob.SuitesCount++
 
 			for _, tc := range suite.TestCases {
 				if tc == nil {
@@ -267,20 +336,7 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 					err = uidErr
 					return nil, err
 				}
-				status := "passed"
-				var failureMsg, failureOut, skipMsg *string
-
-				if tc.FailureOutput != nil {
-					status = "failed"
-					msg := tc.FailureOutput.Message
-					failureMsg = &msg
-					out := tc.FailureOutput.Output
-					failureOut = &out
-				} else if tc.SkipMessage != nil {
-					status = "skipped"
-					msg := tc.SkipMessage.Message
-					skipMsg = &msg
-				}
+				status, failureMsg, failureOut, skipMsg := tc.Result()
 
 				testCase := &models.TestCase{
 					ConnectionId:   connectionId,
@@ -289,6 +345,9 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 					TestCaseId:     testCaseId,
 					Name:           tc.Name,
 					Classname:      tc.Classname,
+					TestIdentity:   testIdentityNormalizer.Identity(suite.Name, tc.Name),
+					File:           tc.File,
+					Assertions:     tc.Assertions,
 					Duration:       tc.Duration,
 					Status:         status,
 					FailureMessage: failureMsg,
@@ -305,6 +364,12 @@ func postTestResultsImpl(input *plugin.ApiResourceInput, connectionId uint64) (*
 		}
 	}
 
+	// Saved once its suites are counted
+	if dbErr := db.CreateOrUpdate(ciJob); dbErr != nil {
+		err = errors.Default.Wrap(dbErr, "failed to save CI job")
+		return nil, err
+	}
+
 	return &plugin.ApiResourceOutput{
 		Body: map[string]interface{}{
 			"jobId":       domainJobId,
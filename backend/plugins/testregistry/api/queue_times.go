@@ -0,0 +1,198 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	defaultQueueTimeDays  = 14
+	defaultQueueAlertDays = 30
+)
+
+// QueueTimeBucket is the queue time of the runs of a job on a build cluster queued on one day (UTC)
+type QueueTimeBucket struct {
+	Date      string  `json:"date"`
+	Cluster   string  `json:"cluster"`
+	JobName   string  `json:"jobName"`
+	Runs      int     `json:"runs"`
+	AvgSec    float64 `json:"avgSec"`
+	MedianSec float64 `json:"medianSec"`
+	P90Sec    float64 `json:"p90Sec"`
+	MaxSec    float64 `json:"maxSec"`
+}
+
+// queueTimeRow is the queue time of one run
+type queueTimeRow struct {
+	Cluster           string
+	JobName           string
+	QueuedAt          time.Time
+	QueuedDurationSec float64
+}
+
+// GetQueueTimes aggregates the queue time of the runs of a connection per build cluster, job and day,
+// so infra teams can follow how long jobs wait for a runner. Runs without queue time are left out.
+//
+// Query parameters:
+//   - days: Only include runs queued in the last N days (default 14)
+//   - scopeId: Only include runs of this scope (optional)
+//   - cluster: Only include runs of this build cluster (optional)
+//   - jobName: Only include runs of this job (optional)
+func GetQueueTimes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	days, err := positiveIntQuery(input, "days", defaultQueueTimeDays)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := "connection_id = ? AND queued_at >= ? AND queued_duration_sec IS NOT NULL"
+	args := []interface{}{connectionId, time.Now().AddDate(0, 0, -days)}
+	for _, column := range []struct{ param, column string }{
+		{"scopeId", "scope_id"},
+		{"cluster", "cluster"},
+		{"jobName", "job_name"},
+	} {
+		if value := input.Query.Get(column.param); value != "" {
+			filter += fmt.Sprintf(" AND %s = ?", column.column)
+			args = append(args, value)
+		}
+	}
+
+	var rows []queueTimeRow
+	err = basicRes.GetDal().All(&rows,
+		dal.Select("cluster, job_name, queued_at, queued_duration_sec"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where(filter, args...),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query queue times")
+	}
+	return &plugin.ApiResourceOutput{Body: buildQueueTimeBuckets(rows), Status: http.StatusOK}, nil
+}
+
+// buildQueueTimeBuckets groups the queue times by cluster, job and UTC day of queueing, sorted by
+// cluster, job and date
+func buildQueueTimeBuckets(rows []queueTimeRow) []QueueTimeBucket {
+	type bucketKey struct {
+		cluster, jobName, date string
+	}
+	durations := make(map[bucketKey][]float64)
+	for _, row := range rows {
+		key := bucketKey{row.Cluster, row.JobName, row.QueuedAt.UTC().Format("2006-01-02")}
+		durations[key] = append(durations[key], row.QueuedDurationSec)
+	}
+
+	buckets := make([]QueueTimeBucket, 0, len(durations))
+	for key, values := range durations {
+		sort.Float64s(values)
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		buckets = append(buckets, QueueTimeBucket{
+			Date:      key.date,
+			Cluster:   key.cluster,
+			JobName:   key.jobName,
+			Runs:      len(values),
+			AvgSec:    sum / float64(len(values)),
+			MedianSec: percentile(values, 50),
+			P90Sec:    percentile(values, 90),
+			MaxSec:    values[len(values)-1],
+		})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		a, b := buckets[i], buckets[j]
+		if a.Cluster != b.Cluster {
+			return a.Cluster < b.Cluster
+		}
+		if a.JobName != b.JobName {
+			return a.JobName < b.JobName
+		}
+		return a.Date < b.Date
+	})
+	return buckets
+}
+
+// percentile returns the p-th percentile of sorted values, interpolating between the closest ranks
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// GetQueueAlerts lists the queue saturation alerts of the detectQueueSaturation subtask, newest first:
+// the open ones and the ones opened in the last days.
+//
+// Query parameters:
+//   - days: Include the alerts opened in the last N days (default 30), open alerts are always included
+//   - scopeId: Only include alerts of this scope (optional)
+//   - status: Only include "open" or "resolved" alerts (optional)
+func GetQueueAlerts(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	days, err := positiveIntQuery(input, "days", defaultQueueAlertDays)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := "connection_id = ? AND (resolved_at IS NULL OR opened_at >= ?)"
+	args := []interface{}{connectionId, time.Now().AddDate(0, 0, -days)}
+	if scopeId := input.Query.Get("scopeId"); scopeId != "" {
+		filter += " AND scope_id = ?"
+		args = append(args, scopeId)
+	}
+	switch status := input.Query.Get("status"); status {
+	case "":
+	case "open":
+		filter += " AND resolved_at IS NULL"
+	case "resolved":
+		filter += " AND resolved_at IS NOT NULL"
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("unknown status %q, expected open or resolved", status))
+	}
+
+	alerts := []models.QueueAlert{}
+	err = basicRes.GetDal().All(&alerts,
+		dal.Where(filter, args...),
+		dal.Orderby("opened_at DESC, scope_id, cluster, job_name"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query queue alerts")
+	}
+	return &plugin.ApiResourceOutput{Body: alerts, Status: http.StatusOK}, nil
+}
@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildQueueTimeBuckets(t *testing.T) {
+	day1 := time.Date(2025, 2, 10, 8, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	rows := []queueTimeRow{
+		{Cluster: "build05", JobName: "e2e", QueuedAt: day2, QueuedDurationSec: 600},
+		{Cluster: "build05", JobName: "e2e", QueuedAt: day1, QueuedDurationSec: 30},
+		{Cluster: "build05", JobName: "e2e", QueuedAt: day1.Add(time.Hour), QueuedDurationSec: 10},
+		{Cluster: "build05", JobName: "e2e", QueuedAt: day1.Add(2 * time.Hour), QueuedDurationSec: 20},
+		{Cluster: "build01", JobName: "e2e", QueuedAt: day1, QueuedDurationSec: 5},
+	}
+
+	buckets := buildQueueTimeBuckets(rows)
+
+	if assert.Len(t, buckets, 3) {
+		assert.Equal(t, QueueTimeBucket{Date: "2025-02-10", Cluster: "build01", JobName: "e2e", Runs: 1, AvgSec: 5, MedianSec: 5, P90Sec: 5, MaxSec: 5}, buckets[0])
+		assert.Equal(t, "2025-02-10", buckets[1].Date)
+		assert.Equal(t, 3, buckets[1].Runs)
+		assert.Equal(t, 20.0, buckets[1].AvgSec)
+		assert.Equal(t, 20.0, buckets[1].MedianSec)
+		assert.InDelta(t, 28.0, buckets[1].P90Sec, 0.0001)
+		assert.Equal(t, 30.0, buckets[1].MaxSec)
+		assert.Equal(t, "2025-02-11", buckets[2].Date)
+	}
+	assert.Empty(t, buildQueueTimeBuckets(nil))
+}
+
+func TestPercentile(t *testing.T) {
+	assert.Equal(t, 0.0, percentile(nil, 50))
+	assert.Equal(t, 2.5, percentile([]float64{1, 2, 3, 4}, 50))
+	assert.Equal(t, 4.0, percentile([]float64{1, 2, 3, 4}, 100))
+}
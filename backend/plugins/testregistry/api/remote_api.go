@@ -178,7 +178,7 @@ func listTektonCIScopes(
 	apiURL := "/api/v1/repository"
 	queryParams := url.Values{}
 	queryParams.Set("namespace", connection.QuayOrganization)
-	queryParams.Set("public", "true") // Include public repositories
+	queryParams.Set("public", "true") // Include public repositories, private ones come with the connection's credentials
 
 	if pageToken != "" {
 		// Parse pageToken to extract page number if needed
@@ -193,6 +193,9 @@ func listTektonCIScopes(
 
 	// Check if response is successful
 	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			return nil, "", errors.Unauthorized.New(fmt.Sprintf("Quay.io API returned status %d, check the Quay.io credentials of the connection", resp.StatusCode))
+		}
 		return nil, "", errors.Default.New(fmt.Sprintf("Quay.io API returned status %d", resp.StatusCode))
 	}
 
@@ -270,10 +273,10 @@ func RemoteScopes(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, er
 			})
 		}
 	} else if connection.CITool == models.CIToolTektonCI {
-		// Quay.io API client (no authentication needed for public repos)
-		apiClient, err = api.NewApiClient(gocontext.TODO(), "https://quay.io", nil, 0, "", basicRes)
+		// Quay.io API client, authenticated when the connection has credentials for private repos
+		apiClient, err = newQuayApiClient(gocontext.TODO(), connection)
 		if err != nil {
-			return nil, errors.Default.Wrap(err, "failed to create Quay.io API client")
+			return nil, err
 		}
 	} else {
 		return nil, errors.BadInput.New("ciTool must be either 'Openshift CI' or 'Tekton CI'")
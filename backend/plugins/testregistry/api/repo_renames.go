@@ -0,0 +1,132 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
+)
+
+// MergedRepoRename is the number of CI jobs moved from an old org or repo name to the new one
+type MergedRepoRename struct {
+	tasks.RepoRename
+	Jobs int64 `json:"jobs"`
+}
+
+// validateRepoRenames rejects repoRenames entries that NewRepoRenamer cannot parse, so a typo
+// does not fail the next collection
+func validateRepoRenames(body map[string]interface{}) errors.Error {
+	raw, ok := body["repoRenames"]
+	if !ok || raw == nil {
+		return nil
+	}
+	mapping, ok := raw.(map[string]interface{})
+	if !ok {
+		return errors.BadInput.New("repoRenames must be an object mapping old org/repo names to new ones")
+	}
+	renames := make(map[string]string, len(mapping))
+	for oldName, newName := range mapping {
+		value, ok := newName.(string)
+		if !ok {
+			return errors.BadInput.New(fmt.Sprintf("invalid new name %v for %s", newName, oldName))
+		}
+		renames[oldName] = value
+	}
+	_, err := tasks.NewRepoRenamer(renames)
+	return err
+}
+
+// PostMergeRenamedRepos moves the CI jobs saved under an old org or repo name to the new name,
+// following the connection's repoRenames, so the history of a renamed repo is not split across
+// two identities. Jobs collected after repoRenames was set are already saved under the new name.
+// For a repo rename the jobs saved under the old repo scope also get the new scope_id, which the
+// dashboards filter on; suites and test cases follow their job. All moves run in one transaction.
+//
+// Query parameters:
+//   - dryRun: Only count the jobs that would be moved (optional)
+func PostMergeRenamedRepos(input *plugin.ApiResourceInput) (output *plugin.ApiResourceOutput, err errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	dryRun := input.Query.Get("dryRun") == "true"
+
+	connection := &models.TestRegistryConnection{}
+	if err := connectionHelper.FirstById(connection, connectionId); err != nil {
+		return nil, err
+	}
+	renamer, err := tasks.NewRepoRenamer(connection.RepoRenames)
+	if err != nil {
+		return nil, err
+	}
+
+	txHelper := dbhelper.NewTxHelper(basicRes, &err)
+	defer txHelper.End()
+	db := txHelper.Begin()
+	merged := []MergedRepoRename{}
+	// Repo renames come first, so an org rename does not move a repo renamed to another org
+	for _, rename := range renamer.Renames() {
+		where := renamedJobsFilter(connectionId, rename)
+		jobs, countErr := db.Count(dal.From(&models.TestRegistryCIJob{}), where)
+		if countErr != nil {
+			err = errors.Default.Wrap(countErr, "failed to count jobs of "+rename.OldOrganization)
+			return nil, err
+		}
+		if jobs > 0 && !dryRun {
+			// The scope_id goes first, while the jobs can still be told apart by their old name
+			if rename.OldRepository != "" {
+				scopeSet := []dal.DalSet{{ColumnName: "scope_id", Value: rename.NewRepository}}
+				if updateErr := db.UpdateColumns(&models.TestRegistryCIJob{}, scopeSet, where, dal.Where("scope_id = ?", rename.OldRepository)); updateErr != nil {
+					err = errors.Default.Wrap(updateErr, "failed to move the scope of jobs of "+rename.OldOrganization+"/"+rename.OldRepository)
+					return nil, err
+				}
+			}
+			if updateErr := db.UpdateColumns(&models.TestRegistryCIJob{}, renamedJobsSet(rename), where); updateErr != nil {
+				err = errors.Default.Wrap(updateErr, "failed to move jobs of "+rename.OldOrganization)
+				return nil, err
+			}
+		}
+		merged = append(merged, MergedRepoRename{RepoRename: rename, Jobs: jobs})
+	}
+	return &plugin.ApiResourceOutput{Body: merged, Status: http.StatusOK}, nil
+}
+
+// renamedJobsFilter selects the jobs of the connection saved under the old name of a rename
+func renamedJobsFilter(connectionId uint64, rename tasks.RepoRename) dal.Clause {
+	if rename.OldRepository == "" {
+		return dal.Where("connection_id = ? AND organization = ?", connectionId, rename.OldOrganization)
+	}
+	return dal.Where("connection_id = ? AND organization = ? AND repository = ?", connectionId, rename.OldOrganization, rename.OldRepository)
+}
+
+// renamedJobsSet sets the new name of a rename, the organization only for an org rename
+func renamedJobsSet(rename tasks.RepoRename) []dal.DalSet {
+	set := []dal.DalSet{{ColumnName: "organization", Value: rename.NewOrganization}}
+	if rename.OldRepository != "" {
+		set = append(set, dal.DalSet{ColumnName: "repository", Value: rename.NewRepository})
+	}
+	return set
+}
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateRepoRenames(t *testing.T) {
+	assert.Nil(t, validateRepoRenames(map[string]interface{}{}))
+	assert.Nil(t, validateRepoRenames(map[string]interface{}{"repoRenames": nil}))
+	assert.Nil(t, validateRepoRenames(map[string]interface{}{
+		"repoRenames": map[string]interface{}{
+			"redhat-appstudio":             "konflux-ci",
+			"konflux-ci/build-service-old": "konflux-ci/build-service",
+		},
+	}))
+
+	assert.NotNil(t, validateRepoRenames(map[string]interface{}{"repoRenames": []interface{}{"konflux-ci"}}))
+	assert.NotNil(t, validateRepoRenames(map[string]interface{}{
+		"repoRenames": map[string]interface{}{"redhat-appstudio": 1},
+	}))
+	assert.NotNil(t, validateRepoRenames(map[string]interface{}{
+		"repoRenames": map[string]interface{}{"redhat-appstudio": "konflux-ci/e2e-tests"},
+	}))
+}
+
+func TestRenamedJobsSet(t *testing.T) {
+	orgRename := tasks.RepoRename{OldOrganization: "redhat-appstudio", NewOrganization: "konflux-ci"}
+	assert.Equal(t, []dal.DalSet{{ColumnName: "organization", Value: "konflux-ci"}}, renamedJobsSet(orgRename))
+
+	repoRename := tasks.RepoRename{
+		OldOrganization: "konflux-ci", OldRepository: "build-service-old",
+		NewOrganization: "konflux-ci", NewRepository: "build-service",
+	}
+	assert.Equal(t, []dal.DalSet{
+		{ColumnName: "organization", Value: "konflux-ci"},
+		{ColumnName: "repository", Value: "build-service"},
+	}, renamedJobsSet(repoRename))
+}
@@ -0,0 +1,151 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	// maxJUnitReprocessJobs is the most jobs one reprocess request may queue, larger ranges must be split
+	maxJUnitReprocessJobs = 10000
+	// reprocessRequestBatchSize keeps the inserts of the queued requests under the placeholder limits
+	reprocessRequestBatchSize = 500
+)
+
+// reprocessJob is a job matched by PostReprocessJUnit
+type reprocessJob struct {
+	JobId   string
+	ScopeId string
+}
+
+// JUnitReprocessResult is the answer of PostReprocessJUnit
+type JUnitReprocessResult struct {
+	Matched int  `json:"matched"` // Prow jobs matching the filters
+	Queued  int  `json:"queued"`  // Requests queued, 0 on a dry run
+	DryRun  bool `json:"dryRun"`
+}
+
+// PostReprocessJUnit queues the Prow jobs of a connection for a JUnit re-fetch, so improvements of the
+// JUnit regex or path handling apply to jobs collected before them without a full recollection. The
+// reprocessJUnit subtask of the next collection of each scope fetches their JUnit files again and
+// replaces their suites (see tasks.ReprocessJUnit). Queuing a job again resets its earlier request.
+//
+// Query parameters:
+//   - since: Only include jobs started from this time, RFC 3339 or YYYY-MM-DD (required)
+//   - until: Only include jobs started before this time (optional)
+//   - scopeId, jobName, result, triggerType: Only include matching jobs, e.g. result=FAILURE (optional)
+//   - junitMissing: Only include jobs without any test suite (optional)
+//   - dryRun: Only count the jobs that would be queued (optional)
+func PostReprocessJUnit(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	if input.Query.Get("since") == "" {
+		return nil, errors.BadInput.New("since is required")
+	}
+	if jobType := input.Query.Get("jobType"); jobType != "" && jobType != "prow" {
+		return nil, errors.BadInput.New("only the JUnit files of prow jobs can be reprocessed")
+	}
+	junitMissing, dryRun := false, false
+	for _, flag := range []struct {
+		param string
+		value *bool
+	}{{"junitMissing", &junitMissing}, {"dryRun", &dryRun}} {
+		if s := input.Query.Get(flag.param); s != "" {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, errors.BadInput.New(flag.param + " must be a boolean")
+			}
+			*flag.value = b
+		}
+	}
+
+	connection := &models.TestRegistryConnection{}
+	if err := connectionHelper.FirstById(connection, connectionId); err != nil {
+		return nil, err
+	}
+	if connection.CITool != models.CIToolOpenshiftCI {
+		return nil, errors.BadInput.New("JUnit reprocessing is only supported for Openshift CI connections")
+	}
+
+	filter, args, err := jobListFilter(connectionId, input.Query)
+	if err != nil {
+		return nil, err
+	}
+	filter += " AND job_type = ?"
+	args = append(args, "prow")
+	if junitMissing {
+		filter += " AND suites_count = 0"
+	}
+
+	db := basicRes.GetDal()
+	var jobs []reprocessJob
+	err = db.All(&jobs,
+		dal.Select("job_id, scope_id"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where(filter, args...),
+		dal.Limit(maxJUnitReprocessJobs+1),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query the jobs to reprocess")
+	}
+	if len(jobs) > maxJUnitReprocessJobs {
+		return nil, errors.BadInput.New(fmt.Sprintf("more than %d jobs match, narrow the time range", maxJUnitReprocessJobs))
+	}
+
+	result := JUnitReprocessResult{Matched: len(jobs), DryRun: dryRun}
+	if dryRun || len(jobs) == 0 {
+		return &plugin.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+	}
+	requests := buildReprocessRequests(connectionId, jobs, time.Now())
+	for start := 0; start < len(requests); start += reprocessRequestBatchSize {
+		end := start + reprocessRequestBatchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+		if err := db.CreateOrUpdate(requests[start:end]); err != nil {
+			return nil, errors.Default.Wrap(err, "failed to queue JUnit reprocess requests")
+		}
+		result.Queued = end
+	}
+	return &plugin.ApiResourceOutput{Body: result, Status: http.StatusOK}, nil
+}
+
+// buildReprocessRequests returns a pending request for each job
+func buildReprocessRequests(connectionId uint64, jobs []reprocessJob, now time.Time) []*models.JUnitReprocessRequest {
+	requests := make([]*models.JUnitReprocessRequest, 0, len(jobs))
+	for _, job := range jobs {
+		requests = append(requests, &models.JUnitReprocessRequest{
+			ConnectionId: connectionId,
+			JobId:        job.JobId,
+			ScopeId:      job.ScopeId,
+			RequestedAt:  now,
+		})
+	}
+	return requests
+}
@@ -0,0 +1,62 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostReprocessJUnit_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{name: "since is required", query: url.Values{"result": {"FAILURE"}}},
+		{name: "tekton jobs", query: url.Values{"since": {"2025-02-01"}, "jobType": {"tekton"}}},
+		{name: "invalid junitMissing", query: url.Values{"since": {"2025-02-01"}, "junitMissing": {"maybe"}}},
+		{name: "invalid dryRun", query: url.Values{"since": {"2025-02-01"}, "dryRun": {"yes please"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := PostReprocessJUnit(&plugin.ApiResourceInput{Params: map[string]string{"connectionId": "1"}, Query: tt.query})
+			if assert.NotNil(t, err) {
+				assert.Equal(t, errors.BadInput, err.GetType())
+			}
+		})
+	}
+}
+
+func TestBuildReprocessRequests(t *testing.T) {
+	now := time.Date(2025, 2, 17, 9, 0, 0, 0, time.UTC)
+	requests := buildReprocessRequests(3, []reprocessJob{
+		{JobId: "1890", ScopeId: "konflux-ci/build-service"},
+		{JobId: "1891", ScopeId: "konflux-ci/e2e-tests"},
+	}, now)
+
+	assert.Equal(t, []*models.JUnitReprocessRequest{
+		{ConnectionId: 3, JobId: "1890", ScopeId: "konflux-ci/build-service", RequestedAt: now},
+		{ConnectionId: 3, JobId: "1891", ScopeId: "konflux-ci/e2e-tests", RequestedAt: now},
+	}, requests)
+}
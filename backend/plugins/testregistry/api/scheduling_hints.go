@@ -0,0 +1,205 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	defaultSchedulingDays = 7
+
+	// maxJobsPerRun is the job volume a single collection should stay under
+	maxJobsPerRun = 100
+
+	// maxRecentOnlyIntervalHours caps the interval of sources that only list recent runs:
+	// the Prow jobs API and PipelineRuns not yet pruned from the cluster
+	maxRecentOnlyIntervalHours = 24
+)
+
+// syncSchedule is a sync frequency the hints can suggest, as a blueprint cron expression
+type syncSchedule struct {
+	IntervalHours int
+	Cron          string
+}
+
+// syncSchedules are the suggested frequencies, most frequent first
+var syncSchedules = []syncSchedule{
+	{IntervalHours: 1, Cron: "0 * * * *"},
+	{IntervalHours: 6, Cron: "0 */6 * * *"},
+	{IntervalHours: 24, Cron: "0 0 * * *"},
+	{IntervalHours: 168, Cron: "0 0 * * 1"},
+}
+
+// LastCollectionRun is the volume and duration of the last collection of a scope
+type LastCollectionRun struct {
+	StartedAt       time.Time `json:"startedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+	ItemsListed     int       `json:"itemsListed"`
+	ItemsProcessed  int       `json:"itemsProcessed"`
+	JobsSaved       int       `json:"jobsSaved"`
+	JunitFound      int       `json:"junitFound"`
+}
+
+// SchedulingHint is the estimated collection cost of a scope and the sync frequency
+// suggested for it, for the blueprint planner or the UI
+type SchedulingHint struct {
+	ScopeId        string             `json:"scopeId"`
+	ScopeName      string             `json:"scopeName"`
+	CollectionMode string             `json:"collectionMode"`
+	JobsPerDay     float64            `json:"jobsPerDay"`
+	LastRun        *LastCollectionRun `json:"lastRun"` // nil until the scope was collected once
+	SecondsPerItem float64            `json:"secondsPerItem"`
+
+	SuggestedIntervalHours int     `json:"suggestedIntervalHours"`
+	SuggestedCron          string  `json:"suggestedCron"`
+	EstimatedJobsPerRun    float64 `json:"estimatedJobsPerRun"`
+	EstimatedRunSeconds    float64 `json:"estimatedRunSeconds"` // 0 until the scope was collected once
+	Reason                 string  `json:"reason"`
+}
+
+// scopeJobCount is one row of the per-scope job volume query
+type scopeJobCount struct {
+	ScopeId string
+	Jobs    int64
+}
+
+// GetSchedulingHints estimates what collecting each scope of a connection costs, from the job
+// volume of the last days and the duration of the scope's last collection, and suggests how
+// often to sync it.
+//
+// Query parameters:
+//   - days: Job volume is averaged over the last N days (default 7)
+//   - scopeId: Only include this scope (optional)
+func GetSchedulingHints(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+	days, err := positiveIntQuery(input, "days", defaultSchedulingDays)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := dsHelper.ConnSrv.FindByPk(connectionId)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := "connection_id = ?"
+	args := []interface{}{connectionId}
+	if scopeId := input.Query.Get("scopeId"); scopeId != "" {
+		filter += " AND full_name = ?"
+		args = append(args, scopeId)
+	}
+	db := basicRes.GetDal()
+	var scopes []models.TestRegistryScope
+	if err := db.All(&scopes, dal.Where(filter, args...), dal.Orderby("full_name")); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to list scopes")
+	}
+
+	var jobCounts []scopeJobCount
+	err = db.All(&jobCounts,
+		dal.Select("scope_id, COUNT(*) AS jobs"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND started_at >= ?", connectionId, time.Now().AddDate(0, 0, -days)),
+		dal.Groupby("scope_id"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count jobs per scope")
+	}
+	jobs := make(map[string]int64, len(jobCounts))
+	for _, c := range jobCounts {
+		jobs[c.ScopeId] = c.Jobs
+	}
+
+	var runs []models.CollectionRun
+	if err := db.All(&runs, dal.Where("connection_id = ?", connectionId)); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load collection runs")
+	}
+	lastRuns := make(map[string]*models.CollectionRun, len(runs))
+	for i := range runs {
+		lastRuns[runs[i].ScopeId] = &runs[i]
+	}
+
+	mode := connection.CollectionMode()
+	recentOnly := mode == models.CollectionModeProw || mode == models.CollectionModeKubernetes
+	hints := make([]SchedulingHint, 0, len(scopes))
+	for _, scope := range scopes {
+		jobsPerDay := float64(jobs[scope.FullName]) / float64(days)
+		hint := buildSchedulingHint(jobsPerDay, lastRuns[scope.FullName], recentOnly)
+		hint.ScopeId = scope.FullName
+		hint.ScopeName = scope.Name
+		hint.CollectionMode = mode
+		hints = append(hints, hint)
+	}
+	return &plugin.ApiResourceOutput{Body: hints, Status: http.StatusOK}, nil
+}
+
+// buildSchedulingHint suggests the least frequent sync that keeps each run under maxJobsPerRun
+// jobs, and estimates the run's duration from the seconds per item of the last run.
+// Sources that only list recent runs are synced at least daily so no run is missed.
+func buildSchedulingHint(jobsPerDay float64, lastRun *models.CollectionRun, recentOnly bool) SchedulingHint {
+	hint := SchedulingHint{JobsPerDay: jobsPerDay}
+	if lastRun != nil {
+		hint.LastRun = &LastCollectionRun{
+			StartedAt:       lastRun.StartedAt,
+			DurationSeconds: lastRun.DurationSeconds,
+			ItemsListed:     lastRun.ItemsListed,
+			ItemsProcessed:  lastRun.ItemsProcessed,
+			JobsSaved:       lastRun.JobsSaved,
+			JunitFound:      lastRun.JunitFound,
+		}
+		if lastRun.ItemsProcessed > 0 {
+			hint.SecondsPerItem = lastRun.DurationSeconds / float64(lastRun.ItemsProcessed)
+		}
+	}
+
+	chosen := syncSchedules[0]
+	reason := fmt.Sprintf("keeps each run under %d jobs", maxJobsPerRun)
+	if jobsPerDay == 0 {
+		reason = "no jobs in the period"
+	}
+	for i, schedule := range syncSchedules {
+		if recentOnly && schedule.IntervalHours > maxRecentOnlyIntervalHours {
+			reason = "the source only lists recent runs, so it is synced at least daily"
+			break
+		}
+		if jobsPerDay*float64(schedule.IntervalHours)/24 > maxJobsPerRun {
+			if i == 0 {
+				reason = fmt.Sprintf("even hourly runs collect more than %d jobs", maxJobsPerRun)
+			}
+			break
+		}
+		chosen = schedule
+	}
+
+	hint.SuggestedIntervalHours = chosen.IntervalHours
+	hint.SuggestedCron = chosen.Cron
+	hint.EstimatedJobsPerRun = jobsPerDay * float64(chosen.IntervalHours) / 24
+	hint.EstimatedRunSeconds = hint.EstimatedJobsPerRun * hint.SecondsPerItem
+	hint.Reason = reason
+	return hint
+}
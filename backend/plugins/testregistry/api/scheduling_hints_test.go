@@ -0,0 +1,88 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSchedulingHint(t *testing.T) {
+	tests := []struct {
+		name         string
+		jobsPerDay   float64
+		recentOnly   bool
+		wantInterval int
+		wantCron     string
+		wantReason   string
+	}{
+		{"no jobs syncs weekly", 0, false, 168, "0 0 * * 1", "no jobs in the period"},
+		{"low volume syncs weekly", 10, false, 168, "0 0 * * 1", "keeps each run under 100 jobs"},
+		{"moderate volume syncs daily", 50, false, 24, "0 0 * * *", "keeps each run under 100 jobs"},
+		{"high volume syncs every 6 hours", 300, false, 6, "0 */6 * * *", "keeps each run under 100 jobs"},
+		{"very high volume syncs hourly", 1000, false, 1, "0 * * * *", "keeps each run under 100 jobs"},
+		{"overwhelming volume still syncs hourly", 5000, false, 1, "0 * * * *", "even hourly runs collect more than 100 jobs"},
+		{"recent-only source syncs at least daily", 10, true, 24, "0 0 * * *", "the source only lists recent runs, so it is synced at least daily"},
+		{"recent-only source with volume", 300, true, 6, "0 */6 * * *", "keeps each run under 100 jobs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hint := buildSchedulingHint(tt.jobsPerDay, nil, tt.recentOnly)
+			assert.Equal(t, tt.wantInterval, hint.SuggestedIntervalHours)
+			assert.Equal(t, tt.wantCron, hint.SuggestedCron)
+			assert.Equal(t, tt.wantReason, hint.Reason)
+			assert.Nil(t, hint.LastRun)
+			assert.Zero(t, hint.EstimatedRunSeconds)
+		})
+	}
+}
+
+func TestBuildSchedulingHint_EstimatesRunFromLastRun(t *testing.T) {
+	startedAt := time.Date(2025, 1, 31, 8, 0, 0, 0, time.UTC)
+	hint := buildSchedulingHint(48, &models.CollectionRun{
+		StartedAt:       startedAt,
+		DurationSeconds: 120,
+		ItemsListed:     60,
+		ItemsProcessed:  40,
+		JobsSaved:       40,
+		JunitFound:      30,
+	}, false)
+
+	assert.Equal(t, &LastCollectionRun{
+		StartedAt:       startedAt,
+		DurationSeconds: 120,
+		ItemsListed:     60,
+		ItemsProcessed:  40,
+		JobsSaved:       40,
+		JunitFound:      30,
+	}, hint.LastRun)
+	assert.Equal(t, 3.0, hint.SecondsPerItem)
+	assert.Equal(t, 24, hint.SuggestedIntervalHours)
+	assert.Equal(t, 48.0, hint.EstimatedJobsPerRun)
+	assert.Equal(t, 144.0, hint.EstimatedRunSeconds)
+}
+
+func TestBuildSchedulingHint_EmptyLastRun(t *testing.T) {
+	hint := buildSchedulingHint(0, &models.CollectionRun{DurationSeconds: 2}, false)
+	assert.NotNil(t, hint.LastRun)
+	assert.Zero(t, hint.SecondsPerItem)
+	assert.Zero(t, hint.EstimatedRunSeconds)
+}
@@ -0,0 +1,140 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// minSearchLength is the shortest search term accepted, shorter ones match most test cases
+const minSearchLength = 3
+
+// TestCaseSearchResult is a test case run found by SearchTestCases, with the suite and job it ran in
+type TestCaseSearchResult struct {
+	ConnectionId uint64     `gorm:"column:connection_id" json:"connection_id"`
+	JobId        string     `gorm:"column:job_id" json:"job_id"`
+	SuiteId      string     `gorm:"column:suite_id" json:"suite_id"`
+	TestCaseId   string     `gorm:"column:test_case_id" json:"test_case_id"`
+	Name         string     `gorm:"column:name" json:"name"`
+	Classname    string     `gorm:"column:classname" json:"classname"`
+	TestIdentity string     `gorm:"column:test_identity" json:"test_identity"`
+	Status       string     `gorm:"column:status" json:"status"`
+	Duration     float64    `gorm:"column:duration" json:"duration"`
+	SuiteName    string     `gorm:"column:suite_name" json:"suite_name"`
+	ScopeId      string     `gorm:"column:scope_id" json:"scope_id"`
+	JobName      string     `gorm:"column:job_name" json:"job_name"`
+	JobResult    string     `gorm:"column:job_result" json:"job_result"`
+	StartedAt    *time.Time `gorm:"column:started_at" json:"started_at"`
+	ViewURL      string     `gorm:"column:view_url" json:"view_url"`
+}
+
+// SearchTestCases finds the runs of test cases whose name or classname starts with a search term,
+// across all scopes and most recent job first, so engineers can find where a test runs without
+// knowing its scope. The term is matched as a prefix so that the name and classname indexes are
+// used. The body holds the page of "testCases" and the "total".
+//
+// Served at search/test-cases, where connectionId is an optional query parameter, and at
+// connections/:connectionId/search/test-cases.
+//
+// Query parameters:
+//   - q: Search term, at least 3 characters, matched case-insensitively by the column collation (required)
+//   - connectionId: Only include test cases of this connection (optional)
+//   - status: Only include test cases with this status: passed, failed, errored or skipped (optional)
+//   - page, pageSize: Pagination (default 1 and 50, pageSize at most 100)
+func SearchTestCases(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	var connectionId uint64
+	rawConnectionId, fromPath := input.Params["connectionId"]
+	if !fromPath {
+		rawConnectionId = input.Query.Get("connectionId")
+	}
+	if fromPath || rawConnectionId != "" {
+		var parseErr error
+		connectionId, parseErr = strconv.ParseUint(rawConnectionId, 10, 64)
+		if parseErr != nil || connectionId == 0 {
+			return nil, errors.BadInput.New("invalid connectionId")
+		}
+	}
+	q := strings.TrimSpace(input.Query.Get("q"))
+	if len([]rune(q)) < minSearchLength {
+		return nil, errors.BadInput.New(fmt.Sprintf("q must be at least %d characters", minSearchLength))
+	}
+	page, pageSize, err := pageQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	pattern := likePrefixPattern(q)
+	filter := "(tc.name LIKE ? OR tc.classname LIKE ?)"
+	args := []interface{}{pattern, pattern}
+	if connectionId != 0 {
+		filter += " AND tc.connection_id = ?"
+		args = append(args, connectionId)
+	}
+	if status := input.Query.Get("status"); status != "" {
+		if !validTestCaseStatuses[status] {
+			return nil, errors.BadInput.New(fmt.Sprintf("unknown status %q", status))
+		}
+		filter += " AND tc.status = ?"
+		args = append(args, status)
+	}
+	clauses := []dal.Clause{
+		dal.From(fmt.Sprintf("%s tc", models.TestCase{}.TableName())),
+		dal.Join(fmt.Sprintf("JOIN %s j ON j.connection_id = tc.connection_id AND j.job_id = tc.job_id", models.TestRegistryCIJob{}.TableName())),
+		dal.Join(fmt.Sprintf("LEFT JOIN %s s ON s.connection_id = tc.connection_id AND s.job_id = tc.job_id AND s.suite_id = tc.suite_id", models.TestSuite{}.TableName())),
+		dal.Where(filter, args...),
+	}
+
+	db := basicRes.GetDal()
+	total, err := db.Count(clauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to count matching test cases")
+	}
+	testCases := []TestCaseSearchResult{}
+	err = db.All(&testCases, append(clauses,
+		dal.Select("tc.connection_id, tc.job_id, tc.suite_id, tc.test_case_id, tc.name, tc.classname, tc.test_identity, "+
+			"tc.status, tc.duration, s.name AS suite_name, j.scope_id, j.job_name, j.result AS job_result, j.started_at, j.view_url"),
+		dal.Orderby("j.started_at DESC, tc.job_id, tc.name, tc.test_case_id"),
+		dal.Limit(pageSize),
+		dal.Offset((page-1)*pageSize),
+	)...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to search test cases")
+	}
+	return &plugin.ApiResourceOutput{Body: map[string]any{
+		"testCases": testCases,
+		"page":      page,
+		"pageSize":  pageSize,
+		"total":     total,
+	}, Status: http.StatusOK}, nil
+}
+
+// likePrefixPattern returns the LIKE pattern matching values that start with term, with the LIKE
+// wildcards and escape character of term escaped. A pattern without a leading wildcard can use an index.
+func likePrefixPattern(term string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(term)
+	return escaped + "%"
+}
@@ -0,0 +1,51 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLikePrefixPattern(t *testing.T) {
+	assert.Equal(t, "TestLogin%", likePrefixPattern("TestLogin"))
+	assert.Equal(t, `100\% \_ok\_%`, likePrefixPattern("100% _ok_"))
+	assert.Equal(t, `a\\b%`, likePrefixPattern(`a\b`))
+}
+
+func TestSearchTestCasesBadInput(t *testing.T) {
+	for name, input := range map[string]*plugin.ApiResourceInput{
+		"invalid connection":            {Params: map[string]string{"connectionId": "x"}, Query: url.Values{"q": {"login"}}},
+		"missing term":                  {Params: map[string]string{"connectionId": "1"}, Query: url.Values{}},
+		"short term":                    {Params: map[string]string{"connectionId": "1"}, Query: url.Values{"q": {" ab "}}},
+		"unknown status":                {Params: map[string]string{"connectionId": "1"}, Query: url.Values{"q": {"login"}, "status": {"broken"}}},
+		"invalid page":                  {Params: map[string]string{"connectionId": "1"}, Query: url.Values{"q": {"login"}, "page": {"0"}}},
+		"invalid connection query":      {Params: map[string]string{}, Query: url.Values{"q": {"login"}, "connectionId": {"x"}}},
+		"short term without connection": {Params: map[string]string{}, Query: url.Values{"q": {"ab"}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := SearchTestCases(input)
+			assert.Error(t, err)
+			assert.Equal(t, errors.BadInput, err.GetType())
+		})
+	}
+}
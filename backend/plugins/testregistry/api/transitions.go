@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const defaultTransitionDays = 1
+
+var validTransitions = map[string]bool{
+	models.TransitionPassToFail: true,
+	models.TransitionFailToPass: true,
+	models.TransitionNewJob:     true,
+	models.TransitionRemovedJob: true,
+}
+
+// GetJobTransitions lists the job outcome changes recorded by the diffJobOutcomes subtask,
+// newest first, e.g. to answer "what broke since yesterday".
+//
+// Query parameters:
+//   - days: Only include transitions detected in the last N days (default 1)
+//   - scopeId: Only include transitions of this scope (optional)
+//   - transition: Only include this transition type: pass_to_fail, fail_to_pass, new_job or removed_job (optional)
+func GetJobTransitions(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connectionId, parseErr := strconv.ParseUint(input.Params["connectionId"], 10, 64)
+	if parseErr != nil || connectionId == 0 {
+		return nil, errors.BadInput.New("invalid connectionId")
+	}
+
+	days := defaultTransitionDays
+	if s := input.Query.Get("days"); s != "" {
+		d, err := strconv.Atoi(s)
+		if err != nil || d <= 0 {
+			return nil, errors.BadInput.New(fmt.Sprintf("days must be a positive integer, got %q", s))
+		}
+		days = d
+	}
+
+	filter := "connection_id = ? AND detected_at >= ?"
+	args := []interface{}{connectionId, time.Now().AddDate(0, 0, -days)}
+	if scopeId := input.Query.Get("scopeId"); scopeId != "" {
+		filter += " AND scope_id = ?"
+		args = append(args, scopeId)
+	}
+	if transition := input.Query.Get("transition"); transition != "" {
+		if !validTransitions[transition] {
+			return nil, errors.BadInput.New(fmt.Sprintf("unknown transition %q", transition))
+		}
+		filter += " AND transition = ?"
+		args = append(args, transition)
+	}
+
+	transitions := []models.JobTransition{}
+	err := basicRes.GetDal().All(&transitions,
+		dal.Where(filter, args...),
+		dal.Orderby("detected_at DESC, scope_id, job_name"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to query job transitions")
+	}
+	return &plugin.ApiResourceOutput{Body: transitions, Status: http.StatusOK}, nil
+}
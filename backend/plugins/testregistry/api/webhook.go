@@ -0,0 +1,145 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	contextimpl "github.com/apache/incubator-devlake/impls/context"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
+)
+
+// webhookSecretHeader is the request header holding the shared secret of the connection
+const webhookSecretHeader = "X-Webhook-Secret"
+
+// webhookPayload is the pipeline-status.json of a PipelineRun, as the store-pipeline-status
+// task writes it, with its JUnit reports inline
+type webhookPayload struct {
+	tasks.TektonPipelineRun
+	JUnitFiles []tasks.WebhookJUnitFile `json:"junitFiles"`
+}
+
+// checkWebhookSecret reports whether the secret of a request matches the one of the connection,
+// in constant time. An empty connection secret matches nothing.
+func checkWebhookSecret(expected, got string) bool {
+	if expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(got)) == 1
+}
+
+// decodeWebhookPayload decodes the request body as a pipeline-status.json payload
+func decodeWebhookPayload(body map[string]interface{}) (*webhookPayload, errors.Error) {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid request body")
+	}
+	payload := &webhookPayload{}
+	if err := json.Unmarshal(raw, payload); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid request body")
+	}
+	if strings.TrimSpace(payload.PipelineRunName) == "" {
+		return nil, errors.BadInput.New("required field: pipelineRunName")
+	}
+	for i, file := range payload.JUnitFiles {
+		if strings.TrimSpace(file.Content) == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("junitFiles[%d] has no content", i))
+		}
+	}
+	return payload, nil
+}
+
+// PostWebhook saves a PipelineRun pushed by a Tekton finally-task as soon as it completes, with
+// the scope config of its scope, instead of waiting for the next collection. The request must
+// carry the connection's webhook secret in the X-Webhook-Secret header; the webhook is disabled
+// while the connection has no secret.
+//
+// Query parameters:
+//   - scopeId: Full name of the scope the PipelineRun belongs to (required); for Quay.io
+//     connections the organization prefix may be left out
+//
+// Body: the pipeline-status.json of the PipelineRun, plus an optional
+// "junitFiles": [{"name": "e2e-report.xml", "content": "<testsuites>...</testsuites>"}]
+func PostWebhook(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection := &models.TestRegistryConnection{}
+	if err := connectionHelper.First(connection, input.Params); err != nil {
+		return nil, err
+	}
+	if connection.CITool != models.CIToolTektonCI {
+		return nil, errors.BadInput.New("webhook ingestion needs a Tekton CI connection")
+	}
+	if connection.WebhookSecret == "" {
+		return nil, errors.Forbidden.New("the webhook is disabled, set a webhook secret on the connection first")
+	}
+	var secret string
+	if input.Request != nil {
+		secret = input.Request.Header.Get(webhookSecretHeader)
+	}
+	if !checkWebhookSecret(connection.WebhookSecret, secret) {
+		return nil, errors.Unauthorized.New("invalid or missing " + webhookSecretHeader + " header")
+	}
+
+	fullName := strings.Trim(strings.TrimSpace(input.Query.Get("scopeId")), "/")
+	if fullName == "" {
+		return nil, errors.BadInput.New("scopeId is required")
+	}
+	if !connection.UsesKubernetes() {
+		fullName = ingestScopeFullName(strings.TrimSpace(connection.QuayOrganization), fullName)
+	}
+	payload, err := decodeWebhookPayload(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	scopeDetail, err := dsHelper.ScopeSrv.GetScopeDetail(false, connection.ID, fullName)
+	if err != nil {
+		return nil, errors.NotFound.Wrap(err, fmt.Sprintf("scope %s not found, add it to the connection first", fullName))
+	}
+
+	op := &tasks.TestRegistryOptions{
+		ConnectionId:   connection.ID,
+		FullName:       fullName,
+		ScopeConfig:    scopeDetail.ScopeConfig,
+		CollectionMode: connection.CollectionMode(),
+	}
+	if op.ScopeConfig == nil {
+		op.ScopeConfig = &models.TestRegistryScopeConfig{}
+	}
+	data, err := tasks.NewTestRegistryTaskData(op, connection, basicRes.GetLogger())
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	if input.Request != nil {
+		ctx = input.Request.Context()
+	}
+	taskCtx := contextimpl.NewStandaloneSubTaskContext(ctx, basicRes, "webhook", data, pluginName, nil)
+	summary, err := tasks.IngestTektonWebhook(taskCtx, &payload.TektonPipelineRun, payload.JUnitFiles)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: summary, Status: http.StatusOK}, nil
+}
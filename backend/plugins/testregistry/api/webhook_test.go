@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckWebhookSecret(t *testing.T) {
+	assert.True(t, checkWebhookSecret("s3cret", "s3cret"))
+	assert.False(t, checkWebhookSecret("s3cret", "s3cre"))
+	assert.False(t, checkWebhookSecret("s3cret", ""))
+	assert.False(t, checkWebhookSecret("", ""))
+}
+
+func TestDecodeWebhookPayload(t *testing.T) {
+	payload, err := decodeWebhookPayload(map[string]interface{}{
+		"pipelineRunName": "konflux-e2e-z28lw",
+		"status":          "Succeeded",
+		"scenario":        "konflux-e2e",
+		"git":             map[string]interface{}{"gitOrganization": "konflux-ci", "gitRepository": "build-service"},
+		"junitFiles": []interface{}{
+			map[string]interface{}{"name": "e2e-report.xml", "content": "<testsuites/>"},
+		},
+	})
+	if assert.Nil(t, err) {
+		assert.Equal(t, "konflux-e2e-z28lw", payload.PipelineRunName)
+		assert.Equal(t, "konflux-e2e", payload.Scenario)
+		assert.Equal(t, "build-service", payload.Git.GitRepository)
+		if assert.Len(t, payload.JUnitFiles, 1) {
+			assert.Equal(t, "e2e-report.xml", payload.JUnitFiles[0].Name)
+		}
+	}
+
+	_, err = decodeWebhookPayload(map[string]interface{}{"status": "Succeeded"})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errors.BadInput, err.GetType())
+	}
+
+	_, err = decodeWebhookPayload(map[string]interface{}{
+		"pipelineRunName": "konflux-e2e-z28lw",
+		"junitFiles":      []interface{}{map[string]interface{}{"name": "empty.xml"}},
+	})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,353 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/impl"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/tasks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ciJobFields are the ci_test_jobs columns compared with the golden files
+var ciJobFields = []string{
+	"connection_id", "job_id", "job_name", "job_type", "base_job_name", "job_variant", "organization", "repository",
+	"commit_sha", "pull_request_number", "pull_request_author", "trigger_type", "result", "namespace",
+	"queued_at", "started_at", "finished_at", "duration_sec", "queued_duration_sec", "view_url", "scope_id",
+	"total_tests", "failed_tests", "skipped_tests", "suites_count",
+}
+
+// fixtureResultsFetcher serves the JUnit files recorded under {dir}/{jobId} instead of the GCS bucket
+type fixtureResultsFetcher struct {
+	dir string
+}
+
+func (f fixtureResultsFetcher) GetJobJunitContent(_ context.Context, _, _, _, _, jobId, _, _ string, fileName *regexp.Regexp) ([]tasks.JUnitFile, error) {
+	entries, err := os.ReadDir(filepath.Join(f.dir, jobId))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []tasks.JUnitFile
+	for _, entry := range entries {
+		if !fileName.MatchString(entry.Name()) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(f.dir, jobId, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, tasks.JUnitFile{Content: content, Path: entry.Name()})
+	}
+	return files, nil
+}
+
+// fixtureTagLister lists one Quay.io tag per recorded Tekton artifact
+type fixtureTagLister struct {
+	tags []tasks.QuayTag
+}
+
+func (f fixtureTagLister) ListTags(_ context.Context, _, _ string, _, _ *time.Time) ([]tasks.QuayTag, errors.Error) {
+	return f.tags, nil
+}
+
+// fixtureArtifactPuller copies the artifact recorded under {dir}/{tag} into a fresh
+// directory, as the collector removes every pulled artifact once it is processed
+type fixtureArtifactPuller struct {
+	dir     string
+	pullDir string
+}
+
+func (f fixtureArtifactPuller) PullArtifact(_ context.Context, ref string) (string, errors.Error) {
+	entries, err := os.ReadDir(filepath.Join(f.dir, ref))
+	if os.IsNotExist(err) {
+		return "", errors.NotFound.New(fmt.Sprintf("no recorded artifact for tag %s", ref))
+	}
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	artifactPath, err := os.MkdirTemp(f.pullDir, ref+"-")
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	for _, entry := range entries {
+		content, err := os.ReadFile(filepath.Join(f.dir, ref, entry.Name()))
+		if err != nil {
+			return "", errors.Convert(err)
+		}
+		if err := os.WriteFile(filepath.Join(artifactPath, entry.Name()), content, 0o644); err != nil {
+			return "", errors.Convert(err)
+		}
+	}
+	return artifactPath, nil
+}
+
+// recordedProwServer replays the recorded prowjobs.js response
+func recordedProwServer(t *testing.T, path string) *httptest.Server {
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/"+tasks.ProwJobsPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(content)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func flushCollectorTables(tester *e2ehelper.DataFlowTester) {
+	tester.FlushRawTable("_raw_" + tasks.RAW_PROW_TABLE)
+	tester.FlushTabler(&models.TestRegistryCIJob{})
+	tester.FlushTabler(&models.TestSuite{})
+	tester.FlushTabler(&models.TestCase{})
+	tester.FlushTabler(&models.JUnitFile{})
+	tester.FlushTabler(&models.TektonTask{})
+	tester.FlushTabler(&models.TektonBackfillCursor{})
+	tester.FlushTabler(&models.ProwCollectionCursor{})
+	tester.FlushTabler(&models.ExpiredTag{})
+}
+
+// verifySuitesAndCases compares the saved suites, test cases and JUnit files with their golden
+// files. Suite and test case ids are random, so rows are compared on their content only.
+func verifySuitesAndCases(t *testing.T, tester *e2ehelper.DataFlowTester, connectionId uint64, prefix string) {
+	var suites []models.TestSuite
+	require.NoError(t, tester.Dal.All(&suites, dal.Where("connection_id = ?", connectionId)))
+	suiteRows := make([][]string, 0, len(suites))
+	for _, suite := range suites {
+		suiteRows = append(suiteRows, []string{
+			suite.JobId,
+			suite.Name,
+			strconv.FormatUint(uint64(suite.NumTests), 10),
+			strconv.FormatUint(uint64(suite.NumFailed), 10),
+			strconv.FormatUint(uint64(suite.NumSkipped), 10),
+			strconv.FormatUint(uint64(suite.NumErrors), 10),
+			strconv.FormatFloat(suite.Duration, 'f', -1, 64),
+			suite.RawDataRemark,
+		})
+	}
+	verifyRows(t, fmt.Sprintf("./snapshot_tables/%s_ci_test_suites.csv", prefix), suiteRows)
+
+	var testCases []models.TestCase
+	require.NoError(t, tester.Dal.All(&testCases, dal.Where("connection_id = ?", connectionId)))
+	caseRows := make([][]string, 0, len(testCases))
+	for _, testCase := range testCases {
+		caseRows = append(caseRows, []string{
+			testCase.JobId,
+			testCase.Name,
+			testCase.Classname,
+			testCase.Status,
+			strconv.FormatFloat(testCase.Duration, 'f', -1, 64),
+			stringValue(testCase.FailureMessage),
+			stringValue(testCase.SkipMessage),
+		})
+	}
+	verifyRows(t, fmt.Sprintf("./snapshot_tables/%s_ci_test_cases.csv", prefix), caseRows)
+
+	// linked_suites counts the suites pointing at the file, checking the file-to-suite mapping
+	linkedSuites := make(map[string]int)
+	for _, suite := range suites {
+		linkedSuites[suite.FileId]++
+	}
+	var files []models.JUnitFile
+	require.NoError(t, tester.Dal.All(&files, dal.Where("connection_id = ?", connectionId)))
+	fileRows := make([][]string, 0, len(files))
+	for _, file := range files {
+		fileRows = append(fileRows, []string{
+			file.JobId,
+			file.Path,
+			file.Status,
+			file.Error,
+			strconv.FormatUint(uint64(file.SuitesCount), 10),
+			strconv.FormatUint(uint64(file.TestCasesCount), 10),
+			strconv.Itoa(linkedSuites[file.FileId]),
+		})
+	}
+	verifyRows(t, fmt.Sprintf("./snapshot_tables/%s_ci_test_junit_files.csv", prefix), fileRows)
+}
+
+// verifyRows compares rows, in any order, with the rows of a golden CSV file (without its header)
+func verifyRows(t *testing.T, csvPath string, rows [][]string) {
+	file, err := os.Open(csvPath)
+	require.NoError(t, err)
+	defer file.Close()
+	expected, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	require.NotEmpty(t, expected, "golden file %s has no header", csvPath)
+
+	sort.Slice(rows, func(i, j int) bool {
+		return strings.Join(rows[i], "\x00") < strings.Join(rows[j], "\x00")
+	})
+	assert.Equal(t, expected[1:], rows, csvPath)
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func TestCollectProwJobsDataFlow(t *testing.T) {
+	var plugin impl.TestRegistry
+	tester := e2ehelper.NewDataFlowTester(t, "testregistry", plugin)
+	server := recordedProwServer(t, "./raw_tables/prow/prowjobs.json")
+
+	// Branch variants: pull-ci-<org>-<repo>-main-unit is job pull-ci-<org>-<repo>-unit on main
+	jobNameNormalizer, err := tasks.NewJobNameNormalizer(&models.TestRegistryScopeConfig{
+		JobNameRules: []models.JobNameRule{{Pattern: `^(.*)-(main|release-[0-9.]+)(-.*)?$`, BaseJob: "$1$3"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	taskData := &tasks.TestRegistryTaskData{
+		Options: &tasks.TestRegistryOptions{
+			ConnectionId: 1,
+			FullName:     "integration-service",
+		},
+		Connection: &models.TestRegistryConnection{
+			CITool:             models.CIToolOpenshiftCI,
+			GitHubOrganization: "konflux-ci",
+		},
+		JUnitRegex:             tasks.JUnitRegexpSearch,
+		JobNameNormalizer:      jobNameNormalizer,
+		ResultsFetcherOverride: fixtureResultsFetcher{dir: "./raw_tables/prow/junit"},
+		ProwBaseURLOverride:    server.URL,
+	}
+
+	flushCollectorTables(tester)
+	tester.Subtask(tasks.CollectProwJobsMeta, taskData)
+
+	// Aborted jobs and jobs of other repositories are left out
+	tester.VerifyTableWithOptions(&models.TestRegistryCIJob{}, e2ehelper.TableOptions{
+		CSVRelPath:   "./snapshot_tables/prow_ci_test_jobs.csv",
+		TargetFields: ciJobFields,
+	})
+	verifySuitesAndCases(t, tester, 1, "prow")
+
+	// Collecting the same jobs again must not duplicate their test results
+	tester.Subtask(tasks.CollectProwJobsMeta, taskData)
+	verifySuitesAndCases(t, tester, 1, "prow")
+}
+
+func TestCollectTektonJobsDataFlow(t *testing.T) {
+	var plugin impl.TestRegistry
+	tester := e2ehelper.NewDataFlowTester(t, "testregistry", plugin)
+	t.Setenv("LOGGING_DIR", t.TempDir())
+
+	createdAt := time.Now().Add(-48 * time.Hour).Unix()
+	taskData := &tasks.TestRegistryTaskData{
+		Options: &tasks.TestRegistryOptions{
+			ConnectionId: 2,
+			FullName:     "konflux-test-storage/konflux-team/release-service",
+			ScopeConfig:  &models.TestRegistryScopeConfig{},
+		},
+		Connection: &models.TestRegistryConnection{
+			CITool:           models.CIToolTektonCI,
+			QuayOrganization: "konflux-test-storage",
+		},
+		JUnitRegex: tasks.JUnitRegexpSearch,
+		TagListerOverride: fixtureTagLister{tags: []tasks.QuayTag{
+			{Name: "integration-e2e-x7k2p", StartTS: createdAt},
+			{Name: "integration-e2e-m4q9d", StartTS: createdAt + 3600},
+			{Name: "integration-e2e-expired", StartTS: createdAt + 7200},
+		}},
+		ArtifactPullerOverride: fixtureArtifactPuller{dir: "./raw_tables/tekton", pullDir: t.TempDir()},
+	}
+
+	flushCollectorTables(tester)
+	tester.Subtask(tasks.CollectTektonJobsMeta, taskData)
+
+	tester.VerifyTableWithOptions(&models.TestRegistryCIJob{}, e2ehelper.TableOptions{
+		CSVRelPath:   "./snapshot_tables/tekton_ci_test_jobs.csv",
+		TargetFields: ciJobFields,
+	})
+	tester.VerifyTableWithOptions(&models.TektonTask{}, e2ehelper.TableOptions{
+		CSVRelPath:   "./snapshot_tables/tekton_ci_tekton_tasks.csv",
+		TargetFields: []string{"connection_id", "job_id", "task_name", "status", "duration_sec"},
+	})
+	verifySuitesAndCases(t, tester, 2, "tekton")
+
+	// The tag without a recorded artifact is remembered as expired
+	expired, err := tester.Dal.Count(dal.From(&models.ExpiredTag{}), dal.Where("connection_id = ? AND tag = ?", 2, "integration-e2e-expired"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), expired)
+}
+
+func TestIngestTektonArtifactDataFlow(t *testing.T) {
+	var plugin impl.TestRegistry
+	tester := e2ehelper.NewDataFlowTester(t, "testregistry", plugin)
+	t.Setenv("LOGGING_DIR", t.TempDir())
+
+	taskData := &tasks.TestRegistryTaskData{
+		Options: &tasks.TestRegistryOptions{
+			ConnectionId: 2,
+			FullName:     "konflux-test-storage/konflux-team/release-service",
+			ScopeConfig:  &models.TestRegistryScopeConfig{},
+		},
+		Connection: &models.TestRegistryConnection{
+			CITool:           models.CIToolTektonCI,
+			QuayOrganization: "konflux-test-storage",
+		},
+		JUnitRegex:             tasks.JUnitRegexpSearch,
+		ArtifactPullerOverride: fixtureArtifactPuller{dir: "./raw_tables/tekton", pullDir: t.TempDir()},
+	}
+
+	flushCollectorTables(tester)
+	summary, err := tasks.IngestTektonArtifact(tester.SubtaskContext(taskData), "integration-e2e-x7k2p")
+	require.NoError(t, err)
+	assert.Equal(t, "konflux-test-storage/konflux-team/release-service", summary.Repository)
+	assert.Equal(t, 1, summary.PipelineRuns)
+	require.Len(t, summary.Jobs, 1)
+	assert.Equal(t, "integration-e2e-x7k2p", summary.Jobs[0].JobId)
+	assert.True(t, summary.Jobs[0].JUnitFound)
+	assert.NotZero(t, summary.Jobs[0].TotalTests)
+	assert.Empty(t, summary.CollectedJobIds)
+
+	// Ingesting the same artifact again keeps the saved job as it is
+	summary, err = tasks.IngestTektonArtifact(tester.SubtaskContext(taskData), "integration-e2e-x7k2p")
+	require.NoError(t, err)
+	assert.Empty(t, summary.Jobs)
+	assert.Equal(t, []string{"integration-e2e-x7k2p"}, summary.CollectedJobIds)
+
+	_, err = tasks.IngestTektonArtifact(tester.SubtaskContext(taskData), "integration-e2e-expired")
+	require.Error(t, err)
+}
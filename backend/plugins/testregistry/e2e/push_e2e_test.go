@@ -122,6 +122,9 @@ func TestTestRegistryPush(t *testing.T) {
 		require.Len(t, jobs, 1)
 		require.Equal(t, "FAILURE", jobs[0].Result)
 		require.Equal(t, "test-org", jobs[0].Organization)
+		require.Equal(t, uint(3), jobs[0].TotalTests)
+		require.Equal(t, uint(1), jobs[0].FailedTests)
+		require.Equal(t, uint(1), jobs[0].SuitesCount)
 
 		// Verify ci_test_suites
 		domainJobID := fmt.Sprintf("testregistry:%d:e2e-job-1", connID)
@@ -139,6 +142,7 @@ func TestTestRegistryPush(t *testing.T) {
 		statuses := make(map[string]int)
 		for _, c := range cases {
 			statuses[c.Status]++
+			require.Equal(t, "unit::"+c.Name, c.TestIdentity)
 		}
 		require.Equal(t, 1, statuses["passed"])
 		require.Equal(t, 1, statuses["failed"])
@@ -18,6 +18,8 @@ limitations under the License.
 package impl
 
 import (
+	"fmt"
+
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
@@ -36,6 +38,7 @@ var _ interface {
 	plugin.PluginInit
 	plugin.PluginApi
 	plugin.PluginModel
+	plugin.PluginMetric
 	plugin.PluginMigration
 	plugin.PluginTask
 	plugin.DataSourcePluginBlueprintV200
@@ -53,6 +56,66 @@ func (p TestRegistry) Name() string {
 
 func (p TestRegistry) Init(basicRes context.BasicRes) errors.Error {
 	api.Init(basicRes, p)
+	// Remove working directories left behind by runs that crashed or were killed
+	if removed := tasks.CleanupStaleWorkDirs(tasks.LoggingDir(), tasks.StaleWorkDirAge, basicRes.GetLogger()); removed > 0 {
+		basicRes.GetLogger().Info("removed %d stale testregistry working directories", removed)
+	}
+	return nil
+}
+
+// RequiredDataEntities lists the CI tables the key CI metrics are computed from: job pass rate and
+// duration from ci_test_jobs, suite and test case pass rates, errors and flakiness from ci_test_suites
+// and ci_test_cases. The plugin fills them itself, so they are not expected from another plugin.
+func (p TestRegistry) RequiredDataEntities() (data []map[string]interface{}, err errors.Error) {
+	return []map[string]interface{}{
+		{
+			"model": models.TestRegistryCIJob{}.TableName(),
+			"requiredFields": map[string]string{
+				"job_id":       "string",
+				"job_name":     "string",
+				"scope_id":     "string",
+				"trigger_type": "string",
+				"result":       "string",
+				"started_at":   "datetime",
+				"finished_at":  "datetime",
+			},
+		},
+		{
+			"model": models.TestSuite{}.TableName(),
+			"requiredFields": map[string]string{
+				"job_id":      "string",
+				"suite_id":    "string",
+				"name":        "string",
+				"num_tests":   "uint",
+				"num_failed":  "uint",
+				"num_errors":  "uint",
+				"num_skipped": "uint",
+			},
+		},
+		{
+			"model": models.TestCase{}.TableName(),
+			"requiredFields": map[string]string{
+				"job_id":        "string",
+				"suite_id":      "string",
+				"test_identity": "string",
+				"status":        "string",
+				"duration":      "float64",
+			},
+		},
+	}, nil
+}
+
+// IsProjectMetric is false: the CI metrics are computed per scope, without a project
+func (p TestRegistry) IsProjectMetric() bool {
+	return false
+}
+
+func (p TestRegistry) RunAfter() ([]string, errors.Error) {
+	// The plugin collects its CI jobs and test results itself
+	return []string{}, nil
+}
+
+func (p TestRegistry) Settings() interface{} {
 	return nil
 }
 
@@ -64,6 +127,18 @@ func (p TestRegistry) GetTablesInfo() []dal.Tabler {
 		&models.TestRegistryCIJob{},
 		&models.TestSuite{},
 		&models.TestCase{},
+		&models.ArchivedTestCase{},
+		&models.TektonBackfillCursor{},
+		&models.JobOutcome{},
+		&models.JobTransition{},
+		&models.ExpiredTag{},
+		&models.FailureCluster{},
+		&models.CollectionRun{},
+		&models.JUnitFile{},
+		&models.ProwCollectionCursor{},
+		&models.JUnitAvailability{},
+		&models.QueueAlert{},
+		&models.JUnitReprocessRequest{},
 	}
 }
 
@@ -71,6 +146,16 @@ func (p TestRegistry) SubTaskMetas() []plugin.SubTaskMeta {
 	return []plugin.SubTaskMeta{
 		tasks.CollectProwJobsMeta,
 		tasks.CollectTektonJobsMeta,
+		tasks.CollectKubernetesPipelineRunsMeta,
+		tasks.ReprocessJUnitMeta,
+		tasks.SummarizeProwFailuresMeta,
+		tasks.ConvertCIJobsMeta,
+		tasks.ConvertTestCasesMeta,
+		tasks.GenerateCiIncidentsMeta,
+		tasks.DiffJobOutcomesMeta,
+		tasks.ClusterFailureMessagesMeta,
+		tasks.DetectQueueSaturationMeta,
+		tasks.PruneTestCasesMeta,
 		// Add more tasks here as needed (extractors, converters, etc.)
 	}
 }
@@ -92,23 +177,22 @@ func (p TestRegistry) PrepareTaskData(taskCtx plugin.TaskContext, options map[st
 		return nil, err
 	}
 
-	// Initialize the JUnit regex from connection configuration
-	// Uses default regex if JUnitRegex is empty or invalid
 	logger := taskCtx.GetLogger()
-	junitRegex := tasks.GetJUnitRegexOrDefault(connection.JUnitRegex, logger)
-	if connection.JUnitRegex != "" {
-		logger.Info("Using custom JUnit regex pattern: %s", connection.JUnitRegex)
-	} else {
-		logger.Debug("Using default JUnit regex pattern: %s", tasks.DefaultJUnitRegexPattern)
-	}
 
-	taskData := &tasks.TestRegistryTaskData{
-		Options:    &op,
-		Connection: connection,
-		JUnitRegex: junitRegex,
+	// Pipelines created outside a blueprint may only carry the scope config id
+	if op.ScopeConfig == nil && op.ScopeConfigId != 0 {
+		scopeConfig := &models.TestRegistryScopeConfig{}
+		err = taskCtx.GetDal().First(scopeConfig, dal.Where("id = ?", op.ScopeConfigId))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to load scope config %d", op.ScopeConfigId))
+		}
+		op.ScopeConfig = scopeConfig
+	}
+	if op.CollectionMode != "" && op.CollectionMode != connection.CollectionMode() {
+		logger.Warn(nil, "Blueprint was planned for collection mode %s but the connection now uses %s, update the blueprint", op.CollectionMode, connection.CollectionMode())
 	}
 
-	return taskData, nil
+	return tasks.NewTestRegistryTaskData(&op, connection, logger)
 }
 
 func (p TestRegistry) MakeDataSourcePipelinePlanV200(
@@ -169,6 +253,60 @@ func (p TestRegistry) ApiResources() map[string]map[string]plugin.ApiResourceHan
 			"GET":    api.GetScopeConfig,
 			"DELETE": api.DeleteScopeConfig,
 		},
+		"connections/:connectionId/components": {
+			"GET": api.GetComponentPassRates,
+		},
+		"connections/:connectionId/transitions": {
+			"GET": api.GetJobTransitions,
+		},
+		"connections/:connectionId/failure-clusters": {
+			"GET": api.GetFailureClusters,
+		},
+		"connections/:connectionId/scheduling-hints": {
+			"GET": api.GetSchedulingHints,
+		},
+		"connections/:connectionId/queue-times": {
+			"GET": api.GetQueueTimes,
+		},
+		"connections/:connectionId/queue-alerts": {
+			"GET": api.GetQueueAlerts,
+		},
+		"connections/:connectionId/junit-availability": {
+			"GET": api.GetJUnitAvailability,
+		},
+		"connections/:connectionId/reprocess-junit": {
+			"POST": api.PostReprocessJUnit,
+		},
+		"connections/:connectionId/ingest-artifact": {
+			"POST": api.PostIngestArtifact,
+		},
+		"connections/:connectionId/webhook": {
+			"POST": api.PostWebhook,
+		},
+		"connections/:connectionId/jobs": {
+			"GET": api.ListJobs,
+		},
+		"connections/:connectionId/jobs/:jobId": {
+			"GET": api.GetJob,
+		},
+		"connections/:connectionId/jobs/:jobId/suites": {
+			"GET": api.ListJobSuites,
+		},
+		"connections/:connectionId/jobs/:jobId/suites/:suiteId/test-cases": {
+			"GET": api.ListSuiteTestCases,
+		},
+		"connections/:connectionId/search/test-cases": {
+			"GET": api.SearchTestCases,
+		},
+		"search/test-cases": {
+			"GET": api.SearchTestCases,
+		},
+		"connections/:connectionId/merge-renamed-repos": {
+			"POST": api.PostMergeRenamedRepos,
+		},
+		"metrics": {
+			"GET": api.GetTestTrendMetrics,
+		},
 		"scope-config/:scopeConfigId/projects": {
 			"GET": api.GetProjectsByScopeConfig,
 		},
@@ -37,12 +37,20 @@ type TestRegistryCIJob struct {
 	JobName string `gorm:"type:varchar(500);index" json:"job_name"` // Name of the job/pipeline
 	JobType string `gorm:"type:varchar(50);index" json:"job_type"`  // "prow" or "tekton"
 
+	// Job name without its variant suffix (e.g. -arm64, -ocp4.15), from the scope config jobNameRules, so
+	// dashboards can group the runs of one job across variants; equal to JobName when no rule matches
+	BaseJobName string `gorm:"type:varchar(500);index" json:"base_job_name"`
+	JobVariant  string `gorm:"type:varchar(255);index" json:"job_variant"` // Variant captured by the rule, empty if none
+
 	// Repository and Organization (key identifiers as requested)
 	Organization string `gorm:"type:varchar(255);index" json:"organization"` // GitHub org or Quay org
 	Repository   string `gorm:"type:varchar(255);index" json:"repository"`   // Repository name
 
 	// Git references
-	CommitSHA string `gorm:"type:varchar(40);index" json:"commit_sha"` // Git commit SHA
+	CommitSHA string `gorm:"type:varchar(40);index" json:"commit_sha"` // Git commit SHA, lowercase
+	// CommitShaShort is set when only an abbreviated SHA was reported and it couldn't be resolved to the full
+	// SHA, so CommitSHA won't join the domain commits
+	CommitShaShort bool `gorm:"not null;default:false" json:"commit_sha_short"`
 
 	// Pull Request information (for Prow presubmit, Tekton PR-triggered runs)
 	PullRequestNumber *int   `gorm:"type:int" json:"pull_request_number"` // PR number if triggered by PR
@@ -55,7 +63,8 @@ type TestRegistryCIJob struct {
 	Result string `gorm:"type:varchar(100)" json:"result"` // "SUCCESS", "FAILURE", "ABORTED", etc.
 
 	// Execution environment (optional - only if applicable)
-	Namespace string `gorm:"type:varchar(255)" json:"namespace"` // Kubernetes namespace (if applicable)
+	Namespace string `gorm:"type:varchar(255)" json:"namespace"`     // Kubernetes namespace (if applicable)
+	Cluster   string `gorm:"type:varchar(100);index" json:"cluster"` // Build cluster of Prow jobs (e.g. "build05"), empty for Tekton
 
 	// Timestamps
 	QueuedAt          *time.Time `gorm:"index" json:"queued_at"`   // When job was queued
@@ -64,9 +73,26 @@ type TestRegistryCIJob struct {
 	DurationSec       *float64   `json:"duration_sec"`             // Execution duration in seconds
 	QueuedDurationSec *float64   `json:"queued_duration_sec"`      // Time spent in queue
 
+	// Read from the OCI annotations of Tekton artifacts (see tasks.artifactAnnotationKeys), empty otherwise
+	Application  string `gorm:"type:varchar(255);index" json:"application"`   // Konflux application
+	PipelineName string `gorm:"type:varchar(255);index" json:"pipeline_name"` // Tekton Pipeline the PipelineRun ran
+
 	// URLs
 	ViewURL string `gorm:"type:text" json:"view_url"` // URL to view job in UI
 
+	// Test counts of the job's JUnit results, refreshed at the end of JUnit processing so
+	// dashboards don't need to join ci_test_suites / ci_test_cases for simple totals
+	TotalTests   uint `gorm:"not null;default:0" json:"total_tests"`   // Tests of the top-level suites
+	FailedTests  uint `gorm:"not null;default:0" json:"failed_tests"`  // Failures and errors of the top-level suites
+	SkippedTests uint `gorm:"not null;default:0" json:"skipped_tests"` // Skipped tests of the top-level suites
+	SuitesCount  uint `gorm:"not null;default:0" json:"suites_count"`  // All suites, nested ones included
+
+	// Why a failed Prow job failed, read from its build-log.txt by the optional summarizeProwFailures subtask
+	FailureSignature      string     `gorm:"type:varchar(255);index" json:"failure_signature"` // First scope config failureSignatures pattern found in the log
+	FailureSummary        string     `gorm:"type:text" json:"failure_summary"`                 // Log lines matching the failureSignatures, in log order
+	FailureLogTail        string     `gorm:"type:text" json:"failure_log_tail"`                // Last failureLogLines lines of the log
+	FailureLogCollectedAt *time.Time `json:"failure_log_collected_at"`                         // When the log was looked up, nil before
+
 	// Foreign key to scope (which repository/scope this job belongs to)
 	ScopeId string `gorm:"type:varchar(500);index" json:"scope_id"` // Links to TestRegistryScope.FullName
 }
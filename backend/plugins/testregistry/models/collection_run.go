@@ -0,0 +1,50 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// CollectionRun records the volume and duration of the last collection of a scope, so the
+// scheduling hints API can estimate what a sync costs and suggest how often to run it
+type CollectionRun struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL"`
+	ScopeId      string `gorm:"primaryKey;type:varchar(500)"` // Scope FullName
+
+	// prow, quay or kubernetes, see TestRegistryConnection.CollectionMode
+	CollectionMode string `gorm:"type:varchar(50)"`
+
+	StartedAt       time.Time
+	DurationSeconds float64
+
+	// Prow jobs matching the scope, Quay.io tags in the collection window or PipelineRuns listed
+	ItemsListed int
+	// Of those, the ones processed by this run: a capped Tekton backfill leaves tags to the next run
+	ItemsProcessed int
+	JobsSaved      int
+	JunitFound     int
+}
+
+func (CollectionRun) TableName() string {
+	return "_tool_testregistry_collection_runs"
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package models
 
 import (
+	"encoding/base64"
+
 	"github.com/apache/incubator-devlake/core/utils"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 )
@@ -27,6 +29,22 @@ const (
 	CIToolTektonCI    = "Tekton CI"
 )
 
+// Sources of Tekton PipelineRuns
+const (
+	TektonSourceQuay       = "quay"       // pipeline-status.json in OCI artifacts pushed to Quay.io (default)
+	TektonSourceKubernetes = "kubernetes" // PipelineRun objects read from the Kubernetes API of the cluster running them
+)
+
+// TektonStatusResults are the CI job results a Tekton status can be mapped to
+var TektonStatusResults = []string{"SUCCESS", "FAILURE", "ABORTED", "OTHER"}
+
+// Collection modes, i.e. which collector a connection feeds
+const (
+	CollectionModeProw       = "prow"       // Prow jobs and JUnit results from Openshift CI
+	CollectionModeQuay       = "quay"       // Tekton PipelineRuns from Quay.io OCI artifacts
+	CollectionModeKubernetes = "kubernetes" // Tekton PipelineRuns from the Kubernetes API
+)
+
 type TestRegistryConnection struct {
 	helper.BaseConnection `mapstructure:",squash"`
 	CITool                string `mapstructure:"ciTool" json:"ciTool" validate:"required" gorm:"column:ci_tool;type:varchar(50)"` // CI tool type: Openshift CI or Tekton CI
@@ -36,8 +54,46 @@ type TestRegistryConnection struct {
 	GitHubOrganization string `mapstructure:"githubOrganization" json:"githubOrganization" gorm:"column:github_organization;type:varchar(200)"` // GitHub organization (required when CI tool is Openshift CI)
 	GitHubToken        string `mapstructure:"githubToken" json:"githubToken" gorm:"column:github_token;serializer:encdec"`                      // GitHub token (required when CI tool is Openshift CI, encrypted)
 
+	// Prow artifacts fallback: when the Openshift CI GCS bucket cannot be read (no credentials, listing errors),
+	// JUnit files are listed and downloaded over HTTP from the Prow artifacts browser instead.
+	ProwArtifactsFallback bool   `mapstructure:"prowArtifactsFallback" json:"prowArtifactsFallback" gorm:"column:prow_artifacts_fallback"`    // Enable the HTTP fallback for JUnit collection
+	ProwArtifactsURL      string `mapstructure:"prowArtifactsUrl" json:"prowArtifactsUrl" gorm:"column:prow_artifacts_url;type:varchar(255)"` // Optional, defaults to the Openshift CI gcsweb instance
+
+	// JUnit results bucket: the public Openshift CI bucket, read anonymously, unless teams with private or
+	// alternative buckets set the bucket, the template of a job's artifacts directory in it and a service account key.
+	// Template placeholders: {org}, {repo}, {pull}, {branch}, {job}, {build}, {type} and {default}, the Openshift CI layout.
+	GCSBucket         string `mapstructure:"gcsBucket" json:"gcsBucket" gorm:"column:gcs_bucket;type:varchar(255)"`                          // Optional, defaults to test-platform-results
+	GCSPrefixTemplate string `mapstructure:"gcsPrefixTemplate" json:"gcsPrefixTemplate" gorm:"column:gcs_prefix_template;type:varchar(500)"` // Optional, e.g. "ci/{job}/{build}/artifacts" or "team-a/{default}"
+	GCSCredentials    string `mapstructure:"gcsCredentials" json:"gcsCredentials" gorm:"column:gcs_credentials;serializer:encdec"`           // Optional service account key JSON (encrypted)
+
 	// Tekton CI fields
-	QuayOrganization string `mapstructure:"quayOrganization" json:"quayOrganization" gorm:"column:quay_organization;type:varchar(200)"` // Quay.io organization (required when CI tool is Tekton CI)
+	QuayOrganization string `mapstructure:"quayOrganization" json:"quayOrganization" gorm:"column:quay_organization;type:varchar(200)"` // Quay.io organization (required when CI tool is Tekton CI and source is quay)
+
+	// Quay.io credentials for private repositories, all optional (public repositories are read anonymously).
+	// A robot account ("org+robot") pulls artifacts; an OAuth application token reads the Quay.io API
+	// (repositories and tags) and pulls artifacts when no robot account is set.
+	QuayRobotUsername string `mapstructure:"quayRobotUsername" json:"quayRobotUsername" gorm:"column:quay_robot_username;type:varchar(255)"` // Robot account name, e.g. "org+robot"
+	QuayRobotToken    string `mapstructure:"quayRobotToken" json:"quayRobotToken" gorm:"column:quay_robot_token;serializer:encdec"`          // Robot account token (encrypted)
+	QuayOAuthToken    string `mapstructure:"quayOAuthToken" json:"quayOAuthToken" gorm:"column:quay_oauth_token;serializer:encdec"`          // OAuth application token (encrypted)
+
+	// Tekton CI Kubernetes source: for DevLake instances running in the Konflux cluster.
+	// Scopes are namespaces; the API server and token default to the pod's service account.
+	TektonSource           string `mapstructure:"tektonSource" json:"tektonSource" gorm:"column:tekton_source;type:varchar(50)"`                        // quay (default) or kubernetes
+	KubernetesApiServer    string `mapstructure:"kubernetesApiServer" json:"kubernetesApiServer" gorm:"column:kubernetes_api_server;type:varchar(255)"` // Optional, defaults to the in-cluster API server
+	KubernetesToken        string `mapstructure:"kubernetesToken" json:"kubernetesToken" gorm:"column:kubernetes_token;serializer:encdec"`              // Optional, defaults to the mounted service account token (encrypted)
+	KubernetesWatchSeconds int    `mapstructure:"kubernetesWatchSeconds" json:"kubernetesWatchSeconds" gorm:"column:kubernetes_watch_seconds"`          // Keep watching for new PipelineRuns after listing (0 = list only)
+
+	// TektonStatusMapping maps PipelineRun statuses or condition reasons (e.g. "CouldntGetTask", "PipelineRunTimeout")
+	// to CI job results, looked up before the built-in mapping. Values must be one of TektonStatusResults.
+	TektonStatusMapping map[string]string `mapstructure:"tektonStatusMapping" json:"tektonStatusMapping" gorm:"column:tekton_status_mapping;type:json;serializer:json"`
+
+	// WebhookSecret is the shared secret Tekton finally-tasks send in the X-Webhook-Secret header of
+	// POST connections/:connectionId/webhook; the webhook is disabled while it is empty
+	WebhookSecret string `mapstructure:"webhookSecret" json:"webhookSecret" gorm:"column:webhook_secret;serializer:encdec"` // Optional (encrypted)
+
+	// RepoRenames maps renamed GitHub orgs or repos to their new name, "old-org/old-repo" to "new-org/new-repo"
+	// or "old-org" to "new-org" for every repo of the org. CI jobs are saved under the new name.
+	RepoRenames map[string]string `mapstructure:"repoRenames" json:"repoRenames" gorm:"column:repo_renames;type:json;serializer:json"`
 
 	// JUnit XML file matching configuration
 	// Regex pattern to match JUnit XML file names in artifacts
@@ -49,16 +105,78 @@ func (TestRegistryConnection) TableName() string {
 	return "_tool_testregistry_connections"
 }
 
+// UsesKubernetes reports whether Tekton PipelineRuns are read from the Kubernetes API instead of Quay.io artifacts
+func (c TestRegistryConnection) UsesKubernetes() bool {
+	return c.CITool == CIToolTektonCI && c.TektonSource == TektonSourceKubernetes
+}
+
+// CollectionMode returns the collection mode of the connection, or "" for an unknown CI tool
+func (c TestRegistryConnection) CollectionMode() string {
+	switch {
+	case c.CITool == CIToolOpenshiftCI:
+		return CollectionModeProw
+	case c.UsesKubernetes():
+		return CollectionModeKubernetes
+	case c.CITool == CIToolTektonCI:
+		return CollectionModeQuay
+	}
+	return ""
+}
+
+// QuayRegistryCredential returns the username and password logging in to the Quay.io registry,
+// empty for anonymous pulls. An OAuth token logs in as "$oauthtoken", like `docker login quay.io` does.
+func (c TestRegistryConnection) QuayRegistryCredential() (string, string) {
+	if c.QuayRobotUsername != "" && c.QuayRobotToken != "" {
+		return c.QuayRobotUsername, c.QuayRobotToken
+	}
+	if c.QuayOAuthToken != "" {
+		return "$oauthtoken", c.QuayOAuthToken
+	}
+	return "", ""
+}
+
+// QuayApiAuthorization returns the Authorization header of Quay.io API requests, empty for anonymous access.
+// The OAuth token is preferred; the robot account is sent with basic auth otherwise.
+func (c TestRegistryConnection) QuayApiAuthorization() string {
+	if c.QuayOAuthToken != "" {
+		return "Bearer " + c.QuayOAuthToken
+	}
+	if c.QuayRobotUsername != "" && c.QuayRobotToken != "" {
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(c.QuayRobotUsername+":"+c.QuayRobotToken))
+	}
+	return ""
+}
+
 func (c TestRegistryConnection) Sanitize() TestRegistryConnection {
 	if c.GitHubToken != "" {
 		c.GitHubToken = utils.SanitizeString(c.GitHubToken)
 	}
+	if c.KubernetesToken != "" {
+		c.KubernetesToken = utils.SanitizeString(c.KubernetesToken)
+	}
+	if c.GCSCredentials != "" {
+		c.GCSCredentials = utils.SanitizeString(c.GCSCredentials)
+	}
+	if c.QuayRobotToken != "" {
+		c.QuayRobotToken = utils.SanitizeString(c.QuayRobotToken)
+	}
+	if c.QuayOAuthToken != "" {
+		c.QuayOAuthToken = utils.SanitizeString(c.QuayOAuthToken)
+	}
+	if c.WebhookSecret != "" {
+		c.WebhookSecret = utils.SanitizeString(c.WebhookSecret)
+	}
 	return c
 }
 
 func (connection *TestRegistryConnection) MergeFromRequest(target *TestRegistryConnection, body map[string]interface{}) error {
-	// Preserve existing GitHub token if it wasn't changed (user sent sanitized version)
+	// Preserve existing tokens if they weren't changed (user sent sanitized version)
 	existingToken := target.GitHubToken
+	existingKubernetesToken := target.KubernetesToken
+	existingGCSCredentials := target.GCSCredentials
+	existingQuayRobotToken := target.QuayRobotToken
+	existingQuayOAuthToken := target.QuayOAuthToken
+	existingWebhookSecret := target.WebhookSecret
 	if err := helper.DecodeMapStruct(body, target, true); err != nil {
 		return err
 	}
@@ -68,6 +186,21 @@ func (connection *TestRegistryConnection) MergeFromRequest(target *TestRegistryC
 	if modifiedToken == "" || modifiedToken == utils.SanitizeString(existingToken) {
 		target.GitHubToken = existingToken
 	}
+	if target.KubernetesToken == "" || target.KubernetesToken == utils.SanitizeString(existingKubernetesToken) {
+		target.KubernetesToken = existingKubernetesToken
+	}
+	if target.GCSCredentials == "" || target.GCSCredentials == utils.SanitizeString(existingGCSCredentials) {
+		target.GCSCredentials = existingGCSCredentials
+	}
+	if target.QuayRobotToken == "" || target.QuayRobotToken == utils.SanitizeString(existingQuayRobotToken) {
+		target.QuayRobotToken = existingQuayRobotToken
+	}
+	if target.QuayOAuthToken == "" || target.QuayOAuthToken == utils.SanitizeString(existingQuayOAuthToken) {
+		target.QuayOAuthToken = existingQuayOAuthToken
+	}
+	if target.WebhookSecret == "" || target.WebhookSecret == utils.SanitizeString(existingWebhookSecret) {
+		target.WebhookSecret = existingWebhookSecret
+	}
 
 	return nil
 }
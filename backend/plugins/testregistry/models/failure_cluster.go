@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// FailureCluster groups the failed test cases of a scope whose failure messages are the same once
+// run-specific details (IDs, timestamps, hashes, numbers) are stripped.
+// The clusterFailureMessages subtask replaces the clusters of a scope on every run.
+type FailureCluster struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL" json:"connection_id"`
+	ScopeId      string `gorm:"primaryKey;type:varchar(500)" json:"scope_id"`
+	Signature    string `gorm:"primaryKey;type:varchar(64)" json:"signature"` // sha256 of the normalized message
+
+	NormalizedMessage string `gorm:"type:text" json:"normalized_message"`
+	SampleMessage     string `gorm:"type:text" json:"sample_message"` // Failure message of the latest occurrence
+	SampleTestName    string `gorm:"type:varchar(500)" json:"sample_test_name"`
+
+	Occurrences  int `json:"occurrences"`            // Failed test cases in the cluster
+	JobCount     int `gorm:"index" json:"job_count"` // Distinct job runs hit by the failure
+	JobNameCount int `json:"job_name_count"`         // Distinct job names hit by the failure
+	TestCount    int `json:"test_count"`             // Distinct test cases (classname + name) hit by the failure
+
+	FirstSeenAt   *time.Time `json:"first_seen_at"`
+	LastSeenAt    *time.Time `gorm:"index" json:"last_seen_at"`
+	LatestJobId   string     `gorm:"type:varchar(255)" json:"latest_job_id"`
+	LatestViewURL string     `gorm:"type:text" json:"latest_view_url"`
+}
+
+func (FailureCluster) TableName() string {
+	return "_tool_testregistry_failure_clusters"
+}
@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// Job outcome transition types
+const (
+	TransitionPassToFail = "pass_to_fail"
+	TransitionFailToPass = "fail_to_pass"
+	TransitionNewJob     = "new_job"
+	TransitionRemovedJob = "removed_job"
+)
+
+// JobOutcome is the latest outcome of a job as of the previous pipeline run.
+// The diffJobOutcomes subtask compares the next run against it and then replaces it.
+type JobOutcome struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL" json:"connection_id"`
+	ScopeId      string `gorm:"primaryKey;type:varchar(500)" json:"scope_id"`
+	JobName      string `gorm:"primaryKey;type:varchar(255)" json:"job_name"` // Prow job name or Tekton scenario
+
+	JobId      string     `gorm:"type:varchar(255)" json:"job_id"` // Run that produced the outcome
+	Result     string     `gorm:"type:varchar(100)" json:"result"` // "SUCCESS" or "FAILURE"
+	FinishedAt *time.Time `json:"finished_at"`
+	ViewURL    string     `gorm:"type:text" json:"view_url"`
+}
+
+func (JobOutcome) TableName() string {
+	return "_tool_testregistry_job_outcomes"
+}
+
+// JobTransition is a change of a job's outcome between two pipeline runs
+type JobTransition struct {
+	common.NoPKModel
+
+	ConnectionId uint64    `gorm:"primaryKey;type:BIGINT NOT NULL" json:"connection_id"`
+	ScopeId      string    `gorm:"primaryKey;type:varchar(500)" json:"scope_id"`
+	JobName      string    `gorm:"primaryKey;type:varchar(255)" json:"job_name"`
+	DetectedAt   time.Time `gorm:"primaryKey;index" json:"detected_at"` // When the pipeline run compared the outcomes
+
+	Transition string `gorm:"type:varchar(20);index" json:"transition"` // pass_to_fail, fail_to_pass, new_job or removed_job
+
+	PreviousJobId  string `gorm:"type:varchar(255)" json:"previous_job_id"`
+	PreviousResult string `gorm:"type:varchar(100)" json:"previous_result"`
+	CurrentJobId   string `gorm:"type:varchar(255)" json:"current_job_id"`
+	CurrentResult  string `gorm:"type:varchar(100)" json:"current_result"`
+
+	CurrentFinishedAt *time.Time `json:"current_finished_at"`
+	ViewURL           string     `gorm:"type:text" json:"view_url"` // Run that caused the transition, or the last run of a removed job
+}
+
+func (JobTransition) TableName() string {
+	return "_tool_testregistry_job_transitions"
+}
@@ -0,0 +1,46 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// JUnitAvailability records whether the runs of a job uploaded JUnit results in the last
+// collection that saw the job, so teams can find the jobs that never upload them
+type JUnitAvailability struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL"`
+	ScopeId      string `gorm:"primaryKey;type:varchar(500)"` // Scope FullName
+	JobName      string `gorm:"primaryKey;type:varchar(255)"` // Job name as collected (ci_test_jobs.job_name)
+
+	// Start of the last collection that saved runs of the job
+	LastRunAt time.Time
+	// Runs of the job saved by that collection, with and without JUnit results
+	JunitFound    int
+	JunitNotFound int
+	// Start of the last collection that found JUnit results for the job, nil if none ever did
+	LastJunitFoundAt *time.Time
+}
+
+func (JUnitAvailability) TableName() string {
+	return "_tool_testregistry_junit_availability"
+}
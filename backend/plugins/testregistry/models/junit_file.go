@@ -0,0 +1,54 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// JUnit file parse statuses
+const (
+	JUnitFileParsed = "parsed" // At least one suite was read and saved
+	JUnitFileFailed = "failed" // The XML could not be parsed
+	JUnitFileEmpty  = "empty"  // The file was empty or held no test suites
+)
+
+// JUnitFile records one JUnit XML file found for a CI job and whether it parsed, so a
+// job whose results come from several files shows which ones were lost. Suites saved
+// from the file carry its FileId.
+type JUnitFile struct {
+	common.NoPKModel
+
+	// Primary keys: connection + job + sha256 of the path
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL"`
+	JobId        string `gorm:"primaryKey;type:varchar(255)" json:"job_id"` // Links to TestRegistryCIJob.JobId
+	FileId       string `gorm:"primaryKey;type:varchar(64)" json:"file_id"`
+
+	// GCS object path or path inside the Tekton artifact
+	Path string `gorm:"type:text" json:"path"`
+
+	Status string `gorm:"type:varchar(20);index" json:"status"` // parsed, failed or empty
+	Error  string `gorm:"type:text" json:"error"`               // Why the file was not parsed, empty when parsed
+
+	SuitesCount    uint `json:"suites_count"`     // Suites saved from the file, nested ones included
+	TestCasesCount uint `json:"test_cases_count"` // Test cases saved from the file
+}
+
+func (JUnitFile) TableName() string {
+	return "ci_test_junit_files"
+}
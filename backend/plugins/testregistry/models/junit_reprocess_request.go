@@ -0,0 +1,52 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// JUnit reprocess outcomes
+const (
+	JUnitReprocessParsed      = "parsed"        // JUnit files were found and replaced the job's suites
+	JUnitReprocessFailed      = "failed"        // JUnit files were found but none parsed, the job's suites were kept
+	JUnitReprocessNotFound    = "not_found"     // No JUnit file matched, the job's suites were kept
+	JUnitReprocessJobNotFound = "job_not_found" // The CI job was deleted since the request
+)
+
+// JUnitReprocessRequest asks the reprocessJUnit subtask to fetch the JUnit files of a Prow job again,
+// so a fixed JUnit regex or path handling applies to jobs collected before the fix.
+// POST connections/:connectionId/reprocess-junit queues the requests.
+type JUnitReprocessRequest struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL" json:"connection_id"`
+	JobId        string `gorm:"primaryKey;type:varchar(255)" json:"job_id"`
+	ScopeId      string `gorm:"type:varchar(500);index" json:"scope_id"`
+
+	RequestedAt time.Time  `json:"requested_at"`
+	ProcessedAt *time.Time `gorm:"index" json:"processed_at"`       // nil until the subtask handled the request
+	Outcome     string     `gorm:"type:varchar(20)" json:"outcome"` // One of the JUnitReprocess* outcomes, empty while pending
+	SuitesCount uint       `json:"suites_count"`                    // Suites of the job after reprocessing
+}
+
+func (JUnitReprocessRequest) TableName() string {
+	return "_tool_testregistry_junit_reprocess_requests"
+}
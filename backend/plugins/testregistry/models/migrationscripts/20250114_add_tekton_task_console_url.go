@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addTektonTaskConsoleUrl)(nil)
+
+type addTektonTaskConsoleUrl struct{}
+
+func (*addTektonTaskConsoleUrl) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	// Add console_url column to ci_tekton_tasks table
+	err := db.Exec("ALTER TABLE ci_tekton_tasks ADD COLUMN console_url VARCHAR(1000)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+			return errors.Default.Wrap(err, "failed to add console_url column")
+		}
+	}
+
+	return nil
+}
+
+func (*addTektonTaskConsoleUrl) Version() uint64 {
+	return 20250114000001
+}
+
+func (*addTektonTaskConsoleUrl) Name() string {
+	return "add console_url column to ci_tekton_tasks table for failed task deep links"
+}
@@ -0,0 +1,64 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addPassedCasesSampling)(nil)
+
+type addPassedCasesSampling struct{}
+
+func (*addPassedCasesSampling) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		table  string
+		column string
+		ddl    string
+	}{
+		{"_tool_testregistry_scope_configs", "passed_cases_mode", "VARCHAR(20)"},
+		{"_tool_testregistry_scope_configs", "passed_cases_sample_percent", "INT"},
+		{"ci_test_suites", "num_passed_omitted", "INT DEFAULT 0"},
+	}
+
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (*addPassedCasesSampling) Version() uint64 {
+	return 20250115000001
+}
+
+func (*addPassedCasesSampling) Name() string {
+	return "add passed test case sampling to testregistry scope configs and ci_test_suites"
+}
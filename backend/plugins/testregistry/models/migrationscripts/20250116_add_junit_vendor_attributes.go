@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addJUnitVendorAttributes)(nil)
+
+type addJUnitVendorAttributes struct{}
+
+func (*addJUnitVendorAttributes) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		table  string
+		column string
+		ddl    string
+	}{
+		{"ci_test_suites", "num_errors", "INT DEFAULT 0"},
+		{"ci_test_suites", "num_assertions", "INT DEFAULT 0"},
+		{"ci_test_suites", "timestamp", "TIMESTAMP NULL"},
+		{"ci_test_cases", "file", "VARCHAR(500)"},
+		{"ci_test_cases", "assertions", "INT DEFAULT 0"},
+	}
+
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (*addJUnitVendorAttributes) Version() uint64 {
+	return 20250116000001
+}
+
+func (*addJUnitVendorAttributes) Name() string {
+	return "add JUnit errors, timestamp, file and assertions attributes to ci_test_suites and ci_test_cases"
+}
@@ -0,0 +1,46 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+var _ plugin.MigrationScript = (*addJobOutcomeTransitions)(nil)
+
+type addJobOutcomeTransitions struct{}
+
+func (*addJobOutcomeTransitions) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&models.JobOutcome{},
+		&models.JobTransition{},
+	)
+}
+
+func (*addJobOutcomeTransitions) Version() uint64 {
+	return 20250121000001
+}
+
+func (*addJobOutcomeTransitions) Name() string {
+	return "add _tool_testregistry_job_outcomes and _tool_testregistry_job_transitions tables"
+}
@@ -0,0 +1,52 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addTektonStatusMapping)(nil)
+
+type addTektonStatusMapping struct{}
+
+func (*addTektonStatusMapping) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	err := db.Exec("ALTER TABLE _tool_testregistry_connections ADD COLUMN tekton_status_mapping JSON")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+			return errors.Default.Wrap(err, "failed to add tekton_status_mapping column")
+		}
+	}
+
+	return nil
+}
+
+func (*addTektonStatusMapping) Version() uint64 {
+	return 20250124000001
+}
+
+func (*addTektonStatusMapping) Name() string {
+	return "add tekton_status_mapping to testregistry connections"
+}
@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addProwArtifactsFallback)(nil)
+
+type addProwArtifactsFallback struct{}
+
+func (*addProwArtifactsFallback) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		name       string
+		definition string
+	}{
+		{"prow_artifacts_fallback", "BOOLEAN DEFAULT FALSE"},
+		{"prow_artifacts_url", "VARCHAR(255)"},
+	}
+	for _, column := range columns {
+		err := db.Exec("ALTER TABLE _tool_testregistry_connections ADD COLUMN " + column.name + " " + column.definition)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+column.name+" column")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (*addProwArtifactsFallback) Version() uint64 {
+	return 20250125000001
+}
+
+func (*addProwArtifactsFallback) Name() string {
+	return "add prow artifacts fallback settings to testregistry connections"
+}
@@ -0,0 +1,72 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addJobTestCounts)(nil)
+
+type addJobTestCounts struct{}
+
+func (*addJobTestCounts) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []string{"total_tests", "failed_tests", "skipped_tests", "suites_count"}
+	for _, column := range columns {
+		err := db.Exec("ALTER TABLE ci_test_jobs ADD COLUMN " + column + " BIGINT UNSIGNED NOT NULL DEFAULT 0")
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+column+" column")
+			}
+		}
+	}
+
+	// Backfill the jobs whose JUnit results were processed before the columns existed
+	err := db.Exec(`UPDATE ci_test_jobs j
+		JOIN (
+			SELECT connection_id, job_id,
+				SUM(CASE WHEN parent_suite_id IS NULL THEN num_tests ELSE 0 END) AS total_tests,
+				SUM(CASE WHEN parent_suite_id IS NULL THEN num_failed + num_errors ELSE 0 END) AS failed_tests,
+				SUM(CASE WHEN parent_suite_id IS NULL THEN num_skipped ELSE 0 END) AS skipped_tests,
+				COUNT(*) AS suites_count
+			FROM ci_test_suites
+			GROUP BY connection_id, job_id
+		) s ON s.connection_id = j.connection_id AND s.job_id = j.job_id
+		SET j.total_tests = s.total_tests, j.failed_tests = s.failed_tests,
+			j.skipped_tests = s.skipped_tests, j.suites_count = s.suites_count`)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to backfill job test counts")
+	}
+
+	return nil
+}
+
+func (*addJobTestCounts) Version() uint64 {
+	return 20250128000001
+}
+
+func (*addJobTestCounts) Name() string {
+	return "add test counts to testregistry CI jobs"
+}
@@ -0,0 +1,81 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addJobNameNormalization)(nil)
+
+type addJobNameNormalization struct{}
+
+func (*addJobNameNormalization) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		table  string
+		column string
+		ddl    string
+	}{
+		{"ci_test_jobs", "base_job_name", "VARCHAR(500)"},
+		{"ci_test_jobs", "job_variant", "VARCHAR(255)"},
+		{"_tool_testregistry_scope_configs", "job_name_rules", "JSON"},
+	}
+
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	// Index the new columns for grouping and filtering; MySQL has no CREATE INDEX IF NOT EXISTS
+	for _, column := range []string{"base_job_name", "job_variant"} {
+		err := db.Exec("CREATE INDEX idx_ci_test_jobs_" + column + " ON ci_test_jobs(" + column + ")")
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+				basicRes.GetLogger().Warn(err, "failed to create index on "+column)
+			}
+		}
+	}
+
+	// Existing jobs keep their name as base job name until the next collection applies the rules
+	err := db.Exec("UPDATE ci_test_jobs SET base_job_name = job_name, job_variant = '' WHERE base_job_name IS NULL")
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to backfill base_job_name")
+	}
+
+	return nil
+}
+
+func (*addJobNameNormalization) Version() uint64 {
+	return 20250130000001
+}
+
+func (*addJobNameNormalization) Name() string {
+	return "add base job name and job variant to ci test jobs and job name rules to scope configs"
+}
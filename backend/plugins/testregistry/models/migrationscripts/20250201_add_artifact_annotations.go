@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addArtifactAnnotations)(nil)
+
+type addArtifactAnnotations struct{}
+
+func (*addArtifactAnnotations) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	for _, column := range []string{"application", "pipeline_name"} {
+		err := db.Exec("ALTER TABLE ci_test_jobs ADD COLUMN " + column + " VARCHAR(255)")
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+column+" column")
+			}
+		}
+
+		// MySQL has no CREATE INDEX IF NOT EXISTS
+		err = db.Exec("CREATE INDEX idx_ci_test_jobs_" + column + " ON ci_test_jobs(" + column + ")")
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+				basicRes.GetLogger().Warn(err, "failed to create index on "+column)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (*addArtifactAnnotations) Version() uint64 {
+	return 20250201000001
+}
+
+func (*addArtifactAnnotations) Name() string {
+	return "add application and pipeline name from OCI artifact annotations to ci test jobs"
+}
@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+var _ plugin.MigrationScript = (*addJUnitFiles)(nil)
+
+type addJUnitFiles struct{}
+
+func (*addJUnitFiles) Up(basicRes context.BasicRes) errors.Error {
+	if err := migrationhelper.AutoMigrateTables(basicRes, &models.JUnitFile{}); err != nil {
+		return err
+	}
+
+	db := basicRes.GetDal()
+	err := db.Exec("ALTER TABLE ci_test_suites ADD COLUMN file_id VARCHAR(64)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+			return errors.Default.Wrap(err, "failed to add file_id column")
+		}
+	}
+
+	// MySQL has no CREATE INDEX IF NOT EXISTS
+	err = db.Exec("CREATE INDEX idx_ci_test_suites_file_id ON ci_test_suites(file_id)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+			basicRes.GetLogger().Warn(err, "failed to create index on file_id")
+		}
+	}
+
+	return nil
+}
+
+func (*addJUnitFiles) Version() uint64 {
+	return 20250202000001
+}
+
+func (*addJUnitFiles) Name() string {
+	return "add ci_test_junit_files table and the source file of ci test suites"
+}
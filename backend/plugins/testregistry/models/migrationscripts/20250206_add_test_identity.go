@@ -0,0 +1,83 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addTestIdentity)(nil)
+
+type addTestIdentity struct{}
+
+func (*addTestIdentity) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		table  string
+		column string
+		ddl    string
+	}{
+		{"ci_test_cases", "test_identity", "VARCHAR(500)"},
+		{"_tool_testregistry_scope_configs", "test_suite_prefixes", "JSON"},
+		{"_tool_testregistry_scope_configs", "test_suite_aliases", "JSON"},
+	}
+
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	// MySQL has no CREATE INDEX IF NOT EXISTS
+	err := db.Exec("CREATE INDEX idx_ci_test_cases_test_identity ON ci_test_cases(test_identity)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+			basicRes.GetLogger().Warn(err, "failed to create index on test_identity")
+		}
+	}
+
+	// Existing test cases get the identity of their suite and name without normalization
+	// until their job is collected again, the name alone outside of a named suite like
+	// TestIdentityNormalizer.Identity()
+	err = db.Exec(`UPDATE ci_test_cases tc JOIN ci_test_suites s ON tc.connection_id = s.connection_id AND tc.job_id = s.job_id AND tc.suite_id = s.suite_id
+		SET tc.test_identity = LEFT(CASE WHEN s.name IS NULL OR s.name = '' THEN tc.name ELSE CONCAT(s.name, '::', tc.name) END, 500)
+		WHERE tc.test_identity IS NULL`)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to backfill test_identity")
+	}
+
+	return nil
+}
+
+func (*addTestIdentity) Version() uint64 {
+	return 20250206000001
+}
+
+func (*addTestIdentity) Name() string {
+	return "add test identity to ci test cases and suite normalization to scope configs"
+}
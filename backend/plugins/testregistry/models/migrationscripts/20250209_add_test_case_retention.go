@@ -0,0 +1,77 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+var _ plugin.MigrationScript = (*addTestCaseRetention)(nil)
+
+type addTestCaseRetention struct{}
+
+func (*addTestCaseRetention) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		column string
+		ddl    string
+	}{
+		{"test_case_retention_days", "INT"},
+		{"test_case_retention_mode", "VARCHAR(20)"},
+	}
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE _tool_testregistry_scope_configs ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	err := migrationhelper.AutoMigrateTables(basicRes, &models.ArchivedTestCase{})
+	if err != nil {
+		return err
+	}
+
+	// The retention subtask looks up the old jobs of a scope by start time; MySQL has no CREATE INDEX IF NOT EXISTS
+	err = db.Exec("CREATE INDEX idx_ci_test_jobs_scope_started ON ci_test_jobs(connection_id, scope_id, started_at)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+			basicRes.GetLogger().Warn(err, "failed to create index on the scope and start time of ci_test_jobs")
+		}
+	}
+
+	return nil
+}
+
+func (*addTestCaseRetention) Version() uint64 {
+	return 20250209000001
+}
+
+func (*addTestCaseRetention) Name() string {
+	return "add test case retention to testregistry scope configs and ci_test_cases_archive table"
+}
@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addCommitShaShort)(nil)
+
+type addCommitShaShort struct{}
+
+func (*addCommitShaShort) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	err := db.Exec("ALTER TABLE ci_test_jobs ADD COLUMN commit_sha_short BOOLEAN NOT NULL DEFAULT FALSE")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+			return errors.Default.Wrap(err, "failed to add commit_sha_short column")
+		}
+	}
+
+	// Flag the abbreviated SHAs collected before; the next collection of their jobs tries to resolve them
+	err = db.Exec("UPDATE ci_test_jobs SET commit_sha = LOWER(TRIM(commit_sha)), commit_sha_short = (CHAR_LENGTH(TRIM(commit_sha)) < 40) WHERE commit_sha <> ''")
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to flag short commit SHAs")
+	}
+
+	return nil
+}
+
+func (*addCommitShaShort) Version() uint64 {
+	return 20250211000001
+}
+
+func (*addCommitShaShort) Name() string {
+	return "add commit_sha_short flag to testregistry CI jobs"
+}
@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addFailureSummaries)(nil)
+
+type addFailureSummaries struct{}
+
+func (*addFailureSummaries) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		table  string
+		column string
+		ddl    string
+	}{
+		{"ci_test_jobs", "failure_signature", "VARCHAR(255)"},
+		{"ci_test_jobs", "failure_summary", "TEXT"},
+		{"ci_test_jobs", "failure_log_tail", "TEXT"},
+		{"ci_test_jobs", "failure_log_collected_at", "DATETIME(3)"},
+		{"_tool_testregistry_scope_configs", "failure_log_lines", "INT"},
+		{"_tool_testregistry_scope_configs", "failure_signatures", "JSON"},
+	}
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	// Dashboards group failed jobs by signature; MySQL has no CREATE INDEX IF NOT EXISTS
+	err := db.Exec("CREATE INDEX idx_ci_test_jobs_failure_signature ON ci_test_jobs(failure_signature)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+			basicRes.GetLogger().Warn(err, "failed to create index on failure_signature")
+		}
+	}
+
+	return nil
+}
+
+func (*addFailureSummaries) Version() uint64 {
+	return 20250215000001
+}
+
+func (*addFailureSummaries) Name() string {
+	return "add build log failure summaries to ci test jobs"
+}
@@ -0,0 +1,76 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+var _ plugin.MigrationScript = (*addQueueSaturation)(nil)
+
+type addQueueSaturation struct{}
+
+func (*addQueueSaturation) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	columns := []struct {
+		table  string
+		column string
+		ddl    string
+	}{
+		{"ci_test_jobs", "cluster", "VARCHAR(100)"},
+		{"_tool_testregistry_scope_configs", "queue_saturation_factor", "DOUBLE"},
+		{"_tool_testregistry_scope_configs", "queue_saturation_window_hours", "INT"},
+		{"_tool_testregistry_scope_configs", "queue_saturation_min_seconds", "INT"},
+		{"_tool_testregistry_scope_configs", "queue_saturation_webhook_url", "VARCHAR(500)"},
+	}
+	for _, c := range columns {
+		err := db.Exec("ALTER TABLE " + c.table + " ADD COLUMN " + c.column + " " + c.ddl)
+		if err != nil {
+			errMsg := err.Error()
+			if !strings.Contains(errMsg, "Duplicate column name") && !strings.Contains(errMsg, "1060") {
+				return errors.Default.Wrap(err, "failed to add "+c.column+" column")
+			}
+		}
+	}
+
+	// Queue times are grouped by cluster; MySQL has no CREATE INDEX IF NOT EXISTS
+	err := db.Exec("CREATE INDEX idx_ci_test_jobs_cluster ON ci_test_jobs(cluster)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+			basicRes.GetLogger().Warn(err, "failed to create index on cluster")
+		}
+	}
+
+	return migrationhelper.AutoMigrateTables(basicRes, &models.QueueAlert{})
+}
+
+func (*addQueueSaturation) Version() uint64 {
+	return 20250216000001
+}
+
+func (*addQueueSaturation) Name() string {
+	return "add build clusters to ci test jobs and queue saturation alerts"
+}
@@ -0,0 +1,54 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.MigrationScript = (*addTestCaseClassnameIndex)(nil)
+
+type addTestCaseClassnameIndex struct{}
+
+func (*addTestCaseClassnameIndex) Up(basicRes context.BasicRes) errors.Error {
+	db := basicRes.GetDal()
+
+	// The test case search matches name and classname prefixes, name is indexed already.
+	// MySQL has no CREATE INDEX IF NOT EXISTS
+	err := db.Exec("CREATE INDEX idx_ci_test_cases_classname ON ci_test_cases(classname)")
+	if err != nil {
+		errMsg := err.Error()
+		if !strings.Contains(errMsg, "Duplicate key name") && !strings.Contains(errMsg, "1061") {
+			return errors.Default.Wrap(err, "failed to create index on classname")
+		}
+	}
+
+	return nil
+}
+
+func (*addTestCaseClassnameIndex) Version() uint64 {
+	return 20250219000001
+}
+
+func (*addTestCaseClassnameIndex) Name() string {
+	return "add index on test case classnames for the test case search"
+}
@@ -37,5 +37,42 @@ func All() []plugin.MigrationScript {
 		new(addTestCasesTable),
 		new(addTektonTasksTable),
 		new(addJUnitRegexColumn),
+		new(addTektonTaskConsoleUrl),
+		new(addPassedCasesSampling),
+		new(addJUnitVendorAttributes),
+		new(addSuiteComponentMapping),
+		new(addCiIncidentThreshold),
+		new(addKubernetesSource),
+		new(addTektonBackfillCursor),
+		new(addJobOutcomeTransitions),
+		new(addExpiredTags),
+		new(addScopeConfigJUnitRegex),
+		new(addTektonStatusMapping),
+		new(addProwArtifactsFallback),
+		new(addFailureClusters),
+		new(addPostsubmitBranch),
+		new(addJobTestCounts),
+		new(addArtifactAllowlist),
+		new(addJobNameNormalization),
+		new(addCollectionRuns),
+		new(addArtifactAnnotations),
+		new(addJUnitFiles),
+		new(addRepoRenames),
+		new(addProwCursors),
+		new(addProwHistoryMaxDepth),
+		new(addTestIdentity),
+		new(addDeploymentPatterns),
+		new(addJUnitAvailability),
+		new(addTestCaseRetention),
+		new(addQuayCredentials),
+		new(addCommitShaShort),
+		new(addGCSSettings),
+		new(addScopeFilters),
+		new(addWebhookSecret),
+		new(addFailureSummaries),
+		new(addQueueSaturation),
+		new(addJUnitReprocessRequests),
+		new(addTektonCursorSince),
+		new(addTestCaseClassnameIndex),
 	}
 }
@@ -0,0 +1,40 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// ProwCollectionCursor records the completion time of the latest Prow job collected for a
+// scope, so the next incremental run only processes the jobs that completed after it
+type ProwCollectionCursor struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL"`
+	ScopeId      string `gorm:"primaryKey;type:varchar(500)"` // Scope FullName
+
+	// Prow jobs that completed before LatestCompletionTime have been collected
+	LatestCompletionTime time.Time
+}
+
+func (ProwCollectionCursor) TableName() string {
+	return "_tool_testregistry_prow_cursors"
+}
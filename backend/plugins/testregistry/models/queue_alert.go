@@ -0,0 +1,56 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// QueueAlert is a sustained queue time increase of a job on a build cluster, a sign of runner saturation.
+// The detectQueueSaturation subtask opens it and resolves it once the queue time is back to normal.
+type QueueAlert struct {
+	common.NoPKModel
+
+	// Hex sha256 of the connection, scope, cluster, job name and opening time, the natural key
+	// is too long for an InnoDB primary key
+	Id string `gorm:"primaryKey;type:varchar(64)" json:"id"`
+
+	ConnectionId uint64    `gorm:"index:idx_testregistry_queue_alerts_scope;type:BIGINT NOT NULL" json:"connection_id"`
+	ScopeId      string    `gorm:"index:idx_testregistry_queue_alerts_scope;type:varchar(500)" json:"scope_id"`
+	Cluster      string    `gorm:"type:varchar(100)" json:"cluster"` // Empty for sources that report no cluster
+	JobName      string    `gorm:"type:varchar(255)" json:"job_name"`
+	OpenedAt     time.Time `gorm:"index" json:"opened_at"`
+
+	ResolvedAt *time.Time `gorm:"index" json:"resolved_at"` // nil while the queue time stays high
+	LastSeenAt time.Time  `json:"last_seen_at"`             // Last pipeline run that found the queue time high
+
+	// Medians of the last detection, queued_duration_sec of the runs queued in each window
+	RecentRuns        int     `json:"recent_runs"`
+	RecentMedianSec   float64 `json:"recent_median_sec"`
+	BaselineRuns      int     `json:"baseline_runs"`
+	BaselineMedianSec float64 `json:"baseline_median_sec"`
+	Ratio             float64 `json:"ratio"` // RecentMedianSec / BaselineMedianSec
+
+	NotifiedAt *time.Time `json:"notified_at"` // When the scope config webhook accepted the alert, nil if not sent
+}
+
+func (QueueAlert) TableName() string {
+	return "_tool_testregistry_queue_alerts"
+}
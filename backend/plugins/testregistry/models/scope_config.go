@@ -21,8 +21,112 @@ import (
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
+// Passed test case storage modes for very large suites
+const (
+	PassedCasesModeAll       = "all"       // store every test case (default)
+	PassedCasesModeSample    = "sample"    // store a deterministic sample of passing cases
+	PassedCasesModeAggregate = "aggregate" // store no passing cases, only the suite counters
+
+	DefaultPassedCasesSamplePercent = 10
+
+	DefaultBackfillSliceDays = 7
+
+	DefaultQueueSaturationWindowHours = 24
+	DefaultQueueSaturationMinSeconds  = 120
+)
+
+// Test case retention modes
+const (
+	TestCaseRetentionDelete  = "delete"  // delete the test cases of old jobs (default)
+	TestCaseRetentionArchive = "archive" // move them to ci_test_cases_archive first
+)
+
+// ComponentMapping maps JUnit suites to a Konflux component.
+// Component may reference capture groups of Pattern, e.g. "$1".
+type ComponentMapping struct {
+	Pattern   string `mapstructure:"pattern" json:"pattern"`
+	Component string `mapstructure:"component" json:"component"`
+}
+
+// JobNameRule splits a job name into a base job name and a variant, e.g. "-arm64" or "-ocp4.15" suffixes.
+// BaseJob and Variant may reference capture groups of Pattern; they default to "$1" and "$2".
+type JobNameRule struct {
+	Pattern string `mapstructure:"pattern" json:"pattern"`
+	BaseJob string `mapstructure:"baseJob" json:"baseJob"`
+	Variant string `mapstructure:"variant" json:"variant"`
+}
+
 type TestRegistryScopeConfig struct {
 	common.ScopeConfig `mapstructure:",squash" json:",inline" gorm:"embedded"`
+
+	// PassedCasesMode controls how passing test cases are stored: "all" (default), "sample" or "aggregate".
+	// Failed and skipped test cases are always stored.
+	PassedCasesMode string `mapstructure:"passedCasesMode" json:"passedCasesMode" gorm:"type:varchar(20)"`
+	// PassedCasesSamplePercent is the share of passing test cases kept in "sample" mode (1-99, default 10)
+	PassedCasesSamplePercent int `mapstructure:"passedCasesSamplePercent" json:"passedCasesSamplePercent"`
+	// ComponentMappings assigns suites to Konflux components; the first pattern matching the suite name wins
+	ComponentMappings []ComponentMapping `mapstructure:"componentMappings" json:"componentMappings" gorm:"type:json;serializer:json"`
+	// IncidentFailureThreshold opens an incident once a job fails this many times in a row (0 disables incidents)
+	IncidentFailureThreshold int `mapstructure:"incidentFailureThreshold" json:"incidentFailureThreshold"`
+	// BackfillSliceDays is the size of the time slices Tekton tags are processed in, oldest first (default 7)
+	BackfillSliceDays int `mapstructure:"backfillSliceDays" json:"backfillSliceDays"`
+	// BackfillMaxSlices caps the slices processed per run so a long backfill spans several runs (0 = no cap)
+	BackfillMaxSlices int `mapstructure:"backfillMaxSlices" json:"backfillMaxSlices"`
+	// ProwHistoryMaxDepth walks the GCS job history of each Prow job of the scope, newest build first, up to this
+	// many builds per job to backfill runs older than the prowjobs.js snapshot (0 only collects the snapshot)
+	ProwHistoryMaxDepth int `mapstructure:"prowHistoryMaxDepth" json:"prowHistoryMaxDepth"`
+	// JUnitRegex overrides the connection's JUnit file name pattern for the scopes using this config (empty keeps the connection's)
+	JUnitRegex string `mapstructure:"junitRegex" json:"junitRegex" gorm:"column:junit_regex;type:varchar(500)"`
+	// DefaultBranch is the {branch} of the connection's gcsPrefixTemplate for postsubmit jobs that report no branch.
+	// Openshift CI keeps postsubmits under logs/<job>/<id>, so it only matters for buckets laid out by branch
+	DefaultBranch string `mapstructure:"defaultBranch" json:"defaultBranch" gorm:"type:varchar(255)"`
+	// BranchOverrides renames the {branch} of postsubmit JUnit paths, e.g. {"main": "trunk"} for artifacts stored under another branch name
+	BranchOverrides map[string]string `mapstructure:"branchOverrides" json:"branchOverrides" gorm:"type:json;serializer:json"`
+	// JobNameRules set ci_test_jobs.base_job_name and job_variant; the first pattern matching the job name wins and
+	// unmatched jobs keep their name as base job name with an empty variant
+	JobNameRules []JobNameRule `mapstructure:"jobNameRules" json:"jobNameRules" gorm:"type:json;serializer:json"`
+	// TestSuitePrefixes strip the first matching prefix pattern from suite names in ci_test_cases.test_identity,
+	// e.g. "^e2e-(aws|gcp) / " so that a test run by several scenarios keeps one identity
+	TestSuitePrefixes []string `mapstructure:"testSuitePrefixes" json:"testSuitePrefixes" gorm:"type:json;serializer:json"`
+	// TestSuiteAliases map suite names, after the prefixes are stripped, to a canonical suite name in test identities
+	TestSuiteAliases map[string]string `mapstructure:"testSuiteAliases" json:"testSuiteAliases" gorm:"type:json;serializer:json"`
+	// DeploymentPattern marks the CI jobs whose name matches as deployments in cicd_pipelines and cicd_tasks
+	DeploymentPattern string `mapstructure:"deploymentPattern" json:"deploymentPattern" gorm:"type:varchar(255)"`
+	// ProductionPattern marks the matching CI jobs as running in production (empty puts every deployment in production)
+	ProductionPattern string `mapstructure:"productionPattern" json:"productionPattern" gorm:"type:varchar(255)"`
+	// ArtifactAllowlist lists the files visited in pulled Tekton artifacts as globs relative to the artifact root,
+	// e.g. ["/pipeline-status.json", "e2e-tests/**/*.xml"]; directories no glob can reach are skipped (empty visits every file)
+	ArtifactAllowlist []string `mapstructure:"artifactAllowlist" json:"artifactAllowlist" gorm:"type:json;serializer:json"`
+	// TestCaseRetentionDays prunes the test cases of jobs started more than this many days ago; the jobs and suites
+	// are kept with their counters (0 keeps every test case)
+	TestCaseRetentionDays int `mapstructure:"testCaseRetentionDays" json:"testCaseRetentionDays"`
+	// TestCaseRetentionMode is "delete" (default) or "archive", which moves the pruned test cases to ci_test_cases_archive
+	TestCaseRetentionMode string `mapstructure:"testCaseRetentionMode" json:"testCaseRetentionMode" gorm:"type:varchar(20)"`
+	// IncludedScenarios only collects the CI jobs whose name (Prow job name or Tekton scenario) matches one of these
+	// patterns (empty collects every job)
+	IncludedScenarios []string `mapstructure:"includedScenarios" json:"includedScenarios" gorm:"type:json;serializer:json"`
+	// ExcludedScenarios skips the CI jobs whose name matches one of these patterns, also when an included pattern matches
+	ExcludedScenarios []string `mapstructure:"excludedScenarios" json:"excludedScenarios" gorm:"type:json;serializer:json"`
+	// TriggerTypes only collects the CI jobs with these trigger types: "pull_request", "push" or "periodic" (empty collects every type)
+	TriggerTypes []string `mapstructure:"triggerTypes" json:"triggerTypes" gorm:"type:json;serializer:json"`
+	// DefaultLookbackDays starts the collection window this many days ago when the blueprint sets no timeAfter
+	// (0 keeps the built-in windows: 6 months for Tekton, the prowjobs.js snapshot for Prow)
+	DefaultLookbackDays int `mapstructure:"defaultLookbackDays" json:"defaultLookbackDays"`
+	// FailureLogLines makes summarizeProwFailures read the build-log.txt of the failed Prow jobs of the scope and keep
+	// this many lines from its end, at most 500 (0 = off)
+	FailureLogLines int `mapstructure:"failureLogLines" json:"failureLogLines"`
+	// FailureSignatures are known failure patterns, e.g. "(?i)timed out waiting for" or "no space left on device";
+	// summarizeProwFailures keeps the build log lines matching them and the first pattern found
+	FailureSignatures []string `mapstructure:"failureSignatures" json:"failureSignatures" gorm:"type:json;serializer:json"`
+	// QueueSaturationFactor makes detectQueueSaturation open an alert for a cluster and job whose median queue time over
+	// the last queueSaturationWindowHours reaches this multiple of its median over the 7 days before (0 = off)
+	QueueSaturationFactor float64 `mapstructure:"queueSaturationFactor" json:"queueSaturationFactor"`
+	// QueueSaturationWindowHours is the recent window compared with the 7 days baseline (default 24)
+	QueueSaturationWindowHours int `mapstructure:"queueSaturationWindowHours" json:"queueSaturationWindowHours"`
+	// QueueSaturationMinSeconds is the recent median queue time below which no alert is opened (default 120)
+	QueueSaturationMinSeconds int `mapstructure:"queueSaturationMinSeconds" json:"queueSaturationMinSeconds"`
+	// QueueSaturationWebhookUrl, when set, receives the new queue saturation alerts as a JSON POST
+	QueueSaturationWebhookUrl string `mapstructure:"queueSaturationWebhookUrl" json:"queueSaturationWebhookUrl" gorm:"type:varchar(500)"`
 }
 
 func (TestRegistryScopeConfig) TableName() string {
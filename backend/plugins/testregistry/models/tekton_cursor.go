@@ -0,0 +1,43 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// TektonBackfillCursor records how far the Tekton collection of a scope got through its
+// Quay.io tags, so an interrupted or throttled backfill resumes where it left off
+type TektonBackfillCursor struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey;type:BIGINT NOT NULL"`
+	ScopeId      string `gorm:"primaryKey;type:varchar(500)"` // Scope FullName
+
+	// Tags created before Cursor have been processed
+	Cursor time.Time
+	// Since is the start of the collection window the cursor was reached from; a window
+	// starting earlier (timeAfter moved back) discards the cursor. Nil for older cursors.
+	Since *time.Time
+}
+
+func (TektonBackfillCursor) TableName() string {
+	return "_tool_testregistry_tekton_cursors"
+}
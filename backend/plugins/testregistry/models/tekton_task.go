@@ -36,6 +36,9 @@ type TektonTask struct {
 
 	// Duration in seconds (parsed from duration string like "499s")
 	DurationSec float64 `json:"duration_sec"` // Duration in seconds as a number
+
+	// Deep link to the task run logs in the Konflux console, only set for failed tasks
+	ConsoleUrl string `gorm:"type:varchar(1000)" json:"console_url"`
 }
 
 func (TektonTask) TableName() string {
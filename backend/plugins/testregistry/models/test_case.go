@@ -18,6 +18,8 @@ limitations under the License.
 package models
 
 import (
+	"time"
+
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
@@ -32,14 +34,22 @@ type TestCase struct {
 	TestCaseId   string `gorm:"primaryKey;type:varchar(255)" json:"test_case_id"`   // Unique identifier for the test case
 
 	// Test case identification
-	Name      string  `gorm:"type:varchar(500);index" json:"name"` // Name of the test case
-	Classname string  `gorm:"type:varchar(500)" json:"classname"`  // Class name (if applicable)
-	Duration  float64 `json:"duration"`                            // Duration in seconds
+	Name      string  `gorm:"type:varchar(500);index" json:"name"`      // Name of the test case
+	Classname string  `gorm:"type:varchar(500);index" json:"classname"` // Class name (if applicable)
+	Duration  float64 `json:"duration"`                                 // Duration in seconds
+
+	// TestIdentity is the canonical "<suite>::<name>" of the test case, with the suite name normalized by the
+	// scope config testSuitePrefixes and testSuiteAliases so that runs of one test under several suite names group together
+	TestIdentity string `gorm:"type:varchar(500);index" json:"test_identity"`
 
-	// Test result status: "passed", "failed", "skipped"
+	// Vendor extensions reported by some frameworks (pytest, PHPUnit)
+	File       string `gorm:"type:varchar(500)" json:"file"` // Source file of the test case
+	Assertions uint   `json:"assertions"`                    // Number of assertions
+
+	// Test result status: "passed", "failed", "errored", "skipped"
 	Status string `gorm:"type:varchar(50);index" json:"status"` // Test case status
 
-	// Failure information (if status is "failed")
+	// Failure information (if status is "failed", or the error of an "errored" test case)
 	FailureMessage *string `gorm:"type:text" json:"failure_message"` // Failure message from the test
 	FailureOutput  *string `gorm:"type:text" json:"failure_output"`  // Detailed failure output
 
@@ -54,3 +64,14 @@ type TestCase struct {
 func (TestCase) TableName() string {
 	return "ci_test_cases"
 }
+
+// ArchivedTestCase is a test case moved out of ci_test_cases by the scope config testCaseRetentionMode "archive"
+type ArchivedTestCase struct {
+	TestCase
+
+	ArchivedAt time.Time `gorm:"index" json:"archived_at"` // When the retention subtask archived the test case
+}
+
+func (ArchivedTestCase) TableName() string {
+	return "ci_test_cases_archive"
+}
@@ -18,6 +18,8 @@ limitations under the License.
 package models
 
 import (
+	"time"
+
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
@@ -38,11 +40,25 @@ type TestSuite struct {
 	NumTests   uint    `json:"num_tests"`   // Total number of tests in the suite
 	NumSkipped uint    `json:"num_skipped"` // Number of skipped tests
 	NumFailed  uint    `json:"num_failed"`  // Number of failed tests
+	NumErrors  uint    `json:"num_errors"`  // Number of tests that errored (JUnit errors attribute)
 	Duration   float64 `json:"duration"`    // Duration in seconds
 
+	// Vendor extensions reported by some frameworks (pytest, Maven Surefire, PHPUnit)
+	NumAssertions uint       `json:"num_assertions"` // Number of assertions (JUnit assertions attribute)
+	Timestamp     *time.Time `json:"timestamp"`      // When the suite started (JUnit timestamp attribute)
+
+	// Number of passing test cases not stored because of the scope config passedCasesMode
+	NumPassedOmitted uint `json:"num_passed_omitted"`
+
+	// Konflux component resolved from the scope config componentMappings (empty if none matched)
+	Component string `gorm:"type:varchar(255)" json:"component"`
+
 	// Properties stored as JSON (optional test suite properties)
 	Properties string `gorm:"type:text" json:"properties"` // JSON string of suite properties
 
+	// JUnit file the suite was read from, links to JUnitFile.FileId (empty for pushed results)
+	FileId string `gorm:"type:varchar(64);index" json:"file_id"`
+
 	// Parent suite reference (for nested suites)
 	ParentSuiteId *string `gorm:"type:varchar(255);index" json:"parent_suite_id"` // NULL for top-level suites
 }
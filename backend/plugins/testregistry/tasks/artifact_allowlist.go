@@ -0,0 +1,180 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// ArtifactAllowlist limits the files visited when walking a pulled artifact
+//
+// Patterns are slash-separated globs relative to the artifact root, where "**"
+// matches any number of directories, e.g. "/pipeline-status.json" or
+// "e2e-tests/**/junit-*.xml". As in .gitignore, a pattern without a '/' matches
+// the file name at any depth, and a leading '/' anchors it to the artifact root.
+// Directories no pattern can reach are skipped without being read.
+type ArtifactAllowlist struct {
+	patterns [][]string
+}
+
+// NewArtifactAllowlist compiles the artifact allowlist of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *ArtifactAllowlist: The allowlist, or nil if none is configured (every file is visited)
+//   - errors.Error: BadInput if a pattern is empty or not a valid glob
+func NewArtifactAllowlist(scopeConfig *models.TestRegistryScopeConfig) (*ArtifactAllowlist, errors.Error) {
+	if scopeConfig == nil || len(scopeConfig.ArtifactAllowlist) == 0 {
+		return nil, nil
+	}
+
+	allowlist := &ArtifactAllowlist{}
+	for i, pattern := range scopeConfig.ArtifactAllowlist {
+		pattern = strings.TrimSpace(pattern)
+		anchored := strings.Contains(strings.TrimRight(pattern, "/"), "/")
+		pattern = strings.Trim(pattern, "/")
+		if pattern == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("artifactAllowlist[%d]: pattern is required", i))
+		}
+		segments := strings.Split(pattern, "/")
+		for _, segment := range segments {
+			if _, err := path.Match(segment, ""); err != nil {
+				return nil, errors.BadInput.Wrap(err, fmt.Sprintf("artifactAllowlist[%d]: invalid pattern %q", i, pattern))
+			}
+		}
+		if !anchored {
+			segments = append([]string{"**"}, segments...)
+		}
+		allowlist.patterns = append(allowlist.patterns, segments)
+	}
+	return allowlist, nil
+}
+
+// visitDir reports whether a directory may contain allowed files
+//
+// Parameters:
+//   - relPath: Path of the directory relative to the artifact root
+//
+// Returns:
+//   - bool: false if the walk can skip the directory
+func (a *ArtifactAllowlist) visitDir(relPath string) bool {
+	if a == nil || relPath == "." {
+		return true
+	}
+	dir := strings.Split(filepath.ToSlash(relPath), "/")
+	for _, pattern := range a.patterns {
+		if globPrefixMatch(pattern, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// visitFile reports whether a file matches the allowlist
+//
+// Parameters:
+//   - relPath: Path of the file relative to the artifact root
+//
+// Returns:
+//   - bool: true if the walk should look at the file
+func (a *ArtifactAllowlist) visitFile(relPath string) bool {
+	if a == nil {
+		return true
+	}
+	file := strings.Split(filepath.ToSlash(relPath), "/")
+	for _, pattern := range a.patterns {
+		if globMatch(pattern, file) {
+			return true
+		}
+	}
+	return false
+}
+
+// skip returns the walk decision for an entry of the artifact tree
+//
+// Parameters:
+//   - root: The artifact root the walk started from
+//   - entryPath: Path of the entry being walked
+//   - isDir: Whether the entry is a directory
+//
+// Returns:
+//   - bool: true if the entry is not allowed
+//   - error: filepath.SkipDir for directories the walk can skip, nil otherwise
+func (a *ArtifactAllowlist) skip(root, entryPath string, isDir bool) (bool, error) {
+	if a == nil {
+		return false, nil
+	}
+	relPath, err := filepath.Rel(root, entryPath)
+	if err != nil {
+		return false, nil
+	}
+	if isDir {
+		if a.visitDir(relPath) {
+			return false, nil
+		}
+		return true, filepath.SkipDir
+	}
+	return !a.visitFile(relPath), nil
+}
+
+// globMatch matches path segments against pattern segments, "**" matching any number of segments
+func globMatch(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if globMatch(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(segments) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+		return false
+	}
+	return globMatch(pattern[1:], segments[1:])
+}
+
+// globPrefixMatch reports whether a path below the directory segments can match the pattern
+func globPrefixMatch(pattern, dir []string) bool {
+	if len(dir) == 0 {
+		return len(pattern) > 0
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return true
+	}
+	if ok, _ := path.Match(pattern[0], dir[0]); !ok {
+		return false
+	}
+	return globPrefixMatch(pattern[1:], dir[1:])
+}
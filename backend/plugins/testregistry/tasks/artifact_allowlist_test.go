@@ -0,0 +1,124 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewArtifactAllowlist(t *testing.T) {
+	allowlist, err := NewArtifactAllowlist(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, allowlist)
+
+	allowlist, err = NewArtifactAllowlist(&models.TestRegistryScopeConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, allowlist)
+
+	_, err = NewArtifactAllowlist(&models.TestRegistryScopeConfig{ArtifactAllowlist: []string{"[a-"}})
+	assert.NotNil(t, err)
+
+	_, err = NewArtifactAllowlist(&models.TestRegistryScopeConfig{ArtifactAllowlist: []string{" / "}})
+	assert.NotNil(t, err)
+}
+
+func TestArtifactAllowlistVisit(t *testing.T) {
+	allowlist, err := NewArtifactAllowlist(&models.TestRegistryScopeConfig{
+		ArtifactAllowlist: []string{"pipeline-status.json", "e2e-tests/**/junit-*.xml", "/reports/*.xml/"},
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		path  string
+		isDir bool
+		visit bool
+	}{
+		{".", true, true},
+		{"run-1/pipeline-status.json", false, true},
+		{"pipeline-status.json", false, true},
+		{"e2e-tests", true, true},
+		{"e2e-tests/suite/deep", true, true},
+		{"e2e-tests/junit-a.xml", false, true},
+		{"e2e-tests/suite/deep/junit-b.xml", false, true},
+		{"e2e-tests/suite/report.xml", false, false},
+		{"reports", true, true},
+		{"reports/e2e.xml", false, true},
+		{"reports/nested", true, true},
+		{"reports/nested/e2e.xml", false, false},
+		{"must-gather/namespaces/pods.yaml", false, false},
+	}
+	for _, tt := range tests {
+		if tt.isDir {
+			assert.Equal(t, tt.visit, allowlist.visitDir(tt.path), tt.path)
+		} else {
+			assert.Equal(t, tt.visit, allowlist.visitFile(tt.path), tt.path)
+		}
+	}
+
+	// Only anchored patterns let unrelated directories be pruned
+	assert.True(t, allowlist.visitDir("must-gather"), "a pattern without '/' matches at any depth")
+	anchored, err := NewArtifactAllowlist(&models.TestRegistryScopeConfig{
+		ArtifactAllowlist: []string{"/pipeline-status.json", "e2e-tests/**"},
+	})
+	assert.Nil(t, err)
+	assert.False(t, anchored.visitDir("must-gather"))
+	assert.True(t, anchored.visitDir("e2e-tests/any/depth"))
+	assert.True(t, anchored.visitFile("pipeline-status.json"))
+	assert.False(t, anchored.visitFile("run-1/pipeline-status.json"))
+
+	var nilAllowlist *ArtifactAllowlist
+	assert.True(t, nilAllowlist.visitDir("anything"))
+	assert.True(t, nilAllowlist.visitFile("anything/else.txt"))
+}
+
+func TestArtifactAllowlistWalk(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		path := filepath.Join(dir, rel)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	}
+	write("pipeline-status.json", `{"pipelineRunName": "root-run", "status": "Succeeded"}`)
+	write("must-gather/pipeline-status.json", `{"pipelineRunName": "ignored-run", "status": "Failed"}`)
+	write("e2e-tests/e2e-report.xml", `<testsuites></testsuites>`)
+
+	allowlist, err := NewArtifactAllowlist(&models.TestRegistryScopeConfig{
+		ArtifactAllowlist: []string{"/pipeline-status.json", "e2e-tests/*.xml"},
+	})
+	assert.Nil(t, err)
+
+	runs, err := extractTektonPipelineRuns(context.Background(), nil, dir, "/tmp/logs", allowlist, newMockLogger())
+	assert.Nil(t, err)
+	if assert.Len(t, runs, 1) {
+		assert.Equal(t, "root-run", runs[0].PipelineRunName)
+	}
+
+	// Files outside the allowlist are never matched against the JUnit regex
+	allowlist, err = NewArtifactAllowlist(&models.TestRegistryScopeConfig{ArtifactAllowlist: []string{"/pipeline-status.json"}})
+	assert.Nil(t, err)
+	mockCtx, _, _ := setupMockContext(t)
+	ciJob := &models.TestRegistryCIJob{JobId: "job-1"}
+	assert.False(t, findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", regexp.MustCompile(`e2e-report\.xml`), allowlist))
+}
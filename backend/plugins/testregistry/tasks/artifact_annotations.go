@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// artifactAnnotationKeys are the OCI manifest annotations copied onto the CI jobs of a
+// Tekton artifact, by CI job field. The first key present on the manifest wins.
+var artifactAnnotationKeys = struct {
+	commit       []string
+	pipelineName []string
+	application  []string
+}{
+	commit:       []string{"org.opencontainers.image.revision", "vcs-ref"},
+	pipelineName: []string{"tekton.dev/pipeline", "pipelines.appstudio.openshift.io/pipeline"},
+	application:  []string{"appstudio.openshift.io/application"},
+}
+
+// firstAnnotation returns the value of the first of keys set on the manifest
+func firstAnnotation(annotations map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value := strings.TrimSpace(annotations[key]); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// applyArtifactAnnotations fills the CI job fields read from the artifact's manifest
+// annotations. The commit only fills a job whose PipelineRun carries no Git info, and
+// is dropped when it isn't a commit SHA; abbreviated ones are expanded by CommitShaResolver.
+//
+// Parameters:
+//   - ciJob: The CI job converted from one of the artifact's PipelineRuns
+//   - annotations: The manifest annotations, nil when they couldn't be read
+func applyArtifactAnnotations(ciJob *models.TestRegistryCIJob, annotations map[string]string) {
+	if len(annotations) == 0 {
+		return
+	}
+	ciJob.Application = firstAnnotation(annotations, artifactAnnotationKeys.application)
+	ciJob.PipelineName = firstAnnotation(annotations, artifactAnnotationKeys.pipelineName)
+	if ciJob.CommitSHA == "" {
+		if commit := NormalizeCommitSha(firstAnnotation(annotations, artifactAnnotationKeys.commit)); commit != "" {
+			ciJob.CommitSHA = commit
+		}
+	}
+}
@@ -0,0 +1,71 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyArtifactAnnotations(t *testing.T) {
+	const sha = "d4e5f6a7b8c90a1b2c3d4e5f6a7b8c9d0e1f2a3b"
+
+	t.Run("fills application, pipeline and missing commit", func(t *testing.T) {
+		ciJob := &models.TestRegistryCIJob{}
+		applyArtifactAnnotations(ciJob, map[string]string{
+			"appstudio.openshift.io/application": "release-service",
+			"tekton.dev/pipeline":                "integration-e2e",
+			"org.opencontainers.image.revision":  sha,
+		})
+		assert.Equal(t, "release-service", ciJob.Application)
+		assert.Equal(t, "integration-e2e", ciJob.PipelineName)
+		assert.Equal(t, sha, ciJob.CommitSHA)
+	})
+
+	t.Run("keeps the commit of the PipelineRun", func(t *testing.T) {
+		ciJob := &models.TestRegistryCIJob{CommitSHA: "f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5"}
+		applyArtifactAnnotations(ciJob, map[string]string{"org.opencontainers.image.revision": sha})
+		assert.Equal(t, "f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2e3f4a5", ciJob.CommitSHA)
+	})
+
+	t.Run("falls back to later keys and keeps short commits", func(t *testing.T) {
+		ciJob := &models.TestRegistryCIJob{}
+		applyArtifactAnnotations(ciJob, map[string]string{
+			"tekton.dev/pipeline":                       " ",
+			"pipelines.appstudio.openshift.io/pipeline": "e2e-tests",
+			"vcs-ref": "D4E5F6A",
+		})
+		assert.Equal(t, "e2e-tests", ciJob.PipelineName)
+		assert.Equal(t, "d4e5f6a", ciJob.CommitSHA)
+		assert.Empty(t, ciJob.Application)
+	})
+
+	t.Run("ignores revisions that are not commits", func(t *testing.T) {
+		ciJob := &models.TestRegistryCIJob{}
+		applyArtifactAnnotations(ciJob, map[string]string{"vcs-ref": "v1.2.3"})
+		assert.Empty(t, ciJob.CommitSHA)
+	})
+
+	t.Run("no annotations leaves the job unchanged", func(t *testing.T) {
+		ciJob := &models.TestRegistryCIJob{JobName: "integration-e2e"}
+		applyArtifactAnnotations(ciJob, nil)
+		assert.Equal(t, &models.TestRegistryCIJob{JobName: "integration-e2e"}, ciJob)
+	})
+}
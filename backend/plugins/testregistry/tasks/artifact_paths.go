@@ -0,0 +1,170 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+)
+
+// unsafeFileNameChars are characters that are illegal in file names on Windows
+// (and ':' on macOS), plus ASCII control characters
+var unsafeFileNameChars = regexp.MustCompile(`[<>:"|?*\\\x00-\x1f]`)
+
+// reservedWindowsNames are device names Windows refuses as file names, with or without extension
+var reservedWindowsNames = regexp.MustCompile(`(?i)^(con|prn|aux|nul|com[0-9]|lpt[0-9])(\..*)?$`)
+
+// normalizeArtifactFileName makes a single path element safe on Linux, macOS and Windows
+//
+// Parameters:
+//   - name: A file or directory name (not a path)
+//
+// Returns:
+//   - string: The name with illegal characters replaced by '_', trailing dots and spaces
+//     removed and reserved device names prefixed with '_'
+func normalizeArtifactFileName(name string) string {
+	normalized := unsafeFileNameChars.ReplaceAllString(name, "_")
+	normalized = strings.TrimRight(normalized, ". ")
+	if normalized == "" {
+		return "_"
+	}
+	if reservedWindowsNames.MatchString(normalized) {
+		normalized = "_" + normalized
+	}
+	return normalized
+}
+
+// safeArtifactPath joins a path taken from artifact content onto the artifact directory
+//
+// Parameters:
+//   - root: The artifact directory
+//   - relPath: A path relative to root
+//
+// Returns:
+//   - string: The joined path
+//   - errors.Error: BadInput if relPath is absolute or escapes root (e.g. "../../etc/passwd")
+func safeArtifactPath(root, relPath string) (string, errors.Error) {
+	if filepath.IsAbs(relPath) || filepath.VolumeName(relPath) != "" {
+		return "", errors.BadInput.New(fmt.Sprintf("artifact path %q must be relative", relPath))
+	}
+	joined := filepath.Join(root, relPath)
+	if !isWithinDir(root, joined) {
+		return "", errors.BadInput.New(fmt.Sprintf("artifact path %q escapes the artifact directory", relPath))
+	}
+	return joined, nil
+}
+
+// isWithinDir reports whether path is dir or lies below it, without following symlinks
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// sanitizeArtifactDir makes a pulled artifact safe to walk and read.
+//
+// OCI layers are produced by the CI pipelines under test, so their content is untrusted:
+//   - symlinks pointing outside the artifact directory are removed, so reading a
+//     "junit.xml" can't read an arbitrary file of the DevLake host
+//   - devices, sockets and named pipes are removed (reading a pipe blocks forever)
+//   - names with characters that are illegal on Windows/macOS are renamed with
+//     normalizeArtifactFileName; a numeric suffix is added if the new name is taken
+//
+// Parameters:
+//   - root: The artifact directory returned by PullArtifact
+//   - logger: Logger for reporting removed and renamed entries
+//
+// Returns:
+//   - errors.Error: Any error encountered while walking or fixing the directory
+func sanitizeArtifactDir(root string, logger log.Logger) errors.Error {
+	var entries []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if path == root {
+			return nil
+		}
+
+		switch {
+		case d.Type()&fs.ModeSymlink != 0:
+			target, readErr := os.Readlink(path)
+			if readErr != nil {
+				return readErr
+			}
+			if !filepath.IsAbs(target) {
+				target = filepath.Join(filepath.Dir(path), target)
+			}
+			if !isWithinDir(root, target) {
+				logger.Warn(nil, "removing artifact symlink pointing outside the artifact", "path", path, "target", target)
+				return os.Remove(path)
+			}
+		case !d.IsDir() && !d.Type().IsRegular():
+			logger.Warn(nil, "removing special file from artifact", "path", path, "mode", d.Type().String())
+			return os.Remove(path)
+		}
+		entries = append(entries, path)
+		return nil
+	})
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to sanitize artifact directory")
+	}
+
+	// Rename deepest entries first so parent renames don't invalidate collected paths
+	sort.Slice(entries, func(i, j int) bool {
+		return strings.Count(entries[i], string(filepath.Separator)) > strings.Count(entries[j], string(filepath.Separator))
+	})
+	for _, path := range entries {
+		name := filepath.Base(path)
+		normalized := normalizeArtifactFileName(name)
+		if normalized == name {
+			continue
+		}
+		target := availablePath(filepath.Join(filepath.Dir(path), normalized))
+		if renameErr := os.Rename(path, target); renameErr != nil {
+			return errors.Default.Wrap(renameErr, fmt.Sprintf("failed to normalize artifact file name %q", path))
+		}
+		logger.Debug("normalized artifact file name", "from", path, "to", target)
+	}
+	return nil
+}
+
+// availablePath returns path, or path with a "-N" suffix before the extension if it already exists
+func availablePath(path string) string {
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for i := 1; ; i++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, i, ext)
+		if _, err := os.Lstat(candidate); os.IsNotExist(err) {
+			return candidate
+		}
+	}
+}
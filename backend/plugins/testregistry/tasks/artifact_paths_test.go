@@ -0,0 +1,110 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNormalizeArtifactFileName(t *testing.T) {
+	cases := map[string]string{
+		"junit.xml":            "junit.xml",
+		"e2e:report?.xml":      "e2e_report_.xml",
+		`a<b>c|d*e"f\g.xml`:    "a_b_c_d_e_f_g.xml",
+		"tab\tname.xml":        "tab_name.xml",
+		"trailing. ":           "trailing",
+		"...":                  "_",
+		"CON":                  "_CON",
+		"nul.txt":              "_nul.txt",
+		"console.log":          "console.log",
+		"pipeline-status.json": "pipeline-status.json",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, normalizeArtifactFileName(input), input)
+	}
+}
+
+func TestSafeArtifactPath(t *testing.T) {
+	root := t.TempDir()
+
+	path, err := safeArtifactPath(root, "artifacts/junit.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "artifacts", "junit.xml"), path)
+
+	path, err = safeArtifactPath(root, "a/../b.xml")
+	assert.Nil(t, err)
+	assert.Equal(t, filepath.Join(root, "b.xml"), path)
+
+	_, err = safeArtifactPath(root, "../../etc/passwd")
+	assert.NotNil(t, err)
+
+	_, err = safeArtifactPath(root, "/etc/passwd")
+	assert.NotNil(t, err)
+
+	_, err = safeArtifactPath(root, "..")
+	assert.NotNil(t, err)
+}
+
+func TestSanitizeArtifactDir(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(outside, "secret"), []byte("secret"), 0600))
+
+	assert.NoError(t, os.MkdirAll(filepath.Join(root, "logs:run?1"), 0755))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "logs:run?1", "junit:e2e.xml"), []byte("<testsuites/>"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "junit_e2e.xml"), []byte("<testsuites/>"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "junit:e2e.xml"), []byte("<testsuites/>"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "inside.xml"), []byte("<testsuites/>"), 0644))
+	symlinks := true
+	if err := os.Symlink(filepath.Join(outside, "secret"), filepath.Join(root, "junit-escape.xml")); err != nil {
+		symlinks = false
+	}
+	if symlinks {
+		assert.NoError(t, os.Symlink("inside.xml", filepath.Join(root, "junit-inside.xml")))
+		assert.NoError(t, os.Symlink("../../"+filepath.Base(outside), filepath.Join(root, "logs:run?1", "up")))
+	}
+
+	logger := new(mocklog.Logger)
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	logger.On("Debug", mock.Anything, mock.Anything).Maybe()
+
+	assert.Nil(t, sanitizeArtifactDir(root, logger))
+
+	assert.FileExists(t, filepath.Join(root, "logs_run_1", "junit_e2e.xml"))
+	assert.NoDirExists(t, filepath.Join(root, "logs:run?1"))
+	assert.FileExists(t, filepath.Join(root, "junit_e2e.xml"))
+	assert.FileExists(t, filepath.Join(root, "junit_e2e-1.xml"), "colliding names get a numeric suffix")
+	assert.NoFileExists(t, filepath.Join(root, "junit:e2e.xml"))
+
+	if symlinks {
+		_, err := os.Lstat(filepath.Join(root, "junit-escape.xml"))
+		assert.True(t, os.IsNotExist(err), "symlink escaping the artifact must be removed")
+		_, err = os.Lstat(filepath.Join(root, "logs_run_1", "up"))
+		assert.True(t, os.IsNotExist(err), "relative symlink escaping the artifact must be removed")
+		_, err = os.Lstat(filepath.Join(root, "junit-inside.xml"))
+		assert.NoError(t, err, "symlink within the artifact is kept")
+	}
+	_, err := os.Stat(filepath.Join(outside, "secret"))
+	assert.NoError(t, err, "files outside the artifact are never touched")
+}
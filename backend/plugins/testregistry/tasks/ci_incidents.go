@@ -0,0 +1,209 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// GenerateCiIncidentsMeta defines the metadata for the CI incident subtask
+var GenerateCiIncidentsMeta = plugin.SubTaskMeta{
+	Name:             "generateCiIncidents",
+	EntryPoint:       GenerateCiIncidents,
+	EnabledByDefault: true,
+	Description:      "Open a domain incident when a periodic or postsubmit job fails incidentFailureThreshold times in a row, resolved by the next successful run",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD, plugin.DOMAIN_TYPE_TICKET},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta, &CollectTektonJobsMeta},
+}
+
+// ciIncidentJob is the subset of ci_test_jobs needed to detect failure streaks
+type ciIncidentJob struct {
+	JobId      string
+	JobName    string
+	Result     string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	ViewURL    string `gorm:"column:view_url"`
+}
+
+// ciIncident is a run of consecutive failures of one job
+type ciIncident struct {
+	JobName      string
+	FirstFailure ciIncidentJob
+	Failures     int
+	ResolvedBy   *ciIncidentJob // nil while the job is still failing
+}
+
+// GenerateCiIncidents models persistent CI breakage as DevLake incidents.
+//
+// A job that fails IncidentFailureThreshold times in a row opens an incident at the first
+// failure of the streak; the next successful run of the same job resolves it. Presubmit
+// (pull_request) runs are ignored because their failures belong to a single PR, and
+// aborted runs neither extend nor break a streak.
+//
+// Incidents are written to the domain incidents table with table/scope_id pointing at the
+// testregistry scope, so the DORA plugin links them to the project like any other incident.
+// Incidents of the scope are regenerated from scratch on every run.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered while generating incidents, or nil if successful
+func GenerateCiIncidents(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	connectionId := data.Options.ConnectionId
+	fullName := data.Options.FullName
+	scopeTable := models.TestRegistryScope{}.TableName()
+
+	// Drop incidents from previous runs, also when the feature was turned off since
+	err := db.Delete(&ticket.Incident{}, dal.Where(
+		"incidents.table = ? AND incidents.scope_id = ? AND incidents.id LIKE ?", scopeTable, fullName, ciIncidentIdPrefix(connectionId)+"%",
+	))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete previous CI incidents")
+	}
+
+	threshold := 0
+	if data.Options.ScopeConfig != nil {
+		threshold = data.Options.ScopeConfig.IncidentFailureThreshold
+	}
+	if threshold <= 0 {
+		logger.Debug("incidentFailureThreshold is not set, skipping CI incidents for scope %s", fullName)
+		return nil
+	}
+
+	var jobs []ciIncidentJob
+	err = db.All(&jobs,
+		dal.Select("job_id, job_name, result, started_at, finished_at, view_url"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND trigger_type != ?", connectionId, fullName, "pull_request"),
+		dal.Orderby("job_name, started_at, finished_at"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load CI jobs for incident detection")
+	}
+
+	incidents := detectCiIncidents(jobs, threshold)
+	for _, incident := range incidents {
+		if err := db.CreateOrUpdate(incident.toDomain(connectionId, fullName, scopeTable)); err != nil {
+			return errors.Default.Wrap(err, "failed to save CI incident")
+		}
+	}
+
+	logger.Info("generated %d CI incidents for scope %s (threshold: %d consecutive failures)", len(incidents), fullName, threshold)
+	return nil
+}
+
+// detectCiIncidents finds failure streaks of at least threshold runs per job name
+//
+// Parameters:
+//   - jobs: CI jobs ordered by job name, then by start time
+//   - threshold: Number of consecutive failures that makes an incident
+//
+// Returns:
+//   - []ciIncident: One entry per streak, in the order of the input
+func detectCiIncidents(jobs []ciIncidentJob, threshold int) []ciIncident {
+	var incidents []ciIncident
+	var streak *ciIncident
+
+	closeStreak := func(resolvedBy *ciIncidentJob) {
+		if streak != nil && streak.Failures >= threshold {
+			streak.ResolvedBy = resolvedBy
+			incidents = append(incidents, *streak)
+		}
+		streak = nil
+	}
+
+	for i := range jobs {
+		job := jobs[i]
+		if streak != nil && streak.JobName != job.JobName {
+			closeStreak(nil)
+		}
+		switch job.Result {
+		case "FAILURE":
+			if streak == nil {
+				streak = &ciIncident{JobName: job.JobName, FirstFailure: job}
+			}
+			streak.Failures++
+		case "SUCCESS":
+			closeStreak(&job)
+		}
+	}
+	closeStreak(nil)
+
+	return incidents
+}
+
+// ciIncidentIdPrefix is shared by all incident IDs of a connection
+func ciIncidentIdPrefix(connectionId uint64) string {
+	return fmt.Sprintf("testregistry:%d:incident:", connectionId)
+}
+
+// toDomain converts a failure streak into a domain incident
+func (i ciIncident) toDomain(connectionId uint64, fullName, scopeTable string) *ticket.Incident {
+	hash := sha256.Sum256([]byte(fullName + "\x00" + i.JobName + "\x00" + i.FirstFailure.JobId))
+
+	createdDate := i.FirstFailure.StartedAt
+	if createdDate == nil {
+		createdDate = i.FirstFailure.FinishedAt
+	}
+	incident := &ticket.Incident{
+		DomainEntity: domainlayer.DomainEntity{Id: ciIncidentIdPrefix(connectionId) + hex.EncodeToString(hash[:16])},
+		Url:          i.FirstFailure.ViewURL,
+		IncidentKey:  i.FirstFailure.JobId,
+		Title:        fmt.Sprintf("CI job %s is failing", i.JobName),
+		Description:  fmt.Sprintf("%d consecutive failures of %s since run %s", i.Failures, i.JobName, i.FirstFailure.JobId),
+		Status:       ticket.IN_PROGRESS,
+		CreatedDate:  createdDate,
+		UpdatedDate:  createdDate,
+		Component:    i.JobName,
+		Table:        scopeTable,
+		ScopeId:      fullName,
+	}
+	if i.ResolvedBy != nil {
+		resolvedAt := i.ResolvedBy.FinishedAt
+		if resolvedAt == nil {
+			resolvedAt = i.ResolvedBy.StartedAt
+		}
+		incident.Status = ticket.DONE
+		incident.OriginalStatus = "SUCCESS"
+		incident.ResolutionDate = resolvedAt
+		incident.UpdatedDate = resolvedAt
+		if createdDate != nil && resolvedAt != nil && resolvedAt.After(*createdDate) {
+			minutes := uint(resolvedAt.Sub(*createdDate).Minutes())
+			incident.LeadTimeMinutes = &minutes
+		}
+	} else {
+		incident.OriginalStatus = "FAILURE"
+	}
+	return incident
+}
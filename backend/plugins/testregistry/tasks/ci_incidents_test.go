@@ -0,0 +1,98 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/stretchr/testify/assert"
+)
+
+func incidentJob(id, name, result string, startedAt time.Time) ciIncidentJob {
+	finishedAt := startedAt.Add(30 * time.Minute)
+	return ciIncidentJob{JobId: id, JobName: name, Result: result, StartedAt: &startedAt, FinishedAt: &finishedAt}
+}
+
+func TestDetectCiIncidents(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+
+	t.Run("streak below threshold is ignored", func(t *testing.T) {
+		jobs := []ciIncidentJob{
+			incidentJob("1", "e2e", "FAILURE", hour(0)),
+			incidentJob("2", "e2e", "FAILURE", hour(1)),
+			incidentJob("3", "e2e", "SUCCESS", hour(2)),
+		}
+		assert.Empty(t, detectCiIncidents(jobs, 3))
+	})
+
+	t.Run("streak resolved by the next success", func(t *testing.T) {
+		jobs := []ciIncidentJob{
+			incidentJob("1", "e2e", "SUCCESS", hour(0)),
+			incidentJob("2", "e2e", "FAILURE", hour(1)),
+			incidentJob("3", "e2e", "ABORTED", hour(2)),
+			incidentJob("4", "e2e", "FAILURE", hour(3)),
+			incidentJob("5", "e2e", "FAILURE", hour(4)),
+			incidentJob("6", "e2e", "SUCCESS", hour(5)),
+			incidentJob("7", "e2e", "FAILURE", hour(6)),
+		}
+		incidents := detectCiIncidents(jobs, 3)
+		assert.Len(t, incidents, 1)
+		assert.Equal(t, "2", incidents[0].FirstFailure.JobId)
+		assert.Equal(t, 3, incidents[0].Failures)
+		assert.Equal(t, "6", incidents[0].ResolvedBy.JobId)
+	})
+
+	t.Run("streaks are tracked per job name and may stay open", func(t *testing.T) {
+		jobs := []ciIncidentJob{
+			incidentJob("1", "a-periodic", "FAILURE", hour(0)),
+			incidentJob("2", "a-periodic", "FAILURE", hour(1)),
+			incidentJob("3", "b-periodic", "FAILURE", hour(0)),
+			incidentJob("4", "b-periodic", "SUCCESS", hour(1)),
+		}
+		incidents := detectCiIncidents(jobs, 2)
+		assert.Len(t, incidents, 1)
+		assert.Equal(t, "a-periodic", incidents[0].JobName)
+		assert.Nil(t, incidents[0].ResolvedBy)
+	})
+}
+
+func TestCiIncidentToDomain(t *testing.T) {
+	started := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	first := incidentJob("run-1", "e2e", "FAILURE", started)
+	first.ViewURL = "https://prow.example/run-1"
+	fix := incidentJob("run-9", "e2e", "SUCCESS", started.Add(4*time.Hour))
+
+	open := ciIncident{JobName: "e2e", FirstFailure: first, Failures: 3}.toDomain(1, "org/repo", "_tool_testregistry_scopes")
+	assert.True(t, strings.HasPrefix(open.Id, ciIncidentIdPrefix(1)))
+	assert.Equal(t, ticket.IN_PROGRESS, open.Status)
+	assert.Equal(t, started, *open.CreatedDate)
+	assert.Nil(t, open.ResolutionDate)
+	assert.Equal(t, "https://prow.example/run-1", open.Url)
+	assert.Equal(t, "_tool_testregistry_scopes", open.Table)
+	assert.Equal(t, "org/repo", open.ScopeId)
+
+	resolved := ciIncident{JobName: "e2e", FirstFailure: first, Failures: 3, ResolvedBy: &fix}.toDomain(1, "org/repo", "_tool_testregistry_scopes")
+	assert.Equal(t, open.Id, resolved.Id)
+	assert.Equal(t, ticket.DONE, resolved.Status)
+	assert.Equal(t, *fix.FinishedAt, *resolved.ResolutionDate)
+	assert.Equal(t, uint(270), *resolved.LeadTimeMinutes)
+}
@@ -0,0 +1,251 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// ConvertCIJobsMeta defines the metadata for the CI job converter subtask
+var ConvertCIJobsMeta = plugin.SubTaskMeta{
+	Name:             "convertCIJobs",
+	EntryPoint:       ConvertCIJobs,
+	EnabledByDefault: true,
+	Description:      "Convert the CI jobs of the scope into the domain layer cicd_scopes, cicd_pipelines, cicd_tasks and cicd_pipeline_commits tables",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta, &CollectTektonJobsMeta, &CollectKubernetesPipelineRunsMeta},
+}
+
+// ciJobResultRule maps ci_test_jobs.result to the domain result
+var ciJobResultRule = &devops.ResultRule{
+	Success: []string{"SUCCESS"},
+	Failure: []string{"FAILURE", "ERROR"},
+	Default: devops.RESULT_DEFAULT,
+}
+
+// ciJobStatusRule maps ci_test_jobs.result to the domain status
+var ciJobStatusRule = &devops.StatusRule{
+	InProgress: []string{"PENDING", "TRIGGERED", "RUNNING"},
+	Done:       []string{"SUCCESS", "FAILURE", "ERROR", "ABORTED"},
+	Default:    devops.STATUS_OTHER,
+}
+
+// tektonTaskResultRule maps ci_tekton_tasks.status to the domain result
+var tektonTaskResultRule = &devops.ResultRule{
+	Success: []string{"Succeeded"},
+	Failure: []string{"Failed"},
+	Default: devops.RESULT_DEFAULT,
+}
+
+// tektonTaskStatusRule maps ci_tekton_tasks.status to the domain status
+var tektonTaskStatusRule = &devops.StatusRule{
+	InProgress: []string{"Running", "Pending"},
+	Done:       []string{"Succeeded", "Failed", "Cancelled"},
+	Default:    devops.STATUS_OTHER,
+}
+
+// ConvertCIJobs maps the CI jobs of the scope into the cicd domain layer, so they take part in
+// the DORA metrics and the cross-plugin dashboards.
+//
+// The scope becomes a cicd_scopes row and each job a pipeline with the job's commit. Tekton
+// jobs get one task per Tekton task run, other jobs a single task mirroring the job. The
+// scope config deploymentPattern and productionPattern set the type and environment from the
+// job name.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered during the conversion, or nil if successful
+func ConvertCIJobs(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	db := taskCtx.GetDal()
+	connectionId := data.Options.ConnectionId
+
+	scopeName := data.Options.FullName
+	scope := &models.TestRegistryScope{}
+	if err := db.First(scope, dal.Where("connection_id = ? AND full_name = ?", connectionId, data.Options.FullName)); err == nil && scope.Name != "" {
+		scopeName = scope.Name
+	}
+	cicdScopeId := didgen.NewDomainIdGenerator(&models.TestRegistryScope{}).Generate(connectionId, data.Options.FullName)
+	if err := db.CreateOrUpdate(devops.NewCicdScope(cicdScopeId, scopeName)); err != nil {
+		return errors.Default.Wrap(err, "failed to save the cicd scope")
+	}
+
+	cursor, err := db.Cursor(
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ?", connectionId, data.Options.FullName),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load the CI jobs of the scope")
+	}
+	defer cursor.Close()
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: TestRegistryApiParams{
+				ConnectionId: connectionId,
+				FullName:     data.Options.FullName,
+			},
+			Table: RAW_PROW_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.TestRegistryCIJob{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			ciJob := inputRow.(*models.TestRegistryCIJob)
+			var tektonTasks []models.TektonTask
+			if ciJob.JobType == "tekton" {
+				err := db.All(&tektonTasks, dal.Where("connection_id = ? AND job_id = ?", ciJob.ConnectionId, ciJob.JobId), dal.Orderby("task_name"))
+				if err != nil {
+					return nil, errors.Default.Wrap(err, "failed to load the Tekton tasks of a job")
+				}
+			}
+			return convertCIJobToDomain(ciJob, cicdScopeId, tektonTasks, data.RegexEnricher), nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+// convertCIJobToDomain maps a CI job into its domain pipeline, tasks and pipeline commit
+//
+// Parameters:
+//   - ciJob: The CI job to convert
+//   - cicdScopeId: Domain ID of the scope
+//   - tektonTasks: The Tekton task runs of the job, empty for a single task mirroring the job
+//   - regexEnricher: Sets the type and environment from the job name (may be nil)
+//
+// Returns:
+//   - []interface{}: The domain rows, nothing if the job has no timestamp at all
+func convertCIJobToDomain(ciJob *models.TestRegistryCIJob, cicdScopeId string, tektonTasks []models.TektonTask, regexEnricher *helper.RegexEnricher) []interface{} {
+	datesInfo, ok := ciJobDatesInfo(ciJob)
+	if !ok {
+		return nil
+	}
+	if regexEnricher == nil {
+		regexEnricher = helper.NewRegexEnricher()
+	}
+
+	pipeline := &devops.CICDPipeline{
+		DomainEntity:      domainlayer.DomainEntity{Id: didgen.NewDomainIdGenerator(&models.TestRegistryCIJob{}).Generate(ciJob.ConnectionId, ciJob.JobId)},
+		Name:              ciJob.JobName,
+		DisplayTitle:      fmt.Sprintf("%s#%s", ciJob.JobName, ciJob.JobId),
+		Url:               ciJob.ViewURL,
+		Result:            devops.GetResult(ciJobResultRule, ciJob.Result),
+		Status:            devops.GetStatus(ciJobStatusRule, ciJob.Result),
+		OriginalStatus:    ciJob.Result,
+		OriginalResult:    ciJob.Result,
+		Type:              regexEnricher.ReturnNameIfMatched(devops.DEPLOYMENT, ciJob.JobName),
+		Environment:       regexEnricher.ReturnNameIfOmittedOrMatched(devops.PRODUCTION, ciJob.JobName),
+		QueuedDurationSec: ciJob.QueuedDurationSec,
+		TaskDatesInfo:     datesInfo,
+		CicdScopeId:       cicdScopeId,
+	}
+	if ciJob.DurationSec != nil {
+		pipeline.DurationSec = *ciJob.DurationSec
+	}
+	rows := []interface{}{pipeline}
+
+	if len(tektonTasks) == 0 {
+		rows = append(rows, &devops.CICDTask{
+			DomainEntity:      pipeline.DomainEntity,
+			Name:              pipeline.Name,
+			PipelineId:        pipeline.Id,
+			Result:            pipeline.Result,
+			Status:            pipeline.Status,
+			OriginalStatus:    pipeline.OriginalStatus,
+			OriginalResult:    pipeline.OriginalResult,
+			Type:              pipeline.Type,
+			Environment:       pipeline.Environment,
+			DurationSec:       pipeline.DurationSec,
+			QueuedDurationSec: pipeline.QueuedDurationSec,
+			TaskDatesInfo:     pipeline.TaskDatesInfo,
+			CicdScopeId:       cicdScopeId,
+		})
+	}
+	taskIdGen := didgen.NewDomainIdGenerator(&models.TektonTask{})
+	for _, tektonTask := range tektonTasks {
+		rows = append(rows, &devops.CICDTask{
+			DomainEntity:   domainlayer.DomainEntity{Id: taskIdGen.Generate(tektonTask.ConnectionId, tektonTask.JobId, tektonTask.TaskName)},
+			Name:           tektonTask.TaskName,
+			PipelineId:     pipeline.Id,
+			Result:         devops.GetResult(tektonTaskResultRule, tektonTask.Status),
+			Status:         devops.GetStatus(tektonTaskStatusRule, tektonTask.Status),
+			OriginalStatus: tektonTask.Status,
+			OriginalResult: tektonTask.Status,
+			Type:           regexEnricher.ReturnNameIfMatched(devops.DEPLOYMENT, tektonTask.TaskName),
+			Environment:    regexEnricher.ReturnNameIfOmittedOrMatched(devops.PRODUCTION, tektonTask.TaskName),
+			DurationSec:    tektonTask.DurationSec,
+			// Task runs only report their duration, they are dated by their pipeline
+			TaskDatesInfo: devops.TaskDatesInfo{CreatedDate: datesInfo.CreatedDate},
+			CicdScopeId:   cicdScopeId,
+		})
+	}
+
+	// An abbreviated SHA would never join the domain commits
+	if ciJob.CommitSHA != "" && !ciJob.CommitShaShort {
+		pipelineCommit := &devops.CiCDPipelineCommit{
+			PipelineId:   pipeline.Id,
+			CommitSha:    ciJob.CommitSHA,
+			DisplayTitle: pipeline.DisplayTitle,
+			Url:          pipeline.Url,
+		}
+		// Prow jobs report the GitHub org and repo, Tekton jobs the Quay.io ones
+		if ciJob.JobType == "prow" && ciJob.Organization != "" && ciJob.Repository != "" {
+			pipelineCommit.RepoUrl = fmt.Sprintf("https://github.com/%s/%s", ciJob.Organization, ciJob.Repository)
+		}
+		rows = append(rows, pipelineCommit)
+	}
+	return rows
+}
+
+// ciJobDatesInfo returns the domain dates of a CI job. The domain creation date is the
+// earliest of the queued, started and finished times.
+//
+// Returns:
+//   - devops.TaskDatesInfo: The dates of the job
+//   - bool: false if the job has none of the timestamps
+func ciJobDatesInfo(ciJob *models.TestRegistryCIJob) (devops.TaskDatesInfo, bool) {
+	datesInfo := devops.TaskDatesInfo{
+		QueuedDate:   ciJob.QueuedAt,
+		StartedDate:  ciJob.StartedAt,
+		FinishedDate: ciJob.FinishedAt,
+	}
+	for _, date := range []*time.Time{ciJob.QueuedAt, ciJob.StartedAt, ciJob.FinishedAt} {
+		if date != nil {
+			datesInfo.CreatedDate = *date
+			return datesInfo, true
+		}
+	}
+	return datesInfo, false
+}
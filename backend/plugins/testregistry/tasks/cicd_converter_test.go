@@ -0,0 +1,133 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockTestRegistryPlugin(t *testing.T) {
+	mockMeta := mockplugin.NewPluginMeta(t)
+	mockMeta.On("RootPkgPath").Return("github.com/apache/incubator-devlake/plugins/testregistry")
+	mockMeta.On("Name").Return("dummy").Maybe()
+	assert.Nil(t, plugin.RegisterPlugin("testregistry", mockMeta))
+}
+
+func TestConvertCIJobToDomain(t *testing.T) {
+	mockTestRegistryPlugin(t)
+	queuedAt := time.Date(2025, 3, 1, 9, 58, 0, 0, time.UTC)
+	startedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	finishedAt := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+	duration := 1800.0
+	enricher := helper.NewRegexEnricher()
+	assert.Nil(t, enricher.TryAdd(devops.DEPLOYMENT, "deploy"))
+	assert.Nil(t, enricher.TryAdd(devops.PRODUCTION, "prod"))
+
+	t.Run("prow job with a commit", func(t *testing.T) {
+		rows := convertCIJobToDomain(&models.TestRegistryCIJob{
+			ConnectionId: 1,
+			JobId:        "1800000000000000001",
+			JobName:      "periodic-deploy-prod",
+			JobType:      "prow",
+			Organization: "konflux-ci",
+			Repository:   "e2e-tests",
+			CommitSHA:    "abc123",
+			Result:       "FAILURE",
+			QueuedAt:     &queuedAt,
+			StartedAt:    &startedAt,
+			FinishedAt:   &finishedAt,
+			DurationSec:  &duration,
+			ViewURL:      "https://prow.ci.openshift.org/view/gs/1",
+		}, "testregistry:TestRegistryScope:1:konflux-ci/e2e-tests", nil, enricher)
+		assert.Len(t, rows, 3)
+
+		pipeline := rows[0].(*devops.CICDPipeline)
+		assert.Equal(t, "testregistry:TestRegistryCIJob:1:1800000000000000001", pipeline.Id)
+		assert.Equal(t, "periodic-deploy-prod#1800000000000000001", pipeline.DisplayTitle)
+		assert.Equal(t, devops.RESULT_FAILURE, pipeline.Result)
+		assert.Equal(t, devops.STATUS_DONE, pipeline.Status)
+		assert.Equal(t, devops.DEPLOYMENT, pipeline.Type)
+		assert.Equal(t, devops.PRODUCTION, pipeline.Environment)
+		assert.Equal(t, queuedAt, pipeline.CreatedDate)
+		assert.Equal(t, duration, pipeline.DurationSec)
+		assert.Equal(t, "testregistry:TestRegistryScope:1:konflux-ci/e2e-tests", pipeline.CicdScopeId)
+
+		task := rows[1].(*devops.CICDTask)
+		assert.Equal(t, pipeline.Id, task.Id)
+		assert.Equal(t, pipeline.Id, task.PipelineId)
+		assert.Equal(t, pipeline.Result, task.Result)
+
+		commit := rows[2].(*devops.CiCDPipelineCommit)
+		assert.Equal(t, pipeline.Id, commit.PipelineId)
+		assert.Equal(t, "abc123", commit.CommitSha)
+		assert.Equal(t, "https://github.com/konflux-ci/e2e-tests", commit.RepoUrl)
+	})
+
+	t.Run("tekton job with task runs", func(t *testing.T) {
+		rows := convertCIJobToDomain(&models.TestRegistryCIJob{
+			ConnectionId: 2,
+			JobId:        "e2e-run-x1",
+			JobName:      "e2e-run",
+			JobType:      "tekton",
+			CommitSHA:    "def456",
+			Result:       "SUCCESS",
+			FinishedAt:   &finishedAt,
+		}, "scope", []models.TektonTask{
+			{ConnectionId: 2, JobId: "e2e-run-x1", TaskName: "deploy-konflux", Status: "Succeeded", DurationSec: 499},
+			{ConnectionId: 2, JobId: "e2e-run-x1", TaskName: "run-tests", Status: "Failed"},
+		}, nil)
+		assert.Len(t, rows, 4)
+
+		pipeline := rows[0].(*devops.CICDPipeline)
+		assert.Equal(t, devops.RESULT_SUCCESS, pipeline.Result)
+		assert.Equal(t, finishedAt, pipeline.CreatedDate)
+		assert.Empty(t, pipeline.Type)
+		assert.Equal(t, devops.PRODUCTION, pipeline.Environment, "no production pattern puts every job in production")
+
+		deploy := rows[1].(*devops.CICDTask)
+		assert.Equal(t, "testregistry:TektonTask:2:e2e-run-x1:deploy-konflux", deploy.Id)
+		assert.Equal(t, devops.RESULT_SUCCESS, deploy.Result)
+		assert.Equal(t, 499.0, deploy.DurationSec)
+		assert.Equal(t, devops.RESULT_FAILURE, rows[2].(*devops.CICDTask).Result)
+
+		assert.Empty(t, rows[3].(*devops.CiCDPipelineCommit).RepoUrl, "Tekton jobs report the Quay.io org and repo")
+	})
+
+	t.Run("job without timestamps or commit", func(t *testing.T) {
+		assert.Empty(t, convertCIJobToDomain(&models.TestRegistryCIJob{ConnectionId: 1, JobId: "1"}, "scope", nil, nil))
+
+		rows := convertCIJobToDomain(&models.TestRegistryCIJob{ConnectionId: 1, JobId: "2", Result: "PENDING", StartedAt: &startedAt}, "scope", nil, nil)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, devops.STATUS_IN_PROGRESS, rows[0].(*devops.CICDPipeline).Status)
+	})
+
+	t.Run("unresolved short commit is not linked", func(t *testing.T) {
+		rows := convertCIJobToDomain(&models.TestRegistryCIJob{ConnectionId: 1, JobId: "3", CommitSHA: "b4f3f3f", CommitShaShort: true, FinishedAt: &finishedAt}, "scope", nil, nil)
+		assert.Len(t, rows, 2)
+		assert.IsType(t, &devops.CICDPipeline{}, rows[0])
+		assert.IsType(t, &devops.CICDTask{}, rows[1])
+	})
+}
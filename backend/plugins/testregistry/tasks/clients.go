@@ -0,0 +1,84 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/helpers/gcshelper"
+)
+
+// ArtifactPuller pulls an OCI artifact into a local directory and returns its path.
+// A tag that no longer exists in the registry yields an errors.NotFound error.
+// Implemented by ORASClient.
+type ArtifactPuller interface {
+	PullArtifact(ctx context.Context, ref string) (string, errors.Error)
+}
+
+// ManifestAnnotationReader reads the annotations of an OCI artifact manifest.
+// Optional: the annotation columns of jobs pulled with an ArtifactPuller that
+// doesn't implement it stay empty. Implemented by ORASClient.
+type ManifestAnnotationReader interface {
+	FetchAnnotations(ctx context.Context, ref string) (map[string]string, errors.Error)
+}
+
+// TagLister lists the tags of a Quay.io repository created within [since, until].
+// Implemented by QuayClient.
+type TagLister interface {
+	ListTags(ctx context.Context, org, repo string, since, until *time.Time) ([]QuayTag, errors.Error)
+}
+
+// ResultsFetcher fetches the JUnit XML files stored for a Prow job.
+// Branch is only set for postsubmit jobs and only used by a gcsPrefixTemplate with {branch} (see postsubmitBranch).
+// Implemented by GCSBucket and, as its HTTP fallback, ProwArtifactsClient.
+type ResultsFetcher interface {
+	GetJobJunitContent(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, fileName *regexp.Regexp) ([]JUnitFile, error)
+}
+
+// BuildLogFetcher reads the end of the build-log.txt of a Prow job, with the same path arguments as
+// ResultsFetcher; a missing log returns no content and no error. Implemented by GCSBucket.
+type BuildLogFetcher interface {
+	GetBuildLogTail(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, maxBytes int64) ([]byte, error)
+}
+
+// JobHistoryStore lists and reads the Prow job history kept in the Openshift CI bucket
+// (the layout behind the Prow job-history pages). Implemented by GCSBucket.
+type JobHistoryStore interface {
+	gcshelper.HistoryStore
+	// ListObjectNames returns the names of the objects directly below prefix
+	ListObjectNames(ctx context.Context, prefix string) ([]string, error)
+}
+
+// PipelineRunWatcher lists and watches the Tekton PipelineRuns of a namespace.
+// Implemented by KubernetesClient.
+type PipelineRunWatcher interface {
+	ListPipelineRuns(ctx context.Context, namespace string) ([]KubernetesPipelineRun, string, errors.Error)
+	WatchPipelineRuns(ctx context.Context, namespace, resourceVersion string, timeout time.Duration, handle func(*KubernetesPipelineRun)) errors.Error
+}
+
+var _ ArtifactPuller = (*ORASClient)(nil)
+var _ ManifestAnnotationReader = (*ORASClient)(nil)
+var _ TagLister = (*QuayClient)(nil)
+var _ ResultsFetcher = (*GCSBucket)(nil)
+var _ BuildLogFetcher = (*GCSBucket)(nil)
+var _ JobHistoryStore = (*GCSBucket)(nil)
+var _ ResultsFetcher = (*ProwArtifactsClient)(nil)
+var _ PipelineRunWatcher = (*KubernetesClient)(nil)
@@ -0,0 +1,92 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/stretchr/testify/mock"
+)
+
+// mockArtifactPuller is a testify mock for ArtifactPuller
+type mockArtifactPuller struct {
+	mock.Mock
+}
+
+func (m *mockArtifactPuller) PullArtifact(ctx context.Context, ref string) (string, errors.Error) {
+	ret := m.Called(ctx, ref)
+	var err errors.Error
+	if e := ret.Get(1); e != nil {
+		err = e.(errors.Error)
+	}
+	return ret.String(0), err
+}
+
+// mockTagLister is a testify mock for TagLister
+type mockTagLister struct {
+	mock.Mock
+}
+
+func (m *mockTagLister) ListTags(ctx context.Context, org, repo string, since, until *time.Time) ([]QuayTag, errors.Error) {
+	ret := m.Called(ctx, org, repo, since, until)
+	var tags []QuayTag
+	if t := ret.Get(0); t != nil {
+		tags = t.([]QuayTag)
+	}
+	var err errors.Error
+	if e := ret.Get(1); e != nil {
+		err = e.(errors.Error)
+	}
+	return tags, err
+}
+
+// mockResultsFetcher is a testify mock for ResultsFetcher
+type mockResultsFetcher struct {
+	mock.Mock
+}
+
+func (m *mockResultsFetcher) GetJobJunitContent(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, fileName *regexp.Regexp) ([]JUnitFile, error) {
+	ret := m.Called(ctx, orgName, repoName, pullNumber, branch, jobId, jobType, jobName, fileName)
+	var files []JUnitFile
+	if f := ret.Get(0); f != nil {
+		files = f.([]JUnitFile)
+	}
+	return files, ret.Error(1)
+}
+
+// mockBuildLogFetcher is a testify mock for BuildLogFetcher
+type mockBuildLogFetcher struct {
+	mock.Mock
+}
+
+func (m *mockBuildLogFetcher) GetBuildLogTail(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, maxBytes int64) ([]byte, error) {
+	ret := m.Called(ctx, orgName, repoName, pullNumber, branch, jobId, jobType, jobName, maxBytes)
+	var content []byte
+	if c := ret.Get(0); c != nil {
+		content = c.([]byte)
+	}
+	return content, ret.Error(1)
+}
+
+var _ ArtifactPuller = (*mockArtifactPuller)(nil)
+var _ TagLister = (*mockTagLister)(nil)
+var _ ResultsFetcher = (*mockResultsFetcher)(nil)
+var _ BuildLogFetcher = (*mockBuildLogFetcher)(nil)
@@ -0,0 +1,253 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const pipelineStatusJSON = `{
+	"pipelineRunName": "%s",
+	"status": "Failed",
+	"eventType": "push",
+	"scenario": "e2e-test",
+	"duration": "120s",
+	"git": {"gitOrganization": "org", "gitRepository": "repo", "commitSha": "abc123"},
+	"timestamps": {
+		"createdAt": "2024-06-15T10:00:00Z",
+		"startedAt": "2024-06-15T10:01:00Z",
+		"finishedAt": "2024-06-15T10:03:00Z"
+	},
+	"taskRuns": [{"name": "run-tests", "status": "Failed", "duration": "100s"}]
+}`
+
+// writeTektonArtifact creates a directory that looks like a pulled Tekton artifact
+func writeTektonArtifact(t *testing.T, pipelineRunName string) string {
+	t.Helper()
+	dir := t.TempDir()
+	content := fmt.Sprintf(pipelineStatusJSON, pipelineRunName)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "pipeline-status.json"), []byte(content), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "e2e-report.xml"), []byte(validJUnitXML), 0o644))
+	return dir
+}
+
+func setupTektonProcessingContext(t *testing.T, alreadyProcessed int64) (*mockplugin.SubTaskContext, *mockdal.Dal) {
+	t.Helper()
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDal := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+
+	mockCtx.On("GetLogger").Return(mockLogger)
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	mockCtx.On("GetData").Return(nil).Maybe()
+	mockCtx.On("SetProgress", mock.Anything, mock.Anything).Maybe()
+
+	mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+	mockLogger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	mockDal.On("Count", mock.Anything).Return(alreadyProcessed, nil).Maybe()
+	mockDal.On("Create", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Maybe()
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	return mockCtx, mockDal
+}
+
+func TestProcessTektonArtifacts(t *testing.T) {
+	data := &TestRegistryTaskData{
+		Options:    &TestRegistryOptions{ConnectionId: 1, FullName: "quay-org/repo"},
+		JUnitRegex: regexp.MustCompile(`e2e-.*\.xml`),
+	}
+
+	t.Run("pulled artifact is saved as CI job with JUnit results", func(t *testing.T) {
+		mockCtx, mockDal := setupTektonProcessingContext(t, 0)
+		puller := new(mockArtifactPuller)
+		puller.On("PullArtifact", mock.Anything, "run-1").Return(writeTektonArtifact(t, "run-1"), nil)
+
+		stats := processTektonArtifacts(mockCtx, puller, []QuayTag{{Name: "run-1"}}, data, nil, mockDal,
+			"_raw_table", "{}", "oras://quay.io/quay-org/repo", t.TempDir(), "quay-org/repo", "quay-org", "repo")
+
+		puller.AssertExpectations(t)
+		assert.Equal(t, 1, stats.savedCount)
+		assert.Equal(t, 1, stats.rawSavedCount)
+		assert.Equal(t, 1, stats.junitFoundCount)
+		mockDal.AssertCalled(t, "CreateOrUpdate", mock.MatchedBy(func(job *models.TestRegistryCIJob) bool {
+			return job.JobId == "run-1" && job.Result == "FAILURE"
+		}), mock.Anything)
+	})
+
+	t.Run("pull failure skips artifact and records it for a retry", func(t *testing.T) {
+		mockCtx, mockDal := setupTektonProcessingContext(t, 0)
+		puller := new(mockArtifactPuller)
+		puller.On("PullArtifact", mock.Anything, "run-2").Return("", errors.Default.New("oras pull failed"))
+
+		stats := processTektonArtifacts(mockCtx, puller, []QuayTag{{Name: "run-2"}}, data, nil, mockDal,
+			"_raw_table", "{}", "oras://quay.io/quay-org/repo", t.TempDir(), "quay-org/repo", "quay-org", "repo")
+
+		assert.Equal(t, 0, stats.savedCount)
+		assert.Equal(t, []string{"run-2"}, tagNames(stats.failedTags))
+		mockDal.AssertNotCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
+	})
+
+	t.Run("expired tag is recorded and counted", func(t *testing.T) {
+		mockCtx, mockDal := setupTektonProcessingContext(t, 0)
+		puller := new(mockArtifactPuller)
+		puller.On("PullArtifact", mock.Anything, "run-4").Return("", errors.NotFound.New("artifact no longer exists"))
+
+		stats := processTektonArtifacts(mockCtx, puller, []QuayTag{{Name: "run-4"}}, data, nil, mockDal,
+			"_raw_table", "{}", "oras://quay.io/quay-org/repo", t.TempDir(), "quay-org/repo", "quay-org", "repo")
+
+		assert.Equal(t, 0, stats.savedCount)
+		assert.Equal(t, 1, stats.expiredCount)
+		mockDal.AssertCalled(t, "CreateOrUpdate", mock.MatchedBy(func(tag *models.ExpiredTag) bool {
+			return tag.Tag == "run-4" && tag.ScopeId == "quay-org/repo"
+		}), mock.Anything)
+	})
+
+	t.Run("already processed tag is not pulled", func(t *testing.T) {
+		mockCtx, mockDal := setupTektonProcessingContext(t, 1)
+		puller := new(mockArtifactPuller)
+
+		stats := processTektonArtifacts(mockCtx, puller, []QuayTag{{Name: "run-3"}}, data, nil, mockDal,
+			"_raw_table", "{}", "oras://quay.io/quay-org/repo", t.TempDir(), "quay-org/repo", "quay-org", "repo")
+
+		assert.Equal(t, 0, stats.savedCount)
+		puller.AssertNotCalled(t, "PullArtifact", mock.Anything, mock.Anything)
+	})
+}
+
+func TestFetchJUnitFromGCS(t *testing.T) {
+	junitRegex := regexp.MustCompile(`junit.*\.xml`)
+	prNumber := 42
+	job := &ProwJob{}
+	job.Spec.Refs = &ProwJobRefs{Org: "konflux-ci", Repo: "build-service"}
+
+	tests := []struct {
+		name          string
+		jobType       string
+		ciJob         *models.TestRegistryCIJob
+		pullNumber    string
+		branch        string
+		expectOrg     string
+		expectRepo    string
+		expectCall    bool
+		expectedFiles int
+	}{
+		{
+			name:          "periodic job has no org/repo/pr",
+			jobType:       "periodic",
+			ciJob:         &models.TestRegistryCIJob{JobId: "1", JobName: "periodic-e2e"},
+			expectCall:    true,
+			expectedFiles: 1,
+		},
+		{
+			name:          "presubmit job uses refs and pr number",
+			jobType:       "presubmit",
+			ciJob:         &models.TestRegistryCIJob{JobId: "2", JobName: "pull-e2e", PullRequestNumber: &prNumber},
+			pullNumber:    "42",
+			expectOrg:     "konflux-ci",
+			expectRepo:    "build-service",
+			expectCall:    true,
+			expectedFiles: 1,
+		},
+		{
+			name:       "presubmit job without pr number is skipped",
+			jobType:    "presubmit",
+			ciJob:      &models.TestRegistryCIJob{JobId: "3", JobName: "pull-e2e"},
+			expectCall: false,
+		},
+		{
+			name:          "postsubmit job uses refs without pr number",
+			jobType:       "postsubmit",
+			ciJob:         &models.TestRegistryCIJob{JobId: "4", JobName: "branch-e2e"},
+			expectOrg:     "konflux-ci",
+			expectRepo:    "build-service",
+			expectCall:    true,
+			expectedFiles: 1,
+		},
+		{
+			name:          "postsubmit job passes the branch",
+			jobType:       "postsubmit",
+			ciJob:         &models.TestRegistryCIJob{JobId: "6", JobName: "branch-e2e"},
+			branch:        "develop",
+			expectOrg:     "konflux-ci",
+			expectRepo:    "build-service",
+			expectCall:    true,
+			expectedFiles: 1,
+		},
+		{
+			name:          "branch is ignored for presubmit jobs",
+			jobType:       "presubmit",
+			ciJob:         &models.TestRegistryCIJob{JobId: "7", JobName: "pull-e2e", PullRequestNumber: &prNumber},
+			pullNumber:    "42",
+			branch:        "develop",
+			expectOrg:     "konflux-ci",
+			expectRepo:    "build-service",
+			expectCall:    true,
+			expectedFiles: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := new(mockResultsFetcher)
+			if tt.expectCall {
+				expectBranch := ""
+				if tt.jobType == "postsubmit" {
+					expectBranch = tt.branch
+				}
+				fetcher.On("GetJobJunitContent", mock.Anything, tt.expectOrg, tt.expectRepo, tt.pullNumber, expectBranch,
+					tt.ciJob.JobId, tt.jobType, tt.ciJob.JobName, junitRegex).
+					Return([]JUnitFile{{Path: "artifacts/junit.xml", Content: []byte(validJUnitXML)}}, nil)
+			}
+
+			files := fetchJUnitFromGCS(context.Background(), fetcher, job, tt.ciJob, tt.jobType, "org", "repo", tt.pullNumber, tt.branch, newMockLogger(), junitRegex)
+
+			assert.Len(t, files, tt.expectedFiles)
+			if tt.expectCall {
+				fetcher.AssertExpectations(t)
+			} else {
+				fetcher.AssertNotCalled(t, "GetJobJunitContent")
+			}
+		})
+	}
+
+	t.Run("listing error still returns partial results", func(t *testing.T) {
+		fetcher := new(mockResultsFetcher)
+		fetcher.On("GetJobJunitContent", mock.Anything, "", "", "", "", "5", "periodic", "periodic-e2e", junitRegex).
+			Return([]JUnitFile{{Path: "artifacts/junit.xml"}}, fmt.Errorf("GCS listing interrupted"))
+
+		ciJob := &models.TestRegistryCIJob{JobId: "5", JobName: "periodic-e2e"}
+		files := fetchJUnitFromGCS(context.Background(), fetcher, job, ciJob, "periodic", "org", "repo", "", "", newMockLogger(), junitRegex)
+		assert.Len(t, files, 1)
+	})
+}
@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// recordCollectionRun saves the volume and duration of the collection that started at startedAt,
+// replacing the record of the scope's previous run, and the JUnit availability of its jobs. Failures are only logged: the record feeds
+// the scheduling hints API and must never fail a collection.
+func recordCollectionRun(db dal.Dal, logger log.Logger, data *TestRegistryTaskData, startedAt time.Time, itemsListed, itemsProcessed int, stats collectionStats) {
+	run := &models.CollectionRun{
+		ConnectionId:    data.Options.ConnectionId,
+		ScopeId:         data.Options.FullName,
+		CollectionMode:  data.Connection.CollectionMode(),
+		StartedAt:       startedAt,
+		DurationSeconds: time.Since(startedAt).Seconds(),
+		ItemsListed:     itemsListed,
+		ItemsProcessed:  itemsProcessed,
+		JobsSaved:       stats.savedCount,
+		JunitFound:      stats.junitFoundCount,
+	}
+	if err := db.CreateOrUpdate(run); err != nil {
+		logger.Warn(err, "failed to record collection run", "scope", data.Options.FullName)
+	}
+	recordJUnitAvailability(db, logger, data, startedAt, stats.junitByJobName)
+}
+
+// recordJUnitAvailability saves whether the runs of each job saved by the collection uploaded JUnit
+// results. Jobs without runs in this collection keep the record of the last collection that saw them.
+// Like the collection run record, failures are only logged.
+func recordJUnitAvailability(db dal.Dal, logger log.Logger, data *TestRegistryTaskData, startedAt time.Time, junitByJobName map[string]*junitJobStats) {
+	if len(junitByJobName) == 0 {
+		return
+	}
+	var existing []models.JUnitAvailability
+	err := db.All(&existing, dal.Where("connection_id = ? AND scope_id = ?", data.Options.ConnectionId, data.Options.FullName))
+	if err != nil {
+		logger.Warn(err, "failed to load JUnit availability", "scope", data.Options.FullName)
+		return
+	}
+	for _, availability := range junitAvailabilityRows(data.Options.ConnectionId, data.Options.FullName, startedAt, existing, junitByJobName) {
+		if err := db.CreateOrUpdate(availability); err != nil {
+			logger.Warn(err, "failed to record JUnit availability", "scope", data.Options.FullName, "job_name", availability.JobName)
+		}
+	}
+}
+
+// junitAvailabilityRows builds the JUnit availability of the jobs saved by a collection, keeping
+// when JUnit results were last found from the existing rows of the scope
+func junitAvailabilityRows(connectionId uint64, scopeId string, startedAt time.Time, existing []models.JUnitAvailability, junitByJobName map[string]*junitJobStats) []*models.JUnitAvailability {
+	lastFound := make(map[string]*time.Time, len(existing))
+	for _, availability := range existing {
+		lastFound[availability.JobName] = availability.LastJunitFoundAt
+	}
+	jobNames := make([]string, 0, len(junitByJobName))
+	for jobName := range junitByJobName {
+		jobNames = append(jobNames, jobName)
+	}
+	sort.Strings(jobNames)
+
+	rows := make([]*models.JUnitAvailability, 0, len(jobNames))
+	for _, jobName := range jobNames {
+		jobStats := junitByJobName[jobName]
+		availability := &models.JUnitAvailability{
+			ConnectionId:     connectionId,
+			ScopeId:          scopeId,
+			JobName:          jobName,
+			LastRunAt:        startedAt,
+			JunitFound:       jobStats.found,
+			JunitNotFound:    jobStats.notFound,
+			LastJunitFoundAt: lastFound[jobName],
+		}
+		if jobStats.found > 0 {
+			foundAt := startedAt
+			availability.LastJunitFoundAt = &foundAt
+		}
+		rows = append(rows, availability)
+	}
+	return rows
+}
@@ -0,0 +1,121 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRecordCollectionRun(t *testing.T) {
+	data := &TestRegistryTaskData{
+		Options:    &TestRegistryOptions{ConnectionId: 3, FullName: "quay-org/repo"},
+		Connection: &models.TestRegistryConnection{CITool: models.CIToolTektonCI},
+	}
+	stats := collectionStats{savedCount: 4, junitFoundCount: 2, rawSavedCount: 4}
+	startedAt := time.Now().Add(-90 * time.Second)
+
+	t.Run("saves the run of the scope", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		var saved *models.CollectionRun
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			saved = args.Get(0).(*models.CollectionRun)
+		}).Return(nil)
+
+		recordCollectionRun(mockDal, newMockLogger(), data, startedAt, 10, 6, stats)
+
+		if assert.NotNil(t, saved) {
+			assert.Equal(t, uint64(3), saved.ConnectionId)
+			assert.Equal(t, "quay-org/repo", saved.ScopeId)
+			assert.Equal(t, models.CollectionModeQuay, saved.CollectionMode)
+			assert.Equal(t, startedAt, saved.StartedAt)
+			assert.GreaterOrEqual(t, saved.DurationSeconds, 90.0)
+			assert.Equal(t, 10, saved.ItemsListed)
+			assert.Equal(t, 6, saved.ItemsProcessed)
+			assert.Equal(t, 4, saved.JobsSaved)
+			assert.Equal(t, 2, saved.JunitFound)
+		}
+	})
+
+	t.Run("a failed save does not panic", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(errors.Default.New("db down"))
+		logger := newMockLogger()
+
+		recordCollectionRun(mockDal, logger, data, startedAt, 0, 0, collectionStats{})
+
+		logger.AssertCalled(t, "Warn", mock.Anything, "failed to record collection run", mock.Anything)
+	})
+}
+
+func TestJUnitAvailabilityRows(t *testing.T) {
+	startedAt := time.Date(2025, 2, 8, 10, 0, 0, 0, time.UTC)
+	previousFound := time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC)
+	existing := []models.JUnitAvailability{
+		{JobName: "e2e-broken", LastJunitFoundAt: &previousFound},
+		{JobName: "unit", LastJunitFoundAt: &previousFound},
+	}
+	stats := collectionStats{}
+	stats.recordJUnit("unit", true)
+	stats.recordJUnit("unit", false)
+	stats.recordJUnit("e2e-broken", false)
+	stats.recordJUnit("lint", false)
+	assert.Equal(t, 1, stats.junitFoundCount)
+	assert.Equal(t, 3, stats.junitNotFoundCount)
+
+	rows := junitAvailabilityRows(3, "konflux-ci/e2e", startedAt, existing, stats.junitByJobName)
+
+	if assert.Len(t, rows, 3) {
+		assert.Equal(t, "e2e-broken", rows[0].JobName)
+		assert.Equal(t, 0, rows[0].JunitFound)
+		assert.Equal(t, 1, rows[0].JunitNotFound)
+		assert.Equal(t, &previousFound, rows[0].LastJunitFoundAt)
+
+		assert.Equal(t, "lint", rows[1].JobName)
+		assert.Nil(t, rows[1].LastJunitFoundAt)
+
+		assert.Equal(t, "unit", rows[2].JobName)
+		assert.Equal(t, uint64(3), rows[2].ConnectionId)
+		assert.Equal(t, "konflux-ci/e2e", rows[2].ScopeId)
+		assert.Equal(t, startedAt, rows[2].LastRunAt)
+		assert.Equal(t, 1, rows[2].JunitFound)
+		assert.Equal(t, 1, rows[2].JunitNotFound)
+		assert.Equal(t, startedAt, *rows[2].LastJunitFoundAt)
+	}
+}
+
+func TestCollectionStatsAddMergesJUnitByJobName(t *testing.T) {
+	stats := collectionStats{}
+	stats.recordJUnit("unit", true)
+	slice := collectionStats{}
+	slice.recordJUnit("unit", false)
+	slice.recordJUnit("e2e", true)
+
+	stats.add(slice)
+
+	assert.Equal(t, 2, stats.junitFoundCount)
+	assert.Equal(t, 1, stats.junitNotFoundCount)
+	assert.Equal(t, &junitJobStats{found: 1, notFound: 1}, stats.junitByJobName["unit"])
+	assert.Equal(t, &junitJobStats{found: 1}, stats.junitByJobName["e2e"])
+}
@@ -0,0 +1,198 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	// fullCommitShaLength is the length of a full Git commit SHA-1, the only form that joins the domain commits
+	fullCommitShaLength = 40
+	// commitShaLookupTimeout bounds one GitHub API call resolving a short SHA
+	commitShaLookupTimeout = 10 * time.Second
+)
+
+// commitShaPattern matches full and abbreviated (git abbreviates to at least 4 characters) commit SHAs
+var commitShaPattern = regexp.MustCompile(`^[0-9a-f]{4,40}$`)
+
+// NormalizeCommitSha trims and lowercases a commit SHA
+//
+// Parameters:
+//   - sha: The commit SHA as reported by the CI system or artifact
+//
+// Returns:
+//   - string: The normalized SHA, "" when it isn't a hex SHA of 4 to 40 characters
+func NormalizeCommitSha(sha string) string {
+	sha = strings.ToLower(strings.TrimSpace(sha))
+	if !commitShaPattern.MatchString(sha) {
+		return ""
+	}
+	return sha
+}
+
+// CommitShaResolver expands the short commit SHAs some artifacts carry ("b4f3f3f") to full SHAs, so CI jobs
+// join the domain commits. A short SHA is looked up in the collected commits first, then on GitHub when the
+// connection has a GitHub token; SHAs that can't be resolved are kept and flagged with commit_sha_short.
+type CommitShaResolver struct {
+	githubURL   string
+	githubToken string
+	httpClient  *http.Client
+	resolved    map[string]string // full SHA by "org/repo@short", "" when it couldn't be resolved
+}
+
+// NewCommitShaResolver creates the commit SHA resolver of a connection
+//
+// Parameters:
+//   - connection: The connection, whose GitHub token (if any) enables the GitHub API lookup; may be nil
+//
+// Returns:
+//   - *CommitShaResolver: The resolver, never nil
+func NewCommitShaResolver(connection *models.TestRegistryConnection) *CommitShaResolver {
+	resolver := &CommitShaResolver{
+		githubURL:  "https://api.github.com",
+		httpClient: &http.Client{Timeout: commitShaLookupTimeout},
+		resolved:   map[string]string{},
+	}
+	if connection != nil {
+		resolver.githubToken = strings.TrimSpace(connection.GitHubToken)
+	}
+	return resolver
+}
+
+// Resolve normalizes a commit SHA and expands it to the full SHA when it is abbreviated
+//
+// Parameters:
+//   - db: Database holding the collected commits, nil to skip the lookup
+//   - logger: Logger for failed lookups
+//   - organization: GitHub organization of the commit
+//   - repository: GitHub repository of the commit
+//   - sha: The commit SHA as reported
+//
+// Returns:
+//   - string: The full SHA, the normalized short SHA if it couldn't be resolved, "" for an invalid SHA
+//   - bool: true if the returned SHA is still abbreviated
+func (r *CommitShaResolver) Resolve(db dal.Dal, logger log.Logger, organization, repository, sha string) (string, bool) {
+	sha = NormalizeCommitSha(sha)
+	if sha == "" || len(sha) == fullCommitShaLength {
+		return sha, false
+	}
+	if r == nil {
+		return sha, true
+	}
+
+	key := fmt.Sprintf("%s/%s@%s", organization, repository, sha)
+	full, ok := r.resolved[key]
+	if !ok {
+		full = lookupCollectedCommit(db, logger, organization, repository, sha)
+		if full == "" {
+			full = r.lookupGitHubCommit(logger, organization, repository, sha)
+		}
+		r.resolved[key] = full
+	}
+	if full == "" {
+		return sha, true
+	}
+	return full, false
+}
+
+// apply normalizes the commit SHA of a CI job, expanding it when abbreviated and flagging it when that fails.
+// A nil resolver only normalizes and flags.
+func (r *CommitShaResolver) apply(db dal.Dal, logger log.Logger, ciJob *models.TestRegistryCIJob) {
+	reported := ciJob.CommitSHA
+	ciJob.CommitSHA, ciJob.CommitShaShort = r.Resolve(db, logger, ciJob.Organization, ciJob.Repository, reported)
+	if reported != "" && ciJob.CommitSHA == "" {
+		logger.Debug("Dropping invalid commit SHA %q of job %s", reported, ciJob.JobId)
+	}
+}
+
+// lookupCollectedCommit returns the only full SHA starting with the short SHA, from the domain commits
+// of the repository (matched by its org/repo name) and the CI jobs already collected for it, "" when
+// there is none or it is ambiguous
+func lookupCollectedCommit(db dal.Dal, logger log.Logger, organization, repository, short string) string {
+	if db == nil {
+		return ""
+	}
+	var shas []string
+	err := db.Pluck("DISTINCT c.sha", &shas,
+		dal.From("commits c"),
+		dal.Join("JOIN repo_commits rc ON rc.commit_sha = c.sha"),
+		dal.Join("JOIN repos r ON r.id = rc.repo_id"),
+		dal.Where("r.name = ? AND c.sha LIKE ?", organization+"/"+repository, short+"%"),
+		dal.Limit(2))
+	if err != nil {
+		logger.Warn(err, "failed to look up commit %s in the collected commits", short)
+	}
+	if len(shas) == 0 {
+		err = db.Pluck("DISTINCT commit_sha", &shas, dal.From(&models.TestRegistryCIJob{}),
+			dal.Where("organization = ? AND repository = ? AND commit_sha LIKE ? AND commit_sha_short = ?", organization, repository, short+"%", false),
+			dal.Limit(2))
+		if err != nil {
+			logger.Warn(err, "failed to look up commit %s in the collected CI jobs", short)
+		}
+	}
+	if len(shas) != 1 || len(shas[0]) != fullCommitShaLength {
+		return ""
+	}
+	return shas[0]
+}
+
+// lookupGitHubCommit asks the GitHub API for the full SHA of a short SHA, "" without a token or on failure
+func (r *CommitShaResolver) lookupGitHubCommit(logger log.Logger, organization, repository, short string) string {
+	if r.githubToken == "" || organization == "" || repository == "" {
+		return ""
+	}
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/commits/%s", r.githubURL, organization, repository, short)
+	req, err := http.NewRequest(http.MethodGet, apiURL, nil)
+	if err != nil {
+		return ""
+	}
+	// The sha media type answers with the bare full SHA
+	req.Header.Set("Accept", "application/vnd.github.sha")
+	req.Header.Set("Authorization", "Bearer "+r.githubToken)
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		logger.Warn(err, "failed to resolve commit %s of %s/%s on GitHub", short, organization, repository)
+		return ""
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// 404 for unknown repos or commits, 422 for ambiguous short SHAs
+		logger.Debug("GitHub returned status %d resolving commit %s of %s/%s", resp.StatusCode, short, organization, repository)
+		return ""
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return ""
+	}
+	full := NormalizeCommitSha(string(body))
+	if len(full) != fullCommitShaLength || !strings.HasPrefix(full, short) {
+		return ""
+	}
+	return full
+}
@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCommitSha(t *testing.T) {
+	assert.Equal(t, "b4f3f3f", NormalizeCommitSha(" B4F3F3F\n"))
+	assert.Equal(t, "d4e5f6a7b8c90a1b2c3d4e5f6a7b8c9d0e1f2a3b", NormalizeCommitSha("d4e5f6a7b8c90a1b2c3d4e5f6a7b8c9d0e1f2a3b"))
+	assert.Equal(t, "b4f3", NormalizeCommitSha("b4f3"))
+	assert.Empty(t, NormalizeCommitSha("b4f"), "shorter than git abbreviates")
+	assert.Empty(t, NormalizeCommitSha("d4e5f6a7b8c90a1b2c3d4e5f6a7b8c9d0e1f2a3b4"), "longer than a SHA-1")
+	assert.Empty(t, NormalizeCommitSha("main"))
+	assert.Empty(t, NormalizeCommitSha(""))
+}
+
+func TestCommitShaResolver(t *testing.T) {
+	const full = "b4f3f3fa7b8c90a1b2c3d4e5f6a7b8c9d0e1f2a3"
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		calls++
+		if req.Header.Get("Authorization") != "Bearer gh-token" || req.URL.Path != "/repos/konflux-ci/e2e-tests/commits/b4f3f3f" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(full))
+	}))
+	t.Cleanup(server.Close)
+	logger := newMockLogger()
+
+	t.Run("full and invalid SHAs are not looked up", func(t *testing.T) {
+		var resolver *CommitShaResolver
+		sha, short := resolver.Resolve(nil, logger, "konflux-ci", "e2e-tests", strings.ToUpper(full))
+		assert.Equal(t, full, sha)
+		assert.False(t, short)
+		sha, short = resolver.Resolve(nil, logger, "konflux-ci", "e2e-tests", "not-a-sha")
+		assert.Empty(t, sha)
+		assert.False(t, short)
+	})
+
+	t.Run("short SHA is flagged without a GitHub token", func(t *testing.T) {
+		resolver := NewCommitShaResolver(&models.TestRegistryConnection{})
+		resolver.githubURL = server.URL
+		sha, short := resolver.Resolve(nil, logger, "konflux-ci", "e2e-tests", "b4f3f3f")
+		assert.Equal(t, "b4f3f3f", sha)
+		assert.True(t, short)
+		assert.Zero(t, calls)
+	})
+
+	t.Run("short SHA is resolved on GitHub once", func(t *testing.T) {
+		resolver := NewCommitShaResolver(&models.TestRegistryConnection{GitHubToken: "gh-token"})
+		resolver.githubURL = server.URL
+		for i := 0; i < 2; i++ {
+			ciJob := &models.TestRegistryCIJob{Organization: "konflux-ci", Repository: "e2e-tests", CommitSHA: "B4F3F3F"}
+			resolver.apply(nil, logger, ciJob)
+			assert.Equal(t, full, ciJob.CommitSHA)
+			assert.False(t, ciJob.CommitShaShort)
+		}
+		assert.Equal(t, 1, calls)
+
+		ciJob := &models.TestRegistryCIJob{Organization: "konflux-ci", Repository: "other", CommitSHA: "b4f3f3f"}
+		resolver.apply(nil, logger, ciJob)
+		assert.Equal(t, "b4f3f3f", ciJob.CommitSHA)
+		assert.True(t, ciJob.CommitShaShort)
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// componentRule is a compiled scope config component mapping
+type componentRule struct {
+	pattern   *regexp.Regexp
+	component string
+}
+
+// ComponentMapper resolves the Konflux component of a JUnit suite from its name
+type ComponentMapper struct {
+	rules []componentRule
+}
+
+// NewComponentMapper compiles the component mappings of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *ComponentMapper: The mapper, or nil if no mappings are configured
+//   - errors.Error: BadInput if a pattern is not a valid regex or a component is empty
+func NewComponentMapper(scopeConfig *models.TestRegistryScopeConfig) (*ComponentMapper, errors.Error) {
+	if scopeConfig == nil || len(scopeConfig.ComponentMappings) == 0 {
+		return nil, nil
+	}
+
+	mapper := &ComponentMapper{}
+	for i, mapping := range scopeConfig.ComponentMappings {
+		if strings.TrimSpace(mapping.Component) == "" {
+			return nil, errors.BadInput.New(fmt.Sprintf("componentMappings[%d]: component is required", i))
+		}
+		pattern, err := regexp.Compile(mapping.Pattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("componentMappings[%d]: invalid pattern %q", i, mapping.Pattern))
+		}
+		mapper.rules = append(mapper.rules, componentRule{pattern: pattern, component: mapping.Component})
+	}
+	return mapper, nil
+}
+
+// component returns the component of a suite
+//
+// The first rule matching the suite name wins; capture groups can be used in the
+// component, e.g. pattern "^(\w+)-e2e" with component "$1". Nested suites that match
+// no rule inherit the component of their parent.
+//
+// Parameters:
+//   - suiteName: Name of the suite
+//   - parentComponent: Component of the parent suite (empty for top-level suites)
+//
+// Returns:
+//   - string: The component, or parentComponent if no rule matches
+func (m *ComponentMapper) component(suiteName, parentComponent string) string {
+	if m == nil {
+		return parentComponent
+	}
+	for _, rule := range m.rules {
+		match := rule.pattern.FindStringSubmatchIndex(suiteName)
+		if match == nil {
+			continue
+		}
+		return string(rule.pattern.ExpandString(nil, rule.component, suiteName, match))
+	}
+	return parentComponent
+}
@@ -0,0 +1,104 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewComponentMapper(t *testing.T) {
+	mapper, err := NewComponentMapper(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, mapper)
+
+	mapper, err = NewComponentMapper(&models.TestRegistryScopeConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, mapper)
+
+	_, err = NewComponentMapper(&models.TestRegistryScopeConfig{
+		ComponentMappings: []models.ComponentMapping{{Pattern: "(", Component: "build-service"}},
+	})
+	assert.NotNil(t, err)
+
+	_, err = NewComponentMapper(&models.TestRegistryScopeConfig{
+		ComponentMappings: []models.ComponentMapping{{Pattern: "^build", Component: " "}},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestComponentMapperComponent(t *testing.T) {
+	mapper, err := NewComponentMapper(&models.TestRegistryScopeConfig{
+		ComponentMappings: []models.ComponentMapping{
+			{Pattern: `^Build Service`, Component: "build-service"},
+			{Pattern: `^([a-z-]+)-e2e$`, Component: "$1"},
+			{Pattern: `e2e`, Component: "e2e-tests"},
+		},
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "build-service", mapper.component("Build Service E2E", ""))
+	assert.Equal(t, "integration-service", mapper.component("integration-service-e2e", ""))
+	assert.Equal(t, "e2e-tests", mapper.component("Red Hat App Studio e2e", ""))
+	assert.Equal(t, "release-service", mapper.component("unmatched", "release-service"))
+	assert.Equal(t, "", mapper.component("unmatched", ""))
+
+	var nilMapper *ComponentMapper
+	assert.Equal(t, "parent", nilMapper.component("Build Service", "parent"))
+}
+
+func TestSaveSuiteRecursivelyComponents(t *testing.T) {
+	mockDal := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+
+	saved := map[string]*models.TestSuite{}
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		if row, ok := args.Get(0).(*models.TestSuite); ok {
+			saved[row.Name] = row
+		}
+	}).Return(nil)
+
+	mapper, err := NewComponentMapper(&models.TestRegistryScopeConfig{
+		ComponentMappings: []models.ComponentMapping{
+			{Pattern: `^Build Service`, Component: "build-service"},
+			{Pattern: `\[release\]`, Component: "release-service"},
+		},
+	})
+	assert.Nil(t, err)
+
+	suite := &TestSuite{
+		Name: "Build Service E2E",
+		Children: []*TestSuite{
+			{Name: "builds a component"},
+			{Name: "[release] pushes the image"},
+		},
+	}
+	s, _ := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, &junitSaveRules{Components: mapper}, nil)
+	assert.Equal(t, 3, s)
+	assert.Equal(t, "build-service", saved["Build Service E2E"].Component)
+	assert.Equal(t, "build-service", saved["builds a component"].Component)
+	assert.Equal(t, "release-service", saved["[release] pushes the image"].Component)
+	assert.Equal(t, saved["Build Service E2E"].SuiteId, *saved["builds a component"].ParentSuiteId)
+}
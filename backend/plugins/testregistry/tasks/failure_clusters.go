@@ -0,0 +1,237 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// failureClusterWindow is how far back failed test cases are clustered, by the finish time of their job
+const failureClusterWindow = 7 * 24 * time.Hour
+
+// maxNormalizedMessageLength caps the normalized message so huge outputs still cluster on their head
+const maxNormalizedMessageLength = 2000
+
+// ClusterFailureMessagesMeta defines the metadata for the failure message clustering subtask
+var ClusterFailureMessagesMeta = plugin.SubTaskMeta{
+	Name:             "clusterFailureMessages",
+	EntryPoint:       ClusterFailureMessages,
+	EnabledByDefault: true,
+	Description:      "Group the failed test cases of the last 7 days by normalized failure message and count the jobs each failure hit",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta, &CollectTektonJobsMeta},
+}
+
+// failureMessageNormalizers replace run-specific details of a failure message with placeholders, in
+// order: earlier rules consume text that later, more generic rules would otherwise split up
+var failureMessageNormalizers = []func(string) string{
+	replaceWith(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`, "<uuid>"),
+	replaceWith(`\b\d{4}-\d{2}-\d{2}([T ]\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:?\d{2})?)?\b`, "<time>"),
+	replaceWith(`\b\d{2}:\d{2}:\d{2}(\.\d+)?\b`, "<time>"),
+	replaceWith(`\b\d{1,3}(\.\d{1,3}){3}(:\d+)?\b`, "<ip>"),
+	// Hashes, commit SHAs and pointers: hex tokens mixing digits and letters
+	replaceMatching(`(?i)\b(0x)?[0-9a-f]{7,}\b`, "<hash>", func(token string) bool {
+		token = strings.ToLower(token)
+		return strings.ContainsAny(token, "0123456789") && strings.ContainsAny(token, "abcdefx")
+	}),
+	// Random suffixes of generated Kubernetes names (pod-7d9f8c-x2kq9)
+	replaceMatching(`-[a-z0-9]{5,10}\b`, "-<id>", func(suffix string) bool {
+		return strings.ContainsAny(suffix, "0123456789") && strings.ContainsAny(suffix, "abcdefghijklmnopqrstuvwxyz")
+	}),
+	replaceWith(`\b(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+\b`, "<duration>"),
+	replaceWith(`\b\d+(\.\d+)?\b`, "<n>"),
+	replaceWith(`\s+`, " "),
+}
+
+func replaceWith(pattern, placeholder string) func(string) string {
+	re := regexp.MustCompile(pattern)
+	return func(s string) string { return re.ReplaceAllString(s, placeholder) }
+}
+
+func replaceMatching(pattern, placeholder string, applies func(string) bool) func(string) string {
+	re := regexp.MustCompile(pattern)
+	return func(s string) string {
+		return re.ReplaceAllStringFunc(s, func(match string) string {
+			if applies(match) {
+				return placeholder
+			}
+			return match
+		})
+	}
+}
+
+// failedTestCase is a failed test case of the scope with the job it ran in
+type failedTestCase struct {
+	JobId          string
+	Name           string
+	Classname      string
+	FailureMessage string
+	JobName        string
+	FinishedAt     *time.Time
+	ViewURL        string `gorm:"column:view_url"`
+}
+
+// ClusterFailureMessages groups recent test failures of the scope that share a failure message.
+//
+// Failure messages of test cases that failed within failureClusterWindow are normalized with
+// normalizeFailureMessage, so the same assertion failing on different pods, commits or days
+// yields the same signature. Each signature becomes a row of _tool_testregistry_failure_clusters
+// counting the failed cases, job runs, job names and tests it hit; the clusters of the scope are
+// replaced on every run. Test cases without a failure message are not clustered.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered while clustering failures, or nil if successful
+func ClusterFailureMessages(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	connectionId := data.Options.ConnectionId
+	fullName := data.Options.FullName
+
+	var cases []failedTestCase
+	err := db.All(&cases,
+		dal.Select("tc.job_id, tc.name, tc.classname, tc.failure_message, j.job_name, j.finished_at, j.view_url"),
+		dal.From("ci_test_cases tc"),
+		dal.Join("JOIN ci_test_jobs j ON j.connection_id = tc.connection_id AND j.job_id = tc.job_id"),
+		dal.Where("j.connection_id = ? AND j.scope_id = ? AND j.finished_at >= ? AND tc.status IN ? AND tc.failure_message IS NOT NULL",
+			connectionId, fullName, time.Now().Add(-failureClusterWindow), []string{"failed", "errored"}),
+		dal.Orderby("j.finished_at, tc.job_id"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load failed test cases for clustering")
+	}
+
+	clusters := clusterFailures(cases, connectionId, fullName)
+
+	err = db.Delete(&models.FailureCluster{}, dal.Where("connection_id = ? AND scope_id = ?", connectionId, fullName))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete previous failure clusters")
+	}
+	for _, cluster := range clusters {
+		if err := db.CreateOrUpdate(cluster); err != nil {
+			return errors.Default.Wrap(err, "failed to save failure cluster")
+		}
+	}
+	logger.Info("clustered %d failed test cases of scope %s into %d failure clusters", len(cases), fullName, len(clusters))
+	return nil
+}
+
+// clusterFailures groups failed test cases by the signature of their normalized failure message
+//
+// Parameters:
+//   - cases: Failed test cases ordered by the finish time of their job
+//   - connectionId: Connection of the scope
+//   - fullName: Scope the test cases belong to
+//
+// Returns:
+//   - []*models.FailureCluster: One cluster per signature, the ones hitting the most jobs first
+func clusterFailures(cases []failedTestCase, connectionId uint64, fullName string) []*models.FailureCluster {
+	type clusterSets struct {
+		jobs, jobNames, tests map[string]bool
+	}
+	bySignature := make(map[string]*models.FailureCluster)
+	sets := make(map[string]*clusterSets)
+	for _, c := range cases {
+		normalized := normalizeFailureMessage(c.FailureMessage)
+		if normalized == "" {
+			continue
+		}
+		signature := failureSignature(normalized)
+		cluster, ok := bySignature[signature]
+		if !ok {
+			cluster = &models.FailureCluster{
+				ConnectionId:      connectionId,
+				ScopeId:           fullName,
+				Signature:         signature,
+				NormalizedMessage: normalized,
+				FirstSeenAt:       c.FinishedAt,
+			}
+			bySignature[signature] = cluster
+			sets[signature] = &clusterSets{jobs: map[string]bool{}, jobNames: map[string]bool{}, tests: map[string]bool{}}
+		}
+		cluster.Occurrences++
+		cluster.SampleMessage = c.FailureMessage
+		cluster.SampleTestName = c.Name
+		cluster.LastSeenAt = c.FinishedAt
+		cluster.LatestJobId = c.JobId
+		cluster.LatestViewURL = c.ViewURL
+
+		s := sets[signature]
+		s.jobs[c.JobId] = true
+		s.jobNames[c.JobName] = true
+		s.tests[c.Classname+"\x00"+c.Name] = true
+		cluster.JobCount = len(s.jobs)
+		cluster.JobNameCount = len(s.jobNames)
+		cluster.TestCount = len(s.tests)
+	}
+
+	clusters := make([]*models.FailureCluster, 0, len(bySignature))
+	for _, cluster := range bySignature {
+		clusters = append(clusters, cluster)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		if clusters[i].JobCount != clusters[j].JobCount {
+			return clusters[i].JobCount > clusters[j].JobCount
+		}
+		if clusters[i].Occurrences != clusters[j].Occurrences {
+			return clusters[i].Occurrences > clusters[j].Occurrences
+		}
+		return clusters[i].Signature < clusters[j].Signature
+	})
+	return clusters
+}
+
+// normalizeFailureMessage strips the run-specific details of a failure message: UUIDs, timestamps,
+// IP addresses, durations, numbers, hex hashes and generated name suffixes become placeholders
+// and whitespace runs collapse to a single space. The result is capped at maxNormalizedMessageLength
+// bytes.
+func normalizeFailureMessage(message string) string {
+	normalized := message
+	for _, normalize := range failureMessageNormalizers {
+		normalized = normalize(normalized)
+	}
+	normalized = strings.TrimSpace(normalized)
+	if len(normalized) > maxNormalizedMessageLength {
+		normalized = normalized[:maxNormalizedMessageLength]
+		for !utf8.ValidString(normalized) {
+			normalized = normalized[:len(normalized)-1]
+		}
+	}
+	return normalized
+}
+
+// failureSignature is the hex sha256 of a normalized failure message
+func failureSignature(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
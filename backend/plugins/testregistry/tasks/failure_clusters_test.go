@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeFailureMessage(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  string
+		expected string
+	}{
+		{
+			name:     "uuid and timestamp",
+			message:  "pipelinerun 3f2b8c1e-9a4d-4e6f-8b7a-1c2d3e4f5a6b failed at 2025-01-10T08:15:30Z",
+			expected: "pipelinerun <uuid> failed at <time>",
+		},
+		{
+			name:     "commit sha and pod name",
+			message:  "image quay.io/org/app:4b825dc642cb6eb9a060e54bf8d69288fbee4904 not found on pod build-7d9f8c-x2kq9",
+			expected: "image quay.io/org/app:<hash> not found on pod build-<id>-<id>",
+		},
+		{
+			name:     "numbers, durations and addresses",
+			message:  "Timed out after 300.5s waiting for 10.0.0.12:8443 (attempt 3 of 5)",
+			expected: "Timed out after <duration> waiting for <ip> (attempt <n> of <n>)",
+		},
+		{
+			name:     "whitespace collapses",
+			message:  "  expected\n\ttrue   got false ",
+			expected: "expected true got false",
+		},
+		{
+			name:     "words are kept",
+			message:  "component-tests failed: facade decade",
+			expected: "component-tests failed: facade decade",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, normalizeFailureMessage(tt.message))
+		})
+	}
+}
+
+func TestNormalizeFailureMessageTruncates(t *testing.T) {
+	long := make([]byte, maxNormalizedMessageLength+10)
+	for i := range long {
+		long[i] = 'a'
+	}
+	// A multi-byte rune straddling the cap is dropped rather than split
+	message := string(long[:maxNormalizedMessageLength-1]) + "é" + string(long[:10])
+	normalized := normalizeFailureMessage(message)
+	assert.Len(t, normalized, maxNormalizedMessageLength-1)
+}
+
+func TestClusterFailures(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	failed := func(jobId, jobName, test, message string, hour int) failedTestCase {
+		finishedAt := base.Add(time.Duration(hour) * time.Hour)
+		return failedTestCase{
+			JobId: jobId, JobName: jobName, Name: test, Classname: "suite", FailureMessage: message,
+			FinishedAt: &finishedAt, ViewURL: "https://prow/view/" + jobId,
+		}
+	}
+
+	clusters := clusterFailures([]failedTestCase{
+		failed("1", "e2e-aws", "TestA", "timeout after 30s on pod a-1b2c3", 0),
+		failed("1", "e2e-aws", "TestB", "timeout after 45s on pod a-9z8y7", 0),
+		failed("2", "e2e-gcp", "TestA", "timeout after 12s on pod a-4d5e6", 1),
+		failed("3", "e2e-aws", "TestC", "expected 1 got 2", 2),
+		failed("4", "unit", "TestD", "   ", 3),
+	}, 1, "org/repo")
+
+	assert.Len(t, clusters, 2)
+
+	timeout := clusters[0]
+	assert.Equal(t, "timeout after <duration> on pod a-<id>", timeout.NormalizedMessage)
+	assert.Equal(t, failureSignature(timeout.NormalizedMessage), timeout.Signature)
+	assert.Equal(t, uint64(1), timeout.ConnectionId)
+	assert.Equal(t, "org/repo", timeout.ScopeId)
+	assert.Equal(t, 3, timeout.Occurrences)
+	assert.Equal(t, 2, timeout.JobCount)
+	assert.Equal(t, 2, timeout.JobNameCount)
+	assert.Equal(t, 2, timeout.TestCount)
+	assert.Equal(t, base, *timeout.FirstSeenAt)
+	assert.Equal(t, base.Add(time.Hour), *timeout.LastSeenAt)
+	assert.Equal(t, "2", timeout.LatestJobId)
+	assert.Equal(t, "https://prow/view/2", timeout.LatestViewURL)
+	assert.Equal(t, "timeout after 12s on pod a-4d5e6", timeout.SampleMessage)
+
+	mismatch := clusters[1]
+	assert.Equal(t, "expected <n> got <n>", mismatch.NormalizedMessage)
+	assert.Equal(t, 1, mismatch.JobCount)
+	assert.Equal(t, "TestC", mismatch.SampleTestName)
+}
@@ -0,0 +1,312 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	// maxFailureLogLines caps the scope config failureLogLines
+	maxFailureLogLines = 500
+	// maxBuildLogBytes is how much of the end of a build log is read, huge logs are not downloaded whole
+	maxBuildLogBytes = 4 << 20
+	// maxFailureSummaryLines caps the signature lines kept in the failure summary
+	maxFailureSummaryLines = 20
+	// maxFailureTextBytes keeps the failure summary and the log tail within a TEXT column
+	maxFailureTextBytes = 60000
+	// maxFailureLogsPerRun bounds the build logs read by one run, the newest failures first
+	maxFailureLogsPerRun = 200
+)
+
+// SummarizeProwFailuresMeta defines the metadata for the Prow failure summary subtask
+var SummarizeProwFailuresMeta = plugin.SubTaskMeta{
+	Name:             "summarizeProwFailures",
+	EntryPoint:       SummarizeProwFailures,
+	EnabledByDefault: true,
+	Description:      "Read the build-log.txt of failed Prow jobs from GCS and store its last lines and the known failure signatures found on the job, when the scope config sets failureLogLines",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta},
+}
+
+// ansiEscapePattern matches the terminal color codes of build logs
+var ansiEscapePattern = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+// FailureLogSummarizer extracts the failure summary of a build log with the scope config rules
+type FailureLogSummarizer struct {
+	tailLines  int
+	signatures []*regexp.Regexp
+}
+
+// FailureLogSummary is what a failed job's build log tells about the failure
+type FailureLogSummary struct {
+	Signature string // First failure signature pattern found in the log, empty if none
+	Summary   string // Distinct log lines matching a failure signature, in log order
+	Tail      string // Last lines of the log
+}
+
+// NewFailureLogSummarizer compiles the failure signatures of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *FailureLogSummarizer: The summarizer, or nil if failureLogLines is not set (no build log is read)
+//   - errors.Error: BadInput if failureLogLines is out of range or a signature is not a valid regex
+func NewFailureLogSummarizer(scopeConfig *models.TestRegistryScopeConfig) (*FailureLogSummarizer, errors.Error) {
+	if scopeConfig == nil || scopeConfig.FailureLogLines == 0 {
+		return nil, nil
+	}
+	if scopeConfig.FailureLogLines < 0 || scopeConfig.FailureLogLines > maxFailureLogLines {
+		return nil, errors.BadInput.New(fmt.Sprintf("invalid value for `failureLogLines`: %d, expected 0 to %d", scopeConfig.FailureLogLines, maxFailureLogLines))
+	}
+	signatures := make([]*regexp.Regexp, 0, len(scopeConfig.FailureSignatures))
+	for i, pattern := range scopeConfig.FailureSignatures {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("failureSignatures[%d]: invalid pattern %q", i, pattern))
+		}
+		signatures = append(signatures, re)
+	}
+	return &FailureLogSummarizer{tailLines: scopeConfig.FailureLogLines, signatures: signatures}, nil
+}
+
+// Summarize extracts the failure summary of the end of a build log.
+//
+// Terminal color codes and carriage returns are dropped first. The signature is the first pattern of
+// failureSignatures, in config order, matching a line; the summary lists the distinct matching lines,
+// at most maxFailureSummaryLines. Summary and tail are cut to maxFailureTextBytes, keeping the end of the tail.
+//
+// Parameters:
+//   - content: The build log, or its last bytes
+//   - truncated: true if content doesn't start at the beginning of the log, its first line is then dropped as partial
+//
+// Returns:
+//   - FailureLogSummary: The summary, empty for an empty log
+func (s *FailureLogSummarizer) Summarize(content []byte, truncated bool) FailureLogSummary {
+	text := ansiEscapePattern.ReplaceAllString(string(content), "")
+	text = strings.ReplaceAll(text, "\r", "")
+	if truncated {
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		}
+	}
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return FailureLogSummary{}
+	}
+
+	var summary FailureLogSummary
+	firstMatch := len(s.signatures)
+	seen := make(map[string]bool)
+	var matched []string
+	for _, line := range lines {
+		for i, signature := range s.signatures {
+			if !signature.MatchString(line) {
+				continue
+			}
+			if i < firstMatch {
+				firstMatch = i
+			}
+			trimmed := strings.TrimSpace(line)
+			if !seen[trimmed] && len(matched) < maxFailureSummaryLines {
+				seen[trimmed] = true
+				matched = append(matched, trimmed)
+			}
+			break
+		}
+	}
+	if firstMatch < len(s.signatures) {
+		summary.Signature = truncateUtf8(s.signatures[firstMatch].String(), 255, false)
+	}
+	summary.Summary = truncateUtf8(strings.Join(matched, "\n"), maxFailureTextBytes, false)
+
+	if len(lines) > s.tailLines {
+		lines = lines[len(lines)-s.tailLines:]
+	}
+	summary.Tail = truncateUtf8(strings.Join(lines, "\n"), maxFailureTextBytes, true)
+	return summary
+}
+
+// truncateUtf8 cuts s to at most maxBytes bytes on a rune boundary, keeping its end when keepEnd is set
+func truncateUtf8(s string, maxBytes int, keepEnd bool) string {
+	if len(s) <= maxBytes {
+		return s
+	}
+	if keepEnd {
+		s = s[len(s)-maxBytes:]
+		for !utf8.ValidString(s) {
+			s = s[1:]
+		}
+		return s
+	}
+	s = s[:maxBytes]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+	return s
+}
+
+// SummarizeProwFailures stores why the failed Prow jobs of the scope failed, read from their build-log.txt.
+//
+// Only runs when the scope config sets failureLogLines. The failed Prow jobs whose log was not looked up yet,
+// at most maxFailureLogsPerRun of them and the newest first, get the last maxBuildLogBytes of their build log
+// read from the connection's bucket, at the path their JUnit files are looked up from (see gcsBuildLogPath).
+// The FailureLogSummarizer result is saved on the job with failure_log_collected_at, so a job is read once;
+// jobs without build log are marked too. Jobs whose log can't be read are retried by the next run.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered while loading the failed jobs, or nil if successful
+func SummarizeProwFailures(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	fullName := data.Options.FullName
+	if data.FailureLogSummarizer == nil {
+		logger.Debug("failureLogLines is not set, skipping Prow failure summaries for scope %s", fullName)
+		return nil
+	}
+	if data.Connection.CITool != models.CIToolOpenshiftCI {
+		logger.Info("Connection is not Openshift CI, skipping Prow failure summaries")
+		return nil
+	}
+
+	var jobs []models.TestRegistryCIJob
+	err := db.All(&jobs,
+		dal.Where("connection_id = ? AND scope_id = ? AND job_type = ? AND result = ? AND failure_log_collected_at IS NULL",
+			data.Options.ConnectionId, fullName, "prow", "FAILURE"),
+		dal.Orderby("finished_at DESC"),
+		dal.Limit(maxFailureLogsPerRun),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load failed Prow jobs")
+	}
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	fetcher := data.BuildLogFetcherOverride
+	if fetcher == nil {
+		bucket, gcsErr := NewGCSBucketClient(taskCtx.GetContext(), data.Connection)
+		if gcsErr != nil {
+			logger.Warn(gcsErr, "failed to create GCS client, Prow failure summaries will be skipped")
+			return nil
+		}
+		defer func() { _ = bucket.Close() }()
+		fetcher = bucket
+	}
+
+	summarized := 0
+	taskCtx.SetProgress(0, len(jobs))
+	for i := range jobs {
+		job := &jobs[i]
+		content, found := fetchBuildLogTail(taskCtx, fetcher, data, job)
+		if found {
+			summary := data.FailureLogSummarizer.Summarize(content, len(content) >= maxBuildLogBytes)
+			saveFailureSummary(db, logger, job, summary)
+			summarized++
+		}
+		taskCtx.IncProgress(1)
+	}
+	logger.Info("summarized the build logs of %d of %d failed Prow jobs of scope %s", summarized, len(jobs), fullName)
+	return nil
+}
+
+// fetchBuildLogTail reads the end of the build log of a failed Prow job, at the path built from the raw Prow
+// job like the JUnit lookups of the collector. It returns false when the log could not be read and the job
+// must be retried later; a missing log is found and empty.
+func fetchBuildLogTail(taskCtx plugin.SubTaskContext, fetcher BuildLogFetcher, data *TestRegistryTaskData, job *models.TestRegistryCIJob) ([]byte, bool) {
+	logger := taskCtx.GetLogger()
+	prowJob := loadRawProwJob(taskCtx.GetDal(), logger, job)
+
+	jobType, err := determineJobTypeForGCS(job, prowJob)
+	if err != nil {
+		logger.Info("unknown trigger type, skipping build log", "trigger_type", job.TriggerType, "job_id", job.JobId)
+		return nil, true
+	}
+	var orgName, repoName, pullNumber, branch string
+	if jobType != "periodic" {
+		orgName, repoName = extractOrgRepoForGCS(prowJob, job.Organization, job.Repository, job.JobId, logger)
+	}
+	switch jobType {
+	case "presubmit":
+		pullNumber = extractPullRequestNumber(job)
+		if pullNumber == "" {
+			logger.Info("Missing PR number for presubmit job, skipping build log", "job_id", job.JobId)
+			return nil, true
+		}
+	case "postsubmit":
+		branch = postsubmitBranch(prowJob, data.Options.ScopeConfig)
+	}
+
+	content, fetchErr := fetcher.GetBuildLogTail(taskCtx.GetContext(), orgName, repoName, pullNumber, branch, job.JobId, jobType, job.JobName, maxBuildLogBytes)
+	if fetchErr != nil {
+		logger.Warn(errors.Convert(fetchErr), "failed to read build log", "job_id", job.JobId)
+		return nil, false
+	}
+	return content, true
+}
+
+// loadRawProwJob reads back the Prow job a CI job was converted from, for the refs and type of the job.
+// Without its raw record an empty Prow job is returned and the paths fall back to the CI job columns.
+func loadRawProwJob(db dal.Dal, logger log.Logger, job *models.TestRegistryCIJob) *ProwJob {
+	prowJob := &ProwJob{}
+	if job.RawDataTable == "" || job.RawDataId == 0 {
+		return prowJob
+	}
+	raw := &helper.RawData{}
+	err := db.First(raw, dal.From(job.RawDataTable), dal.Where("id = ?", job.RawDataId))
+	if err != nil {
+		logger.Debug("raw Prow job not found, building the build log path from the CI job", "job_id", job.JobId)
+		return prowJob
+	}
+	if jsonErr := json.Unmarshal(raw.Data, prowJob); jsonErr != nil {
+		logger.Debug("failed to parse raw Prow job", "error", jsonErr, "job_id", job.JobId)
+		return &ProwJob{}
+	}
+	return prowJob
+}
+
+// saveFailureSummary stores the failure summary of a job and marks its build log as looked up
+func saveFailureSummary(db dal.Dal, logger log.Logger, job *models.TestRegistryCIJob, summary FailureLogSummary) {
+	err := db.UpdateColumns(&models.TestRegistryCIJob{}, []dal.DalSet{
+		{ColumnName: "failure_signature", Value: summary.Signature},
+		{ColumnName: "failure_summary", Value: summary.Summary},
+		{ColumnName: "failure_log_tail", Value: summary.Tail},
+		{ColumnName: "failure_log_collected_at", Value: time.Now()},
+	}, dal.Where("connection_id = ? AND job_id = ?", job.ConnectionId, job.JobId))
+	if err != nil {
+		logger.Warn(err, "failed to save failure summary", "job_id", job.JobId)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFailureLogSummarizer(t *testing.T) {
+	summarizer, err := NewFailureLogSummarizer(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, summarizer)
+
+	summarizer, err = NewFailureLogSummarizer(&models.TestRegistryScopeConfig{FailureSignatures: []string{"panic:"}})
+	assert.Nil(t, err)
+	assert.Nil(t, summarizer, "signatures alone don't enable the summaries")
+
+	_, err = NewFailureLogSummarizer(&models.TestRegistryScopeConfig{FailureLogLines: 501})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errors.BadInput, err.GetType())
+	}
+
+	_, err = NewFailureLogSummarizer(&models.TestRegistryScopeConfig{FailureLogLines: 10, FailureSignatures: []string{"ok", "(unclosed"}})
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "failureSignatures[1]")
+	}
+}
+
+func TestFailureLogSummarizerSummarize(t *testing.T) {
+	summarizer, err := NewFailureLogSummarizer(&models.TestRegistryScopeConfig{
+		FailureLogLines:   3,
+		FailureSignatures: []string{`(?i)timed out waiting for`, `no space left on device`},
+	})
+	require.Nil(t, err)
+
+	log := "INFO starting\n" +
+		"write /tmp/x: no space left on device\r\n" +
+		"\x1b[31mERROR timed out waiting for the condition\x1b[0m\n" +
+		"write /tmp/x: no space left on device\n" +
+		"INFO cleaning up\n" +
+		"exit status 1\n"
+	summary := summarizer.Summarize([]byte(log), false)
+
+	assert.Equal(t, `(?i)timed out waiting for`, summary.Signature, "the first pattern of the config wins")
+	assert.Equal(t, "write /tmp/x: no space left on device\nERROR timed out waiting for the condition", summary.Summary)
+	assert.Equal(t, "write /tmp/x: no space left on device\nINFO cleaning up\nexit status 1", summary.Tail)
+
+	summary = summarizer.Summarize([]byte("device\nexit status 1"), true)
+	assert.Empty(t, summary.Signature)
+	assert.Empty(t, summary.Summary)
+	assert.Equal(t, "exit status 1", summary.Tail, "the partial first line of a truncated log is dropped")
+
+	assert.Equal(t, FailureLogSummary{}, summarizer.Summarize(nil, false))
+}
+
+func TestTruncateUtf8(t *testing.T) {
+	assert.Equal(t, "abc", truncateUtf8("abc", 5, false))
+	assert.Equal(t, "a", truncateUtf8("aé", 2, false))
+	assert.Equal(t, "b", truncateUtf8("éb", 2, true))
+	assert.Equal(t, strings.Repeat("x", 4), truncateUtf8(strings.Repeat("x", 10), 4, true))
+}
+
+func TestSummarizeProwFailures(t *testing.T) {
+	summarizer, err := NewFailureLogSummarizer(&models.TestRegistryScopeConfig{FailureLogLines: 2, FailureSignatures: []string{"panic:"}})
+	require.Nil(t, err)
+	pullNumber := 42
+	jobs := []models.TestRegistryCIJob{
+		{ConnectionId: 1, JobId: "101", JobName: "pull-ci-e2e", TriggerType: "pull_request", PullRequestNumber: &pullNumber,
+			Organization: "konflux-ci", Repository: "build-service"},
+		{ConnectionId: 1, JobId: "102", JobName: "periodic-e2e", TriggerType: "periodic"},
+		{ConnectionId: 1, JobId: "103", JobName: "branch-ci-e2e", TriggerType: "push", Organization: "konflux-ci", Repository: "build-service"},
+	}
+	// The raw record of job 103 carries the base ref the postsubmit path needs
+	jobs[2].RawDataTable = "_raw_cicd_test_jobs"
+	jobs[2].RawDataId = 7
+	rawJob, _ := json.Marshal(ProwJob{Spec: ProwJobSpec{Type: "postsubmit", Refs: &ProwJobRefs{Org: "konflux-ci", Repo: "build-service", BaseRef: "release-1.4"}}})
+
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDal := new(mockdal.Dal)
+	mockCtx.On("GetLogger").Return(newMockLogger())
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	mockCtx.On("SetProgress", mock.Anything, mock.Anything).Maybe()
+	mockCtx.On("IncProgress", mock.Anything).Maybe()
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]models.TestRegistryCIJob) = jobs
+	}).Return(nil)
+	mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*helper.RawData).Data = rawJob
+	}).Return(nil)
+	var saved []string
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		set := args.Get(1).([]dal.DalSet)
+		saved = append(saved, fmt.Sprintf("%v|%v|%v", set[0].Value, set[1].Value, set[2].Value))
+	}).Return(nil)
+
+	fetcher := new(mockBuildLogFetcher)
+	fetcher.On("GetBuildLogTail", mock.Anything, "konflux-ci", "build-service", "42", "", "101", "presubmit", "pull-ci-e2e", int64(maxBuildLogBytes)).
+		Return([]byte("step 1\npanic: nil map\nexit status 2\n"), nil)
+	fetcher.On("GetBuildLogTail", mock.Anything, "", "", "", "", "102", "periodic", "periodic-e2e", int64(maxBuildLogBytes)).
+		Return(nil, fmt.Errorf("connection reset"))
+	fetcher.On("GetBuildLogTail", mock.Anything, "konflux-ci", "build-service", "", "release-1.4", "103", "postsubmit", "branch-ci-e2e", int64(maxBuildLogBytes)).
+		Return(nil, nil)
+
+	mockCtx.On("GetData").Return(&TestRegistryTaskData{
+		Options: &TestRegistryOptions{ConnectionId: 1, FullName: "build-service",
+			ScopeConfig: &models.TestRegistryScopeConfig{DefaultBranch: "main"}},
+		Connection:              &models.TestRegistryConnection{CITool: models.CIToolOpenshiftCI},
+		FailureLogSummarizer:    summarizer,
+		BuildLogFetcherOverride: fetcher,
+	})
+
+	assert.Nil(t, SummarizeProwFailures(mockCtx))
+	fetcher.AssertExpectations(t)
+	// The unreadable log of job 102 is retried later, the missing log of job 103 is not
+	assert.Equal(t, []string{"panic:|panic: nil map|panic: nil map\nexit status 2", "||"}, saved)
+}
+
+func TestSummarizeProwFailures_Disabled(t *testing.T) {
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDal := new(mockdal.Dal)
+	mockCtx.On("GetLogger").Return(newMockLogger())
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetData").Return(&TestRegistryTaskData{
+		Options:    &TestRegistryOptions{ConnectionId: 1, FullName: "build-service"},
+		Connection: &models.TestRegistryConnection{CITool: models.CIToolOpenshiftCI},
+	})
+
+	assert.Nil(t, SummarizeProwFailures(mockCtx))
+	mockDal.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+}
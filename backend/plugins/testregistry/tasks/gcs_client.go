@@ -19,34 +19,166 @@ package tasks
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"path"
 	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/helpers/gcshelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
 	"google.golang.org/api/iterator"
 
 	"cloud.google.com/go/storage"
 )
 
 // GCSBucket wraps gcshelper.GCSBucket and adds testregistry-specific helpers
-// for fetching JUnit XML artifacts from the Openshift CI public bucket.
+// for fetching JUnit XML artifacts from the Openshift CI public bucket, or the
+// bucket configured on the connection.
 type GCSBucket struct {
 	*gcshelper.GCSBucket
 	// bkt is needed for the JUnit artifact listing which uses the raw GCS API.
 	bkt *storage.BucketHandle
+	// prefixTemplate is the connection's gcsPrefixTemplate, empty for the Openshift CI layout.
+	prefixTemplate string
 }
 
-// NewGCSBucketClient creates a new GCS client for the Openshift CI bucket.
-func NewGCSBucketClient(ctx context.Context) (*GCSBucket, errors.Error) {
-	inner, err := gcshelper.New(ctx, gcshelper.OpenshiftCIBucketName)
+// NewGCSBucketClient creates a new GCS client for the JUnit results bucket of the connection:
+// the Openshift CI bucket read anonymously unless the connection sets gcsBucket, gcsPrefixTemplate
+// or gcsCredentials (a service account key). A nil connection reads the Openshift CI bucket.
+func NewGCSBucketClient(ctx context.Context, connection *models.TestRegistryConnection) (*GCSBucket, errors.Error) {
+	bucketName := gcshelper.OpenshiftCIBucketName
+	var credentials, prefixTemplate string
+	if connection != nil {
+		if name := strings.TrimSpace(connection.GCSBucket); name != "" {
+			bucketName = name
+		}
+		credentials = strings.TrimSpace(connection.GCSCredentials)
+		prefixTemplate = strings.TrimSpace(connection.GCSPrefixTemplate)
+	}
+
+	var inner *gcshelper.GCSBucket
+	var err errors.Error
+	if credentials != "" {
+		// Only service account keys: other credential types, e.g. external_account, make the client read
+		// local files or fetch URLs named in the JSON
+		if err := ValidateGCSCredentials(credentials); err != nil {
+			return nil, err
+		}
+		inner, err = gcshelper.NewWithCredentials(ctx, bucketName, []byte(credentials))
+	} else {
+		inner, err = gcshelper.New(ctx, bucketName)
+	}
 	if err != nil {
 		return nil, err
 	}
 	// Access the underlying bucket handle via a thin accessor so that
 	// GetJobJunitContent can build its own object iterator.
 	bkt := inner.BucketHandle()
-	return &GCSBucket{GCSBucket: inner, bkt: bkt}, nil
+	return &GCSBucket{GCSBucket: inner, bkt: bkt, prefixTemplate: prefixTemplate}, nil
+}
+
+// gcsPrefixPlaceholders are the placeholders a gcsPrefixTemplate may use
+var gcsPrefixPlaceholders = []string{"{default}", "{org}", "{repo}", "{pull}", "{branch}", "{job}", "{build}", "{type}"}
+
+var gcsPlaceholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+// ValidateGCSPrefixTemplate checks the placeholders of a gcsPrefixTemplate. Every job needs its own
+// directory, so the template must use {build} or {default}.
+//
+// Parameters:
+//   - template: The template, empty for the Openshift CI layout
+//
+// Returns:
+//   - errors.Error: BadInput naming the unknown placeholder or the missing build, nil if valid
+func ValidateGCSPrefixTemplate(template string) errors.Error {
+	template = strings.TrimSpace(template)
+	if template == "" {
+		return nil
+	}
+	for _, placeholder := range gcsPlaceholderPattern.FindAllString(template, -1) {
+		if !slices.Contains(gcsPrefixPlaceholders, placeholder) {
+			return errors.BadInput.New(fmt.Sprintf("unknown placeholder %s in gcsPrefixTemplate, must be one of %s", placeholder, strings.Join(gcsPrefixPlaceholders, ", ")))
+		}
+	}
+	if !strings.Contains(template, "{build}") && !strings.Contains(template, "{default}") {
+		return errors.BadInput.New("gcsPrefixTemplate must contain {build} or {default} so every job has its own directory")
+	}
+	return nil
+}
+
+// ValidateGCSCredentials checks that gcsCredentials is a service account key
+//
+// Parameters:
+//   - credentials: The JSON key file content of the service account
+//
+// Returns:
+//   - errors.Error: BadInput if it isn't a service account JSON key, nil if valid
+func ValidateGCSCredentials(credentials string) errors.Error {
+	var key struct {
+		Type        string `json:"type"`
+		ClientEmail string `json:"client_email"`
+		PrivateKey  string `json:"private_key"`
+	}
+	if err := json.Unmarshal([]byte(credentials), &key); err != nil {
+		return errors.BadInput.Wrap(err, "gcsCredentials must be the JSON key of a service account")
+	}
+	if key.Type != "service_account" || key.ClientEmail == "" || key.PrivateKey == "" {
+		return errors.BadInput.New("gcsCredentials must be a service account key with type, client_email and private_key")
+	}
+	return nil
+}
+
+// gcsArtifactsPrefix returns the artifacts directory of a job in the bucket, expanding the template
+// of the connection or, without one, following the Openshift CI layout
+func gcsArtifactsPrefix(template, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string) string {
+	defaultPrefix := junitArtifactsPrefix(orgName, repoName, pullNumber, jobId, jobType, jobName)
+	if template == "" {
+		return defaultPrefix
+	}
+	replacer := strings.NewReplacer(
+		"{default}", defaultPrefix,
+		"{org}", orgName,
+		"{repo}", repoName,
+		"{pull}", pullNumber,
+		"{branch}", branch,
+		"{job}", jobName,
+		"{build}", jobId,
+		"{type}", jobType,
+	)
+	return strings.TrimSuffix(replacer.Replace(template), "/")
+}
+
+// CheckAccess lists one object of the bucket, failing when it doesn't exist or the credentials can't read it
+func (b *GCSBucket) CheckAccess(ctx context.Context) error {
+	it := b.bkt.Objects(ctx, &storage.Query{Delimiter: "/"})
+	if _, err := it.Next(); err != nil && err != iterator.Done {
+		return err
+	}
+	return nil
+}
+
+// ListObjectNames returns the names of the objects directly below prefix, leaving out
+// the deeper "directories".
+func (b *GCSBucket) ListObjectNames(ctx context.Context, prefix string) ([]string, error) {
+	var names []string
+	it := b.bkt.Objects(ctx, &storage.Query{Prefix: prefix, Delimiter: "/"})
+	for {
+		obj, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return names, fmt.Errorf("GCS listing failed for %s: %w", prefix, err)
+		}
+		if obj.Name != "" {
+			names = append(names, obj.Name)
+		}
+	}
+	return names, nil
 }
 
 // maxJUnitFilesPerJob limits the number of JUnit files collected per job to
@@ -59,25 +191,24 @@ type JUnitFile struct {
 	Path    string
 }
 
+// junitArtifactsPrefix returns the path of a Prow job's artifacts directory
+// within the Openshift CI bucket. Presubmit jobs live under pr-logs/pull,
+// every other job type under logs.
+func junitArtifactsPrefix(orgName, repoName, pullNumber, jobId, jobType, jobName string) string {
+	if jobType == "presubmit" {
+		return fmt.Sprintf("pr-logs/pull/%s_%s/%s/%s/%s/artifacts", orgName, repoName, pullNumber, jobName, jobId)
+	}
+	return fmt.Sprintf("logs/%s/%s/artifacts", jobName, jobId)
+}
+
 // GetJobJunitContent retrieves all matching JUnit XML files from GCS for a
-// specific job. It iterates through all objects in the artifact directory and
+// specific job, below the artifacts directory of the job (see gcsArtifactsPrefix). It iterates through all objects in the artifact directory and
 // returns every file matching the regex pattern.
 //
 // Based on the quality-dashboard implementation:
 // https://github.com/konflux-ci/quality-dashboard/blob/main/backend/pkg/connectors/gcs/gcs_authentication.go
-func (b *GCSBucket) GetJobJunitContent(ctx context.Context, orgName, repoName, pullNumber, jobId, jobType, jobName string, fileName *regexp.Regexp) ([]JUnitFile, error) {
-	query := &storage.Query{}
-
-	switch jobType {
-	case "presubmit":
-		query.Prefix = fmt.Sprintf("pr-logs/pull/%s_%s/%s/%s/%s/artifacts", orgName, repoName, pullNumber, jobName, jobId)
-	case "postsubmit":
-		query.Prefix = fmt.Sprintf("logs/%s/%s/artifacts", jobName, jobId)
-	case "periodic":
-		query.Prefix = fmt.Sprintf("logs/%s/%s/artifacts", jobName, jobId)
-	default:
-		query.Prefix = fmt.Sprintf("logs/%s/%s/artifacts", jobName, jobId)
-	}
+func (b *GCSBucket) GetJobJunitContent(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, fileName *regexp.Regexp) ([]JUnitFile, error) {
+	query := &storage.Query{Prefix: gcsArtifactsPrefix(b.prefixTemplate, orgName, repoName, pullNumber, branch, jobId, jobType, jobName)}
 
 	var results []JUnitFile
 
@@ -105,3 +236,31 @@ func (b *GCSBucket) GetJobJunitContent(ctx context.Context, orgName, repoName, p
 
 	return results, nil
 }
+
+// buildLogName is the console output Prow stores for every job, in the job directory above its artifacts
+const buildLogName = "build-log.txt"
+
+// gcsBuildLogPath returns the path of the build log of a job: next to its artifacts directory (see
+// gcsArtifactsPrefix), or inside the directory a gcsPrefixTemplate expands to when it isn't an artifacts directory
+func gcsBuildLogPath(template, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string) string {
+	prefix := gcsArtifactsPrefix(template, orgName, repoName, pullNumber, branch, jobId, jobType, jobName)
+	if path.Base(prefix) == "artifacts" {
+		prefix = path.Dir(prefix)
+	}
+	return prefix + "/" + buildLogName
+}
+
+// GetBuildLogTail reads the last maxBytes bytes of the build log of a Prow job, the whole log when it is
+// smaller. A job without build log returns no content and no error.
+func (b *GCSBucket) GetBuildLogTail(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, maxBytes int64) ([]byte, error) {
+	logPath := gcsBuildLogPath(b.prefixTemplate, orgName, repoName, pullNumber, branch, jobId, jobType, jobName)
+	reader, err := b.bkt.Object(logPath).NewRangeReader(ctx, -maxBytes, -1)
+	if err == storage.ErrObjectNotExist {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GCS read failed for %s: %w", logPath, err)
+	}
+	defer reader.Close()
+	return io.ReadAll(io.LimitReader(reader, maxBytes))
+}
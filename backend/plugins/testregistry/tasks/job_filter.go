@@ -0,0 +1,148 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// validTriggerTypes are the trigger types the collectors set on CI jobs
+var validTriggerTypes = map[string]bool{
+	"pull_request": true,
+	"push":         true,
+	"periodic":     true,
+}
+
+// JobFilter selects the CI jobs collected for a scope by job name and trigger type
+type JobFilter struct {
+	included     []*regexp.Regexp
+	excluded     []*regexp.Regexp
+	triggerTypes map[string]bool
+}
+
+// NewJobFilter compiles the scenario and trigger type filters of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *JobFilter: The filter, or nil if none is configured (every job is collected)
+//   - errors.Error: BadInput if a pattern is not a valid regex or a trigger type is unknown
+func NewJobFilter(scopeConfig *models.TestRegistryScopeConfig) (*JobFilter, errors.Error) {
+	if scopeConfig == nil || len(scopeConfig.IncludedScenarios)+len(scopeConfig.ExcludedScenarios)+len(scopeConfig.TriggerTypes) == 0 {
+		return nil, nil
+	}
+
+	filter := &JobFilter{}
+	var err errors.Error
+	if filter.included, err = compileScenarioPatterns("includedScenarios", scopeConfig.IncludedScenarios); err != nil {
+		return nil, err
+	}
+	if filter.excluded, err = compileScenarioPatterns("excludedScenarios", scopeConfig.ExcludedScenarios); err != nil {
+		return nil, err
+	}
+	for i, triggerType := range scopeConfig.TriggerTypes {
+		if !validTriggerTypes[triggerType] {
+			return nil, errors.BadInput.New(fmt.Sprintf("triggerTypes[%d]: unknown trigger type %q, expected pull_request, push or periodic", i, triggerType))
+		}
+		if filter.triggerTypes == nil {
+			filter.triggerTypes = make(map[string]bool)
+		}
+		filter.triggerTypes[triggerType] = true
+	}
+	return filter, nil
+}
+
+// compileScenarioPatterns compiles the job name patterns of one scope config field
+func compileScenarioPatterns(field string, patterns []string) ([]*regexp.Regexp, errors.Error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for i, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("%s[%d]: invalid pattern %q", field, i, pattern))
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+// Allows reports whether a CI job is collected
+//
+// A job is collected when its trigger type is one of the configured ones, its name matches
+// an included pattern and it matches no excluded pattern. Each check passes when nothing is
+// configured for it.
+//
+// Parameters:
+//   - ciJob: The converted CI job, with its job name and trigger type set
+//
+// Returns:
+//   - bool: true if the job is collected
+func (f *JobFilter) Allows(ciJob *models.TestRegistryCIJob) bool {
+	if f == nil {
+		return true
+	}
+	if f.triggerTypes != nil && !f.triggerTypes[ciJob.TriggerType] {
+		return false
+	}
+	if len(f.included) > 0 && !matchesAnyPattern(f.included, ciJob.JobName) {
+		return false
+	}
+	return !matchesAnyPattern(f.excluded, ciJob.JobName)
+}
+
+// matchesAnyPattern reports whether one of the patterns matches s
+func matchesAnyPattern(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopeSyncPolicy applies the default lookback window of the scope config to the sync policy of the run
+//
+// Parameters:
+//   - syncPolicy: The sync policy of the run (may be nil)
+//   - scopeConfig: The scope config of the task (may be nil)
+//   - now: The current time
+//
+// Returns:
+//   - *coreModels.SyncPolicy: syncPolicy, or a copy whose timeAfter is defaultLookbackDays before now
+//     when the scope config sets a lookback and the sync policy has no timeAfter
+func scopeSyncPolicy(syncPolicy *coreModels.SyncPolicy, scopeConfig *models.TestRegistryScopeConfig, now time.Time) *coreModels.SyncPolicy {
+	if scopeConfig == nil || scopeConfig.DefaultLookbackDays <= 0 {
+		return syncPolicy
+	}
+	if syncPolicy != nil && syncPolicy.TimeAfter != nil {
+		return syncPolicy
+	}
+	policy := coreModels.SyncPolicy{}
+	if syncPolicy != nil {
+		policy = *syncPolicy
+	}
+	timeAfter := now.AddDate(0, 0, -scopeConfig.DefaultLookbackDays)
+	policy.TimeAfter = &timeAfter
+	return &policy
+}
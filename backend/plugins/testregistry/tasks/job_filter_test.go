@@ -0,0 +1,108 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJobFilter(t *testing.T) {
+	filter, err := NewJobFilter(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+
+	filter, err = NewJobFilter(&models.TestRegistryScopeConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, filter)
+
+	_, err = NewJobFilter(&models.TestRegistryScopeConfig{IncludedScenarios: []string{"("}})
+	assert.NotNil(t, err)
+
+	_, err = NewJobFilter(&models.TestRegistryScopeConfig{ExcludedScenarios: []string{"("}})
+	assert.NotNil(t, err)
+
+	_, err = NewJobFilter(&models.TestRegistryScopeConfig{TriggerTypes: []string{"nightly"}})
+	assert.NotNil(t, err)
+}
+
+func TestJobFilterAllows(t *testing.T) {
+	filter, err := NewJobFilter(&models.TestRegistryScopeConfig{
+		IncludedScenarios: []string{"^konflux-e2e", "^integration-"},
+		ExcludedScenarios: []string{"-flaky$"},
+		TriggerTypes:      []string{"pull_request", "push"},
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		jobName     string
+		triggerType string
+		allowed     bool
+	}{
+		{"konflux-e2e-tests", "pull_request", true},
+		{"integration-upgrade", "push", true},
+		{"konflux-e2e-tests", "periodic", false},
+		{"unit-tests", "pull_request", false},
+		{"konflux-e2e-flaky", "pull_request", false},
+	}
+	for _, tt := range tests {
+		ciJob := &models.TestRegistryCIJob{JobName: tt.jobName, TriggerType: tt.triggerType}
+		assert.Equal(t, tt.allowed, filter.Allows(ciJob), "%s (%s)", tt.jobName, tt.triggerType)
+	}
+
+	// Excluded patterns alone keep every other job
+	filter, err = NewJobFilter(&models.TestRegistryScopeConfig{ExcludedScenarios: []string{"-flaky$"}})
+	assert.Nil(t, err)
+	assert.True(t, filter.Allows(&models.TestRegistryCIJob{JobName: "unit-tests", TriggerType: "periodic"}))
+	assert.False(t, filter.Allows(&models.TestRegistryCIJob{JobName: "e2e-flaky"}))
+
+	// A nil filter collects every job
+	var none *JobFilter
+	assert.True(t, none.Allows(&models.TestRegistryCIJob{JobName: "anything"}))
+}
+
+func TestScopeSyncPolicy(t *testing.T) {
+	now := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC)
+	lookback := &models.TestRegistryScopeConfig{DefaultLookbackDays: 30}
+
+	// No lookback keeps the sync policy of the run
+	assert.Nil(t, scopeSyncPolicy(nil, nil, now))
+	assert.Nil(t, scopeSyncPolicy(nil, &models.TestRegistryScopeConfig{}, now))
+
+	// The lookback applies when the run has no timeAfter
+	policy := scopeSyncPolicy(nil, lookback, now)
+	if assert.NotNil(t, policy) && assert.NotNil(t, policy.TimeAfter) {
+		assert.Equal(t, now.AddDate(0, 0, -30), *policy.TimeAfter)
+	}
+
+	fullSync := &coreModels.SyncPolicy{}
+	fullSync.FullSync = true
+	policy = scopeSyncPolicy(fullSync, lookback, now)
+	assert.True(t, policy.FullSync)
+	assert.Equal(t, now.AddDate(0, 0, -30), *policy.TimeAfter)
+	assert.Nil(t, fullSync.TimeAfter, "the sync policy of the run is not modified")
+
+	// A blueprint timeAfter wins over the lookback
+	timeAfter := now.AddDate(-1, 0, 0)
+	withTimeAfter := &coreModels.SyncPolicy{TimeAfter: &timeAfter}
+	assert.Same(t, withTimeAfter, scopeSyncPolicy(withTimeAfter, lookback, now))
+}
@@ -0,0 +1,104 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// jobNameRule is a compiled scope config job name rule
+type jobNameRule struct {
+	pattern *regexp.Regexp
+	baseJob string
+	variant string
+}
+
+// JobNameNormalizer splits job names into a base job name and a variant
+type JobNameNormalizer struct {
+	rules []jobNameRule
+}
+
+// NewJobNameNormalizer compiles the job name rules of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *JobNameNormalizer: The normalizer, or nil if no rules are configured
+//   - errors.Error: BadInput if a pattern is not a valid regex
+func NewJobNameNormalizer(scopeConfig *models.TestRegistryScopeConfig) (*JobNameNormalizer, errors.Error) {
+	if scopeConfig == nil || len(scopeConfig.JobNameRules) == 0 {
+		return nil, nil
+	}
+
+	normalizer := &JobNameNormalizer{}
+	for i, rule := range scopeConfig.JobNameRules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("jobNameRules[%d]: invalid pattern %q", i, rule.Pattern))
+		}
+		compiled := jobNameRule{pattern: pattern, baseJob: rule.BaseJob, variant: rule.Variant}
+		if compiled.baseJob == "" {
+			compiled.baseJob = "$1"
+		}
+		if compiled.variant == "" {
+			compiled.variant = "$2"
+		}
+		normalizer.rules = append(normalizer.rules, compiled)
+	}
+	return normalizer, nil
+}
+
+// Normalize returns the base job name and the variant of a job
+//
+// The first rule matching the job name wins, e.g. pattern `^(.+?)-(arm64|ocp4\.\d+)$` splits
+// "e2e-tests-arm64" into "e2e-tests" and "arm64". A rule whose base job expands to an empty
+// string is skipped.
+//
+// Parameters:
+//   - jobName: Name of the job
+//
+// Returns:
+//   - string: The base job name, jobName if no rule matches
+//   - string: The variant, empty if no rule matches
+func (n *JobNameNormalizer) Normalize(jobName string) (string, string) {
+	if n == nil {
+		return jobName, ""
+	}
+	for _, rule := range n.rules {
+		match := rule.pattern.FindStringSubmatchIndex(jobName)
+		if match == nil {
+			continue
+		}
+		baseJob := string(rule.pattern.ExpandString(nil, rule.baseJob, jobName, match))
+		if baseJob == "" {
+			continue
+		}
+		return baseJob, string(rule.pattern.ExpandString(nil, rule.variant, jobName, match))
+	}
+	return jobName, ""
+}
+
+// apply sets the base job name and variant of a CI job from its job name
+func (n *JobNameNormalizer) apply(ciJob *models.TestRegistryCIJob) {
+	ciJob.BaseJobName, ciJob.JobVariant = n.Normalize(ciJob.JobName)
+}
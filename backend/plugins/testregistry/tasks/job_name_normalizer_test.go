@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJobNameNormalizer(t *testing.T) {
+	normalizer, err := NewJobNameNormalizer(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, normalizer)
+
+	normalizer, err = NewJobNameNormalizer(&models.TestRegistryScopeConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, normalizer)
+
+	_, err = NewJobNameNormalizer(&models.TestRegistryScopeConfig{
+		JobNameRules: []models.JobNameRule{{Pattern: "("}},
+	})
+	assert.NotNil(t, err)
+}
+
+func TestJobNameNormalizerNormalize(t *testing.T) {
+	normalizer, err := NewJobNameNormalizer(&models.TestRegistryScopeConfig{
+		JobNameRules: []models.JobNameRule{
+			{Pattern: `^(.+?)-(arm64|ocp4\.\d+)$`},
+			{Pattern: `^(?P<base>.+)-(?P<platform>aws|gcp)-(?P<version>\d+)$`, BaseJob: "${base}", Variant: "${platform}-${version}"},
+			{Pattern: `^-(.*)$`, BaseJob: "$2"},
+		},
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		jobName string
+		baseJob string
+		variant string
+	}{
+		{"e2e-tests-arm64", "e2e-tests", "arm64"},
+		{"e2e-tests-ocp4.15", "e2e-tests", "ocp4.15"},
+		{"upgrade-aws-2", "upgrade", "aws-2"},
+		{"e2e-tests", "e2e-tests", ""},
+		{"-oddly-named", "-oddly-named", ""}, // a rule expanding to an empty base job is skipped
+	}
+	for _, tt := range tests {
+		baseJob, variant := normalizer.Normalize(tt.jobName)
+		assert.Equal(t, tt.baseJob, baseJob, tt.jobName)
+		assert.Equal(t, tt.variant, variant, tt.jobName)
+	}
+
+	var nilNormalizer *JobNameNormalizer
+	baseJob, variant := nilNormalizer.Normalize("e2e-tests-arm64")
+	assert.Equal(t, "e2e-tests-arm64", baseJob)
+	assert.Empty(t, variant)
+
+	ciJob := &models.TestRegistryCIJob{JobName: "e2e-tests-arm64"}
+	normalizer.apply(ciJob)
+	assert.Equal(t, "e2e-tests", ciJob.BaseJobName)
+	assert.Equal(t, "arm64", ciJob.JobVariant)
+}
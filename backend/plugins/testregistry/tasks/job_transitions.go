@@ -0,0 +1,212 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// activeJobWindow is how recently a job must have finished a run to count as present.
+// A job of the previous snapshot without a run in this window is reported as removed.
+const activeJobWindow = 7 * 24 * time.Hour
+
+// DiffJobOutcomesMeta defines the metadata for the job outcome diff subtask
+var DiffJobOutcomesMeta = plugin.SubTaskMeta{
+	Name:             "diffJobOutcomes",
+	EntryPoint:       DiffJobOutcomes,
+	EnabledByDefault: true,
+	Description:      "Compare the latest outcome of each job with the previous pipeline run and record pass/fail, new and removed job transitions",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta, &CollectTektonJobsMeta, &CollectKubernetesPipelineRunsMeta},
+}
+
+// outcomeJob is the subset of ci_test_jobs needed to find the latest outcome of each job
+type outcomeJob struct {
+	JobId      string
+	JobName    string
+	Result     string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	ViewURL    string `gorm:"column:view_url"`
+}
+
+// DiffJobOutcomes records what changed in the scope's CI since the previous pipeline run.
+//
+// The latest SUCCESS or FAILURE run of every job (Prow job name, Tekton scenario) that finished
+// within activeJobWindow forms the current snapshot. It is compared with the snapshot stored by
+// the previous run in _tool_testregistry_job_outcomes, and every difference is saved as a
+// transition: pass_to_fail, fail_to_pass, new_job or removed_job. The current snapshot then
+// replaces the stored one. The first run of a scope only stores the snapshot, so enabling the
+// subtask doesn't report every job as new. Presubmit runs are ignored, as they reflect a PR
+// rather than the state of the branch, and aborted runs don't change a job's outcome.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered while comparing outcomes, or nil if successful
+func DiffJobOutcomes(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	connectionId := data.Options.ConnectionId
+	fullName := data.Options.FullName
+	now := time.Now()
+
+	var jobs []outcomeJob
+	err := db.All(&jobs,
+		dal.Select("job_id, job_name, result, started_at, finished_at, view_url"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND trigger_type != ? AND result IN (?) AND finished_at >= ?",
+			connectionId, fullName, "pull_request", []string{"SUCCESS", "FAILURE"}, now.Add(-activeJobWindow)),
+		dal.Orderby("job_name, started_at, finished_at"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load CI jobs for outcome diff")
+	}
+
+	var previous []models.JobOutcome
+	err = db.All(&previous, dal.Where("connection_id = ? AND scope_id = ?", connectionId, fullName))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load previous job outcomes")
+	}
+
+	current := latestJobOutcomes(jobs, connectionId, fullName)
+	if len(previous) > 0 {
+		transitions := diffJobOutcomes(previous, current, now)
+		for _, transition := range transitions {
+			if err := db.CreateOrUpdate(transition); err != nil {
+				return errors.Default.Wrap(err, "failed to save job transition")
+			}
+		}
+		logger.Info("recorded %d job outcome transitions for scope %s", len(transitions), fullName)
+	} else {
+		logger.Info("no previous job outcomes for scope %s, storing the first snapshot", fullName)
+	}
+
+	err = db.Delete(&models.JobOutcome{}, dal.Where("connection_id = ? AND scope_id = ?", connectionId, fullName))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to delete previous job outcomes")
+	}
+	for _, outcome := range current {
+		if err := db.CreateOrUpdate(outcome); err != nil {
+			return errors.Default.Wrap(err, "failed to save job outcome")
+		}
+	}
+	return nil
+}
+
+// latestJobOutcomes keeps the last run of each job
+//
+// Parameters:
+//   - jobs: Finished runs ordered by job name, then by start time
+//   - connectionId: Connection of the scope
+//   - fullName: Scope the runs belong to
+//
+// Returns:
+//   - []*models.JobOutcome: One outcome per job name, sorted by job name
+func latestJobOutcomes(jobs []outcomeJob, connectionId uint64, fullName string) []*models.JobOutcome {
+	var outcomes []*models.JobOutcome
+	for _, job := range jobs {
+		outcome := &models.JobOutcome{
+			ConnectionId: connectionId,
+			ScopeId:      fullName,
+			JobName:      job.JobName,
+			JobId:        job.JobId,
+			Result:       job.Result,
+			FinishedAt:   job.FinishedAt,
+			ViewURL:      job.ViewURL,
+		}
+		if n := len(outcomes); n > 0 && outcomes[n-1].JobName == job.JobName {
+			outcomes[n-1] = outcome
+			continue
+		}
+		outcomes = append(outcomes, outcome)
+	}
+	return outcomes
+}
+
+// diffJobOutcomes compares two snapshots of the same scope
+//
+// Parameters:
+//   - previous: Outcomes stored by the previous pipeline run
+//   - current: Outcomes of this run
+//   - detectedAt: Time of this run, shared by all returned transitions
+//
+// Returns:
+//   - []*models.JobTransition: One transition per changed job, sorted by job name
+func diffJobOutcomes(previous []models.JobOutcome, current []*models.JobOutcome, detectedAt time.Time) []*models.JobTransition {
+	previousByName := make(map[string]*models.JobOutcome, len(previous))
+	for i := range previous {
+		previousByName[previous[i].JobName] = &previous[i]
+	}
+
+	var transitions []*models.JobTransition
+	for _, outcome := range current {
+		transition := &models.JobTransition{
+			ConnectionId:      outcome.ConnectionId,
+			ScopeId:           outcome.ScopeId,
+			JobName:           outcome.JobName,
+			DetectedAt:        detectedAt,
+			CurrentJobId:      outcome.JobId,
+			CurrentResult:     outcome.Result,
+			CurrentFinishedAt: outcome.FinishedAt,
+			ViewURL:           outcome.ViewURL,
+		}
+		before, existed := previousByName[outcome.JobName]
+		delete(previousByName, outcome.JobName)
+		switch {
+		case !existed:
+			transition.Transition = models.TransitionNewJob
+		case before.Result == "SUCCESS" && outcome.Result == "FAILURE":
+			transition.Transition = models.TransitionPassToFail
+		case before.Result == "FAILURE" && outcome.Result == "SUCCESS":
+			transition.Transition = models.TransitionFailToPass
+		default:
+			continue
+		}
+		if existed {
+			transition.PreviousJobId = before.JobId
+			transition.PreviousResult = before.Result
+		}
+		transitions = append(transitions, transition)
+	}
+
+	for _, before := range previousByName {
+		transitions = append(transitions, &models.JobTransition{
+			ConnectionId:   before.ConnectionId,
+			ScopeId:        before.ScopeId,
+			JobName:        before.JobName,
+			DetectedAt:     detectedAt,
+			Transition:     models.TransitionRemovedJob,
+			PreviousJobId:  before.JobId,
+			PreviousResult: before.Result,
+			ViewURL:        before.ViewURL,
+		})
+	}
+
+	sort.Slice(transitions, func(i, j int) bool { return transitions[i].JobName < transitions[j].JobName })
+	return transitions
+}
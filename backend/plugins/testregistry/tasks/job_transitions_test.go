@@ -0,0 +1,95 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatestJobOutcomes(t *testing.T) {
+	base := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	job := func(id, name, result string, hour int) outcomeJob {
+		finishedAt := base.Add(time.Duration(hour) * time.Hour)
+		return outcomeJob{JobId: id, JobName: name, Result: result, FinishedAt: &finishedAt}
+	}
+
+	outcomes := latestJobOutcomes([]outcomeJob{
+		job("1", "e2e", "SUCCESS", 0),
+		job("2", "e2e", "FAILURE", 1),
+		job("3", "unit", "SUCCESS", 0),
+	}, 1, "org/repo")
+
+	assert.Len(t, outcomes, 2)
+	assert.Equal(t, "e2e", outcomes[0].JobName)
+	assert.Equal(t, "2", outcomes[0].JobId)
+	assert.Equal(t, "FAILURE", outcomes[0].Result)
+	assert.Equal(t, "org/repo", outcomes[0].ScopeId)
+	assert.Equal(t, "3", outcomes[1].JobId)
+}
+
+func TestDiffJobOutcomes(t *testing.T) {
+	detectedAt := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+	outcome := func(name, id, result string) models.JobOutcome {
+		return models.JobOutcome{ConnectionId: 1, ScopeId: "org/repo", JobName: name, JobId: id, Result: result}
+	}
+	current := func(outcomes ...models.JobOutcome) []*models.JobOutcome {
+		result := make([]*models.JobOutcome, len(outcomes))
+		for i := range outcomes {
+			result[i] = &outcomes[i]
+		}
+		return result
+	}
+
+	previous := []models.JobOutcome{
+		outcome("broken", "1", "SUCCESS"),
+		outcome("fixed", "2", "FAILURE"),
+		outcome("stable", "3", "SUCCESS"),
+		outcome("still-failing", "4", "FAILURE"),
+		outcome("deleted", "5", "SUCCESS"),
+	}
+	transitions := diffJobOutcomes(previous, current(
+		outcome("broken", "11", "FAILURE"),
+		outcome("fixed", "12", "SUCCESS"),
+		outcome("stable", "13", "SUCCESS"),
+		outcome("still-failing", "14", "FAILURE"),
+		outcome("added", "15", "FAILURE"),
+	), detectedAt)
+
+	assert.Len(t, transitions, 4)
+	byJob := make(map[string]*models.JobTransition)
+	for _, transition := range transitions {
+		assert.Equal(t, detectedAt, transition.DetectedAt)
+		byJob[transition.JobName] = transition
+	}
+
+	assert.Equal(t, models.TransitionPassToFail, byJob["broken"].Transition)
+	assert.Equal(t, "1", byJob["broken"].PreviousJobId)
+	assert.Equal(t, "11", byJob["broken"].CurrentJobId)
+	assert.Equal(t, models.TransitionFailToPass, byJob["fixed"].Transition)
+	assert.Equal(t, models.TransitionNewJob, byJob["added"].Transition)
+	assert.Empty(t, byJob["added"].PreviousResult)
+	assert.Equal(t, models.TransitionRemovedJob, byJob["deleted"].Transition)
+	assert.Equal(t, "SUCCESS", byJob["deleted"].PreviousResult)
+	assert.Empty(t, byJob["deleted"].CurrentJobId)
+
+	assert.Equal(t, "added", transitions[0].JobName, "transitions are sorted by job name")
+}
@@ -20,16 +20,20 @@ package tasks
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/plugins/testregistry/models"
 )
@@ -81,15 +85,17 @@ func isJobAlreadyProcessed(db dal.Dal, connectionId uint64, jobId string) bool {
 //
 // Parameters:
 //   - taskCtx: The subtask context
+//   - gcsClient: Fetcher for JUnit artifacts (the Openshift CI GCS bucket in production)
 //   - job: The source Prow job
 //   - githubOrg: Default GitHub organization (used as fallback)
 //   - repoName: Default repository name (used as fallback)
+//   - branch: Branch of a postsubmit job for the {branch} of the gcsPrefixTemplate (see postsubmitBranch)
 //   - ciJob: The CI job model
 //   - junitRegex: Compiled regex pattern for matching JUnit file names (uses default if nil)
 //
 // Returns:
 //   - bool: true if JUnit XML was found and parsed successfully, false otherwise
-func fetchAndPrintJUnitSuites(taskCtx plugin.SubTaskContext, gcsClient *GCSBucket, job *ProwJob, githubOrg, repoName string, ciJob *models.TestRegistryCIJob, junitRegex *regexp.Regexp) bool {
+func fetchAndPrintJUnitSuites(taskCtx plugin.SubTaskContext, gcsClient ResultsFetcher, job *ProwJob, githubOrg, repoName, branch string, ciJob *models.TestRegistryCIJob, junitRegex *regexp.Regexp) bool {
 	logger := taskCtx.GetLogger()
 	db := taskCtx.GetDal()
 
@@ -101,6 +107,8 @@ func fetchAndPrintJUnitSuites(taskCtx plugin.SubTaskContext, gcsClient *GCSBucke
 	// Check if this job is already processed (has test suites in database)
 	if isJobAlreadyProcessed(db, ciJob.ConnectionId, ciJob.JobId) {
 		logger.Info("Job already processed, skipping JUnit fetch", "job_id", ciJob.JobId, "job_name", ciJob.JobName)
+		// Saving the job again reset its test counts
+		updateJobTestCounts(db, logger, ciJob)
 		return true // Return true since we consider it "found" (already in DB)
 	}
 
@@ -116,7 +124,7 @@ func fetchAndPrintJUnitSuites(taskCtx plugin.SubTaskContext, gcsClient *GCSBucke
 
 	// Fetch all JUnit XML files from GCS using configurable regex
 	ctx := taskCtx.GetContext()
-	junitFiles := fetchJUnitFromGCS(ctx, gcsClient, job, ciJob, jobTypeForGCS, githubOrg, repoName, pullNumber, logger, junitRegex)
+	junitFiles := fetchJUnitFromGCS(ctx, gcsClient, job, ciJob, jobTypeForGCS, githubOrg, repoName, pullNumber, branch, logger, junitRegex)
 
 	if len(junitFiles) == 0 {
 		logger.Info("No JUnit XML found for job", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "trigger_type", ciJob.TriggerType)
@@ -132,9 +140,50 @@ func fetchAndPrintJUnitSuites(taskCtx plugin.SubTaskContext, gcsClient *GCSBucke
 			anySuccess = true
 		}
 	}
+	if anySuccess {
+		updateJobTestCounts(db, logger, ciJob)
+	}
 	return anySuccess
 }
 
+// jobTestCountsSql are the ci_test_jobs test count columns and their aggregate over the job's suites
+var jobTestCountsSql = []struct {
+	column    string
+	aggregate string
+}{
+	{"total_tests", "SUM(CASE WHEN parent_suite_id IS NULL THEN num_tests ELSE 0 END)"},
+	{"failed_tests", "SUM(CASE WHEN parent_suite_id IS NULL THEN num_failed + num_errors ELSE 0 END)"},
+	{"skipped_tests", "SUM(CASE WHEN parent_suite_id IS NULL THEN num_skipped ELSE 0 END)"},
+	{"suites_count", "COUNT(*)"},
+}
+
+// updateJobTestCounts stores the test totals of the job's saved suites on the CI job.
+//
+// Totals are summed over the top-level suites, whose JUnit attributes already include
+// their nested suites; suites_count counts every suite. A failure is logged, the
+// suites themselves are saved either way.
+//
+// Parameters:
+//   - db: Database connection
+//   - logger: Logger for error reporting
+//   - ciJob: The CI job whose counts are refreshed
+func updateJobTestCounts(db dal.Dal, logger log.Logger, ciJob *models.TestRegistryCIJob) {
+	var set []dal.DalSet
+	for _, count := range jobTestCountsSql {
+		set = append(set, dal.DalSet{
+			ColumnName: count.column,
+			Value: dal.Expr(
+				"(SELECT COALESCE("+count.aggregate+", 0) FROM ci_test_suites WHERE connection_id = ? AND job_id = ?)",
+				ciJob.ConnectionId, ciJob.JobId,
+			),
+		})
+	}
+	err := db.UpdateColumns(&models.TestRegistryCIJob{}, set, dal.Where("connection_id = ? AND job_id = ?", ciJob.ConnectionId, ciJob.JobId))
+	if err != nil {
+		logger.Warn(err, "failed to update job test counts", "job_id", ciJob.JobId)
+	}
+}
+
 // determineJobTypeForGCS maps our trigger type to GCS job type format.
 //
 // Mapping:
@@ -186,13 +235,14 @@ func extractPullRequestNumber(ciJob *models.TestRegistryCIJob) string {
 // Reference: https://github.com/konflux-ci/quality-dashboard/blob/e846aa2dd9b3c1cad9ac4d16d18ddf677e3e6247/backend/api/server/prow_rotate.go#L64-L67
 func fetchJUnitFromGCS(
 	ctx context.Context,
-	gcsClient *GCSBucket,
+	gcsClient ResultsFetcher,
 	job *ProwJob,
 	ciJob *models.TestRegistryCIJob,
 	jobTypeForGCS string,
 	githubOrg string,
 	repoName string,
 	pullNumber string,
+	branch string,
 	logger log.Logger,
 	junitRegex *regexp.Regexp,
 ) []JUnitFile {
@@ -203,7 +253,7 @@ func fetchJUnitFromGCS(
 
 	// Periodic jobs: empty org/repo/pr
 	if jobTypeForGCS == "periodic" {
-		files, gcsErr = gcsClient.GetJobJunitContent(ctx, "", "", "", ciJob.JobId, "periodic", ciJob.JobName, junitRegex)
+		files, gcsErr = gcsClient.GetJobJunitContent(ctx, "", "", "", "", ciJob.JobId, "periodic", ciJob.JobName, junitRegex)
 	} else {
 		// For non-periodic jobs, extract org/repo from Prow job refs
 		orgForGCS, repoForGCS := extractOrgRepoForGCS(job, githubOrg, repoName, ciJob.JobId, logger)
@@ -214,10 +264,10 @@ func fetchJUnitFromGCS(
 				logger.Info("Missing PR number for presubmit job, skipping JUnit fetch", "job_id", ciJob.JobId, "job_name", ciJob.JobName)
 				return nil
 			}
-			files, gcsErr = gcsClient.GetJobJunitContent(ctx, orgForGCS, repoForGCS, pullNumber, ciJob.JobId, "presubmit", ciJob.JobName, junitRegex)
+			files, gcsErr = gcsClient.GetJobJunitContent(ctx, orgForGCS, repoForGCS, pullNumber, "", ciJob.JobId, "presubmit", ciJob.JobName, junitRegex)
 		} else {
-			// Postsubmit: need org and repo, but no PR number
-			files, gcsErr = gcsClient.GetJobJunitContent(ctx, orgForGCS, repoForGCS, "", ciJob.JobId, "postsubmit", ciJob.JobName, junitRegex)
+			// Postsubmit: need org and repo, but no PR number; the branch only for a {branch} template
+			files, gcsErr = gcsClient.GetJobJunitContent(ctx, orgForGCS, repoForGCS, "", branch, ciJob.JobId, "postsubmit", ciJob.JobName, junitRegex)
 		}
 	}
 
@@ -228,6 +278,35 @@ func fetchJUnitFromGCS(
 	return files
 }
 
+// postsubmitBranch returns the {branch} of the gcsPrefixTemplate for a postsubmit Prow job.
+//
+// The branch is the job's base ref, or the scope config's defaultBranch when the job reports none,
+// renamed through the config's branchOverrides. The Openshift CI layout has no branch segment, so
+// it only matters for connections whose gcsPrefixTemplate uses {branch}.
+//
+// Parameters:
+//   - job: The source Prow job
+//   - scopeConfig: Scope config of the scope being collected (may be nil)
+//
+// Returns:
+//   - string: The branch path segment, or empty when neither the job nor the config names one
+func postsubmitBranch(job *ProwJob, scopeConfig *models.TestRegistryScopeConfig) string {
+	branch := ""
+	if job.Spec.Refs != nil {
+		branch = job.Spec.Refs.BaseRef
+	}
+	if scopeConfig == nil {
+		return branch
+	}
+	if branch == "" {
+		branch = scopeConfig.DefaultBranch
+	}
+	if override, ok := scopeConfig.BranchOverrides[branch]; ok && override != "" {
+		return override
+	}
+	return branch
+}
+
 // extractOrgRepoForGCS extracts organization and repository names for GCS path construction.
 //
 // For non-periodic jobs, this function extracts org/repo from Prow job refs (matching quality-dashboard).
@@ -255,6 +334,9 @@ func extractOrgRepoForGCS(job *ProwJob, githubOrg, repoName, jobId string, logge
 
 // parseAndSaveJUnitSuites parses JUnit XML, logs comprehensive test suite information, and saves to database.
 //
+// The outcome is recorded in ci_test_junit_files whether the file parsed or not, so a job with
+// several JUnit files shows which of them produced its suites.
+//
 // Parameters:
 //   - taskCtx: The subtask context (for database access)
 //   - logger: Logger for output
@@ -267,27 +349,35 @@ func extractOrgRepoForGCS(job *ProwJob, githubOrg, repoName, jobId string, logge
 // Returns:
 //   - bool: true if JUnit XML was successfully parsed, logged, and saved, false otherwise
 func parseAndSaveJUnitSuites(taskCtx plugin.SubTaskContext, logger log.Logger, suites []byte, xmlFileName string, ciJob *models.TestRegistryCIJob, githubOrg, repoName string) bool {
+	// Suites and test cases inherit the job's raw record; the remark keeps the source file
+	origin := ciJob.RawDataOrigin
+	origin.RawDataRemark = xmlFileName
+
+	file := &models.JUnitFile{
+		NoPKModel:    common.NoPKModel{RawDataOrigin: origin},
+		ConnectionId: ciJob.ConnectionId,
+		JobId:        ciJob.JobId,
+		FileId:       junitFileId(xmlFileName),
+		Path:         xmlFileName,
+	}
+
 	if len(suites) == 0 {
 		logger.Info("No JUnit XML found for job", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "trigger_type", ciJob.TriggerType)
+		file.Status = models.JUnitFileEmpty
+		file.Error = "file is empty"
+		recordJUnitFile(taskCtx.GetDal(), logger, file)
 		return false
 	}
 
-	// Parse XML — JUnit files can have either <testsuites> (wrapper) or bare <testsuite> as root
-	var suitesXml TestSuites
-	if err := xml.Unmarshal(suites, &suitesXml); err != nil {
+	suitesXml, err := decodeJUnitSuites(suites)
+	if err != nil {
 		logger.Debug("failed to parse JUnit XML", "error", err, "job_id", ciJob.JobId, "xml_file", xmlFileName)
+		file.Status = models.JUnitFileFailed
+		file.Error = err.Error()
+		recordJUnitFile(taskCtx.GetDal(), logger, file)
 		return false
 	}
 
-	// If no suites found, try parsing as a single bare <testsuite> root element
-	// (e.g., prowjob_junit.xml uses <testsuite> instead of <testsuites>)
-	if len(suitesXml.Suites) == 0 {
-		var singleSuite TestSuite
-		if err := xml.Unmarshal(suites, &singleSuite); err == nil && singleSuite.Name != "" {
-			suitesXml.Suites = []*TestSuite{&singleSuite}
-		}
-	}
-
 	// Log job context
 	logger.Info("JUnit XML found for job",
 		"job_id", ciJob.JobId,
@@ -301,6 +391,9 @@ func parseAndSaveJUnitSuites(taskCtx plugin.SubTaskContext, logger log.Logger, s
 	// Check if we have any suites
 	if len(suitesXml.Suites) == 0 {
 		logger.Info("No test suites found in JUnit XML", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "xml_file", xmlFileName)
+		file.Status = models.JUnitFileEmpty
+		file.Error = "no test suites found"
+		recordJUnitFile(taskCtx.GetDal(), logger, file)
 		return false
 	}
 
@@ -309,6 +402,13 @@ func parseAndSaveJUnitSuites(taskCtx plugin.SubTaskContext, logger log.Logger, s
 	// Get database connection
 	db := taskCtx.GetDal()
 
+	rules := &junitSaveRules{FileId: file.FileId}
+	if data, ok := taskCtx.GetData().(*TestRegistryTaskData); ok && data != nil {
+		rules.PassedCases = data.PassedCasePolicy
+		rules.Components = data.ComponentMapper
+		rules.TestIdentity = data.TestIdentityNormalizer
+	}
+
 	// Process and save each suite (including nested ones)
 	savedSuites := 0
 	savedTestCases := 0
@@ -317,7 +417,7 @@ func parseAndSaveJUnitSuites(taskCtx plugin.SubTaskContext, logger log.Logger, s
 			logSuiteInfo(logger, suite, ciJob.JobId, idx+1, 0)
 
 			// Save top-level suite and all nested suites recursively
-			suiteCount, testCaseCount := saveSuiteRecursively(db, logger, suite, ciJob.ConnectionId, ciJob.JobId, nil)
+			suiteCount, testCaseCount := saveSuiteRecursively(db, logger, suite, ciJob.ConnectionId, ciJob.JobId, origin, rules, nil)
 			savedSuites += suiteCount
 			savedTestCases += testCaseCount
 		}
@@ -328,9 +428,56 @@ func parseAndSaveJUnitSuites(taskCtx plugin.SubTaskContext, logger log.Logger, s
 		"suites_saved", savedSuites,
 		"test_cases_saved", savedTestCases)
 
+	file.Status = models.JUnitFileParsed
+	file.SuitesCount = uint(savedSuites)
+	file.TestCasesCount = uint(savedTestCases)
+	recordJUnitFile(db, logger, file)
 	return true
 }
 
+// decodeJUnitSuites parses JUnit XML, whose root is either a <testsuites> wrapper or a bare
+// <testsuite> (e.g., prowjob_junit.xml). It has no suites when the XML holds none.
+func decodeJUnitSuites(content []byte) (*TestSuites, error) {
+	var suitesXml TestSuites
+	if err := xml.Unmarshal(content, &suitesXml); err != nil {
+		return nil, err
+	}
+	if len(suitesXml.Suites) == 0 {
+		var singleSuite TestSuite
+		if err := xml.Unmarshal(content, &singleSuite); err == nil && singleSuite.Name != "" {
+			suitesXml.Suites = []*TestSuite{&singleSuite}
+		}
+	}
+	return &suitesXml, nil
+}
+
+// hasJUnitSuites tells whether any of the files parses into at least one suite
+func hasJUnitSuites(files []JUnitFile) bool {
+	for _, file := range files {
+		if len(file.Content) == 0 {
+			continue
+		}
+		if suites, err := decodeJUnitSuites(file.Content); err == nil && len(suites.Suites) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// junitFileId returns the id of a JUnit file of a job: the hex sha256 of its path
+func junitFileId(path string) string {
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:])
+}
+
+// recordJUnitFile saves the parse status of a JUnit file. A failure is logged, the
+// suites themselves are saved either way.
+func recordJUnitFile(db dal.Dal, logger log.Logger, file *models.JUnitFile) {
+	if err := db.CreateOrUpdate(file); err != nil {
+		logger.Warn(err, "failed to save JUnit file status", "job_id", file.JobId, "path", file.Path)
+	}
+}
+
 // logSuiteInfo logs information about a test suite.
 //
 // Parameters:
@@ -346,6 +493,7 @@ func logSuiteInfo(logger log.Logger, suite *TestSuite, jobId string, suiteIndex
 		"suite_name", suite.Name,
 		"tests", suite.NumTests,
 		"failures", suite.NumFailed,
+		"errors", suite.Errors(),
 		"skipped", suite.NumSkipped,
 		"duration_sec", suite.Duration)
 }
@@ -367,6 +515,18 @@ func generateUID() string {
 	return string(b)
 }
 
+// junitSaveRules bundles the source file and the scope config rules applied while saving JUnit results
+type junitSaveRules struct {
+	// FileId links the saved suites to their ci_test_junit_files row (empty for none)
+	FileId string
+	// PassedCases decides which passing test cases are stored (nil stores all of them)
+	PassedCases *PassedCasePolicy
+	// Components resolves the Konflux component of each suite (nil leaves it empty)
+	Components *ComponentMapper
+	// TestIdentity normalizes the suite names in test case identities (nil keeps them as reported)
+	TestIdentity *TestIdentityNormalizer
+}
+
 // saveSuiteRecursively saves a test suite and all its nested suites and test cases to the database.
 //
 // This function recursively processes nested suites and saves them with proper parent-child relationships.
@@ -377,15 +537,27 @@ func generateUID() string {
 //   - suite: The test suite XML structure to save
 //   - connectionId: The DevLake connection ID
 //   - jobId: The CI job ID
-//   - parentSuiteId: The parent suite ID (nil for top-level suites)
+//   - origin: Raw data origin linking the rows back to the raw job record
+//   - rules: Scope config rules for passing test cases, components and test identities (nil applies none)
+//   - parent: The saved parent suite (nil for top-level suites)
 //
 // Returns:
 //   - int: Number of suites saved (including nested ones)
 //   - int: Number of test cases saved
-func saveSuiteRecursively(db dal.Dal, logger log.Logger, suite *TestSuite, connectionId uint64, jobId string, parentSuiteId *string) (int, int) {
+func saveSuiteRecursively(db dal.Dal, logger log.Logger, suite *TestSuite, connectionId uint64, jobId string, origin common.RawDataOrigin, rules *junitSaveRules, parent *models.TestSuite) (int, int) {
 	if suite == nil || suite.Name == "" {
 		return 0, 0
 	}
+	if rules == nil {
+		rules = &junitSaveRules{}
+	}
+
+	var parentSuiteId *string
+	parentComponent := ""
+	if parent != nil {
+		parentSuiteId = &parent.SuiteId
+		parentComponent = parent.Component
+	}
 
 	// Always create a new suite — dedup across JUnit files is intentionally skipped so that
 	// suites with the same name from different files (e.g., same test suite run with different
@@ -404,18 +576,39 @@ func saveSuiteRecursively(db dal.Dal, logger log.Logger, suite *TestSuite, conne
 		}
 	}
 
+	// Select the test cases to store; omitted passing cases are only counted on the suite
+	var keptCases []*TestCase
+	var numPassedOmitted uint
+	for _, testCase := range suite.TestCases {
+		if testCase == nil {
+			continue
+		}
+		if rules.PassedCases.keep(suite.Name, testCase) {
+			keptCases = append(keptCases, testCase)
+		} else {
+			numPassedOmitted++
+		}
+	}
+
 	// Create database model
 	testSuite := &models.TestSuite{
-		ConnectionId:  connectionId,
-		JobId:         jobId,
-		SuiteId:       suiteId,
-		Name:          suite.Name,
-		NumTests:      suite.NumTests,
-		NumSkipped:    suite.NumSkipped,
-		NumFailed:     suite.NumFailed,
-		Duration:      suite.Duration,
-		Properties:    propertiesJSON,
-		ParentSuiteId: parentSuiteId,
+		NoPKModel:        common.NoPKModel{RawDataOrigin: origin},
+		ConnectionId:     connectionId,
+		JobId:            jobId,
+		SuiteId:          suiteId,
+		Name:             suite.Name,
+		NumTests:         suite.NumTests,
+		NumSkipped:       suite.NumSkipped,
+		NumFailed:        suite.NumFailed,
+		NumErrors:        suite.Errors(),
+		NumAssertions:    suite.NumAssertions,
+		Timestamp:        ParseJUnitTimestamp(suite.Timestamp),
+		Duration:         suite.Duration,
+		NumPassedOmitted: numPassedOmitted,
+		Component:        rules.Components.component(suite.Name, parentComponent),
+		Properties:       propertiesJSON,
+		FileId:           rules.FileId,
+		ParentSuiteId:    parentSuiteId,
 	}
 
 	// Save suite to database
@@ -428,19 +621,17 @@ func saveSuiteRecursively(db dal.Dal, logger log.Logger, suite *TestSuite, conne
 	testCaseCount := 0
 
 	// Save test cases for this suite
-	for _, testCase := range suite.TestCases {
-		if testCase != nil {
-			if err := saveTestCase(db, logger, testCase, connectionId, jobId, suiteId); err == nil {
-				testCaseCount++
-			}
+	for _, testCase := range keptCases {
+		testIdentity := rules.TestIdentity.Identity(suite.Name, testCase.Name)
+		if err := saveTestCase(db, logger, testCase, connectionId, jobId, suiteId, testIdentity, origin); err == nil {
+			testCaseCount++
 		}
 	}
 
 	// Recursively save nested suites
 	for _, child := range suite.Children {
 		if child != nil {
-			childSuiteId := suiteId // Pass current suite ID as parent
-			nestedSuiteCount, nestedTestCaseCount := saveSuiteRecursively(db, logger, child, connectionId, jobId, &childSuiteId)
+			nestedSuiteCount, nestedTestCaseCount := saveSuiteRecursively(db, logger, child, connectionId, jobId, origin, rules, testSuite)
 			suiteCount += nestedSuiteCount
 			testCaseCount += nestedTestCaseCount
 		}
@@ -458,39 +649,31 @@ func saveSuiteRecursively(db dal.Dal, logger log.Logger, suite *TestSuite, conne
 //   - connectionId: The DevLake connection ID
 //   - jobId: The CI job ID
 //   - suiteId: The parent suite ID
+//   - testIdentity: The canonical identity of the test case
+//   - origin: Raw data origin linking the row back to the raw job record
 //
 // Returns:
 //   - errors.Error: Any error encountered during saving, or nil if successful
-func saveTestCase(db dal.Dal, logger log.Logger, testCase *TestCase, connectionId uint64, jobId, suiteId string) errors.Error {
+func saveTestCase(db dal.Dal, logger log.Logger, testCase *TestCase, connectionId uint64, jobId, suiteId, testIdentity string, origin common.RawDataOrigin) errors.Error {
 	// Always create a new test case — each suite has a unique ID so test cases are
 	// naturally scoped to their source JUnit file. No cross-file dedup needed.
 	testCaseId := generateUID()
 
 	// Determine test case status
-	status := "passed"
-	var failureMessage, failureOutput *string
-	var skipMessage *string
-
-	if testCase.FailureOutput != nil {
-		status = "failed"
-		failureMsg := testCase.FailureOutput.Message
-		failureMessage = &failureMsg
-		failureOut := testCase.FailureOutput.Output
-		failureOutput = &failureOut
-	} else if testCase.SkipMessage != nil {
-		status = "skipped"
-		skipMsg := testCase.SkipMessage.Message
-		skipMessage = &skipMsg
-	}
+	status, failureMessage, failureOutput, skipMessage := testCase.Result()
 
 	// Create database model
 	testCaseModel := &models.TestCase{
+		NoPKModel:      common.NoPKModel{RawDataOrigin: origin},
 		ConnectionId:   connectionId,
 		JobId:          jobId,
 		SuiteId:        suiteId,
 		TestCaseId:     testCaseId,
 		Name:           testCase.Name,
 		Classname:      testCase.Classname,
+		TestIdentity:   testIdentity,
+		File:           testCase.File,
+		Assertions:     testCase.Assertions,
 		Duration:       testCase.Duration,
 		Status:         status,
 		FailureMessage: failureMessage,
@@ -508,6 +691,35 @@ func saveTestCase(db dal.Dal, logger log.Logger, testCase *TestCase, connectionI
 	return nil
 }
 
+// junitTimestampLayouts lists the timestamp formats seen in JUnit reports.
+// Most frameworks omit the time zone, in which case UTC is assumed.
+var junitTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// ParseJUnitTimestamp parses the timestamp attribute of a test suite
+// It is shared by the collectors and the push API
+//
+// Parameters:
+//   - value: Raw attribute value (e.g., "2024-06-15T10:00:00" or "2024-06-15T10:00:00.123+02:00")
+//
+// Returns:
+//   - *time.Time: Parsed time, or nil if the value is empty or in an unknown format
+func ParseJUnitTimestamp(value string) *time.Time {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range junitTimestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t
+		}
+	}
+	return nil
+}
+
 // stringPtrOrNil converts a string to a pointer, returning nil if the string is empty
 func stringPtrOrNil(s string) *string {
 	if s == "" {
@@ -18,9 +18,13 @@ limitations under the License.
 package tasks
 
 import (
+	"encoding/xml"
 	"testing"
+	"time"
 
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
 	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
 	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
 	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
@@ -66,6 +70,45 @@ func TestDetermineJobTypeForGCS(t *testing.T) {
 	})
 }
 
+func TestPostsubmitBranch(t *testing.T) {
+	withBaseRef := &ProwJob{Spec: ProwJobSpec{Refs: &ProwJobRefs{BaseRef: "release-1.2"}}}
+
+	t.Run("no scope config uses the base ref", func(t *testing.T) {
+		assert.Equal(t, "release-1.2", postsubmitBranch(withBaseRef, nil))
+		assert.Equal(t, "", postsubmitBranch(&ProwJob{}, nil))
+	})
+
+	t.Run("overrides apply without a default branch", func(t *testing.T) {
+		config := &models.TestRegistryScopeConfig{BranchOverrides: map[string]string{"release-1.2": "rel"}}
+		assert.Equal(t, "rel", postsubmitBranch(withBaseRef, config))
+	})
+
+	t.Run("base ref wins over the default branch", func(t *testing.T) {
+		config := &models.TestRegistryScopeConfig{DefaultBranch: "develop"}
+		assert.Equal(t, "release-1.2", postsubmitBranch(withBaseRef, config))
+	})
+
+	t.Run("default branch when the job has no base ref", func(t *testing.T) {
+		config := &models.TestRegistryScopeConfig{DefaultBranch: "develop"}
+		assert.Equal(t, "develop", postsubmitBranch(&ProwJob{}, config))
+	})
+
+	t.Run("overrides rename the branch", func(t *testing.T) {
+		config := &models.TestRegistryScopeConfig{
+			DefaultBranch:   "develop",
+			BranchOverrides: map[string]string{"develop": "dev", "release-1.2": "rel-1.2"},
+		}
+		assert.Equal(t, "dev", postsubmitBranch(&ProwJob{}, config))
+		assert.Equal(t, "rel-1.2", postsubmitBranch(withBaseRef, config))
+	})
+}
+
+func TestJunitArtifactsPrefix(t *testing.T) {
+	assert.Equal(t, "pr-logs/pull/org_repo/42/pull-e2e/1/artifacts", junitArtifactsPrefix("org", "repo", "42", "1", "presubmit", "pull-e2e"))
+	assert.Equal(t, "logs/branch-e2e/2/artifacts", junitArtifactsPrefix("org", "repo", "", "2", "postsubmit", "branch-e2e"))
+	assert.Equal(t, "logs/periodic-e2e/4/artifacts", junitArtifactsPrefix("", "", "", "4", "periodic", "periodic-e2e"))
+}
+
 func TestExtractPullRequestNumber(t *testing.T) {
 	t.Run("returns PR number for pull_request trigger", func(t *testing.T) {
 		prNum := 42
@@ -106,6 +149,99 @@ func TestStringPtrOrNil(t *testing.T) {
 	})
 }
 
+func TestParseJUnitTimestamp(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  *time.Time
+	}{
+		{"empty", "", nil},
+		{"without zone", "2024-06-15T10:00:00", ptrTime(time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC))},
+		{"with fraction", "2024-06-15T10:00:00.250", ptrTime(time.Date(2024, 6, 15, 10, 0, 0, 250000000, time.UTC))},
+		{"with zone", "2024-06-15T12:00:00+02:00", ptrTime(time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC))},
+		{"space separated", "2024-06-15 10:00:00", ptrTime(time.Date(2024, 6, 15, 10, 0, 0, 0, time.UTC))},
+		{"unknown format", "15/06/2024", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseJUnitTimestamp(tt.value)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.True(t, tt.want.Equal(*got), "got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func ptrTime(t time.Time) *time.Time {
+	return &t
+}
+
+func TestJUnitVendorAttributes(t *testing.T) {
+	report := `<testsuite name="pytest" tests="2" failures="0" errors="1" skipped="0" time="1.5"
+		timestamp="2024-06-15T10:00:00.123456" assertions="7" hostname="runner">
+		<testcase classname="tests.test_api" name="test_get" file="tests/test_api.py" line="12" assertions="3" time="0.5"/>
+		<testcase classname="tests.test_api" name="test_post" file="tests/test_api.py" line="30" time="1.0"/>
+	</testsuite>`
+	suite := &TestSuite{}
+	assert.NoError(t, xml.Unmarshal([]byte(report), suite))
+	assert.Equal(t, uint(1), suite.NumErrors)
+	assert.Equal(t, uint(7), suite.NumAssertions)
+	assert.Equal(t, "2024-06-15T10:00:00.123456", suite.Timestamp)
+	assert.Equal(t, "tests/test_api.py", suite.TestCases[0].File)
+	assert.Equal(t, uint(3), suite.TestCases[0].Assertions)
+	assert.Equal(t, uint(0), suite.TestCases[1].Assertions)
+
+	mockDal := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
+
+	saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, nil, nil)
+
+	mockDal.AssertCalled(t, "CreateOrUpdate", mock.MatchedBy(func(s *models.TestSuite) bool {
+		return s.NumErrors == 1 && s.NumAssertions == 7 && s.Timestamp != nil &&
+			s.Timestamp.Equal(time.Date(2024, 6, 15, 10, 0, 0, 123456000, time.UTC))
+	}), mock.Anything)
+	mockDal.AssertCalled(t, "CreateOrUpdate", mock.MatchedBy(func(tc *models.TestCase) bool {
+		return tc.Name == "test_get" && tc.File == "tests/test_api.py" && tc.Assertions == 3
+	}), mock.Anything)
+}
+
+func TestJUnitErrorElements(t *testing.T) {
+	report := `<testsuite name="e2e" tests="4" failures="1" skipped="1" time="3">
+		<testcase name="passes" time="0.5"/>
+		<testcase name="fails" time="0.5"><failure message="expected 1, got 2">assert.go:12</failure></testcase>
+		<testcase name="errors" time="1"><error message="connection refused" type="IOError">dial tcp: connection refused</error></testcase>
+		<testcase name="skips" time="0"><skipped message="flaky"/></testcase>
+	</testsuite>`
+	suite := &TestSuite{}
+	assert.NoError(t, xml.Unmarshal([]byte(report), suite))
+
+	var statuses []string
+	for _, tc := range suite.TestCases {
+		status, _, _, _ := tc.Result()
+		statuses = append(statuses, status)
+	}
+	assert.Equal(t, []string{"passed", "failed", "errored", "skipped"}, statuses)
+	assert.Equal(t, "IOError", suite.TestCases[2].ErrorOutput.Type)
+
+	_, message, output, _ := suite.TestCases[2].Result()
+	assert.Equal(t, "connection refused", *message)
+	assert.Equal(t, "dial tcp: connection refused", *output)
+
+	// The suite leaves out the errors attribute, its errored cases are counted instead
+	assert.Equal(t, uint(0), suite.NumErrors)
+	assert.Equal(t, uint(1), suite.Errors())
+	suite.Children = []*TestSuite{{Name: "nested", TestCases: []*TestCase{{Name: "boom", ErrorOutput: &ErrorOutput{}}}}}
+	assert.Equal(t, uint(2), suite.Errors())
+	suite.NumErrors = 5
+	assert.Equal(t, uint(5), suite.Errors())
+}
+
 func TestGenerateUID(t *testing.T) {
 	t.Run("returns 16-char string", func(t *testing.T) {
 		uid := generateUID()
@@ -146,6 +282,42 @@ func TestIsJobAlreadyProcessed(t *testing.T) {
 	})
 }
 
+func TestUpdateJobTestCounts(t *testing.T) {
+	ciJob := &models.TestRegistryCIJob{ConnectionId: 1, JobId: "job-1"}
+
+	t.Run("sets every count from the job's suites", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockLogger := new(mocklog.Logger)
+		var set []dal.DalSet
+		mockDal.On("UpdateColumns", mock.AnythingOfType("*models.TestRegistryCIJob"), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			set = args.Get(1).([]dal.DalSet)
+		}).Return(nil).Once()
+
+		updateJobTestCounts(mockDal, mockLogger, ciJob)
+
+		mockDal.AssertExpectations(t)
+		columns := make([]string, 0, len(set))
+		for _, s := range set {
+			columns = append(columns, s.ColumnName)
+			expr := s.Value.(dal.DalClause)
+			assert.Contains(t, expr.Expr, "FROM ci_test_suites WHERE connection_id = ? AND job_id = ?")
+			assert.Equal(t, []interface{}{uint64(1), "job-1"}, expr.Params)
+		}
+		assert.Equal(t, []string{"total_tests", "failed_tests", "skipped_tests", "suites_count"}, columns)
+	})
+
+	t.Run("update failure is only logged", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockLogger := new(mocklog.Logger)
+		mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Return(errors.Default.New("db error"))
+		mockLogger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Once()
+
+		updateJobTestCounts(mockDal, mockLogger, ciJob)
+
+		mockLogger.AssertExpectations(t)
+	})
+}
+
 func TestLogSuiteInfo(t *testing.T) {
 	mockLogger := new(mocklog.Logger)
 	mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
@@ -170,7 +342,7 @@ func TestSaveTestCase(t *testing.T) {
 		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
 
 		tc := &TestCase{Name: "TestFoo", Classname: "pkg.Foo", Duration: 1.5}
-		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1")
+		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1", "suite-1::TestFoo", common.RawDataOrigin{})
 		assert.Nil(t, err)
 		mockDal.AssertCalled(t, "CreateOrUpdate", mock.Anything, mock.Anything)
 	})
@@ -184,7 +356,7 @@ func TestSaveTestCase(t *testing.T) {
 			Name: "TestBar",
 			FailureOutput: &FailureOutput{Message: "assertion failed", Output: "expected true"},
 		}
-		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1")
+		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1", "suite-1::TestBar", common.RawDataOrigin{})
 		assert.Nil(t, err)
 	})
 
@@ -197,8 +369,25 @@ func TestSaveTestCase(t *testing.T) {
 			Name:        "TestSkipped",
 			SkipMessage: &SkipMessage{Message: "not implemented"},
 		}
-		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1")
+		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1", "suite-1::TestSkipped", common.RawDataOrigin{})
+		assert.Nil(t, err)
+	})
+
+	t.Run("errored test", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockLogger := new(mocklog.Logger)
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
+
+		tc := &TestCase{
+			Name:        "TestPanics",
+			ErrorOutput: &ErrorOutput{Message: "nil pointer dereference", Type: "panic", Output: "goroutine 1 [running]"},
+		}
+		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1", "suite-1::TestPanics", common.RawDataOrigin{})
 		assert.Nil(t, err)
+		mockDal.AssertCalled(t, "CreateOrUpdate", mock.MatchedBy(func(saved *models.TestCase) bool {
+			return saved.Status == "errored" && saved.FailureMessage != nil && *saved.FailureMessage == "nil pointer dereference" &&
+				saved.FailureOutput != nil && *saved.FailureOutput == "goroutine 1 [running]" && saved.SkipMessage == nil
+		}), mock.Anything)
 	})
 
 	t.Run("CreateOrUpdate error", func(t *testing.T) {
@@ -207,7 +396,7 @@ func TestSaveTestCase(t *testing.T) {
 		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(errors.Default.New("db error"))
 
 		tc := &TestCase{Name: "TestErr"}
-		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1")
+		err := saveTestCase(mockDal, mockLogger, tc, 1, "job-1", "suite-1", "suite-1::TestErr", common.RawDataOrigin{})
 		assert.NotNil(t, err)
 	})
 }
@@ -216,7 +405,7 @@ func TestSaveSuiteRecursively(t *testing.T) {
 	t.Run("nil suite returns 0,0", func(t *testing.T) {
 		mockDal := new(mockdal.Dal)
 		mockLogger := new(mocklog.Logger)
-		s, tc := saveSuiteRecursively(mockDal, mockLogger, nil, 1, "job-1", nil)
+		s, tc := saveSuiteRecursively(mockDal, mockLogger, nil, 1, "job-1", common.RawDataOrigin{}, nil, nil)
 		assert.Equal(t, 0, s)
 		assert.Equal(t, 0, tc)
 	})
@@ -225,7 +414,7 @@ func TestSaveSuiteRecursively(t *testing.T) {
 		mockDal := new(mockdal.Dal)
 		mockLogger := new(mocklog.Logger)
 		suite := &TestSuite{Name: ""}
-		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", nil)
+		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, nil, nil)
 		assert.Equal(t, 0, s)
 		assert.Equal(t, 0, tc)
 	})
@@ -244,7 +433,7 @@ func TestSaveSuiteRecursively(t *testing.T) {
 				{Name: "TestFoo", Duration: 1.0},
 			},
 		}
-		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", nil)
+		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, nil, nil)
 		assert.Equal(t, 1, s)
 		assert.Equal(t, 1, tc)
 	})
@@ -266,11 +455,32 @@ func TestSaveSuiteRecursively(t *testing.T) {
 			Name:     "ParentSuite",
 			Children: []*TestSuite{child},
 		}
-		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", nil)
+		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, nil, nil)
 		assert.Equal(t, 2, s)
 		assert.Equal(t, 1, tc)
 	})
 
+	t.Run("suite and test cases carry raw data origin", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockLogger := new(mocklog.Logger)
+		mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+
+		var origins []common.RawDataOrigin
+		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			switch row := args.Get(0).(type) {
+			case *models.TestSuite:
+				origins = append(origins, row.RawDataOrigin)
+			case *models.TestCase:
+				origins = append(origins, row.RawDataOrigin)
+			}
+		}).Return(nil)
+
+		origin := common.RawDataOrigin{RawDataTable: "_raw_cicd_test_jobs", RawDataId: 7, RawDataRemark: "junit.xml"}
+		suite := &TestSuite{Name: "MySuite", TestCases: []*TestCase{{Name: "TestFoo"}}}
+		saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", origin, nil, nil)
+		assert.Equal(t, []common.RawDataOrigin{origin, origin}, origins)
+	})
+
 	t.Run("suite with properties", func(t *testing.T) {
 		mockDal := new(mockdal.Dal)
 		mockLogger := new(mocklog.Logger)
@@ -283,7 +493,7 @@ func TestSaveSuiteRecursively(t *testing.T) {
 				{Name: "key1", Value: "val1"},
 			},
 		}
-		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", nil)
+		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, nil, nil)
 		assert.Equal(t, 1, s)
 		assert.Equal(t, 0, tc)
 	})
@@ -295,7 +505,7 @@ func TestSaveSuiteRecursively(t *testing.T) {
 		mockLogger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 		suite := &TestSuite{Name: "FailSuite"}
-		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", nil)
+		s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, nil, nil)
 		assert.Equal(t, 0, s)
 		assert.Equal(t, 0, tc)
 	})
@@ -308,6 +518,7 @@ func TestParseAndSaveJUnitSuites(t *testing.T) {
 		mockLogger := new(mocklog.Logger)
 
 		mockCtx.On("GetDal").Return(mockDal)
+		mockCtx.On("GetData").Return(nil).Maybe()
 		mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
 		mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
 		mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
@@ -321,16 +532,37 @@ func TestParseAndSaveJUnitSuites(t *testing.T) {
 		ciJob := &models.TestRegistryCIJob{ConnectionId: 1, JobId: "job-1", JobName: "test", TriggerType: "push", Result: "SUCCESS"}
 		result := parseAndSaveJUnitSuites(mockCtx, mockLogger, xmlData, "junit.xml", ciJob, "org", "repo")
 		assert.True(t, result)
+
+		var suite *models.TestSuite
+		var file *models.JUnitFile
+		for _, call := range mockDal.Calls {
+			switch v := call.Arguments.Get(0).(type) {
+			case *models.TestSuite:
+				suite = v
+			case *models.JUnitFile:
+				file = v
+			}
+		}
+		if assert.NotNil(t, file) && assert.NotNil(t, suite) {
+			assert.Equal(t, models.JUnitFileParsed, file.Status)
+			assert.Equal(t, "junit.xml", file.Path)
+			assert.Empty(t, file.Error)
+			assert.Equal(t, uint(1), file.SuitesCount)
+			assert.Equal(t, uint(1), file.TestCasesCount)
+			assert.Equal(t, file.FileId, suite.FileId)
+		}
 	})
 
 	t.Run("empty suites bytes", func(t *testing.T) {
 		mockCtx := new(mockplugin.SubTaskContext)
 		mockLogger := new(mocklog.Logger)
 		mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
+		file := expectJUnitFile(mockCtx)
 
 		ciJob := &models.TestRegistryCIJob{JobId: "job-1", JobName: "test"}
 		result := parseAndSaveJUnitSuites(mockCtx, mockLogger, []byte{}, "junit.xml", ciJob, "org", "repo")
 		assert.False(t, result)
+		assert.Equal(t, models.JUnitFileEmpty, file.Status)
 	})
 
 	t.Run("invalid XML", func(t *testing.T) {
@@ -338,9 +570,13 @@ func TestParseAndSaveJUnitSuites(t *testing.T) {
 		mockLogger := new(mocklog.Logger)
 		mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
 
+		file := expectJUnitFile(mockCtx)
+
 		ciJob := &models.TestRegistryCIJob{JobId: "job-1", JobName: "test"}
 		result := parseAndSaveJUnitSuites(mockCtx, mockLogger, []byte("not xml"), "junit.xml", ciJob, "org", "repo")
 		assert.False(t, result)
+		assert.Equal(t, models.JUnitFileFailed, file.Status)
+		assert.Equal(t, "EOF", file.Error)
 	})
 
 	t.Run("bare testsuite root element returns false", func(t *testing.T) {
@@ -349,11 +585,14 @@ func TestParseAndSaveJUnitSuites(t *testing.T) {
 
 		// xml.Unmarshal returns an error when root is <testsuite> instead of <testsuites>
 		mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+		file := expectJUnitFile(mockCtx)
 
 		xmlData := []byte(`<testsuite name="BareSuite" tests="1"><testcase name="Test1"/></testsuite>`)
 		ciJob := &models.TestRegistryCIJob{ConnectionId: 1, JobId: "job-1", JobName: "test", Result: "SUCCESS"}
 		result := parseAndSaveJUnitSuites(mockCtx, mockLogger, xmlData, "junit.xml", ciJob, "org", "repo")
 		assert.False(t, result)
+		assert.Equal(t, models.JUnitFileFailed, file.Status)
+		assert.NotEmpty(t, file.Error)
 	})
 
 	t.Run("testsuites with empty suites and bare fallback", func(t *testing.T) {
@@ -365,9 +604,31 @@ func TestParseAndSaveJUnitSuites(t *testing.T) {
 
 		// Empty testsuites wrapper triggers the fallback path, but since it's a valid
 		// <testsuites/> with no children, the single suite fallback won't match either
+		file := expectJUnitFile(mockCtx)
+
 		xmlData := []byte(`<testsuites></testsuites>`)
 		ciJob := &models.TestRegistryCIJob{ConnectionId: 1, JobId: "job-1", JobName: "test", Result: "SUCCESS"}
 		result := parseAndSaveJUnitSuites(mockCtx, mockLogger, xmlData, "junit.xml", ciJob, "org", "repo")
 		assert.False(t, result)
+		assert.Equal(t, models.JUnitFileEmpty, file.Status)
+		assert.Equal(t, "no test suites found", file.Error)
 	})
 }
+
+// expectJUnitFile mocks the dal of mockCtx and returns the JUnit file status saved through it
+func expectJUnitFile(mockCtx *mockplugin.SubTaskContext) *models.JUnitFile {
+	mockDal := new(mockdal.Dal)
+	file := &models.JUnitFile{}
+	mockCtx.On("GetDal").Return(mockDal)
+	mockDal.On("CreateOrUpdate", mock.AnythingOfType("*models.JUnitFile"), mock.Anything).
+		Run(func(args mock.Arguments) { *file = *args.Get(0).(*models.JUnitFile) }).
+		Return(nil)
+	return file
+}
+
+func TestJUnitFileId(t *testing.T) {
+	id := junitFileId("artifacts/e2e/junit.xml")
+	assert.Len(t, id, 64)
+	assert.Equal(t, id, junitFileId("artifacts/e2e/junit.xml"))
+	assert.NotEqual(t, id, junitFileId("artifacts/unit/junit.xml"))
+}
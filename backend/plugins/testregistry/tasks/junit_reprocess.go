@@ -0,0 +1,160 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// maxJUnitReprocessPerRun bounds the jobs whose JUnit files one run fetches again, the oldest requests first
+const maxJUnitReprocessPerRun = 200
+
+// ReprocessJUnitMeta defines the metadata for the JUnit reprocessing subtask
+var ReprocessJUnitMeta = plugin.SubTaskMeta{
+	Name:             "reprocessJUnit",
+	EntryPoint:       ReprocessJUnit,
+	EnabledByDefault: true,
+	Description:      "Fetch the JUnit files of the Prow jobs queued by POST reprocess-junit again and replace their test suites",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta},
+}
+
+// ReprocessJUnit fetches the JUnit files of the queued Prow jobs of the scope again, with the current JUnit
+// regex and path handling, so fixes apply to jobs collected before them without a full recollection.
+//
+// Up to maxJUnitReprocessPerRun pending requests of _tool_testregistry_junit_reprocess_requests are handled,
+// the oldest first; the rest wait for the next run. The files of a job are looked up like the collector does,
+// from the raw Prow job. When at least one of them parses into suites, the job's suites, test cases and JUnit
+// file records are replaced by the ones parsed from them; a job whose files are missing or don't parse keeps
+// what it had. Each request is marked processed with its outcome.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered while loading or updating the requests, or nil if successful
+func ReprocessJUnit(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	fullName := data.Options.FullName
+	if data.Connection.CITool != models.CIToolOpenshiftCI {
+		logger.Info("Connection is not Openshift CI, skipping JUnit reprocessing")
+		return nil
+	}
+
+	var requests []models.JUnitReprocessRequest
+	err := db.All(&requests,
+		dal.Where("connection_id = ? AND scope_id = ? AND processed_at IS NULL", data.Options.ConnectionId, fullName),
+		dal.Orderby("requested_at, job_id"),
+		dal.Limit(maxJUnitReprocessPerRun),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load JUnit reprocess requests")
+	}
+	if len(requests) == 0 {
+		return nil
+	}
+
+	fetcher, closeFetcher := newJUnitResultsFetcher(taskCtx, data)
+	defer closeFetcher()
+	if fetcher == nil {
+		logger.Info("no JUnit source, %d JUnit reprocess requests of scope %s are left for the next run", len(requests), fullName)
+		return nil
+	}
+
+	outcomes := make(map[string]int)
+	taskCtx.SetProgress(0, len(requests))
+	for i := range requests {
+		request := &requests[i]
+		request.Outcome, err = reprocessJobJUnit(taskCtx, fetcher, data, request.JobId)
+		if err != nil {
+			return err
+		}
+		request.SuitesCount = 0
+		if request.Outcome != models.JUnitReprocessJobNotFound {
+			count, countErr := db.Count(dal.From(&models.TestSuite{}), dal.Where("connection_id = ? AND job_id = ?", request.ConnectionId, request.JobId))
+			if countErr != nil {
+				return errors.Default.Wrap(countErr, "failed to count reprocessed suites")
+			}
+			request.SuitesCount = uint(count)
+		}
+		now := time.Now()
+		request.ProcessedAt = &now
+		if err := db.Update(request); err != nil {
+			return errors.Default.Wrap(err, "failed to mark JUnit reprocess request as processed")
+		}
+		outcomes[request.Outcome]++
+		taskCtx.IncProgress(1)
+	}
+	logger.Info("reprocessed the JUnit files of %d Prow jobs of scope %s: %v", len(requests), fullName, outcomes)
+	return nil
+}
+
+// reprocessJobJUnit fetches the JUnit files of one Prow job again and, when any parses, replaces the
+// job's JUnit data with them. It returns the outcome of the request; errors are database errors.
+func reprocessJobJUnit(taskCtx plugin.SubTaskContext, fetcher ResultsFetcher, data *TestRegistryTaskData, jobId string) (string, errors.Error) {
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	ciJob := &models.TestRegistryCIJob{}
+	err := db.First(ciJob, dal.Where("connection_id = ? AND job_id = ?", data.Options.ConnectionId, jobId))
+	if db.IsErrorNotFound(err) {
+		return models.JUnitReprocessJobNotFound, nil
+	}
+	if err != nil {
+		return "", errors.Default.Wrap(err, "failed to load CI job "+jobId)
+	}
+
+	prowJob := loadRawProwJob(db, logger, ciJob)
+	jobType, err := determineJobTypeForGCS(ciJob, prowJob)
+	if err != nil {
+		logger.Info("unknown trigger type, skipping JUnit reprocessing", "trigger_type", ciJob.TriggerType, "job_id", jobId)
+		return models.JUnitReprocessNotFound, nil
+	}
+	branch := postsubmitBranch(prowJob, data.Options.ScopeConfig)
+	files := fetchJUnitFromGCS(taskCtx.GetContext(), fetcher, prowJob, ciJob, jobType, ciJob.Organization, ciJob.Repository,
+		extractPullRequestNumber(ciJob), branch, logger, data.JUnitRegex)
+	if len(files) == 0 {
+		return models.JUnitReprocessNotFound, nil
+	}
+	if !hasJUnitSuites(files) {
+		logger.Info("no JUnit file of the job parsed, keeping its previous results", "job_id", jobId, "files", len(files))
+		return models.JUnitReprocessFailed, nil
+	}
+
+	// Suite and test case ids are random, the previous results must go before saving the files again
+	jobFilter := dal.Where("connection_id = ? AND job_id = ?", ciJob.ConnectionId, ciJob.JobId)
+	for _, table := range []interface{}{&models.TestCase{}, &models.TestSuite{}, &models.JUnitFile{}} {
+		if err := db.Delete(table, jobFilter); err != nil {
+			return "", errors.Default.Wrap(err, "failed to delete the JUnit data of job "+jobId)
+		}
+	}
+
+	for _, file := range files {
+		parseAndSaveJUnitSuites(taskCtx, logger, file.Content, file.Path, ciJob, ciJob.Organization, ciJob.Repository)
+	}
+	updateJobTestCounts(db, logger, ciJob)
+	return models.JUnitReprocessParsed, nil
+}
@@ -0,0 +1,118 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestReprocessJUnit(t *testing.T) {
+	requests := []models.JUnitReprocessRequest{
+		{ConnectionId: 1, JobId: "201", ScopeId: "build-service"},
+		{ConnectionId: 1, JobId: "202", ScopeId: "build-service"},
+		{ConnectionId: 1, JobId: "203", ScopeId: "build-service"},
+		{ConnectionId: 1, JobId: "204", ScopeId: "build-service"},
+	}
+	fillJob := func(jobId string) func(mock.Arguments) {
+		return func(args mock.Arguments) {
+			*args.Get(0).(*models.TestRegistryCIJob) = models.TestRegistryCIJob{ConnectionId: 1, JobId: jobId, JobName: "periodic-e2e", TriggerType: "periodic"}
+		}
+	}
+	errNotFound := errors.NotFound.New("record not found")
+
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDal := new(mockdal.Dal)
+	mockCtx.On("GetLogger").Return(newMockLogger())
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	mockCtx.On("SetProgress", mock.Anything, mock.Anything).Maybe()
+	mockCtx.On("IncProgress", mock.Anything).Maybe()
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]models.JUnitReprocessRequest) = requests
+	}).Return(nil)
+	mockDal.On("First", mock.Anything, mock.Anything).Run(fillJob("201")).Return(nil).Once()
+	mockDal.On("First", mock.Anything, mock.Anything).Run(fillJob("202")).Return(nil).Once()
+	mockDal.On("First", mock.Anything, mock.Anything).Run(fillJob("203")).Return(nil).Once()
+	mockDal.On("First", mock.Anything, mock.Anything).Return(errNotFound).Once()
+	mockDal.On("IsErrorNotFound", nil).Return(false)
+	mockDal.On("IsErrorNotFound", errNotFound).Return(true)
+	var deleted []string
+	mockDal.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		deleted = append(deleted, args.Get(0).(interface{ TableName() string }).TableName())
+	}).Return(nil)
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil)
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockDal.On("Count", mock.Anything, mock.Anything).Return(int64(1), nil)
+	var processed []*models.JUnitReprocessRequest
+	mockDal.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		processed = append(processed, args.Get(0).(*models.JUnitReprocessRequest))
+	}).Return(nil)
+
+	fetcher := new(mockResultsFetcher)
+	fetcher.On("GetJobJunitContent", mock.Anything, "", "", "", "", "201", "periodic", "periodic-e2e", mock.Anything).
+		Return([]JUnitFile{{Path: "logs/periodic-e2e/201/artifacts/junit.xml", Content: []byte(`<testsuites><testsuite name="e2e" tests="1"><testcase name="creates a component"/></testsuite></testsuites>`)}}, nil)
+	fetcher.On("GetJobJunitContent", mock.Anything, "", "", "", "", "202", "periodic", "periodic-e2e", mock.Anything).Return(nil, nil)
+	fetcher.On("GetJobJunitContent", mock.Anything, "", "", "", "", "203", "periodic", "periodic-e2e", mock.Anything).
+		Return([]JUnitFile{{Path: "logs/periodic-e2e/203/artifacts/build-log.txt", Content: []byte("not xml")}}, nil)
+
+	mockCtx.On("GetData").Return(&TestRegistryTaskData{
+		Options:                &TestRegistryOptions{ConnectionId: 1, FullName: "build-service"},
+		Connection:             &models.TestRegistryConnection{CITool: models.CIToolOpenshiftCI},
+		ResultsFetcherOverride: fetcher,
+	})
+
+	assert.Nil(t, ReprocessJUnit(mockCtx))
+	fetcher.AssertExpectations(t)
+	// Only the job whose files parsed again loses its previous results
+	assert.Equal(t, []string{"ci_test_cases", "ci_test_suites", "ci_test_junit_files"}, deleted)
+	if assert.Len(t, processed, 4) {
+		assert.Equal(t, models.JUnitReprocessParsed, processed[0].Outcome)
+		assert.Equal(t, uint(1), processed[0].SuitesCount)
+		assert.Equal(t, models.JUnitReprocessNotFound, processed[1].Outcome)
+		// files that don't parse leave the job's suites in place
+		assert.Equal(t, models.JUnitReprocessFailed, processed[2].Outcome)
+		assert.Equal(t, uint(1), processed[2].SuitesCount)
+		assert.Equal(t, models.JUnitReprocessJobNotFound, processed[3].Outcome)
+		assert.Equal(t, uint(0), processed[3].SuitesCount)
+		for _, request := range processed {
+			assert.NotNil(t, request.ProcessedAt)
+		}
+	}
+}
+
+func TestReprocessJUnit_NotOpenshiftCI(t *testing.T) {
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDal := new(mockdal.Dal)
+	mockCtx.On("GetLogger").Return(newMockLogger())
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetData").Return(&TestRegistryTaskData{
+		Options:    &TestRegistryOptions{ConnectionId: 1, FullName: "rhtap-releng-tenant"},
+		Connection: &models.TestRegistryConnection{CITool: models.CIToolTektonCI},
+	})
+
+	assert.Nil(t, ReprocessJUnit(mockCtx))
+	mockDal.AssertNotCalled(t, "All", mock.Anything, mock.Anything)
+}
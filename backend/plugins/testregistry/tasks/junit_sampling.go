@@ -0,0 +1,93 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"hash/fnv"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// PassedCasePolicy decides which passing test cases are stored.
+// Failed and skipped test cases are always stored so failure analytics stay exact;
+// only passing cases, which dominate large e2e suites, are thinned out.
+type PassedCasePolicy struct {
+	Mode          string
+	SamplePercent int
+}
+
+// NewPassedCasePolicy builds the policy from the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *PassedCasePolicy: The policy, or nil if every test case should be stored
+func NewPassedCasePolicy(scopeConfig *models.TestRegistryScopeConfig) *PassedCasePolicy {
+	if scopeConfig == nil {
+		return nil
+	}
+
+	switch scopeConfig.PassedCasesMode {
+	case models.PassedCasesModeAggregate:
+		return &PassedCasePolicy{Mode: models.PassedCasesModeAggregate}
+	case models.PassedCasesModeSample:
+		percent := scopeConfig.PassedCasesSamplePercent
+		if percent <= 0 {
+			percent = models.DefaultPassedCasesSamplePercent
+		}
+		if percent >= 100 {
+			return nil
+		}
+		return &PassedCasePolicy{Mode: models.PassedCasesModeSample, SamplePercent: percent}
+	default:
+		return nil
+	}
+}
+
+// keep reports whether a test case should be stored
+//
+// Sampling is deterministic on suite and test case name, so the same passing tests
+// are kept in every run and per-test pass history stays comparable across jobs.
+//
+// Parameters:
+//   - suiteName: Name of the suite the test case belongs to
+//   - testCase: The test case XML structure
+//
+// Returns:
+//   - bool: true if the test case should be stored
+func (p *PassedCasePolicy) keep(suiteName string, testCase *TestCase) bool {
+	if p == nil || testCase.FailureOutput != nil || testCase.ErrorOutput != nil || testCase.SkipMessage != nil {
+		return true
+	}
+
+	switch p.Mode {
+	case models.PassedCasesModeAggregate:
+		return false
+	case models.PassedCasesModeSample:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(suiteName))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(testCase.Classname))
+		_, _ = h.Write([]byte{0})
+		_, _ = h.Write([]byte(testCase.Name))
+		return int(h.Sum32()%100) < p.SamplePercent
+	default:
+		return true
+	}
+}
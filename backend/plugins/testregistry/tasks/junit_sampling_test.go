@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewPassedCasePolicy(t *testing.T) {
+	assert.Nil(t, NewPassedCasePolicy(nil))
+	assert.Nil(t, NewPassedCasePolicy(&models.TestRegistryScopeConfig{}))
+	assert.Nil(t, NewPassedCasePolicy(&models.TestRegistryScopeConfig{PassedCasesMode: models.PassedCasesModeAll}))
+	assert.Nil(t, NewPassedCasePolicy(&models.TestRegistryScopeConfig{PassedCasesMode: models.PassedCasesModeSample, PassedCasesSamplePercent: 100}))
+
+	policy := NewPassedCasePolicy(&models.TestRegistryScopeConfig{PassedCasesMode: models.PassedCasesModeSample})
+	assert.Equal(t, &PassedCasePolicy{Mode: models.PassedCasesModeSample, SamplePercent: models.DefaultPassedCasesSamplePercent}, policy)
+
+	policy = NewPassedCasePolicy(&models.TestRegistryScopeConfig{PassedCasesMode: models.PassedCasesModeAggregate})
+	assert.Equal(t, models.PassedCasesModeAggregate, policy.Mode)
+}
+
+func TestPassedCasePolicyKeep(t *testing.T) {
+	failed := &TestCase{Name: "TestFail", FailureOutput: &FailureOutput{Message: "boom"}}
+	errored := &TestCase{Name: "TestError", ErrorOutput: &ErrorOutput{Message: "panic"}}
+	skipped := &TestCase{Name: "TestSkip", SkipMessage: &SkipMessage{Message: "n/a"}}
+	passed := &TestCase{Name: "TestPass"}
+
+	t.Run("nil policy keeps everything", func(t *testing.T) {
+		var policy *PassedCasePolicy
+		assert.True(t, policy.keep("suite", passed))
+	})
+
+	t.Run("aggregate keeps only failed, errored and skipped", func(t *testing.T) {
+		policy := &PassedCasePolicy{Mode: models.PassedCasesModeAggregate}
+		assert.True(t, policy.keep("suite", failed))
+		assert.True(t, policy.keep("suite", errored))
+		assert.True(t, policy.keep("suite", skipped))
+		assert.False(t, policy.keep("suite", passed))
+	})
+
+	t.Run("sample is deterministic and roughly proportional", func(t *testing.T) {
+		policy := &PassedCasePolicy{Mode: models.PassedCasesModeSample, SamplePercent: 10}
+		assert.True(t, policy.keep("suite", failed))
+		for i := 0; i < 100; i++ {
+			assert.True(t, policy.keep("suite", &TestCase{Name: fmt.Sprintf("TestError%d", i), ErrorOutput: &ErrorOutput{}}), "errored cases are never sampled out")
+		}
+
+		kept := 0
+		for i := 0; i < 1000; i++ {
+			tc := &TestCase{Name: fmt.Sprintf("Test%d", i)}
+			first := policy.keep("suite", tc)
+			assert.Equal(t, first, policy.keep("suite", tc))
+			if first {
+				kept++
+			}
+		}
+		assert.InDelta(t, 100, kept, 40)
+	})
+}
+
+func TestSaveSuiteRecursivelyAggregateMode(t *testing.T) {
+	mockDal := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+
+	var savedSuite *models.TestSuite
+	var savedCases []*models.TestCase
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		switch row := args.Get(0).(type) {
+		case *models.TestSuite:
+			savedSuite = row
+		case *models.TestCase:
+			savedCases = append(savedCases, row)
+		}
+	}).Return(nil)
+
+	suite := &TestSuite{
+		Name:     "BigSuite",
+		NumTests: 3,
+		TestCases: []*TestCase{
+			{Name: "TestPass1"},
+			{Name: "TestFail", FailureOutput: &FailureOutput{Message: "boom"}},
+			{Name: "TestPass2"},
+		},
+	}
+	policy := &PassedCasePolicy{Mode: models.PassedCasesModeAggregate}
+	s, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, &junitSaveRules{PassedCases: policy}, nil)
+	assert.Equal(t, 1, s)
+	assert.Equal(t, 1, tc)
+	assert.Equal(t, uint(2), savedSuite.NumPassedOmitted)
+	assert.Equal(t, uint(3), savedSuite.NumTests)
+	assert.Len(t, savedCases, 1)
+	assert.Equal(t, "failed", savedCases[0].Status)
+}
@@ -138,6 +138,15 @@ type TestSuite struct {
 	// NumFailed records the number of failed tests in the suite
 	NumFailed uint `xml:"failures,attr"`
 
+	// NumErrors records the number of tests that errored, as opposed to failed assertions
+	NumErrors uint `xml:"errors,attr,omitempty"`
+
+	// NumAssertions records the number of assertions made by the suite (PHPUnit, Surefire)
+	NumAssertions uint `xml:"assertions,attr,omitempty"`
+
+	// Timestamp is when the suite started, usually ISO 8601 without a time zone
+	Timestamp string `xml:"timestamp,attr,omitempty"`
+
 	// Duration is the time taken in seconds to run all tests in the suite
 	Duration float64 `xml:"time,attr"`
 
@@ -169,6 +178,12 @@ type TestCase struct {
 	// Classname is an attribute set by the package type and is required
 	Classname string `xml:"classname,attr,omitempty"`
 
+	// File is the source file of the test case (pytest, PHPUnit)
+	File string `xml:"file,attr,omitempty"`
+
+	// Assertions records the number of assertions made by the test case
+	Assertions uint `xml:"assertions,attr,omitempty"`
+
 	// Duration is the time taken in seconds to run the test
 	Duration float64 `xml:"time,attr"`
 
@@ -178,6 +193,9 @@ type TestCase struct {
 	// FailureOutput holds the output from a failing test
 	FailureOutput *FailureOutput `xml:"failure"`
 
+	// ErrorOutput holds the output from a test that errored, e.g. an unexpected exception
+	ErrorOutput *ErrorOutput `xml:"error"`
+
 	// SystemOut is output written to stdout during the execution of this test case
 	SystemOut string `xml:"system-out,omitempty"`
 
@@ -203,3 +221,55 @@ type FailureOutput struct {
 	// Output holds verbose failure output from the test
 	Output string `xml:",chardata"`
 }
+
+// ErrorOutput holds the output from a test that errored rather than failed an assertion
+type ErrorOutput struct {
+	XMLName xml.Name `xml:"error"`
+
+	// Message holds the error message from the test
+	Message string `xml:"message,attr"`
+
+	// Type holds the kind of error, usually the exception class
+	Type string `xml:"type,attr,omitempty"`
+
+	// Output holds verbose error output from the test
+	Output string `xml:",chardata"`
+}
+
+// Result returns the ci_test_cases status of the test case with its message and output.
+// A failure wins over an error, and both over a skip; the message and output of a failed or
+// errored test go into the failure columns, the skip reason into the skip message.
+func (tc *TestCase) Result() (status string, failureMessage, failureOutput, skipMessage *string) {
+	switch {
+	case tc.FailureOutput != nil:
+		message, output := tc.FailureOutput.Message, tc.FailureOutput.Output
+		return "failed", &message, &output, nil
+	case tc.ErrorOutput != nil:
+		message, output := tc.ErrorOutput.Message, tc.ErrorOutput.Output
+		return "errored", &message, &output, nil
+	case tc.SkipMessage != nil:
+		message := tc.SkipMessage.Message
+		return "skipped", nil, nil, &message
+	}
+	return "passed", nil, nil, nil
+}
+
+// Errors returns the errors attribute of the suite or, when the report leaves it out,
+// the number of test cases of the suite and its children that errored
+func (s *TestSuite) Errors() uint {
+	if s.NumErrors > 0 {
+		return s.NumErrors
+	}
+	var count uint
+	for _, tc := range s.TestCases {
+		if tc != nil && tc.FailureOutput == nil && tc.ErrorOutput != nil {
+			count++
+		}
+	}
+	for _, child := range s.Children {
+		if child != nil {
+			count += child.Errors()
+		}
+	}
+	return count
+}
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -87,3 +88,26 @@ func TestGetJUnitRegexOrDefault(t *testing.T) {
 		assert.Equal(t, JUnitRegexpSearch, regex)
 	})
 }
+
+func TestJUnitRegexPattern(t *testing.T) {
+	connection := &models.TestRegistryConnection{JUnitRegex: `connection-.*\.xml`}
+
+	t.Run("task option wins", func(t *testing.T) {
+		op := &TestRegistryOptions{
+			JUnitRegex:  `option-.*\.xml`,
+			ScopeConfig: &models.TestRegistryScopeConfig{JUnitRegex: `scope-.*\.xml`},
+		}
+		assert.Equal(t, `option-.*\.xml`, op.JUnitRegexPattern(connection))
+	})
+
+	t.Run("scope config overrides the connection", func(t *testing.T) {
+		op := &TestRegistryOptions{ScopeConfig: &models.TestRegistryScopeConfig{JUnitRegex: `scope-.*\.xml`}}
+		assert.Equal(t, `scope-.*\.xml`, op.JUnitRegexPattern(connection))
+	})
+
+	t.Run("falls back to the connection", func(t *testing.T) {
+		op := &TestRegistryOptions{ScopeConfig: &models.TestRegistryScopeConfig{}}
+		assert.Equal(t, `connection-.*\.xml`, op.JUnitRegexPattern(connection))
+		assert.Empty(t, op.JUnitRegexPattern(nil))
+	})
+}
@@ -0,0 +1,312 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+	// pipelineRunListLimit is the page size used when listing PipelineRuns
+	pipelineRunListLimit = 200
+	// kubernetesRequestTimeout bounds every API call but watches, which end with their own deadline
+	kubernetesRequestTimeout = 60 * time.Second
+)
+
+// KubernetesClient reads Tekton PipelineRun objects from the Kubernetes API
+// Used instead of QuayClient/ORASClient when DevLake runs in the cluster running the pipelines
+type KubernetesClient struct {
+	apiServer   string
+	token       string
+	httpClient  *http.Client
+	watchClient *http.Client
+	logger      log.Logger
+}
+
+// KubernetesPipelineRun is the subset of a tekton.dev/v1 PipelineRun object used by the collector
+type KubernetesPipelineRun struct {
+	Metadata struct {
+		Name              string            `json:"name"`
+		Namespace         string            `json:"namespace"`
+		Labels            map[string]string `json:"labels"`
+		Annotations       map[string]string `json:"annotations"`
+		CreationTimestamp *time.Time        `json:"creationTimestamp"`
+	} `json:"metadata"`
+	Status struct {
+		StartTime      *time.Time `json:"startTime"`
+		CompletionTime *time.Time `json:"completionTime"`
+		Conditions     []struct {
+			Type   string `json:"type"`
+			Status string `json:"status"`
+			Reason string `json:"reason"`
+		} `json:"conditions"`
+	} `json:"status"`
+}
+
+// pipelineRunList is a page of the PipelineRun list API
+type pipelineRunList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+		Continue        string `json:"continue"`
+	} `json:"metadata"`
+	Items []KubernetesPipelineRun `json:"items"`
+}
+
+// watchEvent is one event of a Kubernetes watch stream
+type watchEvent struct {
+	Type   string          `json:"type"`
+	Object json.RawMessage `json:"object"`
+}
+
+// NewKubernetesClient creates a Kubernetes API client for a Tekton CI connection
+//
+// Connection fields left empty fall back to the pod's service account, like
+// client-go's in-cluster config: the API server comes from KUBERNETES_SERVICE_HOST/PORT,
+// the token and CA from /var/run/secrets/kubernetes.io/serviceaccount. The mounted token is
+// only sent to that in-cluster API server, any other server needs kubernetesToken.
+//
+// Parameters:
+//   - connection: The testregistry connection
+//   - logger: Logger for output
+//
+// Returns:
+//   - *KubernetesClient: The Kubernetes client instance
+//   - errors.Error: BadInput if neither the connection nor the environment define the API server or token
+func NewKubernetesClient(connection *models.TestRegistryConnection, logger log.Logger) (*KubernetesClient, errors.Error) {
+	inClusterServer := ""
+	if host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT"); host != "" && port != "" {
+		inClusterServer = "https://" + net.JoinHostPort(host, port)
+	}
+	apiServer := strings.TrimSuffix(strings.TrimSpace(connection.KubernetesApiServer), "/")
+	if apiServer == "" {
+		if inClusterServer == "" {
+			return nil, errors.BadInput.New("kubernetesApiServer is required when DevLake does not run inside a Kubernetes cluster")
+		}
+		apiServer = inClusterServer
+	}
+	inCluster := apiServer == inClusterServer
+
+	token := strings.TrimSpace(connection.KubernetesToken)
+	if token == "" {
+		if !inCluster {
+			return nil, errors.BadInput.New("kubernetesToken is required for an API server other than the in-cluster one")
+		}
+		content, err := os.ReadFile(serviceAccountDir + "/token")
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "kubernetesToken is required when no service account token is mounted")
+		}
+		token = strings.TrimSpace(string(content))
+	}
+
+	// Trust the cluster CA on top of the system roots when it is mounted
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = kubernetesRequestTimeout
+	if ca, err := os.ReadFile(serviceAccountDir + "/ca.crt"); inCluster && err == nil {
+		pool, poolErr := x509.SystemCertPool()
+		if poolErr != nil {
+			pool = x509.NewCertPool()
+		}
+		pool.AppendCertsFromPEM(ca)
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	return &KubernetesClient{
+		apiServer:   apiServer,
+		token:       token,
+		httpClient:  &http.Client{Transport: transport, Timeout: kubernetesRequestTimeout},
+		watchClient: &http.Client{Transport: transport},
+		logger:      logger,
+	}, nil
+}
+
+// Ping checks that the token can read Tekton PipelineRuns through the API server
+//
+// Returns:
+//   - errors.Error: An actionable error if the API server can't be reached or the token is rejected
+func (c *KubernetesClient) Ping(ctx context.Context) errors.Error {
+	resp, err := c.get(ctx, "/apis/tekton.dev/v1", nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return kubernetesStatusError(resp.StatusCode, "the tekton.dev/v1 API")
+}
+
+// ListPipelineRuns lists all PipelineRuns of a namespace, following pagination
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - namespace: Kubernetes namespace (the scope of Kubernetes-sourced connections)
+//
+// Returns:
+//   - []KubernetesPipelineRun: All PipelineRuns of the namespace
+//   - string: The resourceVersion of the list, to start a watch from
+//   - errors.Error: Any error encountered during listing
+func (c *KubernetesClient) ListPipelineRuns(ctx context.Context, namespace string) ([]KubernetesPipelineRun, string, errors.Error) {
+	var runs []KubernetesPipelineRun
+	continueToken := ""
+	for {
+		query := url.Values{}
+		query.Set("limit", fmt.Sprintf("%d", pipelineRunListLimit))
+		if continueToken != "" {
+			query.Set("continue", continueToken)
+		}
+
+		resp, err := c.get(ctx, pipelineRunsPath(namespace), query)
+		if err != nil {
+			return nil, "", err
+		}
+		if statusErr := kubernetesStatusError(resp.StatusCode, fmt.Sprintf("PipelineRuns of namespace '%s'", namespace)); statusErr != nil {
+			resp.Body.Close()
+			return nil, "", statusErr
+		}
+
+		var page pipelineRunList
+		decodeErr := json.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if decodeErr != nil {
+			return nil, "", errors.Default.Wrap(decodeErr, "failed to parse PipelineRun list")
+		}
+
+		runs = append(runs, page.Items...)
+		if page.Metadata.Continue == "" {
+			c.logger.Info("Listed PipelineRuns from Kubernetes", "namespace", namespace, "total", len(runs))
+			return runs, page.Metadata.ResourceVersion, nil
+		}
+		continueToken = page.Metadata.Continue
+	}
+}
+
+// WatchPipelineRuns watches the PipelineRuns of a namespace for a bounded time,
+// calling handle for every added or modified PipelineRun
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - namespace: Kubernetes namespace
+//   - resourceVersion: The resourceVersion returned by ListPipelineRuns
+//   - timeout: How long to watch
+//   - handle: Called with every added or modified PipelineRun
+//
+// Returns:
+//   - errors.Error: Any error encountered; an expired resourceVersion only ends the watch early
+func (c *KubernetesClient) WatchPipelineRuns(ctx context.Context, namespace, resourceVersion string, timeout time.Duration, handle func(*KubernetesPipelineRun)) errors.Error {
+	query := url.Values{}
+	query.Set("watch", "true")
+	query.Set("resourceVersion", resourceVersion)
+	query.Set("timeoutSeconds", fmt.Sprintf("%d", int(timeout.Seconds())))
+
+	// The server closes the stream after timeoutSeconds; the deadline only guards against a hung connection
+	watchCtx, cancel := context.WithTimeout(ctx, timeout+30*time.Second)
+	defer cancel()
+
+	resp, err := c.send(watchCtx, c.watchClient, pipelineRunsPath(namespace), query)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if statusErr := kubernetesStatusError(resp.StatusCode, fmt.Sprintf("PipelineRuns of namespace '%s'", namespace)); statusErr != nil {
+		return statusErr
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var event watchEvent
+		if decodeErr := decoder.Decode(&event); decodeErr != nil {
+			if decodeErr == io.EOF || watchCtx.Err() != nil {
+				return nil
+			}
+			return errors.Default.Wrap(decodeErr, "failed to read PipelineRun watch stream")
+		}
+
+		switch event.Type {
+		case "ADDED", "MODIFIED":
+			var run KubernetesPipelineRun
+			if unmarshalErr := json.Unmarshal(event.Object, &run); unmarshalErr != nil {
+				c.logger.Warn(unmarshalErr, "failed to parse PipelineRun watch event")
+				continue
+			}
+			handle(&run)
+		case "ERROR":
+			// Typically 410 Gone: the resourceVersion expired, the next collection lists again
+			c.logger.Warn(nil, "PipelineRun watch ended by the API server", "namespace", namespace, "status", string(event.Object))
+			return nil
+		}
+	}
+}
+
+// get sends an authenticated GET request to the API server
+func (c *KubernetesClient) get(ctx context.Context, path string, query url.Values) (*http.Response, errors.Error) {
+	return c.send(ctx, c.httpClient, path, query)
+}
+
+// send sends an authenticated GET request to the API server with the given HTTP client
+func (c *KubernetesClient) send(ctx context.Context, httpClient *http.Client, path string, query url.Values) (*http.Response, errors.Error) {
+	apiURL := c.apiServer + path
+	if len(query) > 0 {
+		apiURL += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to reach Kubernetes API server %s", c.apiServer))
+	}
+	return resp, nil
+}
+
+// pipelineRunsPath returns the API path of the PipelineRuns of a namespace
+func pipelineRunsPath(namespace string) string {
+	return fmt.Sprintf("/apis/tekton.dev/v1/namespaces/%s/pipelineruns", url.PathEscape(namespace))
+}
+
+// kubernetesStatusError turns a non-200 status of the Kubernetes API into an actionable error
+func kubernetesStatusError(status int, resource string) errors.Error {
+	switch status {
+	case http.StatusOK:
+		return nil
+	case http.StatusUnauthorized:
+		return errors.Unauthorized.New("the Kubernetes API rejected the token, check kubernetesToken or the mounted service account token")
+	case http.StatusForbidden:
+		return errors.Forbidden.New(fmt.Sprintf("the service account is not allowed to read %s, grant it get/list/watch on pipelineruns.tekton.dev", resource))
+	case http.StatusNotFound:
+		return errors.NotFound.New(fmt.Sprintf("%s not found, check that Tekton is installed and the namespace exists", resource))
+	default:
+		return errors.Default.New(fmt.Sprintf("Kubernetes API returned status %d for %s", status, resource))
+	}
+}
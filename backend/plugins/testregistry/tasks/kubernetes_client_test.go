@@ -0,0 +1,132 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newTestKubernetesClient(t *testing.T, handler http.HandlerFunc) *KubernetesClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	logger := new(mocklog.Logger)
+	logger.On("Info", mock.Anything, mock.Anything).Maybe()
+	logger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
+
+	client, err := NewKubernetesClient(&models.TestRegistryConnection{
+		KubernetesApiServer: server.URL + "/",
+		KubernetesToken:     "sa-token",
+	}, logger)
+	assert.Nil(t, err)
+	return client
+}
+
+func TestNewKubernetesClientRequiresApiServerOutsideCluster(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+	_, err := NewKubernetesClient(&models.TestRegistryConnection{KubernetesToken: "token"}, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.BadInput, err.GetType())
+}
+
+func TestNewKubernetesClientRequiresTokenForOtherApiServer(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("KUBERNETES_SERVICE_PORT", "443")
+	_, err := NewKubernetesClient(&models.TestRegistryConnection{KubernetesApiServer: "https://k8s.example.com"}, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.BadInput, err.GetType())
+	assert.Contains(t, err.Error(), "kubernetesToken is required")
+}
+
+func TestKubernetesClientListPipelineRuns(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer sa-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "/apis/tekton.dev/v1/namespaces/konflux-ci/pipelineruns", r.URL.Path)
+		if r.URL.Query().Get("continue") == "" {
+			fmt.Fprint(w, `{"metadata": {"resourceVersion": "10", "continue": "page2"}, "items": [{"metadata": {"name": "run-1"}}]}`)
+			return
+		}
+		assert.Equal(t, "page2", r.URL.Query().Get("continue"))
+		fmt.Fprint(w, `{"metadata": {"resourceVersion": "12"}, "items": [{"metadata": {"name": "run-2"}}]}`)
+	})
+
+	runs, resourceVersion, err := client.ListPipelineRuns(context.Background(), "konflux-ci")
+	assert.Nil(t, err)
+	assert.Equal(t, "12", resourceVersion)
+	if assert.Len(t, runs, 2) {
+		assert.Equal(t, "run-1", runs[0].Metadata.Name)
+		assert.Equal(t, "run-2", runs[1].Metadata.Name)
+	}
+}
+
+func TestKubernetesClientWatchPipelineRuns(t *testing.T) {
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.URL.Query().Get("watch"))
+		assert.Equal(t, "12", r.URL.Query().Get("resourceVersion"))
+		assert.Equal(t, "5", r.URL.Query().Get("timeoutSeconds"))
+		fmt.Fprintln(w, `{"type": "ADDED", "object": {"metadata": {"name": "run-3"}}}`)
+		fmt.Fprintln(w, `{"type": "BOOKMARK", "object": {"metadata": {"resourceVersion": "13"}}}`)
+		fmt.Fprintln(w, `{"type": "MODIFIED", "object": {"metadata": {"name": "run-3"}, "status": {"completionTime": "2024-06-18T11:20:06Z"}}}`)
+		fmt.Fprintln(w, `{"type": "DELETED", "object": {"metadata": {"name": "run-1"}}}`)
+	})
+
+	var names []string
+	err := client.WatchPipelineRuns(context.Background(), "konflux-ci", "12", 5*time.Second, func(run *KubernetesPipelineRun) {
+		names = append(names, run.Metadata.Name)
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"run-3", "run-3"}, names)
+
+	t.Run("expired resourceVersion ends the watch", func(t *testing.T) {
+		client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintln(w, `{"type": "ERROR", "object": {"kind": "Status", "code": 410, "reason": "Expired"}}`)
+			fmt.Fprintln(w, `{"type": "ADDED", "object": {"metadata": {"name": "run-4"}}}`)
+		})
+		called := false
+		err := client.WatchPipelineRuns(context.Background(), "konflux-ci", "1", time.Second, func(*KubernetesPipelineRun) { called = true })
+		assert.Nil(t, err)
+		assert.False(t, called)
+	})
+}
+
+func TestKubernetesStatusError(t *testing.T) {
+	assert.Nil(t, kubernetesStatusError(http.StatusOK, "PipelineRuns"))
+	assert.Equal(t, errors.Unauthorized, kubernetesStatusError(http.StatusUnauthorized, "PipelineRuns").GetType())
+	assert.Equal(t, errors.Forbidden, kubernetesStatusError(http.StatusForbidden, "PipelineRuns").GetType())
+	assert.Equal(t, errors.NotFound, kubernetesStatusError(http.StatusNotFound, "PipelineRuns").GetType())
+	assert.Contains(t, kubernetesStatusError(http.StatusInternalServerError, "PipelineRuns").Error(), "500")
+
+	client := newTestKubernetesClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/apis/tekton.dev/v1", r.URL.Path)
+		w.WriteHeader(http.StatusForbidden)
+	})
+	err := client.Ping(context.Background())
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "pipelineruns.tekton.dev")
+}
@@ -0,0 +1,230 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+// Label prefixes set on PipelineRuns by Pipelines as Code, and copied by the
+// Konflux integration service onto integration test PipelineRuns
+var pacLabelPrefixes = []string{"pipelinesascode.tekton.dev/", "pac.test.appstudio.openshift.io/"}
+
+// CollectKubernetesPipelineRunsMeta defines the metadata for the Kubernetes PipelineRun collection subtask
+var CollectKubernetesPipelineRunsMeta = plugin.SubTaskMeta{
+	Name:             "collectKubernetesPipelineRuns",
+	EntryPoint:       CollectKubernetesPipelineRuns,
+	EnabledByDefault: true,
+	Description:      "Collect finished Tekton PipelineRuns directly from the Kubernetes API for connections with tektonSource=kubernetes. Lists the scope namespace, then optionally watches it for kubernetesWatchSeconds to ingest runs as they finish.",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+}
+
+// CollectKubernetesPipelineRuns collects Tekton PipelineRuns from the cluster running them.
+//
+// This function:
+// 1. Validates that the connection is a Tekton CI connection with the Kubernetes source
+// 2. Lists the PipelineRuns of the scope namespace (the scope fullName)
+// 3. Saves raw data, CI jobs and task rows for finished runs not collected yet
+// 4. Watches the namespace for kubernetesWatchSeconds, saving runs as they finish
+//
+// List then watch is the sequence a client-go informer uses, bounded in time so
+// the subtask ends; scheduling the blueprint frequently keeps the data near real time.
+//
+// JUnit results are not available from the Kubernetes API, they are only
+// collected by the Quay.io artifact source or pushed through the push API.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered during listing, or nil if successful
+func CollectKubernetesPipelineRuns(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+
+	if !data.Connection.UsesKubernetes() {
+		logger.Debug("Connection does not read PipelineRuns from Kubernetes, skipping")
+		return nil
+	}
+
+	namespace := strings.TrimSpace(data.Options.FullName)
+	if namespace == "" {
+		return errors.BadInput.New("FullName (the namespace) is required")
+	}
+	logger.Info("Collecting Tekton PipelineRuns from Kubernetes", "namespace", namespace)
+	startedAt := time.Now()
+
+	rawDataSubTask, err := setupRawTektonDataCollection(taskCtx, data)
+	if err != nil {
+		return err
+	}
+
+	watcher := data.PipelineRunWatcherOverride
+	if watcher == nil {
+		client, err := NewKubernetesClient(data.Connection, logger)
+		if err != nil {
+			return err
+		}
+		watcher = client
+	}
+
+	ctx := taskCtx.GetContext()
+	runs, resourceVersion, err := watcher.ListPipelineRuns(ctx, namespace)
+	if err != nil {
+		return errors.Default.Wrap(err, fmt.Sprintf("failed to list PipelineRuns of namespace %s", namespace))
+	}
+
+	db := taskCtx.GetDal()
+	rawTable := rawDataSubTask.GetTable()
+	rawParams := rawDataSubTask.GetParams()
+	apiURL := fmt.Sprintf("kubernetes://%s/pipelineruns", namespace)
+	since := tektonCollectionSince(scopeSyncPolicy(taskCtx.TaskContext().SyncPolicy(), data.Options.ScopeConfig, time.Now()))
+
+	stats := collectionStats{}
+	collected := make(map[string]bool)
+	ingest := func(run *KubernetesPipelineRun) {
+		pipelineRun := kubernetesToTektonPipelineRun(run)
+		if pipelineRun == nil {
+			return // still running
+		}
+		if run.Status.CompletionTime.Before(*since) {
+			return
+		}
+		jobId := pipelineRun.PipelineRunName
+		if collected[jobId] || isTektonJobAlreadyProcessed(db, data.Options.ConnectionId, jobId) {
+			return
+		}
+		collected[jobId] = true
+		saveTektonPipelineRun(db, logger, data, pipelineRun, nil, rawParams, rawTable, apiURL, namespace, namespace, &stats)
+	}
+
+	taskCtx.SetProgress(0, len(runs))
+	for i := range runs {
+		ingest(&runs[i])
+		taskCtx.IncProgress(1)
+	}
+
+	if watchSeconds := data.Connection.KubernetesWatchSeconds; watchSeconds > 0 {
+		logger.Info("Watching PipelineRuns", "namespace", namespace, "seconds", watchSeconds)
+		if err := watcher.WatchPipelineRuns(ctx, namespace, resourceVersion, time.Duration(watchSeconds)*time.Second, ingest); err != nil {
+			// Runs missed here are picked up by the list of the next collection
+			logger.Warn(err, "PipelineRun watch failed", "namespace", namespace)
+		}
+	}
+
+	logger.Info("Completed Kubernetes PipelineRun collection", "namespace", namespace, "listed", len(runs), "jobs_saved", stats.savedCount, "raw_records_saved", stats.rawSavedCount, "filtered", stats.filteredCount)
+	recordCollectionRun(db, logger, data, startedAt, len(runs), len(collected), stats)
+	return nil
+}
+
+// kubernetesToTektonPipelineRun converts a PipelineRun object to the pipeline-status.json
+// structure stored in Quay.io artifacts, so both sources share the same conversion
+//
+// Parameters:
+//   - run: The PipelineRun object read from the Kubernetes API
+//
+// Returns:
+//   - *TektonPipelineRun: The converted PipelineRun, or nil if the run has not finished
+func kubernetesToTektonPipelineRun(run *KubernetesPipelineRun) *TektonPipelineRun {
+	if run.Status.CompletionTime == nil {
+		return nil
+	}
+	status, reason := "", ""
+	for _, condition := range run.Status.Conditions {
+		if condition.Type != "Succeeded" {
+			continue
+		}
+		reason = condition.Reason
+		switch {
+		case condition.Status == "True":
+			status = "Succeeded"
+		case condition.Status == "False" && (strings.HasPrefix(condition.Reason, "Cancelled") || condition.Reason == "StoppedRunFinally"):
+			status = "Cancelled"
+		case condition.Status == "False":
+			status = "Failed"
+		}
+	}
+	if status == "" {
+		return nil
+	}
+
+	labels := run.Metadata.Labels
+	pipelineRun := &TektonPipelineRun{
+		PipelineRunName: run.Metadata.Name,
+		Namespace:       run.Metadata.Namespace,
+		Status:          status,
+		Reason:          reason,
+		EventType:       pacLabel(labels, "event-type"),
+		Scenario:        firstNonEmpty(labels["test.appstudio.openshift.io/scenario"], labels["tekton.dev/pipeline"], pacLabel(labels, "original-prname"), run.Metadata.Name),
+		ConsoleUrl:      pacLabel(run.Metadata.Annotations, "log-url"),
+		Git: TektonGitInfo{
+			GitOrganization:   pacLabel(labels, "url-org"),
+			GitRepository:     pacLabel(labels, "url-repository"),
+			PullRequestNumber: pacLabel(labels, "pull-request"),
+			CommitSha:         pacLabel(labels, "sha"),
+			PullRequestAuthor: pacLabel(labels, "sender"),
+		},
+		Timestamps: TektonTimestamps{
+			FinishedAt: run.Status.CompletionTime.UTC().Format(time.RFC3339),
+		},
+	}
+	if run.Metadata.CreationTimestamp != nil {
+		pipelineRun.Timestamps.CreatedAt = run.Metadata.CreationTimestamp.UTC().Format(time.RFC3339)
+	}
+	if run.Status.StartTime != nil {
+		pipelineRun.Timestamps.StartedAt = run.Status.StartTime.UTC().Format(time.RFC3339)
+		pipelineRun.Duration = fmt.Sprintf("%.0fs", run.Status.CompletionTime.Sub(*run.Status.StartTime).Seconds())
+	}
+	return pipelineRun
+}
+
+// pacLabel returns the Pipelines as Code label or annotation with the given name, whichever prefix it uses
+func pacLabel(values map[string]string, name string) string {
+	for _, prefix := range pacLabelPrefixes {
+		if value := values[prefix+name]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// tektonCollectionSince returns the start of the collection window from the sync policy,
+// defaulting to the last 6 months
+func tektonCollectionSince(syncPolicy *coreModels.SyncPolicy) *time.Time {
+	if syncPolicy != nil && syncPolicy.TimeAfter != nil {
+		return syncPolicy.TimeAfter
+	}
+	sixMonthsAgo := time.Now().AddDate(0, -6, 0)
+	return &sixMonthsAgo
+}
@@ -0,0 +1,147 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func kubernetesPipelineRun(t *testing.T, raw string) *KubernetesPipelineRun {
+	var run KubernetesPipelineRun
+	assert.NoError(t, json.Unmarshal([]byte(raw), &run))
+	return &run
+}
+
+func TestKubernetesToTektonPipelineRun(t *testing.T) {
+	t.Run("integration test PipelineRun", func(t *testing.T) {
+		run := kubernetesPipelineRun(t, `{
+			"metadata": {
+				"name": "konflux-e2e-z28lw",
+				"namespace": "konflux-ci",
+				"creationTimestamp": "2024-06-18T10:15:30Z",
+				"labels": {
+					"test.appstudio.openshift.io/scenario": "konflux-e2e",
+					"tekton.dev/pipeline": "e2e-pipeline",
+					"pac.test.appstudio.openshift.io/event-type": "pull_request",
+					"pac.test.appstudio.openshift.io/url-org": "konflux-ci",
+					"pac.test.appstudio.openshift.io/url-repository": "integration-service",
+					"pac.test.appstudio.openshift.io/pull-request": "1315",
+					"pac.test.appstudio.openshift.io/sha": "b4f3f3f",
+					"pac.test.appstudio.openshift.io/sender": "bot-konflux"
+				},
+				"annotations": {"pac.test.appstudio.openshift.io/log-url": "https://console.example.com/run"}
+			},
+			"status": {
+				"startTime": "2024-06-18T10:16:00Z",
+				"completionTime": "2024-06-18T11:20:06Z",
+				"conditions": [{"type": "Succeeded", "status": "False", "reason": "Failed"}]
+			}
+		}`)
+
+		pipelineRun := kubernetesToTektonPipelineRun(run)
+		assert.NotNil(t, pipelineRun)
+		assert.Equal(t, &TektonPipelineRun{
+			PipelineRunName: "konflux-e2e-z28lw",
+			Namespace:       "konflux-ci",
+			Duration:        "3846s",
+			Status:          "Failed",
+			Reason:          "Failed",
+			EventType:       "pull_request",
+			Scenario:        "konflux-e2e",
+			ConsoleUrl:      "https://console.example.com/run",
+			Git: TektonGitInfo{
+				GitOrganization:   "konflux-ci",
+				GitRepository:     "integration-service",
+				PullRequestNumber: "1315",
+				CommitSha:         "b4f3f3f",
+				PullRequestAuthor: "bot-konflux",
+			},
+			Timestamps: TektonTimestamps{
+				CreatedAt:  "2024-06-18T10:15:30Z",
+				StartedAt:  "2024-06-18T10:16:00Z",
+				FinishedAt: "2024-06-18T11:20:06Z",
+			},
+		}, pipelineRun)
+
+		ciJob, err := convertTektonPipelineRunToCIJob(pipelineRun, 1, "konflux-ci", "konflux-ci", "konflux-ci", nil)
+		assert.Nil(t, err)
+		assert.Empty(t, validateRequiredCIJobFields(ciJob))
+		assert.Equal(t, "FAILURE", ciJob.Result)
+		assert.Equal(t, 1315, *ciJob.PullRequestNumber)
+	})
+
+	t.Run("status mapping", func(t *testing.T) {
+		cases := []struct {
+			status   string
+			reason   string
+			expected string
+		}{
+			{"True", "Succeeded", "Succeeded"},
+			{"True", "Completed", "Succeeded"},
+			{"False", "Failed", "Failed"},
+			{"False", "PipelineRunTimeout", "Failed"},
+			{"False", "Cancelled", "Cancelled"},
+			{"False", "CancelledRunFinally", "Cancelled"},
+			{"False", "StoppedRunFinally", "Cancelled"},
+		}
+		for _, tt := range cases {
+			run := &KubernetesPipelineRun{}
+			completion := time.Now()
+			run.Metadata.Name = "run"
+			run.Status.CompletionTime = &completion
+			run.Status.Conditions = append(run.Status.Conditions, struct {
+				Type   string `json:"type"`
+				Status string `json:"status"`
+				Reason string `json:"reason"`
+			}{"Succeeded", tt.status, tt.reason})
+			pipelineRun := kubernetesToTektonPipelineRun(run)
+			if assert.NotNil(t, pipelineRun, tt.reason) {
+				assert.Equal(t, tt.expected, pipelineRun.Status, tt.reason)
+				assert.Equal(t, "run", pipelineRun.Scenario, "falls back to the PipelineRun name")
+			}
+		}
+	})
+
+	t.Run("running PipelineRuns are skipped", func(t *testing.T) {
+		assert.Nil(t, kubernetesToTektonPipelineRun(kubernetesPipelineRun(t, `{
+			"metadata": {"name": "run"},
+			"status": {"startTime": "2024-06-18T10:16:00Z", "conditions": [{"type": "Succeeded", "status": "Unknown", "reason": "Running"}]}
+		}`)))
+		assert.Nil(t, kubernetesToTektonPipelineRun(kubernetesPipelineRun(t, `{
+			"metadata": {"name": "run"},
+			"status": {"completionTime": "2024-06-18T10:16:00Z", "conditions": [{"type": "Succeeded", "status": "Unknown"}]}
+		}`)))
+	})
+}
+
+func TestPacLabel(t *testing.T) {
+	labels := map[string]string{
+		"pipelinesascode.tekton.dev/event-type":     "push",
+		"pac.test.appstudio.openshift.io/url-org":   "konflux-ci",
+		"pipelinesascode.tekton.dev/url-repository": "build-service",
+	}
+	assert.Equal(t, "push", pacLabel(labels, "event-type"))
+	assert.Equal(t, "konflux-ci", pacLabel(labels, "url-org"))
+	assert.Equal(t, "build-service", pacLabel(labels, "url-repository"))
+	assert.Empty(t, pacLabel(labels, "sha"))
+	assert.Empty(t, pacLabel(nil, "sha"))
+}
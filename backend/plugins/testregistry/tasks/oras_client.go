@@ -21,46 +21,71 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
+	stderrors "errors"
 	"fmt"
+	"net/http"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+	"oras.land/oras-go/v2/registry/remote/retry"
 )
 
-// ORASClient wraps the ORAS CLI tool for pulling OCI artifacts from Quay.io
+const (
+	// orasPullTimeout bounds one attempt to pull an artifact, all its layers included
+	orasPullTimeout = 10 * time.Minute
+	// orasPullAttempts is how many times a pull is tried before the artifact is given up
+	orasPullAttempts = 3
+	// orasRetryDelay is the wait before the second attempt, doubled before each further one
+	orasRetryDelay = 5 * time.Second
+)
+
+// ORASClient pulls OCI artifacts from Quay.io in-process with the oras-go library
 // Similar to the qe-tools controller: https://github.com/konflux-ci/qe-tools/blob/main/pkg/oci/controller.go
-// Uses the ORAS CLI tool (oras pull) to pull artifacts from Quay.io
+// Registry requests are retried on 429 and 5xx answers by the oras-go retry transport, and a
+// failed pull is tried again up to orasPullAttempts times, each bounded by orasPullTimeout
 type ORASClient struct {
 	registryURL string
 	repoPath    string
 	loggingDir  string
 	logger      log.Logger
-	orasPath    string // Path to oras executable (default: "oras")
+	repo        *remote.Repository
+
+	pullTimeout time.Duration
+	retryDelay  time.Duration
 }
 
-// NewORASClient creates a new ORAS client that uses the ORAS CLI tool
+// NewORASClient creates a new ORAS client for a repository of the registry
 //
 // Parameters:
 //   - ctx: Context for the operation
 //   - registryURL: Registry URL (e.g., "quay.io")
 //   - repoPath: Repository path (e.g., "org/repo")
-//   - loggingDir: Directory to store pulled artifacts (from LOGGING_DIR env var)
+//   - loggingDir: Directory to store pulled artifacts, usually the run working directory
+//     (falls back to {LOGGING_DIR}/tmp when empty)
+//   - connection: Connection holding the Quay.io credentials, nil or without credentials for anonymous pulls
 //   - logger: Logger for output
 //
 // Returns:
 //   - *ORASClient: The ORAS client instance
 //   - errors.Error: Any error encountered during client creation
-func NewORASClient(ctx context.Context, registryURL, repoPath, loggingDir string, logger log.Logger) (*ORASClient, errors.Error) {
+func NewORASClient(ctx context.Context, registryURL, repoPath, loggingDir string, connection *models.TestRegistryConnection, logger log.Logger) (*ORASClient, errors.Error) {
 	if loggingDir == "" {
 		// Fallback to LOGGING_DIR environment variable or default
-		loggingDir = os.Getenv("LOGGING_DIR")
-		if loggingDir == "" {
-			loggingDir = "/app/logs"
-		}
+		loggingDir = filepath.Join(LoggingDir(), "tmp")
 	}
 
 	// Ensure logging directory exists
@@ -68,21 +93,65 @@ func NewORASClient(ctx context.Context, registryURL, repoPath, loggingDir string
 		return nil, errors.Default.Wrap(err, "failed to create logging directory")
 	}
 
-	// Check if oras is available globally in PATH
-	orasPath, err := exec.LookPath("oras")
+	repo, err := remote.NewRepository(fmt.Sprintf("%s/%s", registryURL, repoPath))
 	if err != nil {
-		return nil, errors.Default.Wrap(err, "oras CLI not found in PATH. Please ensure ORAS CLI is installed.")
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid OCI repository %s/%s", registryURL, repoPath))
+	}
+	client := &auth.Client{
+		Client: retry.DefaultClient,
+		Cache:  auth.NewCache(),
 	}
+	if connection != nil {
+		// Private repositories need a registry login; the credential is only sent to this registry
+		if username, password := connection.QuayRegistryCredential(); password != "" {
+			client.Credential = auth.StaticCredential(repo.Reference.Registry, auth.Credential{Username: username, Password: password})
+		}
+	}
+	repo.Client = client
 
 	return &ORASClient{
 		registryURL: registryURL,
 		repoPath:    repoPath,
 		loggingDir:  loggingDir,
 		logger:      logger,
-		orasPath:    orasPath,
+		repo:        repo,
+		pullTimeout: orasPullTimeout,
+		retryDelay:  orasRetryDelay,
 	}, nil
 }
 
+// CheckRegistryLogin checks that a username and password can log in to the registry
+//
+// Parameters:
+//   - ctx: Context for the operation
+//   - registryURL: Registry URL (e.g., "quay.io")
+//   - username: Registry username, e.g. a Quay.io robot account
+//   - password: Registry password or token
+//
+// Returns:
+//   - errors.Error: Unauthorized if the registry rejects the credentials, nil if the login succeeds
+func CheckRegistryLogin(ctx context.Context, registryURL, username, password string) errors.Error {
+	if username == "" || password == "" {
+		return errors.BadInput.New("a registry login needs both a username and a token")
+	}
+	registry, err := remote.NewRegistry(registryURL)
+	if err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid registry %s", registryURL))
+	}
+	registry.Client = &auth.Client{
+		Client:     retry.DefaultClient,
+		Cache:      auth.NewCache(),
+		Credential: auth.StaticCredential(registry.Reference.Registry, auth.Credential{Username: username, Password: password}),
+	}
+	if err := registry.Ping(ctx); err != nil {
+		if stderrors.Is(err, auth.ErrBasicCredentialNotFound) || strings.Contains(err.Error(), "401") {
+			return errors.Unauthorized.Wrap(err, fmt.Sprintf("%s rejected the login of %s", registryURL, username))
+		}
+		return errors.Default.Wrap(err, fmt.Sprintf("failed to log in to %s", registryURL))
+	}
+	return nil
+}
+
 // generateUUID generates a unique identifier using crypto/rand
 // Returns a hex-encoded string (16 bytes = 32 hex characters)
 func generateUUID() (string, errors.Error) {
@@ -93,21 +162,21 @@ func generateUUID() (string, errors.Error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// PullArtifact pulls an OCI artifact from Quay.io using ORAS CLI and stores it in a unique tmp directory
+// PullArtifact pulls an OCI artifact from Quay.io and stores its files in a unique directory
 //
 // This method:
 // 1. Generates a unique UUID for this artifact pull
-// 2. Creates a tmp/{uuid} directory for storing the artifact
-// 3. Uses `oras pull` command to pull the artifact from the registry
-// 4. Returns the local path where artifacts were stored (tmp/{uuid})
+// 2. Creates a {loggingDir}/{uuid} directory for storing the artifact
+// 3. Copies the artifact into that directory like `oras pull`, one file per layer named by its title annotation
+// 4. Returns the local path where artifacts were stored ({loggingDir}/{uuid})
 //
 // Parameters:
 //   - ctx: Context for the operation
 //   - ref: Artifact reference (tag, digest, or "latest")
 //
 // Returns:
-//   - string: Local directory path where artifacts were stored (tmp/{uuid})
-//   - errors.Error: Any error encountered during pull operation
+//   - string: Local directory path where artifacts were stored ({loggingDir}/{uuid})
+//   - errors.Error: Any error encountered during pull operation, errors.NotFound if the tag no longer exists
 func (c *ORASClient) PullArtifact(ctx context.Context, ref string) (string, errors.Error) {
 	if ref == "" {
 		ref = "latest"
@@ -119,89 +188,188 @@ func (c *ORASClient) PullArtifact(ctx context.Context, ref string) (string, erro
 		return "", err
 	}
 
-	// Create unique directory for this artifact: tmp/{uuid}
-	tmpBaseDir := filepath.Join(c.loggingDir, "tmp")
-	artifactDir := filepath.Join(tmpBaseDir, uuid)
-	if mkdirErr := os.MkdirAll(artifactDir, 0755); mkdirErr != nil {
-		return "", errors.Default.Wrap(mkdirErr, "failed to create artifact directory")
+	// Create unique directory for this artifact: {loggingDir}/{uuid}
+	artifactDir := filepath.Join(c.loggingDir, uuid)
+	artifactRef := artifactReference(c.registryURL, c.repoPath, ref)
+	c.logger.Info("Pulling OCI artifact %s into %s", artifactRef, artifactDir)
+
+	delay := c.retryDelay
+	var pullErr error
+	for attempt := 1; attempt <= orasPullAttempts; attempt++ {
+		if attempt > 1 {
+			c.logger.Warn(pullErr, "pulling OCI artifact %s failed, retrying in %s (attempt %d of %d)", artifactRef, delay, attempt, orasPullAttempts)
+			select {
+			case <-ctx.Done():
+				return "", errors.Default.Wrap(ctx.Err(), fmt.Sprintf("pulling %s was canceled", artifactRef))
+			case <-time.After(delay):
+			}
+			delay *= 2
+		}
+
+		var progress pullProgress
+		progress, pullErr = c.pullOnce(ctx, ref, artifactDir)
+		if pullErr == nil {
+			c.logger.Info("Pulled OCI artifact %s: %d files, %d bytes", artifactRef, progress.files, progress.bytes)
+			return artifactDir, nil
+		}
+		_ = os.RemoveAll(artifactDir)
+		if isArtifactNotFound(pullErr) {
+			// The tag expired between listing and pulling; callers skip it quietly
+			return "", errors.NotFound.Wrap(pullErr, fmt.Sprintf("artifact %s no longer exists", artifactRef))
+		}
+		if ctx.Err() != nil {
+			break
+		}
 	}
 
-	// Build artifact reference
-	artifactRef := fmt.Sprintf("%s/%s:%s", c.registryURL, c.repoPath, ref)
+	c.logger.Error(pullErr, "failed to pull OCI artifact %s", artifactRef)
+	return "", errors.Default.Wrap(pullErr, fmt.Sprintf("failed to pull OCI artifact %s", artifactRef))
+}
 
-	c.logger.Info("Pulling OCI artifact using ORAS CLI", "artifact", artifactRef, "target", artifactDir, "uuid", uuid)
+// pullProgress counts the files written by a pull
+type pullProgress struct {
+	files int
+	bytes int64
+}
+
+// pullOnce copies the artifact into artifactDir within the pull timeout, logging each file written
+func (c *ORASClient) pullOnce(ctx context.Context, ref, artifactDir string) (pullProgress, error) {
+	var progress pullProgress
+	if err := os.MkdirAll(artifactDir, 0755); err != nil {
+		return progress, err
+	}
+	store, err := file.New(artifactDir)
+	if err != nil {
+		return progress, err
+	}
+	defer store.Close()
 
-	// Execute oras pull command
-	// oras pull quay.io/org/repo:tag -o /path/to/output
-	cmd := exec.CommandContext(ctx, c.orasPath, "pull", artifactRef, "-o", artifactDir)
+	ctx, cancel := context.WithTimeout(ctx, c.pullTimeout)
+	defer cancel()
 
-	// Capture output for logging
-	output, execErr := cmd.CombinedOutput()
-	if execErr != nil {
-		outputStr := string(output)
-		c.logger.Error(execErr, "failed to pull artifact with ORAS CLI", "artifact", artifactRef, "output", outputStr, "uuid", uuid)
-		return "", errors.Default.Wrap(execErr, fmt.Sprintf("oras pull failed: %s", outputStr))
+	opts := oras.DefaultCopyOptions
+	opts.PreCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		// Like `oras pull`, only named layers are written; manifests are needed to walk the artifact
+		if desc.Annotations[ocispec.AnnotationTitle] == "" && !isManifestMediaType(desc.MediaType) {
+			return oras.SkipNode
+		}
+		return nil
+	}
+	opts.PostCopy = func(ctx context.Context, desc ocispec.Descriptor) error {
+		if name := desc.Annotations[ocispec.AnnotationTitle]; name != "" {
+			progress.files++
+			progress.bytes += desc.Size
+			c.logger.Debug("Pulled %s (%d bytes) of %s", name, desc.Size, ref)
+		}
+		return nil
 	}
+	_, err = oras.Copy(ctx, c.repo, ref, store, ref, opts)
+	return progress, err
+}
 
-	c.logger.Info("Successfully pulled OCI artifact", "artifact", artifactRef, "local_path", artifactDir, "uuid", uuid, "output", string(output))
-	return artifactDir, nil
+// isManifestMediaType reports whether a descriptor points at an OCI or Docker manifest or index
+func isManifestMediaType(mediaType string) bool {
+	switch mediaType {
+	case ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageIndex,
+		"application/vnd.docker.distribution.manifest.v2+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json":
+		return true
+	}
+	return false
 }
 
-// ListArtifacts lists available artifacts (tags) in the Quay.io repository
-// Uses Quay.io REST API since ORAS CLI doesn't have a direct tag listing command
+// FetchAnnotations fetches the manifest of an OCI artifact and returns its annotations,
+// without pulling the artifact layers
 //
 // Parameters:
 //   - ctx: Context for the operation
+//   - ref: Artifact reference (tag or digest)
 //
 // Returns:
-//   - []string: List of available artifact tags/refs
-//   - errors.Error: Any error encountered during listing
-func (c *ORASClient) ListArtifacts(ctx context.Context) ([]string, errors.Error) {
-	// Quay.io API endpoint for listing tags
-	tagsURL := fmt.Sprintf("https://quay.io/api/v1/repository/%s/tag/", c.repoPath)
-
-	// Use curl or http client to fetch tags
-	// For simplicity, we'll use exec with curl (or we could use http.Client)
-	cmd := exec.CommandContext(ctx, "curl", "-s", tagsURL)
-
-	output, err := cmd.Output()
+//   - map[string]string: The manifest annotations, empty if the manifest has none
+//   - errors.Error: Any error encountered fetching or parsing the manifest
+func (c *ORASClient) FetchAnnotations(ctx context.Context, ref string) (map[string]string, errors.Error) {
+	artifactRef := artifactReference(c.registryURL, c.repoPath, ref)
+	desc, reader, err := c.repo.FetchReference(ctx, ref)
 	if err != nil {
-		// Fallback: return "latest" if we can't list tags
-		c.logger.Warn(err, "failed to list tags from Quay.io API, will use 'latest'", "url", tagsURL)
-		return []string{"latest"}, nil
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to fetch the manifest of %s", artifactRef))
 	}
+	defer reader.Close()
+	manifest, err := content.ReadAll(reader, desc)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to read the manifest of %s", artifactRef))
+	}
+	return parseManifestAnnotations(manifest)
+}
 
-	// Simple JSON parsing (we could use encoding/json but this is simpler for now)
-	// For production, we should properly parse the JSON
-	outputStr := string(output)
-	var tagList []string
+// parseManifestAnnotations reads the annotations of an OCI image manifest
+func parseManifestAnnotations(manifest []byte) (map[string]string, errors.Error) {
+	var parsed struct {
+		Annotations map[string]string `json:"annotations"`
+	}
+	if err := json.Unmarshal(manifest, &parsed); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to parse OCI manifest")
+	}
+	if parsed.Annotations == nil {
+		return map[string]string{}, nil
+	}
+	return parsed.Annotations, nil
+}
 
-	// Simple extraction of tag names from JSON
-	// This is a basic implementation - should use proper JSON parsing
-	if strings.Contains(outputStr, `"name"`) {
-		lines := strings.Split(outputStr, "\n")
-		for _, line := range lines {
-			if strings.Contains(line, `"name"`) && strings.Contains(line, ":") {
-				parts := strings.Split(line, `"name":`)
-				if len(parts) > 1 {
-					namePart := strings.TrimSpace(parts[1])
-					namePart = strings.Trim(namePart, `"`)
-					namePart = strings.Trim(namePart, `,`)
-					namePart = strings.TrimSpace(namePart)
-					if namePart != "" && !strings.Contains(namePart, "{") {
-						tagList = append(tagList, namePart)
-					}
-				}
-			}
+// artifactReference builds the ORAS reference of a tag or digest of the repository.
+// Tags can't contain ':', so a ref that does is a digest (sha256:...) and is joined with '@'.
+func artifactReference(registryURL, repoPath, ref string) string {
+	if strings.Contains(ref, ":") {
+		return fmt.Sprintf("%s/%s@%s", registryURL, repoPath, ref)
+	}
+	return fmt.Sprintf("%s/%s:%s", registryURL, repoPath, ref)
+}
+
+// isArtifactNotFound reports whether a pull failed because the registry has no manifest for the
+// reference, which Quay.io answers with 404 MANIFEST_UNKNOWN once a tag has expired. ORAS reports
+// it as errdef.ErrNotFound when resolving the tag and as an errcode.ErrorResponse when fetching it
+func isArtifactNotFound(err error) bool {
+	if stderrors.Is(err, errdef.ErrNotFound) {
+		return true
+	}
+	var errResp *errcode.ErrorResponse
+	if !stderrors.As(err, &errResp) {
+		return false
+	}
+	if errResp.StatusCode == http.StatusNotFound {
+		return true
+	}
+	for _, e := range errResp.Errors {
+		if e.Code == errcode.ErrorCodeManifestUnknown {
+			return true
 		}
 	}
+	return false
+}
 
-	// Fallback to "latest" if no tags found
+// ListArtifacts lists the tags of the repository with the registry tag list API
+// Falls back to "latest" when the tags can't be listed
+//
+// Parameters:
+//   - ctx: Context for the operation
+//
+// Returns:
+//   - []string: List of available artifact tags/refs
+//   - errors.Error: Any error encountered during listing
+func (c *ORASClient) ListArtifacts(ctx context.Context) ([]string, errors.Error) {
+	var tagList []string
+	err := c.repo.Tags(ctx, "", func(tags []string) error {
+		tagList = append(tagList, tags...)
+		return nil
+	})
+	if err != nil {
+		c.logger.Warn(err, "failed to list the tags of %s/%s, will use 'latest'", c.registryURL, c.repoPath)
+		return []string{"latest"}, nil
+	}
 	if len(tagList) == 0 {
-		c.logger.Info("No tags found or failed to parse, using 'latest'", "output_preview", outputStr[:min(200, len(outputStr))])
+		c.logger.Info("No tags found in %s/%s, using 'latest'", c.registryURL, c.repoPath)
 		return []string{"latest"}, nil
 	}
-
 	return tagList, nil
 }
 
@@ -216,7 +384,10 @@ func (c *ORASClient) ListArtifacts(ctx context.Context) ([]string, errors.Error)
 //   - []byte: The file content
 //   - errors.Error: Any error encountered during retrieval
 func (c *ORASClient) GetArtifactContent(ctx context.Context, artifactPath, filePath string) ([]byte, errors.Error) {
-	fullPath := filepath.Join(artifactPath, filePath)
+	fullPath, pathErr := safeArtifactPath(artifactPath, filePath)
+	if pathErr != nil {
+		return nil, pathErr
+	}
 	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to read file %s", fullPath))
@@ -225,7 +396,7 @@ func (c *ORASClient) GetArtifactContent(ctx context.Context, artifactPath, fileP
 }
 
 // ExtractArtifactFiles lists all files extracted from an OCI artifact
-// ORAS CLI extracts files automatically to the output directory
+// PullArtifact writes the files of the artifact to its output directory
 //
 // Parameters:
 //   - ctx: Context for the operation
@@ -244,7 +415,7 @@ func (c *ORASClient) ExtractArtifactFiles(ctx context.Context, artifactPath, tar
 			return err // Continue on error
 		}
 
-		if !info.IsDir() {
+		if info.Mode().IsRegular() {
 			// Get relative path from targetDir
 			relPath, err := filepath.Rel(targetDir, path)
 			if err != nil {
@@ -262,11 +433,3 @@ func (c *ORASClient) ExtractArtifactFiles(ctx context.Context, artifactPath, tar
 	c.logger.Info("Found extracted files in artifact", "artifact_path", artifactPath, "file_count", len(extractedFiles))
 	return extractedFiles, nil
 }
-
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
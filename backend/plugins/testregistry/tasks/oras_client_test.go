@@ -18,9 +18,26 @@ limitations under the License.
 package tasks
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
 )
 
 func TestGenerateUUID(t *testing.T) {
@@ -37,10 +54,213 @@ func TestGenerateUUID(t *testing.T) {
 	})
 }
 
-func TestMin(t *testing.T) {
-	assert.Equal(t, 3, min(3, 5))
-	assert.Equal(t, 3, min(5, 3))
-	assert.Equal(t, 3, min(3, 3))
-	assert.Equal(t, 0, min(0, 1))
-	assert.Equal(t, -1, min(-1, 0))
+func TestIsArtifactNotFound(t *testing.T) {
+	assert.True(t, isArtifactNotFound(fmt.Errorf("run-1: %w", errdef.ErrNotFound)))
+	assert.True(t, isArtifactNotFound(fmt.Errorf("failed to resolve run-1: %w", &errcode.ErrorResponse{
+		Method:     http.MethodGet,
+		StatusCode: http.StatusNotFound,
+		Errors:     errcode.Errors{{Code: errcode.ErrorCodeManifestUnknown, Message: "manifest unknown"}},
+	})))
+	assert.True(t, isArtifactNotFound(&errcode.ErrorResponse{
+		Method:     http.MethodGet,
+		StatusCode: http.StatusBadRequest,
+		Errors:     errcode.Errors{{Code: errcode.ErrorCodeManifestUnknown}},
+	}))
+	assert.False(t, isArtifactNotFound(&errcode.ErrorResponse{Method: http.MethodGet, StatusCode: http.StatusUnauthorized}))
+	assert.False(t, isArtifactNotFound(fmt.Errorf("dial tcp: lookup quay.io: no such host")))
+	assert.False(t, isArtifactNotFound(fmt.Errorf("Error: quay.io/org/repo:run-1: not found")), "messages are not matched")
+}
+
+func TestArtifactReference(t *testing.T) {
+	assert.Equal(t, "quay.io/org/repo:run-1", artifactReference("quay.io", "org/repo", "run-1"))
+	assert.Equal(t, "quay.io/org/repo@sha256:0a1b2c", artifactReference("quay.io", "org/repo", "sha256:0a1b2c"))
+}
+
+func TestParseManifestAnnotations(t *testing.T) {
+	annotations, err := parseManifestAnnotations([]byte(`{"schemaVersion":2,"annotations":{"appstudio.openshift.io/application":"release-service"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"appstudio.openshift.io/application": "release-service"}, annotations)
+
+	annotations, err = parseManifestAnnotations([]byte(`{"schemaVersion":2}`))
+	assert.Nil(t, err)
+	assert.Empty(t, annotations)
+
+	_, err = parseManifestAnnotations([]byte("Error: not found"))
+	assert.NotNil(t, err)
+}
+
+// fakeRegistry serves the manifests, blobs and tags of one repository over the OCI distribution API
+type fakeRegistry struct {
+	manifests map[string][]byte // by tag and digest
+	blobs     map[string][]byte // by digest
+	tags      []string
+	failures  int    // manifest requests answered with 500 before the registry recovers
+	login     string // "username:password" required with basic auth, empty for anonymous access
+}
+
+func (r *fakeRegistry) addBlob(content []byte) ocispec.Descriptor {
+	blobDigest := digest.FromBytes(content)
+	r.blobs[blobDigest.String()] = content
+	return ocispec.Descriptor{MediaType: "application/octet-stream", Digest: blobDigest, Size: int64(len(content))}
+}
+
+func (r *fakeRegistry) addManifest(tag string, manifest ocispec.Manifest) {
+	manifest.Versioned.SchemaVersion = 2
+	manifest.MediaType = ocispec.MediaTypeImageManifest
+	content, _ := json.Marshal(manifest)
+	r.manifests[tag] = content
+	r.manifests[digest.FromBytes(content).String()] = content
+	r.tags = append(r.tags, tag)
+}
+
+func (r *fakeRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.login != "" {
+		if username, password, ok := req.BasicAuth(); !ok || username+":"+password != r.login {
+			w.Header().Set("WWW-Authenticate", `Basic realm="fake"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	path := req.URL.Path
+	switch {
+	case path == "/v2/":
+		w.WriteHeader(http.StatusOK)
+	case strings.HasSuffix(path, "/tags/list"):
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "org/repo", "tags": r.tags})
+	case strings.Contains(path, "/manifests/"):
+		if r.failures > 0 {
+			r.failures--
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		content, ok := r.manifests[path[strings.LastIndex(path, "/")+1:]]
+		if !ok {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[{"code":"MANIFEST_UNKNOWN","message":"manifest unknown"}]}`))
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(content).String())
+		w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+		if req.Method != http.MethodHead {
+			_, _ = w.Write(content)
+		}
+	case strings.Contains(path, "/blobs/"):
+		content, ok := r.blobs[path[strings.LastIndex(path, "/")+1:]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(content)))
+		_, _ = w.Write(content)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// newFakeRegistryClient starts a fake registry holding the run-1 artifact and returns a client for it
+func newFakeRegistryClient(t *testing.T) (*ORASClient, *fakeRegistry) {
+	registry := &fakeRegistry{manifests: map[string][]byte{}, blobs: map[string][]byte{}}
+	config := registry.addBlob([]byte("{}"))
+	config.MediaType = ocispec.MediaTypeEmptyJSON
+	status := registry.addBlob([]byte(`{"status":"Succeeded"}`))
+	status.Annotations = map[string]string{ocispec.AnnotationTitle: "pipeline-status.json"}
+	unnamed := registry.addBlob([]byte("unnamed layer"))
+	registry.addManifest("run-1", ocispec.Manifest{
+		Config:      config,
+		Layers:      []ocispec.Descriptor{status, unnamed},
+		Annotations: map[string]string{"appstudio.openshift.io/application": "release-service"},
+	})
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+
+	logger := newMockLogger()
+	logger.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	client, err := NewORASClient(context.Background(), strings.TrimPrefix(server.URL, "http://"), "org/repo", t.TempDir(), nil, logger)
+	assert.Nil(t, err)
+	client.repo.PlainHTTP = true
+	client.retryDelay = time.Millisecond
+	return client, registry
+}
+
+func TestORASClientPullArtifact(t *testing.T) {
+	client, _ := newFakeRegistryClient(t)
+
+	artifactDir, err := client.PullArtifact(context.Background(), "run-1")
+	assert.Nil(t, err)
+	content, readErr := os.ReadFile(filepath.Join(artifactDir, "pipeline-status.json"))
+	assert.NoError(t, readErr)
+	assert.Equal(t, `{"status":"Succeeded"}`, string(content))
+
+	// Layers without a title are not written, like with `oras pull`
+	files, err := client.ExtractArtifactFiles(context.Background(), artifactDir, artifactDir)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"pipeline-status.json"}, files)
+}
+
+func TestORASClientPullArtifactPrivate(t *testing.T) {
+	_, registry := newFakeRegistryClient(t)
+	registry.login = "org+robot:robot-token"
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+	host := strings.TrimPrefix(server.URL, "http://")
+
+	anonymous, err := NewORASClient(context.Background(), host, "org/repo", t.TempDir(), nil, newMockLogger())
+	assert.Nil(t, err)
+	anonymous.repo.PlainHTTP = true
+	_, annotationsErr := anonymous.FetchAnnotations(context.Background(), "run-1")
+	assert.NotNil(t, annotationsErr)
+
+	connection := &models.TestRegistryConnection{QuayRobotUsername: "org+robot", QuayRobotToken: "robot-token"}
+	client, err := NewORASClient(context.Background(), host, "org/repo", t.TempDir(), connection, newMockLogger())
+	assert.Nil(t, err)
+	client.repo.PlainHTTP = true
+	artifactDir, err := client.PullArtifact(context.Background(), "run-1")
+	assert.Nil(t, err)
+	assert.FileExists(t, filepath.Join(artifactDir, "pipeline-status.json"))
+}
+
+func TestORASClientPullArtifactRetries(t *testing.T) {
+	client, registry := newFakeRegistryClient(t)
+	// Without the retry transport, the failures are left to the pull attempts
+	client.repo.Client = &auth.Client{Client: http.DefaultClient}
+	registry.failures = orasPullAttempts - 1
+
+	artifactDir, err := client.PullArtifact(context.Background(), "run-1")
+	assert.Nil(t, err)
+	assert.FileExists(t, filepath.Join(artifactDir, "pipeline-status.json"))
+}
+
+func TestORASClientPullArtifactNotFound(t *testing.T) {
+	client, _ := newFakeRegistryClient(t)
+
+	artifactDir, err := client.PullArtifact(context.Background(), "expired")
+	assert.Empty(t, artifactDir)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.NotFound, err.GetType())
+	entries, _ := os.ReadDir(client.loggingDir)
+	assert.Empty(t, entries)
+}
+
+func TestORASClientFetchAnnotations(t *testing.T) {
+	client, _ := newFakeRegistryClient(t)
+
+	annotations, err := client.FetchAnnotations(context.Background(), "run-1")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"appstudio.openshift.io/application": "release-service"}, annotations)
+}
+
+func TestORASClientListArtifacts(t *testing.T) {
+	client, registry := newFakeRegistryClient(t)
+	registry.tags = append(registry.tags, "run-2")
+
+	tags, err := client.ListArtifacts(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"run-1", "run-2"}, tags)
+
+	registry.tags = nil
+	tags, err = client.ListArtifacts(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"latest"}, tags)
 }
@@ -0,0 +1,229 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/helpers/gcshelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	// ProwArtifactsBaseURL is the gcsweb instance Prow's Spyglass links job artifacts to.
+	// It lists and serves the objects of the public Openshift CI bucket without GCS credentials.
+	ProwArtifactsBaseURL = "https://gcsweb-ci.apps.ci.l2s4.p1.openshiftapps.com"
+
+	// maxArtifactsListingDepth limits how deep below the artifacts directory JUnit files are searched
+	maxArtifactsListingDepth = 8
+
+	// maxArtifactsListingRequests limits the directory listings made for a single job
+	maxArtifactsListingRequests = 200
+
+	// maxArtifactFileSize caps the size of a downloaded JUnit file
+	maxArtifactFileSize = 50 << 20
+
+	// prowArtifactsRequestTimeout bounds one listing or download, so a hung artifacts server can't block collection
+	prowArtifactsRequestTimeout = 2 * time.Minute
+)
+
+var artifactsHrefRe = regexp.MustCompile(`href="([^"]+)"`)
+
+// ProwArtifactsClient fetches JUnit XML files of Prow jobs over HTTP from the
+// artifacts browser Spyglass links to. It is the fallback for GCSBucket in
+// environments without GCS access.
+type ProwArtifactsClient struct {
+	baseURL        string
+	bucket         string
+	prefixTemplate string // the connection's gcsPrefixTemplate, empty for the Openshift CI layout
+	httpClient     *http.Client
+}
+
+// NewProwArtifactsClient creates a client for the artifacts browser at baseURL,
+// or at ProwArtifactsBaseURL when baseURL is empty.
+func NewProwArtifactsClient(baseURL string) *ProwArtifactsClient {
+	if baseURL == "" {
+		baseURL = ProwArtifactsBaseURL
+	}
+	return &ProwArtifactsClient{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		bucket:     gcshelper.OpenshiftCIBucketName,
+		httpClient: &http.Client{Timeout: prowArtifactsRequestTimeout},
+	}
+}
+
+// GetJobJunitContent walks the job's artifacts directory listing breadth-first
+// and downloads every file whose object name matches fileName, like
+// GCSBucket.GetJobJunitContent. A job without artifacts yields no files and no
+// error; files already downloaded are returned along with a listing error.
+func (c *ProwArtifactsClient) GetJobJunitContent(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, fileName *regexp.Regexp) ([]JUnitFile, error) {
+	prefix := gcsArtifactsPrefix(c.prefixTemplate, orgName, repoName, pullNumber, branch, jobId, jobType, jobName) + "/"
+
+	var results []JUnitFile
+	dirs := []string{prefix}
+	for requests := 0; len(dirs) > 0; requests++ {
+		if requests >= maxArtifactsListingRequests {
+			return results, fmt.Errorf("artifacts listing of %s stopped after %d directories", prefix, requests)
+		}
+		dir := dirs[0]
+		dirs = dirs[1:]
+
+		entries, err := c.listDirectory(ctx, dir)
+		if err != nil {
+			return results, err
+		}
+		for _, entry := range entries {
+			if strings.HasSuffix(entry, "/") {
+				if strings.Count(strings.TrimPrefix(entry, prefix), "/") <= maxArtifactsListingDepth {
+					dirs = append(dirs, entry)
+				}
+				continue
+			}
+			if fileName == nil || !fileName.MatchString(entry) {
+				continue
+			}
+			content, err := c.getContent(ctx, entry)
+			if err != nil {
+				continue
+			}
+			results = append(results, JUnitFile{Content: content, Path: entry})
+			if len(results) >= maxJUnitFilesPerJob {
+				return results, nil
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// listDirectory returns the object names (files) and sub-directories (ending
+// in "/") linked from the listing page of dir. A missing directory is empty.
+func (c *ProwArtifactsClient) listDirectory(ctx context.Context, dir string) ([]string, error) {
+	body, status, err := c.get(ctx, c.objectURL(dir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", dir, err)
+	}
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("artifacts listing of %s returned status %d", dir, status)
+	}
+
+	bucketPath := "/gcs/" + c.bucket + "/"
+	seen := map[string]bool{}
+	var entries []string
+	for _, match := range artifactsHrefRe.FindAllSubmatch(body, -1) {
+		href := html.UnescapeString(string(match[1]))
+		if i := strings.IndexAny(href, "?#"); i >= 0 {
+			href = href[:i]
+		}
+		href = strings.TrimPrefix(href, c.baseURL)
+		if !strings.HasPrefix(href, bucketPath) {
+			continue
+		}
+		name := strings.TrimPrefix(href, bucketPath)
+		// Skip the parent directory link and the directory itself
+		if name == dir || !strings.HasPrefix(name, dir) || seen[name] {
+			continue
+		}
+		seen[name] = true
+		entries = append(entries, name)
+	}
+	return entries, nil
+}
+
+// getContent downloads the object with the given name.
+func (c *ProwArtifactsClient) getContent(ctx context.Context, name string) ([]byte, error) {
+	body, status, err := c.get(ctx, c.objectURL(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("download of %s returned status %d", name, status)
+	}
+	return body, nil
+}
+
+func (c *ProwArtifactsClient) objectURL(name string) string {
+	return fmt.Sprintf("%s/gcs/%s/%s", c.baseURL, c.bucket, name)
+}
+
+func (c *ProwArtifactsClient) get(ctx context.Context, url string) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArtifactFileSize))
+	if err != nil {
+		return nil, resp.StatusCode, err
+	}
+	return body, resp.StatusCode, nil
+}
+
+// fallbackResultsFetcher fetches JUnit files from primary and, when primary
+// fails, from fallback. The fallback result wins unless it fails with fewer
+// files than primary returned.
+type fallbackResultsFetcher struct {
+	primary  ResultsFetcher
+	fallback ResultsFetcher
+}
+
+func (f *fallbackResultsFetcher) GetJobJunitContent(ctx context.Context, orgName, repoName, pullNumber, branch, jobId, jobType, jobName string, fileName *regexp.Regexp) ([]JUnitFile, error) {
+	files, err := f.primary.GetJobJunitContent(ctx, orgName, repoName, pullNumber, branch, jobId, jobType, jobName, fileName)
+	if err == nil {
+		return files, nil
+	}
+	fallbackFiles, fallbackErr := f.fallback.GetJobJunitContent(ctx, orgName, repoName, pullNumber, branch, jobId, jobType, jobName, fileName)
+	if fallbackErr != nil && len(fallbackFiles) <= len(files) {
+		return files, err
+	}
+	return fallbackFiles, fallbackErr
+}
+
+// withArtifactsFallback adds the Prow artifacts fallback to fetcher when the
+// connection enables it. A nil fetcher (GCS unavailable) is replaced by the
+// fallback; without the fallback fetcher is returned unchanged.
+func withArtifactsFallback(fetcher ResultsFetcher, connection *models.TestRegistryConnection) ResultsFetcher {
+	if connection == nil || !connection.ProwArtifactsFallback {
+		return fetcher
+	}
+	fallback := NewProwArtifactsClient(connection.ProwArtifactsURL)
+	// The artifacts browser serves the same bucket and layout as the GCS client
+	if bucket := strings.TrimSpace(connection.GCSBucket); bucket != "" {
+		fallback.bucket = bucket
+	}
+	fallback.prefixTemplate = strings.TrimSpace(connection.GCSPrefixTemplate)
+	if fetcher == nil {
+		return fallback
+	}
+	return &fallbackResultsFetcher{primary: fetcher, fallback: fallback}
+}
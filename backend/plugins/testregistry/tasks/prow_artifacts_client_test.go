@@ -0,0 +1,162 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newTestArtifactsServer serves a gcsweb-like listing of the given bucket
+// objects, keyed by object name; directories are derived from the names.
+func newTestArtifactsServer(t *testing.T, objects map[string]string) *ProwArtifactsClient {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/gcs/test-platform-results/")
+		if content, ok := objects[name]; ok {
+			_, _ = w.Write([]byte(content))
+			return
+		}
+		children := map[string]bool{}
+		for object := range objects {
+			if child, ok := strings.CutPrefix(object, name); ok && child != "" {
+				if i := strings.Index(child, "/"); i >= 0 {
+					child = child[:i+1]
+				}
+				children[child] = true
+			}
+		}
+		if len(children) == 0 {
+			http.NotFound(w, r)
+			return
+		}
+		listing := `<a href="/gcs/test-platform-results/` + name + `../">..</a>`
+		for child := range children {
+			listing += fmt.Sprintf(`<li><a href="/gcs/test-platform-results/%s%s"><img src="/icons/file.png"> %s</a></li>`, name, child, child)
+		}
+		_, _ = w.Write([]byte("<html><body><ul>" + listing + "</ul></body></html>"))
+	}))
+	t.Cleanup(server.Close)
+	return NewProwArtifactsClient(server.URL + "/")
+}
+
+func TestProwArtifactsClient_GetJobJunitContent(t *testing.T) {
+	junitRegex := regexp.MustCompile(`junit.*\.xml$`)
+	prefix := "pr-logs/pull/org_repo/42/pull-ci-e2e/1001/artifacts/"
+
+	t.Run("walks sub-directories and downloads matching files", func(t *testing.T) {
+		client := newTestArtifactsServer(t, map[string]string{
+			prefix + "e2e/junit-e2e.xml":                                          validJUnitXML,
+			prefix + "e2e/build-log.txt":                                          "log",
+			prefix + "e2e/artifacts/junit-unit.xml":                               validJUnitXML,
+			"pr-logs/pull/org_repo/42/pull-ci-e2e/1002/artifacts/junit-other.xml": validJUnitXML,
+		})
+
+		files, err := client.GetJobJunitContent(context.Background(), "org", "repo", "42", "", "1001", "presubmit", "pull-ci-e2e", junitRegex)
+
+		assert.NoError(t, err)
+		paths := []string{}
+		for _, f := range files {
+			paths = append(paths, f.Path)
+			assert.Equal(t, validJUnitXML, string(f.Content))
+		}
+		assert.ElementsMatch(t, []string{prefix + "e2e/junit-e2e.xml", prefix + "e2e/artifacts/junit-unit.xml"}, paths)
+	})
+
+	t.Run("job without artifacts", func(t *testing.T) {
+		client := newTestArtifactsServer(t, map[string]string{})
+
+		files, err := client.GetJobJunitContent(context.Background(), "", "", "", "", "2002", "periodic", "periodic-e2e", junitRegex)
+
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("listing error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+
+		files, err := NewProwArtifactsClient(server.URL).GetJobJunitContent(context.Background(), "", "", "", "", "2002", "periodic", "periodic-e2e", junitRegex)
+
+		assert.Error(t, err)
+		assert.Empty(t, files)
+	})
+}
+
+func TestFallbackResultsFetcher(t *testing.T) {
+	junitRegex := regexp.MustCompile(`\.xml$`)
+	gcsFiles := []JUnitFile{{Path: "gcs.xml"}}
+	httpFiles := []JUnitFile{{Path: "a.xml"}, {Path: "b.xml"}}
+
+	tests := []struct {
+		name        string
+		primaryErr  error
+		fallback    []JUnitFile
+		fallbackErr error
+		want        []JUnitFile
+		wantErr     bool
+	}{
+		{name: "primary succeeds", want: gcsFiles},
+		{name: "primary fails, fallback succeeds", primaryErr: fmt.Errorf("403"), fallback: httpFiles, want: httpFiles},
+		{name: "both fail keeps primary files", primaryErr: fmt.Errorf("403"), fallbackErr: fmt.Errorf("502"), want: gcsFiles, wantErr: true},
+		{name: "both fail keeps larger partial result", primaryErr: fmt.Errorf("403"), fallback: httpFiles, fallbackErr: fmt.Errorf("502"), want: httpFiles, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := new(mockResultsFetcher)
+			primary.On("GetJobJunitContent", mock.Anything, "org", "repo", "42", "", "1001", "presubmit", "job", junitRegex).Return(gcsFiles, tt.primaryErr)
+			fallback := new(mockResultsFetcher)
+			fallback.On("GetJobJunitContent", mock.Anything, "org", "repo", "42", "", "1001", "presubmit", "job", junitRegex).Return(tt.fallback, tt.fallbackErr).Maybe()
+
+			fetcher := &fallbackResultsFetcher{primary: primary, fallback: fallback}
+			files, err := fetcher.GetJobJunitContent(context.Background(), "org", "repo", "42", "", "1001", "presubmit", "job", junitRegex)
+
+			assert.Equal(t, tt.want, files)
+			assert.Equal(t, tt.wantErr, err != nil)
+			if tt.primaryErr == nil {
+				fallback.AssertNotCalled(t, "GetJobJunitContent")
+			}
+		})
+	}
+}
+
+func TestWithArtifactsFallback(t *testing.T) {
+	gcs := new(mockResultsFetcher)
+
+	assert.Equal(t, ResultsFetcher(gcs), withArtifactsFallback(gcs, nil))
+	assert.Equal(t, ResultsFetcher(gcs), withArtifactsFallback(gcs, &models.TestRegistryConnection{}))
+
+	enabled := &models.TestRegistryConnection{ProwArtifactsFallback: true, ProwArtifactsURL: "https://gcsweb.example.com/"}
+	wrapped, ok := withArtifactsFallback(gcs, enabled).(*fallbackResultsFetcher)
+	assert.True(t, ok)
+	assert.Equal(t, "https://gcsweb.example.com", wrapped.fallback.(*ProwArtifactsClient).baseURL)
+
+	client, ok := withArtifactsFallback(nil, &models.TestRegistryConnection{ProwArtifactsFallback: true}).(*ProwArtifactsClient)
+	assert.True(t, ok)
+	assert.Equal(t, ProwArtifactsBaseURL, client.baseURL)
+}
@@ -55,11 +55,12 @@ var CollectProwJobsMeta = plugin.SubTaskMeta{
 // CollectProwJobs is the main entry point for collecting Prow jobs from Openshift CI.
 //
 // This function:
-// 1. Fetches all Prow jobs from the Openshift CI API
+// 1. Fetches all Prow jobs from the Openshift CI API, plus the older runs of the GCS job history when the scope config sets prowHistoryMaxDepth
 // 2. Filters jobs that match the specified GitHub organization and repository
-// 3. Saves raw job JSON to the raw data table
-// 4. Converts and saves normalized CI job records
-// 5. Attempts to fetch and log JUnit test suite information from GCS
+// 3. Skips jobs completed before the sync policy timeAfter or the scope cursor, or already collected
+// 4. Saves raw job JSON to the raw data table
+// 5. Converts and saves normalized CI job records
+// 6. Attempts to fetch and log JUnit test suite information from GCS
 //
 // Parameters:
 //   - taskCtx: The subtask context providing access to logger, database, and other resources
@@ -70,6 +71,7 @@ func CollectProwJobs(taskCtx plugin.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*TestRegistryTaskData)
 	logger := taskCtx.GetLogger()
 	logger.Info("collecting Prow jobs for scope: %s", data.Options.FullName)
+	startedAt := time.Now()
 
 	// Validate connection type
 	if data.Connection.CITool != models.CIToolOpenshiftCI {
@@ -90,19 +92,40 @@ func CollectProwJobs(taskCtx plugin.SubTaskContext) errors.Error {
 		return err
 	}
 
+	// Only jobs that completed since the last collection of the scope are processed
+	db := taskCtx.GetDal()
+	syncPolicy := scopeSyncPolicy(taskCtx.TaskContext().SyncPolicy(), data.Options.ScopeConfig, time.Now())
+	window, err := newProwIncrementalWindow(db, syncPolicy, data.Options.ConnectionId, repoName)
+	if err != nil {
+		return err
+	}
+
 	// Fetch Prow jobs from API
-	allJobs, err := fetchProwJobsFromAPI(taskCtx)
+	baseURL := ProwBaseURL
+	if data.ProwBaseURLOverride != "" {
+		baseURL = data.ProwBaseURLOverride
+	}
+	allJobs, err := fetchProwJobsFromAPI(taskCtx, baseURL)
 	if err != nil {
 		return err
 	}
 
 	logger.Info("Fetched %d Prow jobs total, filtering for scope %s/%s", len(allJobs), githubOrg, repoName)
 
+	// Backfill the runs older than the snapshot from the GCS job history
+	if data.Options.ScopeConfig != nil && data.Options.ScopeConfig.ProwHistoryMaxDepth > 0 {
+		history, err := fetchProwJobHistory(taskCtx, db, data, allJobs, githubOrg, repoName, window)
+		if err != nil {
+			return err
+		}
+		logger.Info("Found %d older Prow jobs in the GCS job history of scope %s/%s", len(history), githubOrg, repoName)
+		allJobs = append(allJobs, history...)
+	}
+
 	// Process and save matching jobs
-	db := taskCtx.GetDal()
 	rawTable := rawDataSubTask.GetTable()
 	rawParams := rawDataSubTask.GetParams()
-	apiURL := fmt.Sprintf("%s/%s", ProwBaseURL, ProwJobsPath)
+	apiURL := fmt.Sprintf("%s/%s", baseURL, ProwJobsPath)
 
 	stats := &collectionStats{}
 	stats.processJobs(
@@ -115,23 +138,71 @@ func CollectProwJobs(taskCtx plugin.SubTaskContext) errors.Error {
 		githubOrg,
 		repoName,
 		data,
+		window,
 	)
+	if !window.Latest.IsZero() {
+		if err := saveProwCursor(db, data.Options.ConnectionId, repoName, window.Latest); err != nil {
+			logger.Warn(err, "failed to save Prow collection cursor", "scope", repoName)
+		}
+	}
 
 	// Log final summary
 	logger.Info(
-		"Found %d Prow jobs matching scope %s/%s, saved %d CI jobs and %d raw records to database. JUnit XML found for %d jobs, not found for %d jobs",
+		"Found %d Prow jobs matching scope %s/%s, skipped %d already collected and %d filtered out by the scope config, saved %d CI jobs and %d raw records to database. JUnit XML found for %d jobs, not found for %d jobs",
 		stats.matchingCount,
 		githubOrg,
 		repoName,
+		stats.skippedCount,
+		stats.filteredCount,
 		stats.savedCount,
 		stats.rawSavedCount,
 		stats.junitFoundCount,
 		stats.junitNotFoundCount,
 	)
+	recordCollectionRun(db, logger, data, startedAt, stats.matchingCount, stats.matchingCount-stats.skippedCount, *stats)
 
 	return nil
 }
 
+// fetchProwJobHistory walks the GCS job history of the Prow jobs of the scope, up to the
+// prowHistoryMaxDepth of the scope config, for the runs missing from the live snapshot
+func fetchProwJobHistory(
+	taskCtx plugin.SubTaskContext,
+	db dal.Dal,
+	data *TestRegistryTaskData,
+	allJobs []ProwJob,
+	githubOrg string,
+	repoName string,
+	window *prowIncrementalWindow,
+) ([]ProwJob, errors.Error) {
+	logger := taskCtx.GetLogger()
+
+	var liveJobs []ProwJob
+	liveBuildIDs := map[string]bool{}
+	for i := range allJobs {
+		if matchesRenamedScope(&allJobs[i], data.RepoRenamer, githubOrg, repoName) {
+			liveJobs = append(liveJobs, allJobs[i])
+			liveBuildIDs[extractJobID(&allJobs[i])] = true
+		}
+	}
+	jobs, err := prowHistoryJobs(db, data.Options.ConnectionId, repoName, liveJobs)
+	if err != nil {
+		return nil, err
+	}
+
+	store := data.JobHistoryStoreOverride
+	if store == nil {
+		bucket, gcsErr := NewGCSBucketClient(taskCtx.GetContext(), data.Connection)
+		if gcsErr != nil {
+			logger.Warn(gcsErr, "failed to create GCS client, the Prow job history will not be walked")
+			return nil, nil
+		}
+		store = bucket
+		defer func() { _ = bucket.Close() }()
+	}
+	return collectProwHistory(taskCtx.GetContext(), logger, store, jobs, data.Options.ScopeConfig.ProwHistoryMaxDepth, window, liveBuildIDs), nil
+}
+
 // collectionStats tracks statistics during job collection
 type collectionStats struct {
 	matchingCount      int
@@ -140,6 +211,46 @@ type collectionStats struct {
 	processedCount     int
 	junitFoundCount    int
 	junitNotFoundCount int
+	expiredCount       int // Quay.io tags that expired before their artifact was pulled
+	skippedCount       int // Prow jobs completed before the incremental window or already collected
+	filteredCount      int // CI jobs left out by the scenario and trigger type filters of the scope config
+
+	// Quay.io tags whose artifact failed to process for another reason than expiry, retried by the next run
+	failedTags []QuayTag
+
+	// JUnit availability per job name as collected, nil until a job is recorded
+	junitByJobName map[string]*junitJobStats
+}
+
+// junitJobStats counts the saved runs of a job with and without JUnit results
+type junitJobStats struct {
+	found    int
+	notFound int
+}
+
+// recordJUnit counts whether JUnit results were found for a saved job run
+func (stats *collectionStats) recordJUnit(jobName string, found bool) {
+	jobStats := stats.junitJobStats(jobName)
+	if found {
+		stats.junitFoundCount++
+		jobStats.found++
+	} else {
+		stats.junitNotFoundCount++
+		jobStats.notFound++
+	}
+}
+
+// junitJobStats returns the JUnit counters of a job, created on first use
+func (stats *collectionStats) junitJobStats(jobName string) *junitJobStats {
+	if stats.junitByJobName == nil {
+		stats.junitByJobName = make(map[string]*junitJobStats)
+	}
+	jobStats, ok := stats.junitByJobName[jobName]
+	if !ok {
+		jobStats = &junitJobStats{}
+		stats.junitByJobName[jobName] = jobStats
+	}
+	return jobStats
 }
 
 // processJobs iterates through all Prow jobs, filters matching ones, and saves them to the database
@@ -153,18 +264,14 @@ func (stats *collectionStats) processJobs(
 	githubOrg string,
 	repoName string,
 	data *TestRegistryTaskData,
+	window *prowIncrementalWindow,
 ) {
 	logger := taskCtx.GetLogger()
 	taskCtx.SetProgress(0, len(allJobs))
 
 	// Create GCS client once for the entire task run
-	gcsClient, gcsErr := NewGCSBucketClient(taskCtx.GetContext())
-	if gcsErr != nil {
-		logger.Warn(gcsErr, "failed to create GCS client, JUnit collection will be skipped")
-	}
-	if gcsClient != nil {
-		defer func() { _ = gcsClient.Close() }()
-	}
+	gcsClient, closeClient := newJUnitResultsFetcher(taskCtx, data)
+	defer closeClient()
 
 	for _, job := range allJobs {
 		stats.processedCount++
@@ -174,27 +281,47 @@ func (stats *collectionStats) processJobs(
 			taskCtx.SetProgress(stats.processedCount, len(allJobs))
 		}
 
-		// Process matching jobs only
-		if !matchesScope(&job, githubOrg, repoName) {
+		// Process matching jobs only, including the ones still reported under an old name of the repo
+		if !matchesRenamedScope(&job, data.RepoRenamer, githubOrg, repoName) {
 			continue
 		}
 
 		stats.matchingCount++
 
-		// Save raw job JSON
-		if err := saveRawJobData(db, rawTable, rawParams, apiURL, &job); err != nil {
-			logger.Warn(err, "failed to save raw Prow job data")
-		} else {
-			stats.rawSavedCount++
+		// Skip jobs of an earlier sync before saving or converting anything
+		if window.skip(&job) {
+			stats.skippedCount++
+			continue
 		}
+		window.observe(&job)
 
-		// Convert and save normalized CI job
+		// Convert to normalized CI job
 		ciJob, err := convertProwJobToCIJob(&job, data.Options.ConnectionId, data.Options.FullName, githubOrg, repoName)
 		if err != nil {
 			logger.Warn(err, "failed to convert Prow job to CI job")
 			continue
 		}
 
+		// Skip the scenarios and trigger types the scope config doesn't collect
+		if !data.JobFilter.Allows(ciJob) {
+			stats.filteredCount++
+			continue
+		}
+
+		// Save raw job JSON
+		origin, rawErr := saveRawJobData(db, rawTable, rawParams, apiURL, &job)
+		if rawErr != nil {
+			logger.Warn(rawErr, "failed to save raw Prow job data")
+		} else {
+			stats.rawSavedCount++
+		}
+
+		// Save normalized CI job
+		ciJob.RawDataOrigin = origin
+		data.JobNameNormalizer.apply(ciJob)
+		data.RepoRenamer.apply(ciJob)
+		data.CommitShaResolver.apply(db, logger, ciJob)
+
 		if err := db.CreateOrUpdate(ciJob); err != nil {
 			logger.Warn(err, "failed to save CI job to database", "job_id", ciJob.JobId)
 			continue
@@ -204,21 +331,43 @@ func (stats *collectionStats) processJobs(
 
 		// Fetch and log JUnit test suites using configured regex
 		if gcsClient == nil {
-			stats.junitNotFoundCount++
+			stats.recordJUnit(ciJob.JobName, false)
+			// Saving the job again reset the counts of JUnit results processed by an earlier run
+			updateJobTestCounts(db, logger, ciJob)
 			continue
 		}
 		logger.Debug("Attempting to fetch JUnit XML for job", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "trigger_type", ciJob.TriggerType)
-		if fetchAndPrintJUnitSuites(taskCtx, gcsClient, &job, githubOrg, repoName, ciJob, data.JUnitRegex) {
-			stats.junitFoundCount++
-		} else {
-			stats.junitNotFoundCount++
-		}
+		branch := postsubmitBranch(&job, data.Options.ScopeConfig)
+		stats.recordJUnit(ciJob.JobName, fetchAndPrintJUnitSuites(taskCtx, gcsClient, &job, githubOrg, repoName, branch, ciJob, data.JUnitRegex))
 	}
 
 	// Final progress update
 	taskCtx.SetProgress(len(allJobs), len(allJobs))
 }
 
+// newJUnitResultsFetcher returns the fetcher of the JUnit files of Prow jobs: the task's override, else the
+// connection's GCS bucket, wrapped with the artifacts browser fallback when the connection enables it.
+// It is nil when no source is usable. The returned func closes the bucket.
+func newJUnitResultsFetcher(taskCtx plugin.SubTaskContext, data *TestRegistryTaskData) (ResultsFetcher, func()) {
+	logger := taskCtx.GetLogger()
+	closeClient := func() {}
+	gcsClient := data.ResultsFetcherOverride
+	if gcsClient == nil {
+		bucket, gcsErr := NewGCSBucketClient(taskCtx.GetContext(), data.Connection)
+		if gcsErr != nil {
+			if data.Connection != nil && data.Connection.ProwArtifactsFallback {
+				logger.Warn(gcsErr, "failed to create GCS client, JUnit files will be fetched from the Prow artifacts browser")
+			} else {
+				logger.Warn(gcsErr, "failed to create GCS client, JUnit collection will be skipped")
+			}
+		} else {
+			gcsClient = bucket
+			closeClient = func() { _ = bucket.Close() }
+		}
+	}
+	return withArtifactsFallback(gcsClient, data.Connection), closeClient
+}
+
 // setupRawDataCollection initializes the raw data collection subtask
 func setupRawDataCollection(taskCtx plugin.SubTaskContext, data *TestRegistryTaskData) (*helper.RawDataSubTask, errors.Error) {
 	return helper.NewRawDataSubTask(helper.RawDataSubTaskArgs{
@@ -244,13 +393,13 @@ func isTransientStatusCode(code int) bool {
 		code == http.StatusTooManyRequests
 }
 
-// fetchProwJobsFromAPI retrieves all Prow jobs from the Openshift CI API.
+// fetchProwJobsFromAPI retrieves all Prow jobs from the Openshift CI API served at baseURL.
 // Transient errors (502, 503, 504, 429) are retried up to prowMaxRetries times
 // with exponential backoff starting at prowRetryBaseWait.
-func fetchProwJobsFromAPI(taskCtx plugin.SubTaskContext) ([]ProwJob, errors.Error) {
+func fetchProwJobsFromAPI(taskCtx plugin.SubTaskContext, baseURL string) ([]ProwJob, errors.Error) {
 	logger := taskCtx.GetLogger()
 
-	apiClient, err := helper.NewApiClient(taskCtx.GetContext(), ProwBaseURL, nil, 0, "", taskCtx)
+	apiClient, err := helper.NewApiClient(taskCtx.GetContext(), baseURL, nil, 0, "", taskCtx)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "failed to create API client for Prow")
 	}
@@ -306,11 +455,12 @@ func fetchProwJobsFromAPI(taskCtx plugin.SubTaskContext) ([]ProwJob, errors.Erro
 //   - job: The Prow job to save
 //
 // Returns:
+//   - common.RawDataOrigin: Origin pointing at the saved raw record, to be stored on the derived rows
 //   - errors.Error: Any error encountered during saving, or nil if successful
-func saveRawJobData(db dal.Dal, rawTable, rawParams, apiURL string, job *ProwJob) errors.Error {
+func saveRawJobData(db dal.Dal, rawTable, rawParams, apiURL string, job *ProwJob) (common.RawDataOrigin, errors.Error) {
 	jobJSON, err := json.Marshal(job)
 	if err != nil {
-		return errors.Default.Wrap(err, "failed to marshal Prow job to JSON")
+		return common.RawDataOrigin{}, errors.Default.Wrap(err, "failed to marshal Prow job to JSON")
 	}
 
 	rawData := &helper.RawData{
@@ -320,7 +470,20 @@ func saveRawJobData(db dal.Dal, rawTable, rawParams, apiURL string, job *ProwJob
 		CreatedAt: time.Now(),
 	}
 
-	return db.Create(rawData, dal.From(rawTable))
+	if err := db.Create(rawData, dal.From(rawTable)); err != nil {
+		return common.RawDataOrigin{}, err
+	}
+
+	return rawDataOrigin(rawTable, rawParams, rawData.ID), nil
+}
+
+// rawDataOrigin builds the lineage fields linking a derived row to its raw record
+func rawDataOrigin(rawTable, rawParams string, rawId uint64) common.RawDataOrigin {
+	return common.RawDataOrigin{
+		RawDataTable:  rawTable,
+		RawDataParams: rawParams,
+		RawDataId:     rawId,
+	}
 }
 
 // matchesScope checks if a Prow job matches the given GitHub organization and repository.
@@ -368,6 +531,29 @@ func matchesScope(job *ProwJob, githubOrg, repoName string) bool {
 	return false
 }
 
+// matchesRenamedScope checks if a Prow job matches the scope under its name or, for a renamed
+// org or repo, under one of the old names mapped to it by the connection's repoRenames.
+//
+// Parameters:
+//   - job: The Prow job to check
+//   - renamer: The connection's repo renamer (may be nil)
+//   - githubOrg: Expected GitHub organization name
+//   - repoName: Expected repository name
+//
+// Returns:
+//   - bool: true if the job matches one of the names and is in a valid state, false otherwise
+func matchesRenamedScope(job *ProwJob, renamer *RepoRenamer, githubOrg, repoName string) bool {
+	if matchesScope(job, githubOrg, repoName) {
+		return true
+	}
+	for _, rename := range renamer.renamedTo(githubOrg, repoName) {
+		if matchesScope(job, rename.OldOrganization, rename.OldRepository) {
+			return true
+		}
+	}
+	return false
+}
+
 // isValidJobState checks if a Prow job state is valid for processing.
 //
 // Jobs in "aborted", "pending", or "triggered" states are excluded as they
@@ -428,8 +614,9 @@ func convertProwJobToCIJob(prowJob *ProwJob, connectionId uint64, scopeId, organ
 	// Map job status
 	mapJobStatus(ciJob, prowJob)
 
-	// Set namespace
+	// Set namespace and build cluster
 	ciJob.Namespace = prowJob.Spec.Namespace
+	ciJob.Cluster = prowJob.Spec.Cluster
 
 	// Parse and set timestamps
 	parseTimestamps(ciJob, prowJob)
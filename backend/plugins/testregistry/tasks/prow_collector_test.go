@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/apache/incubator-devlake/core/errors"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
 	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
 	"github.com/apache/incubator-devlake/plugins/testregistry/models"
@@ -29,6 +30,23 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
+func TestMatchesRenamedScope(t *testing.T) {
+	renamer, err := NewRepoRenamer(map[string]string{"redhat-appstudio": "konflux-ci"})
+	assert.Nil(t, err)
+
+	job := &ProwJob{
+		Labels: map[string]string{
+			"prow.k8s.io/refs.org":  "redhat-appstudio",
+			"prow.k8s.io/refs.repo": "e2e-tests",
+		},
+		Status: ProwJobStatus{State: "success"},
+	}
+	assert.True(t, matchesRenamedScope(job, renamer, "konflux-ci", "e2e-tests"))
+	assert.True(t, matchesRenamedScope(job, renamer, "redhat-appstudio", "e2e-tests"))
+	assert.False(t, matchesRenamedScope(job, renamer, "konflux-ci", "build-service"))
+	assert.False(t, matchesRenamedScope(job, nil, "konflux-ci", "e2e-tests"))
+}
+
 func TestMatchesScope(t *testing.T) {
 	t.Run("matches via labels", func(t *testing.T) {
 		job := &ProwJob{
@@ -372,6 +390,7 @@ func TestConvertProwJobToCIJob(t *testing.T) {
 				Job:       "e2e-test",
 				Type:      "presubmit",
 				Namespace: "ci",
+				Cluster:   "build05",
 				Refs: &ProwJobRefs{
 					Org:  "openshift",
 					Repo: "console",
@@ -399,6 +418,7 @@ func TestConvertProwJobToCIJob(t *testing.T) {
 		assert.Equal(t, "pull_request", ciJob.TriggerType)
 		assert.Equal(t, "SUCCESS", ciJob.Result)
 		assert.Equal(t, "ci", ciJob.Namespace)
+		assert.Equal(t, "build05", ciJob.Cluster)
 		assert.Equal(t, "abc123", ciJob.CommitSHA)
 		assert.NotNil(t, ciJob.PullRequestNumber)
 		assert.Equal(t, 99, *ciJob.PullRequestNumber)
@@ -466,9 +486,11 @@ func TestSaveRawJobData(t *testing.T) {
 			Spec:   ProwJobSpec{Job: "e2e-test"},
 			Status: ProwJobStatus{State: "success", BuildID: "b1"},
 		}
-		err := saveRawJobData(mockDal, "raw_table", `{"ConnectionId":1}`, "https://api.example.com", job)
+		origin, err := saveRawJobData(mockDal, "raw_table", `{"ConnectionId":1}`, "https://api.example.com", job)
 		assert.Nil(t, err)
 		mockDal.AssertCalled(t, "Create", mock.Anything, mock.Anything)
+		assert.Equal(t, "raw_table", origin.RawDataTable)
+		assert.Equal(t, `{"ConnectionId":1}`, origin.RawDataParams)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -476,11 +498,25 @@ func TestSaveRawJobData(t *testing.T) {
 		mockDal.On("Create", mock.Anything, mock.Anything).Return(errors.Default.New("db error"))
 
 		job := &ProwJob{Spec: ProwJobSpec{Job: "test"}}
-		err := saveRawJobData(mockDal, "raw_table", `{}`, "url", job)
+		origin, err := saveRawJobData(mockDal, "raw_table", `{}`, "url", job)
 		assert.NotNil(t, err)
+		assert.Empty(t, origin.RawDataTable)
 	})
 }
 
+func TestSaveRawJobDataLineage(t *testing.T) {
+	mockDal := new(mockdal.Dal)
+	mockDal.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// the database assigns the raw row id on insert
+		args.Get(0).(*helper.RawData).ID = 42
+	}).Return(nil)
+
+	origin, err := saveRawJobData(mockDal, "_raw_cicd_test_jobs", `{"ConnectionId":1}`, "url", &ProwJob{})
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(42), origin.RawDataId)
+	assert.Equal(t, "_raw_cicd_test_jobs", origin.RawDataTable)
+}
+
 func TestParseTimestamps(t *testing.T) {
 	t.Run("all timestamps set", func(t *testing.T) {
 		ciJob := &models.TestRegistryCIJob{}
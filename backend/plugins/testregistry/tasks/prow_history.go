@@ -0,0 +1,217 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/helpers/gcshelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// prowHistoryJob identifies a Prow job whose GCS job history is walked
+type prowHistoryJob struct {
+	Name string
+	// Type is the Prow job type: "presubmit", "postsubmit" or "periodic"
+	Type string
+}
+
+// prowHistoryBuild is one build listed in the job history of a Prow job
+type prowHistoryBuild struct {
+	BuildID string
+	// Path is the build directory, or for presubmits the pr-logs/directory pointer to it
+	Path string
+}
+
+// prowJobDocument mirrors the prowjob.json object Prow writes into each build directory
+type prowJobDocument struct {
+	Metadata struct {
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Spec   ProwJobSpec   `json:"spec"`
+	Status ProwJobStatus `json:"status"`
+}
+
+// prowHistoryJobs returns the Prow jobs of a scope whose history is walked: the jobs
+// collected by earlier runs and the jobs of the live snapshot matching the scope.
+func prowHistoryJobs(db dal.Dal, connectionId uint64, scopeId string, liveJobs []ProwJob) ([]prowHistoryJob, errors.Error) {
+	var rows []struct {
+		JobName     string
+		TriggerType string
+	}
+	err := db.All(&rows,
+		dal.Select("DISTINCT job_name, trigger_type"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND job_type = ?", connectionId, scopeId, "prow"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load the Prow jobs of the scope")
+	}
+
+	seen := map[string]bool{}
+	var jobs []prowHistoryJob
+	add := func(job prowHistoryJob) {
+		if job.Name == "" || seen[job.Name] {
+			return
+		}
+		seen[job.Name] = true
+		jobs = append(jobs, job)
+	}
+	for i := range liveJobs {
+		add(prowHistoryJob{Name: liveJobs[i].Spec.Job, Type: liveJobs[i].Spec.Type})
+	}
+	for _, row := range rows {
+		add(prowHistoryJob{Name: row.JobName, Type: prowTypeOfTrigger(row.TriggerType)})
+	}
+	return jobs, nil
+}
+
+// prowTypeOfTrigger maps a trigger type back to the Prow job type, the reverse of mapTriggerType
+func prowTypeOfTrigger(triggerType string) string {
+	switch triggerType {
+	case "pull_request":
+		return "presubmit"
+	case "periodic":
+		return "periodic"
+	default:
+		return "postsubmit"
+	}
+}
+
+// listProwHistoryBuilds lists the builds of a Prow job in the bucket, newest first. Presubmit
+// builds are spread across pull requests, so they are listed from the pr-logs/directory index
+// where each build is a <build>.txt object pointing at its directory.
+func listProwHistoryBuilds(ctx context.Context, store JobHistoryStore, job prowHistoryJob) ([]prowHistoryBuild, error) {
+	var paths []string
+	var err error
+	if job.Type == "presubmit" {
+		paths, err = store.ListObjectNames(ctx, fmt.Sprintf("pr-logs/directory/%s/", job.Name))
+	} else {
+		paths, err = store.ListSubdirectories(ctx, fmt.Sprintf("logs/%s/", job.Name))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	builds := make([]prowHistoryBuild, 0, len(paths))
+	for _, path := range paths {
+		buildID := strings.TrimSuffix(gcshelper.LastSegment(path), ".txt")
+		if buildID == "" || strings.HasSuffix(path, "latest-build.txt") {
+			continue
+		}
+		builds = append(builds, prowHistoryBuild{BuildID: buildID, Path: path})
+	}
+	// Build IDs grow with time, the ones that are not numbers go last
+	sort.SliceStable(builds, func(i, j int) bool {
+		a, errA := strconv.ParseInt(builds[i].BuildID, 10, 64)
+		b, errB := strconv.ParseInt(builds[j].BuildID, 10, 64)
+		if errA != nil || errB != nil {
+			return errA == nil && errB != nil
+		}
+		return a > b
+	})
+	return builds, nil
+}
+
+// readProwHistoryBuild reads the prowjob.json of a listed build. A presubmit pointer is
+// resolved first, its content being the gs:// URL of the build directory.
+func readProwHistoryBuild(ctx context.Context, store JobHistoryStore, build prowHistoryBuild) (*ProwJob, error) {
+	dir := build.Path
+	if strings.HasSuffix(dir, ".txt") {
+		pointer, err := store.ReadFile(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		dir = strings.TrimPrefix(strings.TrimSpace(string(pointer)), "gs://")
+		// Drop the bucket name, the store is already bound to the bucket
+		if slash := strings.Index(dir, "/"); slash >= 0 {
+			dir = dir[slash+1:]
+		}
+	}
+	dir = strings.TrimSuffix(dir, "/") + "/"
+
+	content, err := store.ReadFile(ctx, dir+"prowjob.json")
+	if err != nil {
+		return nil, err
+	}
+	var doc prowJobDocument
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parse prowjob.json at %s: %w", dir, err)
+	}
+	job := &ProwJob{Spec: doc.Spec, Status: doc.Status, Labels: doc.Metadata.Labels}
+	if job.Status.BuildID == "" {
+		job.Status.BuildID = build.BuildID
+	}
+	return job, nil
+}
+
+// collectProwHistory walks the GCS job history of the given Prow jobs, newest build first, and
+// returns the builds the live snapshot no longer holds. Builds already collected or present in
+// the snapshot are skipped without being read or counted, so up to maxDepth new builds are read
+// per job and older builds get backfilled run after run. A job's walk stops at the first build
+// completed before the incremental window.
+//
+// Parameters:
+//   - ctx: Context of the GCS requests
+//   - logger: Logger for the builds that cannot be listed or read
+//   - store: The bucket holding the job history
+//   - jobs: The Prow jobs to walk
+//   - maxDepth: The maximum number of new builds read per job
+//   - window: The incremental window of the run
+//   - liveBuildIDs: The build IDs of the live snapshot
+//
+// Returns:
+//   - []ProwJob: The historical Prow jobs, to be processed like the live ones
+func collectProwHistory(ctx context.Context, logger log.Logger, store JobHistoryStore, jobs []prowHistoryJob, maxDepth int, window *prowIncrementalWindow, liveBuildIDs map[string]bool) []ProwJob {
+	var history []ProwJob
+	for _, job := range jobs {
+		builds, err := listProwHistoryBuilds(ctx, store, job)
+		if err != nil {
+			logger.Warn(err, "failed to list the Prow job history", "job_name", job.Name)
+			continue
+		}
+		walked := 0
+		for _, build := range builds {
+			if liveBuildIDs[build.BuildID] || window.Ingested[build.BuildID] {
+				continue
+			}
+			if walked >= maxDepth {
+				break
+			}
+			walked++
+			prowJob, err := readProwHistoryBuild(ctx, store, build)
+			if err != nil {
+				logger.Warn(err, "failed to read a Prow job from its history", "job_name", job.Name, "build_id", build.BuildID)
+				continue
+			}
+			if completedAt := prowJobCompletionTime(prowJob); window.Start != nil && completedAt != nil && completedAt.Before(*window.Start) {
+				break
+			}
+			history = append(history, *prowJob)
+		}
+	}
+	return history
+}
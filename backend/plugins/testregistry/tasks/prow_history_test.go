@@ -0,0 +1,178 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJobHistoryStore serves a job history from an in-memory object map
+type fakeJobHistoryStore struct {
+	objects map[string]string
+	reads   []string
+}
+
+func (s *fakeJobHistoryStore) ListSubdirectories(_ context.Context, prefix string) ([]string, error) {
+	seen := map[string]bool{}
+	var dirs []string
+	for name := range s.objects {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok || !strings.Contains(rest, "/") {
+			continue
+		}
+		dir := prefix + rest[:strings.Index(rest, "/")+1]
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+func (s *fakeJobHistoryStore) ListObjectNames(_ context.Context, prefix string) ([]string, error) {
+	var names []string
+	for name := range s.objects {
+		if rest, ok := strings.CutPrefix(name, prefix); ok && !strings.Contains(rest, "/") {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func (s *fakeJobHistoryStore) ReadFile(_ context.Context, path string) ([]byte, error) {
+	s.reads = append(s.reads, path)
+	content, ok := s.objects[path]
+	if !ok {
+		return nil, fmt.Errorf("object %s not found", path)
+	}
+	return []byte(content), nil
+}
+
+func prowJobJSON(jobName, jobType, buildId, completionTime string) string {
+	return fmt.Sprintf(`{"kind":"ProwJob","metadata":{"labels":{"prow.k8s.io/refs.org":"org","prow.k8s.io/refs.repo":"repo"}},`+
+		`"spec":{"job":%q,"type":%q},"status":{"state":"success","completionTime":%q,"build_id":%q}}`, jobName, jobType, completionTime, buildId)
+}
+
+func TestListProwHistoryBuilds(t *testing.T) {
+	store := &fakeJobHistoryStore{objects: map[string]string{
+		"logs/periodic-e2e/9/prowjob.json":                     "{}",
+		"logs/periodic-e2e/100/prowjob.json":                   "{}",
+		"logs/periodic-e2e/latest-build.txt":                   "100",
+		"pr-logs/directory/pull-e2e/7.txt":                     "gs://bucket/pr-logs/pull/org_repo/1/pull-e2e/7",
+		"pr-logs/directory/pull-e2e/12.txt":                    "gs://bucket/pr-logs/pull/org_repo/2/pull-e2e/12",
+		"pr-logs/directory/pull-e2e/latest-build.txt":          "12",
+		"pr-logs/pull/org_repo/2/pull-e2e/12/prowjob.json":     "{}",
+		"logs/periodic-e2e-other/1/prowjob.json":               "{}",
+		"pr-logs/directory/pull-e2e-other/3.txt":               "gs://bucket/x",
+		"pr-logs/directory/pull-e2e/nested/ignored/object.txt": "",
+	}}
+
+	builds, err := listProwHistoryBuilds(context.Background(), store, prowHistoryJob{Name: "periodic-e2e", Type: "periodic"})
+	assert.NoError(t, err)
+	assert.Equal(t, []prowHistoryBuild{
+		{BuildID: "100", Path: "logs/periodic-e2e/100/"},
+		{BuildID: "9", Path: "logs/periodic-e2e/9/"},
+	}, builds)
+
+	builds, err = listProwHistoryBuilds(context.Background(), store, prowHistoryJob{Name: "pull-e2e", Type: "presubmit"})
+	assert.NoError(t, err)
+	assert.Equal(t, []prowHistoryBuild{
+		{BuildID: "12", Path: "pr-logs/directory/pull-e2e/12.txt"},
+		{BuildID: "7", Path: "pr-logs/directory/pull-e2e/7.txt"},
+	}, builds)
+}
+
+func TestReadProwHistoryBuild(t *testing.T) {
+	store := &fakeJobHistoryStore{objects: map[string]string{
+		"pr-logs/directory/pull-e2e/12.txt":                "gs://test-platform-results/pr-logs/pull/org_repo/2/pull-e2e/12\n",
+		"pr-logs/pull/org_repo/2/pull-e2e/12/prowjob.json": prowJobJSON("pull-e2e", "presubmit", "12", "2025-02-02T10:00:00Z"),
+		"logs/periodic-e2e/5/prowjob.json":                 prowJobJSON("periodic-e2e", "periodic", "", "2025-02-02T10:00:00Z"),
+		"logs/periodic-e2e/6/prowjob.json":                 "not json",
+	}}
+
+	job, err := readProwHistoryBuild(context.Background(), store, prowHistoryBuild{BuildID: "12", Path: "pr-logs/directory/pull-e2e/12.txt"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pull-e2e", job.Spec.Job)
+	assert.Equal(t, "12", job.Status.BuildID)
+	assert.Equal(t, "org", job.Labels["prow.k8s.io/refs.org"])
+
+	job, err = readProwHistoryBuild(context.Background(), store, prowHistoryBuild{BuildID: "5", Path: "logs/periodic-e2e/5/"})
+	assert.NoError(t, err)
+	assert.Equal(t, "5", job.Status.BuildID, "the build directory names a job without build_id")
+
+	_, err = readProwHistoryBuild(context.Background(), store, prowHistoryBuild{BuildID: "6", Path: "logs/periodic-e2e/6/"})
+	assert.Error(t, err)
+}
+
+func TestCollectProwHistory(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	objects := map[string]string{}
+	for build, completionTime := range map[string]string{
+		"105": "2025-02-05T10:00:00Z",
+		"104": "2025-02-04T10:00:00Z",
+		"103": "2025-02-03T10:00:00Z",
+		"102": "2025-02-02T10:00:00Z",
+		"101": "2025-01-31T10:00:00Z",
+		"100": "2025-01-30T10:00:00Z",
+	} {
+		objects["logs/periodic-e2e/"+build+"/prowjob.json"] = prowJobJSON("periodic-e2e", "periodic", build, completionTime)
+	}
+	jobs := []prowHistoryJob{{Name: "periodic-e2e", Type: "periodic"}, {Name: "periodic-gone", Type: "periodic"}}
+	buildIDs := func(history []ProwJob) []string {
+		var ids []string
+		for i := range history {
+			ids = append(ids, history[i].Status.BuildID)
+		}
+		return ids
+	}
+
+	t.Run("skips live and collected builds unread and stops before the window", func(t *testing.T) {
+		store := &fakeJobHistoryStore{objects: objects}
+		window := &prowIncrementalWindow{Start: &start, Ingested: map[string]bool{"104": true}}
+		history := collectProwHistory(context.Background(), newMockLogger(), store, jobs, 10, window, map[string]bool{"105": true})
+
+		assert.Equal(t, []string{"103", "102"}, buildIDs(history))
+		assert.Equal(t, []string{
+			"logs/periodic-e2e/103/prowjob.json",
+			"logs/periodic-e2e/102/prowjob.json",
+			"logs/periodic-e2e/101/prowjob.json",
+		}, store.reads)
+	})
+
+	t.Run("reads at most maxDepth new builds per job", func(t *testing.T) {
+		store := &fakeJobHistoryStore{objects: objects}
+		window := &prowIncrementalWindow{Ingested: map[string]bool{}}
+		history := collectProwHistory(context.Background(), newMockLogger(), store, jobs, 3, window, map[string]bool{"105": true})
+
+		assert.Equal(t, []string{"104", "103", "102"}, buildIDs(history))
+	})
+
+	t.Run("collected builds don't count against maxDepth, so older builds are backfilled", func(t *testing.T) {
+		store := &fakeJobHistoryStore{objects: objects}
+		window := &prowIncrementalWindow{Ingested: map[string]bool{"105": true, "104": true, "103": true}}
+		history := collectProwHistory(context.Background(), newMockLogger(), store, jobs, 2, window, map[string]bool{})
+
+		assert.Equal(t, []string{"102", "101"}, buildIDs(history))
+	})
+}
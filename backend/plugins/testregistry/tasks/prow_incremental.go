@@ -0,0 +1,145 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// prowIncrementalWindow decides which Prow jobs of the scope a run still has to process
+type prowIncrementalWindow struct {
+	// Start skips the jobs that completed before it, nil to keep all of them
+	Start *time.Time
+	// Ingested holds the IDs of the jobs already saved since Start
+	Ingested map[string]bool
+	// Latest is the latest completion time of the jobs processed by this run or an earlier one
+	Latest time.Time
+}
+
+// prowCollectionStart returns where an incremental Prow collection of a scope starts
+//
+// Parameters:
+//   - syncPolicy: The sync policy of the run, its timeAfter bounds the window
+//   - cursor: The persisted cursor of the scope, nil if none
+//
+// Returns:
+//   - *time.Time: The later of timeAfter and the cursor minus backfillCursorOverlap, nil if neither is set.
+//     A full sync ignores the cursor
+func prowCollectionStart(syncPolicy *coreModels.SyncPolicy, cursor *time.Time) *time.Time {
+	var timeAfter *time.Time
+	fullSync := false
+	if syncPolicy != nil {
+		timeAfter = syncPolicy.TimeAfter
+		fullSync = syncPolicy.FullSync
+	}
+	if fullSync || cursor == nil {
+		return timeAfter
+	}
+	resume := cursor.Add(-backfillCursorOverlap)
+	if timeAfter != nil && timeAfter.After(resume) {
+		return timeAfter
+	}
+	return &resume
+}
+
+// newProwIncrementalWindow loads the cursor of the scope and the IDs of the jobs it already
+// collected, so they are skipped before their raw data is saved or converted. A full sync
+// processes every job again.
+func newProwIncrementalWindow(db dal.Dal, syncPolicy *coreModels.SyncPolicy, connectionId uint64, scopeId string) (*prowIncrementalWindow, errors.Error) {
+	cursor := loadProwCursor(db, connectionId, scopeId)
+	window := &prowIncrementalWindow{
+		Start:    prowCollectionStart(syncPolicy, cursor),
+		Ingested: map[string]bool{},
+	}
+	if cursor != nil {
+		window.Latest = *cursor
+	}
+	if syncPolicy != nil && syncPolicy.FullSync {
+		return window, nil
+	}
+
+	clauses := []dal.Clause{
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND job_type = ?", connectionId, scopeId, "prow"),
+	}
+	if window.Start != nil {
+		clauses = append(clauses, dal.Where("finished_at >= ?", *window.Start))
+	}
+	var jobIds []string
+	if err := db.Pluck("job_id", &jobIds, clauses...); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load the collected Prow job IDs")
+	}
+	for _, jobId := range jobIds {
+		window.Ingested[jobId] = true
+	}
+	return window, nil
+}
+
+// skip reports whether a Prow job completed before the window or was already collected.
+// Jobs without a completion time are always processed.
+func (window *prowIncrementalWindow) skip(job *ProwJob) bool {
+	completedAt := prowJobCompletionTime(job)
+	if window.Start != nil && completedAt != nil && completedAt.Before(*window.Start) {
+		return true
+	}
+	return window.Ingested[extractJobID(job)]
+}
+
+// observe moves Latest to the completion time of a processed job if it is later
+func (window *prowIncrementalWindow) observe(job *ProwJob) {
+	if completedAt := prowJobCompletionTime(job); completedAt != nil && completedAt.After(window.Latest) {
+		window.Latest = *completedAt
+	}
+}
+
+// prowJobCompletionTime returns when a Prow job completed, nil if it has no valid completion time
+func prowJobCompletionTime(job *ProwJob) *time.Time {
+	if job.Status.CompletionTime == "" {
+		return nil
+	}
+	completedAt, err := common.ConvertStringToTime(job.Status.CompletionTime)
+	if err != nil {
+		return nil
+	}
+	return &completedAt
+}
+
+// loadProwCursor returns the persisted Prow cursor of a scope, nil if there is none
+func loadProwCursor(db dal.Dal, connectionId uint64, scopeId string) *time.Time {
+	cursor := &models.ProwCollectionCursor{}
+	err := db.First(cursor, dal.Where("connection_id = ? AND scope_id = ?", connectionId, scopeId))
+	if err != nil || cursor.LatestCompletionTime.IsZero() {
+		return nil
+	}
+	return &cursor.LatestCompletionTime
+}
+
+// saveProwCursor persists the latest completion time of the Prow jobs collected for a scope
+func saveProwCursor(db dal.Dal, connectionId uint64, scopeId string, latest time.Time) errors.Error {
+	return db.CreateOrUpdate(&models.ProwCollectionCursor{
+		ConnectionId:         connectionId,
+		ScopeId:              scopeId,
+		LatestCompletionTime: latest,
+	})
+}
@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	coreModels "github.com/apache/incubator-devlake/core/models"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestProwCollectionStart(t *testing.T) {
+	timeAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	incremental := &coreModels.SyncPolicy{TimeAfter: &timeAfter}
+	fullSync := &coreModels.SyncPolicy{TimeAfter: &timeAfter, TriggerSyncPolicy: coreModels.TriggerSyncPolicy{FullSync: true}}
+
+	assert.Nil(t, prowCollectionStart(nil, nil))
+	assert.Equal(t, &timeAfter, prowCollectionStart(incremental, nil))
+	assert.Equal(t, cursor.Add(-backfillCursorOverlap), *prowCollectionStart(incremental, &cursor))
+	assert.Equal(t, cursor.Add(-backfillCursorOverlap), *prowCollectionStart(nil, &cursor))
+	assert.Equal(t, &timeAfter, prowCollectionStart(fullSync, &cursor))
+
+	// a timeAfter later than the cursor wins
+	early := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, &timeAfter, prowCollectionStart(incremental, &early))
+}
+
+func TestProwIncrementalWindow(t *testing.T) {
+	start := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	window := &prowIncrementalWindow{Start: &start, Ingested: map[string]bool{"101": true}, Latest: start}
+	job := func(buildId, completionTime string) *ProwJob {
+		prowJob := &ProwJob{}
+		prowJob.Status.BuildID = buildId
+		prowJob.Status.CompletionTime = completionTime
+		return prowJob
+	}
+
+	assert.True(t, window.skip(job("100", "2025-01-31T23:00:00Z")), "completed before the window")
+	assert.True(t, window.skip(job("101", "2025-02-02T10:00:00Z")), "already collected")
+	assert.False(t, window.skip(job("102", "2025-02-02T10:00:00Z")))
+	assert.False(t, window.skip(job("103", "")), "no completion time")
+
+	window.observe(job("102", "2025-02-02T10:00:00Z"))
+	window.observe(job("104", "2025-02-01T10:00:00Z"))
+	window.observe(job("103", ""))
+	assert.Equal(t, time.Date(2025, 2, 2, 10, 0, 0, 0, time.UTC), window.Latest.UTC())
+
+	assert.False(t, (&prowIncrementalWindow{Ingested: map[string]bool{}}).skip(job("100", "2020-01-01T00:00:00Z")), "no window start")
+}
+
+func TestNewProwIncrementalWindow(t *testing.T) {
+	cursor := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("resumes from the cursor and loads the collected job IDs", func(t *testing.T) {
+		db := new(mockdal.Dal)
+		db.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*models.ProwCollectionCursor).LatestCompletionTime = cursor
+		}).Return(nil)
+		db.On("Pluck", "job_id", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(1).(*[]string) = []string{"101", "102"}
+		}).Return(nil)
+
+		window, err := newProwIncrementalWindow(db, &coreModels.SyncPolicy{}, 1, "integration-service")
+
+		assert.Nil(t, err)
+		assert.Equal(t, cursor.Add(-backfillCursorOverlap), *window.Start)
+		assert.Equal(t, cursor, window.Latest)
+		assert.Equal(t, map[string]bool{"101": true, "102": true}, window.Ingested)
+	})
+
+	t.Run("first collection loads every collected job ID", func(t *testing.T) {
+		db := new(mockdal.Dal)
+		db.On("First", mock.Anything, mock.Anything).Return(errors.NotFound.New("record not found"))
+		db.On("Pluck", "job_id", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+		window, err := newProwIncrementalWindow(db, nil, 1, "integration-service")
+
+		assert.Nil(t, err)
+		assert.Nil(t, window.Start)
+		assert.True(t, window.Latest.IsZero())
+		assert.Empty(t, window.Ingested)
+	})
+
+	t.Run("full sync processes every job again", func(t *testing.T) {
+		db := new(mockdal.Dal)
+		db.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*models.ProwCollectionCursor).LatestCompletionTime = cursor
+		}).Return(nil)
+
+		window, err := newProwIncrementalWindow(db, &coreModels.SyncPolicy{TriggerSyncPolicy: coreModels.TriggerSyncPolicy{FullSync: true}}, 1, "integration-service")
+
+		assert.Nil(t, err)
+		assert.Nil(t, window.Start)
+		assert.Equal(t, cursor, window.Latest)
+		assert.Empty(t, window.Ingested)
+		db.AssertNotCalled(t, "Pluck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,208 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"time"
+	"unicode/utf8"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/qa"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// ConvertTestCasesMeta defines the metadata for the JUnit test case converter subtask
+var ConvertTestCasesMeta = plugin.SubTaskMeta{
+	Name:             "convertTestCases",
+	EntryPoint:       ConvertTestCases,
+	EnabledByDefault: true,
+	Description:      "Convert the JUnit test cases of the scope into the domain layer qa_projects, qa_test_cases and qa_test_case_executions tables",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD, plugin.DOMAIN_TYPE_CODE_QUALITY},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta, &CollectTektonJobsMeta, &CollectKubernetesPipelineRunsMeta},
+}
+
+// maxQaTestCaseNameLength is the size of the qa_test_cases.name column
+const maxQaTestCaseNameLength = 255
+
+// qaTestCaseRow is a ci_test_cases row with the name of its suite and the dates of its job
+type qaTestCaseRow struct {
+	models.TestCase
+	SuiteName     string
+	JobQueuedAt   *time.Time
+	JobStartedAt  *time.Time
+	JobFinishedAt *time.Time
+}
+
+// ConvertTestCases maps the JUnit results of the scope into the qa domain layer, so test
+// results from Prow and Tekton blend with the other QA data sources in the dashboards.
+//
+// The scope becomes a qa_projects row sharing the ID of its cicd scope. Each test identity
+// (see TestIdentityNormalizer) becomes one qa_test_cases row, created at its first run, and
+// each ci_test_cases row an execution of it dated by its job.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered during the conversion, or nil if successful
+func ConvertTestCases(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	db := taskCtx.GetDal()
+	connectionId := data.Options.ConnectionId
+
+	scopeName := data.Options.FullName
+	scope := &models.TestRegistryScope{}
+	if err := db.First(scope, dal.Where("connection_id = ? AND full_name = ?", connectionId, data.Options.FullName)); err == nil && scope.Name != "" {
+		scopeName = scope.Name
+	}
+	qaProject := &qa.QaProject{
+		DomainEntityExtended: domainlayer.DomainEntityExtended{
+			Id: didgen.NewDomainIdGenerator(&models.TestRegistryScope{}).Generate(connectionId, data.Options.FullName),
+		},
+		Name: scopeName,
+	}
+	if err := db.CreateOrUpdate(qaProject); err != nil {
+		return errors.Default.Wrap(err, "failed to save the qa project")
+	}
+
+	// Oldest jobs first, so a test case is created at its first run
+	cursor, err := db.Cursor(
+		dal.Select("ci_test_cases.*, ci_test_suites.name AS suite_name, ci_test_jobs.queued_at AS job_queued_at, "+
+			"ci_test_jobs.started_at AS job_started_at, ci_test_jobs.finished_at AS job_finished_at"),
+		dal.From(&models.TestCase{}),
+		dal.Join("JOIN ci_test_jobs ON ci_test_jobs.connection_id = ci_test_cases.connection_id AND ci_test_jobs.job_id = ci_test_cases.job_id"),
+		dal.Join("LEFT JOIN ci_test_suites ON ci_test_suites.connection_id = ci_test_cases.connection_id "+
+			"AND ci_test_suites.job_id = ci_test_cases.job_id AND ci_test_suites.suite_id = ci_test_cases.suite_id"),
+		dal.Where("ci_test_jobs.connection_id = ? AND ci_test_jobs.scope_id = ?", connectionId, data.Options.FullName),
+		dal.Orderby("ci_test_jobs.started_at, ci_test_cases.job_id"),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load the test cases of the scope")
+	}
+	defer cursor.Close()
+
+	seenTestCases := make(map[string]bool)
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		RawDataSubTaskArgs: helper.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: TestRegistryApiParams{
+				ConnectionId: connectionId,
+				FullName:     data.Options.FullName,
+			},
+			Table: RAW_PROW_TABLE,
+		},
+		InputRowType: reflect.TypeOf(qaTestCaseRow{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			row := inputRow.(*qaTestCaseRow)
+			qaTestCase, execution := convertTestCaseToDomain(row, data.Options.FullName, qaProject.Id, data.TestIdentityNormalizer)
+			if seenTestCases[qaTestCase.Id] {
+				return []interface{}{execution}, nil
+			}
+			seenTestCases[qaTestCase.Id] = true
+			return []interface{}{qaTestCase, execution}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+// convertTestCaseToDomain maps a test case run into its domain test case and execution
+//
+// Parameters:
+//   - row: The test case run with its suite name and job dates
+//   - fullName: Full name of the scope, the test case IDs are unique per scope
+//   - qaProjectId: Domain ID of the scope
+//   - normalizer: Builds the test identity of rows stored before test_identity existed (may be nil)
+//
+// Returns:
+//   - *qa.QaTestCase: The test case shared by all runs of the test identity
+//   - *qa.QaTestCaseExecution: The execution of the test case in the job
+func convertTestCaseToDomain(row *qaTestCaseRow, fullName, qaProjectId string, normalizer *TestIdentityNormalizer) (*qa.QaTestCase, *qa.QaTestCaseExecution) {
+	identity := row.TestIdentity
+	if identity == "" {
+		identity = normalizer.Identity(row.SuiteName, row.Name)
+	}
+	hash := sha256.Sum256([]byte(fullName + "\x00" + identity))
+	// qa_test_cases.name is shorter than the test_identity column
+	name := identity
+	if utf8.RuneCountInString(name) > maxQaTestCaseNameLength {
+		name = string([]rune(name)[:maxQaTestCaseNameLength])
+	}
+
+	createTime := time.Time{}
+	for _, date := range []*time.Time{row.JobQueuedAt, row.JobStartedAt, row.JobFinishedAt} {
+		if date != nil {
+			createTime = *date
+			break
+		}
+	}
+	// JUnit only reports durations, the cases are dated by the start of their job
+	startTime := createTime
+	if row.JobStartedAt != nil {
+		startTime = *row.JobStartedAt
+	}
+
+	qaTestCase := &qa.QaTestCase{
+		DomainEntityExtended: domainlayer.DomainEntityExtended{
+			Id: fmt.Sprintf("testregistry:%d:testcase:%s", row.ConnectionId, hex.EncodeToString(hash[:16])),
+		},
+		Name:        name,
+		CreateTime:  createTime,
+		Type:        "functional",
+		QaProjectId: qaProjectId,
+	}
+	execution := &qa.QaTestCaseExecution{
+		DomainEntityExtended: domainlayer.DomainEntityExtended{
+			Id: didgen.NewDomainIdGenerator(&models.TestCase{}).Generate(row.ConnectionId, row.JobId, row.SuiteId, row.TestCaseId),
+		},
+		QaProjectId:  qaProjectId,
+		QaTestCaseId: qaTestCase.Id,
+		CreateTime:   createTime,
+		StartTime:    startTime,
+		FinishTime:   startTime.Add(time.Duration(row.Duration * float64(time.Second))),
+		Status:       convertTestCaseStatus(row.Status),
+		// Skipped test cases did not run, they are kept but left out of the pass rates
+		IsInvalid: row.Status == "skipped",
+	}
+	return qaTestCase, execution
+}
+
+// convertTestCaseStatus maps ci_test_cases.status to the domain execution status
+func convertTestCaseStatus(status string) string {
+	switch status {
+	case "passed":
+		return "SUCCESS"
+	case "failed", "errored":
+		return "FAILED"
+	default:
+		return "PENDING"
+	}
+}
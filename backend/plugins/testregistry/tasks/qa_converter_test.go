@@ -0,0 +1,81 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertTestCaseToDomain(t *testing.T) {
+	mockTestRegistryPlugin(t)
+	queuedAt := time.Date(2025, 3, 1, 9, 58, 0, 0, time.UTC)
+	startedAt := time.Date(2025, 3, 1, 10, 0, 0, 0, time.UTC)
+	newRow := func(jobId, suiteName, name, identity, status string) *qaTestCaseRow {
+		row := &qaTestCaseRow{SuiteName: suiteName, JobQueuedAt: &queuedAt, JobStartedAt: &startedAt}
+		row.ConnectionId = 1
+		row.JobId = jobId
+		row.SuiteId = "suite-" + jobId
+		row.TestCaseId = "case-" + name
+		row.Name = name
+		row.TestIdentity = identity
+		row.Status = status
+		row.Duration = 1.5
+		return row
+	}
+
+	t.Run("passed test case", func(t *testing.T) {
+		testCase, execution := convertTestCaseToDomain(newRow("101", "unit", "TestA", "unit::TestA", "passed"), "konflux-ci/e2e", "qa-project", nil)
+		assert.Equal(t, "unit::TestA", testCase.Name)
+		assert.Equal(t, "qa-project", testCase.QaProjectId)
+		assert.Equal(t, queuedAt, testCase.CreateTime)
+		assert.Equal(t, testCase.Id, execution.QaTestCaseId)
+		assert.Equal(t, "qa-project", execution.QaProjectId)
+		assert.Equal(t, "SUCCESS", execution.Status)
+		assert.False(t, execution.IsInvalid)
+		assert.Equal(t, startedAt, execution.StartTime)
+		assert.Equal(t, startedAt.Add(1500*time.Millisecond), execution.FinishTime)
+	})
+
+	t.Run("runs of a test identity share the test case", func(t *testing.T) {
+		first, firstExecution := convertTestCaseToDomain(newRow("101", "unit", "TestA", "unit::TestA", "passed"), "konflux-ci/e2e", "qa-project", nil)
+		second, secondExecution := convertTestCaseToDomain(newRow("102", "unit", "TestA", "unit::TestA", "failed"), "konflux-ci/e2e", "qa-project", nil)
+		assert.Equal(t, first.Id, second.Id)
+		assert.NotEqual(t, firstExecution.Id, secondExecution.Id)
+		assert.Equal(t, "FAILED", secondExecution.Status)
+
+		otherScope, _ := convertTestCaseToDomain(newRow("101", "unit", "TestA", "unit::TestA", "passed"), "konflux-ci/other", "qa-project", nil)
+		assert.NotEqual(t, first.Id, otherScope.Id)
+	})
+
+	t.Run("rows without a test identity are identified by suite and name", func(t *testing.T) {
+		testCase, execution := convertTestCaseToDomain(newRow("101", "e2e-aws / Konflux E2E", "TestB", "", "skipped"), "konflux-ci/e2e", "qa-project", nil)
+		assert.Equal(t, "e2e-aws / Konflux E2E::TestB", testCase.Name)
+		assert.Equal(t, "PENDING", execution.Status)
+		assert.True(t, execution.IsInvalid)
+	})
+
+	t.Run("long test identities are cut to the name column", func(t *testing.T) {
+		identity := "e2e::" + strings.Repeat("x", 400)
+		testCase, _ := convertTestCaseToDomain(newRow("101", "e2e", identity[5:], identity, "passed"), "konflux-ci/e2e", "qa-project", nil)
+		assert.Len(t, testCase.Name, maxQaTestCaseNameLength)
+	})
+}
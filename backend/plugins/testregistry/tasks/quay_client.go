@@ -27,14 +27,16 @@ import (
 
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
 )
 
 // QuayClient wraps a Quay.io API client for listing artifacts/tags
 // Similar to GCSBucket for Openshift CI
 type QuayClient struct {
-	baseURL    string
-	httpClient *http.Client
-	logger     log.Logger
+	baseURL       string
+	authorization string // Authorization header of API requests, empty for public repositories
+	httpClient    *http.Client
+	logger        log.Logger
 }
 
 // QuayTag represents a tag from Quay.io API
@@ -63,17 +65,42 @@ type QuayTagsResponse struct {
 //
 // Parameters:
 //   - ctx: Context for the operation
+//   - connection: Connection holding the Quay.io credentials, nil or without credentials for anonymous access
 //   - logger: Logger for output
 //
 // Returns:
 //   - *QuayClient: The Quay.io client instance
 //   - errors.Error: Any error encountered during client creation
-func NewQuayClient(ctx context.Context, logger log.Logger) (*QuayClient, errors.Error) {
-	return &QuayClient{
+func NewQuayClient(ctx context.Context, connection *models.TestRegistryConnection, logger log.Logger) (*QuayClient, errors.Error) {
+	client := &QuayClient{
 		baseURL:    "https://quay.io",
 		httpClient: &http.Client{},
 		logger:     logger,
-	}, nil
+	}
+	if connection != nil {
+		client.authorization = connection.QuayApiAuthorization()
+	}
+	return client, nil
+}
+
+// newRequest creates a Quay.io API request carrying the credentials of the client
+func (c *QuayClient) newRequest(ctx context.Context, apiURL string) (*http.Request, errors.Error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to create request")
+	}
+	if c.authorization != "" {
+		req.Header.Set("Authorization", c.authorization)
+	}
+	return req, nil
+}
+
+// quayStatusError describes a Quay.io API error status, pointing at the credentials when access is denied
+func quayStatusError(status int, what string) errors.Error {
+	if status == http.StatusUnauthorized || status == http.StatusForbidden {
+		return errors.Unauthorized.New(fmt.Sprintf("Quay.io API returned status %d for %s, check the Quay.io credentials of the connection", status, what))
+	}
+	return errors.Default.New(fmt.Sprintf("Quay.io API returned status %d for %s", status, what))
 }
 
 // ListTags lists all tags for a repository with optional date filtering
@@ -99,9 +126,9 @@ func (c *QuayClient) ListTags(ctx context.Context, org, repo string, since, unti
 
 	for hasMore {
 		// Build request with pagination
-		req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-		if err != nil {
-			return nil, errors.Default.Wrap(err, "failed to create request")
+		req, reqErr := c.newRequest(ctx, apiURL)
+		if reqErr != nil {
+			return nil, reqErr
 		}
 
 		// If we're not using NextPage (first iteration or fallback), add page parameter manually
@@ -120,7 +147,7 @@ func (c *QuayClient) ListTags(ctx context.Context, org, repo string, since, unti
 		defer resp.Body.Close()
 
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Default.New(fmt.Sprintf("Quay.io API returned status %d for tags", resp.StatusCode))
+			return nil, quayStatusError(resp.StatusCode, "tags")
 		}
 
 		var tagsResponse QuayTagsResponse
@@ -178,9 +205,9 @@ func (c *QuayClient) ListTags(ctx context.Context, org, repo string, since, unti
 func (c *QuayClient) GetTagByName(ctx context.Context, org, repo, tagName string) (*QuayTag, errors.Error) {
 	apiURL := fmt.Sprintf("%s/api/v1/repository/%s/%s/tag/%s", c.baseURL, org, repo, tagName)
 
-	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
-	if err != nil {
-		return nil, errors.Default.Wrap(err, "failed to create request")
+	req, reqErr := c.newRequest(ctx, apiURL)
+	if reqErr != nil {
+		return nil, reqErr
 	}
 
 	resp, err := c.httpClient.Do(req)
@@ -194,7 +221,7 @@ func (c *QuayClient) GetTagByName(ctx context.Context, org, repo, tagName string
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.Default.New(fmt.Sprintf("Quay.io API returned status %d for tag %s", resp.StatusCode, tagName))
+		return nil, quayStatusError(resp.StatusCode, "tag "+tagName)
 	}
 
 	var tag QuayTag
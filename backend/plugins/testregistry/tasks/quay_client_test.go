@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuayClientListTagsAuthorization(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer oauth-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(QuayTagsResponse{Tags: []QuayTag{{Name: "run-1", StartTS: 1700000000}}})
+	}))
+	t.Cleanup(server.Close)
+
+	anonymous, _ := NewQuayClient(context.Background(), nil, newMockLogger())
+	anonymous.baseURL = server.URL
+	_, err := anonymous.ListTags(context.Background(), "org", "private-repo", nil, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.Unauthorized, err.GetType())
+
+	client, _ := NewQuayClient(context.Background(), &models.TestRegistryConnection{QuayOAuthToken: "oauth-token"}, newMockLogger())
+	client.baseURL = server.URL
+	tags, err := client.ListTags(context.Background(), "org", "private-repo", nil, nil)
+	assert.Nil(t, err)
+	assert.Len(t, tags, 1)
+}
+
+func TestQuayCredentials(t *testing.T) {
+	anonymous := models.TestRegistryConnection{}
+	username, password := anonymous.QuayRegistryCredential()
+	assert.Empty(t, username+password)
+	assert.Empty(t, anonymous.QuayApiAuthorization())
+
+	robot := models.TestRegistryConnection{QuayRobotUsername: "org+robot", QuayRobotToken: "robot-token"}
+	username, password = robot.QuayRegistryCredential()
+	assert.Equal(t, "org+robot", username)
+	assert.Equal(t, "robot-token", password)
+	assert.Equal(t, "Basic b3JnK3JvYm90OnJvYm90LXRva2Vu", robot.QuayApiAuthorization())
+
+	oauth := models.TestRegistryConnection{QuayOAuthToken: "oauth-token"}
+	username, password = oauth.QuayRegistryCredential()
+	assert.Equal(t, "$oauthtoken", username)
+	assert.Equal(t, "oauth-token", password)
+	assert.Equal(t, "Bearer oauth-token", oauth.QuayApiAuthorization())
+
+	// The robot account pulls, the OAuth token reads the API
+	both := models.TestRegistryConnection{QuayRobotUsername: "org+robot", QuayRobotToken: "robot-token", QuayOAuthToken: "oauth-token"}
+	username, _ = both.QuayRegistryCredential()
+	assert.Equal(t, "org+robot", username)
+	assert.Equal(t, "Bearer oauth-token", both.QuayApiAuthorization())
+}
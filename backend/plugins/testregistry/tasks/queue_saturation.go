@@ -0,0 +1,421 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+const (
+	// queueBaselineDays is the period before the recent window the queue times are compared with
+	queueBaselineDays = 7
+	// minQueueSamples is the number of queued runs each window needs before a job is judged
+	minQueueSamples = 5
+	// webhookAllowedHostsEnv lists the hosts, comma separated, the scope config webhooks may be sent to.
+	// An entry starting with a dot also allows its subdomains.
+	webhookAllowedHostsEnv = "TESTREGISTRY_WEBHOOK_ALLOWED_HOSTS"
+)
+
+// DetectQueueSaturationMeta defines the metadata for the queue saturation subtask
+var DetectQueueSaturationMeta = plugin.SubTaskMeta{
+	Name:             "detectQueueSaturation",
+	EntryPoint:       DetectQueueSaturation,
+	EnabledByDefault: true,
+	Description:      "Open an alert when the queue time of a job on a build cluster stays well above its last week, when the scope config sets queueSaturationFactor",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&CollectProwJobsMeta, &CollectTektonJobsMeta, &CollectKubernetesPipelineRunsMeta},
+}
+
+// QueueSaturationRules are the queue saturation settings of a scope
+type QueueSaturationRules struct {
+	Factor     float64
+	Window     time.Duration
+	MinSeconds float64
+}
+
+// NewQueueSaturationRules reads the queue saturation settings of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *QueueSaturationRules: The rules, or nil if queueSaturationFactor is not set (no alert is opened)
+//   - errors.Error: BadInput if the factor is not above 1 or a setting is negative
+func NewQueueSaturationRules(scopeConfig *models.TestRegistryScopeConfig) (*QueueSaturationRules, errors.Error) {
+	if scopeConfig == nil || scopeConfig.QueueSaturationFactor == 0 {
+		return nil, nil
+	}
+	if scopeConfig.QueueSaturationFactor <= 1 {
+		return nil, errors.BadInput.New(fmt.Sprintf("invalid value for `queueSaturationFactor`: %g, expected more than 1", scopeConfig.QueueSaturationFactor))
+	}
+	if scopeConfig.QueueSaturationWindowHours < 0 || scopeConfig.QueueSaturationMinSeconds < 0 {
+		return nil, errors.BadInput.New("`queueSaturationWindowHours` and `queueSaturationMinSeconds` must not be negative")
+	}
+	rules := &QueueSaturationRules{
+		Factor:     scopeConfig.QueueSaturationFactor,
+		Window:     time.Duration(models.DefaultQueueSaturationWindowHours) * time.Hour,
+		MinSeconds: models.DefaultQueueSaturationMinSeconds,
+	}
+	if scopeConfig.QueueSaturationWindowHours > 0 {
+		rules.Window = time.Duration(scopeConfig.QueueSaturationWindowHours) * time.Hour
+	}
+	if scopeConfig.QueueSaturationMinSeconds > 0 {
+		rules.MinSeconds = float64(scopeConfig.QueueSaturationMinSeconds)
+	}
+	return rules, nil
+}
+
+// queueTimeSample is the queue time of one run
+type queueTimeSample struct {
+	Cluster           string
+	JobName           string
+	QueuedAt          time.Time
+	QueuedDurationSec float64
+}
+
+// queueSaturation is a job of a cluster whose recent queue time is too high
+type queueSaturation struct {
+	Cluster           string
+	JobName           string
+	RecentRuns        int
+	RecentMedianSec   float64
+	BaselineRuns      int
+	BaselineMedianSec float64
+	Ratio             float64
+}
+
+// queueSaturationNotification is the JSON body POSTed to the queue saturation webhook
+type queueSaturationNotification struct {
+	ScopeId string                    `json:"scopeId"`
+	Alerts  []queueSaturationAlertDto `json:"alerts"`
+}
+
+type queueSaturationAlertDto struct {
+	Cluster           string    `json:"cluster"`
+	JobName           string    `json:"jobName"`
+	OpenedAt          time.Time `json:"openedAt"`
+	RecentRuns        int       `json:"recentRuns"`
+	RecentMedianSec   float64   `json:"recentMedianSec"`
+	BaselineMedianSec float64   `json:"baselineMedianSec"`
+	Ratio             float64   `json:"ratio"`
+}
+
+// DetectQueueSaturation flags sustained queue time increases, a sign of runner saturation.
+//
+// Only runs when the scope config sets queueSaturationFactor. The queue times of the scope's runs are
+// grouped by build cluster and job name, and a group is saturated when its median over the recent window
+// reaches queueSaturationMinSeconds and queueSaturationFactor times its median over the queueBaselineDays
+// before, each window holding at least minQueueSamples runs. A saturated group opens a row in
+// _tool_testregistry_queue_alerts, or refreshes its open one; open alerts of groups no longer saturated are
+// resolved. New alerts are POSTed to queueSaturationWebhookUrl when set, a failing webhook is retried on
+// the next run.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered while detecting saturation, or nil if successful
+func DetectQueueSaturation(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	connectionId := data.Options.ConnectionId
+	fullName := data.Options.FullName
+	rules := data.QueueSaturationRules
+	if rules == nil {
+		logger.Debug("queueSaturationFactor is not set, skipping queue saturation for scope %s", fullName)
+		return nil
+	}
+
+	// Whole seconds, the opening time is part of the key of the alerts
+	now := time.Now().Truncate(time.Second)
+	var samples []queueTimeSample
+	err := db.All(&samples,
+		dal.Select("cluster, job_name, queued_at, queued_duration_sec"),
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND queued_at >= ? AND queued_duration_sec IS NOT NULL",
+			connectionId, fullName, now.Add(-rules.Window).AddDate(0, 0, -queueBaselineDays)),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load queue times")
+	}
+
+	var open []models.QueueAlert
+	err = db.All(&open, dal.Where("connection_id = ? AND scope_id = ? AND resolved_at IS NULL", connectionId, fullName))
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to load open queue alerts")
+	}
+
+	saturations := detectSaturatedQueues(samples, now, rules)
+	alerts := reconcileQueueAlerts(open, saturations, connectionId, fullName, now)
+	var pending []*models.QueueAlert
+	for _, alert := range alerts {
+		if err := db.CreateOrUpdate(alert); err != nil {
+			return errors.Default.Wrap(err, "failed to save queue alert")
+		}
+		if alert.ResolvedAt == nil && alert.NotifiedAt == nil {
+			pending = append(pending, alert)
+		}
+	}
+	logger.Info("found %d saturated queues among the %d queued runs of scope %s", len(saturations), len(samples), fullName)
+
+	webhookUrl := ""
+	if data.Options.ScopeConfig != nil {
+		webhookUrl = data.Options.ScopeConfig.QueueSaturationWebhookUrl
+	}
+	if webhookUrl == "" || len(pending) == 0 {
+		return nil
+	}
+	if err := ValidateWebhookUrl(webhookUrl, taskCtx.GetConfig(webhookAllowedHostsEnv)); err != nil {
+		logger.Warn(err, "not notifying %d queue alerts of scope %s", len(pending), fullName)
+		return nil
+	}
+	notification := &queueSaturationNotification{ScopeId: fullName}
+	for _, alert := range pending {
+		notification.Alerts = append(notification.Alerts, queueSaturationAlertDto{
+			Cluster:           alert.Cluster,
+			JobName:           alert.JobName,
+			OpenedAt:          alert.OpenedAt,
+			RecentRuns:        alert.RecentRuns,
+			RecentMedianSec:   alert.RecentMedianSec,
+			BaselineMedianSec: alert.BaselineMedianSec,
+			Ratio:             alert.Ratio,
+		})
+	}
+	if err := postQueueSaturationWebhook(taskCtx.GetContext(), webhookUrl, notification); err != nil {
+		logger.Warn(err, "failed to notify %d queue alerts of scope %s, retrying on the next run", len(pending), fullName)
+		return nil
+	}
+	for _, alert := range pending {
+		alert.NotifiedAt = &now
+		if err := db.Update(alert); err != nil {
+			return errors.Default.Wrap(err, "failed to mark queue alert as notified")
+		}
+	}
+	return nil
+}
+
+// detectSaturatedQueues compares the median queue time of each cluster and job over the recent window
+// with its median over the baseline before it
+//
+// Parameters:
+//   - samples: Queue times of the runs queued since the start of the baseline
+//   - now: End of the recent window
+//   - rules: Queue saturation settings of the scope
+//
+// Returns:
+//   - []queueSaturation: The saturated groups, sorted by cluster and job name
+func detectSaturatedQueues(samples []queueTimeSample, now time.Time, rules *QueueSaturationRules) []queueSaturation {
+	type queueGroup struct {
+		recent, baseline []float64
+	}
+	windowStart := now.Add(-rules.Window)
+	baselineStart := windowStart.AddDate(0, 0, -queueBaselineDays)
+	groups := make(map[[2]string]*queueGroup)
+	for _, sample := range samples {
+		if sample.QueuedAt.Before(baselineStart) || sample.QueuedAt.After(now) {
+			continue
+		}
+		key := [2]string{sample.Cluster, sample.JobName}
+		group, ok := groups[key]
+		if !ok {
+			group = &queueGroup{}
+			groups[key] = group
+		}
+		if sample.QueuedAt.Before(windowStart) {
+			group.baseline = append(group.baseline, sample.QueuedDurationSec)
+		} else {
+			group.recent = append(group.recent, sample.QueuedDurationSec)
+		}
+	}
+
+	var saturations []queueSaturation
+	for key, group := range groups {
+		if len(group.recent) < minQueueSamples || len(group.baseline) < minQueueSamples {
+			continue
+		}
+		recent, baseline := median(group.recent), median(group.baseline)
+		if recent < rules.MinSeconds || recent < rules.Factor*baseline {
+			continue
+		}
+		saturation := queueSaturation{
+			Cluster:           key[0],
+			JobName:           key[1],
+			RecentRuns:        len(group.recent),
+			RecentMedianSec:   recent,
+			BaselineRuns:      len(group.baseline),
+			BaselineMedianSec: baseline,
+		}
+		if baseline > 0 {
+			saturation.Ratio = recent / baseline
+		}
+		saturations = append(saturations, saturation)
+	}
+	sort.Slice(saturations, func(i, j int) bool {
+		if saturations[i].Cluster != saturations[j].Cluster {
+			return saturations[i].Cluster < saturations[j].Cluster
+		}
+		return saturations[i].JobName < saturations[j].JobName
+	})
+	return saturations
+}
+
+// reconcileQueueAlerts refreshes the open alert of each saturated group or opens one, and resolves
+// the open alerts of the groups no longer saturated
+//
+// Returns:
+//   - []*models.QueueAlert: The alerts to save
+func reconcileQueueAlerts(open []models.QueueAlert, saturations []queueSaturation, connectionId uint64, scopeId string, now time.Time) []*models.QueueAlert {
+	openByGroup := make(map[[2]string]*models.QueueAlert, len(open))
+	for i := range open {
+		openByGroup[[2]string{open[i].Cluster, open[i].JobName}] = &open[i]
+	}
+
+	alerts := make([]*models.QueueAlert, 0, len(saturations)+len(open))
+	for _, saturation := range saturations {
+		key := [2]string{saturation.Cluster, saturation.JobName}
+		alert, ok := openByGroup[key]
+		if ok {
+			delete(openByGroup, key)
+		} else {
+			alert = &models.QueueAlert{
+				Id:           queueAlertId(connectionId, scopeId, saturation.Cluster, saturation.JobName, now),
+				ConnectionId: connectionId,
+				ScopeId:      scopeId,
+				Cluster:      saturation.Cluster,
+				JobName:      saturation.JobName,
+				OpenedAt:     now,
+			}
+		}
+		alert.LastSeenAt = now
+		alert.RecentRuns = saturation.RecentRuns
+		alert.RecentMedianSec = saturation.RecentMedianSec
+		alert.BaselineRuns = saturation.BaselineRuns
+		alert.BaselineMedianSec = saturation.BaselineMedianSec
+		alert.Ratio = saturation.Ratio
+		alerts = append(alerts, alert)
+	}
+	for i := range open {
+		if alert, ok := openByGroup[[2]string{open[i].Cluster, open[i].JobName}]; ok {
+			resolvedAt := now
+			alert.ResolvedAt = &resolvedAt
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+// queueAlertId is the hex sha256 of the natural key of an alert
+func queueAlertId(connectionId uint64, scopeId, cluster, jobName string, openedAt time.Time) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s\x00%s\x00%d", connectionId, scopeId, cluster, jobName, openedAt.Unix())))
+	return hex.EncodeToString(hash[:])
+}
+
+// ValidateWebhookUrl checks that a scope config webhook is an http(s) URL whose host is allowed
+//
+// Parameters:
+//   - webhookUrl: The URL from the scope config
+//   - allowedHosts: Comma separated allowed hosts, an entry starting with a dot also allows its subdomains
+//
+// Returns:
+//   - errors.Error: BadInput if the URL is invalid, not http(s) or its host is not allowed (none is when the list is empty)
+func ValidateWebhookUrl(webhookUrl, allowedHosts string) errors.Error {
+	parsed, err := url.Parse(webhookUrl)
+	if err != nil {
+		return errors.BadInput.Wrap(err, "invalid webhook URL")
+	}
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return errors.BadInput.New(fmt.Sprintf("webhook URL must use http or https, got %q", parsed.Scheme))
+	}
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return errors.BadInput.New("webhook URL has no host")
+	}
+	for _, allowed := range strings.Split(allowedHosts, ",") {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "" {
+			continue
+		}
+		if host == allowed || (strings.HasPrefix(allowed, ".") && (strings.HasSuffix(host, allowed) || host == allowed[1:])) {
+			return nil
+		}
+	}
+	return errors.BadInput.New(fmt.Sprintf("webhook host %s is not listed in %s", host, webhookAllowedHostsEnv))
+}
+
+// webhookClient does not follow redirects, so a webhook cannot be bounced to a host outside the allow-list
+var webhookClient = &http.Client{
+	Timeout: 30 * time.Second,
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// median returns the median of values, 0 for none
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// postQueueSaturationWebhook POSTs the notification as JSON, any non-2xx answer is an error
+func postQueueSaturationWebhook(ctx context.Context, url string, notification *queueSaturationNotification) errors.Error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to encode the queue saturation webhook payload")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.BadInput.Wrap(err, "invalid queue saturation webhook URL")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	res, err := webhookClient.Do(req)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to call the queue saturation webhook")
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("queue saturation webhook returned status %d", res.StatusCode))
+	}
+	return nil
+}
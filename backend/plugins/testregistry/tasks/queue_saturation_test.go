@@ -0,0 +1,185 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQueueSaturationRules(t *testing.T) {
+	rules, err := NewQueueSaturationRules(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, rules)
+
+	rules, err = NewQueueSaturationRules(&models.TestRegistryScopeConfig{QueueSaturationFactor: 2})
+	assert.Nil(t, err)
+	assert.Equal(t, &QueueSaturationRules{Factor: 2, Window: 24 * time.Hour, MinSeconds: 120}, rules)
+
+	rules, err = NewQueueSaturationRules(&models.TestRegistryScopeConfig{QueueSaturationFactor: 3, QueueSaturationWindowHours: 6, QueueSaturationMinSeconds: 30})
+	assert.Nil(t, err)
+	assert.Equal(t, &QueueSaturationRules{Factor: 3, Window: 6 * time.Hour, MinSeconds: 30}, rules)
+
+	_, err = NewQueueSaturationRules(&models.TestRegistryScopeConfig{QueueSaturationFactor: 0.5})
+	if assert.NotNil(t, err) {
+		assert.Equal(t, errors.BadInput, err.GetType())
+	}
+	_, err = NewQueueSaturationRules(&models.TestRegistryScopeConfig{QueueSaturationFactor: 2, QueueSaturationWindowHours: -1})
+	assert.NotNil(t, err)
+}
+
+// queueSamples returns n runs of a job queued for seconds each, one per hour back from queuedAt
+func queueSamples(cluster, jobName string, queuedAt time.Time, n int, seconds float64) []queueTimeSample {
+	samples := make([]queueTimeSample, 0, n)
+	for i := 0; i < n; i++ {
+		samples = append(samples, queueTimeSample{Cluster: cluster, JobName: jobName, QueuedAt: queuedAt.Add(-time.Duration(i) * time.Hour), QueuedDurationSec: seconds})
+	}
+	return samples
+}
+
+func TestDetectSaturatedQueues(t *testing.T) {
+	now := time.Date(2025, 2, 16, 12, 0, 0, 0, time.UTC)
+	recent, baseline := now.Add(-time.Hour), now.AddDate(0, 0, -3)
+	rules := &QueueSaturationRules{Factor: 2, Window: 24 * time.Hour, MinSeconds: 120}
+
+	var samples []queueTimeSample
+	// saturated: 10 minutes instead of 2
+	samples = append(samples, queueSamples("build05", "e2e", recent, 5, 600)...)
+	samples = append(samples, queueSamples("build05", "e2e", baseline, 6, 120)...)
+	// doubled but under the minimum queue time
+	samples = append(samples, queueSamples("build05", "unit", recent, 5, 60)...)
+	samples = append(samples, queueSamples("build05", "unit", baseline, 5, 10)...)
+	// too few recent runs to judge
+	samples = append(samples, queueSamples("build01", "e2e", recent, 4, 900)...)
+	samples = append(samples, queueSamples("build01", "e2e", baseline, 5, 60)...)
+	// stable
+	samples = append(samples, queueSamples("build01", "lint", recent, 5, 300)...)
+	samples = append(samples, queueSamples("build01", "lint", baseline, 5, 250)...)
+
+	saturations := detectSaturatedQueues(samples, now, rules)
+
+	assert.Equal(t, []queueSaturation{{
+		Cluster: "build05", JobName: "e2e",
+		RecentRuns: 5, RecentMedianSec: 600, BaselineRuns: 6, BaselineMedianSec: 120, Ratio: 5,
+	}}, saturations)
+}
+
+func TestReconcileQueueAlerts(t *testing.T) {
+	openedAt := time.Date(2025, 2, 15, 12, 0, 0, 0, time.UTC)
+	now := openedAt.Add(24 * time.Hour)
+	open := []models.QueueAlert{
+		{ConnectionId: 1, ScopeId: "s", Cluster: "build05", JobName: "e2e", OpenedAt: openedAt, NotifiedAt: &openedAt},
+		{ConnectionId: 1, ScopeId: "s", Cluster: "build01", JobName: "lint", OpenedAt: openedAt},
+	}
+	saturations := []queueSaturation{
+		{Cluster: "build05", JobName: "e2e", RecentRuns: 7, RecentMedianSec: 700, BaselineRuns: 9, BaselineMedianSec: 100, Ratio: 7},
+		{Cluster: "build05", JobName: "unit", RecentRuns: 5, RecentMedianSec: 300, BaselineRuns: 5, BaselineMedianSec: 100, Ratio: 3},
+	}
+
+	alerts := reconcileQueueAlerts(open, saturations, 1, "s", now)
+
+	if assert.Len(t, alerts, 3) {
+		assert.Equal(t, openedAt, alerts[0].OpenedAt, "the open alert is refreshed")
+		assert.Equal(t, now, alerts[0].LastSeenAt)
+		assert.Equal(t, 7.0, alerts[0].Ratio)
+		assert.NotNil(t, alerts[0].NotifiedAt)
+
+		assert.Equal(t, "unit", alerts[1].JobName)
+		assert.Equal(t, now, alerts[1].OpenedAt)
+		assert.Equal(t, queueAlertId(1, "s", "build05", "unit", now), alerts[1].Id)
+		assert.Len(t, alerts[1].Id, 64)
+		assert.Nil(t, alerts[1].NotifiedAt)
+
+		assert.Equal(t, "lint", alerts[2].JobName)
+		if assert.NotNil(t, alerts[2].ResolvedAt) {
+			assert.Equal(t, now, *alerts[2].ResolvedAt)
+		}
+	}
+}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, 0.0, median(nil))
+	assert.Equal(t, 2.0, median([]float64{3, 1, 2}))
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+}
+
+func TestDetectQueueSaturation(t *testing.T) {
+	var received queueSaturationNotification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	var samples []queueTimeSample
+	samples = append(samples, queueSamples("build05", "e2e", now.Add(-time.Hour), 5, 600)...)
+	samples = append(samples, queueSamples("build05", "e2e", now.AddDate(0, 0, -3), 5, 60)...)
+
+	scopeConfig := &models.TestRegistryScopeConfig{QueueSaturationFactor: 2, QueueSaturationWebhookUrl: server.URL}
+	rules, err := NewQueueSaturationRules(scopeConfig)
+	require.Nil(t, err)
+
+	mockCtx := new(mockplugin.SubTaskContext)
+	mockDal := new(mockdal.Dal)
+	mockCtx.On("GetLogger").Return(newMockLogger())
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	mockCtx.On("GetConfig", webhookAllowedHostsEnv).Return("127.0.0.1")
+	mockCtx.On("GetData").Return(&TestRegistryTaskData{
+		Options:              &TestRegistryOptions{ConnectionId: 1, FullName: "konflux-ci/e2e", ScopeConfig: scopeConfig},
+		QueueSaturationRules: rules,
+	})
+	mockDal.On("All", mock.AnythingOfType("*[]tasks.queueTimeSample"), mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]queueTimeSample) = samples
+	}).Return(nil)
+	mockDal.On("All", mock.AnythingOfType("*[]models.QueueAlert"), mock.Anything).Return(nil)
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Once()
+	mockDal.On("Update", mock.MatchedBy(func(alert *models.QueueAlert) bool { return alert.NotifiedAt != nil }), mock.Anything).Return(nil).Once()
+
+	assert.Nil(t, DetectQueueSaturation(mockCtx))
+	mockDal.AssertExpectations(t)
+	assert.Equal(t, "konflux-ci/e2e", received.ScopeId)
+	if assert.Len(t, received.Alerts, 1) {
+		assert.Equal(t, "build05", received.Alerts[0].Cluster)
+		assert.Equal(t, 10.0, received.Alerts[0].Ratio)
+	}
+}
+
+func TestValidateWebhookUrl(t *testing.T) {
+	assert.Nil(t, ValidateWebhookUrl("https://hooks.example.com/queue", "hooks.example.com"))
+	assert.Nil(t, ValidateWebhookUrl("http://alerts.team.example.com:8080/q", " other.org , .example.com"))
+	assert.NotNil(t, ValidateWebhookUrl("https://hooks.example.com/queue", ""), "nothing is allowed without a list")
+	assert.NotNil(t, ValidateWebhookUrl("https://169.254.169.254/latest", "hooks.example.com"))
+	assert.NotNil(t, ValidateWebhookUrl("https://evilexample.com/", ".example.com"))
+	assert.NotNil(t, ValidateWebhookUrl("file:///etc/passwd", "hooks.example.com"))
+	assert.NotNil(t, ValidateWebhookUrl("gopher://hooks.example.com/", "hooks.example.com"))
+}
@@ -0,0 +1,140 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// RepoRename is one entry of a connection's repoRenames, split into org and repo
+type RepoRename struct {
+	OldOrganization string `json:"oldOrganization"`
+	OldRepository   string `json:"oldRepository"` // Empty for an org rename
+	NewOrganization string `json:"newOrganization"`
+	NewRepository   string `json:"newRepository"` // Empty for an org rename
+}
+
+// RepoRenamer moves CI jobs of renamed GitHub orgs and repos to their new name
+type RepoRenamer struct {
+	// Repo renames are looked up before org renames
+	repos map[string]RepoRename
+	orgs  map[string]RepoRename
+}
+
+// NewRepoRenamer parses the repoRenames of a connection
+//
+// Parameters:
+//   - renames: "old-org/old-repo" to "new-org/new-repo", or "old-org" to "new-org"
+//
+// Returns:
+//   - *RepoRenamer: The renamer, or nil if no renames are configured
+//   - errors.Error: BadInput if an entry mixes an org and a repo name or has an empty part
+func NewRepoRenamer(renames map[string]string) (*RepoRenamer, errors.Error) {
+	if len(renames) == 0 {
+		return nil, nil
+	}
+	renamer := &RepoRenamer{repos: map[string]RepoRename{}, orgs: map[string]RepoRename{}}
+	for oldName, newName := range renames {
+		oldOrg, oldRepo, oldIsRepo := strings.Cut(strings.TrimSpace(oldName), "/")
+		newOrg, newRepo, newIsRepo := strings.Cut(strings.TrimSpace(newName), "/")
+		if oldIsRepo != newIsRepo {
+			return nil, errors.BadInput.New(fmt.Sprintf("repoRenames: %q and %q must both be org/repo or both be an org", oldName, newName))
+		}
+		if oldOrg == "" || newOrg == "" || (oldIsRepo && (oldRepo == "" || newRepo == "")) {
+			return nil, errors.BadInput.New(fmt.Sprintf("repoRenames: invalid rename %q to %q", oldName, newName))
+		}
+		rename := RepoRename{OldOrganization: oldOrg, OldRepository: oldRepo, NewOrganization: newOrg, NewRepository: newRepo}
+		if oldIsRepo {
+			renamer.repos[oldOrg+"/"+oldRepo] = rename
+		} else {
+			renamer.orgs[oldOrg] = rename
+		}
+	}
+	return renamer, nil
+}
+
+// Rename returns the current name of an org and repo
+//
+// Parameters:
+//   - organization: Organization a CI job was reported with
+//   - repository: Repository a CI job was reported with
+//
+// Returns:
+//   - string: The new organization, organization if it was not renamed
+//   - string: The new repository, repository if it was not renamed
+func (r *RepoRenamer) Rename(organization, repository string) (string, string) {
+	if r == nil {
+		return organization, repository
+	}
+	if rename, ok := r.repos[organization+"/"+repository]; ok {
+		return rename.NewOrganization, rename.NewRepository
+	}
+	if rename, ok := r.orgs[organization]; ok {
+		return rename.NewOrganization, repository
+	}
+	return organization, repository
+}
+
+// Renames lists the renames, repo renames first, each sorted by old name
+func (r *RepoRenamer) Renames() []RepoRename {
+	if r == nil {
+		return nil
+	}
+	renames := make([]RepoRename, 0, len(r.repos)+len(r.orgs))
+	for _, group := range []map[string]RepoRename{r.repos, r.orgs} {
+		start := len(renames)
+		for _, rename := range group {
+			renames = append(renames, rename)
+		}
+		sort.Slice(renames[start:], func(i, j int) bool {
+			a, b := renames[start+i], renames[start+j]
+			if a.OldOrganization != b.OldOrganization {
+				return a.OldOrganization < b.OldOrganization
+			}
+			return a.OldRepository < b.OldRepository
+		})
+	}
+	return renames
+}
+
+// renamedTo returns the old names renamed to an org and repo, with OldRepository set to the
+// repo's name for org renames
+func (r *RepoRenamer) renamedTo(organization, repository string) []RepoRename {
+	var renames []RepoRename
+	for _, rename := range r.Renames() {
+		if rename.OldRepository == "" {
+			if rename.NewOrganization == organization {
+				rename.OldRepository, rename.NewRepository = repository, repository
+				renames = append(renames, rename)
+			}
+		} else if rename.NewOrganization == organization && rename.NewRepository == repository {
+			renames = append(renames, rename)
+		}
+	}
+	return renames
+}
+
+// apply moves a CI job to the new name of its org and repo
+func (r *RepoRenamer) apply(ciJob *models.TestRegistryCIJob) {
+	ciJob.Organization, ciJob.Repository = r.Rename(ciJob.Organization, ciJob.Repository)
+}
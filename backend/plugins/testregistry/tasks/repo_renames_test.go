@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRepoRenamer(t *testing.T) {
+	renamer, err := NewRepoRenamer(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, renamer)
+
+	for _, renames := range []map[string]string{
+		{"redhat-appstudio/e2e-tests": "konflux-ci"},
+		{"redhat-appstudio": "konflux-ci/e2e-tests"},
+		{"redhat-appstudio/": "konflux-ci/e2e-tests"},
+		{"": "konflux-ci"},
+		{"redhat-appstudio/e2e-tests": "/e2e-tests"},
+	} {
+		_, err = NewRepoRenamer(renames)
+		assert.NotNil(t, err, "%v", renames)
+	}
+}
+
+func TestRepoRenamerRename(t *testing.T) {
+	renamer, err := NewRepoRenamer(map[string]string{
+		"redhat-appstudio":                   "konflux-ci",
+		"redhat-appstudio/infra-deployments": "redhat-appstudio-infra/infra-deployments",
+		"konflux-ci/build-service-old":       "konflux-ci/build-service",
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		org, repo       string
+		newOrg, newRepo string
+	}{
+		// org rename
+		{"redhat-appstudio", "e2e-tests", "konflux-ci", "e2e-tests"},
+		// a repo rename wins over the org rename
+		{"redhat-appstudio", "infra-deployments", "redhat-appstudio-infra", "infra-deployments"},
+		{"konflux-ci", "build-service-old", "konflux-ci", "build-service"},
+		{"konflux-ci", "release-service", "konflux-ci", "release-service"},
+	}
+	for _, tt := range tests {
+		org, repo := renamer.Rename(tt.org, tt.repo)
+		assert.Equal(t, tt.newOrg, org, "%s/%s", tt.org, tt.repo)
+		assert.Equal(t, tt.newRepo, repo, "%s/%s", tt.org, tt.repo)
+	}
+
+	var nilRenamer *RepoRenamer
+	org, repo := nilRenamer.Rename("redhat-appstudio", "e2e-tests")
+	assert.Equal(t, "redhat-appstudio", org)
+	assert.Equal(t, "e2e-tests", repo)
+
+	ciJob := &models.TestRegistryCIJob{Organization: "redhat-appstudio", Repository: "e2e-tests"}
+	renamer.apply(ciJob)
+	assert.Equal(t, "konflux-ci", ciJob.Organization)
+	assert.Equal(t, "e2e-tests", ciJob.Repository)
+}
+
+func TestRepoRenamerRenames(t *testing.T) {
+	renamer, err := NewRepoRenamer(map[string]string{
+		"redhat-appstudio":                   "konflux-ci",
+		"redhat-appstudio/infra-deployments": "redhat-appstudio-infra/infra-deployments",
+		"konflux-ci/build-service-old":       "konflux-ci/build-service",
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, []RepoRename{
+		{OldOrganization: "konflux-ci", OldRepository: "build-service-old", NewOrganization: "konflux-ci", NewRepository: "build-service"},
+		{OldOrganization: "redhat-appstudio", OldRepository: "infra-deployments", NewOrganization: "redhat-appstudio-infra", NewRepository: "infra-deployments"},
+		{OldOrganization: "redhat-appstudio", NewOrganization: "konflux-ci"},
+	}, renamer.Renames())
+
+	assert.Equal(t, []RepoRename{
+		{OldOrganization: "konflux-ci", OldRepository: "build-service-old", NewOrganization: "konflux-ci", NewRepository: "build-service"},
+		{OldOrganization: "redhat-appstudio", OldRepository: "build-service", NewOrganization: "konflux-ci", NewRepository: "build-service"},
+	}, renamer.renamedTo("konflux-ci", "build-service"))
+	assert.Empty(t, renamer.renamedTo("konflux-ci-other", "build-service"))
+}
@@ -20,13 +20,38 @@ package tasks
 import (
 	"regexp"
 
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/testregistry/models"
 )
 
 type TestRegistryOptions struct {
-	ConnectionId uint64 `json:"connectionId"`
-	FullName     string `json:"fullName"` // Repository name (scope fullName)
-	ScopeConfig  *models.TestRegistryScopeConfig
+	ConnectionId  uint64                          `json:"connectionId"`
+	FullName      string                          `json:"fullName"` // Repository name (scope fullName)
+	ScopeConfigId uint64                          `json:"scopeConfigId,omitempty" mapstructure:"scopeConfigId,omitempty"`
+	ScopeConfig   *models.TestRegistryScopeConfig `json:"scopeConfig,omitempty" mapstructure:"scopeConfig,omitempty"`
+
+	// JUnitRegex overrides the connection's JUnit file name pattern; the blueprint copies it from the scope config
+	JUnitRegex string `json:"junitRegex,omitempty" mapstructure:"junitRegex,omitempty"`
+	// CollectionMode is the collection mode of the connection when the blueprint plan was made (prow, quay or kubernetes)
+	CollectionMode string `json:"collectionMode,omitempty" mapstructure:"collectionMode,omitempty"`
+}
+
+// JUnitRegexPattern returns the JUnit file name pattern for the task: the task option,
+// then the scope config, then the connection. Empty means the default pattern.
+func (op *TestRegistryOptions) JUnitRegexPattern(connection *models.TestRegistryConnection) string {
+	if op.JUnitRegex != "" {
+		return op.JUnitRegex
+	}
+	if op.ScopeConfig != nil && op.ScopeConfig.JUnitRegex != "" {
+		return op.ScopeConfig.JUnitRegex
+	}
+	if connection != nil {
+		return connection.JUnitRegex
+	}
+	return ""
 }
 
 type TestRegistryTaskData struct {
@@ -36,4 +61,161 @@ type TestRegistryTaskData struct {
 	// JUnitRegex is the compiled regex pattern for matching JUnit XML files
 	// This is compiled once during task initialization and reused throughout collection
 	JUnitRegex *regexp.Regexp
+
+	// PassedCasePolicy thins out passing test cases for very large suites
+	// nil stores every test case
+	PassedCasePolicy *PassedCasePolicy
+
+	// ComponentMapper maps suite names to Konflux components
+	// nil leaves the component of every suite empty
+	ComponentMapper *ComponentMapper
+
+	// ArtifactAllowlist limits the files visited in pulled Tekton artifacts
+	// nil visits every file
+	ArtifactAllowlist *ArtifactAllowlist
+
+	// JobNameNormalizer splits job names into base job name and variant
+	// nil keeps the job name as base job name
+	JobNameNormalizer *JobNameNormalizer
+
+	// TestIdentityNormalizer builds the canonical identity of test cases
+	// nil identifies test cases by their suite and name as reported
+	TestIdentityNormalizer *TestIdentityNormalizer
+
+	// JobFilter selects the collected CI jobs by scenario and trigger type
+	// nil collects every job
+	JobFilter *JobFilter
+
+	// RegexEnricher sets the type and environment of the domain CI/CD rows
+	// from the scope config deploymentPattern and productionPattern
+	RegexEnricher *helper.RegexEnricher
+
+	// RepoRenamer moves jobs of renamed orgs and repos to their new name
+	// nil keeps the reported org and repo
+	RepoRenamer *RepoRenamer
+
+	// CommitShaResolver normalizes commit SHAs and expands abbreviated ones
+	// nil only normalizes them
+	CommitShaResolver *CommitShaResolver
+
+	// FailureLogSummarizer extracts the failure summary of the build logs of failed Prow jobs
+	// nil reads no build log
+	FailureLogSummarizer *FailureLogSummarizer
+
+	// QueueSaturationRules flag sustained queue time increases
+	// nil opens no queue alert
+	QueueSaturationRules *QueueSaturationRules
+
+	// Client overrides allow tests to inject fakes instead of talking to
+	// Quay.io, pulling OCI artifacts, opening the Openshift CI GCS bucket or
+	// calling the Kubernetes API. If nil, the collectors create the real clients.
+	TagListerOverride          TagLister
+	ArtifactPullerOverride     ArtifactPuller
+	ResultsFetcherOverride     ResultsFetcher
+	BuildLogFetcherOverride    BuildLogFetcher
+	JobHistoryStoreOverride    JobHistoryStore
+	PipelineRunWatcherOverride PipelineRunWatcher
+
+	// ProwBaseURLOverride points the Prow collector at another server,
+	// e.g. one replaying recorded prowjobs.js responses. Empty uses ProwBaseURL.
+	ProwBaseURLOverride string
+}
+
+// NewTestRegistryTaskData compiles the JUnit regex and the scope config rules of a scope once,
+// for the collection subtasks and the on-demand artifact ingestion
+func NewTestRegistryTaskData(op *TestRegistryOptions, connection *models.TestRegistryConnection, logger log.Logger) (*TestRegistryTaskData, errors.Error) {
+	// Initialize the JUnit regex from the task options, scope config or connection configuration
+	// Uses default regex if the pattern is empty or invalid
+	junitRegexPattern := op.JUnitRegexPattern(connection)
+	junitRegex := GetJUnitRegexOrDefault(junitRegexPattern, logger)
+	if junitRegexPattern != "" {
+		logger.Info("Using custom JUnit regex pattern: %s", junitRegexPattern)
+	} else {
+		logger.Debug("Using default JUnit regex pattern: %s", DefaultJUnitRegexPattern)
+	}
+
+	passedCasePolicy := NewPassedCasePolicy(op.ScopeConfig)
+	if passedCasePolicy != nil {
+		logger.Info("Passing test cases stored in %s mode (sample percent: %d)", passedCasePolicy.Mode, passedCasePolicy.SamplePercent)
+	}
+
+	componentMapper, err := NewComponentMapper(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactAllowlist, err := NewArtifactAllowlist(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	jobNameNormalizer, err := NewJobNameNormalizer(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	testIdentityNormalizer, err := NewTestIdentityNormalizer(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	jobFilter, err := NewJobFilter(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	regexEnricher, err := newRegexEnricher(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	failureLogSummarizer, err := NewFailureLogSummarizer(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	queueSaturationRules, err := NewQueueSaturationRules(op.ScopeConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	var repoRenamer *RepoRenamer
+	if connection != nil {
+		repoRenamer, err = NewRepoRenamer(connection.RepoRenames)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &TestRegistryTaskData{
+		Options:                op,
+		Connection:             connection,
+		JUnitRegex:             junitRegex,
+		PassedCasePolicy:       passedCasePolicy,
+		ComponentMapper:        componentMapper,
+		ArtifactAllowlist:      artifactAllowlist,
+		JobNameNormalizer:      jobNameNormalizer,
+		TestIdentityNormalizer: testIdentityNormalizer,
+		JobFilter:              jobFilter,
+		RegexEnricher:          regexEnricher,
+		RepoRenamer:            repoRenamer,
+		CommitShaResolver:      NewCommitShaResolver(connection),
+		FailureLogSummarizer:   failureLogSummarizer,
+		QueueSaturationRules:   queueSaturationRules,
+	}, nil
+}
+
+// newRegexEnricher compiles the deployment and production patterns of the scope config
+func newRegexEnricher(scopeConfig *models.TestRegistryScopeConfig) (*helper.RegexEnricher, errors.Error) {
+	regexEnricher := helper.NewRegexEnricher()
+	if scopeConfig == nil {
+		return regexEnricher, nil
+	}
+	if err := regexEnricher.TryAdd(devops.DEPLOYMENT, scopeConfig.DeploymentPattern); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid value for `deploymentPattern`")
+	}
+	if err := regexEnricher.TryAdd(devops.PRODUCTION, scopeConfig.ProductionPattern); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid value for `productionPattern`")
+	}
+	return regexEnricher, nil
 }
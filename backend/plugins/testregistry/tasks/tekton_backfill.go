@@ -0,0 +1,176 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// backfillCursorOverlap is re-listed before the cursor on every run, so tags pushed
+// with a slightly older timestamp than the last processed one aren't missed
+const backfillCursorOverlap = time.Hour
+
+// tagSlice is a group of Quay.io tags created within [Start, End)
+type tagSlice struct {
+	Start time.Time
+	End   time.Time
+	Tags  []QuayTag
+}
+
+// sliceTagsByTime groups tags into consecutive time slices, oldest first
+//
+// Parameters:
+//   - tags: Tags to group, in any order
+//   - start: Start of the first slice
+//   - until: End of the last slice; tags created later are added to the last slice
+//   - sliceDays: Length of a slice in days (DefaultBackfillSliceDays if not positive)
+//
+// Returns:
+//   - []tagSlice: The slices holding at least one tag, ordered oldest first, tags sorted by creation time
+func sliceTagsByTime(tags []QuayTag, start, until time.Time, sliceDays int) []tagSlice {
+	if sliceDays <= 0 {
+		sliceDays = models.DefaultBackfillSliceDays
+	}
+	sorted := make([]QuayTag, len(tags))
+	copy(sorted, tags)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].StartTS < sorted[j].StartTS })
+
+	sliceLength := time.Duration(sliceDays) * 24 * time.Hour
+	var slices []tagSlice
+	for _, tag := range sorted {
+		created := time.Unix(tag.StartTS, 0)
+		if !created.Before(until) {
+			// tags pushed while listing belong to the last slice
+			created = until.Add(-time.Nanosecond)
+		}
+		index := 0
+		if created.After(start) {
+			index = int(created.Sub(start) / sliceLength)
+		}
+		sliceStart := start.Add(time.Duration(index) * sliceLength)
+		sliceEnd := sliceStart.Add(sliceLength)
+		if sliceEnd.After(until) {
+			sliceEnd = until
+		}
+		if n := len(slices); n > 0 && !slices[n-1].Start.Before(sliceStart) {
+			slices[n-1].Tags = append(slices[n-1].Tags, tag)
+			continue
+		}
+		slices = append(slices, tagSlice{Start: sliceStart, End: sliceEnd, Tags: []QuayTag{tag}})
+	}
+	return slices
+}
+
+// backfillStart returns where the Tekton collection of a scope starts
+//
+// Parameters:
+//   - since: Start of the collection window (sync policy timeAfter or 6 months ago)
+//   - cursor: The persisted cursor of the scope, nil if none
+//   - fullSync: Whether a full sync was requested, which ignores the cursor
+//
+// Returns:
+//   - time.Time: The cursor minus backfillCursorOverlap when it lies within the window, since otherwise
+func backfillStart(since time.Time, cursor *time.Time, fullSync bool) time.Time {
+	if fullSync || cursor == nil {
+		return since
+	}
+	resume := cursor.Add(-backfillCursorOverlap)
+	if resume.After(since) {
+		return resume
+	}
+	return since
+}
+
+// loadBackfillCursor returns the persisted Tekton cursor of a scope, nil if there is none or it was
+// reached from a collection window starting after since, e.g. when timeAfter was moved back
+func loadBackfillCursor(db dal.Dal, connectionId uint64, scopeId string, since time.Time) *time.Time {
+	cursor := &models.TektonBackfillCursor{}
+	err := db.First(cursor, dal.Where("connection_id = ? AND scope_id = ?", connectionId, scopeId))
+	if err != nil {
+		return nil
+	}
+	if cursor.Since != nil && since.Before(*cursor.Since) {
+		return nil
+	}
+	return &cursor.Cursor
+}
+
+// saveBackfillCursor persists that the tags of a scope created before cursor have been processed,
+// collecting from since
+func saveBackfillCursor(db dal.Dal, connectionId uint64, scopeId string, cursor, since time.Time) errors.Error {
+	return db.CreateOrUpdate(&models.TektonBackfillCursor{
+		ConnectionId: connectionId,
+		ScopeId:      scopeId,
+		Cursor:       cursor,
+		Since:        &since,
+	})
+}
+
+// failedTagsCursor returns where the cursor stops for a slice with failed tags: the creation time of
+// the oldest failed tag, so the next run lists it again, or the slice start for undated tags
+func failedTagsCursor(slice tagSlice, failedTags []QuayTag) time.Time {
+	cursor := time.Time{}
+	for _, tag := range failedTags {
+		created := time.Unix(tag.StartTS, 0)
+		if tag.StartTS == 0 || created.Before(slice.Start) {
+			return slice.Start
+		}
+		if cursor.IsZero() || created.Before(cursor) {
+			cursor = created
+		}
+	}
+	return cursor
+}
+
+// backfillSliceDays returns the configured slice length in days, DefaultBackfillSliceDays if unset
+func backfillSliceDays(scopeConfig *models.TestRegistryScopeConfig) int {
+	if scopeConfig == nil || scopeConfig.BackfillSliceDays <= 0 {
+		return models.DefaultBackfillSliceDays
+	}
+	return scopeConfig.BackfillSliceDays
+}
+
+// backfillMaxSlices returns how many slices a single run may process, 0 for no limit
+func backfillMaxSlices(scopeConfig *models.TestRegistryScopeConfig) int {
+	if scopeConfig == nil || scopeConfig.BackfillMaxSlices < 0 {
+		return 0
+	}
+	return scopeConfig.BackfillMaxSlices
+}
+
+// add sums the counters of a processed slice into the run's statistics
+func (stats *collectionStats) add(other collectionStats) {
+	stats.matchingCount += other.matchingCount
+	stats.savedCount += other.savedCount
+	stats.rawSavedCount += other.rawSavedCount
+	stats.processedCount += other.processedCount
+	stats.junitFoundCount += other.junitFoundCount
+	stats.junitNotFoundCount += other.junitNotFoundCount
+	stats.expiredCount += other.expiredCount
+	stats.failedTags = append(stats.failedTags, other.failedTags...)
+	for jobName, jobStats := range other.junitByJobName {
+		merged := stats.junitJobStats(jobName)
+		merged.found += jobStats.found
+		merged.notFound += jobStats.notFound
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSliceTagsByTime(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := start.AddDate(0, 0, 20)
+	day := func(d int) int64 { return start.AddDate(0, 0, d).Unix() }
+
+	t.Run("tags are grouped by week, oldest first, empty weeks skipped", func(t *testing.T) {
+		tags := []QuayTag{
+			{Name: "run-c", StartTS: day(15)},
+			{Name: "run-a", StartTS: day(1)},
+			{Name: "run-b", StartTS: day(3)},
+		}
+
+		slices := sliceTagsByTime(tags, start, until, 7)
+
+		assert.Len(t, slices, 2)
+		assert.Equal(t, start, slices[0].Start)
+		assert.Equal(t, start.AddDate(0, 0, 7), slices[0].End)
+		assert.Equal(t, []string{"run-a", "run-b"}, tagNames(slices[0].Tags))
+		assert.Equal(t, start.AddDate(0, 0, 14), slices[1].Start)
+		assert.Equal(t, until, slices[1].End)
+		assert.Equal(t, []string{"run-c"}, tagNames(slices[1].Tags))
+	})
+
+	t.Run("tags pushed after until belong to the last slice", func(t *testing.T) {
+		tags := []QuayTag{{Name: "run-a", StartTS: day(16)}, {Name: "run-late", StartTS: day(40)}}
+
+		slices := sliceTagsByTime(tags, start, until, 7)
+
+		assert.Len(t, slices, 1)
+		assert.Equal(t, until, slices[0].End)
+		assert.Equal(t, []string{"run-a", "run-late"}, tagNames(slices[0].Tags))
+	})
+
+	t.Run("tags without a date go to the first slice", func(t *testing.T) {
+		slices := sliceTagsByTime([]QuayTag{{Name: "undated"}}, start, until, 0)
+
+		assert.Len(t, slices, 1)
+		assert.Equal(t, start, slices[0].Start)
+		assert.Equal(t, start.AddDate(0, 0, models.DefaultBackfillSliceDays), slices[0].End)
+	})
+
+	t.Run("no tags", func(t *testing.T) {
+		assert.Empty(t, sliceTagsByTime(nil, start, until, 7))
+	})
+}
+
+func TestBackfillStart(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	cursor := since.AddDate(0, 1, 0)
+	stale := since.AddDate(0, -1, 0)
+
+	assert.Equal(t, since, backfillStart(since, nil, false))
+	assert.Equal(t, cursor.Add(-backfillCursorOverlap), backfillStart(since, &cursor, false))
+	assert.Equal(t, since, backfillStart(since, &stale, false))
+	assert.Equal(t, since, backfillStart(since, &cursor, true))
+}
+
+func TestLoadBackfillCursor(t *testing.T) {
+	cursor := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("stored cursor", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*models.TektonBackfillCursor).Cursor = cursor
+		}).Return(nil)
+
+		assert.Equal(t, &cursor, loadBackfillCursor(mockDal, 1, "quay-org/repo", cursor.AddDate(0, -1, 0)))
+	})
+
+	t.Run("cursor reached from a later window", func(t *testing.T) {
+		since := cursor.AddDate(0, -1, 0)
+		mockDal := new(mockdal.Dal)
+		mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			args.Get(0).(*models.TektonBackfillCursor).Cursor = cursor
+			args.Get(0).(*models.TektonBackfillCursor).Since = &since
+		}).Return(nil)
+
+		assert.Equal(t, &cursor, loadBackfillCursor(mockDal, 1, "quay-org/repo", since.Add(time.Hour)))
+		// timeAfter moved back before the window the cursor was reached from
+		assert.Nil(t, loadBackfillCursor(mockDal, 1, "quay-org/repo", since.AddDate(0, -3, 0)))
+	})
+
+	t.Run("no cursor yet", func(t *testing.T) {
+		mockDal := new(mockdal.Dal)
+		mockDal.On("First", mock.Anything, mock.Anything).Return(errors.NotFound.New("record not found"))
+
+		assert.Nil(t, loadBackfillCursor(mockDal, 1, "quay-org/repo", cursor))
+	})
+}
+
+func TestFailedTagsCursor(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	slice := tagSlice{Start: start, End: start.AddDate(0, 0, 7)}
+	day := func(d int) int64 { return start.AddDate(0, 0, d).Unix() }
+
+	assert.True(t, start.AddDate(0, 0, 2).Equal(failedTagsCursor(slice, []QuayTag{{Name: "run-b", StartTS: day(4)}, {Name: "run-a", StartTS: day(2)}})))
+	assert.Equal(t, start, failedTagsCursor(slice, []QuayTag{{Name: "run-b", StartTS: day(4)}, {Name: "undated"}}))
+}
+
+func TestBackfillScopeConfig(t *testing.T) {
+	assert.Equal(t, models.DefaultBackfillSliceDays, backfillSliceDays(nil))
+	assert.Equal(t, 0, backfillMaxSlices(nil))
+
+	scopeConfig := &models.TestRegistryScopeConfig{BackfillSliceDays: 1, BackfillMaxSlices: 4}
+	assert.Equal(t, 1, backfillSliceDays(scopeConfig))
+	assert.Equal(t, 4, backfillMaxSlices(scopeConfig))
+}
+
+func tagNames(tags []QuayTag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
@@ -71,122 +71,204 @@ func CollectTektonJobs(taskCtx plugin.SubTaskContext) errors.Error {
 	data := taskCtx.GetData().(*TestRegistryTaskData)
 	logger := taskCtx.GetLogger()
 	logger.Info("Collecting Tekton CI jobs", "scope", data.Options.FullName)
+	startedAt := time.Now()
 
 	// Validate connection type
 	if data.Connection.CITool != models.CIToolTektonCI {
 		logger.Debug("Connection is not Tekton CI, skipping")
 		return nil
 	}
-
-	// Extract scope information
-	// For Tekton CI, FullName format is "quayOrg/repoName" or "quayOrg/sub-org/repoName"
-	// Example: FullName = "konflux-test-storage/konflux-team/release-service"
-	//          quayOrg = "konflux-test-storage"
-	//          Result: repoName = "konflux-team/release-service"
-	quayOrg := strings.TrimSpace(data.Connection.QuayOrganization)
-	if quayOrg == "" {
-		return errors.BadInput.New("Quay organization is required for Tekton CI")
-	}
-
-	fullName := strings.TrimSpace(data.Options.FullName)
-	if fullName == "" {
-		return errors.BadInput.New("FullName is required")
+	if data.Connection.UsesKubernetes() {
+		logger.Debug("Connection reads PipelineRuns from Kubernetes, skipping Quay.io artifacts")
+		return nil
 	}
 
-	// Remove Quay organization prefix from FullName to get repository name
-	// Example: FullName = "konflux-test-storage/konflux-team/release-service"
-	//          quayOrg = "konflux-test-storage"
-	//          Result: repoName = "konflux-team/release-service"
-	orgPrefix := quayOrg + "/"
-	repoName := strings.TrimPrefix(fullName, orgPrefix)
-
-	if repoName == "" {
-		return errors.BadInput.New("Repository name could not be extracted from FullName")
+	quayOrg, repoName, err := tektonQuayRepository(data)
+	if err != nil {
+		return err
 	}
+	fullName := strings.TrimSpace(data.Options.FullName)
 
 	// Build full repository path for Quay.io: org/repo
 	repoFullPath := fmt.Sprintf("%s/%s", quayOrg, repoName)
 
-	// Get logging directory from environment variable
-	loggingDir := os.Getenv("LOGGING_DIR")
-	if loggingDir == "" {
-		loggingDir = "/app/logs"
-	}
-
 	// Setup raw data collection
 	rawDataSubTask, err := setupRawTektonDataCollection(taskCtx, data)
 	if err != nil {
 		return err
 	}
 
-	// Get sync policy to determine date range for artifact collection
-	syncPolicy := taskCtx.TaskContext().SyncPolicy()
-	var since, until *time.Time
-	if syncPolicy != nil {
-		since = syncPolicy.TimeAfter
-		if since == nil {
-			// Default to last 6 months if no timeAfter is set
-			sixMonthsAgo := time.Now().AddDate(0, -6, 0)
-			since = &sixMonthsAgo
-		}
-		until = nil // Collect until now
-	} else {
-		// Default to last 6 months if no sync policy
-		sixMonthsAgo := time.Now().AddDate(0, -6, 0)
-		since = &sixMonthsAgo
+	// Get sync policy to determine date range for artifact collection (until now).
+	// A scope that was collected before resumes from its backfill cursor instead.
+	db := taskCtx.GetDal()
+	syncPolicy := scopeSyncPolicy(taskCtx.TaskContext().SyncPolicy(), data.Options.ScopeConfig, time.Now())
+	fullSync := syncPolicy != nil && syncPolicy.FullSync
+	since := *tektonCollectionSince(syncPolicy)
+	cursor := loadBackfillCursor(db, data.Options.ConnectionId, fullName, since)
+	start := backfillStart(since, cursor, fullSync)
+	until := time.Now()
+
+	// Tags that expired before the collection window can't be listed again
+	err = db.Delete(&models.ExpiredTag{}, dal.Where("connection_id = ? AND scope_id = ? AND expired_at < ?", data.Options.ConnectionId, fullName, since))
+	if err != nil {
+		logger.Warn(err, "failed to prune expired tags", "repository", repoFullPath)
 	}
 
 	// Setup Quay.io API client for listing tags with date filtering
 	ctx := taskCtx.GetContext()
-	quayClient, err := NewQuayClient(ctx, logger)
-	if err != nil {
-		return errors.Default.Wrap(err, "failed to create Quay.io client")
+	tagLister := data.TagListerOverride
+	if tagLister == nil {
+		quayClient, err := NewQuayClient(ctx, data.Connection, logger)
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to create Quay.io client")
+		}
+		tagLister = quayClient
 	}
 
-	// List all tags within sync policy dates
-	quayTags, err := quayClient.ListTags(ctx, quayOrg, repoName, since, until)
+	// List all tags created since the start of the collection
+	var slices []tagSlice
+	quayTags, err := tagLister.ListTags(ctx, quayOrg, repoName, &start, nil)
 	if err != nil {
+		// Without tag dates there is nothing to slice or to move the cursor to
 		logger.Warn(err, "failed to list tags from Quay.io API, will try to pull 'latest'")
 		quayTags = []QuayTag{{Name: "latest"}}
+		slices = []tagSlice{{Tags: quayTags}}
+	} else {
+		slices = sliceTagsByTime(quayTags, start, until, backfillSliceDays(data.Options.ScopeConfig))
 	}
 
 	if len(quayTags) == 0 {
 		logger.Info("No tags found for repository in the specified date range", "repository", repoFullPath)
+		if err := saveBackfillCursor(db, data.Options.ConnectionId, fullName, until, since); err != nil {
+			logger.Warn(err, "failed to save Tekton backfill cursor", "repository", repoFullPath)
+		}
+		recordCollectionRun(db, logger, data, startedAt, 0, 0, collectionStats{})
 		return nil
 	}
 
-	logger.Info("Found tags matching date range", "count", len(quayTags), "repository", repoFullPath)
+	logger.Info("Found tags matching date range", "count", len(quayTags), "slices", len(slices), "since", start, "repository", repoFullPath)
 
-	// Setup ORAS client for pulling artifacts
-	orasClient, err := NewORASClient(ctx, QuayRegistryURL, repoFullPath, loggingDir, logger)
+	// Pull artifacts into a directory owned by this run only, so parallel pipelines
+	// collecting other scopes of the same connection never remove each other's files
+	workDir, err := newRunWorkDir(LoggingDir(), data.Options.ConnectionId, fullName)
 	if err != nil {
-		return errors.Default.Wrap(err, "failed to create ORAS client")
+		return err
 	}
+	// Ensure the run's own working directory is cleaned up even if processing fails
+	defer func() {
+		if cleanupErr := os.RemoveAll(workDir); cleanupErr != nil {
+			logger.Warn(cleanupErr, "failed to cleanup working directory", "path", workDir)
+		}
+	}()
 
-	// Get database connection and raw data parameters
-	db := taskCtx.GetDal()
+	// Setup ORAS client for pulling artifacts
+	orasClient := data.ArtifactPullerOverride
+	if orasClient == nil {
+		client, err := NewORASClient(ctx, QuayRegistryURL, repoFullPath, workDir, data.Connection, logger)
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to create ORAS client")
+		}
+		orasClient = client
+	}
+
+	// Get raw data parameters
 	rawTable := rawDataSubTask.GetTable()
 	rawParams := rawDataSubTask.GetParams()
 	apiURL := fmt.Sprintf("oras://%s/%s", QuayRegistryURL, repoFullPath)
 
-	// Process artifacts
-	stats := processTektonArtifacts(taskCtx, orasClient, quayTags, data, rawDataSubTask, db, rawTable, rawParams, apiURL, loggingDir, repoFullPath, quayOrg, repoName)
+	// Process artifacts slice by slice, oldest first, moving the cursor after each
+	// slice so an interrupted or capped run resumes where it stopped. The cursor stops
+	// at the oldest tag that failed to process, so the next run retries it.
+	maxSlices := backfillMaxSlices(data.Options.ScopeConfig)
+	stats := collectionStats{}
+	artifactCount := 0
+	cursorHeld := false
+	for i, slice := range slices {
+		if maxSlices > 0 && i >= maxSlices {
+			logger.Info("Reached backfill slice limit, remaining tags are collected by the next run", "repository", repoFullPath, "remaining_slices", len(slices)-i, "cursor", slice.Start)
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return errors.Default.Wrap(err, "Tekton collection interrupted")
+		}
+
+		logger.Info("Processing Tekton backfill slice", "repository", repoFullPath, "slice", i+1, "of", len(slices), "from", slice.Start, "to", slice.End, "tags", len(slice.Tags))
+		sliceStats := processTektonArtifacts(taskCtx, orasClient, slice.Tags, data, rawDataSubTask, db, rawTable, rawParams, apiURL, workDir, repoFullPath, quayOrg, repoName)
+		stats.add(sliceStats)
+		artifactCount += len(slice.Tags)
+
+		if slice.End.IsZero() || cursorHeld {
+			continue
+		}
+		cursorAt := slice.End
+		if i == len(slices)-1 {
+			cursorAt = until
+		}
+		if len(sliceStats.failedTags) > 0 {
+			cursorHeld = true
+			cursorAt = failedTagsCursor(slice, sliceStats.failedTags)
+			logger.Warn(nil, "Tekton artifacts failed to process, the next run retries them", "repository", repoFullPath, "failed_tags", len(sliceStats.failedTags), "cursor", cursorAt)
+		}
+		if err := saveBackfillCursor(db, data.Options.ConnectionId, fullName, cursorAt, since); err != nil {
+			logger.Warn(err, "failed to save Tekton backfill cursor", "repository", repoFullPath)
+		}
+	}
 
 	// Log final statistics
-	logger.Info("Completed Tekton job collection", "repository", repoFullPath, "artifacts_processed", len(quayTags), "jobs_saved", stats.savedCount, "raw_records_saved", stats.rawSavedCount, "junit_found", stats.junitFoundCount, "junit_not_found", stats.junitNotFoundCount)
+	logger.Info("Completed Tekton job collection", "repository", repoFullPath, "artifacts_processed", artifactCount, "jobs_saved", stats.savedCount, "raw_records_saved", stats.rawSavedCount, "junit_found", stats.junitFoundCount, "junit_not_found", stats.junitNotFoundCount, "expired_tags", stats.expiredCount, "failed_tags", len(stats.failedTags), "filtered", stats.filteredCount)
+	recordCollectionRun(db, logger, data, startedAt, len(quayTags), artifactCount, stats)
 
 	return nil
 }
 
+// tektonQuayRepository splits the scope FullName into the Quay.io organization of the
+// connection and the repository name within it
+//
+// Parameters:
+//   - data: The task data
+//
+// Returns:
+//   - string: The Quay.io organization
+//   - string: The repository name, without the organization
+//   - errors.Error: errors.BadInput if the organization or repository is missing
+func tektonQuayRepository(data *TestRegistryTaskData) (string, string, errors.Error) {
+	// For Tekton CI, FullName format is "quayOrg/repoName" or "quayOrg/sub-org/repoName"
+	// Example: FullName = "konflux-test-storage/konflux-team/release-service"
+	//          quayOrg = "konflux-test-storage"
+	//          Result: repoName = "konflux-team/release-service"
+	quayOrg := strings.TrimSpace(data.Connection.QuayOrganization)
+	if quayOrg == "" {
+		return "", "", errors.BadInput.New("Quay organization is required for Tekton CI")
+	}
+
+	fullName := strings.TrimSpace(data.Options.FullName)
+	if fullName == "" {
+		return "", "", errors.BadInput.New("FullName is required")
+	}
+
+	// Remove Quay organization prefix from FullName to get repository name
+	// Example: FullName = "konflux-test-storage/konflux-team/release-service"
+	//          quayOrg = "konflux-test-storage"
+	//          Result: repoName = "konflux-team/release-service"
+	orgPrefix := quayOrg + "/"
+	repoName := strings.TrimPrefix(fullName, orgPrefix)
+
+	if repoName == "" {
+		return "", "", errors.BadInput.New("Repository name could not be extracted from FullName")
+	}
+
+	return quayOrg, repoName, nil
+}
+
 // processTektonArtifacts processes Tekton OCI artifacts and extracts PipelineRun data
 //
 // Parameters:
 //   - taskCtx: The subtask context
-//   - orasClient: ORAS client for pulling artifacts
+//   - orasClient: Puller for OCI artifacts (ORASClient in production)
 //   - artifacts: List of QuayTag objects to process (includes tag name and date)
 //   - data: The task data
 //   - rawDataSubTask: Raw data subtask for saving raw JSON
-//   - loggingDir: Directory to store artifacts
+//   - workDir: Working directory of this run, removed by the caller once all artifacts are processed
 //   - repoFullPath: Full repository path (org/repo) - used for ORAS pull and logging
 //   - quayOrg: Quay.io organization name (for CI job organization field)
 //   - repoName: Repository name (for CI job repository field)
@@ -195,7 +277,7 @@ func CollectTektonJobs(taskCtx plugin.SubTaskContext) errors.Error {
 //   - collectionStats: Statistics about the processed artifacts
 func processTektonArtifacts(
 	taskCtx plugin.SubTaskContext,
-	orasClient *ORASClient,
+	orasClient ArtifactPuller,
 	artifacts []QuayTag,
 	data *TestRegistryTaskData,
 	rawDataSubTask *helper.RawDataSubTask,
@@ -203,25 +285,16 @@ func processTektonArtifacts(
 	rawTable string,
 	rawParams string,
 	apiURL string,
-	loggingDir string,
+	workDir string,
 	repoFullPath string,
 	quayOrg string,
 	repoName string,
 ) collectionStats {
 	logger := taskCtx.GetLogger()
-	ctx := taskCtx.GetContext()
 
 	stats := collectionStats{}
 	processedCount := 0
 
-	// Ensure tmp directory cleanup happens even if processing fails
-	tmpDir := filepath.Join(loggingDir, "tmp")
-	defer func() {
-		if cleanupErr := os.RemoveAll(tmpDir); cleanupErr != nil {
-			logger.Warn(cleanupErr, "failed to cleanup tmp directory")
-		}
-	}()
-
 	taskCtx.SetProgress(0, len(artifacts))
 
 	for _, tag := range artifacts {
@@ -239,106 +312,231 @@ func processTektonArtifacts(
 			continue
 		}
 
-		logger.Info("Processing artifact [%d/%d]: quay.io/%s:%s", processedCount, len(artifacts), repoFullPath, artifactRef)
-
-		// Pull artifact using ORAS
-		artifactPath, err := orasClient.PullArtifact(ctx, artifactRef)
-		if err != nil {
-			logger.Warn(err, "failed to pull artifact", "ref", artifactRef)
+		// Tags that expired before a previous run could pull them won't come back
+		if isTagExpired(db, data.Options.ConnectionId, data.Options.FullName, artifactRef) {
+			logger.Debug("Tag expired in a previous run, skipping artifact pull", "tag", artifactRef)
+			stats.expiredCount++
 			continue
 		}
 
-		// Extract and parse PipelineRun data from artifact
-		pipelineRuns, err := extractTektonPipelineRuns(ctx, orasClient, artifactPath, loggingDir, logger)
+		logger.Info("Processing artifact [%d/%d]: quay.io/%s:%s", processedCount, len(artifacts), repoFullPath, artifactRef)
+
+		result, err := processTektonArtifact(taskCtx, orasClient, artifactRef, data, db, rawTable, rawParams, apiURL, workDir, quayOrg, repoName, &stats)
 		if err != nil {
-			logger.Warn(err, "failed to extract PipelineRuns from artifact", "ref", artifactRef)
-			// Cleanup and skip this artifact
-			if artifactPath != "" {
-				os.RemoveAll(artifactPath)
+			if err.GetType() == errors.NotFound {
+				// The tag expired between ListTags and PullArtifact
+				logger.Info("Tag expired before its artifact could be pulled, skipping it from now on", "tag", artifactRef)
+				stats.expiredCount++
+				if saveErr := markTagExpired(db, data.Options.ConnectionId, data.Options.FullName, artifactRef); saveErr != nil {
+					logger.Warn(saveErr, "failed to record expired tag", "tag", artifactRef)
+				}
+				continue
 			}
+			logger.Warn(err, "failed to process artifact", "ref", artifactRef)
+			stats.failedTags = append(stats.failedTags, tag)
 			continue
 		}
 
-		// If no valid pipeline runs found or structure doesn't match, cleanup and skip
-		if len(pipelineRuns) == 0 {
+		// The artifact held no valid PipelineRuns or its structure doesn't match
+		if result.pipelineRuns == 0 {
 			logger.Warn(nil, "no valid PipelineRuns found in artifact", "ref", artifactRef)
-			if artifactPath != "" {
-				os.RemoveAll(artifactPath)
-			}
-			continue
 		}
+	}
 
-		logger.Debug("Found %d PipelineRuns in artifact", len(pipelineRuns), "ref", artifactRef)
+	return stats
+}
 
-		// Process each PipelineRun (keep artifactPath until all jobs are processed for JUnit extraction)
-		for _, pipelineRun := range pipelineRuns {
-			if pipelineRun == nil {
-				continue
-			}
+// tektonArtifactJob is a CI job saved from a pulled Tekton artifact
+type tektonArtifactJob struct {
+	ciJob      *models.TestRegistryCIJob
+	junitFound bool
+}
 
-			// Extract job ID early to check if already processed
-			jobId := pipelineRun.PipelineRunName
-			if jobId == "" {
-				logger.Warn(nil, "PipelineRun missing PipelineRunName, skipping")
-				continue
-			}
+// tektonArtifactResult is the outcome of processing one pulled Tekton artifact
+type tektonArtifactResult struct {
+	// pipelineRuns is the number of valid PipelineRuns found in the artifact
+	pipelineRuns int
+	// savedJobs are the CI jobs saved from the PipelineRuns
+	savedJobs []tektonArtifactJob
+	// collectedJobIds are the PipelineRuns skipped because their job was collected before
+	collectedJobIds []string
+}
 
-			// Check if job already processed
-			if isTektonJobAlreadyProcessed(db, data.Options.ConnectionId, jobId) {
-				logger.Debug("Tekton job already processed, skipping", "job_id", jobId)
-				continue
-			}
+// processTektonArtifact pulls one Tekton OCI artifact, saves its PipelineRuns as CI jobs
+// and processes their JUnit XML files. The pulled artifact is removed afterwards.
+//
+// Parameters:
+//   - taskCtx: The subtask context
+//   - orasClient: Puller for OCI artifacts (ORASClient in production)
+//   - artifactRef: Tag or digest of the artifact
+//   - data: The task data
+//   - db: Database connection
+//   - rawTable: Name of the raw data table
+//   - rawParams: Parameters identifying this collection run
+//   - apiURL: The URL the artifact is pulled from
+//   - workDir: Working directory of this run
+//   - quayOrg: Quay.io organization name (for CI job organization field)
+//   - repoName: Repository name (for CI job repository field)
+//   - stats: Collection statistics, updated with the saved rows
+//
+// Returns:
+//   - *tektonArtifactResult: The PipelineRuns found and the CI jobs saved
+//   - errors.Error: errors.NotFound if the artifact no longer exists, or any pull or extraction error
+func processTektonArtifact(
+	taskCtx plugin.SubTaskContext,
+	orasClient ArtifactPuller,
+	artifactRef string,
+	data *TestRegistryTaskData,
+	db dal.Dal,
+	rawTable string,
+	rawParams string,
+	apiURL string,
+	workDir string,
+	quayOrg string,
+	repoName string,
+	stats *collectionStats,
+) (*tektonArtifactResult, errors.Error) {
+	logger := taskCtx.GetLogger()
+	ctx := taskCtx.GetContext()
 
-			// Save raw PipelineRun JSON
-			if err := saveRawTektonData(db, logger, pipelineRun, rawParams, rawTable, apiURL); err != nil {
-				logger.Warn(err, "failed to save raw Tekton PipelineRun data")
-			} else {
-				stats.rawSavedCount++
-			}
+	// Pull artifact using ORAS
+	artifactPath, err := orasClient.PullArtifact(ctx, artifactRef)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the artifact until all PipelineRuns are processed for JUnit extraction
+	defer os.RemoveAll(artifactPath)
 
-			// Convert to normalized CI job
-			ciJob, err := convertTektonPipelineRunToCIJob(pipelineRun, data.Options.ConnectionId, data.Options.FullName, quayOrg, repoName)
-			if err != nil {
-				logger.Warn(err, "failed to convert Tekton PipelineRun to CI job")
-				continue
-			}
+	// Artifact content is untrusted: drop escaping symlinks and special files and
+	// normalize file names before anything walks or reads it
+	if err := sanitizeArtifactDir(artifactPath, logger); err != nil {
+		return nil, errors.Default.Wrap(err, "failed to sanitize artifact")
+	}
 
-			// Validate required fields
-			missingFields := validateRequiredCIJobFields(ciJob)
-			if len(missingFields) > 0 {
-				logger.Warn(nil, "CI job missing required fields, skipping", "job_id", ciJob.JobId, "missing_fields", missingFields)
-				continue
-			}
+	// Extract and parse PipelineRun data from artifact
+	pipelineRuns, err := extractTektonPipelineRuns(ctx, orasClient, artifactPath, workDir, data.ArtifactAllowlist, logger)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to extract PipelineRuns from artifact")
+	}
 
-			// Save to database
-			if err := db.CreateOrUpdate(ciJob); err != nil {
-				logger.Warn(err, "failed to save CI job to database", "job_id", ciJob.JobId)
-				continue
-			}
+	// Manifest annotations add job metadata without parsing the artifact content;
+	// jobs are still saved without it when the manifest can't be read
+	var annotations map[string]string
+	if reader, ok := orasClient.(ManifestAnnotationReader); ok && len(pipelineRuns) > 0 {
+		annotations, err = reader.FetchAnnotations(ctx, artifactRef)
+		if err != nil {
+			logger.Warn(err, "failed to read artifact annotations", "ref", artifactRef)
+		}
+	}
 
-			stats.savedCount++
-			logger.Debug("Saved Tekton CI job", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "result", ciJob.Result)
+	result := &tektonArtifactResult{pipelineRuns: len(pipelineRuns)}
+	logger.Debug("Found %d PipelineRuns in artifact", len(pipelineRuns), "ref", artifactRef)
 
-			// Save Tekton task runs
-			if err := saveTektonTasks(db, logger, data.Options.ConnectionId, ciJob.JobId, pipelineRun.TaskRuns); err != nil {
-				logger.Warn(err, "failed to save Tekton tasks", "job_id", ciJob.JobId)
-			}
+	for _, pipelineRun := range pipelineRuns {
+		if pipelineRun == nil {
+			continue
+		}
 
-			// Find and process JUnit XML files from artifact using configured regex
-			if findAndProcessJUnitFiles(taskCtx, artifactPath, ciJob, quayOrg, repoName, data.JUnitRegex) {
-				stats.junitFoundCount++
-			} else {
-				stats.junitNotFoundCount++
-			}
+		// Extract job ID early to check if already processed
+		jobId := pipelineRun.PipelineRunName
+		if jobId == "" {
+			logger.Warn(nil, "PipelineRun missing PipelineRunName, skipping")
+			continue
 		}
 
-		// Cleanup artifact after processing all PipelineRuns
-		if artifactPath != "" {
-			os.RemoveAll(artifactPath)
+		// Check if job already processed
+		if isTektonJobAlreadyProcessed(db, data.Options.ConnectionId, jobId) {
+			logger.Debug("Tekton job already processed, skipping", "job_id", jobId)
+			result.collectedJobIds = append(result.collectedJobIds, jobId)
+			continue
 		}
+
+		ciJob := saveTektonPipelineRun(db, logger, data, pipelineRun, annotations, rawParams, rawTable, apiURL, quayOrg, repoName, stats)
+		if ciJob == nil {
+			continue
+		}
+
+		// Find and process JUnit XML files from artifact using configured regex
+		junitFound := findAndProcessJUnitFiles(taskCtx, artifactPath, ciJob, quayOrg, repoName, data.JUnitRegex, data.ArtifactAllowlist)
+		stats.recordJUnit(ciJob.JobName, junitFound)
+		result.savedJobs = append(result.savedJobs, tektonArtifactJob{ciJob: ciJob, junitFound: junitFound})
 	}
 
-	return stats
+	return result, nil
+}
+
+// saveTektonPipelineRun saves a PipelineRun as raw data, CI job and Tekton task rows
+//
+// Parameters:
+//   - db: Database connection
+//   - logger: Logger for error reporting
+//   - data: The task data
+//   - pipelineRun: The PipelineRun to save
+//   - annotations: OCI manifest annotations of the artifact holding the PipelineRun, nil if none
+//   - rawParams: Parameters identifying this collection run
+//   - rawTable: Name of the raw data table
+//   - apiURL: The URL the PipelineRun was read from
+//   - organization: Fallback organization when the PipelineRun has no Git info
+//   - repository: Fallback repository when the PipelineRun has no Git info
+//   - stats: Collection statistics, updated with the saved rows
+//
+// Returns:
+//   - *models.TestRegistryCIJob: The saved CI job, or nil if it was skipped (the reason is logged) or filtered out
+func saveTektonPipelineRun(db dal.Dal, logger log.Logger, data *TestRegistryTaskData, pipelineRun *TektonPipelineRun, annotations map[string]string, rawParams, rawTable, apiURL, organization, repository string, stats *collectionStats) *models.TestRegistryCIJob {
+	// Convert to normalized CI job
+	var statusMapping map[string]string
+	if data.Connection != nil {
+		statusMapping = data.Connection.TektonStatusMapping
+	}
+	ciJob, err := convertTektonPipelineRunToCIJob(pipelineRun, data.Options.ConnectionId, data.Options.FullName, organization, repository, statusMapping)
+	if err != nil {
+		logger.Warn(err, "failed to convert Tekton PipelineRun to CI job")
+		return nil
+	}
+
+	// Skip the scenarios and trigger types the scope config doesn't collect
+	if !data.JobFilter.Allows(ciJob) {
+		stats.filteredCount++
+		logger.Debug("Tekton job filtered out by the scope config", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "trigger_type", ciJob.TriggerType)
+		return nil
+	}
+
+	// Save raw PipelineRun JSON
+	origin, rawErr := saveRawTektonData(db, logger, pipelineRun, rawParams, rawTable, apiURL)
+	if rawErr != nil {
+		logger.Warn(rawErr, "failed to save raw Tekton PipelineRun data")
+	} else {
+		stats.rawSavedCount++
+	}
+	ciJob.RawDataOrigin = origin
+	applyArtifactAnnotations(ciJob, annotations)
+	data.JobNameNormalizer.apply(ciJob)
+	data.RepoRenamer.apply(ciJob)
+
+	// Validate required fields
+	missingFields := validateRequiredCIJobFields(ciJob)
+	if len(missingFields) > 0 {
+		logger.Warn(nil, "CI job missing required fields, skipping", "job_id", ciJob.JobId, "missing_fields", missingFields)
+		return nil
+	}
+	// After the validation, so a job reporting a malformed SHA is saved without it rather than skipped
+	data.CommitShaResolver.apply(db, logger, ciJob)
+
+	// Save to database
+	if err := db.CreateOrUpdate(ciJob); err != nil {
+		logger.Warn(err, "failed to save CI job to database", "job_id", ciJob.JobId)
+		return nil
+	}
+
+	stats.savedCount++
+	logger.Debug("Saved Tekton CI job", "job_id", ciJob.JobId, "job_name", ciJob.JobName, "result", ciJob.Result)
+
+	// Save Tekton task runs
+	if err := saveTektonTasks(db, logger, data.Options.ConnectionId, ciJob.JobId, pipelineRun.ConsoleUrl, pipelineRun.TaskRuns); err != nil {
+		logger.Warn(err, "failed to save Tekton tasks", "job_id", ciJob.JobId)
+	}
+
+	return ciJob
 }
 
 // TektonPipelineRun represents a Tekton PipelineRun structure
@@ -369,16 +567,17 @@ type TektonTimestamps struct {
 // TektonPipelineRun represents a Tekton PipelineRun from pipeline-status.json
 // This structure matches the JSON format found in OCI artifacts
 type TektonPipelineRun struct {
-	PipelineRunName string           `json:"pipelineRunName"` // Pipeline run name (e.g., "konflux-e2e-z28lw")
-	Namespace       string           `json:"namespace"`       // Kubernetes namespace (e.g., "konflux-ci")
-	Duration        string           `json:"duration"`        // Total duration in seconds (e.g., "3846s")
-	Status          string           `json:"status"`          // Overall status: "Succeeded", "Failed", etc.
-	EventType       string           `json:"eventType"`       // Event type: "push", "pull_request", etc.
-	Scenario        string           `json:"scenario"`        // Test scenario name (e.g., "konflux-e2e")
-	ConsoleUrl      string           `json:"consoleUrl"`      // URL to view the pipeline in console (e.g., "https://ci.konflux-ci.dev/...")
-	Git             TektonGitInfo    `json:"git"`             // Git organization and repository info
-	Timestamps      TektonTimestamps `json:"timestamps"`      // Timestamp information
-	TaskRuns        []TektonTaskRun  `json:"taskRuns"`        // List of task runs within the pipeline
+	PipelineRunName string           `json:"pipelineRunName"`  // Pipeline run name (e.g., "konflux-e2e-z28lw")
+	Namespace       string           `json:"namespace"`        // Kubernetes namespace (e.g., "konflux-ci")
+	Duration        string           `json:"duration"`         // Total duration in seconds (e.g., "3846s")
+	Status          string           `json:"status"`           // Overall status: "Succeeded", "Failed", etc.
+	Reason          string           `json:"reason,omitempty"` // Reason of the Succeeded condition (e.g., "PipelineRunTimeout"), only set by the Kubernetes source
+	EventType       string           `json:"eventType"`        // Event type: "push", "pull_request", etc.
+	Scenario        string           `json:"scenario"`         // Test scenario name (e.g., "konflux-e2e")
+	ConsoleUrl      string           `json:"consoleUrl"`       // URL to view the pipeline in console (e.g., "https://ci.konflux-ci.dev/...")
+	Git             TektonGitInfo    `json:"git"`              // Git organization and repository info
+	Timestamps      TektonTimestamps `json:"timestamps"`       // Timestamp information
+	TaskRuns        []TektonTaskRun  `json:"taskRuns"`         // List of task runs within the pipeline
 }
 
 // extractTektonPipelineRuns extracts Tekton PipelineRun data from OCI artifact
@@ -388,14 +587,15 @@ type TektonPipelineRun struct {
 // Parameters:
 //   - ctx: Context for the operation
 //   - orasClient: ORAS client
-//   - artifactPath: Local path where artifact was pulled (tmp/{uuid}/)
-//   - loggingDir: Base logging directory (for logging purposes)
+//   - artifactPath: Local path where artifact was pulled ({workDir}/{uuid}/)
+//   - loggingDir: Run working directory (for logging purposes)
+//   - allowlist: Files of the artifact to visit (nil visits every file)
 //   - logger: Logger for error reporting
 //
 // Returns:
 //   - []*TektonPipelineRun: List of PipelineRun objects found in the artifact
 //   - errors.Error: Any error encountered during extraction (should trigger cleanup)
-func extractTektonPipelineRuns(ctx context.Context, orasClient *ORASClient, artifactPath, loggingDir string, logger log.Logger) ([]*TektonPipelineRun, errors.Error) {
+func extractTektonPipelineRuns(ctx context.Context, orasClient ArtifactPuller, artifactPath, loggingDir string, allowlist *ArtifactAllowlist, logger log.Logger) ([]*TektonPipelineRun, errors.Error) {
 	var pipelineRuns []*TektonPipelineRun
 
 	// ORAS extracts files directly to artifactPath, so we search there
@@ -404,9 +604,12 @@ func extractTektonPipelineRuns(ctx context.Context, orasClient *ORASClient, arti
 		if walkErr != nil {
 			return walkErr // Continue on error, but log it
 		}
+		if skipped, skipErr := allowlist.skip(artifactPath, path, info.IsDir()); skipped {
+			return skipErr
+		}
 
-		// Look for pipeline-status.json files
-		if !info.IsDir() && filepath.Base(path) == "pipeline-status.json" {
+		// Look for pipeline-status.json files (regular files only, never through a symlink)
+		if info.Mode().IsRegular() && filepath.Base(path) == "pipeline-status.json" {
 			// Read and parse the pipeline-status.json file
 			content, readErr := os.ReadFile(path)
 			if readErr != nil {
@@ -464,6 +667,38 @@ func isTektonJobAlreadyProcessed(db dal.Dal, connectionId uint64, jobId string)
 	return jobCount > 0
 }
 
+// isTagExpired checks whether a tag of the scope is on the expired tag skip-list
+//
+// Parameters:
+//   - db: Database connection
+//   - connectionId: Connection ID
+//   - scopeId: Scope FullName the tag was listed for
+//   - tag: Quay.io tag name
+//
+// Returns:
+//   - bool: true if a previous pull found the tag expired, false otherwise
+func isTagExpired(db dal.Dal, connectionId uint64, scopeId, tag string) bool {
+	count, err := db.Count(
+		dal.From(&models.ExpiredTag{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND tag = ?", connectionId, scopeId, tag),
+	)
+	if err != nil {
+		// If query fails, assume not expired (safer to try the pull again)
+		return false
+	}
+	return count > 0
+}
+
+// markTagExpired adds a tag of the scope to the expired tag skip-list
+func markTagExpired(db dal.Dal, connectionId uint64, scopeId, tag string) errors.Error {
+	return db.CreateOrUpdate(&models.ExpiredTag{
+		ConnectionId: connectionId,
+		ScopeId:      scopeId,
+		Tag:          tag,
+		ExpiredAt:    time.Now(),
+	})
+}
+
 // setupRawTektonDataCollection initializes the raw data collection subtask for Tekton
 func setupRawTektonDataCollection(taskCtx plugin.SubTaskContext, data *TestRegistryTaskData) (*helper.RawDataSubTask, errors.Error) {
 	return helper.NewRawDataSubTask(helper.RawDataSubTaskArgs{
@@ -487,11 +722,12 @@ func setupRawTektonDataCollection(taskCtx plugin.SubTaskContext, data *TestRegis
 //   - apiURL: The API URL from which this data was fetched
 //
 // Returns:
+//   - common.RawDataOrigin: Origin pointing at the saved raw record, to be stored on the derived rows
 //   - errors.Error: Any error encountered during saving, or nil if successful
-func saveRawTektonData(db dal.Dal, logger log.Logger, pipelineRun *TektonPipelineRun, rawParams string, rawTable, apiURL string) errors.Error {
+func saveRawTektonData(db dal.Dal, logger log.Logger, pipelineRun *TektonPipelineRun, rawParams string, rawTable, apiURL string) (common.RawDataOrigin, errors.Error) {
 	pipelineRunJSON, err := json.Marshal(pipelineRun)
 	if err != nil {
-		return errors.Default.Wrap(err, "failed to marshal Tekton PipelineRun to JSON")
+		return common.RawDataOrigin{}, errors.Default.Wrap(err, "failed to marshal Tekton PipelineRun to JSON")
 	}
 
 	rawData := &helper.RawData{
@@ -501,7 +737,11 @@ func saveRawTektonData(db dal.Dal, logger log.Logger, pipelineRun *TektonPipelin
 		CreatedAt: time.Now(),
 	}
 
-	return db.Create(rawData, dal.From(rawTable))
+	if err := db.Create(rawData, dal.From(rawTable)); err != nil {
+		return common.RawDataOrigin{}, err
+	}
+
+	return rawDataOrigin(rawTable, rawParams, rawData.ID), nil
 }
 
 // convertTektonPipelineRunToCIJob converts a TektonPipelineRun to a TestRegistryCIJob model
@@ -512,11 +752,12 @@ func saveRawTektonData(db dal.Dal, logger log.Logger, pipelineRun *TektonPipelin
 //   - scopeId: The scope ID (repository full name)
 //   - organization: The Quay.io organization name
 //   - repository: The repository name
+//   - statusMapping: The connection's tektonStatusMapping, consulted before the built-in status mapping (can be nil)
 //
 // Returns:
 //   - *models.TestRegistryCIJob: The converted CI job model
 //   - errors.Error: An error if conversion fails
-func convertTektonPipelineRunToCIJob(pipelineRun *TektonPipelineRun, connectionId uint64, scopeId, organization, repository string) (*models.TestRegistryCIJob, errors.Error) {
+func convertTektonPipelineRunToCIJob(pipelineRun *TektonPipelineRun, connectionId uint64, scopeId, organization, repository string, statusMapping map[string]string) (*models.TestRegistryCIJob, errors.Error) {
 	ciJob := &models.TestRegistryCIJob{
 		ConnectionId: connectionId,
 		JobType:      "tekton",
@@ -566,24 +807,7 @@ func convertTektonPipelineRunToCIJob(pipelineRun *TektonPipelineRun, connectionI
 		ciJob.TriggerType = "push" // Default for "push" or any other event type
 	}
 
-	// Map status from Status field to database result
-	// Based on Tekton PipelineRun status values:
-	// Reference: https://github.com/konflux-ci/tekton-integration-catalog/blob/main/tasks/store-pipeline-status/0.1/store-pipeline-status.yaml
-	// Possible values: "Succeeded", "Failed", "Cancelled", "Running", "Pending", etc.
-	switch pipelineRun.Status {
-	case "Succeeded":
-		ciJob.Result = "SUCCESS"
-	case "Failed":
-		ciJob.Result = "FAILURE"
-	case "Cancelled":
-		ciJob.Result = "ABORTED" // Map cancelled to ABORTED (standard CI/CD status)
-	case "Running":
-		ciJob.Result = "OTHER" // Running jobs are typically not stored, but handle if found
-	case "Pending":
-		ciJob.Result = "OTHER" // Pending jobs are typically not stored, but handle if found
-	default:
-		ciJob.Result = "OTHER" // For "Unknown", or any other unexpected status
-	}
+	ciJob.Result = mapTektonStatus(pipelineRun, statusMapping)
 
 	// Parse duration from string (e.g., "3846s") to seconds
 	// Duration format is like "3846s" - extract the number and convert to float64
@@ -626,6 +850,43 @@ func convertTektonPipelineRunToCIJob(pipelineRun *TektonPipelineRun, connectionI
 	return ciJob, nil
 }
 
+// defaultTektonStatusResults maps the PipelineRun statuses written by store-pipeline-status to CI job results.
+// Reference: https://github.com/konflux-ci/tekton-integration-catalog/blob/main/tasks/store-pipeline-status/0.1/store-pipeline-status.yaml
+var defaultTektonStatusResults = map[string]string{
+	"Succeeded": "SUCCESS",
+	"Failed":    "FAILURE",
+	"Cancelled": "ABORTED", // Map cancelled to ABORTED (standard CI/CD status)
+	"Running":   "OTHER",   // Running jobs are typically not stored, but handle if found
+	"Pending":   "OTHER",   // Pending jobs are typically not stored, but handle if found
+}
+
+// mapTektonStatus maps the status of a PipelineRun to a CI job result.
+//
+// The connection's status mapping is looked up first, by condition reason and then by status,
+// so custom statuses such as "CouldntGetTask" or "PipelineRunTimeout" land in the configured
+// bucket. Statuses missing from both mappings, e.g. "Unknown", map to "OTHER".
+//
+// Parameters:
+//   - pipelineRun: The source Tekton PipelineRun
+//   - statusMapping: Status or reason -> result overrides from the connection (can be nil)
+//
+// Returns:
+//   - string: The CI job result ("SUCCESS", "FAILURE", "ABORTED" or "OTHER")
+func mapTektonStatus(pipelineRun *TektonPipelineRun, statusMapping map[string]string) string {
+	for _, key := range []string{pipelineRun.Reason, pipelineRun.Status} {
+		if key == "" {
+			continue
+		}
+		if result, ok := statusMapping[key]; ok {
+			return result
+		}
+	}
+	if result, ok := defaultTektonStatusResults[pipelineRun.Status]; ok {
+		return result
+	}
+	return "OTHER" // For "Unknown", or any other unexpected status
+}
+
 // validateRequiredCIJobFields validates that all required fields are present in a CI job
 // Returns a list of missing required field names
 func validateRequiredCIJobFields(ciJob *models.TestRegistryCIJob) []string {
@@ -67,7 +67,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 		}`
 		assert.NoError(t, os.WriteFile(filepath.Join(subDir, "pipeline-status.json"), []byte(json), 0o644))
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Len(t, runs, 1)
 		assert.Equal(t, "test-run-1", runs[0].PipelineRunName)
@@ -90,7 +90,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 		json := `{"pipelineRunName": "", "status": "Failed"}`
 		assert.NoError(t, os.WriteFile(filepath.Join(subDir, "pipeline-status.json"), []byte(json), 0o644))
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Empty(t, runs)
 	})
@@ -105,7 +105,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 		json := `{"pipelineRunName": "run-1", "status": ""}`
 		assert.NoError(t, os.WriteFile(filepath.Join(subDir, "pipeline-status.json"), []byte(json), 0o644))
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Empty(t, runs)
 	})
@@ -119,7 +119,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 
 		assert.NoError(t, os.WriteFile(filepath.Join(subDir, "pipeline-status.json"), []byte("{not valid json"), 0o644))
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Empty(t, runs)
 	})
@@ -129,7 +129,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 
 		dir := t.TempDir()
 		// Empty directory — no files
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Empty(t, runs)
 	})
@@ -151,7 +151,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 		json2 := `{"pipelineRunName": "run-beta", "status": "Failed", "namespace": "ns2"}`
 		assert.NoError(t, os.WriteFile(filepath.Join(sub2, "pipeline-status.json"), []byte(json2), 0o644))
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Len(t, runs, 2)
 
@@ -167,7 +167,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 	t.Run("nonexistent directory returns error", func(t *testing.T) {
 		logger := newMockLogger()
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, "/nonexistent/path", "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, "/nonexistent/path", "/tmp/logs", nil, logger)
 		assert.NotNil(t, err)
 		assert.Nil(t, runs)
 	})
@@ -180,7 +180,7 @@ func TestExtractTektonPipelineRuns(t *testing.T) {
 		assert.NoError(t, os.WriteFile(filepath.Join(dir, "other-file.json"), []byte(`{"key":"val"}`), 0o644))
 		assert.NoError(t, os.WriteFile(filepath.Join(dir, "pipeline-status.txt"), []byte(`not json`), 0o644))
 
-		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", logger)
+		runs, err := extractTektonPipelineRuns(ctx, nil, dir, "/tmp/logs", nil, logger)
 		assert.Nil(t, err)
 		assert.Empty(t, runs)
 	})
@@ -53,7 +53,7 @@ func TestConvertTektonPipelineRunToCIJob(t *testing.T) {
 			},
 		}
 
-		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "scope-1", "quay-org", "repo")
+		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "scope-1", "quay-org", "repo", nil)
 		assert.Nil(t, err)
 		assert.Equal(t, "run-abc", ciJob.JobId)
 		assert.Equal(t, "e2e-test", ciJob.JobName)
@@ -90,7 +90,7 @@ func TestConvertTektonPipelineRunToCIJob(t *testing.T) {
 			},
 		}
 
-		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "scope", "org", "repo")
+		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "scope", "org", "repo", nil)
 		assert.Nil(t, err)
 		assert.Equal(t, "push", ciJob.TriggerType)
 		assert.Equal(t, "FAILURE", ciJob.Result)
@@ -117,7 +117,34 @@ func TestConvertTektonPipelineRunToCIJob(t *testing.T) {
 					Status:          tt.input,
 					Scenario:        "test",
 				}
-				ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r")
+				ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r", nil)
+				assert.Nil(t, err)
+				assert.Equal(t, tt.expected, ciJob.Result)
+			})
+		}
+	})
+
+	t.Run("connection status mapping", func(t *testing.T) {
+		statusMapping := map[string]string{
+			"CouldntGetTask":     "FAILURE",
+			"PipelineRunTimeout": "ABORTED",
+			"Failed":             "OTHER",
+		}
+		cases := []struct {
+			status   string
+			reason   string
+			expected string
+		}{
+			{"CouldntGetTask", "", "FAILURE"},
+			{"Failed", "PipelineRunTimeout", "ABORTED"}, // reason wins over status
+			{"Failed", "Failed", "OTHER"},
+			{"Succeeded", "Completed", "SUCCESS"}, // not in the mapping, built-in mapping applies
+			{"Unknown", "", "OTHER"},
+		}
+		for _, tt := range cases {
+			t.Run(tt.status+"/"+tt.reason, func(t *testing.T) {
+				pr := &TektonPipelineRun{PipelineRunName: "run-1", Status: tt.status, Reason: tt.reason, Scenario: "test"}
+				ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r", statusMapping)
 				assert.Nil(t, err)
 				assert.Equal(t, tt.expected, ciJob.Result)
 			})
@@ -131,7 +158,7 @@ func TestConvertTektonPipelineRunToCIJob(t *testing.T) {
 			Scenario:        "test",
 		}
 
-		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "scope", "fallback-org", "fallback-repo")
+		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "scope", "fallback-org", "fallback-repo", nil)
 		assert.Nil(t, err)
 		assert.Equal(t, "fallback-org", ciJob.Organization)
 		assert.Equal(t, "fallback-repo", ciJob.Repository)
@@ -144,7 +171,7 @@ func TestConvertTektonPipelineRunToCIJob(t *testing.T) {
 			Scenario:        "test",
 			Duration:        "not-a-duration",
 		}
-		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r")
+		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r", nil)
 		assert.Nil(t, err)
 		assert.Nil(t, ciJob.DurationSec)
 	})
@@ -155,7 +182,7 @@ func TestConvertTektonPipelineRunToCIJob(t *testing.T) {
 			Status:          "Succeeded",
 			Scenario:        "test",
 		}
-		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r")
+		ciJob, err := convertTektonPipelineRunToCIJob(pr, 1, "s", "o", "r", nil)
 		assert.Nil(t, err)
 		assert.Nil(t, ciJob.DurationSec)
 	})
@@ -288,8 +315,10 @@ func TestSaveRawTektonData(t *testing.T) {
 			PipelineRunName: "run-1",
 			Status:          "Succeeded",
 		}
-		err := saveRawTektonData(mockDal, mockLogger, pr, `{"ConnectionId":1}`, "raw_table", "https://api.example.com")
+		origin, err := saveRawTektonData(mockDal, mockLogger, pr, `{"ConnectionId":1}`, "raw_table", "https://api.example.com")
 		assert.Nil(t, err)
+		assert.Equal(t, "raw_table", origin.RawDataTable)
+		assert.Equal(t, `{"ConnectionId":1}`, origin.RawDataParams)
 	})
 
 	t.Run("error", func(t *testing.T) {
@@ -298,7 +327,7 @@ func TestSaveRawTektonData(t *testing.T) {
 		mockDal.On("Create", mock.Anything, mock.Anything).Return(errors.Default.New("db error"))
 
 		pr := &TektonPipelineRun{PipelineRunName: "run-err"}
-		err := saveRawTektonData(mockDal, mockLogger, pr, `{}`, "raw_table", "url")
+		_, err := saveRawTektonData(mockDal, mockLogger, pr, `{}`, "raw_table", "url")
 		assert.NotNil(t, err)
 	})
 }
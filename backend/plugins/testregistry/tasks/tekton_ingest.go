@@ -0,0 +1,146 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// IngestedJob is a CI job saved by an on-demand artifact ingestion, with the test
+// counts of the JUnit results found for it
+type IngestedJob struct {
+	JobId        string `json:"jobId"`
+	JobName      string `json:"jobName"`
+	Result       string `json:"result"`
+	JUnitFound   bool   `json:"junitFound"`
+	SuitesCount  uint   `json:"suitesCount"`
+	TotalTests   uint   `json:"totalTests"`
+	FailedTests  uint   `json:"failedTests"`
+	SkippedTests uint   `json:"skippedTests"`
+}
+
+// ArtifactIngestSummary reports what an on-demand ingestion of one Tekton artifact saved
+type ArtifactIngestSummary struct {
+	Repository      string        `json:"repository"`
+	Ref             string        `json:"ref"`
+	PipelineRuns    int           `json:"pipelineRuns"`
+	Jobs            []IngestedJob `json:"jobs"`
+	CollectedJobIds []string      `json:"collectedJobIds"`
+	DurationSeconds float64       `json:"durationSeconds"`
+}
+
+// IngestTektonArtifact pulls one Tekton OCI artifact of the task scope by tag or digest
+// and processes it the way CollectTektonJobs does, without listing tags or moving the
+// backfill cursor. It lets a new store-pipeline-status task version be checked on a
+// single artifact before it rolls out. PipelineRuns whose job was collected before
+// are not saved again and are listed in CollectedJobIds.
+//
+// Parameters:
+//   - taskCtx: A subtask context holding the TestRegistryTaskData of the scope
+//   - ref: Tag or digest (sha256:...) of the artifact
+//
+// Returns:
+//   - *ArtifactIngestSummary: The PipelineRuns found and the CI jobs saved
+//   - errors.Error: errors.BadInput for a connection not reading Quay.io artifacts,
+//     errors.NotFound if the artifact doesn't exist, or any pull or extraction error
+func IngestTektonArtifact(taskCtx plugin.SubTaskContext, ref string) (*ArtifactIngestSummary, errors.Error) {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	startedAt := time.Now()
+
+	if data.Connection.CITool != models.CIToolTektonCI || data.Connection.UsesKubernetes() {
+		return nil, errors.BadInput.New("artifact ingestion needs a Tekton CI connection reading Quay.io artifacts")
+	}
+	if ref == "" {
+		return nil, errors.BadInput.New("tag or digest is required")
+	}
+	quayOrg, repoName, err := tektonQuayRepository(data)
+	if err != nil {
+		return nil, err
+	}
+	repoFullPath := fmt.Sprintf("%s/%s", quayOrg, repoName)
+
+	rawDataSubTask, err := setupRawTektonDataCollection(taskCtx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := newRunWorkDir(LoggingDir(), data.Options.ConnectionId, data.Options.FullName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cleanupErr := os.RemoveAll(workDir); cleanupErr != nil {
+			logger.Warn(cleanupErr, "failed to cleanup working directory", "path", workDir)
+		}
+	}()
+
+	orasClient := data.ArtifactPullerOverride
+	if orasClient == nil {
+		client, err := NewORASClient(taskCtx.GetContext(), QuayRegistryURL, repoFullPath, workDir, data.Connection, logger)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "failed to create ORAS client")
+		}
+		orasClient = client
+	}
+
+	logger.Info("Ingesting artifact on demand: quay.io/%s %s", repoFullPath, ref)
+	db := taskCtx.GetDal()
+	stats := collectionStats{}
+	apiURL := fmt.Sprintf("oras://%s/%s", QuayRegistryURL, repoFullPath)
+	result, err := processTektonArtifact(taskCtx, orasClient, ref, data, db, rawDataSubTask.GetTable(), rawDataSubTask.GetParams(), apiURL, workDir, quayOrg, repoName, &stats)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ArtifactIngestSummary{
+		Repository:      repoFullPath,
+		Ref:             ref,
+		PipelineRuns:    result.pipelineRuns,
+		Jobs:            make([]IngestedJob, 0, len(result.savedJobs)),
+		CollectedJobIds: result.collectedJobIds,
+	}
+	for _, saved := range result.savedJobs {
+		job := IngestedJob{
+			JobId:      saved.ciJob.JobId,
+			JobName:    saved.ciJob.JobName,
+			Result:     saved.ciJob.Result,
+			JUnitFound: saved.junitFound,
+		}
+		// The test counts are updated in the database once the JUnit suites are saved
+		counts := &models.TestRegistryCIJob{}
+		if err := db.First(counts, dal.Where("connection_id = ? AND job_id = ?", saved.ciJob.ConnectionId, saved.ciJob.JobId)); err != nil {
+			logger.Warn(err, "failed to read the test counts of the ingested job", "job_id", saved.ciJob.JobId)
+		} else {
+			job.SuitesCount = counts.SuitesCount
+			job.TotalTests = counts.TotalTests
+			job.FailedTests = counts.FailedTests
+			job.SkippedTests = counts.SkippedTests
+		}
+		summary.Jobs = append(summary.Jobs, job)
+	}
+	summary.DurationSeconds = time.Since(startedAt).Seconds()
+	return summary, nil
+}
@@ -18,6 +18,7 @@ limitations under the License.
 package tasks
 
 import (
+	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -38,11 +39,12 @@ import (
 //   - logger: Logger for error reporting
 //   - connectionId: The DevLake connection ID
 //   - jobId: The CI job ID (PipelineRunName)
+//   - consoleUrl: Console URL of the PipelineRun, used to build deep links for failed tasks
 //   - taskRuns: List of TektonTaskRun objects from pipeline-status.json
 //
 // Returns:
 //   - errors.Error: Any error encountered during saving, or nil if successful
-func saveTektonTasks(db dal.Dal, logger log.Logger, connectionId uint64, jobId string, taskRuns []TektonTaskRun) errors.Error {
+func saveTektonTasks(db dal.Dal, logger log.Logger, connectionId uint64, jobId, consoleUrl string, taskRuns []TektonTaskRun) errors.Error {
 	for _, taskRun := range taskRuns {
 		if taskRun.Name == "" {
 			logger.Warn(nil, "Task run missing name, skipping", "job_id", jobId)
@@ -69,6 +71,11 @@ func saveTektonTasks(db dal.Dal, logger log.Logger, connectionId uint64, jobId s
 			DurationSec:  durationSec,
 		}
 
+		// Link failed tasks straight to their logs so triage doesn't start from the pipeline overview
+		if taskRun.Status == "Failed" {
+			task.ConsoleUrl = buildTaskRunConsoleUrl(consoleUrl, taskRun.Name)
+		}
+
 		if err := db.CreateOrUpdate(task); err != nil {
 			logger.Warn(err, "failed to save Tekton task", "job_id", jobId, "task_name", taskRun.Name)
 			continue
@@ -80,19 +87,49 @@ func saveTektonTasks(db dal.Dal, logger log.Logger, connectionId uint64, jobId s
 	return nil
 }
 
+// buildTaskRunConsoleUrl builds a deep link to a task's logs in the Konflux console
+// The PipelineRun console URL opens the pipeline overview; the logs tab accepts a
+// task query parameter that preselects the given task
+//
+// Parameters:
+//   - consoleUrl: Console URL of the PipelineRun (e.g., "https://konflux.dev/ns/ci/pipelineruns/e2e-abc")
+//   - taskName: Task name within the PipelineRun (e.g., "deploy-konflux")
+//
+// Returns:
+//   - string: Deep link to the task logs, or empty string if consoleUrl is empty or invalid
+func buildTaskRunConsoleUrl(consoleUrl, taskName string) string {
+	if consoleUrl == "" || taskName == "" {
+		return ""
+	}
+
+	parsed, err := url.Parse(consoleUrl)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ""
+	}
+
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/") + "/logs"
+	query := parsed.Query()
+	query.Set("task", taskName)
+	parsed.RawQuery = query.Encode()
+	parsed.Fragment = ""
+
+	return parsed.String()
+}
+
 // findAndProcessJUnitFiles finds JUnit XML files in the artifact directory and processes them
 //
 // Parameters:
 //   - taskCtx: The subtask context
-//   - artifactPath: Local path where artifact was pulled (tmp/{uuid}/)
+//   - artifactPath: Local path where artifact was pulled ({workDir}/{uuid}/)
 //   - ciJob: The CI job model
 //   - organization: The organization name (for logging)
 //   - repository: The repository name (for logging)
 //   - junitRegex: Compiled regex pattern for matching JUnit file names
+//   - allowlist: Files of the artifact to visit (nil visits every file)
 //
 // Returns:
 //   - bool: true if at least one JUnit XML file was found and processed successfully, false otherwise
-func findAndProcessJUnitFiles(taskCtx plugin.SubTaskContext, artifactPath string, ciJob *models.TestRegistryCIJob, organization, repository string, junitRegex *regexp.Regexp) bool {
+func findAndProcessJUnitFiles(taskCtx plugin.SubTaskContext, artifactPath string, ciJob *models.TestRegistryCIJob, organization, repository string, junitRegex *regexp.Regexp, allowlist *ArtifactAllowlist) bool {
 	logger := taskCtx.GetLogger()
 
 	// Use default regex if not provided
@@ -113,9 +150,12 @@ func findAndProcessJUnitFiles(taskCtx plugin.SubTaskContext, artifactPath string
 		if walkErr != nil {
 			return walkErr
 		}
+		if skipped, skipErr := allowlist.skip(artifactPath, path, info.IsDir()); skipped {
+			return skipErr
+		}
 
-		// Look for files matching the JUnit regex pattern
-		if !info.IsDir() {
+		// Look for regular files matching the JUnit regex pattern (symlinks are not followed)
+		if info.Mode().IsRegular() {
 			fileName := filepath.Base(path)
 			if junitRegex.MatchString(fileName) {
 				logger.Debug("Found JUnit XML file", "file", fileName, "path", path, "job_id", ciJob.JobId)
@@ -169,6 +209,9 @@ func findAndProcessJUnitFiles(taskCtx plugin.SubTaskContext, artifactPath string
 	}
 
 	logger.Info("Finished processing JUnit XML files", "job_id", ciJob.JobId, "total_files", len(junitFiles), "successful", successCount)
+	if successCount > 0 {
+		updateJobTestCounts(taskCtx.GetDal(), logger, ciJob)
+	}
 
 	// Return true if at least one file was successfully processed
 	return successCount > 0
@@ -44,7 +44,7 @@ func TestSaveTektonTasks(t *testing.T) {
 			{Name: "build", Status: "Succeeded", Duration: "120s"},
 			{Name: "test", Status: "Failed", Duration: "300s"},
 		}
-		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", taskRuns)
+		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", "", taskRuns)
 		assert.Nil(t, err)
 		mockDal.AssertNumberOfCalls(t, "CreateOrUpdate", 2)
 	})
@@ -52,7 +52,7 @@ func TestSaveTektonTasks(t *testing.T) {
 	t.Run("empty task runs", func(t *testing.T) {
 		mockDal := new(mockdal.Dal)
 		mockLogger := new(mocklog.Logger)
-		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", []TektonTaskRun{})
+		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", "", []TektonTaskRun{})
 		assert.Nil(t, err)
 	})
 
@@ -62,7 +62,7 @@ func TestSaveTektonTasks(t *testing.T) {
 		mockLogger.On("Warn", mock.Anything, mock.Anything, mock.Anything).Maybe()
 
 		taskRuns := []TektonTaskRun{{Name: "", Status: "Succeeded"}}
-		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", taskRuns)
+		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", "", taskRuns)
 		assert.Nil(t, err)
 		mockDal.AssertNotCalled(t, "CreateOrUpdate")
 	})
@@ -74,7 +74,7 @@ func TestSaveTektonTasks(t *testing.T) {
 		mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
 
 		taskRuns := []TektonTaskRun{{Name: "task1", Status: "Succeeded", Duration: "invalid"}}
-		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", taskRuns)
+		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", "", taskRuns)
 		assert.Nil(t, err)
 	})
 
@@ -86,11 +86,54 @@ func TestSaveTektonTasks(t *testing.T) {
 		mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
 
 		taskRuns := []TektonTaskRun{{Name: "task1", Status: "Failed"}}
-		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", taskRuns)
+		err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", "", taskRuns)
 		assert.Nil(t, err) // saveTektonTasks continues on error, returns nil
 	})
 }
 
+func TestSaveTektonTasksConsoleUrl(t *testing.T) {
+	mockDal := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+
+	var saved []*models.TektonTask
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, args.Get(0).(*models.TektonTask))
+	}).Return(nil)
+
+	taskRuns := []TektonTaskRun{
+		{Name: "build", Status: "Succeeded", Duration: "120s"},
+		{Name: "deploy-konflux", Status: "Failed", Duration: "300s"},
+	}
+	err := saveTektonTasks(mockDal, mockLogger, 1, "job-1", "https://console.example.com/ns/ci/pipelineruns/job-1", taskRuns)
+	assert.Nil(t, err)
+	assert.Len(t, saved, 2)
+	assert.Empty(t, saved[0].ConsoleUrl)
+	assert.Equal(t, "https://console.example.com/ns/ci/pipelineruns/job-1/logs?task=deploy-konflux", saved[1].ConsoleUrl)
+}
+
+func TestBuildTaskRunConsoleUrl(t *testing.T) {
+	tests := []struct {
+		name       string
+		consoleUrl string
+		taskName   string
+		want       string
+	}{
+		{"empty console url", "", "deploy", ""},
+		{"empty task name", "https://console.example.com/run-abc", "", ""},
+		{"relative url", "run-abc", "deploy", ""},
+		{"plain url", "https://console.example.com/run-abc", "deploy", "https://console.example.com/run-abc/logs?task=deploy"},
+		{"trailing slash", "https://console.example.com/run-abc/", "deploy", "https://console.example.com/run-abc/logs?task=deploy"},
+		{"existing query kept", "https://console.example.com/run-abc?tab=1", "deploy", "https://console.example.com/run-abc/logs?tab=1&task=deploy"},
+		{"task name escaped", "https://console.example.com/run-abc", "e2e tests", "https://console.example.com/run-abc/logs?task=e2e+tests"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildTaskRunConsoleUrl(tt.consoleUrl, tt.taskName))
+		})
+	}
+}
+
 // setupMockContext creates a mock SubTaskContext with logger and dal wired up.
 // The dal mock is configured to allow CreateOrUpdate calls (for suite/test case saves).
 func setupMockContext(t *testing.T) (*mockplugin.SubTaskContext, *mockdal.Dal, *mocklog.Logger) {
@@ -101,6 +144,7 @@ func setupMockContext(t *testing.T) (*mockplugin.SubTaskContext, *mockdal.Dal, *
 
 	mockCtx.On("GetLogger").Return(mockLogger)
 	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetData").Return(nil).Maybe()
 
 	// Logger — the generated mock packs variadic args into a single slice arg
 	mockLogger.On("Info", mock.Anything, mock.Anything).Maybe()
@@ -109,6 +153,8 @@ func setupMockContext(t *testing.T) (*mockplugin.SubTaskContext, *mockdal.Dal, *
 
 	// Dal — CreateOrUpdate is called by saveSuiteRecursively and saveTestCase
 	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Maybe()
+	// UpdateColumns stores the job test counts once its JUnit files are processed
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
 
 	return mockCtx, mockDal, mockLogger
 }
@@ -141,7 +187,7 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 
 		// Use a regex that matches the file we created
 		re := regexp.MustCompile(`e2e-results\.xml`)
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re)
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re, nil)
 		assert.True(t, result)
 	})
 
@@ -150,7 +196,7 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 
 		dir := t.TempDir()
 		// Empty directory — no files at all
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", regexp.MustCompile(`junit.*\.xml`))
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", regexp.MustCompile(`junit.*\.xml`), nil)
 		assert.False(t, result)
 	})
 
@@ -162,7 +208,7 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 		assert.NoError(t, os.WriteFile(filepath.Join(dir, "e2e-second.xml"), []byte(validJUnitXML), 0o644))
 
 		re := regexp.MustCompile(`e2e-.*\.xml`)
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re)
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re, nil)
 		assert.True(t, result)
 	})
 
@@ -173,7 +219,7 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 		// File name must match the DefaultJUnitRegexPattern: (devlake-|e2e|qd-report-)[0-9a-z-]+\.(xml|junit)
 		assert.NoError(t, os.WriteFile(filepath.Join(dir, "e2e-abc123.xml"), []byte(validJUnitXML), 0o644))
 
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", nil)
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", nil, nil)
 		assert.True(t, result)
 	})
 
@@ -188,7 +234,7 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 		t.Cleanup(func() { os.Chmod(filePath, 0o644) })
 
 		re := regexp.MustCompile(`e2e-.*\.xml`)
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re)
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re, nil)
 		// The file is found but cannot be read, so no files are successfully processed
 		assert.False(t, result)
 	})
@@ -202,14 +248,14 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 		assert.NoError(t, os.WriteFile(filepath.Join(dir, "report.json"), []byte("{}"), 0o644))
 
 		re := regexp.MustCompile(`e2e-.*\.xml`)
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re)
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re, nil)
 		assert.False(t, result)
 	})
 
 	t.Run("nonexistent directory returns false", func(t *testing.T) {
 		mockCtx, _, _ := setupMockContext(t)
 
-		result := findAndProcessJUnitFiles(mockCtx, "/nonexistent/path/to/artifacts", ciJob, "org", "repo", regexp.MustCompile(`.*\.xml`))
+		result := findAndProcessJUnitFiles(mockCtx, "/nonexistent/path/to/artifacts", ciJob, "org", "repo", regexp.MustCompile(`.*\.xml`), nil)
 		assert.False(t, result)
 	})
 
@@ -222,7 +268,7 @@ func TestFindAndProcessJUnitFiles(t *testing.T) {
 		assert.NoError(t, os.WriteFile(filepath.Join(subDir, "e2e-deep.xml"), []byte(validJUnitXML), 0o644))
 
 		re := regexp.MustCompile(`e2e-.*\.xml`)
-		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re)
+		result := findAndProcessJUnitFiles(mockCtx, dir, ciJob, "org", "repo", re, nil)
 		assert.True(t, result)
 	})
 }
@@ -0,0 +1,152 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// WebhookJUnitFile is a JUnit XML report sent inline with a webhook PipelineRun
+type WebhookJUnitFile struct {
+	Name    string `json:"name"`    // File name, e.g. "e2e-report.xml"; webhook-junit-<n>.xml when empty
+	Content string `json:"content"` // The JUnit XML
+}
+
+// WebhookIngestSummary reports what a webhook saved for one PipelineRun
+type WebhookIngestSummary struct {
+	JobId string `json:"jobId"`
+	// AlreadyCollected is set when the job was saved before, by the collector or an earlier call,
+	// in which case nothing is saved again
+	AlreadyCollected bool `json:"alreadyCollected"`
+	// Filtered is set when the scope config's scenario or trigger type filters leave the job out
+	Filtered     bool   `json:"filtered"`
+	JobName      string `json:"jobName,omitempty"`
+	Result       string `json:"result,omitempty"`
+	JUnitFiles   int    `json:"junitFiles"`
+	JUnitFound   bool   `json:"junitFound"`
+	SuitesCount  uint   `json:"suitesCount"`
+	TotalTests   uint   `json:"totalTests"`
+	FailedTests  uint   `json:"failedTests"`  // Failures and errors of the top-level suites
+	ErroredTests int64  `json:"erroredTests"` // Test cases that errored rather than failed an assertion
+	SkippedTests uint   `json:"skippedTests"`
+}
+
+// IngestTektonWebhook saves a PipelineRun sent by a Tekton finally-task, in the pipeline-status.json
+// format, and its inline JUnit reports the way the Tekton collector does, so results show up without
+// waiting for the next collection. The collector later skips the job, like any job collected before.
+//
+// Parameters:
+//   - taskCtx: A subtask context holding the TestRegistryTaskData of the scope
+//   - pipelineRun: The PipelineRun of the pipeline-status.json payload
+//   - junitFiles: Inline JUnit XML reports of the PipelineRun (may be empty)
+//
+// Returns:
+//   - *WebhookIngestSummary: The saved job and the test counts of its JUnit results
+//   - errors.Error: errors.BadInput for a connection that isn't a Tekton CI one or a PipelineRun
+//     that can't be converted to a CI job (the missing fields are logged)
+func IngestTektonWebhook(taskCtx plugin.SubTaskContext, pipelineRun *TektonPipelineRun, junitFiles []WebhookJUnitFile) (*WebhookIngestSummary, errors.Error) {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+	db := taskCtx.GetDal()
+
+	if data.Connection.CITool != models.CIToolTektonCI {
+		return nil, errors.BadInput.New("webhook ingestion needs a Tekton CI connection")
+	}
+	if pipelineRun == nil || pipelineRun.PipelineRunName == "" {
+		return nil, errors.BadInput.New("pipelineRunName is required")
+	}
+
+	// Fallback organization and repository when the PipelineRun has no Git info, as the collectors use
+	organization, repository := data.Options.FullName, data.Options.FullName
+	if !data.Connection.UsesKubernetes() {
+		var err errors.Error
+		organization, repository, err = tektonQuayRepository(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	startedAt := time.Now()
+	summary := &WebhookIngestSummary{JobId: pipelineRun.PipelineRunName, JUnitFiles: len(junitFiles)}
+	if isTektonJobAlreadyProcessed(db, data.Options.ConnectionId, pipelineRun.PipelineRunName) {
+		logger.Info("Webhook PipelineRun already collected, skipping", "job_id", pipelineRun.PipelineRunName)
+		summary.AlreadyCollected = true
+		return summary, nil
+	}
+
+	rawDataSubTask, err := setupRawTektonDataCollection(taskCtx, data)
+	if err != nil {
+		return nil, err
+	}
+	stats := collectionStats{}
+	apiURL := fmt.Sprintf("webhook://connections/%d/%s", data.Options.ConnectionId, data.Options.FullName)
+	ciJob := saveTektonPipelineRun(db, logger, data, pipelineRun, nil, rawDataSubTask.GetParams(), rawDataSubTask.GetTable(), apiURL, organization, repository, &stats)
+	if ciJob == nil {
+		if stats.filteredCount > 0 {
+			summary.Filtered = true
+			return summary, nil
+		}
+		return nil, errors.BadInput.New(fmt.Sprintf("PipelineRun %s could not be saved as a CI job, check the required fields", pipelineRun.PipelineRunName))
+	}
+	summary.JobId = ciJob.JobId
+	summary.JobName = ciJob.JobName
+	summary.Result = ciJob.Result
+
+	found := 0
+	for i, file := range junitFiles {
+		name := file.Name
+		if name == "" {
+			name = fmt.Sprintf("webhook-junit-%d.xml", i+1)
+		}
+		if parseAndSaveJUnitSuites(taskCtx, logger, []byte(file.Content), name, ciJob, organization, repository) {
+			found++
+		}
+	}
+	summary.JUnitFound = found > 0
+	if found > 0 {
+		updateJobTestCounts(db, logger, ciJob)
+	}
+	stats.recordJUnit(ciJob.JobName, summary.JUnitFound)
+	recordJUnitAvailability(db, logger, data, startedAt, stats.junitByJobName)
+
+	// The test counts are updated in the database once the JUnit suites are saved
+	counts := &models.TestRegistryCIJob{}
+	if err := db.First(counts, dal.Where("connection_id = ? AND job_id = ?", ciJob.ConnectionId, ciJob.JobId)); err != nil {
+		logger.Warn(err, "failed to read the test counts of the webhook job", "job_id", ciJob.JobId)
+	} else {
+		summary.SuitesCount = counts.SuitesCount
+		summary.TotalTests = counts.TotalTests
+		summary.FailedTests = counts.FailedTests
+		summary.SkippedTests = counts.SkippedTests
+	}
+	errored, err := db.Count(
+		dal.From(&models.TestCase{}),
+		dal.Where("connection_id = ? AND job_id = ? AND status = ?", ciJob.ConnectionId, ciJob.JobId, "errored"),
+	)
+	if err != nil {
+		logger.Warn(err, "failed to count the errored test cases of the webhook job", "job_id", ciJob.JobId)
+	}
+	summary.ErroredTests = errored
+	return summary, nil
+}
@@ -0,0 +1,206 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// PruneTestCasesMeta defines the metadata for the test case retention subtask
+var PruneTestCasesMeta = plugin.SubTaskMeta{
+	Name:             "pruneTestCases",
+	EntryPoint:       PruneTestCases,
+	EnabledByDefault: true,
+	Description:      "Delete or archive the test cases of jobs started more than testCaseRetentionDays ago, keeping the jobs and suites with their counters",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	Dependencies:     []*plugin.SubTaskMeta{&ConvertTestCasesMeta, &ClusterFailureMessagesMeta},
+}
+
+// pruneJobBatchSize is the number of jobs whose test cases are pruned in one transaction
+const pruneJobBatchSize = 100
+
+// TestCaseRetention is the test case retention of a scope
+type TestCaseRetention struct {
+	Days int
+	Mode string
+}
+
+// NewTestCaseRetention builds the retention from the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *TestCaseRetention: The retention, or nil if every test case is kept
+//   - errors.Error: An error if the retention mode is unknown
+func NewTestCaseRetention(scopeConfig *models.TestRegistryScopeConfig) (*TestCaseRetention, errors.Error) {
+	if scopeConfig == nil || scopeConfig.TestCaseRetentionDays <= 0 {
+		return nil, nil
+	}
+	mode := scopeConfig.TestCaseRetentionMode
+	switch mode {
+	case "":
+		mode = models.TestCaseRetentionDelete
+	case models.TestCaseRetentionDelete, models.TestCaseRetentionArchive:
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("invalid value for `testCaseRetentionMode`: %q, expected %q or %q",
+			mode, models.TestCaseRetentionDelete, models.TestCaseRetentionArchive))
+	}
+	return &TestCaseRetention{Days: scopeConfig.TestCaseRetentionDays, Mode: mode}, nil
+}
+
+// cutoff returns the start time before which the test cases of a job are pruned
+func (r *TestCaseRetention) cutoff(now time.Time) time.Time {
+	return now.AddDate(0, 0, -r.Days)
+}
+
+// PruneTestCases keeps ci_test_cases from growing without bounds: the test cases of the
+// scope's jobs started before the retention cutoff are deleted, or moved to
+// ci_test_cases_archive in "archive" mode. Jobs, suites and their counters are kept, so
+// pass rates and suite history stay available. The domain qa_test_case_executions of
+// pruned test cases are dropped by the next convertTestCases run.
+//
+// Parameters:
+//   - taskCtx: The subtask context providing access to logger, database, and other resources
+//
+// Returns:
+//   - errors.Error: Any error encountered during the pruning, or nil if successful
+func PruneTestCases(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*TestRegistryTaskData)
+	logger := taskCtx.GetLogger()
+
+	retention, err := NewTestCaseRetention(data.Options.ScopeConfig)
+	if err != nil {
+		return err
+	}
+	if retention == nil {
+		logger.Debug("Test case retention is off, keeping every test case")
+		return nil
+	}
+
+	db := taskCtx.GetDal()
+	connectionId := data.Options.ConnectionId
+	now := time.Now()
+	var jobIds []string
+	err = db.Pluck("job_id", &jobIds,
+		dal.From(&models.TestRegistryCIJob{}),
+		dal.Where("connection_id = ? AND scope_id = ? AND started_at < ?", connectionId, data.Options.FullName, retention.cutoff(now)),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to list the jobs past the test case retention")
+	}
+
+	var columns []string
+	if retention.Mode == models.TestCaseRetentionArchive {
+		columnMetas, err := db.GetColumns(&models.TestCase{}, nil)
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to read the ci_test_cases columns")
+		}
+		for _, columnMeta := range columnMetas {
+			columns = append(columns, columnMeta.Name())
+		}
+	}
+
+	var pruned int64
+	for _, batch := range splitJobIds(jobIds, pruneJobBatchSize) {
+		count, err := pruneJobTestCases(taskCtx, connectionId, batch, columns, now)
+		if err != nil {
+			return err
+		}
+		pruned += count
+	}
+
+	logger.Info("Pruned %d test cases of %d jobs started more than %d days ago (%s mode)", pruned, len(jobIds), retention.Days, retention.Mode)
+	return nil
+}
+
+// pruneJobTestCases deletes the test cases of a batch of jobs in one transaction, copying them to
+// ci_test_cases_archive first when archiveColumns are given
+//
+// Parameters:
+//   - basicRes: Resources providing the database
+//   - connectionId: The connection of the jobs
+//   - jobIds: The jobs whose test cases are pruned
+//   - archiveColumns: The ci_test_cases columns copied to the archive, nil to only delete
+//   - archivedAt: The archive time of the test cases
+//
+// Returns:
+//   - int64: The number of test cases pruned
+//   - errors.Error: Any error encountered, the batch is rolled back
+func pruneJobTestCases(basicRes context.BasicRes, connectionId uint64, jobIds []string, archiveColumns []string, archivedAt time.Time) (int64, errors.Error) {
+	var err errors.Error
+	txHelper := dbhelper.NewTxHelper(basicRes, &err)
+	defer txHelper.End()
+	db := txHelper.Begin()
+
+	where := dal.Where("connection_id = ? AND job_id IN ?", connectionId, jobIds)
+	count, err := db.Count(dal.From(&models.TestCase{}), where)
+	if err != nil {
+		err = errors.Default.Wrap(err, "failed to count the test cases to prune")
+		return 0, err
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	if archiveColumns != nil {
+		// A job archived before and collected again replaces its archived test cases
+		if err = db.Delete(&models.ArchivedTestCase{}, where); err != nil {
+			err = errors.Default.Wrap(err, "failed to delete previously archived test cases")
+			return 0, err
+		}
+		columns := strings.Join(archiveColumns, ", ")
+		err = db.Exec(
+			fmt.Sprintf("INSERT INTO %s (%s, archived_at) SELECT %s, ? FROM %s WHERE connection_id = ? AND job_id IN ?",
+				models.ArchivedTestCase{}.TableName(), columns, columns, models.TestCase{}.TableName()),
+			archivedAt, connectionId, jobIds,
+		)
+		if err != nil {
+			err = errors.Default.Wrap(err, "failed to archive test cases")
+			return 0, err
+		}
+	}
+
+	if err = db.Delete(&models.TestCase{}, where); err != nil {
+		err = errors.Default.Wrap(err, "failed to delete test cases")
+		return 0, err
+	}
+	return count, nil
+}
+
+// splitJobIds splits job IDs into batches of at most size
+func splitJobIds(jobIds []string, size int) [][]string {
+	var batches [][]string
+	for start := 0; start < len(jobIds); start += size {
+		end := start + size
+		if end > len(jobIds) {
+			end = len(jobIds)
+		}
+		batches = append(batches, jobIds[start:end])
+	}
+	return batches
+}
@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTestCaseRetention(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopeConfig *models.TestRegistryScopeConfig
+		want        *TestCaseRetention
+		wantErr     bool
+	}{
+		{"no scope config", nil, nil, false},
+		{"retention off", &models.TestRegistryScopeConfig{TestCaseRetentionMode: models.TestCaseRetentionArchive}, nil, false},
+		{"delete by default", &models.TestRegistryScopeConfig{TestCaseRetentionDays: 90}, &TestCaseRetention{Days: 90, Mode: models.TestCaseRetentionDelete}, false},
+		{"archive", &models.TestRegistryScopeConfig{TestCaseRetentionDays: 30, TestCaseRetentionMode: "archive"}, &TestCaseRetention{Days: 30, Mode: models.TestCaseRetentionArchive}, false},
+		{"unknown mode", &models.TestRegistryScopeConfig{TestCaseRetentionDays: 30, TestCaseRetentionMode: "truncate"}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retention, err := NewTestCaseRetention(tt.scopeConfig)
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.want, retention)
+		})
+	}
+}
+
+func TestTestCaseRetentionCutoff(t *testing.T) {
+	retention := &TestCaseRetention{Days: 90, Mode: models.TestCaseRetentionDelete}
+	now := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC), retention.cutoff(now))
+}
+
+func TestSplitJobIds(t *testing.T) {
+	assert.Nil(t, splitJobIds(nil, 2))
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}}, splitJobIds([]string{"a", "b", "c"}, 2))
+	assert.Equal(t, [][]string{{"a", "b"}}, splitJobIds([]string{"a", "b"}, 2))
+}
@@ -0,0 +1,112 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+)
+
+// maxTestIdentityLength is the size of the ci_test_cases.test_identity column
+const maxTestIdentityLength = 500
+
+// TestIdentityNormalizer builds the canonical identity of test cases from their suite and name
+type TestIdentityNormalizer struct {
+	suitePrefixes []*regexp.Regexp
+	suiteAliases  map[string]string
+}
+
+// NewTestIdentityNormalizer compiles the suite prefixes and aliases of the scope config
+//
+// Parameters:
+//   - scopeConfig: The scope config of the task (may be nil)
+//
+// Returns:
+//   - *TestIdentityNormalizer: The normalizer, or nil if no prefixes or aliases are configured
+//   - errors.Error: BadInput if a prefix is not a valid regex
+func NewTestIdentityNormalizer(scopeConfig *models.TestRegistryScopeConfig) (*TestIdentityNormalizer, errors.Error) {
+	if scopeConfig == nil || (len(scopeConfig.TestSuitePrefixes) == 0 && len(scopeConfig.TestSuiteAliases) == 0) {
+		return nil, nil
+	}
+
+	normalizer := &TestIdentityNormalizer{suiteAliases: scopeConfig.TestSuiteAliases}
+	for i, prefix := range scopeConfig.TestSuitePrefixes {
+		pattern, err := regexp.Compile(prefix)
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("testSuitePrefixes[%d]: invalid pattern %q", i, prefix))
+		}
+		normalizer.suitePrefixes = append(normalizer.suitePrefixes, pattern)
+	}
+	return normalizer, nil
+}
+
+// Suite returns the canonical name of a suite
+//
+// The first prefix matching at the start of the suite name is stripped, then the aliases map
+// the rest, e.g. prefix `^e2e-(aws|gcp) / ` and alias {"Konflux E2E": "konflux-e2e"} turn
+// "e2e-aws / Konflux E2E" into "konflux-e2e". A prefix covering the whole name is ignored.
+//
+// Parameters:
+//   - suiteName: Name of the suite
+//
+// Returns:
+//   - string: The canonical suite name, suiteName if nothing applies
+func (n *TestIdentityNormalizer) Suite(suiteName string) string {
+	if n == nil {
+		return suiteName
+	}
+	suite := suiteName
+	for _, prefix := range n.suitePrefixes {
+		match := prefix.FindStringIndex(suite)
+		if match == nil || match[0] != 0 || match[1] == len(suite) {
+			continue
+		}
+		suite = strings.TrimSpace(suite[match[1]:])
+		break
+	}
+	if alias, ok := n.suiteAliases[suite]; ok && alias != "" {
+		return alias
+	}
+	return suite
+}
+
+// Identity returns the canonical "<suite>::<name>" identity of a test case, cut to the size of
+// the test_identity column. Test cases outside of a named suite are identified by their name.
+//
+// Parameters:
+//   - suiteName: Name of the suite holding the test case
+//   - testName: Name of the test case
+//
+// Returns:
+//   - string: The test identity
+func (n *TestIdentityNormalizer) Identity(suiteName, testName string) string {
+	identity := testName
+	if suite := n.Suite(suiteName); suite != "" {
+		identity = suite + "::" + testName
+	}
+	// The column counts characters, not bytes
+	if utf8.RuneCountInString(identity) > maxTestIdentityLength {
+		identity = string([]rune(identity)[:maxTestIdentityLength])
+	}
+	return identity
+}
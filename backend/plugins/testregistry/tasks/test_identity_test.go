@@ -0,0 +1,103 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/apache/incubator-devlake/plugins/testregistry/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestNewTestIdentityNormalizer(t *testing.T) {
+	normalizer, err := NewTestIdentityNormalizer(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, normalizer)
+
+	normalizer, err = NewTestIdentityNormalizer(&models.TestRegistryScopeConfig{})
+	assert.Nil(t, err)
+	assert.Nil(t, normalizer)
+
+	_, err = NewTestIdentityNormalizer(&models.TestRegistryScopeConfig{TestSuitePrefixes: []string{"("}})
+	assert.NotNil(t, err)
+}
+
+func TestTestIdentityNormalizerIdentity(t *testing.T) {
+	normalizer, err := NewTestIdentityNormalizer(&models.TestRegistryScopeConfig{
+		TestSuitePrefixes: []string{`^e2e-(aws|gcp) / `, `\[serial\] `, `^.*$`},
+		TestSuiteAliases:  map[string]string{"Konflux E2E": "konflux-e2e", "konflux e2e": "konflux-e2e", "ignored": ""},
+	})
+	assert.Nil(t, err)
+
+	tests := []struct {
+		suiteName string
+		identity  string
+	}{
+		{"e2e-aws / Konflux E2E", "konflux-e2e::creates a release"},
+		{"e2e-gcp / konflux e2e", "konflux-e2e::creates a release"},
+		{"Konflux E2E", "konflux-e2e::creates a release"},
+		{"build [serial] Konflux E2E", "build [serial] Konflux E2E::creates a release"}, // prefixes only match at the start
+		{"e2e-aws / ", "e2e-aws / ::creates a release"},                                 // a prefix covering the whole name is ignored
+		{"ignored", "ignored::creates a release"},                                       // an empty alias is ignored
+		{"", "creates a release"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.identity, normalizer.Identity(tt.suiteName, "creates a release"), tt.suiteName)
+	}
+
+	var nilNormalizer *TestIdentityNormalizer
+	assert.Equal(t, "e2e-aws / Konflux E2E::creates a release", nilNormalizer.Identity("e2e-aws / Konflux E2E", "creates a release"))
+
+	long := nilNormalizer.Identity("suite", strings.Repeat("é", 600))
+	assert.Equal(t, maxTestIdentityLength, len([]rune(long)))
+	assert.True(t, strings.HasPrefix(long, "suite::é"))
+}
+
+func TestSaveSuiteRecursivelyTestIdentity(t *testing.T) {
+	mockDal := new(mockdal.Dal)
+	mockLogger := new(mocklog.Logger)
+	mockLogger.On("Debug", mock.Anything, mock.Anything).Maybe()
+	identities := map[string]string{}
+	mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		if testCase, ok := args.Get(0).(*models.TestCase); ok {
+			identities[testCase.Name] = testCase.TestIdentity
+		}
+	}).Return(nil)
+
+	normalizer, err := NewTestIdentityNormalizer(&models.TestRegistryScopeConfig{
+		TestSuitePrefixes: []string{`^e2e-(aws|gcp) / `},
+	})
+	assert.Nil(t, err)
+
+	suite := &TestSuite{
+		Name:      "e2e-aws / Build Service",
+		TestCases: []*TestCase{{Name: "builds a component"}},
+		Children: []*TestSuite{
+			{Name: "e2e-gcp / Release Service", TestCases: []*TestCase{{Name: "pushes the image"}}},
+		},
+	}
+	_, tc := saveSuiteRecursively(mockDal, mockLogger, suite, 1, "job-1", common.RawDataOrigin{}, &junitSaveRules{TestIdentity: normalizer}, nil)
+	assert.Equal(t, 2, tc)
+	assert.Equal(t, "Build Service::builds a component", identities["builds a component"])
+	assert.Equal(t, "Release Service::pushes the image", identities["pushes the image"])
+}
@@ -0,0 +1,149 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/log"
+)
+
+const (
+	// defaultLoggingDir is used when LOGGING_DIR is not set
+	defaultLoggingDir = "/app/logs"
+	// StaleWorkDirAge is how old a run working directory must be before the startup GC removes it
+	StaleWorkDirAge = 24 * time.Hour
+)
+
+var unsafeWorkDirChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// LoggingDir returns the base directory used for temporary artifacts
+//
+// Returns:
+//   - string: LOGGING_DIR environment variable, or /app/logs if unset
+func LoggingDir() string {
+	if loggingDir := os.Getenv("LOGGING_DIR"); loggingDir != "" {
+		return loggingDir
+	}
+	return defaultLoggingDir
+}
+
+// workDirRoot returns the directory holding the working directories of every testregistry run
+//
+// Parameters:
+//   - loggingDir: Base logging directory
+//
+// Returns:
+//   - string: {loggingDir}/tmp/testregistry
+func workDirRoot(loggingDir string) string {
+	return filepath.Join(loggingDir, "tmp", "testregistry")
+}
+
+// scopeWorkDirName builds a filesystem-safe directory name for a connection and scope
+//
+// Parameters:
+//   - connectionId: Connection ID
+//   - fullName: Scope full name (e.g., "konflux-test-storage/konflux-team/release-service")
+//
+// Returns:
+//   - string: Directory name such as "1-konflux-test-storage_konflux-team_release-service"
+func scopeWorkDirName(connectionId uint64, fullName string) string {
+	return fmt.Sprintf("%d-%s", connectionId, unsafeWorkDirChars.ReplaceAllString(fullName, "_"))
+}
+
+// newRunWorkDir creates a working directory owned by a single collection run
+//
+// Scopes of the same connection may run in parallel pipelines, so every run gets
+// {loggingDir}/tmp/testregistry/{connectionId}-{scope}/{runId} and only ever removes
+// that directory. Leftovers of crashed runs are removed by CleanupStaleWorkDirs.
+//
+// Parameters:
+//   - loggingDir: Base logging directory
+//   - connectionId: Connection ID
+//   - fullName: Scope full name
+//
+// Returns:
+//   - string: Path of the created run directory
+//   - errors.Error: Any error encountered while creating the directory
+func newRunWorkDir(loggingDir string, connectionId uint64, fullName string) (string, errors.Error) {
+	runId, err := generateUUID()
+	if err != nil {
+		return "", err
+	}
+	workDir := filepath.Join(workDirRoot(loggingDir), scopeWorkDirName(connectionId, fullName), runId)
+	if mkdirErr := os.MkdirAll(workDir, 0755); mkdirErr != nil {
+		return "", errors.Default.Wrap(mkdirErr, "failed to create run working directory")
+	}
+	return workDir, nil
+}
+
+// CleanupStaleWorkDirs removes run working directories left behind by crashed or killed runs
+//
+// Only run directories last modified more than maxAge ago are removed, so directories of
+// runs that are still in progress in other pipelines are left alone. Scope directories
+// that end up empty are removed as well.
+//
+// Parameters:
+//   - loggingDir: Base logging directory
+//   - maxAge: Minimum age of a run directory before it is removed
+//   - logger: Logger for cleanup failures
+//
+// Returns:
+//   - int: Number of run directories removed
+func CleanupStaleWorkDirs(loggingDir string, maxAge time.Duration, logger log.Logger) int {
+	root := workDirRoot(loggingDir)
+	scopeDirs, err := os.ReadDir(root)
+	if err != nil {
+		return 0
+	}
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	for _, scopeDir := range scopeDirs {
+		if !scopeDir.IsDir() {
+			continue
+		}
+		scopePath := filepath.Join(root, scopeDir.Name())
+		runDirs, err := os.ReadDir(scopePath)
+		if err != nil {
+			continue
+		}
+		remaining := len(runDirs)
+		for _, runDir := range runDirs {
+			info, err := runDir.Info()
+			if err != nil || !info.ModTime().Before(cutoff) {
+				continue
+			}
+			runPath := filepath.Join(scopePath, runDir.Name())
+			if err := os.RemoveAll(runPath); err != nil {
+				logger.Warn(err, "failed to remove stale working directory", "path", runPath)
+				continue
+			}
+			removed++
+			remaining--
+		}
+		if remaining == 0 {
+			_ = os.Remove(scopePath)
+		}
+	}
+	return removed
+}
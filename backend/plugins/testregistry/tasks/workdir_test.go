@@ -0,0 +1,75 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScopeWorkDirName(t *testing.T) {
+	assert.Equal(t, "1-konflux-test-storage_konflux-team_release-service",
+		scopeWorkDirName(1, "konflux-test-storage/konflux-team/release-service"))
+	assert.Equal(t, "2-org_.._repo", scopeWorkDirName(2, "org/../repo"))
+}
+
+func TestNewRunWorkDir(t *testing.T) {
+	loggingDir := t.TempDir()
+
+	first, err := newRunWorkDir(loggingDir, 1, "org/repo")
+	assert.Nil(t, err)
+	second, err := newRunWorkDir(loggingDir, 1, "org/repo")
+	assert.Nil(t, err)
+	other, err := newRunWorkDir(loggingDir, 1, "org/other")
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, filepath.Dir(first), filepath.Dir(second))
+	assert.NotEqual(t, filepath.Dir(first), filepath.Dir(other))
+	assert.Equal(t, workDirRoot(loggingDir), filepath.Dir(filepath.Dir(first)))
+
+	// cleaning up one run must not touch the directories of concurrent runs
+	assert.NoError(t, os.RemoveAll(first))
+	assert.DirExists(t, second)
+	assert.DirExists(t, other)
+}
+
+func TestCleanupStaleWorkDirs(t *testing.T) {
+	loggingDir := t.TempDir()
+	logger := new(mocklog.Logger)
+
+	stale, err := newRunWorkDir(loggingDir, 1, "org/stale")
+	assert.Nil(t, err)
+	fresh, err := newRunWorkDir(loggingDir, 1, "org/fresh")
+	assert.Nil(t, err)
+	old := time.Now().Add(-2 * StaleWorkDirAge)
+	assert.NoError(t, os.Chtimes(stale, old, old))
+
+	assert.Equal(t, 1, CleanupStaleWorkDirs(loggingDir, StaleWorkDirAge, logger))
+	assert.NoDirExists(t, stale)
+	assert.NoDirExists(t, filepath.Dir(stale))
+	assert.DirExists(t, fresh)
+
+	// a missing root is not an error
+	assert.Equal(t, 0, CleanupStaleWorkDirs(filepath.Join(loggingDir, "missing"), StaleWorkDirAge, logger))
+}
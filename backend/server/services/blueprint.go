@@ -28,6 +28,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/impls/logruslog"
 	"github.com/robfig/cron/v3"
@@ -362,13 +363,19 @@ func MakePlanForBlueprint(blueprint *models.Blueprint, syncPolicy *models.SyncPo
 	metrics := make(map[string]json.RawMessage)
 	projectMetrics := make([]models.ProjectMetricSetting, 0)
 	if blueprint.ProjectName != "" {
-		err := db.All(&projectMetrics, dal.Where("project_name = ? AND enable = ?", blueprint.ProjectName, true))
+		err := db.All(&projectMetrics, dal.Where("project_name = ?", blueprint.ProjectName))
 		if err != nil {
 			return nil, err
 		}
+		disabled := make(map[string]bool)
 		for _, projectMetric := range projectMetrics {
+			if !projectMetric.Enable {
+				disabled[projectMetric.PluginName] = true
+				continue
+			}
 			metrics[projectMetric.PluginName] = projectMetric.PluginOption
 		}
+		addAutoIncludedMetrics(metrics, disabled, blueprint.Connections, plugin.AllPlugins(), hasConnection)
 	}
 	skipCollectors := false
 	if syncPolicy != nil && syncPolicy.SkipCollectors {
@@ -381,6 +388,50 @@ func MakePlanForBlueprint(blueprint *models.Blueprint, syncPolicy *models.SyncPo
 	return SequentializePipelinePlans(blueprint.BeforePlan, plan, blueprint.AfterPlan), nil
 }
 
+// addAutoIncludedMetrics adds the metric plugins implementing MetricPluginAutoIncludeV200
+// to metrics when one of the blueprint connections belongs to a data source they
+// follow and the plugin is configured on this instance, unless the project disabled them
+func addAutoIncludedMetrics(
+	metrics map[string]json.RawMessage,
+	disabled map[string]bool,
+	connections []*models.BlueprintConnection,
+	plugins map[string]plugin.PluginMeta,
+	configured func(plugin.PluginMeta) bool,
+) {
+	connectedPlugins := make(map[string]bool, len(connections))
+	for _, connection := range connections {
+		connectedPlugins[connection.PluginName] = true
+	}
+	for name, p := range plugins {
+		autoInclude, ok := p.(plugin.MetricPluginAutoIncludeV200)
+		if !ok || disabled[name] {
+			continue
+		}
+		if _, ok := metrics[name]; ok {
+			continue
+		}
+		for _, dataSource := range autoInclude.AutoIncludeDataSources() {
+			if connectedPlugins[dataSource] {
+				if configured(p) {
+					metrics[name] = nil
+				}
+				break
+			}
+		}
+	}
+}
+
+// hasConnection reports whether a plugin with connections has at least one, which marks
+// it as configured on this instance
+func hasConnection(p plugin.PluginMeta) bool {
+	source, ok := p.(plugin.PluginSource)
+	if !ok || source.Connection() == nil {
+		return false
+	}
+	count, err := db.Count(dal.From(source.Connection()))
+	return err == nil && count > 0
+}
+
 // ParallelizePipelinePlans merges multiple pipelines into one unified plan
 // by assuming they can be executed in parallel
 func ParallelizePipelinePlans(plans ...models.PipelinePlan) models.PipelinePlan {
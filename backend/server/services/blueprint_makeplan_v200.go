@@ -20,6 +20,7 @@ package services
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/apache/incubator-devlake/core/errors"
@@ -53,10 +54,18 @@ func GeneratePlanJsonV200(
 		}
 		if pluginBp, ok := p.(plugin.DataSourcePluginBlueprintV200); ok {
 			var pluginScopes []plugin.Scope
-			sourcePlans[i], pluginScopes, err = pluginBp.MakeDataSourcePipelinePlanV200(
-				connection.ConnectionId,
-				connection.Scopes,
-			)
+			if projectBp, ok := p.(plugin.DataSourcePluginProjectBlueprintV200); ok {
+				sourcePlans[i], pluginScopes, err = projectBp.MakeProjectDataSourcePipelinePlanV200(
+					projectName,
+					connection.ConnectionId,
+					connection.Scopes,
+				)
+			} else {
+				sourcePlans[i], pluginScopes, err = pluginBp.MakeDataSourcePipelinePlanV200(
+					connection.ConnectionId,
+					connection.Scopes,
+				)
+			}
 			if err != nil {
 				return nil, err
 			}
@@ -96,8 +105,7 @@ func GeneratePlanJsonV200(
 	}
 
 	// make plans for metric plugins
-	metricPlans := make([]coreModels.PipelinePlan, len(metrics))
-	i := 0
+	metricPlans := make(map[string]coreModels.PipelinePlan, len(metrics))
 	for metricPluginName, metricPluginOptJson := range metrics {
 		p, err := plugin.GetPlugin(metricPluginName)
 		if err != nil {
@@ -108,11 +116,10 @@ func GeneratePlanJsonV200(
 			if len(metricPluginOptJson) == 0 {
 				metricPluginOptJson = json.RawMessage("{}")
 			}
-			metricPlans[i], err = pluginBp.MakeMetricPluginPipelinePlanV200(projectName, metricPluginOptJson)
+			metricPlans[metricPluginName], err = pluginBp.MakeMetricPluginPipelinePlanV200(projectName, metricPluginOptJson)
 			if err != nil {
 				return nil, err
 			}
-			i++
 		} else {
 			return nil, errors.Default.New(
 				fmt.Sprintf("plugin %s does not support MetricPluginBlueprintV200", metricPluginName),
@@ -132,14 +139,82 @@ func GeneratePlanJsonV200(
 			}
 		}
 	}
+	metricPlan, err := orderMetricPlans(metricPlans)
+	if err != nil {
+		return nil, err
+	}
 	plan := SequentializePipelinePlans(
 		planForProjectMapping,
 		ParallelizePipelinePlans(sourcePlans...),
-		ParallelizePipelinePlans(metricPlans...),
+		metricPlan,
 	)
 	return plan, err
 }
 
+// orderMetricPlans merges the plans of the metric plugins. A plugin whose RunAfter names other
+// metric plugins of the project runs after their whole plan, the others run in parallel.
+// RunAfter entries that are no metric plugin of the project, e.g. data sources, are ignored.
+func orderMetricPlans(plans map[string]coreModels.PipelinePlan) (coreModels.PipelinePlan, errors.Error) {
+	levels := make(map[string]int, len(plans))
+	visiting := make(map[string]bool)
+	var levelOf func(name string) (int, errors.Error)
+	levelOf = func(name string) (int, errors.Error) {
+		if level, ok := levels[name]; ok {
+			return level, nil
+		}
+		if visiting[name] {
+			return 0, errors.Default.New(fmt.Sprintf("metric plugin %s runs after itself", name))
+		}
+		visiting[name] = true
+		level := 0
+		if p, err := plugin.GetPlugin(name); err == nil {
+			if metric, ok := p.(plugin.PluginMetric); ok {
+				runAfter, err := metric.RunAfter()
+				if err != nil {
+					return 0, err
+				}
+				for _, dependency := range runAfter {
+					if _, ok := plans[dependency]; !ok || dependency == name {
+						continue
+					}
+					dependencyLevel, err := levelOf(dependency)
+					if err != nil {
+						return 0, err
+					}
+					if dependencyLevel+1 > level {
+						level = dependencyLevel + 1
+					}
+				}
+			}
+		}
+		visiting[name] = false
+		levels[name] = level
+		return level, nil
+	}
+
+	names := make([]string, 0, len(plans))
+	for name := range plans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var groups [][]coreModels.PipelinePlan
+	for _, name := range names {
+		level, err := levelOf(name)
+		if err != nil {
+			return nil, err
+		}
+		for len(groups) <= level {
+			groups = append(groups, nil)
+		}
+		groups[level] = append(groups[level], plans[name])
+	}
+	merged := make([]coreModels.PipelinePlan, 0, len(groups))
+	for _, group := range groups {
+		merged = append(merged, ParallelizePipelinePlans(group...))
+	}
+	return SequentializePipelinePlans(merged...), nil
+}
+
 func removeCollectorTasks(plan coreModels.PipelinePlan) coreModels.PipelinePlan {
 	for j, stage := range plan {
 		for k, task := range stage {
@@ -101,3 +101,48 @@ func TestMakePlanV200(t *testing.T) {
 
 	assert.Equal(t, expectedPlan, plan)
 }
+
+// metricPluginWithRunAfter is a metric plugin mock declaring the plugins it runs after
+type metricPluginWithRunAfter struct {
+	*mockplugin.CompositeMetricPluginBlueprintV200
+	*mockplugin.PluginMetric
+}
+
+func TestOrderMetricPlans(t *testing.T) {
+	newMetric := func(runAfter ...string) metricPluginWithRunAfter {
+		metric := new(mockplugin.PluginMetric)
+		metric.On("RunAfter").Return(runAfter, nil)
+		return metricPluginWithRunAfter{new(mockplugin.CompositeMetricPluginBlueprintV200), metric}
+	}
+	plugin.RegisterPlugin("TestOrderMetricPlans-dora", newMetric())
+	plugin.RegisterPlugin("TestOrderMetricPlans-linker", newMetric("github"))
+	plugin.RegisterPlugin("TestOrderMetricPlans-aireview", newMetric("github", "TestOrderMetricPlans-dora"))
+
+	doraPlan := coreModels.PipelinePlan{
+		{{Plugin: "dora", Subtasks: []string{"generateDeployments"}}},
+		{{Plugin: "dora", Subtasks: []string{"calculateChangeLeadTime"}}},
+	}
+	linkerPlan := coreModels.PipelinePlan{{{Plugin: "linker"}}}
+	aireviewPlan := coreModels.PipelinePlan{{{Plugin: "aireview"}}}
+
+	plan, err := orderMetricPlans(map[string]coreModels.PipelinePlan{
+		"TestOrderMetricPlans-dora":     doraPlan,
+		"TestOrderMetricPlans-linker":   linkerPlan,
+		"TestOrderMetricPlans-aireview": aireviewPlan,
+	})
+	assert.Nil(t, err)
+	// linker runs along dora, aireview runs after the whole dora plan
+	assert.Equal(t, coreModels.PipelinePlan{
+		{doraPlan[0][0], linkerPlan[0][0]},
+		doraPlan[1],
+		aireviewPlan[0],
+	}, plan)
+
+	// without dora in the project, aireview runs along the others
+	plan, err = orderMetricPlans(map[string]coreModels.PipelinePlan{
+		"TestOrderMetricPlans-linker":   linkerPlan,
+		"TestOrderMetricPlans-aireview": aireviewPlan,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, coreModels.PipelinePlan{{aireviewPlan[0][0], linkerPlan[0][0]}}, plan)
+}
@@ -18,9 +18,12 @@ limitations under the License.
 package services
 
 import (
+	"encoding/json"
 	"testing"
 
 	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -147,3 +150,52 @@ func TestRemoveCollectorTasks(t *testing.T) {
 		},
 	}, removeCollectorTasks(plan1))
 }
+
+type autoIncludeMetricPlugin struct {
+	plugin.CompositeMetricPluginBlueprintV200
+	dataSources []string
+}
+
+func (p autoIncludeMetricPlugin) AutoIncludeDataSources() []string {
+	return p.dataSources
+}
+
+func TestAddAutoIncludedMetrics(t *testing.T) {
+	plugins := map[string]plugin.PluginMeta{
+		"github":           new(mockplugin.CompositeDataSourcePluginBlueprintV200),
+		"dora":             new(mockplugin.CompositeMetricPluginBlueprintV200),
+		"aireview":         autoIncludeMetricPlugin{dataSources: []string{"github", "gitlab"}},
+		"sonarqube-metric": autoIncludeMetricPlugin{dataSources: []string{"sonarqube"}},
+	}
+	githubConnections := []*coreModels.BlueprintConnection{{PluginName: "github", ConnectionId: 1}}
+	configured := func(plugin.PluginMeta) bool { return true }
+
+	// included when the blueprint collects from a followed data source
+	metrics := map[string]json.RawMessage{"dora": nil}
+	addAutoIncludedMetrics(metrics, map[string]bool{}, githubConnections, plugins, configured)
+	assert.Equal(t, map[string]json.RawMessage{"dora": nil, "aireview": nil}, metrics)
+
+	// options of a plugin enabled for the project are kept
+	metrics = map[string]json.RawMessage{"aireview": json.RawMessage(`{"skipPredictions":true}`)}
+	addAutoIncludedMetrics(metrics, map[string]bool{}, githubConnections, plugins, configured)
+	assert.Equal(t, json.RawMessage(`{"skipPredictions":true}`), metrics["aireview"])
+
+	// a plugin disabled for the project is not added back
+	metrics = map[string]json.RawMessage{}
+	addAutoIncludedMetrics(metrics, map[string]bool{"aireview": true}, githubConnections, plugins, configured)
+	assert.Empty(t, metrics)
+
+	// no followed data source in the blueprint
+	metrics = map[string]json.RawMessage{}
+	addAutoIncludedMetrics(metrics, map[string]bool{}, []*coreModels.BlueprintConnection{{PluginName: "jira", ConnectionId: 1}}, plugins, configured)
+	assert.Empty(t, metrics)
+
+	// a plugin not configured on the instance only runs for projects enabling it
+	notConfigured := func(plugin.PluginMeta) bool { return false }
+	metrics = map[string]json.RawMessage{}
+	addAutoIncludedMetrics(metrics, map[string]bool{}, githubConnections, plugins, notConfigured)
+	assert.Empty(t, metrics)
+	metrics = map[string]json.RawMessage{"aireview": nil}
+	addAutoIncludedMetrics(metrics, map[string]bool{}, githubConnections, plugins, notConfigured)
+	assert.Equal(t, map[string]json.RawMessage{"aireview": nil}, metrics)
+}
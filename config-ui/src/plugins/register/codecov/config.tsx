@@ -19,7 +19,7 @@
 import { IPluginConfig } from '@/types';
 
 import Icon from './assets/icon.svg?react';
-import { Organization, Token } from './connection-fields';
+import { Organization, Service, Token } from './connection-fields';
 
 export const CodecovConfig: IPluginConfig = {
   plugin: 'codecov',
@@ -35,9 +35,20 @@ export const CodecovConfig: IPluginConfig = {
       'name',
       {
         key: 'endpoint',
-        subLabel: 'The Codecov API endpoint URL',
+        subLabel: 'The Codecov API endpoint URL, or the base URL of a self-hosted Codecov installation',
         defaultValue: 'https://api.codecov.io',
       },
+      ({ type, initialValues, values, errors, setValues, setErrors }: any) => (
+        <Service
+          key="service"
+          type={type}
+          initialValues={initialValues}
+          values={values}
+          errors={errors}
+          setValues={setValues}
+          setErrors={setErrors}
+        />
+      ),
       ({ type, initialValues, values, errors, setValues, setErrors }: any) => (
         <Organization
           key="organization"
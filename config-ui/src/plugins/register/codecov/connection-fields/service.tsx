@@ -0,0 +1,60 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one or more
+ * contributor license agreements.  See the NOTICE file distributed with
+ * this work for additional information regarding copyright ownership.
+ * The ASF licenses this file to You under the Apache License, Version 2.0
+ * (the "License"); you may not use this file except in compliance with
+ * the License.  You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ *
+ */
+
+import { useEffect } from 'react';
+import { Select } from 'antd';
+
+import { Block } from '@/components';
+
+interface Props {
+  type: 'create' | 'update';
+  initialValues: any;
+  values: any;
+  errors: any;
+  setValues: (value: any) => void;
+  setErrors: (value: any) => void;
+}
+
+const SERVICE_OPTIONS = [
+  { label: 'GitHub', value: 'github' },
+  { label: 'GitHub Enterprise', value: 'github_enterprise' },
+  { label: 'GitLab', value: 'gitlab' },
+  { label: 'GitLab Enterprise', value: 'gitlab_enterprise' },
+  { label: 'Bitbucket', value: 'bitbucket' },
+  { label: 'Bitbucket Server', value: 'bitbucket_server' },
+];
+
+export const Service = ({ initialValues, values, setValues }: Props) => {
+  useEffect(() => {
+    setValues({ service: initialValues.service || 'github' });
+  }, [initialValues.service]);
+
+  return (
+    <Block
+      title="Git Provider"
+      description="The git provider of the organization. Self-hosted Codecov installations backed by GitHub Enterprise use GitHub Enterprise."
+    >
+      <Select
+        style={{ width: 386 }}
+        value={values.service || 'github'}
+        onChange={(service: string) => setValues({ service })}
+        options={SERVICE_OPTIONS}
+      />
+    </Block>
+  );
+};
@@ -18,6 +18,7 @@
 
 import { IPluginConfig } from '@/types';
 
+import { AiReviewConfig } from './aireview';
 import { ArgoCDConfig } from './argocd';
 import { AsanaConfig } from './asana';
 import { AzureConfig, AzureGoConfig } from './azure';
@@ -46,6 +47,7 @@ import { TestRegistryConfig } from './testregistry';
 import { SlackConfig } from './slack/config';
 
 export const pluginConfigs: IPluginConfig[] = [
+  AiReviewConfig,
   ArgoCDConfig,
   AsanaConfig,
   AzureConfig,